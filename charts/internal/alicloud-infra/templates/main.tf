@@ -1,6 +1,7 @@
 provider "alicloud" {
   access_key = "${var.ACCESS_KEY_ID}"
   secret_key = "${var.ACCESS_KEY_SECRET}"
+  security_token = "${var.SECURITY_TOKEN}"
   region = "{{ required "alicloud.region is required" .Values.alicloud.region }}"
 }
 
@@ -14,42 +15,159 @@ resource "alicloud_key_pair" "publickey" {
 resource "alicloud_vpc" "vpc" {
   name       = "{{ required "clusterName is required" .Values.clusterName }}-vpc"
   cidr_block = "{{ required "vpc.cidr is required" .Values.vpc.cidr }}"
+  {{- if .Values.dualStack.enabled }}
+  enable_ipv6 = true
+  {{- end }}
 }
+{{- if not .Values.natGateway.perZone }}
 resource "alicloud_nat_gateway" "nat_gateway" {
   vpc_id = "{{ required "vpc.id is required" .Values.vpc.id }}"
   specification   = "Small"
   name   = "{{ required "clusterName is required" .Values.clusterName }}-natgw"
 }
 {{- end }}
+{{- end }}
+{{- if .Values.eip.allocationID }}
+
+// Associate the existing EIP which is owned by the user, hence it is not released on destruction.
+data "alicloud_eips" "eip_natgw" {
+  ids = ["{{ .Values.eip.allocationID }}"]
+}
+
+resource "alicloud_eip_association" "eip_natgw_asso" {
+  allocation_id = "{{ .Values.eip.allocationID }}"
+  instance_id   = "{{ required "vpc.natGatewayID is required" .Values.vpc.natGatewayID }}"
+}
+{{- end }}
 
 
 // Loop zones
 {{ range $index, $zone := .Values.zones }}
+{{- $natGatewayID := $.Values.vpc.natGatewayID }}
+{{- $snatTableID := $.Values.vpc.snatTableID }}
+{{- $routeTableID := $.Values.vpc.routeTableID }}
+{{- $snatIP := printf "${alicloud_eip.eip_natgw_z%d.ip_address}" $index }}
+{{- range $eipIndex := untilStep 1 (int $.Values.eip.count) 1 }}
+{{- $snatIP = printf "%s,${alicloud_eip.eip_natgw_z%d_%d.ip_address}" $snatIP $index $eipIndex }}
+{{- end }}
+{{- if $.Values.eip.allocationID }}
+{{- $snatIP = "${data.alicloud_eips.eip_natgw.eips.0.ip_address}" }}
+{{- end }}
+{{- if $.Values.natGateway.perZone }}
+{{- $natGatewayID = printf "${alicloud_nat_gateway.nat_gateway_z%d.id}" $index }}
+{{- $snatTableID = printf "${alicloud_nat_gateway.nat_gateway_z%d.snat_table_ids}" $index }}
+{{- $routeTableID = printf "${alicloud_route_table.rt_z%d.id}" $index }}
+{{- end }}
 
 resource "alicloud_vswitch" "vsw_z{{ $index }}" {
   name              = "{{ required "clusterName is required" $.Values.clusterName }}-{{ required "zone.name is required" $zone.name }}-vsw"
   vpc_id            = "{{ required "vpc.id is required" $.Values.vpc.id }}"
   cidr_block        = "{{ required "zone.cidr.workers is required" $zone.cidr.workers }}"
   availability_zone = "{{ required "zone.name is required" $zone.name }}"
+  {{- if $.Values.dualStack.enabled }}
+  ipv6_cidr_block_mask = {{ $index }}
+  {{- end }}
+}
+{{- if $.Values.create.routeTableAttachments }}
+
+resource "alicloud_route_table_attachment" "rta_z{{ $index }}" {
+  vswitch_id     = "${alicloud_vswitch.vsw_z{{ $index }}.id}"
+  route_table_id = "{{ required "vpc.routeTableID is required" $routeTableID }}"
+}
+{{- end }}
+{{- if $.Values.natGateway.perZone }}
+{{- $natGatewayVSwitchID := printf "${alicloud_vswitch.vsw_z%d.id}" $index }}
+{{- if $zone.cidr.natGateway }}
+{{- $natGatewayVSwitchID = printf "${alicloud_vswitch.vsw_natgw_z%d.id}" $index }}
+
+// Dedicated vswitch of the NAT gateway, which is not used by the workers.
+resource "alicloud_vswitch" "vsw_natgw_z{{ $index }}" {
+  name              = "{{ required "clusterName is required" $.Values.clusterName }}-{{ required "zone.name is required" $zone.name }}-natgw-vsw"
+  vpc_id            = "{{ required "vpc.id is required" $.Values.vpc.id }}"
+  cidr_block        = "{{ $zone.cidr.natGateway }}"
+  availability_zone = "{{ required "zone.name is required" $zone.name }}"
 }
 
+output "{{ $.Values.outputKeys.natGatewayVSwitchPrefix }}{{ $index }}" {
+  value = "${alicloud_vswitch.vsw_natgw_z{{ $index }}.id}"
+}
+{{- end }}
+
+resource "alicloud_nat_gateway" "nat_gateway_z{{ $index }}" {
+  vpc_id     = "{{ required "vpc.id is required" $.Values.vpc.id }}"
+  nat_type   = "Enhanced"
+  vswitch_id = "{{ $natGatewayVSwitchID }}"
+  name       = "{{ required "clusterName is required" $.Values.clusterName }}-{{ required "zone.name is required" $zone.name }}-natgw"
+}
+
+resource "alicloud_route_table" "rt_z{{ $index }}" {
+  vpc_id = "{{ required "vpc.id is required" $.Values.vpc.id }}"
+  name   = "{{ required "clusterName is required" $.Values.clusterName }}-{{ required "zone.name is required" $zone.name }}-rt"
+}
+
+resource "alicloud_route_entry" "default_z{{ $index }}" {
+  route_table_id        = "${alicloud_route_table.rt_z{{ $index }}.id}"
+  destination_cidrblock = "0.0.0.0/0"
+  nexthop_type          = "NatGateway"
+  nexthop_id            = "${alicloud_nat_gateway.nat_gateway_z{{ $index }}.id}"
+}
+
+output "{{ $.Values.outputKeys.natGatewayPrefix }}{{ $index }}" {
+  value = "${alicloud_nat_gateway.nat_gateway_z{{ $index }}.id}"
+}
+{{- end }}
+
+{{- if not $.Values.eip.allocationID }}
+
 // Create a new EIP.
 resource "alicloud_eip" "eip_natgw_z{{ $index }}" {
   name                 = "{{ required "clusterName is required" $.Values.clusterName }}-eip-natgw-z{{ $index }}"
-  bandwidth            = "100"
+  bandwidth            = "{{ required "eip.bandwidth is required" $.Values.eip.bandwidth }}"
   instance_charge_type = "PostPaid"
   internet_charge_type = "{{ required "vpc.internetChargeType is required" $.Values.vpc.internetChargeType }}"
 }
 
 resource "alicloud_eip_association" "eip_natgw_asso_z{{ $index }}" {
   allocation_id = "${alicloud_eip.eip_natgw_z{{ $index }}.id}"
-  instance_id   = "{{ required "natGatewayID is required" $.Values.vpc.natGatewayID }}"
+  instance_id   = "{{ required "natGatewayID is required" $natGatewayID }}"
+}
+{{- range $eipIndex := untilStep 1 (int $.Values.eip.count) 1 }}
+
+// Additional EIP of the enhanced NAT gateway of the zone.
+resource "alicloud_eip" "eip_natgw_z{{ $index }}_{{ $eipIndex }}" {
+  name                 = "{{ required "clusterName is required" $.Values.clusterName }}-eip-natgw-z{{ $index }}-{{ $eipIndex }}"
+  bandwidth            = "{{ required "eip.bandwidth is required" $.Values.eip.bandwidth }}"
+  instance_charge_type = "PostPaid"
+  internet_charge_type = "{{ required "vpc.internetChargeType is required" $.Values.vpc.internetChargeType }}"
 }
 
+resource "alicloud_eip_association" "eip_natgw_asso_z{{ $index }}_{{ $eipIndex }}" {
+  allocation_id = "${alicloud_eip.eip_natgw_z{{ $index }}_{{ $eipIndex }}.id}"
+  instance_id   = "{{ required "natGatewayID is required" $natGatewayID }}"
+}
+{{- end }}
+{{- if $.Values.natGateway.perZone }}
+{{- range $eipIndex := until (int $.Values.eip.count) }}
+{{- $eipResource := printf "alicloud_eip.eip_natgw_z%d" $index }}
+{{- if $eipIndex }}
+{{- $eipResource = printf "%s_%d" $eipResource $eipIndex }}
+{{- end }}
+
+output "{{ $.Values.outputKeys.natGatewayEIPPrefix }}{{ $index }}_{{ $eipIndex }}" {
+  value = "{{ printf "${%s.id}" $eipResource }}"
+}
+
+output "{{ $.Values.outputKeys.natGatewayEIPIPAddressPrefix }}{{ $index }}_{{ $eipIndex }}" {
+  value = "{{ printf "${%s.ip_address}" $eipResource }}"
+}
+{{- end }}
+{{- end }}
+{{- end }}
+
 resource "alicloud_snat_entry" "snat_z{{ $index }}" {
-  snat_table_id     = "{{ required "snatTableID is required" $.Values.vpc.snatTableID }}"
+  snat_table_id     = "{{ required "snatTableID is required" $snatTableID }}"
   source_vswitch_id = "${alicloud_vswitch.vsw_z{{ $index }}.id}"
-  snat_ip           = "${alicloud_eip.eip_natgw_z{{ $index }}.ip_address}"
+  snat_ip           = "{{ $snatIP }}"
 }
 
 // Output
@@ -57,9 +175,81 @@ output "{{ $.Values.outputKeys.vswitchNodesPrefix }}{{ $index }}" {
   value = "${alicloud_vswitch.vsw_z{{ $index }}.id}"
 }
 
+output "{{ $.Values.outputKeys.zoneIDPrefix }}{{ $index }}" {
+  value = "${alicloud_vswitch.vsw_z{{ $index }}.availability_zone}"
+}
+{{- if $.Values.dualStack.enabled }}
+
+output "{{ $.Values.outputKeys.vswitchNodesIPv6Prefix }}{{ $index }}" {
+  value = "${alicloud_vswitch.vsw_z{{ $index }}.ipv6_cidr_block}"
+}
+{{- end }}
+{{- if $zone.cidr.pods }}
+
+// Dedicated vswitch of the secondary ENIs of the pods, which is not used by the nodes.
+resource "alicloud_vswitch" "vsw_pods_z{{ $index }}" {
+  name              = "{{ required "clusterName is required" $.Values.clusterName }}-{{ required "zone.name is required" $zone.name }}-pods-vsw"
+  vpc_id            = "{{ required "vpc.id is required" $.Values.vpc.id }}"
+  cidr_block        = "{{ $zone.cidr.pods }}"
+  availability_zone = "{{ required "zone.name is required" $zone.name }}"
+}
+{{- if $.Values.create.routeTableAttachments }}
+
+resource "alicloud_route_table_attachment" "rta_pods_z{{ $index }}" {
+  vswitch_id     = "${alicloud_vswitch.vsw_pods_z{{ $index }}.id}"
+  route_table_id = "{{ required "vpc.routeTableID is required" $routeTableID }}"
+}
+{{- end }}
+
+resource "alicloud_snat_entry" "snat_pods_z{{ $index }}" {
+  snat_table_id     = "{{ required "snatTableID is required" $snatTableID }}"
+  source_vswitch_id = "${alicloud_vswitch.vsw_pods_z{{ $index }}.id}"
+  snat_ip           = "{{ $snatIP }}"
+}
+
+output "{{ $.Values.outputKeys.vswitchPodsPrefix }}{{ $index }}" {
+  value = "${alicloud_vswitch.vsw_pods_z{{ $index }}.id}"
+}
+{{- end }}
+{{ range $additional := $zone.additionalWorkers }}
+resource "alicloud_vswitch" "vsw_z{{ $index }}_{{ $additional.index }}" {
+  name              = "{{ required "clusterName is required" $.Values.clusterName }}-{{ required "zone.name is required" $zone.name }}-vsw-{{ $additional.index }}"
+  vpc_id            = "{{ required "vpc.id is required" $.Values.vpc.id }}"
+  cidr_block        = "{{ required "additionalWorkers.cidr is required" $additional.cidr }}"
+  availability_zone = "{{ required "zone.name is required" $zone.name }}"
+  {{- if $.Values.dualStack.enabled }}
+  ipv6_cidr_block_mask = {{ $additional.ipv6CIDRMask }}
+  {{- end }}
+}
+{{- if $.Values.create.routeTableAttachments }}
+
+resource "alicloud_route_table_attachment" "rta_z{{ $index }}_{{ $additional.index }}" {
+  vswitch_id     = "${alicloud_vswitch.vsw_z{{ $index }}_{{ $additional.index }}.id}"
+  route_table_id = "{{ required "vpc.routeTableID is required" $routeTableID }}"
+}
+{{- end }}
+
+resource "alicloud_snat_entry" "snat_z{{ $index }}_{{ $additional.index }}" {
+  snat_table_id     = "{{ required "snatTableID is required" $snatTableID }}"
+  source_vswitch_id = "${alicloud_vswitch.vsw_z{{ $index }}_{{ $additional.index }}.id}"
+  snat_ip           = "{{ $snatIP }}"
+}
+
+output "{{ $.Values.outputKeys.vswitchNodesPrefix }}{{ $index }}_{{ $additional.index }}" {
+  value = "${alicloud_vswitch.vsw_z{{ $index }}_{{ $additional.index }}.id}"
+}
+{{- if $.Values.dualStack.enabled }}
+
+output "{{ $.Values.outputKeys.vswitchNodesIPv6Prefix }}{{ $index }}_{{ $additional.index }}" {
+  value = "${alicloud_vswitch.vsw_z{{ $index }}_{{ $additional.index }}.ipv6_cidr_block}"
+}
+{{- end }}
+{{ end }}
+
 {{end}}
 // End of loop zones
 
+{{ if .Values.create.securityGroup -}}
 resource "alicloud_security_group" "sg" {
   name   = "{{ required "clusterName is required" .Values.clusterName }}-sg"
   vpc_id = "{{ required "vpc.id is required" .Values.vpc.id }}"
@@ -95,6 +285,39 @@ resource "alicloud_security_group_rule" "allow_all_internal_udp_in" {
   cidr_ip           = "{{ required "pod is required" .Values.vpc.cidr }}"
 }
 
+{{ range $index, $rule := .Values.securityGroupRules -}}
+resource "alicloud_security_group_rule" "custom_{{ $index }}" {
+  type              = "{{ required "securityGroupRules.direction is required" $rule.direction }}"
+  ip_protocol       = "{{ required "securityGroupRules.protocol is required" $rule.protocol }}"
+  policy            = "accept"
+  port_range        = "{{ required "securityGroupRules.portRange is required" $rule.portRange }}"
+  priority          = 1
+  security_group_id = "${alicloud_security_group.sg.id}"
+  cidr_ip           = "{{ required "securityGroupRules.cidr is required" $rule.cidr }}"
+}
+
+{{ end -}}
+{{ end -}}
+{{ range $index, $route := .Values.routes -}}
+resource "alicloud_route_entry" "custom_{{ $index }}" {
+  route_table_id        = "{{ required "vpc.routeTableID is required" $.Values.vpc.routeTableID }}"
+  destination_cidrblock = "{{ required "routes.destinationCIDR is required" $route.destinationCIDR }}"
+  nexthop_type          = "{{ required "routes.nextHopType is required" $route.nextHopType }}"
+  nexthop_id            = "{{ required "routes.nextHopID is required" $route.nextHopID }}"
+}
+
+{{ end -}}
+{{ if .Values.flowLog.enabled -}}
+resource "alicloud_vpc_flow_log" "flow_log" {
+  flow_log_name  = "{{ required "clusterName is required" .Values.clusterName }}-flow-log"
+  resource_type  = "VPC"
+  resource_id    = "{{ required "vpc.id is required" .Values.vpc.id }}"
+  traffic_type   = "All"
+  project_name   = "{{ required "flowLog.projectName is required" .Values.flowLog.projectName }}"
+  log_store_name = "{{ required "flowLog.logStoreName is required" .Values.flowLog.logStoreName }}"
+}
+
+{{ end -}}
 // We have introduced new output variables. However, they are not applied for
 // existing clusters as Terraform won't detect a diff when we run `terraform plan`.
 // Workaround: Providing a null-resource for letting Terraform think that there are
@@ -110,7 +333,11 @@ resource "null_resource" "outputs" {
 //=====================================================================
 
 output "{{ .Values.outputKeys.securityGroupID }}" {
+{{- if .Values.create.securityGroup }}
   value = "${alicloud_security_group.sg.id}"
+{{- else }}
+  value = "{{ required "vpc.securityGroupID is required" .Values.vpc.securityGroupID }}"
+{{- end }}
 }
 
 output "{{ .Values.outputKeys.vpcID }}" {
@@ -121,6 +348,12 @@ output "{{ .Values.outputKeys.vpcCIDR }}" {
   value = "{{ required "vpc.cidr is required" .Values.vpc.cidr }}"
 }
 
+{{ if .Values.dualStack.enabled -}}
+output "{{ .Values.outputKeys.vpcIPv6CIDR }}" {
+  value = "{{ required "vpc.ipv6CIDR is required" .Values.vpc.ipv6CIDR }}"
+}
+
+{{ end -}}
 output "{{ .Values.outputKeys.keyPairName }}" {
   value = "${alicloud_key_pair.publickey.key_name}"
 }
@@ -7,3 +7,9 @@ variable "ACCESS_KEY_SECRET" {
   description = "Alicloud access key secret"
   type        = "string"
 }
+
+variable "SECURITY_TOKEN" {
+  description = "Alicloud STS security token of temporary credentials"
+  type        = "string"
+  default     = ""
+}
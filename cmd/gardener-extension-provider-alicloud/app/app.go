@@ -20,6 +20,7 @@ import (
 	"os"
 
 	"github.com/gardener/gardener-extension-provider-alicloud/pkg/alicloud"
+	alicloudclient "github.com/gardener/gardener-extension-provider-alicloud/pkg/alicloud/client"
 	alicloudinstall "github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/alicloud/install"
 	alicloudcmd "github.com/gardener/gardener-extension-provider-alicloud/pkg/cmd"
 	alicloudbackupbucket "github.com/gardener/gardener-extension-provider-alicloud/pkg/controller/backupbucket"
@@ -152,9 +153,18 @@ func NewControllerManagerCommand(ctx context.Context) *cobra.Command {
 				&machinev1alpha1.MachineDeploymentList{},
 			)
 			configFileOpts.Completed().ApplyMachineImageOwnerSecretRef(&alicloudinfrastructure.DefaultAddOptions.MachineImageOwnerSecretRef)
+			configFileOpts.Completed().ApplyInfrastructureErrorBackoff(&alicloudinfrastructure.DefaultAddOptions.ErrorBackoff)
+			configFileOpts.Completed().ApplySeedCredentialsSecretRef(&alicloudcontrolplane.DefaultAddOptions.SeedCredentialsSecretRef)
+			configFileOpts.Completed().ApplySeedCredentialsSecretRef(&healthcheck.SeedCredentialsSecretRef)
 			configFileOpts.Completed().ApplyETCDStorage(&alicloudcontrolplaneexposure.DefaultAddOptions.ETCDStorage)
 			configFileOpts.Completed().ApplyETCDBackup(&alicloudcontrolplanebackup.DefaultAddOptions.ETCDBackup)
 			configFileOpts.Completed().ApplyHealthCheckConfig(&healthcheck.DefaultAddOptions.HealthCheckConfig)
+			configFileOpts.Completed().ApplyNodeConditionsHealthCheck(&healthcheck.NodeConditionsHealthCheck)
+			configFileOpts.Completed().ApplyBackupBucketConfig(&alicloudbackupbucket.DefaultAddOptions.BackupBucketConfig)
+			configFileOpts.Completed().ApplyBackupEntryConfig(&alicloudbackupentry.DefaultAddOptions.BackupEntryConfig)
+			configFileOpts.Completed().ApplyBackupBucketConfig(&alicloudbackupentry.DefaultAddOptions.BackupBucketConfig)
+			configFileOpts.Completed().ApplyEndpoints(&alicloudclient.CustomEndpoints)
+			configFileOpts.Completed().ApplyUseInternalOSSEndpoint(&alicloudclient.UseInternalOSSEndpoint)
 			healthCheckCtrlOpts.Completed().Apply(&healthcheck.DefaultAddOptions.Controller)
 			backupBucketCtrlOpts.Completed().Apply(&alicloudbackupbucket.DefaultAddOptions.Controller)
 			backupEntryCtrlOpts.Completed().Apply(&alicloudbackupentry.DefaultAddOptions.Controller)
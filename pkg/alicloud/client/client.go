@@ -16,8 +16,12 @@ package client
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"path"
+	"strings"
+	"time"
 
 	"github.com/gardener/gardener-extension-provider-alicloud/pkg/alicloud"
 
@@ -28,21 +32,40 @@ import (
 	alicloudvpc "github.com/aliyun/alibaba-cloud-sdk-go/services/vpc"
 	"github.com/aliyun/aliyun-oss-go-sdk/oss"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-// FactoryFunc is a function that implements the Factory interface. Used for consuming the
-// `alicloudvpc.NewClientWithAccessKey` function.
-type FactoryFunc func(region, accessKeyID, accessKeySecret string) (*alicloudvpc.Client, error)
+// FactoryFunc is a function that implements the Factory interface.
+type FactoryFunc func(ctx context.Context, region string, credentials *alicloud.Credentials) (VPC, error)
 
 // NewVPC implements Factory.
-func (f FactoryFunc) NewVPC(region, accessKeyID, accessKeySecret string) (VPC, error) {
-	return f(region, accessKeyID, accessKeySecret)
+func (f FactoryFunc) NewVPC(ctx context.Context, region string, credentials *alicloud.Credentials) (VPC, error) {
+	return f(ctx, region, credentials)
 }
 
 // DefaultFactory instantiates a default Factory.
 func DefaultFactory() Factory {
-	return FactoryFunc(alicloudvpc.NewClientWithAccessKey)
+	return FactoryFunc(newVPCClient)
+}
+
+func newVPCClient(ctx context.Context, region string, credentials *alicloud.Credentials) (VPC, error) {
+	credentials, err := ResolveCredentials(ctx, region, credentials)
+	if err != nil {
+		return nil, err
+	}
+
+	var client *alicloudvpc.Client
+	if len(credentials.SecurityToken) > 0 {
+		client, err = alicloudvpc.NewClientWithStsToken(region, credentials.AccessKeyID, credentials.AccessKeySecret, credentials.SecurityToken)
+	} else {
+		client, err = alicloudvpc.NewClientWithAccessKey(region, credentials.AccessKeyID, credentials.AccessKeySecret)
+	}
+	if err != nil {
+		return nil, err
+	}
+	setEndpoint(&client.Client, customEndpoints(region).VPC)
+	return &instrumentedVPC{&vpcClient{client}, newDefaultRetryer()}, nil
 }
 
 type storageClient struct {
@@ -56,7 +79,17 @@ func NewStorageClientFromSecretRef(ctx context.Context, client client.Client, se
 		return nil, err
 	}
 
-	ossClient, err := oss.New(ComputeStorageEndpoint(region), credentials.AccessKeyID, credentials.AccessKeySecret)
+	credentials, err = ResolveCredentials(ctx, region, credentials)
+	if err != nil {
+		return nil, err
+	}
+
+	var options []oss.ClientOption
+	if len(credentials.SecurityToken) > 0 {
+		options = append(options, oss.SecurityToken(credentials.SecurityToken))
+	}
+
+	ossClient, err := oss.New(ResolveStorageEndpoint(ctx, region), credentials.AccessKeyID, credentials.AccessKeySecret, options...)
 	if err != nil {
 		return nil, err
 	}
@@ -75,36 +108,273 @@ func (c *storageClient) DeleteObjectsWithPrefix(ctx context.Context, bucketName,
 		return err
 	}
 
+	_, err = deleteObjectsWithPrefix(ctx, bucket, prefix, nil)
+	return err
+}
+
+// DeleteObjectsWithPrefixOlderThan deletes the s3 objects with the specific <prefix> from <bucketName> which have
+// been modified before <t>. It returns the number of the remaining objects with the prefix.
+func (c *storageClient) DeleteObjectsWithPrefixOlderThan(ctx context.Context, bucketName, prefix string, t time.Time) (int, error) {
+	if len(prefix) == 0 {
+		return 0, fmt.Errorf("a prefix is required to delete the objects of bucket %s", bucketName)
+	}
+
+	bucket, err := c.client.Bucket(bucketName)
+	if err != nil {
+		return 0, err
+	}
+
+	return deleteObjectsWithPrefix(ctx, bucket, prefix, &t)
+}
+
+// ossBucket is the part of the API of an OSS bucket which is required to delete objects.
+type ossBucket interface {
+	ListObjects(options ...oss.Option) (oss.ListObjectsResult, error)
+	DeleteObjects(objectKeys []string, options ...oss.Option) (oss.DeleteObjectsResult, error)
+}
+
+// deleteObjectsWithPrefix deletes the objects with the specific <prefix> from the given bucket. If <olderThan> is set,
+// only the objects which have been modified before are deleted. The objects are listed and deleted in pages, so that
+// buckets with many objects need not be held in memory. It returns the number of the remaining objects.
+func deleteObjectsWithPrefix(ctx context.Context, bucket ossBucket, prefix string, olderThan *time.Time) (int, error) {
 	var expirationOption oss.Option
 	t, ok := ctx.Deadline()
 	if ok {
 		expirationOption = oss.Expires(t)
 	}
 
-	marker := ""
+	var (
+		marker    = ""
+		remaining = 0
+	)
 	for {
 		lsRes, err := bucket.ListObjects(oss.Marker(marker), oss.Prefix(prefix), oss.MaxKeys(1000), expirationOption)
 
 		if err != nil {
-			return err
+			return 0, err
 		}
 
 		var objectKeys []string
 		for _, object := range lsRes.Objects {
+			if !strings.HasPrefix(object.Key, prefix) {
+				continue
+			}
+			if olderThan != nil && !object.LastModified.Before(*olderThan) {
+				remaining++
+				continue
+			}
 			objectKeys = append(objectKeys, object.Key)
 		}
 
 		if len(objectKeys) != 0 {
 			if _, err := bucket.DeleteObjects(objectKeys, oss.DeleteObjectsQuiet(true), expirationOption); err != nil {
-				return err
+				return 0, err
 			}
 		}
 
 		if lsRes.IsTruncated {
 			marker = lsRes.NextMarker
 		} else {
-			return nil
+			return remaining, nil
+		}
+	}
+}
+
+// RestoreLatestArchivedSnapshots restores the archived objects of the latest full etcd snapshot with the specific
+// <prefix> in <bucketName> and of the delta snapshots taken after it, so that they can be read for a restoration of
+// etcd. It returns the number of these objects which cannot be read yet because their restore is still in progress.
+func (c *storageClient) RestoreLatestArchivedSnapshots(ctx context.Context, bucketName, prefix string) (int, error) {
+	bucket, err := c.client.Bucket(bucketName)
+	if err != nil {
+		return 0, err
+	}
+
+	return restoreLatestArchivedSnapshots(ctx, bucket, prefix)
+}
+
+// ossArchiveBucket is the part of the API of an OSS bucket which is required to restore archived objects.
+type ossArchiveBucket interface {
+	ListObjects(options ...oss.Option) (oss.ListObjectsResult, error)
+	GetObjectDetailedMeta(objectKey string, options ...oss.Option) (http.Header, error)
+	RestoreObject(objectKey string, options ...oss.Option) error
+}
+
+// restoreLatestArchivedSnapshots restores the archived objects with the specific <prefix> of the given bucket which
+// have been modified since the latest full snapshot, i.e., the objects whose name starts with `Full-`. It returns the
+// number of these objects whose restore is still in progress.
+func restoreLatestArchivedSnapshots(ctx context.Context, bucket ossArchiveBucket, prefix string) (int, error) {
+	var expirationOption oss.Option
+	t, ok := ctx.Deadline()
+	if ok {
+		expirationOption = oss.Expires(t)
+	}
+
+	var (
+		marker             = ""
+		archivedObjects    []oss.ObjectProperties
+		latestFullSnapshot *oss.ObjectProperties
+	)
+	for {
+		lsRes, err := bucket.ListObjects(oss.Marker(marker), oss.Prefix(prefix), oss.MaxKeys(1000), expirationOption)
+		if err != nil {
+			return 0, err
+		}
+
+		for i, object := range lsRes.Objects {
+			if !strings.HasPrefix(object.Key, prefix) {
+				continue
+			}
+			if strings.HasPrefix(path.Base(object.Key), "Full-") && (latestFullSnapshot == nil || object.LastModified.After(latestFullSnapshot.LastModified)) {
+				latestFullSnapshot = &lsRes.Objects[i]
+			}
+			if object.StorageClass == string(oss.StorageArchive) {
+				archivedObjects = append(archivedObjects, object)
+			}
+		}
+
+		if !lsRes.IsTruncated {
+			break
+		}
+		marker = lsRes.NextMarker
+	}
+
+	if latestFullSnapshot == nil {
+		return 0, nil
+	}
+
+	restoring := 0
+	for _, object := range archivedObjects {
+		if object.LastModified.Before(latestFullSnapshot.LastModified) {
+			continue
+		}
+
+		header, err := bucket.GetObjectDetailedMeta(object.Key, expirationOption)
+		if err != nil {
+			return 0, err
+		}
+		// The restore header is missing if the object has not been restored, and its ongoing-request is false once
+		// the object can be read.
+		restore := header.Get("X-Oss-Restore")
+		if strings.Contains(restore, `ongoing-request="false"`) {
+			continue
+		}
+		if len(restore) == 0 {
+			if err := bucket.RestoreObject(object.Key, expirationOption); err != nil {
+				return 0, err
+			}
+		}
+		restoring++
+	}
+	return restoring, nil
+}
+
+// CopyObjectsWithPrefix copies the objects with the specific <srcPrefix> in <srcBucketName> to <dstBucketName>, with
+// <srcPrefix> replaced by <dstPrefix> in their names. Both buckets have to be in the same region. It returns the number
+// of the copied objects.
+func (c *storageClient) CopyObjectsWithPrefix(ctx context.Context, srcBucketName, srcPrefix, dstBucketName, dstPrefix string) (int, error) {
+	if len(srcPrefix) == 0 || len(dstPrefix) == 0 {
+		return 0, fmt.Errorf("prefixes are required to copy the objects of bucket %s to bucket %s", srcBucketName, dstBucketName)
+	}
+
+	srcBucket, err := c.client.Bucket(srcBucketName)
+	if err != nil {
+		return 0, err
+	}
+	dstBucket, err := c.client.Bucket(dstBucketName)
+	if err != nil {
+		return 0, err
+	}
+
+	return copyObjectsWithPrefix(ctx, srcBucket, srcBucketName, srcPrefix, dstBucket, dstPrefix)
+}
+
+// ossListBucket is the part of the API of an OSS bucket which is required to list objects.
+type ossListBucket interface {
+	ListObjects(options ...oss.Option) (oss.ListObjectsResult, error)
+}
+
+// ossCopyBucket is the part of the API of an OSS bucket which is required to copy objects into it.
+type ossCopyBucket interface {
+	ossListBucket
+	CopyObjectFrom(srcBucketName, srcObjectKey, destObjectKey string, options ...oss.Option) (oss.CopyObjectResult, error)
+}
+
+// copyObjectsWithPrefix copies the objects with the specific <srcPrefix> of the given source bucket to the given
+// destination bucket, with <srcPrefix> replaced by <dstPrefix> in their names. The source has to contain a full etcd
+// snapshot, i.e., an object whose name starts with `Full-`. Objects which already exist in the destination are not
+// copied again, so that an interrupted copy is resumed. It fails if the destination only contains objects with
+// <dstPrefix> which are not in the source, e.g. backups taken by etcd, as they might be restored instead. If it also
+// contains copied objects, the copy has been completed before and etcd has taken backups since, hence nothing is
+// copied. It returns the number of the copied objects.
+func copyObjectsWithPrefix(ctx context.Context, srcBucket ossListBucket, srcBucketName, srcPrefix string, dstBucket ossCopyBucket, dstPrefix string) (int, error) {
+	var expirationOption oss.Option
+	t, ok := ctx.Deadline()
+	if ok {
+		expirationOption = oss.Expires(t)
+	}
+
+	srcKeys, err := listObjectKeys(srcBucket, srcPrefix, expirationOption)
+	if err != nil {
+		return 0, fmt.Errorf("could not list the objects with prefix %s in bucket %s: %v", srcPrefix, srcBucketName, err)
+	}
+	hasFullSnapshot := false
+	for _, key := range srcKeys.List() {
+		if strings.HasPrefix(path.Base(key), "Full-") {
+			hasFullSnapshot = true
+			break
+		}
+	}
+	if !hasFullSnapshot {
+		return 0, fmt.Errorf("there is no full snapshot with prefix %s in bucket %s", srcPrefix, srcBucketName)
+	}
+
+	dstKeys, err := listObjectKeys(dstBucket, dstPrefix, expirationOption)
+	if err != nil {
+		return 0, err
+	}
+
+	missingKeys := srcKeys.Difference(dstKeys)
+	if missingKeys.Len() == 0 {
+		return 0, nil
+	}
+	if otherKeys := dstKeys.Difference(srcKeys); otherKeys.Len() > 0 {
+		// etcd might have garbage collected some of the copied objects.
+		if dstKeys.HasAny(srcKeys.UnsortedList()...) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("%d objects with prefix %s are not copies of objects of bucket %s, e.g. %s, they have to be deleted before the objects can be copied", otherKeys.Len(), dstPrefix, srcBucketName, dstPrefix+otherKeys.List()[0])
+	}
+
+	for _, key := range missingKeys.List() {
+		if _, err := dstBucket.CopyObjectFrom(srcBucketName, srcPrefix+key, dstPrefix+key, expirationOption); err != nil {
+			return 0, fmt.Errorf("could not copy object %s of bucket %s: %v", srcPrefix+key, srcBucketName, err)
+		}
+	}
+	return missingKeys.Len(), nil
+}
+
+// listObjectKeys returns the names of the objects with the specific <prefix> of the given bucket, without the prefix.
+func listObjectKeys(bucket ossListBucket, prefix string, options ...oss.Option) (sets.String, error) {
+	var (
+		marker = ""
+		keys   = sets.NewString()
+	)
+	for {
+		lsRes, err := bucket.ListObjects(append([]oss.Option{oss.Marker(marker), oss.Prefix(prefix), oss.MaxKeys(1000)}, options...)...)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, object := range lsRes.Objects {
+			if strings.HasPrefix(object.Key, prefix) {
+				keys.Insert(strings.TrimPrefix(object.Key, prefix))
+			}
+		}
+
+		if !lsRes.IsTruncated {
+			return keys, nil
 		}
+		marker = lsRes.NextMarker
 	}
 }
 
@@ -125,52 +395,215 @@ func (c *storageClient) CreateBucketIfNotExists(ctx context.Context, bucketName
 		}
 	}
 
-	rules := []oss.LifecycleRule{
+	return c.client.SetBucketLifecycle(bucketName, []oss.LifecycleRule{defaultLifecycleRule()})
+}
+
+// defaultLifecycleRule returns the lifecycle rule of the OSS buckets which aborts incomplete multipart uploads.
+func defaultLifecycleRule() oss.LifecycleRule {
+	return oss.LifecycleRule{
+		Prefix: "",
+		Status: "Enabled",
+		AbortMultipartUpload: &oss.LifecycleAbortMultipartUpload{
+			Days: 7,
+		},
+	}
+}
+
+// SetBucketStorageClassTransition sets the lifecycle of the OSS bucket with name <bucketName> so that its objects are
+// transitioned to the storage class <storageClass> <days> days after their last modification. Incomplete multipart
+// uploads are still aborted.
+func (c *storageClient) SetBucketStorageClassTransition(ctx context.Context, bucketName, storageClass string, days int) error {
+	rule := defaultLifecycleRule()
+	rule.Transitions = []oss.LifecycleTransition{
 		{
-			Prefix: "",
-			Status: "Enabled",
-			AbortMultipartUpload: &oss.LifecycleAbortMultipartUpload{
-				Days: 7,
-			},
+			Days:         days,
+			StorageClass: oss.StorageClassType(storageClass),
 		},
 	}
-	return c.client.SetBucketLifecycle(bucketName, rules)
+	return c.client.SetBucketLifecycle(bucketName, []oss.LifecycleRule{rule})
+}
+
+// EnableBucketVersioning enables the versioning of the objects of the OSS bucket with name <bucketName>.
+func (c *storageClient) EnableBucketVersioning(ctx context.Context, bucketName string) error {
+	var expirationOption oss.Option
+	t, ok := ctx.Deadline()
+	if ok {
+		expirationOption = oss.Expires(t)
+	}
+
+	return c.client.SetBucketVersioning(bucketName, oss.VersioningConfig{Status: string(oss.VersionEnabled)}, expirationOption)
+}
+
+// GetBucketACL returns the ACL of the OSS bucket with name <bucketName>, e.g. `private`.
+func (c *storageClient) GetBucketACL(ctx context.Context, bucketName string) (string, error) {
+	result, err := c.client.GetBucketACL(bucketName)
+	if err != nil {
+		return "", err
+	}
+	return result.ACL, nil
+}
+
+// SetBucketACL sets the ACL of the OSS bucket with name <bucketName> to <acl>.
+func (c *storageClient) SetBucketACL(ctx context.Context, bucketName, acl string) error {
+	return c.client.SetBucketACL(bucketName, oss.ACLType(acl))
+}
+
+// GetBucketRegion returns the region of the OSS bucket with name <bucketName>. If it does not exist, an empty region
+// is returned.
+func (c *storageClient) GetBucketRegion(ctx context.Context, bucketName string) (string, error) {
+	location, err := c.client.GetBucketLocation(bucketName)
+	if err != nil {
+		return "", ignoreNotFound(err)
+	}
+	return strings.TrimPrefix(location, "oss-"), nil
+}
+
+// SetBucketKMSEncryption sets the default server-side encryption of the OSS bucket with name <bucketName> to KMS with
+// the key <kmsKeyID>.
+func (c *storageClient) SetBucketKMSEncryption(ctx context.Context, bucketName, kmsKeyID string) error {
+	var expirationOption oss.Option
+	t, ok := ctx.Deadline()
+	if ok {
+		expirationOption = oss.Expires(t)
+	}
+
+	rule := oss.ServerEncryptionRule{
+		SSEDefault: oss.SSEDefaultRule{
+			SSEAlgorithm:   string(oss.KMSAlgorithm),
+			KMSMasterKeyID: kmsKeyID,
+		},
+	}
+	return c.client.SetBucketEncryption(bucketName, rule, expirationOption)
+}
+
+// SetBucketLogging enables the access logging of the OSS bucket with name <bucketName> to the objects with prefix
+// <targetPrefix> in the OSS bucket with name <targetBucket>. The target bucket has to exist.
+func (c *storageClient) SetBucketLogging(ctx context.Context, bucketName, targetBucket, targetPrefix string) error {
+	exists, err := c.client.IsBucketExist(targetBucket)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("target bucket %s for the access logs does not exist, it has to be created in the region of bucket %s", targetBucket, bucketName)
+	}
+
+	return c.client.SetBucketLogging(bucketName, targetBucket, targetPrefix, true)
+}
+
+// DeleteBucketLogging disables the access logging of the OSS bucket with name <bucketName>.
+func (c *storageClient) DeleteBucketLogging(ctx context.Context, bucketName string) error {
+	return c.client.DeleteBucketLogging(bucketName)
 }
 
 // DeleteBucketIfExists deletes the Alicloud OSS bucket with name <bucketName>. If it does not exist,
-// no error is returned.
+// no error is returned. All objects of the bucket, including all versions of them, are deleted before.
 func (c *storageClient) DeleteBucketIfExists(ctx context.Context, bucketName string) error {
-	if err := c.client.DeleteBucket(bucketName); err != nil {
-		if ossErr, ok := err.(oss.ServiceError); ok {
-			switch ossErr.StatusCode {
-			case http.StatusNotFound:
-				return nil
-
-			case http.StatusConflict:
-				if err := c.DeleteObjectsWithPrefix(ctx, bucketName, ""); err != nil {
-					return err
-				}
-				return c.DeleteBucketIfExists(ctx, bucketName)
+	err := c.client.DeleteBucket(bucketName)
+	if ossErr, ok := err.(oss.ServiceError); !ok || ossErr.StatusCode != http.StatusConflict {
+		return ignoreNotFound(err)
+	}
+
+	versioning, err := c.client.GetBucketVersioning(bucketName)
+	if err != nil {
+		return err
+	}
+	if versioning.Status != "" {
+		err = c.deleteObjectVersions(ctx, bucketName)
+	} else {
+		err = c.DeleteObjectsWithPrefix(ctx, bucketName, "")
+	}
+	if err != nil {
+		return err
+	}
+
+	err = c.client.DeleteBucket(bucketName)
+	if ossErr, ok := err.(oss.ServiceError); ok && ossErr.StatusCode == http.StatusConflict {
+		// Objects which are protected by a retention policy cannot be deleted, retrying would not help.
+		return fmt.Errorf("bucket %s still contains objects after deleting all of them, they are probably protected by a retention policy: %v", bucketName, ossErr)
+	}
+	return ignoreNotFound(err)
+}
+
+// deleteObjectVersions deletes all versions of all objects and all delete markers of the OSS bucket with name
+// <bucketName>.
+func (c *storageClient) deleteObjectVersions(ctx context.Context, bucketName string) error {
+	bucket, err := c.client.Bucket(bucketName)
+	if err != nil {
+		return err
+	}
+
+	var expirationOption oss.Option
+	t, ok := ctx.Deadline()
+	if ok {
+		expirationOption = oss.Expires(t)
+	}
 
-			default:
-				return ossErr
+	keyMarker, versionIDMarker := "", ""
+	for {
+		lsRes, err := bucket.ListObjectVersions(oss.KeyMarker(keyMarker), oss.VersionIdMarker(versionIDMarker), oss.MaxKeys(1000), expirationOption)
+		if err != nil {
+			return err
+		}
+
+		objects := make([]oss.DeleteObject, 0, len(lsRes.ObjectVersions)+len(lsRes.ObjectDeleteMarkers))
+		for _, version := range lsRes.ObjectVersions {
+			objects = append(objects, oss.DeleteObject{Key: version.Key, VersionId: version.VersionId})
+		}
+		for _, deleteMarker := range lsRes.ObjectDeleteMarkers {
+			objects = append(objects, oss.DeleteObject{Key: deleteMarker.Key, VersionId: deleteMarker.VersionId})
+		}
+
+		if len(objects) != 0 {
+			if _, err := bucket.DeleteObjectVersions(objects, oss.DeleteObjectsQuiet(true), expirationOption); err != nil {
+				return err
 			}
 		}
+
+		if !lsRes.IsTruncated {
+			return nil
+		}
+		keyMarker, versionIDMarker = lsRes.NextKeyMarker, lsRes.NextVersionIdMarker
 	}
-	return nil
 }
 
-// ComputeStorageEndpoint computes the OSS storage endpoint based on the given region.
+func ignoreNotFound(err error) error {
+	if ossErr, ok := err.(oss.ServiceError); ok && ossErr.StatusCode == http.StatusNotFound {
+		return nil
+	}
+	return err
+}
+
+// ComputeStorageEndpoint computes the OSS storage endpoint based on the given region. A custom OSS endpoint of the
+// region takes precedence.
 func ComputeStorageEndpoint(region string) string {
+	if endpoint := customEndpoints(region).OSS; len(endpoint) > 0 {
+		return fmt.Sprintf("https://%s/", endpoint)
+	}
 	return fmt.Sprintf("https://oss-%s.aliyuncs.com/", region)
 }
 
+// ResolveStorageEndpoint returns the OSS storage endpoint which is used for the given region. A custom OSS endpoint of
+// the region takes precedence. Otherwise, the internal endpoint of the region is used if UseInternalOSSEndpoint is set
+// and the endpoint can be reached, the public endpoint if not.
+func ResolveStorageEndpoint(ctx context.Context, region string) string {
+	if len(customEndpoints(region).OSS) == 0 && UseInternalOSSEndpoint {
+		host := internalStorageHost(region)
+		if err := checkStorageEndpoint(ctx, host); err == nil {
+			return fmt.Sprintf("https://%s/", host)
+		}
+	}
+	return ComputeStorageEndpoint(region)
+}
+
 type clientFactory struct {
+	credentialsProvider *credentialsProvider
 }
 
 // NewClientFactory creates a new clientFactory instance that can be used to instantiate Alicloud clients
 func NewClientFactory() ClientFactory {
-	return &clientFactory{}
+	return &clientFactory{
+		credentialsProvider: defaultCredentialsProvider,
+	}
 }
 
 type ecsClient struct {
@@ -185,16 +618,27 @@ type slbClient struct {
 	client *slb.Client
 }
 
-// NewECSClient creates a new ECS client with given region, AccessKeyID, and AccessKeySecret
-func (f *clientFactory) NewECSClient(ctx context.Context, region, accessKeyID, accessKeySecret string) (ECS, error) {
-	client, err := ecs.NewClientWithAccessKey(region, accessKeyID, accessKeySecret)
+// NewECSClient creates a new ECS client with given region and credentials
+func (f *clientFactory) NewECSClient(ctx context.Context, region string, credentials *alicloud.Credentials) (ECS, error) {
+	credentials, err := f.credentialsProvider.resolve(ctx, region, credentials)
 	if err != nil {
 		return nil, err
 	}
 
-	return &ecsClient{
+	var client *ecs.Client
+	if len(credentials.SecurityToken) > 0 {
+		client, err = ecs.NewClientWithStsToken(region, credentials.AccessKeyID, credentials.AccessKeySecret, credentials.SecurityToken)
+	} else {
+		client, err = ecs.NewClientWithAccessKey(region, credentials.AccessKeyID, credentials.AccessKeySecret)
+	}
+	if err != nil {
+		return nil, err
+	}
+	setEndpoint(&client.Client, customEndpoints(region).ECS)
+
+	return &instrumentedECS{&ecsClient{
 		client: client,
-	}, nil
+	}, newDefaultRetryer()}, nil
 }
 
 // CheckIfImageExists checks whether given imageID can be accessed by the client
@@ -220,12 +664,452 @@ func (c *ecsClient) ShareImageToAccount(ctx context.Context, regionID, imageID,
 	return err
 }
 
-// NewSTSClient creates a new STS client with given region, AccessKeyID, and AccessKeySecret
-func (f *clientFactory) NewSTSClient(ctx context.Context, region, accessKeyID, accessKeySecret string) (STS, error) {
-	client, err := sts.NewClientWithAccessKey(region, accessKeyID, accessKeySecret)
+// GetImageByName returns the image with the given name owned by the account of the client. Images which are still
+// being created are considered as well. If no such image exists, nil is returned.
+func (c *ecsClient) GetImageByName(ctx context.Context, name string) (*ecs.Image, error) {
+	request := ecs.CreateDescribeImagesRequest()
+	request.ImageName = name
+	request.ImageOwnerAlias = "self"
+	request.Status = "Creating,Waiting,Available"
+	request.SetScheme("HTTPS")
+	response, err := c.client.DescribeImages(request)
+	if err != nil {
+		return nil, err
+	}
+	if len(response.Images.Image) == 0 {
+		return nil, nil
+	}
+	return &response.Images.Image[0], nil
+}
+
+// GetAvailableImageByName returns the available image with the given name which can be accessed by the client, e.g.,
+// a public image of the region. If no such image exists, nil is returned.
+func (c *ecsClient) GetAvailableImageByName(ctx context.Context, name string) (*ecs.Image, error) {
+	request := ecs.CreateDescribeImagesRequest()
+	request.ImageName = name
+	request.Status = "Available"
+	request.SetScheme("HTTPS")
+	response, err := c.client.DescribeImages(request)
 	if err != nil {
 		return nil, err
 	}
+	if len(response.Images.Image) == 0 {
+		return nil, nil
+	}
+	return &response.Images.Image[0], nil
+}
+
+// CopyEncryptedImage copies the given image to an encrypted image with the given name and tags in the same region and
+// returns the ID of the copy. If no KMS key ID is given, the default service key is used for the encryption.
+func (c *ecsClient) CopyEncryptedImage(ctx context.Context, regionID, imageID, name string, kmsKeyID *string, tags map[string]string) (string, error) {
+	request := ecs.CreateCopyImageRequest()
+	request.RegionId = regionID
+	request.ImageId = imageID
+	request.DestinationRegionId = regionID
+	request.DestinationImageName = name
+	request.Encrypted = requests.NewBoolean(true)
+	if kmsKeyID != nil {
+		request.KMSKeyId = *kmsKeyID
+	}
+	ecsTags := make([]ecs.CopyImageTag, 0, len(tags))
+	for key, value := range tags {
+		ecsTags = append(ecsTags, ecs.CopyImageTag{Key: key, Value: value})
+	}
+	request.Tag = &ecsTags
+	request.SetScheme("HTTPS")
+	response, err := c.client.CopyImage(request)
+	if err != nil {
+		return "", err
+	}
+	return response.ImageId, nil
+}
+
+// GetImagesByTags returns the images owned by the account of the client which carry all of the given tags. Images
+// which are still being created are considered as well.
+func (c *ecsClient) GetImagesByTags(ctx context.Context, tags map[string]string) ([]ecs.Image, error) {
+	var (
+		images     []ecs.Image
+		pageNumber = 1
+		pageSize   = 100
+		request    = ecs.CreateDescribeImagesRequest()
+	)
+	request.ImageOwnerAlias = "self"
+	request.Status = "Creating,Waiting,Available"
+	ecsTags := make([]ecs.DescribeImagesTag, 0, len(tags))
+	for key, value := range tags {
+		ecsTags = append(ecsTags, ecs.DescribeImagesTag{Key: key, Value: value})
+	}
+	request.Tag = &ecsTags
+	request.PageSize = requests.NewInteger(pageSize)
+	request.SetScheme("HTTPS")
+
+	for {
+		request.PageNumber = requests.NewInteger(pageNumber)
+		response, err := c.client.DescribeImages(request)
+		if err != nil {
+			return nil, err
+		}
+		images = append(images, response.Images.Image...)
+
+		if pageNumber*pageSize >= response.TotalCount {
+			break
+		}
+		pageNumber++
+	}
+	return images, nil
+}
+
+// DeleteImage deletes the given image. Images which are still used by instances are not deleted.
+func (c *ecsClient) DeleteImage(ctx context.Context, regionID, imageID string) error {
+	request := ecs.CreateDeleteImageRequest()
+	request.RegionId = regionID
+	request.ImageId = imageID
+	request.SetScheme("HTTPS")
+	_, err := c.client.DeleteImage(request)
+	return err
+}
+
+// GetPrePaidInstancesByTags returns the PrePaid (subscription) instances which have all the given tags.
+func (c *ecsClient) GetPrePaidInstancesByTags(ctx context.Context, regionID string, tags map[string]string) ([]ecs.Instance, error) {
+	var (
+		instances  []ecs.Instance
+		pageNumber = 1
+		pageSize   = 100
+		request    = ecs.CreateDescribeInstancesRequest()
+	)
+	request.RegionId = regionID
+	request.InstanceChargeType = "PrePaid"
+	ecsTags := make([]ecs.DescribeInstancesTag, 0, len(tags))
+	for key, value := range tags {
+		ecsTags = append(ecsTags, ecs.DescribeInstancesTag{Key: key, Value: value})
+	}
+	request.Tag = &ecsTags
+	request.PageSize = requests.NewInteger(pageSize)
+	request.SetScheme("HTTPS")
+
+	for {
+		request.PageNumber = requests.NewInteger(pageNumber)
+		response, err := c.client.DescribeInstances(request)
+		if err != nil {
+			return nil, err
+		}
+		instances = append(instances, response.Instances.Instance...)
+
+		if pageNumber*pageSize >= response.TotalCount {
+			break
+		}
+		pageNumber++
+	}
+	return instances, nil
+}
+
+// GetDeploymentSet returns the deployment set with the given ID. If no such deployment set exists, nil is returned.
+func (c *ecsClient) GetDeploymentSet(ctx context.Context, regionID, deploymentSetID string) (*ecs.DeploymentSet, error) {
+	request := ecs.CreateDescribeDeploymentSetsRequest()
+	request.RegionId = regionID
+	request.DeploymentSetIds = fmt.Sprintf("[%q]", deploymentSetID)
+	request.SetScheme("HTTPS")
+	response, err := c.client.DescribeDeploymentSets(request)
+	if err != nil {
+		return nil, err
+	}
+	if len(response.DeploymentSets.DeploymentSet) == 0 {
+		return nil, nil
+	}
+	return &response.DeploymentSets.DeploymentSet[0], nil
+}
+
+// GetAvailableInstanceTypes returns the instance types which are currently available in the given zone for the given
+// instance charge type.
+func (c *ecsClient) GetAvailableInstanceTypes(ctx context.Context, regionID, zoneID, instanceChargeType string) ([]string, error) {
+	request := ecs.CreateDescribeAvailableResourceRequest()
+	request.RegionId = regionID
+	request.ZoneId = zoneID
+	request.InstanceChargeType = instanceChargeType
+	request.DestinationResource = "InstanceType"
+	request.SetScheme("HTTPS")
+	response, err := c.client.DescribeAvailableResource(request)
+	if err != nil {
+		return nil, err
+	}
+
+	var instanceTypes []string
+	for _, zone := range response.AvailableZones.AvailableZone {
+		if zone.ZoneId != zoneID {
+			continue
+		}
+		for _, resource := range zone.AvailableResources.AvailableResource {
+			for _, supportedResource := range resource.SupportedResources.SupportedResource {
+				if supportedResource.Status == "Available" {
+					instanceTypes = append(instanceTypes, supportedResource.Value)
+				}
+			}
+		}
+	}
+	return instanceTypes, nil
+}
+
+// CheckIfSecurityGroupExists checks whether the security group with the given ID exists
+func (c *ecsClient) CheckIfSecurityGroupExists(ctx context.Context, securityGroupID string) (bool, error) {
+	request := ecs.CreateDescribeSecurityGroupsRequest()
+	request.SecurityGroupId = securityGroupID
+	request.SetScheme("HTTPS")
+	response, err := c.client.DescribeSecurityGroups(request)
+	if err != nil {
+		return false, err
+	}
+	return response.TotalCount > 0, nil
+}
+
+// GetSecurityGroup returns the security group with the given ID. If no such security group exists, nil is returned.
+func (c *ecsClient) GetSecurityGroup(ctx context.Context, securityGroupID string) (*ecs.SecurityGroup, error) {
+	request := ecs.CreateDescribeSecurityGroupsRequest()
+	request.SecurityGroupId = securityGroupID
+	request.SetScheme("HTTPS")
+	response, err := c.client.DescribeSecurityGroups(request)
+	if err != nil {
+		return nil, err
+	}
+	if len(response.SecurityGroups.SecurityGroup) == 0 {
+		return nil, nil
+	}
+	return &response.SecurityGroups.SecurityGroup[0], nil
+}
+
+// GetSecurityGroupsByTags returns the security groups of the given VPC which carry all of the given tags.
+func (c *ecsClient) GetSecurityGroupsByTags(ctx context.Context, vpcID string, tags map[string]string) ([]ecs.SecurityGroup, error) {
+	var (
+		securityGroups []ecs.SecurityGroup
+		pageNumber     = 1
+		pageSize       = 50
+		request        = ecs.CreateDescribeSecurityGroupsRequest()
+	)
+	request.VpcId = vpcID
+	ecsTags := make([]ecs.DescribeSecurityGroupsTag, 0, len(tags))
+	for key, value := range tags {
+		ecsTags = append(ecsTags, ecs.DescribeSecurityGroupsTag{Key: key, Value: value})
+	}
+	request.Tag = &ecsTags
+	request.PageSize = requests.NewInteger(pageSize)
+	request.SetScheme("HTTPS")
+
+	for {
+		request.PageNumber = requests.NewInteger(pageNumber)
+		response, err := c.client.DescribeSecurityGroups(request)
+		if err != nil {
+			return nil, err
+		}
+		securityGroups = append(securityGroups, response.SecurityGroups.SecurityGroup...)
+
+		if pageNumber*pageSize >= response.TotalCount {
+			break
+		}
+		pageNumber++
+	}
+	return securityGroups, nil
+}
+
+// GetSecurityGroupRules returns the rules of the security group with the given ID in both directions.
+func (c *ecsClient) GetSecurityGroupRules(ctx context.Context, securityGroupID string) ([]ecs.Permission, error) {
+	request := ecs.CreateDescribeSecurityGroupAttributeRequest()
+	request.SecurityGroupId = securityGroupID
+	request.Direction = "all"
+	request.SetScheme("HTTPS")
+	response, err := c.client.DescribeSecurityGroupAttribute(request)
+	if err != nil {
+		return nil, err
+	}
+	return response.Permissions.Permission, nil
+}
+
+// CreateSecurityGroup creates a security group with the given name in the given VPC and returns its ID
+func (c *ecsClient) CreateSecurityGroup(ctx context.Context, vpcID, name string) (string, error) {
+	request := ecs.CreateCreateSecurityGroupRequest()
+	request.VpcId = vpcID
+	request.SecurityGroupName = name
+	request.SetScheme("HTTPS")
+	response, err := c.client.CreateSecurityGroup(request)
+	if err != nil {
+		return "", err
+	}
+	return response.SecurityGroupId, nil
+}
+
+// AuthorizeSecurityGroupIngress adds an ingress rule accepting the given protocol and port range from the source CIDR
+func (c *ecsClient) AuthorizeSecurityGroupIngress(ctx context.Context, securityGroupID, ipProtocol, portRange, sourceCIDR string) error {
+	request := ecs.CreateAuthorizeSecurityGroupRequest()
+	request.SecurityGroupId = securityGroupID
+	request.IpProtocol = ipProtocol
+	request.PortRange = portRange
+	request.SourceCidrIp = sourceCIDR
+	request.Policy = "accept"
+	request.Priority = "1"
+	request.SetScheme("HTTPS")
+	_, err := c.client.AuthorizeSecurityGroup(request)
+	return err
+}
+
+// RevokeSecurityGroupIngress removes the ingress rule accepting the given protocol and port range from the source CIDR
+func (c *ecsClient) RevokeSecurityGroupIngress(ctx context.Context, securityGroupID, ipProtocol, portRange, sourceCIDR string) error {
+	request := ecs.CreateRevokeSecurityGroupRequest()
+	request.SecurityGroupId = securityGroupID
+	request.IpProtocol = ipProtocol
+	request.PortRange = portRange
+	request.SourceCidrIp = sourceCIDR
+	request.Policy = "accept"
+	request.SetScheme("HTTPS")
+	_, err := c.client.RevokeSecurityGroup(request)
+	return err
+}
+
+// AuthorizeSecurityGroupEgress adds an egress rule accepting the given protocol and port range to the destination CIDR
+func (c *ecsClient) AuthorizeSecurityGroupEgress(ctx context.Context, securityGroupID, ipProtocol, portRange, destCIDR string) error {
+	request := ecs.CreateAuthorizeSecurityGroupEgressRequest()
+	request.SecurityGroupId = securityGroupID
+	request.IpProtocol = ipProtocol
+	request.PortRange = portRange
+	request.DestCidrIp = destCIDR
+	request.Policy = "accept"
+	request.Priority = "1"
+	request.SetScheme("HTTPS")
+	_, err := c.client.AuthorizeSecurityGroupEgress(request)
+	return err
+}
+
+// RevokeSecurityGroupEgress removes the egress rule accepting the given protocol and port range to the destination CIDR
+func (c *ecsClient) RevokeSecurityGroupEgress(ctx context.Context, securityGroupID, ipProtocol, portRange, destCIDR string) error {
+	request := ecs.CreateRevokeSecurityGroupEgressRequest()
+	request.SecurityGroupId = securityGroupID
+	request.IpProtocol = ipProtocol
+	request.PortRange = portRange
+	request.DestCidrIp = destCIDR
+	request.Policy = "accept"
+	request.SetScheme("HTTPS")
+	_, err := c.client.RevokeSecurityGroupEgress(request)
+	return err
+}
+
+// RevokeSecurityGroupPermission removes the given rule of the security group with the given ID as returned by
+// GetSecurityGroupRules, regardless of its direction and policy.
+func (c *ecsClient) RevokeSecurityGroupPermission(ctx context.Context, securityGroupID string, permission ecs.Permission) error {
+	if strings.EqualFold(permission.Direction, "egress") {
+		request := ecs.CreateRevokeSecurityGroupEgressRequest()
+		request.SecurityGroupId = securityGroupID
+		request.IpProtocol = permission.IpProtocol
+		request.PortRange = permission.PortRange
+		request.SourcePortRange = permission.SourcePortRange
+		request.DestCidrIp = permission.DestCidrIp
+		request.Ipv6DestCidrIp = permission.Ipv6DestCidrIp
+		request.DestGroupId = permission.DestGroupId
+		request.DestGroupOwnerAccount = permission.DestGroupOwnerAccount
+		request.NicType = permission.NicType
+		request.Policy = permission.Policy
+		request.Priority = permission.Priority
+		request.SetScheme("HTTPS")
+		_, err := c.client.RevokeSecurityGroupEgress(request)
+		return err
+	}
+
+	request := ecs.CreateRevokeSecurityGroupRequest()
+	request.SecurityGroupId = securityGroupID
+	request.IpProtocol = permission.IpProtocol
+	request.PortRange = permission.PortRange
+	request.SourcePortRange = permission.SourcePortRange
+	request.SourceCidrIp = permission.SourceCidrIp
+	request.Ipv6SourceCidrIp = permission.Ipv6SourceCidrIp
+	request.SourceGroupId = permission.SourceGroupId
+	request.SourceGroupOwnerAccount = permission.SourceGroupOwnerAccount
+	request.NicType = permission.NicType
+	request.Policy = permission.Policy
+	request.Priority = permission.Priority
+	request.SetScheme("HTTPS")
+	_, err := c.client.RevokeSecurityGroup(request)
+	return err
+}
+
+// DeleteSecurityGroup deletes the security group with the given ID
+func (c *ecsClient) DeleteSecurityGroup(ctx context.Context, securityGroupID string) error {
+	request := ecs.CreateDeleteSecurityGroupRequest()
+	request.SecurityGroupId = securityGroupID
+	request.SetScheme("HTTPS")
+	_, err := c.client.DeleteSecurityGroup(request)
+	return err
+}
+
+// CheckIfKeyPairExists checks whether the key pair with the given name exists
+func (c *ecsClient) CheckIfKeyPairExists(ctx context.Context, name string) (bool, error) {
+	request := ecs.CreateDescribeKeyPairsRequest()
+	request.KeyPairName = name
+	request.SetScheme("HTTPS")
+	response, err := c.client.DescribeKeyPairs(request)
+	if err != nil {
+		return false, err
+	}
+	return response.TotalCount > 0, nil
+}
+
+// ImportKeyPair imports the given public key as key pair with the given name
+func (c *ecsClient) ImportKeyPair(ctx context.Context, name, publicKey string) error {
+	request := ecs.CreateImportKeyPairRequest()
+	request.KeyPairName = name
+	request.PublicKeyBody = publicKey
+	request.SetScheme("HTTPS")
+	_, err := c.client.ImportKeyPair(request)
+	return err
+}
+
+// DeleteKeyPair deletes the key pair with the given name
+func (c *ecsClient) DeleteKeyPair(ctx context.Context, name string) error {
+	request := ecs.CreateDeleteKeyPairsRequest()
+	request.KeyPairNames = fmt.Sprintf("[%q]", name)
+	request.SetScheme("HTTPS")
+	_, err := c.client.DeleteKeyPairs(request)
+	return err
+}
+
+// TagResources adds or updates the given tags of the resources with the given type and IDs
+func (c *ecsClient) TagResources(ctx context.Context, resourceType string, resourceIDs []string, tags map[string]string) error {
+	request := ecs.CreateTagResourcesRequest()
+	request.ResourceType = resourceType
+	request.ResourceId = &resourceIDs
+	ecsTags := make([]ecs.TagResourcesTag, 0, len(tags))
+	for key, value := range tags {
+		ecsTags = append(ecsTags, ecs.TagResourcesTag{Key: key, Value: value})
+	}
+	request.Tag = &ecsTags
+	request.SetScheme("HTTPS")
+	_, err := c.client.TagResources(request)
+	return err
+}
+
+// UntagResources removes the tags with the given keys from the resources with the given type and IDs
+func (c *ecsClient) UntagResources(ctx context.Context, resourceType string, resourceIDs []string, tagKeys []string) error {
+	request := ecs.CreateUntagResourcesRequest()
+	request.ResourceType = resourceType
+	request.ResourceId = &resourceIDs
+	request.TagKey = &tagKeys
+	request.SetScheme("HTTPS")
+	_, err := c.client.UntagResources(request)
+	return err
+}
+
+// NewSTSClient creates a new STS client with given region and credentials
+func (f *clientFactory) NewSTSClient(ctx context.Context, region string, credentials *alicloud.Credentials) (STS, error) {
+	credentials, err := f.credentialsProvider.resolve(ctx, region, credentials)
+	if err != nil {
+		return nil, err
+	}
+
+	var client *sts.Client
+	if len(credentials.SecurityToken) > 0 {
+		client, err = sts.NewClientWithStsToken(region, credentials.AccessKeyID, credentials.AccessKeySecret, credentials.SecurityToken)
+	} else {
+		client, err = sts.NewClientWithAccessKey(region, credentials.AccessKeyID, credentials.AccessKeySecret)
+	}
+	if err != nil {
+		return nil, err
+	}
+	setEndpoint(&client.Client, customEndpoints(region).STS)
 
 	return &stsClient{
 		client: client,
@@ -243,13 +1127,24 @@ func (c *stsClient) GetAccountIDFromCallerIdentity(ctx context.Context) (string,
 	return response.AccountId, nil
 }
 
-// NewSLBClient creates a new SLB client with given region, AccessKeyID, and AccessKeySecret
-func (f *clientFactory) NewSLBClient(ctx context.Context, region, accessKeyID, accessKeySecret string) (SLB, error) {
-	client, err := slb.NewClientWithAccessKey(region, accessKeyID, accessKeySecret)
+// NewSLBClient creates a new SLB client with given region and credentials
+func (f *clientFactory) NewSLBClient(ctx context.Context, region string, credentials *alicloud.Credentials) (SLB, error) {
+	credentials, err := f.credentialsProvider.resolve(ctx, region, credentials)
 	if err != nil {
 		return nil, err
 	}
 
+	var client *slb.Client
+	if len(credentials.SecurityToken) > 0 {
+		client, err = slb.NewClientWithStsToken(region, credentials.AccessKeyID, credentials.AccessKeySecret, credentials.SecurityToken)
+	} else {
+		client, err = slb.NewClientWithAccessKey(region, credentials.AccessKeyID, credentials.AccessKeySecret)
+	}
+	if err != nil {
+		return nil, err
+	}
+	setEndpoint(&client.Client, customEndpoints(region).SLB)
+
 	return &slbClient{
 		client: client,
 	}, nil
@@ -285,6 +1180,25 @@ func (c *slbClient) GetLoadBalancerIDs(ctx context.Context, region string) ([]st
 	return loadBalancerIDs, nil
 }
 
+// GetLoadBalancerIDByAddress returns the ID of the LoadBalancer with the given address in the given region, or an empty
+// string if it does not exist.
+func (c *slbClient) GetLoadBalancerIDByAddress(ctx context.Context, region, address string) (string, error) {
+	request := slb.CreateDescribeLoadBalancersRequest()
+	request.SetScheme("HTTPS")
+	request.RegionId = region
+	request.Address = address
+	response, err := c.client.DescribeLoadBalancers(request)
+	if err != nil {
+		return "", err
+	}
+	for _, loadBalancer := range response.LoadBalancers.LoadBalancer {
+		if loadBalancer.Address == address {
+			return loadBalancer.LoadBalancerId, nil
+		}
+	}
+	return "", nil
+}
+
 // GetFirstVServerGroupName gets the VServerGroupName of the first VServerGroup in the LoadBalancer with given region and loadBalancerID
 func (c *slbClient) GetFirstVServerGroupName(ctx context.Context, region, loadBalancerID string) (string, error) {
 	request := slb.CreateDescribeVServerGroupsRequest()
@@ -310,3 +1224,122 @@ func (c *slbClient) DeleteLoadBalancer(ctx context.Context, region, loadBalancer
 	_, err := c.client.DeleteLoadBalancer(request)
 	return err
 }
+
+// GetAccessControlListID returns the ID of the access control list with the given name, or an empty string if it does
+// not exist.
+func (c *slbClient) GetAccessControlListID(ctx context.Context, region, name string) (string, error) {
+	request := slb.CreateDescribeAccessControlListsRequest()
+	request.SetScheme("HTTPS")
+	request.RegionId = region
+	request.AclName = name
+	response, err := c.client.DescribeAccessControlLists(request)
+	if err != nil {
+		return "", err
+	}
+	// The name is matched fuzzily by the API.
+	for _, acl := range response.Acls.Acl {
+		if acl.AclName == name {
+			return acl.AclId, nil
+		}
+	}
+	return "", nil
+}
+
+// CreateAccessControlList creates an IPv4 access control list with the given name and returns its ID.
+func (c *slbClient) CreateAccessControlList(ctx context.Context, region, name string) (string, error) {
+	request := slb.CreateCreateAccessControlListRequest()
+	request.SetScheme("HTTPS")
+	request.RegionId = region
+	request.AclName = name
+	request.AddressIPVersion = "ipv4"
+	response, err := c.client.CreateAccessControlList(request)
+	if err != nil {
+		return "", err
+	}
+	return response.AclId, nil
+}
+
+// GetAccessControlListEntries returns the CIDRs of the entries of the access control list with the given ID.
+func (c *slbClient) GetAccessControlListEntries(ctx context.Context, region, aclID string) ([]string, error) {
+	request := slb.CreateDescribeAccessControlListAttributeRequest()
+	request.SetScheme("HTTPS")
+	request.RegionId = region
+	request.AclId = aclID
+	response, err := c.client.DescribeAccessControlListAttribute(request)
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]string, 0, len(response.AclEntrys.AclEntry))
+	for _, entry := range response.AclEntrys.AclEntry {
+		entries = append(entries, entry.AclEntryIP)
+	}
+	return entries, nil
+}
+
+// maxAccessControlListEntriesPerRequest is the maximum number of entries which can be added to or removed from an
+// access control list with a single request.
+const maxAccessControlListEntriesPerRequest = 50
+
+// AddAccessControlListEntries adds entries with the given CIDRs to the access control list with the given ID.
+func (c *slbClient) AddAccessControlListEntries(ctx context.Context, region, aclID string, entries []string) error {
+	return forEachAccessControlListEntriesChunk(entries, func(aclEntrys string) error {
+		request := slb.CreateAddAccessControlListEntryRequest()
+		request.SetScheme("HTTPS")
+		request.RegionId = region
+		request.AclId = aclID
+		request.AclEntrys = aclEntrys
+		_, err := c.client.AddAccessControlListEntry(request)
+		return err
+	})
+}
+
+// RemoveAccessControlListEntries removes the entries with the given CIDRs from the access control list with the given ID.
+func (c *slbClient) RemoveAccessControlListEntries(ctx context.Context, region, aclID string, entries []string) error {
+	return forEachAccessControlListEntriesChunk(entries, func(aclEntrys string) error {
+		request := slb.CreateRemoveAccessControlListEntryRequest()
+		request.SetScheme("HTTPS")
+		request.RegionId = region
+		request.AclId = aclID
+		request.AclEntrys = aclEntrys
+		_, err := c.client.RemoveAccessControlListEntry(request)
+		return err
+	})
+}
+
+// forEachAccessControlListEntriesChunk calls the given function with the JSON representation of the given entries
+// in chunks which do not exceed the limit of the API.
+func forEachAccessControlListEntriesChunk(entries []string, fn func(aclEntrys string) error) error {
+	type aclEntry struct {
+		Entry string `json:"entry"`
+	}
+
+	for start := 0; start < len(entries); start += maxAccessControlListEntriesPerRequest {
+		end := start + maxAccessControlListEntriesPerRequest
+		if end > len(entries) {
+			end = len(entries)
+		}
+
+		chunk := make([]aclEntry, 0, end-start)
+		for _, entry := range entries[start:end] {
+			chunk = append(chunk, aclEntry{Entry: entry})
+		}
+		aclEntrys, err := json.Marshal(chunk)
+		if err != nil {
+			return err
+		}
+		if err := fn(string(aclEntrys)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteAccessControlList deletes the access control list with the given ID.
+func (c *slbClient) DeleteAccessControlList(ctx context.Context, region, aclID string) error {
+	request := slb.CreateDeleteAccessControlListRequest()
+	request.SetScheme("HTTPS")
+	request.RegionId = region
+	request.AclId = aclID
+	_, err := c.client.DeleteAccessControlList(request)
+	return err
+}
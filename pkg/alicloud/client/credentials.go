@@ -0,0 +1,145 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gardener/gardener-extension-provider-alicloud/pkg/alicloud"
+
+	"github.com/aliyun/alibaba-cloud-sdk-go/sdk/requests"
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/sts"
+)
+
+const (
+	// roleSessionDuration is the duration for which the temporary credentials of an assumed RAM role are requested.
+	roleSessionDuration = time.Hour
+	// roleSessionRefreshMargin is the minimum remaining lifetime of cached temporary credentials. Credentials which
+	// expire earlier are refreshed, so that long running operations (e.g. Terraform) do not run out of time.
+	roleSessionRefreshMargin = 20 * time.Minute
+)
+
+// assumeRoleAPI is the part of the STS API which is required to assume RAM roles.
+type assumeRoleAPI interface {
+	AssumeRole(request *sts.AssumeRoleRequest) (*sts.AssumeRoleResponse, error)
+}
+
+type credentialsKey struct {
+	accessKeyID     string
+	accessKeySecret string
+	roleARN         string
+	roleSessionName string
+}
+
+type temporaryCredentials struct {
+	credentials *alicloud.Credentials
+	expiration  time.Time
+}
+
+// credentialsProvider exchanges credentials which carry a RAM role for temporary credentials of that role. The
+// temporary credentials are cached and shared by all clients until shortly before they expire.
+type credentialsProvider struct {
+	newAssumeRoleAPI func(region, accessKeyID, accessKeySecret string) (assumeRoleAPI, error)
+	now              func() time.Time
+
+	lock  sync.Mutex
+	cache map[credentialsKey]*temporaryCredentials
+}
+
+func newCredentialsProvider() *credentialsProvider {
+	return &credentialsProvider{
+		newAssumeRoleAPI: func(region, accessKeyID, accessKeySecret string) (assumeRoleAPI, error) {
+			client, err := sts.NewClientWithAccessKey(region, accessKeyID, accessKeySecret)
+			if err != nil {
+				return nil, err
+			}
+			setEndpoint(&client.Client, customEndpoints(region).STS)
+			return client, nil
+		},
+		now:   time.Now,
+		cache: make(map[credentialsKey]*temporaryCredentials),
+	}
+}
+
+// defaultCredentialsProvider is shared by all clients of this process, so that the controllers do not assume the
+// same role over and over again.
+var defaultCredentialsProvider = newCredentialsProvider()
+
+// ResolveCredentials returns the credentials which have to be used to access the Alicloud API. If the given
+// credentials carry a RAM role, temporary credentials of this role are returned, otherwise the given credentials.
+func ResolveCredentials(ctx context.Context, region string, credentials *alicloud.Credentials) (*alicloud.Credentials, error) {
+	return defaultCredentialsProvider.resolve(ctx, region, credentials)
+}
+
+func (p *credentialsProvider) resolve(_ context.Context, region string, credentials *alicloud.Credentials) (*alicloud.Credentials, error) {
+	if len(credentials.RoleARN) == 0 {
+		return credentials, nil
+	}
+
+	key := credentialsKey{
+		accessKeyID:     credentials.AccessKeyID,
+		accessKeySecret: credentials.AccessKeySecret,
+		roleARN:         credentials.RoleARN,
+		roleSessionName: credentials.RoleSessionName,
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if cached, ok := p.cache[key]; ok && p.now().Add(roleSessionRefreshMargin).Before(cached.expiration) {
+		return cached.credentials, nil
+	}
+
+	client, err := p.newAssumeRoleAPI(region, credentials.AccessKeyID, credentials.AccessKeySecret)
+	if err != nil {
+		return nil, err
+	}
+
+	request := sts.CreateAssumeRoleRequest()
+	request.SetScheme("HTTPS")
+	request.RoleArn = credentials.RoleARN
+	request.RoleSessionName = credentials.RoleSessionName
+	request.DurationSeconds = requests.NewInteger(int(roleSessionDuration.Seconds()))
+	response, err := client.AssumeRole(request)
+	if err != nil {
+		return nil, fmt.Errorf("could not assume role %s: %v", credentials.RoleARN, err)
+	}
+
+	expiration, err := time.Parse(time.RFC3339, response.Credentials.Expiration)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse expiration of the temporary credentials of role %s: %v", credentials.RoleARN, err)
+	}
+
+	temporary := &temporaryCredentials{
+		credentials: &alicloud.Credentials{
+			AccessKeyID:     response.Credentials.AccessKeyId,
+			AccessKeySecret: response.Credentials.AccessKeySecret,
+			SecurityToken:   response.Credentials.SecurityToken,
+		},
+		expiration: expiration,
+	}
+
+	for k, cached := range p.cache {
+		if !p.now().Before(cached.expiration) {
+			delete(p.cache, k)
+		}
+	}
+	p.cache[key] = temporary
+
+	return temporary.credentials, nil
+}
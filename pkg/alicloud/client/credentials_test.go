@@ -0,0 +1,157 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gardener/gardener-extension-provider-alicloud/pkg/alicloud"
+
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/sts"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// fakeSTS issues numbered temporary credentials which expire after the requested duration.
+type fakeSTS struct {
+	now      func() time.Time
+	requests []*sts.AssumeRoleRequest
+	err      error
+}
+
+func (f *fakeSTS) AssumeRole(request *sts.AssumeRoleRequest) (*sts.AssumeRoleResponse, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	f.requests = append(f.requests, request)
+
+	duration, err := request.DurationSeconds.GetValue()
+	if err != nil {
+		return nil, err
+	}
+
+	response := sts.CreateAssumeRoleResponse()
+	response.Credentials = sts.Credentials{
+		AccessKeyId:     fmt.Sprintf("STS.id-%d", len(f.requests)),
+		AccessKeySecret: fmt.Sprintf("secret-%d", len(f.requests)),
+		SecurityToken:   fmt.Sprintf("token-%d", len(f.requests)),
+		Expiration:      f.now().Add(time.Duration(duration) * time.Second).UTC().Format(time.RFC3339),
+	}
+	return response, nil
+}
+
+var _ = Describe("Credentials", func() {
+	var (
+		ctx = context.TODO()
+
+		now      time.Time
+		fake     *fakeSTS
+		provider *credentialsProvider
+
+		credentials *alicloud.Credentials
+	)
+
+	BeforeEach(func() {
+		now = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+		fake = &fakeSTS{now: func() time.Time { return now }}
+
+		provider = newCredentialsProvider()
+		provider.now = func() time.Time { return now }
+		provider.newAssumeRoleAPI = func(region, accessKeyID, accessKeySecret string) (assumeRoleAPI, error) {
+			Expect(region).To(Equal("cn-beijing"))
+			Expect(accessKeyID).To(Equal("id"))
+			Expect(accessKeySecret).To(Equal("secret"))
+			return fake, nil
+		}
+
+		credentials = &alicloud.Credentials{
+			AccessKeyID:     "id",
+			AccessKeySecret: "secret",
+			RoleARN:         "acs:ram::1234567890:role/gardener",
+			RoleSessionName: "gardener",
+		}
+	})
+
+	Describe("#resolve", func() {
+		It("should return credentials without a role unchanged", func() {
+			credentials.RoleARN = ""
+
+			Expect(provider.resolve(ctx, "cn-beijing", credentials)).To(BeIdenticalTo(credentials))
+			Expect(fake.requests).To(BeEmpty())
+		})
+
+		It("should assume the role and return the temporary credentials", func() {
+			Expect(provider.resolve(ctx, "cn-beijing", credentials)).To(Equal(&alicloud.Credentials{
+				AccessKeyID:     "STS.id-1",
+				AccessKeySecret: "secret-1",
+				SecurityToken:   "token-1",
+			}))
+
+			Expect(fake.requests).To(HaveLen(1))
+			Expect(fake.requests[0].RoleArn).To(Equal("acs:ram::1234567890:role/gardener"))
+			Expect(fake.requests[0].RoleSessionName).To(Equal("gardener"))
+		})
+
+		It("should reuse the temporary credentials until they are about to expire", func() {
+			first, err := provider.resolve(ctx, "cn-beijing", credentials)
+			Expect(err).NotTo(HaveOccurred())
+
+			now = now.Add(roleSessionDuration - roleSessionRefreshMargin - time.Minute)
+			Expect(provider.resolve(ctx, "cn-beijing", credentials)).To(BeIdenticalTo(first))
+			Expect(fake.requests).To(HaveLen(1))
+
+			now = now.Add(time.Minute)
+			Expect(provider.resolve(ctx, "cn-beijing", credentials)).To(Equal(&alicloud.Credentials{
+				AccessKeyID:     "STS.id-2",
+				AccessKeySecret: "secret-2",
+				SecurityToken:   "token-2",
+			}))
+			Expect(fake.requests).To(HaveLen(2))
+		})
+
+		It("should not share temporary credentials between different roles", func() {
+			_, err := provider.resolve(ctx, "cn-beijing", credentials)
+			Expect(err).NotTo(HaveOccurred())
+
+			other := *credentials
+			other.RoleARN = "acs:ram::1234567890:role/other"
+			resolved, err := provider.resolve(ctx, "cn-beijing", &other)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resolved.SecurityToken).To(Equal("token-2"))
+		})
+
+		It("should drop expired temporary credentials from the cache", func() {
+			_, err := provider.resolve(ctx, "cn-beijing", credentials)
+			Expect(err).NotTo(HaveOccurred())
+
+			now = now.Add(2 * roleSessionDuration)
+			other := *credentials
+			other.RoleARN = "acs:ram::1234567890:role/other"
+			_, err = provider.resolve(ctx, "cn-beijing", &other)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(provider.cache).To(HaveLen(1))
+		})
+
+		It("should fail if the role cannot be assumed", func() {
+			fake.err = fmt.Errorf("forbidden")
+
+			_, err := provider.resolve(ctx, "cn-beijing", credentials)
+			Expect(err).To(MatchError(ContainSubstring("could not assume role acs:ram::1234567890:role/gardener")))
+		})
+	})
+})
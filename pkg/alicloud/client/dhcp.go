@@ -0,0 +1,186 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"github.com/aliyun/alibaba-cloud-sdk-go/sdk/requests"
+	"github.com/aliyun/alibaba-cloud-sdk-go/sdk/responses"
+	alicloudvpc "github.com/aliyun/alibaba-cloud-sdk-go/services/vpc"
+)
+
+// The vendored version of the VPC SDK predates the DHCP options set API, hence its requests and responses are
+// declared here in the same way as the ones of the SDK.
+
+// vpcClient is the VPC client of the SDK extended by the DHCP options set API.
+type vpcClient struct {
+	*alicloudvpc.Client
+}
+
+// CreateDhcpOptionsSet creates a DHCP options set.
+func (c *vpcClient) CreateDhcpOptionsSet(req *CreateDhcpOptionsSetRequest) (res *CreateDhcpOptionsSetResponse, err error) {
+	res = &CreateDhcpOptionsSetResponse{BaseResponse: &responses.BaseResponse{}}
+	err = c.DoAction(req, res)
+	return
+}
+
+// GetDhcpOptionsSet gets a DHCP options set and the VPCs it is associated with.
+func (c *vpcClient) GetDhcpOptionsSet(req *GetDhcpOptionsSetRequest) (res *GetDhcpOptionsSetResponse, err error) {
+	res = &GetDhcpOptionsSetResponse{BaseResponse: &responses.BaseResponse{}}
+	err = c.DoAction(req, res)
+	return
+}
+
+// UpdateDhcpOptionsSetAttribute updates the options of a DHCP options set.
+func (c *vpcClient) UpdateDhcpOptionsSetAttribute(req *UpdateDhcpOptionsSetAttributeRequest) (res *DhcpOptionsSetResponse, err error) {
+	res = &DhcpOptionsSetResponse{BaseResponse: &responses.BaseResponse{}}
+	err = c.DoAction(req, res)
+	return
+}
+
+// AttachDhcpOptionsSetToVpc associates a DHCP options set with a VPC.
+func (c *vpcClient) AttachDhcpOptionsSetToVpc(req *AttachDhcpOptionsSetToVpcRequest) (res *DhcpOptionsSetResponse, err error) {
+	res = &DhcpOptionsSetResponse{BaseResponse: &responses.BaseResponse{}}
+	err = c.DoAction(req, res)
+	return
+}
+
+// DetachDhcpOptionsSetFromVpc unassociates a DHCP options set from a VPC.
+func (c *vpcClient) DetachDhcpOptionsSetFromVpc(req *DetachDhcpOptionsSetFromVpcRequest) (res *DhcpOptionsSetResponse, err error) {
+	res = &DhcpOptionsSetResponse{BaseResponse: &responses.BaseResponse{}}
+	err = c.DoAction(req, res)
+	return
+}
+
+// DeleteDhcpOptionsSet deletes a DHCP options set.
+func (c *vpcClient) DeleteDhcpOptionsSet(req *DeleteDhcpOptionsSetRequest) (res *DhcpOptionsSetResponse, err error) {
+	res = &DhcpOptionsSetResponse{BaseResponse: &responses.BaseResponse{}}
+	err = c.DoAction(req, res)
+	return
+}
+
+// CreateDhcpOptionsSetRequest is the request struct for api CreateDhcpOptionsSet
+type CreateDhcpOptionsSetRequest struct {
+	*requests.RpcRequest
+	DhcpOptionsSetName        string `position:"Query" name:"DhcpOptionsSetName"`
+	DhcpOptionsSetDescription string `position:"Query" name:"DhcpOptionsSetDescription"`
+	DomainName                string `position:"Query" name:"DomainName"`
+	DomainNameServers         string `position:"Query" name:"DomainNameServers"`
+}
+
+// CreateDhcpOptionsSetResponse is the response struct for api CreateDhcpOptionsSet
+type CreateDhcpOptionsSetResponse struct {
+	*responses.BaseResponse
+	RequestId        string `json:"RequestId" xml:"RequestId"`
+	DhcpOptionsSetId string `json:"DhcpOptionsSetId" xml:"DhcpOptionsSetId"`
+}
+
+// GetDhcpOptionsSetRequest is the request struct for api GetDhcpOptionsSet
+type GetDhcpOptionsSetRequest struct {
+	*requests.RpcRequest
+	DhcpOptionsSetId string `position:"Query" name:"DhcpOptionsSetId"`
+}
+
+// GetDhcpOptionsSetResponse is the response struct for api GetDhcpOptionsSet
+type GetDhcpOptionsSetResponse struct {
+	*responses.BaseResponse
+	RequestId          string         `json:"RequestId" xml:"RequestId"`
+	DhcpOptionsSetId   string         `json:"DhcpOptionsSetId" xml:"DhcpOptionsSetId"`
+	DhcpOptionsSetName string         `json:"DhcpOptionsSetName" xml:"DhcpOptionsSetName"`
+	Status             string         `json:"Status" xml:"Status"`
+	DhcpOptions        DhcpOptions    `json:"DhcpOptions" xml:"DhcpOptions"`
+	AssociateVpcs      []AssociateVpc `json:"AssociateVpcs" xml:"AssociateVpcs"`
+}
+
+// DhcpOptions is a nested struct in vpc response
+type DhcpOptions struct {
+	DomainName        string `json:"DomainName" xml:"DomainName"`
+	DomainNameServers string `json:"DomainNameServers" xml:"DomainNameServers"`
+}
+
+// AssociateVpc is a nested struct in vpc response
+type AssociateVpc struct {
+	VpcId           string `json:"VpcId" xml:"VpcId"`
+	AssociateStatus string `json:"AssociateStatus" xml:"AssociateStatus"`
+}
+
+// UpdateDhcpOptionsSetAttributeRequest is the request struct for api UpdateDhcpOptionsSetAttribute
+type UpdateDhcpOptionsSetAttributeRequest struct {
+	*requests.RpcRequest
+	DhcpOptionsSetId  string `position:"Query" name:"DhcpOptionsSetId"`
+	DomainName        string `position:"Query" name:"DomainName"`
+	DomainNameServers string `position:"Query" name:"DomainNameServers"`
+}
+
+// AttachDhcpOptionsSetToVpcRequest is the request struct for api AttachDhcpOptionsSetToVpc
+type AttachDhcpOptionsSetToVpcRequest struct {
+	*requests.RpcRequest
+	DhcpOptionsSetId string `position:"Query" name:"DhcpOptionsSetId"`
+	VpcId            string `position:"Query" name:"VpcId"`
+}
+
+// DetachDhcpOptionsSetFromVpcRequest is the request struct for api DetachDhcpOptionsSetFromVpc
+type DetachDhcpOptionsSetFromVpcRequest struct {
+	*requests.RpcRequest
+	DhcpOptionsSetId string `position:"Query" name:"DhcpOptionsSetId"`
+	VpcId            string `position:"Query" name:"VpcId"`
+}
+
+// DeleteDhcpOptionsSetRequest is the request struct for api DeleteDhcpOptionsSet
+type DeleteDhcpOptionsSetRequest struct {
+	*requests.RpcRequest
+	DhcpOptionsSetId string `position:"Query" name:"DhcpOptionsSetId"`
+}
+
+// DhcpOptionsSetResponse is the response struct for the DHCP options set apis which only return the request id.
+type DhcpOptionsSetResponse struct {
+	*responses.BaseResponse
+	RequestId string `json:"RequestId" xml:"RequestId"`
+}
+
+// CreateCreateDhcpOptionsSetRequest creates a request to invoke CreateDhcpOptionsSet API
+func CreateCreateDhcpOptionsSetRequest() *CreateDhcpOptionsSetRequest {
+	return &CreateDhcpOptionsSetRequest{RpcRequest: newVPCRpcRequest("CreateDhcpOptionsSet")}
+}
+
+// CreateGetDhcpOptionsSetRequest creates a request to invoke GetDhcpOptionsSet API
+func CreateGetDhcpOptionsSetRequest() *GetDhcpOptionsSetRequest {
+	return &GetDhcpOptionsSetRequest{RpcRequest: newVPCRpcRequest("GetDhcpOptionsSet")}
+}
+
+// CreateUpdateDhcpOptionsSetAttributeRequest creates a request to invoke UpdateDhcpOptionsSetAttribute API
+func CreateUpdateDhcpOptionsSetAttributeRequest() *UpdateDhcpOptionsSetAttributeRequest {
+	return &UpdateDhcpOptionsSetAttributeRequest{RpcRequest: newVPCRpcRequest("UpdateDhcpOptionsSetAttribute")}
+}
+
+// CreateAttachDhcpOptionsSetToVpcRequest creates a request to invoke AttachDhcpOptionsSetToVpc API
+func CreateAttachDhcpOptionsSetToVpcRequest() *AttachDhcpOptionsSetToVpcRequest {
+	return &AttachDhcpOptionsSetToVpcRequest{RpcRequest: newVPCRpcRequest("AttachDhcpOptionsSetToVpc")}
+}
+
+// CreateDetachDhcpOptionsSetFromVpcRequest creates a request to invoke DetachDhcpOptionsSetFromVpc API
+func CreateDetachDhcpOptionsSetFromVpcRequest() *DetachDhcpOptionsSetFromVpcRequest {
+	return &DetachDhcpOptionsSetFromVpcRequest{RpcRequest: newVPCRpcRequest("DetachDhcpOptionsSetFromVpc")}
+}
+
+// CreateDeleteDhcpOptionsSetRequest creates a request to invoke DeleteDhcpOptionsSet API
+func CreateDeleteDhcpOptionsSetRequest() *DeleteDhcpOptionsSetRequest {
+	return &DeleteDhcpOptionsSetRequest{RpcRequest: newVPCRpcRequest("DeleteDhcpOptionsSet")}
+}
+
+func newVPCRpcRequest(action string) *requests.RpcRequest {
+	req := &requests.RpcRequest{}
+	req.InitWithApiInfo("Vpc", "2016-04-28", action, "vpc", "openAPI")
+	return req
+}
@@ -0,0 +1,74 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/aliyun/alibaba-cloud-sdk-go/sdk"
+)
+
+// RegionEndpoints are the custom endpoints of the Alicloud APIs in a region. The endpoints are host names, empty
+// endpoints are resolved by the Alicloud SDK.
+type RegionEndpoints struct {
+	ECS string
+	VPC string
+	OSS string
+	STS string
+	SLB string
+}
+
+// CustomEndpoints maps regions to the custom endpoints of the Alicloud APIs in these regions, e.g., for the regions
+// of the Alibaba Finance Cloud. It has to be set before any client is created.
+var CustomEndpoints map[string]RegionEndpoints
+
+// UseInternalOSSEndpoint specifies whether the internal OSS endpoint of a region is used instead of the public one if
+// it can be reached, e.g., if the seed runs in the same region as the backup buckets. It has to be set before any
+// client is created.
+var UseInternalOSSEndpoint bool
+
+// storageEndpointDialTimeout is the timeout for checking whether the internal OSS endpoint can be reached.
+const storageEndpointDialTimeout = 3 * time.Second
+
+// checkStorageEndpoint checks whether a connection to the OSS endpoint with the given host name can be established.
+var checkStorageEndpoint = func(ctx context.Context, host string) error {
+	dialer := &net.Dialer{Timeout: storageEndpointDialTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(host, "443"))
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+// internalStorageHost returns the host name of the internal OSS endpoint of the given region, which can only be
+// reached from within the VPCs of the region.
+func internalStorageHost(region string) string {
+	return fmt.Sprintf("oss-%s-internal.aliyuncs.com", region)
+}
+
+// customEndpoints returns the custom endpoints of the given region.
+func customEndpoints(region string) RegionEndpoints {
+	return CustomEndpoints[region]
+}
+
+// setEndpoint makes the given SDK client send all requests to the given endpoint, if any.
+func setEndpoint(client *sdk.Client, endpoint string) {
+	if len(endpoint) > 0 {
+		client.Domain = endpoint
+	}
+}
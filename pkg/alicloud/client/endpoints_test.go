@@ -0,0 +1,135 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gardener/gardener-extension-provider-alicloud/pkg/alicloud"
+
+	alicloudsts "github.com/aliyun/alibaba-cloud-sdk-go/services/sts"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Endpoints", func() {
+	var (
+		ctx         = context.TODO()
+		region      = "cn-shanghai-finance-1"
+		credentials = &alicloud.Credentials{AccessKeyID: "id", AccessKeySecret: "secret"}
+		factory     = NewClientFactory()
+	)
+
+	BeforeEach(func() {
+		CustomEndpoints = map[string]RegionEndpoints{
+			region: {
+				ECS: "ecs.finance.example.com",
+				VPC: "vpc.finance.example.com",
+				OSS: "oss.finance.example.com",
+				STS: "sts.finance.example.com",
+				SLB: "slb.finance.example.com",
+			},
+		}
+	})
+
+	AfterEach(func() {
+		CustomEndpoints = nil
+	})
+
+	It("should use the custom endpoints of the region", func() {
+		ecs, err := factory.NewECSClient(ctx, region, credentials)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ecs.(*instrumentedECS).ECS.(*ecsClient).client.Domain).To(Equal("ecs.finance.example.com"))
+
+		vpc, err := DefaultFactory().NewVPC(ctx, region, credentials)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(vpc.(*instrumentedVPC).VPC.(*vpcClient).Domain).To(Equal("vpc.finance.example.com"))
+
+		sts, err := factory.NewSTSClient(ctx, region, credentials)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sts.(*stsClient).client.Domain).To(Equal("sts.finance.example.com"))
+
+		assumeRoleAPI, err := newCredentialsProvider().newAssumeRoleAPI(region, credentials.AccessKeyID, credentials.AccessKeySecret)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(assumeRoleAPI.(*alicloudsts.Client).Domain).To(Equal("sts.finance.example.com"))
+
+		slb, err := factory.NewSLBClient(ctx, region, credentials)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(slb.(*slbClient).client.Domain).To(Equal("slb.finance.example.com"))
+
+		Expect(ComputeStorageEndpoint(region)).To(Equal("https://oss.finance.example.com/"))
+	})
+
+	It("should leave the endpoints of other regions to the SDK", func() {
+		ecs, err := factory.NewECSClient(ctx, "eu-central-1", credentials)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ecs.(*instrumentedECS).ECS.(*ecsClient).client.Domain).To(BeEmpty())
+
+		Expect(ComputeStorageEndpoint("eu-central-1")).To(Equal("https://oss-eu-central-1.aliyuncs.com/"))
+	})
+
+	Describe("#ResolveStorageEndpoint", func() {
+		var (
+			oldCheckStorageEndpoint = checkStorageEndpoint
+			checkedHosts            []string
+			reachable               bool
+		)
+
+		BeforeEach(func() {
+			checkedHosts = nil
+			reachable = true
+			checkStorageEndpoint = func(_ context.Context, host string) error {
+				checkedHosts = append(checkedHosts, host)
+				if !reachable {
+					return fmt.Errorf("dial tcp %s:443: i/o timeout", host)
+				}
+				return nil
+			}
+		})
+
+		AfterEach(func() {
+			checkStorageEndpoint = oldCheckStorageEndpoint
+			UseInternalOSSEndpoint = false
+		})
+
+		It("should use the public endpoint by default", func() {
+			Expect(ResolveStorageEndpoint(ctx, "eu-central-1")).To(Equal("https://oss-eu-central-1.aliyuncs.com/"))
+			Expect(checkedHosts).To(BeEmpty())
+		})
+
+		It("should use the internal endpoint if enabled and reachable", func() {
+			UseInternalOSSEndpoint = true
+
+			Expect(ResolveStorageEndpoint(ctx, "eu-central-1")).To(Equal("https://oss-eu-central-1-internal.aliyuncs.com/"))
+			Expect(checkedHosts).To(ConsistOf("oss-eu-central-1-internal.aliyuncs.com"))
+		})
+
+		It("should fall back to the public endpoint if the internal endpoint cannot be reached", func() {
+			UseInternalOSSEndpoint = true
+			reachable = false
+
+			Expect(ResolveStorageEndpoint(ctx, "eu-central-1")).To(Equal("https://oss-eu-central-1.aliyuncs.com/"))
+			Expect(checkedHosts).To(ConsistOf("oss-eu-central-1-internal.aliyuncs.com"))
+		})
+
+		It("should prefer the custom endpoint of the region", func() {
+			UseInternalOSSEndpoint = true
+
+			Expect(ResolveStorageEndpoint(ctx, region)).To(Equal("https://oss.finance.example.com/"))
+			Expect(checkedHosts).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,530 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/ecs"
+	alicloudvpc "github.com/aliyun/alibaba-cloud-sdk-go/services/vpc"
+)
+
+// instrumentedVPC is a VPC client which retries throttled requests and records the duration of all requests.
+type instrumentedVPC struct {
+	VPC
+	retryer *retryer
+}
+
+// instrumentedECS is an ECS client which retries throttled requests and records the duration of all requests.
+type instrumentedECS struct {
+	ECS
+	retryer *retryer
+}
+
+func (c *instrumentedVPC) DescribeVpcs(req *alicloudvpc.DescribeVpcsRequest) (res *alicloudvpc.DescribeVpcsResponse, err error) {
+	err = c.retryer.do(context.TODO(), serviceVPC, "DescribeVpcs", func() (interface{}, error) {
+		res, err = c.VPC.DescribeVpcs(req)
+		return res, err
+	})
+	return res, err
+}
+
+func (c *instrumentedVPC) DescribeNatGateways(req *alicloudvpc.DescribeNatGatewaysRequest) (res *alicloudvpc.DescribeNatGatewaysResponse, err error) {
+	err = c.retryer.do(context.TODO(), serviceVPC, "DescribeNatGateways", func() (interface{}, error) {
+		res, err = c.VPC.DescribeNatGateways(req)
+		return res, err
+	})
+	return res, err
+}
+
+func (c *instrumentedVPC) DescribeEipAddresses(req *alicloudvpc.DescribeEipAddressesRequest) (res *alicloudvpc.DescribeEipAddressesResponse, err error) {
+	err = c.retryer.do(context.TODO(), serviceVPC, "DescribeEipAddresses", func() (interface{}, error) {
+		res, err = c.VPC.DescribeEipAddresses(req)
+		return res, err
+	})
+	return res, err
+}
+
+func (c *instrumentedVPC) DescribeVSwitches(req *alicloudvpc.DescribeVSwitchesRequest) (res *alicloudvpc.DescribeVSwitchesResponse, err error) {
+	err = c.retryer.do(context.TODO(), serviceVPC, "DescribeVSwitches", func() (interface{}, error) {
+		res, err = c.VPC.DescribeVSwitches(req)
+		return res, err
+	})
+	return res, err
+}
+
+func (c *instrumentedVPC) DescribeSnatTableEntries(req *alicloudvpc.DescribeSnatTableEntriesRequest) (res *alicloudvpc.DescribeSnatTableEntriesResponse, err error) {
+	err = c.retryer.do(context.TODO(), serviceVPC, "DescribeSnatTableEntries", func() (interface{}, error) {
+		res, err = c.VPC.DescribeSnatTableEntries(req)
+		return res, err
+	})
+	return res, err
+}
+
+func (c *instrumentedVPC) CreateVpc(req *alicloudvpc.CreateVpcRequest) (res *alicloudvpc.CreateVpcResponse, err error) {
+	err = c.retryer.do(context.TODO(), serviceVPC, "CreateVpc", func() (interface{}, error) {
+		res, err = c.VPC.CreateVpc(req)
+		return res, err
+	})
+	return res, err
+}
+
+func (c *instrumentedVPC) DeleteVpc(req *alicloudvpc.DeleteVpcRequest) (res *alicloudvpc.DeleteVpcResponse, err error) {
+	err = c.retryer.do(context.TODO(), serviceVPC, "DeleteVpc", func() (interface{}, error) {
+		res, err = c.VPC.DeleteVpc(req)
+		return res, err
+	})
+	return res, err
+}
+
+func (c *instrumentedVPC) CreateVSwitch(req *alicloudvpc.CreateVSwitchRequest) (res *alicloudvpc.CreateVSwitchResponse, err error) {
+	err = c.retryer.do(context.TODO(), serviceVPC, "CreateVSwitch", func() (interface{}, error) {
+		res, err = c.VPC.CreateVSwitch(req)
+		return res, err
+	})
+	return res, err
+}
+
+func (c *instrumentedVPC) DeleteVSwitch(req *alicloudvpc.DeleteVSwitchRequest) (res *alicloudvpc.DeleteVSwitchResponse, err error) {
+	err = c.retryer.do(context.TODO(), serviceVPC, "DeleteVSwitch", func() (interface{}, error) {
+		res, err = c.VPC.DeleteVSwitch(req)
+		return res, err
+	})
+	return res, err
+}
+
+func (c *instrumentedVPC) CreateNatGateway(req *alicloudvpc.CreateNatGatewayRequest) (res *alicloudvpc.CreateNatGatewayResponse, err error) {
+	err = c.retryer.do(context.TODO(), serviceVPC, "CreateNatGateway", func() (interface{}, error) {
+		res, err = c.VPC.CreateNatGateway(req)
+		return res, err
+	})
+	return res, err
+}
+
+func (c *instrumentedVPC) DeleteNatGateway(req *alicloudvpc.DeleteNatGatewayRequest) (res *alicloudvpc.DeleteNatGatewayResponse, err error) {
+	err = c.retryer.do(context.TODO(), serviceVPC, "DeleteNatGateway", func() (interface{}, error) {
+		res, err = c.VPC.DeleteNatGateway(req)
+		return res, err
+	})
+	return res, err
+}
+
+func (c *instrumentedVPC) AllocateEipAddress(req *alicloudvpc.AllocateEipAddressRequest) (res *alicloudvpc.AllocateEipAddressResponse, err error) {
+	err = c.retryer.do(context.TODO(), serviceVPC, "AllocateEipAddress", func() (interface{}, error) {
+		res, err = c.VPC.AllocateEipAddress(req)
+		return res, err
+	})
+	return res, err
+}
+
+func (c *instrumentedVPC) AssociateEipAddress(req *alicloudvpc.AssociateEipAddressRequest) (res *alicloudvpc.AssociateEipAddressResponse, err error) {
+	err = c.retryer.do(context.TODO(), serviceVPC, "AssociateEipAddress", func() (interface{}, error) {
+		res, err = c.VPC.AssociateEipAddress(req)
+		return res, err
+	})
+	return res, err
+}
+
+func (c *instrumentedVPC) UnassociateEipAddress(req *alicloudvpc.UnassociateEipAddressRequest) (res *alicloudvpc.UnassociateEipAddressResponse, err error) {
+	err = c.retryer.do(context.TODO(), serviceVPC, "UnassociateEipAddress", func() (interface{}, error) {
+		res, err = c.VPC.UnassociateEipAddress(req)
+		return res, err
+	})
+	return res, err
+}
+
+func (c *instrumentedVPC) ReleaseEipAddress(req *alicloudvpc.ReleaseEipAddressRequest) (res *alicloudvpc.ReleaseEipAddressResponse, err error) {
+	err = c.retryer.do(context.TODO(), serviceVPC, "ReleaseEipAddress", func() (interface{}, error) {
+		res, err = c.VPC.ReleaseEipAddress(req)
+		return res, err
+	})
+	return res, err
+}
+
+func (c *instrumentedVPC) CreateSnatEntry(req *alicloudvpc.CreateSnatEntryRequest) (res *alicloudvpc.CreateSnatEntryResponse, err error) {
+	err = c.retryer.do(context.TODO(), serviceVPC, "CreateSnatEntry", func() (interface{}, error) {
+		res, err = c.VPC.CreateSnatEntry(req)
+		return res, err
+	})
+	return res, err
+}
+
+func (c *instrumentedVPC) DeleteSnatEntry(req *alicloudvpc.DeleteSnatEntryRequest) (res *alicloudvpc.DeleteSnatEntryResponse, err error) {
+	err = c.retryer.do(context.TODO(), serviceVPC, "DeleteSnatEntry", func() (interface{}, error) {
+		res, err = c.VPC.DeleteSnatEntry(req)
+		return res, err
+	})
+	return res, err
+}
+
+func (c *instrumentedVPC) DescribeForwardTableEntries(req *alicloudvpc.DescribeForwardTableEntriesRequest) (res *alicloudvpc.DescribeForwardTableEntriesResponse, err error) {
+	err = c.retryer.do(context.TODO(), serviceVPC, "DescribeForwardTableEntries", func() (interface{}, error) {
+		res, err = c.VPC.DescribeForwardTableEntries(req)
+		return res, err
+	})
+	return res, err
+}
+
+func (c *instrumentedVPC) CreateForwardEntry(req *alicloudvpc.CreateForwardEntryRequest) (res *alicloudvpc.CreateForwardEntryResponse, err error) {
+	err = c.retryer.do(context.TODO(), serviceVPC, "CreateForwardEntry", func() (interface{}, error) {
+		res, err = c.VPC.CreateForwardEntry(req)
+		return res, err
+	})
+	return res, err
+}
+
+func (c *instrumentedVPC) DeleteForwardEntry(req *alicloudvpc.DeleteForwardEntryRequest) (res *alicloudvpc.DeleteForwardEntryResponse, err error) {
+	err = c.retryer.do(context.TODO(), serviceVPC, "DeleteForwardEntry", func() (interface{}, error) {
+		res, err = c.VPC.DeleteForwardEntry(req)
+		return res, err
+	})
+	return res, err
+}
+
+func (c *instrumentedVPC) TagResources(req *alicloudvpc.TagResourcesRequest) (res *alicloudvpc.TagResourcesResponse, err error) {
+	err = c.retryer.do(context.TODO(), serviceVPC, "TagResources", func() (interface{}, error) {
+		res, err = c.VPC.TagResources(req)
+		return res, err
+	})
+	return res, err
+}
+
+func (c *instrumentedVPC) ListTagResources(req *alicloudvpc.ListTagResourcesRequest) (res *alicloudvpc.ListTagResourcesResponse, err error) {
+	err = c.retryer.do(context.TODO(), serviceVPC, "ListTagResources", func() (interface{}, error) {
+		res, err = c.VPC.ListTagResources(req)
+		return res, err
+	})
+	return res, err
+}
+
+func (c *instrumentedVPC) DescribeRouteEntryList(req *alicloudvpc.DescribeRouteEntryListRequest) (res *alicloudvpc.DescribeRouteEntryListResponse, err error) {
+	err = c.retryer.do(context.TODO(), serviceVPC, "DescribeRouteEntryList", func() (interface{}, error) {
+		res, err = c.VPC.DescribeRouteEntryList(req)
+		return res, err
+	})
+	return res, err
+}
+
+func (c *instrumentedVPC) CreateRouteEntry(req *alicloudvpc.CreateRouteEntryRequest) (res *alicloudvpc.CreateRouteEntryResponse, err error) {
+	err = c.retryer.do(context.TODO(), serviceVPC, "CreateRouteEntry", func() (interface{}, error) {
+		res, err = c.VPC.CreateRouteEntry(req)
+		return res, err
+	})
+	return res, err
+}
+
+func (c *instrumentedVPC) DeleteRouteEntry(req *alicloudvpc.DeleteRouteEntryRequest) (res *alicloudvpc.DeleteRouteEntryResponse, err error) {
+	err = c.retryer.do(context.TODO(), serviceVPC, "DeleteRouteEntry", func() (interface{}, error) {
+		res, err = c.VPC.DeleteRouteEntry(req)
+		return res, err
+	})
+	return res, err
+}
+
+func (c *instrumentedVPC) DescribeRouteTableList(req *alicloudvpc.DescribeRouteTableListRequest) (res *alicloudvpc.DescribeRouteTableListResponse, err error) {
+	err = c.retryer.do(context.TODO(), serviceVPC, "DescribeRouteTableList", func() (interface{}, error) {
+		res, err = c.VPC.DescribeRouteTableList(req)
+		return res, err
+	})
+	return res, err
+}
+
+func (c *instrumentedVPC) AssociateRouteTable(req *alicloudvpc.AssociateRouteTableRequest) (res *alicloudvpc.AssociateRouteTableResponse, err error) {
+	err = c.retryer.do(context.TODO(), serviceVPC, "AssociateRouteTable", func() (interface{}, error) {
+		res, err = c.VPC.AssociateRouteTable(req)
+		return res, err
+	})
+	return res, err
+}
+
+func (c *instrumentedVPC) UnassociateRouteTable(req *alicloudvpc.UnassociateRouteTableRequest) (res *alicloudvpc.UnassociateRouteTableResponse, err error) {
+	err = c.retryer.do(context.TODO(), serviceVPC, "UnassociateRouteTable", func() (interface{}, error) {
+		res, err = c.VPC.UnassociateRouteTable(req)
+		return res, err
+	})
+	return res, err
+}
+
+func (c *instrumentedVPC) CreateRouteTable(req *alicloudvpc.CreateRouteTableRequest) (res *alicloudvpc.CreateRouteTableResponse, err error) {
+	err = c.retryer.do(context.TODO(), serviceVPC, "CreateRouteTable", func() (interface{}, error) {
+		res, err = c.VPC.CreateRouteTable(req)
+		return res, err
+	})
+	return res, err
+}
+
+func (c *instrumentedVPC) DeleteRouteTable(req *alicloudvpc.DeleteRouteTableRequest) (res *alicloudvpc.DeleteRouteTableResponse, err error) {
+	err = c.retryer.do(context.TODO(), serviceVPC, "DeleteRouteTable", func() (interface{}, error) {
+		res, err = c.VPC.DeleteRouteTable(req)
+		return res, err
+	})
+	return res, err
+}
+
+func (c *instrumentedVPC) DescribeFlowLogs(req *alicloudvpc.DescribeFlowLogsRequest) (res *alicloudvpc.DescribeFlowLogsResponse, err error) {
+	err = c.retryer.do(context.TODO(), serviceVPC, "DescribeFlowLogs", func() (interface{}, error) {
+		res, err = c.VPC.DescribeFlowLogs(req)
+		return res, err
+	})
+	return res, err
+}
+
+func (c *instrumentedVPC) CreateFlowLog(req *alicloudvpc.CreateFlowLogRequest) (res *alicloudvpc.CreateFlowLogResponse, err error) {
+	err = c.retryer.do(context.TODO(), serviceVPC, "CreateFlowLog", func() (interface{}, error) {
+		res, err = c.VPC.CreateFlowLog(req)
+		return res, err
+	})
+	return res, err
+}
+
+func (c *instrumentedVPC) DeleteFlowLog(req *alicloudvpc.DeleteFlowLogRequest) (res *alicloudvpc.DeleteFlowLogResponse, err error) {
+	err = c.retryer.do(context.TODO(), serviceVPC, "DeleteFlowLog", func() (interface{}, error) {
+		res, err = c.VPC.DeleteFlowLog(req)
+		return res, err
+	})
+	return res, err
+}
+
+func (c *instrumentedVPC) CreateDhcpOptionsSet(req *CreateDhcpOptionsSetRequest) (res *CreateDhcpOptionsSetResponse, err error) {
+	err = c.retryer.do(context.TODO(), serviceVPC, "CreateDhcpOptionsSet", func() (interface{}, error) {
+		res, err = c.VPC.CreateDhcpOptionsSet(req)
+		return res, err
+	})
+	return res, err
+}
+
+func (c *instrumentedVPC) GetDhcpOptionsSet(req *GetDhcpOptionsSetRequest) (res *GetDhcpOptionsSetResponse, err error) {
+	err = c.retryer.do(context.TODO(), serviceVPC, "GetDhcpOptionsSet", func() (interface{}, error) {
+		res, err = c.VPC.GetDhcpOptionsSet(req)
+		return res, err
+	})
+	return res, err
+}
+
+func (c *instrumentedVPC) UpdateDhcpOptionsSetAttribute(req *UpdateDhcpOptionsSetAttributeRequest) (res *DhcpOptionsSetResponse, err error) {
+	err = c.retryer.do(context.TODO(), serviceVPC, "UpdateDhcpOptionsSetAttribute", func() (interface{}, error) {
+		res, err = c.VPC.UpdateDhcpOptionsSetAttribute(req)
+		return res, err
+	})
+	return res, err
+}
+
+func (c *instrumentedVPC) AttachDhcpOptionsSetToVpc(req *AttachDhcpOptionsSetToVpcRequest) (res *DhcpOptionsSetResponse, err error) {
+	err = c.retryer.do(context.TODO(), serviceVPC, "AttachDhcpOptionsSetToVpc", func() (interface{}, error) {
+		res, err = c.VPC.AttachDhcpOptionsSetToVpc(req)
+		return res, err
+	})
+	return res, err
+}
+
+func (c *instrumentedVPC) DetachDhcpOptionsSetFromVpc(req *DetachDhcpOptionsSetFromVpcRequest) (res *DhcpOptionsSetResponse, err error) {
+	err = c.retryer.do(context.TODO(), serviceVPC, "DetachDhcpOptionsSetFromVpc", func() (interface{}, error) {
+		res, err = c.VPC.DetachDhcpOptionsSetFromVpc(req)
+		return res, err
+	})
+	return res, err
+}
+
+func (c *instrumentedVPC) DeleteDhcpOptionsSet(req *DeleteDhcpOptionsSetRequest) (res *DhcpOptionsSetResponse, err error) {
+	err = c.retryer.do(context.TODO(), serviceVPC, "DeleteDhcpOptionsSet", func() (interface{}, error) {
+		res, err = c.VPC.DeleteDhcpOptionsSet(req)
+		return res, err
+	})
+	return res, err
+}
+
+func (c *instrumentedECS) CheckIfImageExists(ctx context.Context, imageID string) (res bool, err error) {
+	err = c.retryer.do(ctx, serviceECS, "CheckIfImageExists", func() (interface{}, error) {
+		res, err = c.ECS.CheckIfImageExists(ctx, imageID)
+		return nil, err
+	})
+	return res, err
+}
+
+func (c *instrumentedECS) ShareImageToAccount(ctx context.Context, regionID, imageID, accountID string) error {
+	return c.retryer.do(ctx, serviceECS, "ShareImageToAccount", func() (interface{}, error) {
+		return nil, c.ECS.ShareImageToAccount(ctx, regionID, imageID, accountID)
+	})
+}
+
+func (c *instrumentedECS) GetImageByName(ctx context.Context, name string) (res *ecs.Image, err error) {
+	err = c.retryer.do(ctx, serviceECS, "GetImageByName", func() (interface{}, error) {
+		res, err = c.ECS.GetImageByName(ctx, name)
+		return nil, err
+	})
+	return res, err
+}
+
+func (c *instrumentedECS) GetAvailableImageByName(ctx context.Context, name string) (res *ecs.Image, err error) {
+	err = c.retryer.do(ctx, serviceECS, "GetAvailableImageByName", func() (interface{}, error) {
+		res, err = c.ECS.GetAvailableImageByName(ctx, name)
+		return nil, err
+	})
+	return res, err
+}
+
+func (c *instrumentedECS) CopyEncryptedImage(ctx context.Context, regionID, imageID, name string, kmsKeyID *string, tags map[string]string) (res string, err error) {
+	err = c.retryer.do(ctx, serviceECS, "CopyEncryptedImage", func() (interface{}, error) {
+		res, err = c.ECS.CopyEncryptedImage(ctx, regionID, imageID, name, kmsKeyID, tags)
+		return nil, err
+	})
+	return res, err
+}
+
+func (c *instrumentedECS) GetImagesByTags(ctx context.Context, tags map[string]string) (res []ecs.Image, err error) {
+	err = c.retryer.do(ctx, serviceECS, "GetImagesByTags", func() (interface{}, error) {
+		res, err = c.ECS.GetImagesByTags(ctx, tags)
+		return nil, err
+	})
+	return res, err
+}
+
+func (c *instrumentedECS) DeleteImage(ctx context.Context, regionID, imageID string) error {
+	return c.retryer.do(ctx, serviceECS, "DeleteImage", func() (interface{}, error) {
+		return nil, c.ECS.DeleteImage(ctx, regionID, imageID)
+	})
+}
+
+func (c *instrumentedECS) GetPrePaidInstancesByTags(ctx context.Context, regionID string, tags map[string]string) (res []ecs.Instance, err error) {
+	err = c.retryer.do(ctx, serviceECS, "GetPrePaidInstancesByTags", func() (interface{}, error) {
+		res, err = c.ECS.GetPrePaidInstancesByTags(ctx, regionID, tags)
+		return nil, err
+	})
+	return res, err
+}
+
+func (c *instrumentedECS) GetDeploymentSet(ctx context.Context, regionID, deploymentSetID string) (res *ecs.DeploymentSet, err error) {
+	err = c.retryer.do(ctx, serviceECS, "GetDeploymentSet", func() (interface{}, error) {
+		res, err = c.ECS.GetDeploymentSet(ctx, regionID, deploymentSetID)
+		return nil, err
+	})
+	return res, err
+}
+
+func (c *instrumentedECS) GetAvailableInstanceTypes(ctx context.Context, regionID, zoneID, instanceChargeType string) (res []string, err error) {
+	err = c.retryer.do(ctx, serviceECS, "GetAvailableInstanceTypes", func() (interface{}, error) {
+		res, err = c.ECS.GetAvailableInstanceTypes(ctx, regionID, zoneID, instanceChargeType)
+		return nil, err
+	})
+	return res, err
+}
+
+func (c *instrumentedECS) CheckIfSecurityGroupExists(ctx context.Context, securityGroupID string) (res bool, err error) {
+	err = c.retryer.do(ctx, serviceECS, "CheckIfSecurityGroupExists", func() (interface{}, error) {
+		res, err = c.ECS.CheckIfSecurityGroupExists(ctx, securityGroupID)
+		return nil, err
+	})
+	return res, err
+}
+
+func (c *instrumentedECS) GetSecurityGroup(ctx context.Context, securityGroupID string) (res *ecs.SecurityGroup, err error) {
+	err = c.retryer.do(ctx, serviceECS, "GetSecurityGroup", func() (interface{}, error) {
+		res, err = c.ECS.GetSecurityGroup(ctx, securityGroupID)
+		return nil, err
+	})
+	return res, err
+}
+
+func (c *instrumentedECS) GetSecurityGroupRules(ctx context.Context, securityGroupID string) (res []ecs.Permission, err error) {
+	err = c.retryer.do(ctx, serviceECS, "GetSecurityGroupRules", func() (interface{}, error) {
+		res, err = c.ECS.GetSecurityGroupRules(ctx, securityGroupID)
+		return nil, err
+	})
+	return res, err
+}
+
+func (c *instrumentedECS) GetSecurityGroupsByTags(ctx context.Context, vpcID string, tags map[string]string) (res []ecs.SecurityGroup, err error) {
+	err = c.retryer.do(ctx, serviceECS, "GetSecurityGroupsByTags", func() (interface{}, error) {
+		res, err = c.ECS.GetSecurityGroupsByTags(ctx, vpcID, tags)
+		return nil, err
+	})
+	return res, err
+}
+
+func (c *instrumentedECS) CreateSecurityGroup(ctx context.Context, vpcID, name string) (res string, err error) {
+	err = c.retryer.do(ctx, serviceECS, "CreateSecurityGroup", func() (interface{}, error) {
+		res, err = c.ECS.CreateSecurityGroup(ctx, vpcID, name)
+		return nil, err
+	})
+	return res, err
+}
+
+func (c *instrumentedECS) AuthorizeSecurityGroupIngress(ctx context.Context, securityGroupID, ipProtocol, portRange, sourceCIDR string) error {
+	return c.retryer.do(ctx, serviceECS, "AuthorizeSecurityGroupIngress", func() (interface{}, error) {
+		return nil, c.ECS.AuthorizeSecurityGroupIngress(ctx, securityGroupID, ipProtocol, portRange, sourceCIDR)
+	})
+}
+
+func (c *instrumentedECS) RevokeSecurityGroupIngress(ctx context.Context, securityGroupID, ipProtocol, portRange, sourceCIDR string) error {
+	return c.retryer.do(ctx, serviceECS, "RevokeSecurityGroupIngress", func() (interface{}, error) {
+		return nil, c.ECS.RevokeSecurityGroupIngress(ctx, securityGroupID, ipProtocol, portRange, sourceCIDR)
+	})
+}
+
+func (c *instrumentedECS) AuthorizeSecurityGroupEgress(ctx context.Context, securityGroupID, ipProtocol, portRange, destCIDR string) error {
+	return c.retryer.do(ctx, serviceECS, "AuthorizeSecurityGroupEgress", func() (interface{}, error) {
+		return nil, c.ECS.AuthorizeSecurityGroupEgress(ctx, securityGroupID, ipProtocol, portRange, destCIDR)
+	})
+}
+
+func (c *instrumentedECS) RevokeSecurityGroupEgress(ctx context.Context, securityGroupID, ipProtocol, portRange, destCIDR string) error {
+	return c.retryer.do(ctx, serviceECS, "RevokeSecurityGroupEgress", func() (interface{}, error) {
+		return nil, c.ECS.RevokeSecurityGroupEgress(ctx, securityGroupID, ipProtocol, portRange, destCIDR)
+	})
+}
+
+func (c *instrumentedECS) RevokeSecurityGroupPermission(ctx context.Context, securityGroupID string, permission ecs.Permission) error {
+	return c.retryer.do(ctx, serviceECS, "RevokeSecurityGroupPermission", func() (interface{}, error) {
+		return nil, c.ECS.RevokeSecurityGroupPermission(ctx, securityGroupID, permission)
+	})
+}
+
+func (c *instrumentedECS) DeleteSecurityGroup(ctx context.Context, securityGroupID string) error {
+	return c.retryer.do(ctx, serviceECS, "DeleteSecurityGroup", func() (interface{}, error) {
+		return nil, c.ECS.DeleteSecurityGroup(ctx, securityGroupID)
+	})
+}
+
+func (c *instrumentedECS) CheckIfKeyPairExists(ctx context.Context, name string) (res bool, err error) {
+	err = c.retryer.do(ctx, serviceECS, "CheckIfKeyPairExists", func() (interface{}, error) {
+		res, err = c.ECS.CheckIfKeyPairExists(ctx, name)
+		return nil, err
+	})
+	return res, err
+}
+
+func (c *instrumentedECS) ImportKeyPair(ctx context.Context, name, publicKey string) error {
+	return c.retryer.do(ctx, serviceECS, "ImportKeyPair", func() (interface{}, error) {
+		return nil, c.ECS.ImportKeyPair(ctx, name, publicKey)
+	})
+}
+
+func (c *instrumentedECS) DeleteKeyPair(ctx context.Context, name string) error {
+	return c.retryer.do(ctx, serviceECS, "DeleteKeyPair", func() (interface{}, error) {
+		return nil, c.ECS.DeleteKeyPair(ctx, name)
+	})
+}
+
+func (c *instrumentedECS) TagResources(ctx context.Context, resourceType string, resourceIDs []string, tags map[string]string) error {
+	return c.retryer.do(ctx, serviceECS, "TagResources", func() (interface{}, error) {
+		return nil, c.ECS.TagResources(ctx, resourceType, resourceIDs, tags)
+	})
+}
+
+func (c *instrumentedECS) UntagResources(ctx context.Context, resourceType string, resourceIDs []string, tagKeys []string) error {
+	return c.retryer.do(ctx, serviceECS, "UntagResources", func() (interface{}, error) {
+		return nil, c.ECS.UntagResources(ctx, resourceType, resourceIDs, tagKeys)
+	})
+}
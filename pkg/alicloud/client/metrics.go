@@ -0,0 +1,61 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	serviceVPC = "vpc"
+	serviceECS = "ecs"
+
+	// RequestResultSuccess is the result label of requests which succeeded.
+	RequestResultSuccess = "success"
+	// RequestResultError is the result label of requests which failed.
+	RequestResultError = "error"
+)
+
+// RequestDuration is the histogram of the durations of the requests to the Alicloud API, labeled by service,
+// operation, and result.
+var RequestDuration = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Namespace: "alicloud",
+		Subsystem: "api",
+		Name:      "request_duration_seconds",
+		Help:      "Duration of the requests to the Alicloud API.",
+		Buckets:   prometheus.DefBuckets,
+	},
+	[]string{"service", "operation", "result"},
+)
+
+func init() {
+	metrics.Registry.MustRegister(RequestDuration)
+}
+
+// RequestResult returns the result label for the given error.
+func RequestResult(err error) string {
+	if err != nil {
+		return RequestResultError
+	}
+	return RequestResultSuccess
+}
+
+func observeRequest(service, operation string, start time.Time, err error) {
+	RequestDuration.WithLabelValues(service, operation, RequestResult(err)).Observe(time.Since(start).Seconds())
+}
@@ -0,0 +1,81 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"fmt"
+
+	alicloudvpc "github.com/aliyun/alibaba-cloud-sdk-go/services/vpc"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// fakeVPC answers DescribeVpcs with the configured error.
+type fakeVPC struct {
+	VPC
+	err error
+}
+
+func (f *fakeVPC) DescribeVpcs(_ *alicloudvpc.DescribeVpcsRequest) (*alicloudvpc.DescribeVpcsResponse, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &alicloudvpc.DescribeVpcsResponse{}, nil
+}
+
+// requestCount returns the number of requests recorded in the controller-runtime metrics registry for the given
+// service, operation, and result.
+func requestCount(service, operation, result string) uint64 {
+	families, err := metrics.Registry.Gather()
+	Expect(err).NotTo(HaveOccurred())
+
+	for _, family := range families {
+		if family.GetName() != "alicloud_api_request_duration_seconds" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			labels := map[string]string{}
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			if labels["service"] == service && labels["operation"] == operation && labels["result"] == result {
+				return metric.GetHistogram().GetSampleCount()
+			}
+		}
+	}
+	return 0
+}
+
+var _ = Describe("Metrics", func() {
+	It("should record the duration and result of VPC requests", func() {
+		var (
+			fake      = &fakeVPC{}
+			vpcClient = &instrumentedVPC{fake, newDefaultRetryer()}
+
+			successes = requestCount(serviceVPC, "DescribeVpcs", RequestResultSuccess)
+			errors    = requestCount(serviceVPC, "DescribeVpcs", RequestResultError)
+		)
+
+		_, err := vpcClient.DescribeVpcs(alicloudvpc.CreateDescribeVpcsRequest())
+		Expect(err).NotTo(HaveOccurred())
+		fake.err = fmt.Errorf("throttled")
+		_, err = vpcClient.DescribeVpcs(alicloudvpc.CreateDescribeVpcsRequest())
+		Expect(err).To(MatchError("throttled"))
+
+		Expect(requestCount(serviceVPC, "DescribeVpcs", RequestResultSuccess)).To(Equal(successes + 1))
+		Expect(requestCount(serviceVPC, "DescribeVpcs", RequestResultError)).To(Equal(errors + 1))
+	})
+})
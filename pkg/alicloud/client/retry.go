@@ -0,0 +1,196 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aliyun/alibaba-cloud-sdk-go/sdk/errors"
+)
+
+const (
+	defaultRetryMaxAttempts = 5
+	defaultRetryBaseDelay   = 500 * time.Millisecond
+	defaultRetryMaxDelay    = 10 * time.Second
+
+	headerRetryAfter = "Retry-After"
+)
+
+// readOnlyOperationPrefixes are the prefixes of the operations which can safely be retried on server errors since
+// they don't modify any resources.
+var readOnlyOperationPrefixes = []string{"Describe", "Check", "Get", "List"}
+
+// retryer retries requests to the Alicloud API which failed due to throttling or server errors with an exponential
+// backoff. Each attempt is recorded in the RequestDuration metric.
+type retryer struct {
+	maxAttempts int
+	baseDelay   time.Duration
+	maxDelay    time.Duration
+
+	sleep  func(ctx context.Context, d time.Duration) error
+	jitter func(d time.Duration) time.Duration
+}
+
+func newDefaultRetryer() *retryer {
+	return &retryer{
+		maxAttempts: defaultRetryMaxAttempts,
+		baseDelay:   defaultRetryBaseDelay,
+		maxDelay:    defaultRetryMaxDelay,
+		sleep:       sleep,
+		jitter:      equalJitter,
+	}
+}
+
+// do executes the given request until it succeeds, fails with a non-retryable error, or the maximum number of
+// attempts is reached. The request returns the typed response of the SDK (if any) so that a Retry-After hint of the
+// Alicloud API can be honored.
+func (r *retryer) do(ctx context.Context, service, operation string, request func() (interface{}, error)) error {
+	for attempt := 1; ; attempt++ {
+		start := time.Now()
+		res, err := request()
+		observeRequest(service, operation, start, err)
+
+		if err == nil || attempt >= r.maxAttempts || !isRetryable(operation, err) {
+			return err
+		}
+
+		// The VPC client does not pass the context of the reconciliation, hence the delay of the Alicloud API is capped
+		// as well, so that a request cannot block for longer than the maximum attempts allow.
+		delay, ok := retryAfter(res)
+		if !ok {
+			delay = r.jitter(r.backoff(attempt))
+		} else if delay > r.maxDelay {
+			delay = r.maxDelay
+		}
+		if err := r.sleep(ctx, delay); err != nil {
+			return err
+		}
+	}
+}
+
+// backoff returns the exponential backoff for the given attempt, capped at the maximum delay.
+func (r *retryer) backoff(attempt int) time.Duration {
+	delay := r.baseDelay
+	for i := 1; i < attempt && delay < r.maxDelay; i++ {
+		delay *= 2
+	}
+	if delay > r.maxDelay {
+		return r.maxDelay
+	}
+	return delay
+}
+
+// isRetryable checks whether the given error of the given operation is worth retrying. Throttled requests are always
+// retried, server errors are only retried for read-only operations as the request might have been processed already.
+func isRetryable(operation string, err error) bool {
+	sdkErr, ok := err.(errors.Error)
+	if !ok {
+		return false
+	}
+
+	if isThrottlingError(sdkErr) {
+		return true
+	}
+	return sdkErr.HttpStatus() >= http.StatusInternalServerError && isReadOnlyOperation(operation)
+}
+
+func isThrottlingError(err errors.Error) bool {
+	code := err.ErrorCode()
+	return err.HttpStatus() == http.StatusTooManyRequests ||
+		strings.HasPrefix(code, "Throttling") ||
+		code == "RequestLimitExceeded"
+}
+
+func isReadOnlyOperation(operation string) bool {
+	for _, prefix := range readOnlyOperationPrefixes {
+		if strings.HasPrefix(operation, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+type httpHeadersGetter interface {
+	GetHttpHeaders() map[string][]string
+}
+
+// retryAfter returns the delay the Alicloud API asked for in the Retry-After header of the given response, if any.
+func retryAfter(res interface{}) (time.Duration, bool) {
+	getter, ok := res.(httpHeadersGetter)
+	if !ok || isNilResponse(res) {
+		return 0, false
+	}
+
+	for key, values := range getter.GetHttpHeaders() {
+		if !strings.EqualFold(key, headerRetryAfter) || len(values) == 0 {
+			continue
+		}
+		if seconds, err := strconv.Atoi(strings.TrimSpace(values[0])); err == nil && seconds >= 0 {
+			return time.Duration(seconds) * time.Second, true
+		}
+		if date, err := http.ParseTime(values[0]); err == nil {
+			if delay := time.Until(date); delay > 0 {
+				return delay, true
+			}
+			return 0, true
+		}
+	}
+	return 0, false
+}
+
+// isNilResponse checks whether the given response or its embedded base response is nil. The SDK responses embed
+// *responses.BaseResponse, hence calling GetHttpHeaders on them would panic otherwise.
+func isNilResponse(res interface{}) bool {
+	v := reflect.ValueOf(res)
+	if v.Kind() != reflect.Ptr {
+		return false
+	}
+	if v.IsNil() {
+		return true
+	}
+	if elem := v.Elem(); elem.Kind() == reflect.Struct {
+		if base := elem.FieldByName("BaseResponse"); base.IsValid() && base.Kind() == reflect.Ptr && base.IsNil() {
+			return true
+		}
+	}
+	return false
+}
+
+// equalJitter randomizes the given delay to be within [d/2, d) so that concurrent clients don't retry in lockstep.
+func equalJitter(d time.Duration) time.Duration {
+	half := d / 2
+	if half <= 0 {
+		return d
+	}
+	return half + time.Duration(rand.Int63n(int64(half)))
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,205 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/aliyun/alibaba-cloud-sdk-go/sdk/errors"
+	"github.com/aliyun/alibaba-cloud-sdk-go/sdk/responses"
+	alicloudvpc "github.com/aliyun/alibaba-cloud-sdk-go/services/vpc"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// scriptedVPC answers requests with the configured errors in order and succeeds afterwards.
+type scriptedVPC struct {
+	VPC
+	errs       []error
+	retryAfter string
+	calls      int
+}
+
+func (s *scriptedVPC) next() error {
+	s.calls++
+	if len(s.errs) == 0 {
+		return nil
+	}
+	err := s.errs[0]
+	s.errs = s.errs[1:]
+	return err
+}
+
+func (s *scriptedVPC) DescribeVpcs(_ *alicloudvpc.DescribeVpcsRequest) (*alicloudvpc.DescribeVpcsResponse, error) {
+	response := alicloudvpc.CreateDescribeVpcsResponse()
+	if err := s.next(); err != nil {
+		return response, throttledResponse(response, s.retryAfter)
+	}
+	return response, nil
+}
+
+func (s *scriptedVPC) CreateNatGateway(_ *alicloudvpc.CreateNatGatewayRequest) (*alicloudvpc.CreateNatGatewayResponse, error) {
+	if err := s.next(); err != nil {
+		return nil, err
+	}
+	return alicloudvpc.CreateCreateNatGatewayResponse(), nil
+}
+
+// throttledResponse fills the given response like the SDK does for a throttled request and returns the error.
+func throttledResponse(response responses.AcsResponse, retryAfter string) error {
+	header := http.Header{}
+	if retryAfter != "" {
+		header.Set(headerRetryAfter, retryAfter)
+	}
+	return responses.Unmarshal(response, &http.Response{
+		StatusCode: http.StatusBadRequest,
+		Header:     header,
+		Body:       ioutil.NopCloser(bytes.NewBufferString(`{"Code":"Throttling.User","Message":"Request was denied due to user flow control."}`)),
+	}, "JSON")
+}
+
+var _ = Describe("Retry", func() {
+	var (
+		delays    []time.Duration
+		fake      *scriptedVPC
+		vpcClient VPC
+
+		throttled   = errors.NewServerError(http.StatusBadRequest, `{"Code":"Throttling"}`, "")
+		serverError = errors.NewServerError(http.StatusServiceUnavailable, `{"Code":"InternalError"}`, "")
+		forbidden   = errors.NewServerError(http.StatusForbidden, `{"Code":"Forbidden.RAM"}`, "")
+
+		serviceUnavailable = errors.NewServerError(http.StatusServiceUnavailable, `{"Code":"ServiceUnavailable"}`, "")
+	)
+
+	BeforeEach(func() {
+		delays = nil
+		fake = &scriptedVPC{}
+		vpcClient = &instrumentedVPC{fake, &retryer{
+			maxAttempts: 3,
+			baseDelay:   time.Second,
+			maxDelay:    10 * time.Second,
+			sleep: func(_ context.Context, d time.Duration) error {
+				delays = append(delays, d)
+				return nil
+			},
+			jitter: func(d time.Duration) time.Duration { return d },
+		}}
+	})
+
+	It("should retry throttled requests with exponential backoff", func() {
+		fake.errs = []error{throttled, throttled}
+
+		_, err := vpcClient.DescribeVpcs(alicloudvpc.CreateDescribeVpcsRequest())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fake.calls).To(Equal(3))
+		Expect(delays).To(Equal([]time.Duration{time.Second, 2 * time.Second}))
+	})
+
+	It("should give up after the maximum number of attempts", func() {
+		fake.errs = []error{throttled, throttled, throttled, throttled}
+
+		_, err := vpcClient.DescribeVpcs(alicloudvpc.CreateDescribeVpcsRequest())
+		Expect(err).To(HaveOccurred())
+		Expect(fake.calls).To(Equal(3))
+	})
+
+	It("should honor the Retry-After hint of the Alicloud API", func() {
+		fake.errs = []error{throttled}
+		fake.retryAfter = "7"
+
+		_, err := vpcClient.DescribeVpcs(alicloudvpc.CreateDescribeVpcsRequest())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fake.calls).To(Equal(2))
+		Expect(delays).To(Equal([]time.Duration{7 * time.Second}))
+	})
+
+	It("should cap the Retry-After hint of the Alicloud API at the maximum delay", func() {
+		fake.errs = []error{throttled}
+		fake.retryAfter = "3600"
+
+		_, err := vpcClient.DescribeVpcs(alicloudvpc.CreateDescribeVpcsRequest())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(delays).To(Equal([]time.Duration{10 * time.Second}))
+	})
+
+	It("should retry server errors of read-only requests", func() {
+		fake.errs = []error{serverError}
+
+		_, err := vpcClient.DescribeVpcs(alicloudvpc.CreateDescribeVpcsRequest())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fake.calls).To(Equal(2))
+	})
+
+	It("should not retry server errors of mutating requests", func() {
+		fake.errs = []error{serverError}
+
+		_, err := vpcClient.CreateNatGateway(alicloudvpc.CreateCreateNatGatewayRequest())
+		Expect(err).To(Equal(serverError))
+		Expect(fake.calls).To(Equal(1))
+	})
+
+	It("should not retry mutating requests if the service is unavailable", func() {
+		fake.errs = []error{serviceUnavailable}
+
+		_, err := vpcClient.CreateNatGateway(alicloudvpc.CreateCreateNatGatewayRequest())
+		Expect(err).To(Equal(serviceUnavailable))
+		Expect(fake.calls).To(Equal(1))
+		Expect(delays).To(BeEmpty())
+	})
+
+	It("should retry throttled mutating requests", func() {
+		fake.errs = []error{throttled}
+
+		_, err := vpcClient.CreateNatGateway(alicloudvpc.CreateCreateNatGatewayRequest())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fake.calls).To(Equal(2))
+	})
+
+	It("should propagate non-retryable errors immediately", func() {
+		for _, err := range []error{forbidden, fmt.Errorf("not found")} {
+			fake.calls = 0
+			fake.errs = []error{err}
+
+			_, actual := vpcClient.CreateNatGateway(alicloudvpc.CreateCreateNatGatewayRequest())
+			Expect(actual).To(Equal(err))
+			Expect(fake.calls).To(Equal(1))
+		}
+		Expect(delays).To(BeEmpty())
+	})
+
+	It("should record every attempt in the request duration metric", func() {
+		errorCount := requestCount(serviceVPC, "DescribeVpcs", RequestResultError)
+		fake.errs = []error{throttled, throttled}
+
+		_, err := vpcClient.DescribeVpcs(alicloudvpc.CreateDescribeVpcsRequest())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(requestCount(serviceVPC, "DescribeVpcs", RequestResultError)).To(Equal(errorCount + 2))
+	})
+
+	Describe("#backoff", func() {
+		It("should cap the delay at the maximum delay", func() {
+			r := &retryer{baseDelay: time.Second, maxDelay: 5 * time.Second}
+			Expect(r.backoff(1)).To(Equal(time.Second))
+			Expect(r.backoff(3)).To(Equal(4 * time.Second))
+			Expect(r.backoff(4)).To(Equal(5 * time.Second))
+			Expect(r.backoff(20)).To(Equal(5 * time.Second))
+		})
+	})
+})
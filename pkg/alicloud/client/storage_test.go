@@ -0,0 +1,280 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/aliyun/aliyun-oss-go-sdk/oss"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// fakeBucket returns the given objects in pages of at most 1000 objects, in the order of the calls of ListObjects.
+type fakeBucket struct {
+	objects     []oss.ObjectProperties
+	listCalls   int
+	deletedKeys []string
+	// restoreHeaders are the X-Oss-Restore headers of the objects by key.
+	restoreHeaders map[string]string
+	restoredKeys   []string
+	// copiedKeys are the source and destination keys of the copied objects.
+	copiedKeys map[string]string
+}
+
+func (b *fakeBucket) ListObjects(options ...oss.Option) (oss.ListObjectsResult, error) {
+	start := b.listCalls * 1000
+	end := start + 1000
+	if end > len(b.objects) {
+		end = len(b.objects)
+	}
+	b.listCalls++
+
+	return oss.ListObjectsResult{
+		Objects:     b.objects[start:end],
+		IsTruncated: end < len(b.objects),
+		NextMarker:  fmt.Sprintf("page-%d", b.listCalls),
+	}, nil
+}
+
+func (b *fakeBucket) DeleteObjects(objectKeys []string, options ...oss.Option) (oss.DeleteObjectsResult, error) {
+	Expect(len(objectKeys)).To(BeNumerically("<=", 1000))
+	b.deletedKeys = append(b.deletedKeys, objectKeys...)
+	return oss.DeleteObjectsResult{}, nil
+}
+func (b *fakeBucket) GetObjectDetailedMeta(objectKey string, options ...oss.Option) (http.Header, error) {
+	header := http.Header{}
+	if restore, ok := b.restoreHeaders[objectKey]; ok {
+		header.Set("X-Oss-Restore", restore)
+	}
+	return header, nil
+}
+
+func (b *fakeBucket) RestoreObject(objectKey string, options ...oss.Option) error {
+	b.restoredKeys = append(b.restoredKeys, objectKey)
+	return nil
+}
+
+func (b *fakeBucket) CopyObjectFrom(srcBucketName, srcObjectKey, destObjectKey string, options ...oss.Option) (oss.CopyObjectResult, error) {
+	if b.copiedKeys == nil {
+		b.copiedKeys = make(map[string]string)
+	}
+	b.copiedKeys[srcBucketName+"/"+srcObjectKey] = destObjectKey
+	return oss.CopyObjectResult{}, nil
+}
+
+var _ = Describe("Storage", func() {
+	Describe("#deleteObjectsWithPrefix", func() {
+		var (
+			ctx    = context.TODO()
+			now    = time.Date(2020, 5, 1, 0, 0, 0, 0, time.UTC)
+			bucket *fakeBucket
+		)
+
+		BeforeEach(func() {
+			bucket = &fakeBucket{}
+			for i := 0; i < 2500; i++ {
+				bucket.objects = append(bucket.objects, oss.ObjectProperties{
+					Key:          fmt.Sprintf("entry/%04d", i),
+					LastModified: now.Add(-time.Duration(2500-i) * time.Hour),
+				})
+			}
+		})
+
+		It("should delete all objects with the prefix in pages", func() {
+			remaining, err := deleteObjectsWithPrefix(ctx, bucket, "entry/", nil)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(remaining).To(BeZero())
+			Expect(bucket.listCalls).To(Equal(3))
+			Expect(bucket.deletedKeys).To(HaveLen(2500))
+		})
+
+		It("should only delete the objects which are older than the given time", func() {
+			olderThan := now.Add(-500 * time.Hour)
+
+			remaining, err := deleteObjectsWithPrefix(ctx, bucket, "entry/", &olderThan)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(remaining).To(Equal(500))
+			Expect(bucket.deletedKeys).To(HaveLen(2000))
+			Expect(bucket.deletedKeys[0]).To(Equal("entry/0000"))
+			Expect(bucket.deletedKeys[1999]).To(Equal("entry/1999"))
+		})
+
+		It("should never delete objects without the prefix", func() {
+			bucket.objects[1200].Key = "other/1200"
+
+			_, err := deleteObjectsWithPrefix(ctx, bucket, "entry/", nil)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(bucket.deletedKeys).To(HaveLen(2499))
+			Expect(bucket.deletedKeys).NotTo(ContainElement("other/1200"))
+		})
+	})
+
+	Describe("#restoreLatestArchivedSnapshots", func() {
+		var (
+			ctx    = context.TODO()
+			now    = time.Date(2020, 5, 1, 0, 0, 0, 0, time.UTC)
+			bucket *fakeBucket
+		)
+
+		object := func(key, storageClass string, age time.Duration) oss.ObjectProperties {
+			return oss.ObjectProperties{Key: key, StorageClass: storageClass, LastModified: now.Add(-age)}
+		}
+
+		BeforeEach(func() {
+			bucket = &fakeBucket{
+				objects: []oss.ObjectProperties{
+					object("entry/etcd-main/v1/Backup-1/Full-00000000-00000100-1", "Archive", 72*time.Hour),
+					object("entry/etcd-main/v1/Backup-1/Incr-00000101-00000200-2", "Archive", 71*time.Hour),
+					object("entry/etcd-main/v1/Backup-3/Full-00000000-00000300-3", "Archive", 48*time.Hour),
+					object("entry/etcd-main/v1/Backup-3/Incr-00000301-00000400-4", "Archive", 47*time.Hour),
+					object("entry/etcd-main/v1/Backup-3/Incr-00000401-00000500-5", "Standard", time.Hour),
+				},
+			}
+		})
+
+		It("should only restore the archived objects of the latest full snapshot and the later delta snapshots", func() {
+			restoring, err := restoreLatestArchivedSnapshots(ctx, bucket, "entry/")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(restoring).To(Equal(2))
+			Expect(bucket.restoredKeys).To(ConsistOf(
+				"entry/etcd-main/v1/Backup-3/Full-00000000-00000300-3",
+				"entry/etcd-main/v1/Backup-3/Incr-00000301-00000400-4",
+			))
+		})
+
+		It("should not restore objects again whose restore is in progress or done", func() {
+			bucket.restoreHeaders = map[string]string{
+				"entry/etcd-main/v1/Backup-3/Full-00000000-00000300-3": `ongoing-request="false", expiry-date="Sun, 03 May 2020 00:00:00 GMT"`,
+				"entry/etcd-main/v1/Backup-3/Incr-00000301-00000400-4": `ongoing-request="true"`,
+			}
+
+			restoring, err := restoreLatestArchivedSnapshots(ctx, bucket, "entry/")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(restoring).To(Equal(1))
+			Expect(bucket.restoredKeys).To(BeEmpty())
+		})
+
+		It("should not restore anything without full snapshots", func() {
+			bucket.objects = bucket.objects[4:]
+
+			restoring, err := restoreLatestArchivedSnapshots(ctx, bucket, "entry/")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(restoring).To(BeZero())
+			Expect(bucket.restoredKeys).To(BeEmpty())
+		})
+	})
+
+	Describe("#copyObjectsWithPrefix", func() {
+		var (
+			ctx       = context.TODO()
+			now       = time.Date(2020, 5, 1, 0, 0, 0, 0, time.UTC)
+			srcBucket *fakeBucket
+			dstBucket *fakeBucket
+		)
+
+		object := func(key string) oss.ObjectProperties {
+			return oss.ObjectProperties{Key: key, LastModified: now}
+		}
+
+		BeforeEach(func() {
+			srcBucket = &fakeBucket{
+				objects: []oss.ObjectProperties{
+					object("old-entry/etcd-main/v1/Backup-1/Full-00000000-00000100-1"),
+					object("old-entry/etcd-main/v1/Backup-1/Incr-00000101-00000200-2"),
+				},
+			}
+			dstBucket = &fakeBucket{}
+		})
+
+		It("should copy the objects to the other prefix", func() {
+			copied, err := copyObjectsWithPrefix(ctx, srcBucket, "old-bucket", "old-entry/", dstBucket, "new-entry/")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(copied).To(Equal(2))
+			Expect(dstBucket.copiedKeys).To(Equal(map[string]string{
+				"old-bucket/old-entry/etcd-main/v1/Backup-1/Full-00000000-00000100-1": "new-entry/etcd-main/v1/Backup-1/Full-00000000-00000100-1",
+				"old-bucket/old-entry/etcd-main/v1/Backup-1/Incr-00000101-00000200-2": "new-entry/etcd-main/v1/Backup-1/Incr-00000101-00000200-2",
+			}))
+		})
+
+		It("should only copy the objects which have not been copied yet", func() {
+			dstBucket.objects = []oss.ObjectProperties{object("new-entry/etcd-main/v1/Backup-1/Full-00000000-00000100-1")}
+
+			copied, err := copyObjectsWithPrefix(ctx, srcBucket, "old-bucket", "old-entry/", dstBucket, "new-entry/")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(copied).To(Equal(1))
+			Expect(dstBucket.copiedKeys).To(Equal(map[string]string{
+				"old-bucket/old-entry/etcd-main/v1/Backup-1/Incr-00000101-00000200-2": "new-entry/etcd-main/v1/Backup-1/Incr-00000101-00000200-2",
+			}))
+		})
+
+		It("should not copy anything once all objects have been copied", func() {
+			dstBucket.objects = []oss.ObjectProperties{
+				object("new-entry/etcd-main/v1/Backup-1/Full-00000000-00000100-1"),
+				object("new-entry/etcd-main/v1/Backup-1/Incr-00000101-00000200-2"),
+				object("new-entry/etcd-main/v1/Backup-2/Full-00000000-00000300-3"),
+			}
+
+			copied, err := copyObjectsWithPrefix(ctx, srcBucket, "old-bucket", "old-entry/", dstBucket, "new-entry/")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(copied).To(BeZero())
+			Expect(dstBucket.copiedKeys).To(BeEmpty())
+		})
+
+		It("should not copy anything if etcd has taken newer backups and garbage collected copied objects", func() {
+			dstBucket.objects = []oss.ObjectProperties{
+				object("new-entry/etcd-main/v1/Backup-1/Full-00000000-00000100-1"),
+				object("new-entry/etcd-main/v1/Backup-2/Full-00000000-00000300-3"),
+				object("new-entry/etcd-main/v1/Backup-2/Incr-00000301-00000400-4"),
+			}
+
+			copied, err := copyObjectsWithPrefix(ctx, srcBucket, "old-bucket", "old-entry/", dstBucket, "new-entry/")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(copied).To(BeZero())
+			Expect(dstBucket.copiedKeys).To(BeEmpty())
+		})
+
+		It("should fail if the destination contains other objects", func() {
+			dstBucket.objects = []oss.ObjectProperties{object("new-entry/etcd-main/v1/Backup-2/Full-00000000-00000010-3")}
+
+			_, err := copyObjectsWithPrefix(ctx, srcBucket, "old-bucket", "old-entry/", dstBucket, "new-entry/")
+
+			Expect(err).To(MatchError(ContainSubstring("new-entry/etcd-main/v1/Backup-2/Full-00000000-00000010-3")))
+			Expect(dstBucket.copiedKeys).To(BeEmpty())
+		})
+
+		It("should fail if the source does not contain a full snapshot", func() {
+			srcBucket.objects = srcBucket.objects[1:]
+
+			_, err := copyObjectsWithPrefix(ctx, srcBucket, "old-bucket", "old-entry/", dstBucket, "new-entry/")
+
+			Expect(err).To(MatchError("there is no full snapshot with prefix old-entry/ in bucket old-bucket"))
+			Expect(dstBucket.copiedKeys).To(BeEmpty())
+		})
+	})
+})
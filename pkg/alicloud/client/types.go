@@ -16,7 +16,11 @@ package client
 
 import (
 	"context"
+	"time"
 
+	"github.com/gardener/gardener-extension-provider-alicloud/pkg/alicloud"
+
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/ecs"
 	alicloudvpc "github.com/aliyun/alibaba-cloud-sdk-go/services/vpc"
 )
 
@@ -31,14 +35,86 @@ type VPC interface {
 	DescribeNatGateways(req *alicloudvpc.DescribeNatGatewaysRequest) (*alicloudvpc.DescribeNatGatewaysResponse, error)
 	// DescribeEipAddresses describes the EIP addresses for the request.
 	DescribeEipAddresses(req *alicloudvpc.DescribeEipAddressesRequest) (*alicloudvpc.DescribeEipAddressesResponse, error)
+	// DescribeVSwitches describes the vswitches for the request.
+	DescribeVSwitches(req *alicloudvpc.DescribeVSwitchesRequest) (*alicloudvpc.DescribeVSwitchesResponse, error)
+	// DescribeSnatTableEntries describes the SNAT table entries for the request.
+	DescribeSnatTableEntries(req *alicloudvpc.DescribeSnatTableEntriesRequest) (*alicloudvpc.DescribeSnatTableEntriesResponse, error)
+	// CreateVpc creates a VPC.
+	CreateVpc(req *alicloudvpc.CreateVpcRequest) (*alicloudvpc.CreateVpcResponse, error)
+	// DeleteVpc deletes a VPC.
+	DeleteVpc(req *alicloudvpc.DeleteVpcRequest) (*alicloudvpc.DeleteVpcResponse, error)
+	// CreateVSwitch creates a vswitch.
+	CreateVSwitch(req *alicloudvpc.CreateVSwitchRequest) (*alicloudvpc.CreateVSwitchResponse, error)
+	// DeleteVSwitch deletes a vswitch.
+	DeleteVSwitch(req *alicloudvpc.DeleteVSwitchRequest) (*alicloudvpc.DeleteVSwitchResponse, error)
+	// CreateNatGateway creates a NAT gateway.
+	CreateNatGateway(req *alicloudvpc.CreateNatGatewayRequest) (*alicloudvpc.CreateNatGatewayResponse, error)
+	// DeleteNatGateway deletes a NAT gateway.
+	DeleteNatGateway(req *alicloudvpc.DeleteNatGatewayRequest) (*alicloudvpc.DeleteNatGatewayResponse, error)
+	// AllocateEipAddress allocates an EIP address.
+	AllocateEipAddress(req *alicloudvpc.AllocateEipAddressRequest) (*alicloudvpc.AllocateEipAddressResponse, error)
+	// AssociateEipAddress associates an EIP address with an instance.
+	AssociateEipAddress(req *alicloudvpc.AssociateEipAddressRequest) (*alicloudvpc.AssociateEipAddressResponse, error)
+	// UnassociateEipAddress unassociates an EIP address from an instance.
+	UnassociateEipAddress(req *alicloudvpc.UnassociateEipAddressRequest) (*alicloudvpc.UnassociateEipAddressResponse, error)
+	// ReleaseEipAddress releases an EIP address.
+	ReleaseEipAddress(req *alicloudvpc.ReleaseEipAddressRequest) (*alicloudvpc.ReleaseEipAddressResponse, error)
+	// CreateSnatEntry creates a SNAT table entry.
+	CreateSnatEntry(req *alicloudvpc.CreateSnatEntryRequest) (*alicloudvpc.CreateSnatEntryResponse, error)
+	// DeleteSnatEntry deletes a SNAT table entry.
+	DeleteSnatEntry(req *alicloudvpc.DeleteSnatEntryRequest) (*alicloudvpc.DeleteSnatEntryResponse, error)
+	// DescribeForwardTableEntries describes the DNAT entries of a forward table for the request.
+	DescribeForwardTableEntries(req *alicloudvpc.DescribeForwardTableEntriesRequest) (*alicloudvpc.DescribeForwardTableEntriesResponse, error)
+	// CreateForwardEntry creates a DNAT entry in a forward table.
+	CreateForwardEntry(req *alicloudvpc.CreateForwardEntryRequest) (*alicloudvpc.CreateForwardEntryResponse, error)
+	// DeleteForwardEntry deletes a DNAT entry from a forward table.
+	DeleteForwardEntry(req *alicloudvpc.DeleteForwardEntryRequest) (*alicloudvpc.DeleteForwardEntryResponse, error)
+	// TagResources adds or updates tags of VPC resources.
+	TagResources(req *alicloudvpc.TagResourcesRequest) (*alicloudvpc.TagResourcesResponse, error)
+	// ListTagResources lists the tags of VPC resources for the request.
+	ListTagResources(req *alicloudvpc.ListTagResourcesRequest) (*alicloudvpc.ListTagResourcesResponse, error)
+	// DescribeRouteEntryList describes the route entries for the request.
+	DescribeRouteEntryList(req *alicloudvpc.DescribeRouteEntryListRequest) (*alicloudvpc.DescribeRouteEntryListResponse, error)
+	// CreateRouteEntry creates a route entry.
+	CreateRouteEntry(req *alicloudvpc.CreateRouteEntryRequest) (*alicloudvpc.CreateRouteEntryResponse, error)
+	// DeleteRouteEntry deletes a route entry.
+	DeleteRouteEntry(req *alicloudvpc.DeleteRouteEntryRequest) (*alicloudvpc.DeleteRouteEntryResponse, error)
+	// DescribeRouteTableList describes the route tables for the request.
+	DescribeRouteTableList(req *alicloudvpc.DescribeRouteTableListRequest) (*alicloudvpc.DescribeRouteTableListResponse, error)
+	// AssociateRouteTable associates a route table with a vswitch.
+	AssociateRouteTable(req *alicloudvpc.AssociateRouteTableRequest) (*alicloudvpc.AssociateRouteTableResponse, error)
+	// UnassociateRouteTable unassociates a route table from a vswitch.
+	UnassociateRouteTable(req *alicloudvpc.UnassociateRouteTableRequest) (*alicloudvpc.UnassociateRouteTableResponse, error)
+	// CreateRouteTable creates a custom route table.
+	CreateRouteTable(req *alicloudvpc.CreateRouteTableRequest) (*alicloudvpc.CreateRouteTableResponse, error)
+	// DeleteRouteTable deletes a custom route table.
+	DeleteRouteTable(req *alicloudvpc.DeleteRouteTableRequest) (*alicloudvpc.DeleteRouteTableResponse, error)
+	// DescribeFlowLogs describes the flow logs for the request.
+	DescribeFlowLogs(req *alicloudvpc.DescribeFlowLogsRequest) (*alicloudvpc.DescribeFlowLogsResponse, error)
+	// CreateFlowLog creates a flow log.
+	CreateFlowLog(req *alicloudvpc.CreateFlowLogRequest) (*alicloudvpc.CreateFlowLogResponse, error)
+	// DeleteFlowLog deletes a flow log.
+	DeleteFlowLog(req *alicloudvpc.DeleteFlowLogRequest) (*alicloudvpc.DeleteFlowLogResponse, error)
+	// CreateDhcpOptionsSet creates a DHCP options set.
+	CreateDhcpOptionsSet(req *CreateDhcpOptionsSetRequest) (*CreateDhcpOptionsSetResponse, error)
+	// GetDhcpOptionsSet gets a DHCP options set and the VPCs it is associated with.
+	GetDhcpOptionsSet(req *GetDhcpOptionsSetRequest) (*GetDhcpOptionsSetResponse, error)
+	// UpdateDhcpOptionsSetAttribute updates the options of a DHCP options set.
+	UpdateDhcpOptionsSetAttribute(req *UpdateDhcpOptionsSetAttributeRequest) (*DhcpOptionsSetResponse, error)
+	// AttachDhcpOptionsSetToVpc associates a DHCP options set with a VPC.
+	AttachDhcpOptionsSetToVpc(req *AttachDhcpOptionsSetToVpcRequest) (*DhcpOptionsSetResponse, error)
+	// DetachDhcpOptionsSetFromVpc unassociates a DHCP options set from a VPC.
+	DetachDhcpOptionsSetFromVpc(req *DetachDhcpOptionsSetFromVpcRequest) (*DhcpOptionsSetResponse, error)
+	// DeleteDhcpOptionsSet deletes a DHCP options set.
+	DeleteDhcpOptionsSet(req *DeleteDhcpOptionsSetRequest) (*DhcpOptionsSetResponse, error)
 }
 
 // ClientFactory is the new factory to instantiate Alicloud clients.
 // TODO: move VPC to this new factory.
 type ClientFactory interface {
-	NewECSClient(ctx context.Context, region, accessKeyID, accessKeySecret string) (ECS, error)
-	NewSTSClient(ctx context.Context, region, accessKeyID, accessKeySecret string) (STS, error)
-	NewSLBClient(ctx context.Context, region, accessKeyID, accessKeySecret string) (SLB, error)
+	NewECSClient(ctx context.Context, region string, credentials *alicloud.Credentials) (ECS, error)
+	NewSTSClient(ctx context.Context, region string, credentials *alicloud.Credentials) (STS, error)
+	NewSLBClient(ctx context.Context, region string, credentials *alicloud.Credentials) (SLB, error)
 }
 
 // STS is an interface which must be implemented by alicloud sts clients.
@@ -50,24 +126,66 @@ type STS interface {
 type ECS interface {
 	CheckIfImageExists(ctx context.Context, imageID string) (bool, error)
 	ShareImageToAccount(ctx context.Context, regionID, imageID, accountID string) error
+	GetImageByName(ctx context.Context, name string) (*ecs.Image, error)
+	GetAvailableImageByName(ctx context.Context, name string) (*ecs.Image, error)
+	CopyEncryptedImage(ctx context.Context, regionID, imageID, name string, kmsKeyID *string, tags map[string]string) (string, error)
+	GetImagesByTags(ctx context.Context, tags map[string]string) ([]ecs.Image, error)
+	DeleteImage(ctx context.Context, regionID, imageID string) error
+	GetPrePaidInstancesByTags(ctx context.Context, regionID string, tags map[string]string) ([]ecs.Instance, error)
+	GetDeploymentSet(ctx context.Context, regionID, deploymentSetID string) (*ecs.DeploymentSet, error)
+	GetAvailableInstanceTypes(ctx context.Context, regionID, zoneID, instanceChargeType string) ([]string, error)
+	CheckIfSecurityGroupExists(ctx context.Context, securityGroupID string) (bool, error)
+	GetSecurityGroup(ctx context.Context, securityGroupID string) (*ecs.SecurityGroup, error)
+	GetSecurityGroupRules(ctx context.Context, securityGroupID string) ([]ecs.Permission, error)
+	GetSecurityGroupsByTags(ctx context.Context, vpcID string, tags map[string]string) ([]ecs.SecurityGroup, error)
+	CreateSecurityGroup(ctx context.Context, vpcID, name string) (string, error)
+	AuthorizeSecurityGroupIngress(ctx context.Context, securityGroupID, ipProtocol, portRange, sourceCIDR string) error
+	RevokeSecurityGroupIngress(ctx context.Context, securityGroupID, ipProtocol, portRange, sourceCIDR string) error
+	AuthorizeSecurityGroupEgress(ctx context.Context, securityGroupID, ipProtocol, portRange, destCIDR string) error
+	RevokeSecurityGroupEgress(ctx context.Context, securityGroupID, ipProtocol, portRange, destCIDR string) error
+	RevokeSecurityGroupPermission(ctx context.Context, securityGroupID string, permission ecs.Permission) error
+	DeleteSecurityGroup(ctx context.Context, securityGroupID string) error
+	CheckIfKeyPairExists(ctx context.Context, name string) (bool, error)
+	ImportKeyPair(ctx context.Context, name, publicKey string) error
+	DeleteKeyPair(ctx context.Context, name string) error
+	TagResources(ctx context.Context, resourceType string, resourceIDs []string, tags map[string]string) error
+	UntagResources(ctx context.Context, resourceType string, resourceIDs []string, tagKeys []string) error
 }
 
 // SLB is an interface which must be implemented by alicloud slb clients.
 type SLB interface {
 	GetLoadBalancerIDs(ctx context.Context, region string) ([]string, error)
+	GetLoadBalancerIDByAddress(ctx context.Context, region, address string) (string, error)
 	GetFirstVServerGroupName(ctx context.Context, region, loadBalancerID string) (string, error)
 	DeleteLoadBalancer(ctx context.Context, region, loadBalancerID string) error
+	GetAccessControlListID(ctx context.Context, region, name string) (string, error)
+	CreateAccessControlList(ctx context.Context, region, name string) (string, error)
+	GetAccessControlListEntries(ctx context.Context, region, aclID string) ([]string, error)
+	AddAccessControlListEntries(ctx context.Context, region, aclID string, entries []string) error
+	RemoveAccessControlListEntries(ctx context.Context, region, aclID string, entries []string) error
+	DeleteAccessControlList(ctx context.Context, region, aclID string) error
 }
 
 // Factory is the factory to instantiate Alicloud clients.
 type Factory interface {
 	// NewVPC creates a new VPC client from the given credentials and region.
-	NewVPC(region, accessKeyID, accessKeySecret string) (VPC, error)
+	NewVPC(ctx context.Context, region string, credentials *alicloud.Credentials) (VPC, error)
 }
 
 // Storage is an interface which must be implemented by alicloud oss storage clients.
 type Storage interface {
 	DeleteObjectsWithPrefix(ctx context.Context, bucketName, prefix string) error
+	DeleteObjectsWithPrefixOlderThan(ctx context.Context, bucketName, prefix string, t time.Time) (int, error)
+	RestoreLatestArchivedSnapshots(ctx context.Context, bucketName, prefix string) (int, error)
+	CopyObjectsWithPrefix(ctx context.Context, srcBucketName, srcPrefix, dstBucketName, dstPrefix string) (int, error)
 	CreateBucketIfNotExists(ctx context.Context, bucketName string) error
 	DeleteBucketIfExists(ctx context.Context, bucketName string) error
+	EnableBucketVersioning(ctx context.Context, bucketName string) error
+	GetBucketACL(ctx context.Context, bucketName string) (string, error)
+	SetBucketACL(ctx context.Context, bucketName, acl string) error
+	SetBucketKMSEncryption(ctx context.Context, bucketName, kmsKeyID string) error
+	SetBucketLogging(ctx context.Context, bucketName, targetBucket, targetPrefix string) error
+	DeleteBucketLogging(ctx context.Context, bucketName string) error
+	SetBucketStorageClassTransition(ctx context.Context, bucketName, storageClass string, days int) error
+	GetBucketRegion(ctx context.Context, bucketName string) (string, error)
 }
@@ -17,6 +17,7 @@ package alicloud
 import (
 	"context"
 	"fmt"
+	"regexp"
 
 	extensionscontroller "github.com/gardener/gardener-extensions/pkg/controller"
 
@@ -28,6 +29,13 @@ import (
 type Credentials struct {
 	AccessKeyID     string
 	AccessKeySecret string
+	// SecurityToken is the token of temporary credentials issued by STS.
+	SecurityToken string
+	// RoleARN is the ARN of the RAM role which is assumed with the access key. If it is set, the clients use
+	// temporary credentials of this role instead of the access key.
+	RoleARN string
+	// RoleSessionName is the name of the session used when assuming the RAM role.
+	RoleSessionName string
 }
 
 const (
@@ -35,6 +43,18 @@ const (
 	AccessKeyID = "accessKeyID"
 	// AccessKeySecret is the data field in a secret where the access key secret is stored at.
 	AccessKeySecret = "accessKeySecret"
+	// RoleARN is the data field in a secret where the ARN of the RAM role to assume is stored at.
+	RoleARN = "roleARN"
+	// RoleSessionName is the data field in a secret where the session name for assuming the RAM role is stored at.
+	RoleSessionName = "roleSessionName"
+
+	// DefaultRoleSessionName is the session name used for assuming the RAM role if the secret does not specify one.
+	DefaultRoleSessionName = "gardener-extension-provider-alicloud"
+)
+
+var (
+	roleARNRegex         = regexp.MustCompile(`^acs:ram::[0-9]+:role/[a-zA-Z0-9._-]{1,64}$`)
+	roleSessionNameRegex = regexp.MustCompile(`^[a-zA-Z0-9.@_-]{2,64}$`)
 )
 
 // ReadSecretCredentials reads the Credentials from the given secret.
@@ -53,10 +73,27 @@ func ReadSecretCredentials(secret *corev1.Secret) (*Credentials, error) {
 		return nil, fmt.Errorf("secret %s/%s has no access key secret at data.%s", secret.Namespace, secret.Name, AccessKeySecret)
 	}
 
-	return &Credentials{
+	credentials := &Credentials{
 		AccessKeyID:     string(accessKeyID),
 		AccessKeySecret: string(accessKeySecret),
-	}, nil
+	}
+
+	if roleARN, ok := secret.Data[RoleARN]; ok {
+		if !roleARNRegex.Match(roleARN) {
+			return nil, fmt.Errorf("secret %s/%s has an invalid role arn at data.%s, it must have the format acs:ram::<account-id>:role/<role-name>", secret.Namespace, secret.Name, RoleARN)
+		}
+		credentials.RoleARN = string(roleARN)
+		credentials.RoleSessionName = DefaultRoleSessionName
+
+		if roleSessionName, ok := secret.Data[RoleSessionName]; ok {
+			if !roleSessionNameRegex.Match(roleSessionName) {
+				return nil, fmt.Errorf("secret %s/%s has an invalid role session name at data.%s", secret.Namespace, secret.Name, RoleSessionName)
+			}
+			credentials.RoleSessionName = string(roleSessionName)
+		}
+	}
+
+	return credentials, nil
 }
 
 // ReadCredentialsFromSecretRef reads the credentials from the secret referred by given <secretRef>.
@@ -16,6 +16,7 @@ package alicloud
 
 import (
 	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
 )
@@ -74,5 +75,69 @@ var _ = Describe("Alicloud Suite", func() {
 
 			Expect(err).To(HaveOccurred())
 		})
+
+		It("should read the role to assume", func() {
+			creds, err := ReadSecretCredentials(&corev1.Secret{
+				Data: map[string][]byte{
+					AccessKeyID:     []byte("accessKeyID"),
+					AccessKeySecret: []byte("accessKeySecret"),
+					RoleARN:         []byte("acs:ram::1234567890:role/gardener"),
+					RoleSessionName: []byte("shoot--foo--bar"),
+				},
+			})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(creds).To(Equal(&Credentials{
+				AccessKeyID:     "accessKeyID",
+				AccessKeySecret: "accessKeySecret",
+				RoleARN:         "acs:ram::1234567890:role/gardener",
+				RoleSessionName: "shoot--foo--bar",
+			}))
+		})
+
+		It("should default the role session name", func() {
+			creds, err := ReadSecretCredentials(&corev1.Secret{
+				Data: map[string][]byte{
+					AccessKeyID:     []byte("accessKeyID"),
+					AccessKeySecret: []byte("accessKeySecret"),
+					RoleARN:         []byte("acs:ram::1234567890:role/gardener"),
+				},
+			})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(creds.RoleSessionName).To(Equal(DefaultRoleSessionName))
+		})
+
+		DescribeTable("should error if the role arn is invalid",
+			func(roleARN string) {
+				_, err := ReadSecretCredentials(&corev1.Secret{
+					Data: map[string][]byte{
+						AccessKeyID:     []byte("accessKeyID"),
+						AccessKeySecret: []byte("accessKeySecret"),
+						RoleARN:         []byte(roleARN),
+					},
+				})
+
+				Expect(err).To(HaveOccurred())
+			},
+			Entry("empty", ""),
+			Entry("wrong service", "acs:ecs::1234567890:role/gardener"),
+			Entry("missing account id", "acs:ram:::role/gardener"),
+			Entry("no role", "acs:ram::1234567890:user/gardener"),
+			Entry("missing role name", "acs:ram::1234567890:role/"),
+		)
+
+		It("should error if the role session name is invalid", func() {
+			_, err := ReadSecretCredentials(&corev1.Secret{
+				Data: map[string][]byte{
+					AccessKeyID:     []byte("accessKeyID"),
+					AccessKeySecret: []byte("accessKeySecret"),
+					RoleARN:         []byte("acs:ram::1234567890:role/gardener"),
+					RoleSessionName: []byte("in valid"),
+				},
+			})
+
+			Expect(err).To(HaveOccurred())
+		})
 	})
 })
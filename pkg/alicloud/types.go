@@ -44,6 +44,8 @@ const (
 
 	// CSIPluginImageName is the name of the CSI plugin image.
 	CSIPluginImageName = "csi-plugin-alicloud"
+	// NvidiaDevicePluginImageName is the name of the NVIDIA device plugin image.
+	NvidiaDevicePluginImageName = "nvidia-device-plugin"
 
 	// BucketName is a constant for the key in a backup secret that holds the bucket name.
 	// The bucket name is written to the backup secret by Gardener as a temporary solution.
@@ -66,6 +68,72 @@ const (
 	CloudControllerManagerName = "cloud-controller-manager"
 	// CsiPluginController is the a constant for the name of the CSI Plugin controller
 	CsiPluginController = "csi-plugin-controller"
+	// CSIDiskPluginName is the name of the daemon set of the CSI disk plugin in the shoot.
+	CSIDiskPluginName = "csi-disk-plugin-alicloud"
+
+	// TagKeyShootName is the key of the tag containing the shoot name on all infrastructure resources.
+	TagKeyShootName = "gardener.cloud/shoot-name"
+	// TagKeyProjectName is the key of the tag containing the project name on all infrastructure resources.
+	TagKeyProjectName = "gardener.cloud/project-name"
+	// TagKeyShootUID is the key of the tag containing the UID of the shoot on all infrastructure resources. Unlike the
+	// shoot name, it identifies a shoot uniquely, hence it is used to discover the resources of a shoot.
+	TagKeyShootUID = "gardener.cloud/shoot-uid"
+	// TagKeyEncryptedImageSource is the key of the tag containing the ID of the source image on the encrypted copies of
+	// machine images created by the worker controller.
+	TagKeyEncryptedImageSource = "gardener.cloud/encrypted-image-source"
+	// MaxTagsPerResource is the maximum number of tags Alicloud allows on a single resource.
+	MaxTagsPerResource = 20
+	// MaxUserDataSize is the maximum size in bytes of the (not yet base64 encoded) user data of an ECS instance.
+	MaxUserDataSize = 16 * 1024
+	// KubeletExtraArgsFile is the environment file of the kubelet service which contains the additional command line
+	// arguments of the kubelet configured for the worker pool of a node.
+	KubeletExtraArgsFile = "/var/lib/kubelet/extra-args"
+	// KubeletExtraArgsEnvVar is the environment variable in the KubeletExtraArgsFile containing the additional command
+	// line arguments of the kubelet.
+	KubeletExtraArgsEnvVar = "KUBELET_EXTRA_ARGS"
+
+	// LabelSecondaryENIs is the label of the nodes of worker pools whose ECS instances get secondary elastic network
+	// interfaces for their pods.
+	LabelSecondaryENIs = "networking.alicloud.provider.extensions.gardener.cloud/secondary-enis"
+	// LabelPodsVSwitch is the label of the nodes with secondary elastic network interfaces containing the ID of the
+	// vswitch which the interfaces are created in.
+	LabelPodsVSwitch = "networking.alicloud.provider.extensions.gardener.cloud/pods-vswitch"
+	// LabelGPU is the label of the nodes of worker pools with GPU instance types whose NVIDIA driver is installed by
+	// the extension. The NVIDIA device plugin only runs on these nodes.
+	LabelGPU = "worker.alicloud.provider.extensions.gardener.cloud/gpu"
+
+	// LoadBalancerDefaultsName is the name of the configmap in the kube-system namespace of the shoot containing the
+	// default settings for the load balancers of services of type LoadBalancer.
+	LoadBalancerDefaultsName = "alicloud-loadbalancer-defaults"
+	// LoadBalancerDefaultSpec is the key of the default SLB instance specification in the load balancer defaults.
+	LoadBalancerDefaultSpec = "spec"
+	// LoadBalancerDefaultChargeType is the key of the default SLB charge type in the load balancer defaults.
+	LoadBalancerDefaultChargeType = "chargeType"
+	// LoadBalancerDefaultBandwidth is the key of the default SLB bandwidth in the load balancer defaults.
+	LoadBalancerDefaultBandwidth = "bandwidth"
+
+	// AnnotationLoadBalancerSpec is the service annotation for the SLB instance specification.
+	AnnotationLoadBalancerSpec = "service.beta.kubernetes.io/alicloud-loadbalancer-spec"
+	// AnnotationLoadBalancerChargeType is the service annotation for the SLB charge type.
+	AnnotationLoadBalancerChargeType = "service.beta.kubernetes.io/alicloud-loadbalancer-charge-type"
+	// AnnotationLoadBalancerBandwidth is the service annotation for the SLB bandwidth.
+	AnnotationLoadBalancerBandwidth = "service.beta.kubernetes.io/alicloud-loadbalancer-bandwidth"
+	// AnnotationLoadBalancerAddressType is the service annotation for the SLB address type, either internet or intranet.
+	AnnotationLoadBalancerAddressType = "service.beta.kubernetes.io/alicloud-loadbalancer-address-type"
+	// AnnotationLoadBalancerEstablishedTimeout is the service annotation for the idle timeout of TCP listeners in seconds.
+	AnnotationLoadBalancerEstablishedTimeout = "service.beta.kubernetes.io/alicloud-loadbalancer-established-timeout"
+	// AnnotationLoadBalancerConnectionDrain is the service annotation enabling connection draining, either on or off.
+	AnnotationLoadBalancerConnectionDrain = "service.beta.kubernetes.io/alicloud-loadbalancer-connection-drain"
+	// AnnotationLoadBalancerConnectionDrainTimeout is the service annotation for the connection draining timeout in seconds.
+	AnnotationLoadBalancerConnectionDrainTimeout = "service.beta.kubernetes.io/alicloud-loadbalancer-connection-drain-timeout"
+	// AnnotationLoadBalancerHealthCheckInterval is the service annotation for the health check interval in seconds.
+	AnnotationLoadBalancerHealthCheckInterval = "service.beta.kubernetes.io/alicloud-loadbalancer-health-check-interval"
+	// AnnotationLoadBalancerACLStatus is the service annotation enabling the access control of the listeners, either on or off.
+	AnnotationLoadBalancerACLStatus = "service.beta.kubernetes.io/alicloud-loadbalancer-acl-status"
+	// AnnotationLoadBalancerACLID is the service annotation for the ID of the access control list of the listeners.
+	AnnotationLoadBalancerACLID = "service.beta.kubernetes.io/alicloud-loadbalancer-acl-id"
+	// AnnotationLoadBalancerACLType is the service annotation for the type of the access control list, either white or black.
+	AnnotationLoadBalancerACLType = "service.beta.kubernetes.io/alicloud-loadbalancer-acl-type"
 )
 
 var (
@@ -16,8 +16,12 @@ package helper
 
 import (
 	"fmt"
+	"strings"
 
 	api "github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/alicloud"
+
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	"k8s.io/apimachinery/pkg/api/resource"
 )
 
 // FindVSwitchForPurposeAndZone takes a list of vswitches and tries to find the first entry
@@ -32,6 +36,21 @@ func FindVSwitchForPurposeAndZone(vswitches []api.VSwitch, purpose api.Purpose,
 	return nil, fmt.Errorf("no vswitch with purpose %q in zone %q found", purpose, zone)
 }
 
+// FindVSwitchesForPurposeAndZone takes a list of vswitches and returns all entries whose purpose and
+// zone matches with the given purpose and zone. If no such entry is found then an error will be returned.
+func FindVSwitchesForPurposeAndZone(vswitches []api.VSwitch, purpose api.Purpose, zone string) ([]api.VSwitch, error) {
+	var result []api.VSwitch
+	for _, vswitch := range vswitches {
+		if vswitch.Purpose == purpose && vswitch.Zone == zone {
+			result = append(result, vswitch)
+		}
+	}
+	if len(result) == 0 {
+		return nil, fmt.Errorf("no vswitch with purpose %q in zone %q found", purpose, zone)
+	}
+	return result, nil
+}
+
 // FindSecurityGroupByPurpose takes a list of security groups and tries to find the first entry
 // whose purpose matches with the given purpose. If no such entry is found then an error will be
 // returned.
@@ -45,15 +64,19 @@ func FindSecurityGroupByPurpose(securityGroups []api.SecurityGroup, purpose api.
 }
 
 // FindMachineImage takes a list of machine images and tries to find the first entry
-// whose name and version matches with the given name and version. If no such entry is
-// found then an error will be returned.
-func FindMachineImage(configImages []api.MachineImage, imageName, imageVersion string) (*api.MachineImage, error) {
+// whose name, version, and encryption matches with the given name, version, and encryption. If no such
+// entry is found then an error will be returned.
+func FindMachineImage(configImages []api.MachineImage, imageName, imageVersion string, encrypted bool) (*api.MachineImage, error) {
 	for _, machineImage := range configImages {
-		if machineImage.Name == imageName && machineImage.Version == imageVersion {
+		if machineImage.Name == imageName && machineImage.Version == imageVersion && isEncrypted(machineImage) == encrypted {
 			return &machineImage, nil
 		}
 	}
-	return nil, fmt.Errorf("no machine image name %q in version %q found", imageName, imageVersion)
+	return nil, fmt.Errorf("no machine image name %q in version %q (encrypted: %t) found", imageName, imageVersion, encrypted)
+}
+
+func isEncrypted(machineImage api.MachineImage) bool {
+	return machineImage.Encrypted != nil && *machineImage.Encrypted
 }
 
 // FindImageForRegionFromCloudProfile takes a list of machine images, and the desired image name, version, and region. It tries
@@ -80,3 +103,163 @@ func FindImageForRegionFromCloudProfile(cloudProfileConfig *api.CloudProfileConf
 
 	return "", fmt.Errorf("could not find an image for name %q in version %q", imageName, imageVersion)
 }
+
+// FindMachineImageVersion returns the given version of the machine image with the given name in the given
+// CloudProfileConfig. If it cannot be found then an error is returned.
+func FindMachineImageVersion(cloudProfileConfig *api.CloudProfileConfig, imageName, imageVersion string) (*api.MachineImageVersion, error) {
+	if cloudProfileConfig != nil {
+		for _, machineImage := range cloudProfileConfig.MachineImages {
+			if machineImage.Name != imageName {
+				continue
+			}
+			for _, version := range machineImage.Versions {
+				if imageVersion == version.Version {
+					return &version, nil
+				}
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("could not find machine image %q in version %q", imageName, imageVersion)
+}
+
+// FindDefaultMachineImageForRegion returns the default machine image of the given region in the given
+// CloudProfileConfig, or nil if the region has none.
+func FindDefaultMachineImageForRegion(cloudProfileConfig *api.CloudProfileConfig, regionName string) *api.DefaultMachineImage {
+	if cloudProfileConfig == nil {
+		return nil
+	}
+	for _, defaultMachineImage := range cloudProfileConfig.DefaultMachineImages {
+		if defaultMachineImage.Region == regionName {
+			return &defaultMachineImage
+		}
+	}
+	return nil
+}
+
+// ResolveMachineImage returns the name and version of the machine image a worker pool in the given region uses. A
+// machine image with name and version is used as is. Otherwise, the default machine image of the region is used if
+// the worker pool specifies no name or the name of the default. The returned name or version are empty if the machine
+// image cannot be resolved.
+func ResolveMachineImage(cloudProfileConfig *api.CloudProfileConfig, imageName, imageVersion, regionName string) (string, string) {
+	if len(imageName) > 0 && len(imageVersion) > 0 {
+		return imageName, imageVersion
+	}
+
+	defaultMachineImage := FindDefaultMachineImageForRegion(cloudProfileConfig, regionName)
+	if defaultMachineImage == nil || (len(imageName) > 0 && imageName != defaultMachineImage.Name) {
+		return imageName, imageVersion
+	}
+	return defaultMachineImage.Name, defaultMachineImage.Version
+}
+
+// minimumSystemDiskSize is the minimum size of system disks supported by Alicloud.
+var minimumSystemDiskSize = resource.MustParse("20Gi")
+
+// MinimumVolumeSize returns the minimum size of the system disk of worker pools using the given machine image version,
+// i.e. the size the version requires according to the given CloudProfileConfig but at least the minimum size Alicloud
+// supports. Versions which are not found in the CloudProfileConfig only require the latter.
+func MinimumVolumeSize(cloudProfileConfig *api.CloudProfileConfig, imageName, imageVersion string) (resource.Quantity, error) {
+	version, err := FindMachineImageVersion(cloudProfileConfig, imageName, imageVersion)
+	if err != nil || version.MinimumVolumeSize == nil {
+		return minimumSystemDiskSize.DeepCopy(), nil
+	}
+
+	size, err := resource.ParseQuantity(*version.MinimumVolumeSize)
+	if err != nil {
+		return resource.Quantity{}, fmt.Errorf("could not parse minimum volume size of machine image %s/%s: %v", imageName, imageVersion, err)
+	}
+	if size.Cmp(minimumSystemDiskSize) < 0 {
+		return minimumSystemDiskSize.DeepCopy(), nil
+	}
+	return size, nil
+}
+
+// localNVMeDiskInstanceFamilies are the instance families whose instances come with local NVMe disks.
+var localNVMeDiskInstanceFamilies = map[string]bool{
+	"ecs.i2":    true,
+	"ecs.i2g":   true,
+	"ecs.i2ne":  true,
+	"ecs.i2gne": true,
+}
+
+// HasLocalNVMeDisks returns whether instances of the given instance type, e.g. `ecs.i2.xlarge`, come with local NVMe
+// disks.
+func HasLocalNVMeDisks(instanceType string) bool {
+	index := strings.LastIndex(instanceType, ".")
+	if index < 0 {
+		return false
+	}
+	return localNVMeDiskInstanceFamilies[instanceType[:index]]
+}
+
+// sharedInstanceFamilies are the instance families whose instances share the physical CPUs with other instances.
+var sharedInstanceFamilies = map[string]bool{
+	"ecs.t5":  true,
+	"ecs.t6":  true,
+	"ecs.s6":  true,
+	"ecs.e":   true,
+	"ecs.n4":  true,
+	"ecs.mn4": true,
+	"ecs.xn4": true,
+	"ecs.e4":  true,
+}
+
+// IsSharedInstanceType returns whether the given instance type, e.g. `ecs.t6-c1m2.large`, is a shared or burstable
+// instance type. Such instances cannot join low latency deployment sets.
+func IsSharedInstanceType(instanceType string) bool {
+	index := strings.LastIndex(instanceType, ".")
+	if index < 0 {
+		return false
+	}
+	family := instanceType[:index]
+	// The instance types of burstable families carry the CPU to memory ratio, e.g. `ecs.t6-c1m2`.
+	if dash := strings.Index(family, "-"); dash >= 0 {
+		family = family[:dash]
+	}
+	return sharedInstanceFamilies[family]
+}
+
+// gpuInstanceFamilies are the instance families whose instances come with NVIDIA GPUs.
+var gpuInstanceFamilies = map[string]bool{
+	"ecs.gn5":     true,
+	"ecs.gn5i":    true,
+	"ecs.gn6i":    true,
+	"ecs.gn6v":    true,
+	"ecs.gn6e":    true,
+	"ecs.gn7":     true,
+	"ecs.gn7i":    true,
+	"ecs.gn7e":    true,
+	"ecs.vgn5i":   true,
+	"ecs.vgn6i":   true,
+	"ecs.ebmgn6i": true,
+	"ecs.ebmgn6v": true,
+	"ecs.ebmgn6e": true,
+	"ecs.ebmgn7":  true,
+	"ecs.ebmgn7i": true,
+	"ecs.ebmgn7e": true,
+}
+
+// IsGPUInstanceType returns whether instances of the given instance type, e.g. `ecs.gn6i-c4g1.xlarge`, come with
+// NVIDIA GPUs.
+func IsGPUInstanceType(instanceType string) bool {
+	index := strings.LastIndex(instanceType, ".")
+	if index < 0 {
+		return false
+	}
+	family := instanceType[:index]
+	// The instance types of GPU families carry the number of CPUs and GPUs, e.g. `ecs.gn6i-c4g1`.
+	if dash := strings.Index(family, "-"); dash >= 0 {
+		family = family[:dash]
+	}
+	return gpuInstanceFamilies[family]
+}
+
+// UsesIPVSProxyMode returns whether kube-proxy of the given shoot runs in the IPVS proxy mode.
+func UsesIPVSProxyMode(shoot *gardencorev1beta1.Shoot) bool {
+	if shoot == nil {
+		return false
+	}
+	kubeProxy := shoot.Spec.Kubernetes.KubeProxy
+	return kubeProxy != nil && kubeProxy.Mode != nil && *kubeProxy.Mode == gardencorev1beta1.ProxyModeIPVS
+}
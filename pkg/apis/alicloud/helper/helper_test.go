@@ -18,9 +18,15 @@ import (
 	api "github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/alicloud"
 	. "github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/alicloud/helper"
 
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
 )
 
 const profileImageID = "id-1235"
@@ -29,6 +35,7 @@ var _ = Describe("Helper", func() {
 	var (
 		purpose      api.Purpose = "foo"
 		purposeWrong api.Purpose = "baz"
+		encrypted                = true
 	)
 
 	DescribeTable("#FindVSwitchForPurposeAndZone",
@@ -44,6 +51,22 @@ var _ = Describe("Helper", func() {
 		Entry("entry exists", []api.VSwitch{{ID: "bar", Purpose: purposeWrong, Zone: "europe"}}, purposeWrong, "europe", &api.VSwitch{ID: "bar", Purpose: purposeWrong, Zone: "europe"}, false),
 	)
 
+	DescribeTable("#FindVSwitchesForPurposeAndZone",
+		func(vswitches []api.VSwitch, purpose api.Purpose, zone string, expectedVSwitches []api.VSwitch, expectErr bool) {
+			result, err := FindVSwitchesForPurposeAndZone(vswitches, purpose, zone)
+			if expectErr {
+				Expect(err).To(HaveOccurred())
+			} else {
+				Expect(err).NotTo(HaveOccurred())
+			}
+			Expect(result).To(Equal(expectedVSwitches))
+		},
+
+		Entry("list is nil", nil, purpose, "europe", nil, true),
+		Entry("entry not found (no zone)", []api.VSwitch{{ID: "bar", Purpose: purpose, Zone: "europe"}}, purpose, "asia", nil, true),
+		Entry("entries exist", []api.VSwitch{{ID: "bar", Purpose: purpose, Zone: "europe"}, {ID: "baz", Purpose: purposeWrong, Zone: "europe"}, {ID: "foo", Purpose: purpose, Zone: "europe"}}, purpose, "europe", []api.VSwitch{{ID: "bar", Purpose: purpose, Zone: "europe"}, {ID: "foo", Purpose: purpose, Zone: "europe"}}, false),
+	)
+
 	DescribeTable("#FindSecurityGroupByPurpose",
 		func(securityGroups []api.SecurityGroup, purpose api.Purpose, expectedSecurityGroup *api.SecurityGroup, expectErr bool) {
 			securityGroup, err := FindSecurityGroupByPurpose(securityGroups, purpose)
@@ -57,16 +80,18 @@ var _ = Describe("Helper", func() {
 	)
 
 	DescribeTable("#FindMachineImage",
-		func(configImages []api.MachineImage, name, version string, expectedMachineImage *api.MachineImage, expectErr bool) {
-			machineImage, err := FindMachineImage(configImages, name, version)
+		func(configImages []api.MachineImage, name, version string, encrypted bool, expectedMachineImage *api.MachineImage, expectErr bool) {
+			machineImage, err := FindMachineImage(configImages, name, version, encrypted)
 			expectResults(machineImage, expectedMachineImage, err, expectErr)
 		},
 
-		Entry("list is nil", nil, "foo", "1.2.3", nil, true),
-		Entry("empty list", []api.MachineImage{}, "foo", "1.2.3", nil, true),
-		Entry("entry not found (no name)", []api.MachineImage{{Name: "bar", Version: "1.2.3", ID: "id123"}}, "foo", "1.2.3", nil, true),
-		Entry("entry not found (no version)", []api.MachineImage{{Name: "bar", Version: "1.2.3", ID: "id123"}}, "foo", "1.2.4", nil, true),
-		Entry("entry exists", []api.MachineImage{{Name: "bar", Version: "1.2.3", ID: "id123"}}, "bar", "1.2.3", &api.MachineImage{Name: "bar", Version: "1.2.3", ID: "id123"}, false),
+		Entry("list is nil", nil, "foo", "1.2.3", false, nil, true),
+		Entry("empty list", []api.MachineImage{}, "foo", "1.2.3", false, nil, true),
+		Entry("entry not found (no name)", []api.MachineImage{{Name: "bar", Version: "1.2.3", ID: "id123"}}, "foo", "1.2.3", false, nil, true),
+		Entry("entry not found (no version)", []api.MachineImage{{Name: "bar", Version: "1.2.3", ID: "id123"}}, "foo", "1.2.4", false, nil, true),
+		Entry("entry not found (not encrypted)", []api.MachineImage{{Name: "bar", Version: "1.2.3", ID: "id123"}}, "bar", "1.2.3", true, nil, true),
+		Entry("entry exists", []api.MachineImage{{Name: "bar", Version: "1.2.3", ID: "id123"}}, "bar", "1.2.3", false, &api.MachineImage{Name: "bar", Version: "1.2.3", ID: "id123"}, false),
+		Entry("encrypted entry exists", []api.MachineImage{{Name: "bar", Version: "1.2.3", ID: "id123"}, {Name: "bar", Version: "1.2.3", ID: "id456", Encrypted: &encrypted}}, "bar", "1.2.3", true, &api.MachineImage{Name: "bar", Version: "1.2.3", ID: "id456", Encrypted: &encrypted}, false),
 	)
 
 	DescribeTable("#FindImageForRegion",
@@ -91,8 +116,167 @@ var _ = Describe("Helper", func() {
 		Entry("profile entry", makeProfileMachineImages("ubuntu", "1", "china"), "ubuntu", "1", "china", profileImageID),
 		Entry("profile non matching region", makeProfileMachineImages("ubuntu", "1", "china"), "ubuntu", "1", "eu", ""),
 	)
+
+	DescribeTable("#FindMachineImageVersion",
+		func(profileImages []api.MachineImages, imageName, version string, expectFound bool) {
+			cfg := &api.CloudProfileConfig{}
+			cfg.MachineImages = profileImages
+			machineImageVersion, err := FindMachineImageVersion(cfg, imageName, version)
+
+			if expectFound {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(machineImageVersion.Version).To(Equal(version))
+			} else {
+				Expect(err).To(HaveOccurred())
+			}
+		},
+
+		Entry("list is nil", nil, "ubuntu", "1", false),
+		Entry("profile entry not found (image does not exist)", makeProfileMachineImages("debian", "1", "china"), "ubuntu", "1", false),
+		Entry("profile entry not found (version does not exist)", makeProfileMachineImages("ubuntu", "2", "china"), "ubuntu", "1", false),
+		Entry("profile entry", makeProfileMachineImages("ubuntu", "1", "china"), "ubuntu", "1", true),
+	)
+
+	DescribeTable("#ResolveMachineImage",
+		func(imageName, imageVersion, region, expectedName, expectedVersion string) {
+			cfg := &api.CloudProfileConfig{
+				DefaultMachineImages: []api.DefaultMachineImage{
+					{Region: "china", Name: "ubuntu", Version: "2"},
+				},
+			}
+
+			name, version := ResolveMachineImage(cfg, imageName, imageVersion, region)
+			Expect(name).To(Equal(expectedName))
+			Expect(version).To(Equal(expectedVersion))
+		},
+
+		Entry("explicit machine image", "debian", "1", "china", "debian", "1"),
+		Entry("explicit machine image with the default name", "ubuntu", "1", "china", "ubuntu", "1"),
+		Entry("no machine image", "", "", "china", "ubuntu", "2"),
+		Entry("default name without version", "ubuntu", "", "china", "ubuntu", "2"),
+		Entry("other name without version", "debian", "", "china", "debian", ""),
+		Entry("no default for the region", "", "", "europe", "", ""),
+	)
+
+	DescribeTable("#MinimumVolumeSize",
+		func(minimumVolumeSize *string, imageVersion, expected string, expectErr bool) {
+			cfg := &api.CloudProfileConfig{MachineImages: makeProfileMachineImages("ubuntu", "1", "china")}
+			cfg.MachineImages[0].Versions[0].MinimumVolumeSize = minimumVolumeSize
+
+			size, err := MinimumVolumeSize(cfg, "ubuntu", imageVersion)
+			if expectErr {
+				Expect(err).To(HaveOccurred())
+				return
+			}
+			Expect(err).NotTo(HaveOccurred())
+			Expect(size.Cmp(resource.MustParse(expected))).To(BeZero())
+		},
+
+		Entry("version not found", pointer.StringPtr("40Gi"), "2", "20Gi", false),
+		Entry("no minimum size", nil, "1", "20Gi", false),
+		Entry("minimum size of the version", pointer.StringPtr("40Gi"), "1", "40Gi", false),
+		Entry("minimum size of the version below the Alicloud minimum", pointer.StringPtr("10Gi"), "1", "20Gi", false),
+		Entry("invalid minimum size", pointer.StringPtr("foo"), "1", "", true),
+	)
+
+	DescribeTable("#HasLocalNVMeDisks",
+		func(instanceType string, expected bool) {
+			Expect(HasLocalNVMeDisks(instanceType)).To(Equal(expected))
+		},
+
+		Entry("instance family with local NVMe disks", "ecs.i2.xlarge", true),
+		Entry("instance family without local disks", "ecs.g6.large", false),
+		Entry("invalid instance type", "large", false),
+	)
+
+	DescribeTable("#IsSharedInstanceType",
+		func(instanceType string, expected bool) {
+			Expect(IsSharedInstanceType(instanceType)).To(Equal(expected))
+		},
+
+		Entry("burstable instance family", "ecs.t6-c1m2.large", true),
+		Entry("shared instance family", "ecs.s6-c1m2.small", true),
+		Entry("enterprise-level instance family", "ecs.g6.large", false),
+		Entry("invalid instance type", "large", false),
+	)
+
+	DescribeTable("#IsGPUInstanceType",
+		func(instanceType string, expected bool) {
+			Expect(IsGPUInstanceType(instanceType)).To(Equal(expected))
+		},
+
+		Entry("GPU instance family", "ecs.gn6i-c4g1.xlarge", true),
+		Entry("bare metal GPU instance family", "ecs.ebmgn6v.24xlarge", true),
+		Entry("instance family without GPUs", "ecs.g6.large", false),
+		Entry("invalid instance type", "large", false),
+	)
+
+	Describe("#InfrastructureStatusFromInfrastructure", func() {
+		It("should return nil if the infrastructure has no status yet", func() {
+			status, err := InfrastructureStatusFromInfrastructure(&extensionsv1alpha1.Infrastructure{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status).To(BeNil())
+		})
+
+		It("should convert the availability zones of the status", func() {
+			infra := &extensionsv1alpha1.Infrastructure{
+				Status: extensionsv1alpha1.InfrastructureStatus{
+					ProviderStatus: &runtime.RawExtension{Raw: []byte(`{
+"apiVersion": "alicloud.provider.extensions.gardener.cloud/v1alpha1",
+"kind": "InfrastructureStatus",
+"vpc": {"id": "vpc-1"},
+"keyPairName": "key",
+"zones": [{"name": "cn-beijing-f", "zoneID": "cn-beijing-f"}, {"name": "cn-beijing-g", "zoneID": "cn-beijing-g"}]
+}`)},
+				},
+			}
+
+			status, err := InfrastructureStatusFromInfrastructure(infra)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status.VPC.ID).To(Equal("vpc-1"))
+			Expect(status.Zones).To(Equal([]api.ZoneStatus{
+				{Name: "cn-beijing-f", ZoneID: "cn-beijing-f"},
+				{Name: "cn-beijing-g", ZoneID: "cn-beijing-g"},
+			}))
+		})
+
+		It("should leave the zones empty for statuses without them", func() {
+			infra := &extensionsv1alpha1.Infrastructure{
+				Status: extensionsv1alpha1.InfrastructureStatus{
+					ProviderStatus: &runtime.RawExtension{Raw: []byte(`{
+"apiVersion": "alicloud.provider.extensions.gardener.cloud/v1alpha1",
+"kind": "InfrastructureStatus",
+"vpc": {"id": "vpc-1"},
+"keyPairName": "key"
+}`)},
+				},
+			}
+
+			status, err := InfrastructureStatusFromInfrastructure(infra)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status.Zones).To(BeEmpty())
+		})
+	})
+
+	DescribeTable("#UsesIPVSProxyMode",
+		func(mode *gardencorev1beta1.ProxyMode, expected bool) {
+			shoot := &gardencorev1beta1.Shoot{}
+			if mode != nil {
+				shoot.Spec.Kubernetes.KubeProxy = &gardencorev1beta1.KubeProxyConfig{Mode: mode}
+			}
+			Expect(UsesIPVSProxyMode(shoot)).To(Equal(expected))
+		},
+
+		Entry("no kube-proxy config", nil, false),
+		Entry("iptables proxy mode", proxyModePtr(gardencorev1beta1.ProxyModeIPTables), false),
+		Entry("ipvs proxy mode", proxyModePtr(gardencorev1beta1.ProxyModeIPVS), true),
+	)
 })
 
+func proxyModePtr(mode gardencorev1beta1.ProxyMode) *gardencorev1beta1.ProxyMode {
+	return &mode
+}
+
 func makeProfileMachineImages(name, version, region string) []api.MachineImages {
 	versions := []api.MachineImageVersion{
 		{
@@ -61,6 +61,20 @@ func InfrastructureConfigFromInfrastructure(infra *extensionsv1alpha1.Infrastruc
 	return nil, fmt.Errorf("provider config is not set on the infrastructure resource")
 }
 
+// InfrastructureStatusFromInfrastructure extracts the InfrastructureStatus from the ProviderStatus section of the given
+// Infrastructure. Nil is returned if the Infrastructure has not been reconciled yet.
+func InfrastructureStatusFromInfrastructure(infra *extensionsv1alpha1.Infrastructure) (*api.InfrastructureStatus, error) {
+	if infra.Status.ProviderStatus == nil || infra.Status.ProviderStatus.Raw == nil {
+		return nil, nil
+	}
+
+	status := &api.InfrastructureStatus{}
+	if _, _, err := decoder.Decode(infra.Status.ProviderStatus.Raw, nil, status); err != nil {
+		return nil, errors.Wrapf(err, "could not decode providerStatus of infrastructure '%s'", util.ObjectName(infra))
+	}
+	return status, nil
+}
+
 // CloudProfileConfigFromCluster decodes the provider specific cloud profile configuration for a cluster
 func CloudProfileConfigFromCluster(cluster *controller.Cluster) (*api.CloudProfileConfig, error) {
 	var cloudProfileConfig *api.CloudProfileConfig
@@ -72,3 +86,37 @@ func CloudProfileConfigFromCluster(cluster *controller.Cluster) (*api.CloudProfi
 	}
 	return cloudProfileConfig, nil
 }
+
+// ControlPlaneConfigFromCluster decodes the provider specific control plane configuration of the shoot of the given
+// cluster. An empty configuration is returned if the shoot has none.
+func ControlPlaneConfigFromCluster(cluster *controller.Cluster) (*api.ControlPlaneConfig, error) {
+	controlPlaneConfig := &api.ControlPlaneConfig{}
+	if cluster != nil && cluster.Shoot != nil && cluster.Shoot.Spec.Provider.ControlPlaneConfig != nil && cluster.Shoot.Spec.Provider.ControlPlaneConfig.Raw != nil {
+		if _, _, err := decoder.Decode(cluster.Shoot.Spec.Provider.ControlPlaneConfig.Raw, nil, controlPlaneConfig); err != nil {
+			return nil, errors.Wrapf(err, "could not decode controlPlaneConfig of shoot '%s'", util.ObjectName(cluster.Shoot))
+		}
+	}
+	return controlPlaneConfig, nil
+}
+
+// WorkerConfigsFromCluster decodes the provider specific worker configurations of all worker pools of the shoot of the
+// given cluster. The configurations are keyed by the names of the worker pools, pools without provider config are omitted.
+func WorkerConfigsFromCluster(cluster *controller.Cluster) (map[string]*api.WorkerConfig, error) {
+	workerConfigs := map[string]*api.WorkerConfig{}
+	if cluster == nil || cluster.Shoot == nil {
+		return workerConfigs, nil
+	}
+
+	for _, worker := range cluster.Shoot.Spec.Provider.Workers {
+		if worker.ProviderConfig == nil || worker.ProviderConfig.Raw == nil {
+			continue
+		}
+
+		workerConfig := &api.WorkerConfig{}
+		if _, _, err := decoder.Decode(worker.ProviderConfig.Raw, nil, workerConfig); err != nil {
+			return nil, errors.Wrapf(err, "could not decode providerConfig of worker pool '%s'", worker.Name)
+		}
+		workerConfigs[worker.Name] = workerConfig
+	}
+	return workerConfigs, nil
+}
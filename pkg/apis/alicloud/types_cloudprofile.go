@@ -27,8 +27,36 @@ type CloudProfileConfig struct {
 	// MachineImages is the list of machine images that are understood by the controller. It maps
 	// logical names and versions to provider-specific identifiers.
 	MachineImages []MachineImages
+	// DeprecatedMachineImagePolicy specifies how worker pools which use a deprecated machine image version are
+	// treated by the validation. Defaults to `Warn`.
+	DeprecatedMachineImagePolicy *DeprecatedMachineImagePolicy
+	// DefaultMachineImages maps regions to the machine image which worker pools in the region use if they do not
+	// specify one themselves.
+	DefaultMachineImages []DefaultMachineImage
 }
 
+// DeprecatedMachineImagePolicy specifies how worker pools which use a deprecated machine image version are treated.
+type DeprecatedMachineImagePolicy string
+
+const (
+	// DeprecatedMachineImagePolicyWarn only warns about worker pools which use a deprecated machine image version.
+	DeprecatedMachineImagePolicyWarn DeprecatedMachineImagePolicy = "Warn"
+	// DeprecatedMachineImagePolicyBlock rejects worker pools which use a deprecated machine image version.
+	DeprecatedMachineImagePolicyBlock DeprecatedMachineImagePolicy = "Block"
+)
+
+// MachineImageClassification is the classification of a machine image version.
+type MachineImageClassification string
+
+const (
+	// ClassificationPreview indicates that a machine image version is not yet recommended for productive use.
+	ClassificationPreview MachineImageClassification = "preview"
+	// ClassificationSupported indicates that a machine image version is supported.
+	ClassificationSupported MachineImageClassification = "supported"
+	// ClassificationDeprecated indicates that a machine image version is deprecated and should be replaced.
+	ClassificationDeprecated MachineImageClassification = "deprecated"
+)
+
 // MachineImages is a mapping from logical names and versions to provider-specific identifiers.
 type MachineImages struct {
 	// Name is the logical name of the machine image.
@@ -43,6 +71,30 @@ type MachineImageVersion struct {
 	Version string
 	// Regions is a mapping to the correct ID for the machine image in the supported regions.
 	Regions []RegionIDMapping
+	// ImageName is the name of the image in Alicloud. In regions without mapping, the image is looked up by this name.
+	ImageName *string
+	// Classification is the classification of the version. Defaults to `supported`.
+	Classification *MachineImageClassification
+	// ExpirationDate is the date after which worker pools must no longer use the version.
+	ExpirationDate *metav1.Time
+	// Replacement is the version of the same machine image which is recommended instead of this deprecated version.
+	Replacement *string
+	// SupportsIPVS states whether the kernel of the version provides the modules required by the IPVS proxy mode of
+	// kube-proxy. Defaults to `true`.
+	SupportsIPVS *bool
+	// MinimumVolumeSize is the minimum size of the system disk the image requires, e.g. 40Gi. Worker pools without a
+	// volume size get this size.
+	MinimumVolumeSize *string
+}
+
+// DefaultMachineImage is the machine image which worker pools in the given region use by default.
+type DefaultMachineImage struct {
+	// Region is the name of the region.
+	Region string
+	// Name is the logical name of the machine image.
+	Name string
+	// Version is the version of the machine image.
+	Version string
 }
 
 // RegionIDMapping is a mapping to the correct ID for the machine image in the given region.
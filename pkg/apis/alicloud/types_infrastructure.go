@@ -26,6 +26,10 @@ type InfrastructureConfig struct {
 
 	// Networks specifies the networks for an infrastructure.
 	Networks Networks
+
+	// Tags are additional tags which are applied to all Alicloud resources created for the infrastructure.
+	// +optional
+	Tags map[string]string
 }
 
 // Networks specifies the networks for an infrastructure.
@@ -35,6 +39,172 @@ type Networks struct {
 
 	// Zones are the network zones for an infrastructure.
 	Zones []Zone
+
+	// DualStack contains the IPv6 dual-stack settings for an infrastructure.
+	// +optional
+	DualStack *DualStack
+
+	// NatGateway contains settings for the NAT gateway of the VPC.
+	// +optional
+	NatGateway *NatGateway
+
+	// SecurityGroupID is the ID of an existing security group of the VPC which is used for the nodes instead of
+	// creating one. The security group is not managed, i.e. neither its rules are changed nor it is deleted. It can
+	// only be used together with an existing VPC.
+	// +optional
+	SecurityGroupID *string
+
+	// SecurityGroupRules are additional rules which are added to the security group of the nodes.
+	// +optional
+	SecurityGroupRules []SecurityGroupRule
+
+	// Routes are custom entries which are added to the route table of the VPC, or to the route table given by
+	// RouteTableID.
+	// +optional
+	Routes []Route
+
+	// RouteTableID is the ID of an existing route table of the VPC which the vswitches are associated with instead of
+	// the system route table of the VPC. It can only be used together with an existing VPC.
+	// +optional
+	RouteTableID *string
+
+	// EnableFlowLogs specifies whether a flow log capturing the traffic of the VPC is created.
+	// +optional
+	EnableFlowLogs bool
+
+	// FlowLogTarget is the Log Service (SLS) logstore which the flow log delivers to. It is required if flow logs are
+	// enabled.
+	// +optional
+	FlowLogTarget *FlowLogTarget
+
+	// DHCPOptions are the DHCP options of the VPC, e.g. custom DNS servers for the nodes. If set, a DHCP options set is
+	// created and associated with the VPC.
+	// +optional
+	DHCPOptions *DHCPOptions
+}
+
+// DHCPOptions are the DHCP options which the instances of the VPC receive.
+type DHCPOptions struct {
+	// DomainNameServers are the IP addresses of at most four DNS servers. If not set, the DNS servers of Alicloud are
+	// used.
+	DomainNameServers []string
+	// DomainName is the domain name which is appended to unqualified host names.
+	DomainName *string
+}
+
+// FlowLogTarget is an existing Log Service (SLS) logstore. It is not managed by the extension.
+type FlowLogTarget struct {
+	// ProjectName is the name of the SLS project.
+	ProjectName string
+	// LogStoreName is the name of the logstore in the SLS project.
+	LogStoreName string
+}
+
+// SecurityGroupRuleDirection is the direction of a security group rule.
+type SecurityGroupRuleDirection string
+
+const (
+	// SecurityGroupRuleDirectionIngress is the direction of rules for inbound traffic.
+	SecurityGroupRuleDirectionIngress SecurityGroupRuleDirection = "ingress"
+	// SecurityGroupRuleDirectionEgress is the direction of rules for outbound traffic.
+	SecurityGroupRuleDirectionEgress SecurityGroupRuleDirection = "egress"
+)
+
+// SecurityGroupRule is a rule which accepts traffic in the security group of the nodes.
+type SecurityGroupRule struct {
+	// Direction is the direction of the traffic, either ingress or egress.
+	Direction SecurityGroupRuleDirection
+	// Protocol is the IP protocol of the traffic, one of tcp, udp, icmp, gre, or all.
+	Protocol string
+	// PortRange is the range of ports in the format `<from>/<to>`, e.g. `22/22`. It must be `-1/-1` for
+	// protocols without ports.
+	PortRange string
+	// CIDR is the source CIDR for ingress rules and the destination CIDR for egress rules.
+	CIDR string
+}
+
+// RouteNextHopType is the type of the next hop of a route.
+type RouteNextHopType string
+
+const (
+	// RouteNextHopTypeInstance is the next hop type of routes to an ECS instance.
+	RouteNextHopTypeInstance RouteNextHopType = "Instance"
+	// RouteNextHopTypeHaVip is the next hop type of routes to a high-availability virtual IP address.
+	RouteNextHopTypeHaVip RouteNextHopType = "HaVip"
+	// RouteNextHopTypeNetworkInterface is the next hop type of routes to an elastic network interface.
+	RouteNextHopTypeNetworkInterface RouteNextHopType = "NetworkInterface"
+	// RouteNextHopTypeRouterInterface is the next hop type of routes to a router interface, e.g. of a VPC peering.
+	RouteNextHopTypeRouterInterface RouteNextHopType = "RouterInterface"
+	// RouteNextHopTypeVpnGateway is the next hop type of routes to a VPN gateway.
+	RouteNextHopTypeVpnGateway RouteNextHopType = "VpnGateway"
+)
+
+// Route is a custom entry of the route table of the VPC.
+type Route struct {
+	// DestinationCIDR is the destination CIDR of the route.
+	DestinationCIDR string
+	// NextHopType is the type of the next hop, one of Instance, HaVip, NetworkInterface, RouterInterface, or VpnGateway.
+	NextHopType RouteNextHopType
+	// NextHopID is the ID of the next hop.
+	NextHopID string
+}
+
+// NatGateway contains information about the NAT gateway of the VPC.
+type NatGateway struct {
+	// ID is the ID of an existing NAT gateway in the VPC. It can only be used together with an existing VPC.
+	// +optional
+	ID *string
+	// EIPAllocation contains settings for the EIPs allocated for the NAT gateway.
+	// +optional
+	EIPAllocation *EIPAllocation
+	// EIPAllocationID is the allocation ID of an existing EIP which is associated with the NAT gateway instead of
+	// allocating new EIPs. The EIP is owned by the user, i.e., it is unassociated but not released on deletion. It
+	// cannot be used together with NAT gateways per zone.
+	// +optional
+	EIPAllocationID *string
+	// PerZone specifies whether a NAT gateway is created in every zone instead of a single one for the VPC. The
+	// vswitches of a zone route their internet traffic through the NAT gateway of the zone. It can only be used
+	// together with a new VPC.
+	// +optional
+	PerZone bool
+	// DNATEntries are DNAT entries of the NAT gateway which forward inbound traffic to ports of its EIPs to internal
+	// IP addresses and ports, e.g. to node ports of the nodes. They are reconciled declaratively, entries which are
+	// removed are also deleted.
+	DNATEntries []DNATEntry
+}
+
+// DNATEntry is a DNAT entry of the NAT gateway.
+type DNATEntry struct {
+	// ExternalIP is the IP address of an EIP of the NAT gateway.
+	ExternalIP string
+	// ExternalPort is the port of the EIP which the traffic is received on.
+	ExternalPort int32
+	// InternalIP is the private IP address in the VPC which the traffic is forwarded to.
+	InternalIP string
+	// InternalPort is the port which the traffic is forwarded to.
+	InternalPort int32
+	// Protocol is the IP protocol of the traffic, either tcp or udp.
+	Protocol string
+}
+
+// EIPAllocation contains settings for the EIPs allocated for the NAT gateway.
+type EIPAllocation struct {
+	// Bandwidth is the peak bandwidth of the EIPs in Mbps.
+	// +optional
+	Bandwidth *int32
+	// InternetChargeType is the billing method of the EIPs, either PayByTraffic or PayByBandwidth.
+	// +optional
+	InternetChargeType *string
+	// Count is the number of EIPs allocated for the NAT gateway of every zone, which are all used by its SNAT entries.
+	// More than one EIP can only be allocated for NAT gateways per zone. Defaults to 1.
+	// +optional
+	Count *int32
+}
+
+// DualStack contains information about whether the VPC and its vswitches should get IPv6 CIDRs.
+type DualStack struct {
+	// Enabled specifies whether IPv6 should be enabled in addition to IPv4.
+	Enabled bool
 }
 
 // VPC contains information about whether to create a new or use an existing VPC.
@@ -55,6 +225,12 @@ type VPCStatus struct {
 	VSwitches []VSwitch
 	// SecurityGroups is a list of security groups.
 	SecurityGroups []SecurityGroup
+	// IPv6CIDR is the IPv6 CIDR allocated to the VPC if dual-stack is enabled.
+	// +optional
+	IPv6CIDR string
+	// NatGateways is a list of the NAT gateways of the zones. It is only set if a NAT gateway is created per zone.
+	// +optional
+	NatGateways []NatGatewayStatus
 }
 
 // Purpose is a purpose of a subnet.
@@ -65,6 +241,8 @@ const (
 	PurposeNodes Purpose = "nodes"
 	// PurposeInternal is a Purpose for internal use.
 	PurposeInternal Purpose = "internal"
+	// PurposePods is a Purpose for the secondary ENIs of pods.
+	PurposePods Purpose = "pods"
 )
 
 // VSwitch contains information about a vswitch.
@@ -75,6 +253,9 @@ type VSwitch struct {
 	ID string
 	// Zone is the name of the zone.
 	Zone string
+	// IPv6CIDR is the IPv6 CIDR allocated to the vswitch if dual-stack is enabled.
+	// +optional
+	IPv6CIDR string
 }
 
 // SecurityGroup contains information about a security group.
@@ -85,6 +266,36 @@ type SecurityGroup struct {
 	ID string
 }
 
+// ZoneStatus contains the availability zone which a zone of the infrastructure is resolved to.
+type ZoneStatus struct {
+	// Name is the name of the zone in the InfrastructureConfig.
+	Name string
+	// ZoneID is the ID of the availability zone which Alicloud reports for the vswitches of the zone.
+	ZoneID string
+}
+
+// NatGatewayStatus contains information about the NAT gateway of a zone.
+type NatGatewayStatus struct {
+	// ID is the id of the NAT gateway.
+	ID string
+	// Zone is the name of the zone.
+	Zone string
+	// VSwitchID is the id of the dedicated vswitch of the NAT gateway if the zone has one.
+	// +optional
+	VSwitchID string
+	// EIPs is a list of the EIPs allocated for the NAT gateway.
+	// +optional
+	EIPs []EIPStatus
+}
+
+// EIPStatus contains information about an EIP allocated for a NAT gateway.
+type EIPStatus struct {
+	// AllocationID is the allocation id of the EIP.
+	AllocationID string
+	// IPAddress is the public IP address of the EIP.
+	IPAddress string
+}
+
 // Zone is a zone with a name and worker CIDR.
 type Zone struct {
 	// Name is the name of a zone.
@@ -94,6 +305,18 @@ type Zone struct {
 	Worker string
 	// Workers specifies the worker CIDR to use.
 	Workers string
+	// AdditionalWorkers specifies additional worker CIDRs. For every CIDR an additional vswitch is created in the zone.
+	// +optional
+	AdditionalWorkers []string
+	// NatGatewayCIDR specifies the CIDR of a dedicated vswitch for the NAT gateway of the zone. It can only be used
+	// together with NAT gateways per zone. If it is not set then the NAT gateway is placed in the first worker vswitch.
+	// +optional
+	NatGatewayCIDR *string
+	// PodsCIDR specifies the CIDR of a dedicated vswitch for the secondary ENIs of the zone's instances. Pods get their
+	// IP addresses from this vswitch if the Terway network plugin runs in ENI mode. It must either be set for all zones
+	// or for none.
+	// +optional
+	PodsCIDR *string
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -111,4 +334,37 @@ type InfrastructureStatus struct {
 	// it cannot reconcile anymore existing `Infrastructure` resources that are still using this version. Hence, it stores
 	// the used versions in the provider status to ensure reconciliation is possible.
 	MachineImages []MachineImage
+
+	// Zones contains the availability zones which the zones of the infrastructure are resolved to.
+	Zones []ZoneStatus
+
+	// Plan contains the changes which a reconciliation would apply to the infrastructure. It is only set if the
+	// Infrastructure is reconciled in dry-run mode, in which case no infrastructure resources are changed.
+	Plan *InfrastructurePlan
+}
+
+// InfrastructurePlan contains the changes which a reconciliation would apply to the infrastructure.
+type InfrastructurePlan struct {
+	// Changes is the list of changes to the infrastructure resources.
+	Changes []InfrastructureChange
+}
+
+// InfrastructureChangeAction is the action of an infrastructure change.
+type InfrastructureChangeAction string
+
+const (
+	// InfrastructureChangeActionCreate is the action of resources which would be created.
+	InfrastructureChangeActionCreate InfrastructureChangeAction = "Create"
+	// InfrastructureChangeActionUpdate is the action of resources which would be updated.
+	InfrastructureChangeActionUpdate InfrastructureChangeAction = "Update"
+	// InfrastructureChangeActionDelete is the action of resources which would be deleted.
+	InfrastructureChangeActionDelete InfrastructureChangeAction = "Delete"
+)
+
+// InfrastructureChange is a change to an infrastructure resource.
+type InfrastructureChange struct {
+	// Action is the action which would be applied to the resource.
+	Action InfrastructureChangeAction
+	// Resource describes the affected resource.
+	Resource string
 }
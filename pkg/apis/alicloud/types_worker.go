@@ -16,10 +16,164 @@ package alicloud
 
 import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
+// WorkerConfig contains configuration settings for the worker nodes.
+type WorkerConfig struct {
+	metav1.TypeMeta
+
+	// ImageID is the ID of a custom image which is used for the worker nodes instead of the machine image of the
+	// worker pool.
+	ImageID *string
+	// SystemDisk contains configuration for the system disk of the worker nodes.
+	SystemDisk *SystemDisk
+	// DataVolumes contains configuration for additional data disks of the worker nodes.
+	DataVolumes []DataVolume
+	// Tags are additional tags which are applied to the ECS instances of the worker pool.
+	Tags map[string]string
+	// SpotStrategy is the spot strategy of the ECS instances of the worker pool. If not set, no spot instances are used.
+	SpotStrategy *SpotStrategy
+	// SpotPriceLimit is the maximum hourly price of a spot instance. It is required for the SpotWithPriceLimit strategy.
+	SpotPriceLimit *string
+	// DeploymentSetID is the ID of a deployment set which the ECS instances of the worker pool join, i.e., their
+	// placement group. It cannot be used together with spot instances.
+	DeploymentSetID *string
+	// PlacementStrategy is the strategy of the deployment set given by DeploymentSetID, i.e., whether it spreads the
+	// ECS instances of the worker pool across physical hosts for availability (Spread) or packs them close to each
+	// other for a low network latency (Pack). If set, the strategy of the deployment set is checked against it.
+	PlacementStrategy *PlacementStrategy
+	// SecurityGroupIDs are the IDs of existing security groups of the VPC of the shoot which are attached to the ECS
+	// instances of the worker pool in addition to the security group managed by Gardener.
+	SecurityGroupIDs []string
+	// RAMRoleName is the name of a RAM role which is attached to the ECS instances of the worker pool, so that
+	// workloads can obtain temporary credentials of the role from the instance metadata.
+	RAMRoleName *string
+	// UseLocalDisk specifies whether the local NVMe disks of the ECS instances of the worker pool are formatted and
+	// mounted below /mnt/local-disks. It is only supported for instance families with local NVMe disks.
+	UseLocalDisk bool
+	// GPUDriverVersion is the version of the NVIDIA driver which is installed on the ECS instances of the worker pool.
+	// It must only be set for GPU instance types, if not set a default version is installed on them.
+	GPUDriverVersion *string
+	// SecondaryENIs specifies whether the ECS instances of the worker pool get secondary elastic network interfaces
+	// from the pods vswitches of their zones, e.g. for the Terway network plugin in ENI mode. It requires pods
+	// vswitches in the InfrastructureConfig.
+	SecondaryENIs bool
+	// UserData is an additional cloud-config or shell script which cloud-init runs on the ECS instances of the worker
+	// pool before the user data generated by Gardener, e.g. to configure registry mirrors before the kubelet starts.
+	UserData *string
+	// InstanceChargeType is the billing method of the ECS instances of the worker pool. If not set, PostPaid
+	// (pay-as-you-go) is used.
+	InstanceChargeType *InstanceChargeType
+	// Period is the subscription period of PrePaid ECS instances in units of the period unit. It must only be set for
+	// PrePaid instances, if not set a period of one month is used.
+	Period *int32
+	// PeriodUnit is the unit of the subscription period, either Week or Month. It must only be set for PrePaid
+	// instances, if not set Month is used.
+	PeriodUnit *PeriodUnit
+	// AutoRenew specifies whether the subscription of PrePaid ECS instances is renewed automatically when it ends. It
+	// must only be set for PrePaid instances.
+	AutoRenew bool
+	// AutoRenewPeriod is the period of the automatic renewals in units of the period unit. It must only be set if
+	// AutoRenew is enabled, if not set the subscription is renewed for one period unit.
+	AutoRenewPeriod *int32
+	// DrainTimeout is the maximum duration for which the nodes of the worker pool are drained before their machines
+	// are deleted, if not set the default drain timeout of the machine-controller-manager is used.
+	DrainTimeout *metav1.Duration
+	// MaxSurge is the maximum number of machines of the worker pool which are created in addition to the desired
+	// number during a rolling update, as number or percentage. If set, it overwrites the max surge of the worker pool.
+	MaxSurge *intstr.IntOrString
+	// MaxUnavailable is the maximum number of machines of the worker pool which may be unavailable during a rolling
+	// update, as number or percentage. If set, it overwrites the max unavailable of the worker pool.
+	MaxUnavailable *intstr.IntOrString
+	// KubeletConfig contains kubelet settings of the worker pool. They are passed as command line arguments to the
+	// kubelets of its nodes and must not be set in the kubelet configuration of the shoot as well.
+	KubeletConfig *KubeletConfig
+}
+
+// KubeletConfig contains kubelet settings of a worker pool.
+type KubeletConfig struct {
+	// MaxPods is the maximum number of pods on a node.
+	MaxPods *int32
+	// EvictionHard are the hard eviction thresholds by eviction signal, e.g. memory.available: 500Mi or
+	// nodefs.available: 10%.
+	EvictionHard map[string]string
+	// EvictionSoft are the soft eviction thresholds by eviction signal. Every soft eviction threshold requires a grace
+	// period.
+	EvictionSoft map[string]string
+	// EvictionSoftGracePeriod are the grace periods of the soft eviction thresholds by eviction signal, e.g.
+	// memory.available: 1m30s.
+	EvictionSoftGracePeriod map[string]string
+	// KubeReserved are the resources reserved for the Kubernetes components of a node by resource name, e.g.
+	// memory: 1Gi.
+	KubeReserved map[string]string
+}
+
+// DataVolume contains configuration for an additional data disk of the worker nodes.
+type DataVolume struct {
+	// Name is the name of the data volume.
+	Name string
+	// Type is the disk category of the data volume, e.g. cloud_efficiency, cloud_ssd, or cloud_essd.
+	Type *string
+	// Size is the size of the data volume, e.g. 100Gi.
+	Size string
+	// PerformanceLevel is the performance level of an ESSD data volume, one of PL0, PL1, PL2, or PL3.
+	PerformanceLevel *string
+}
+
+// SpotStrategy is the strategy for spot instances.
+type SpotStrategy string
+
+const (
+	// SpotStrategyAsPriceGo uses spot instances whose price is the current market price.
+	SpotStrategyAsPriceGo SpotStrategy = "SpotAsPriceGo"
+	// SpotStrategyWithPriceLimit uses spot instances whose price is at most the spot price limit.
+	SpotStrategyWithPriceLimit SpotStrategy = "SpotWithPriceLimit"
+)
+
+// PlacementStrategy is the strategy by which a deployment set places ECS instances.
+type PlacementStrategy string
+
+const (
+	// PlacementStrategySpread spreads the instances across physical hosts, so that a host failure affects few of them.
+	PlacementStrategySpread PlacementStrategy = "Spread"
+	// PlacementStrategyPack places the instances close to each other within one zone for a low network latency.
+	PlacementStrategyPack PlacementStrategy = "Pack"
+)
+
+// InstanceChargeType is the billing method of ECS instances.
+type InstanceChargeType string
+
+const (
+	// InstanceChargeTypePrePaid bills the instances by subscription. Subscribed instances cannot be released before
+	// the end of their subscription period.
+	InstanceChargeTypePrePaid InstanceChargeType = "PrePaid"
+	// InstanceChargeTypePostPaid bills the instances pay-as-you-go.
+	InstanceChargeTypePostPaid InstanceChargeType = "PostPaid"
+)
+
+// PeriodUnit is the unit of the subscription period of PrePaid instances.
+type PeriodUnit string
+
+const (
+	// PeriodUnitWeek specifies the subscription period in weeks.
+	PeriodUnitWeek PeriodUnit = "Week"
+	// PeriodUnitMonth specifies the subscription period in months.
+	PeriodUnitMonth PeriodUnit = "Month"
+)
+
+// SystemDisk contains configuration for the system disk of the worker nodes.
+type SystemDisk struct {
+	// Encrypted specifies whether the system disk is encrypted.
+	Encrypted bool
+	// KMSKeyID is the ID of the KMS key used to encrypt the system disk. If not set, the default service key is used.
+	KMSKeyID *string
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
 // WorkerStatus contains information about created worker resources.
 type WorkerStatus struct {
 	metav1.TypeMeta
@@ -40,4 +194,6 @@ type MachineImage struct {
 	Version string
 	// ID is the ID of the image.
 	ID string
+	// Encrypted specifies whether the image is an encrypted copy used for encrypted system disks.
+	Encrypted *bool
 }
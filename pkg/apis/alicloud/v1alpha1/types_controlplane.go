@@ -15,6 +15,7 @@
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -31,11 +32,118 @@ type ControlPlaneConfig struct {
 	// CloudControllerManager contains configuration settings for the cloud-controller-manager.
 	// +optional
 	CloudControllerManager *CloudControllerManagerConfig `json:"cloudControllerManager,omitempty"`
+
+	// LoadBalancerDefaults contains default settings for the load balancers of services of type LoadBalancer.
+	// +optional
+	LoadBalancerDefaults *LoadBalancerDefaults `json:"loadBalancerDefaults,omitempty"`
+
+	// CSI contains configuration settings for the CSI driver.
+	// +optional
+	CSI *CSIConfig `json:"csi,omitempty"`
+
+	// StorageClasses are additional storage classes which are managed in the shoot cluster next to the `default`
+	// storage class.
+	// +optional
+	StorageClasses []StorageClass `json:"storageClasses,omitempty"`
+
+	// RestrictMetadataServiceAccess specifies whether pods which are not in the host network are blocked from
+	// accessing the ECS metadata service. It must not be enabled if pods rely on the credentials of the RAM role of
+	// their node.
+	// +optional
+	RestrictMetadataServiceAccess bool `json:"restrictMetadataServiceAccess,omitempty"`
+
+	// APIServerLoadBalancer contains settings for the load balancer of the kube-apiserver of the shoot.
+	// +optional
+	APIServerLoadBalancer *APIServerLoadBalancer `json:"apiServerLoadBalancer,omitempty"`
 }
 
+// APIServerLoadBalancer contains settings for the load balancer of the kube-apiserver of the shoot.
+type APIServerLoadBalancer struct {
+	// AddressType is the address type of the SLB instance, either internet or intranet. If not set, an
+	// internet-facing SLB instance is created. An intranet SLB instance is only reachable from the VPC of the seed and
+	// networks connected to it.
+	// +optional
+	AddressType *LoadBalancerAddressType `json:"addressType,omitempty"`
+	// IdleTimeout is the time in seconds after which idle connections of the TCP listener are closed, between 10 and
+	// 900. Long-running watch requests of clients keep their connections only if they send data more often.
+	// +optional
+	IdleTimeout *int32 `json:"idleTimeout,omitempty"`
+	// ConnectionDrainTimeout is the time in seconds for which existing connections to a removed backend are kept,
+	// between 10 and 900. Connection draining is only enabled if it is set.
+	// +optional
+	ConnectionDrainTimeout *int32 `json:"connectionDrainTimeout,omitempty"`
+	// HealthCheckInterval is the interval in seconds between the health checks of the backends, between 1 and 50.
+	// +optional
+	HealthCheckInterval *int32 `json:"healthCheckInterval,omitempty"`
+	// AllowedCIDRs are the IPv4 CIDRs which may access the kube-apiserver. If set, all other clients are rejected by an
+	// access control list of the listener of the SLB instance.
+	// +optional
+	AllowedCIDRs []string `json:"allowedCIDRs,omitempty"`
+}
+
+// LoadBalancerAddressType is the address type of an SLB instance.
+type LoadBalancerAddressType string
+
+const (
+	// LoadBalancerAddressTypeInternet is the address type of SLB instances reachable from the internet.
+	LoadBalancerAddressTypeInternet LoadBalancerAddressType = "internet"
+	// LoadBalancerAddressTypeIntranet is the address type of SLB instances only reachable from within the VPC.
+	LoadBalancerAddressTypeIntranet LoadBalancerAddressType = "intranet"
+)
+
 // CloudControllerManagerConfig contains configuration settings for the cloud-controller-manager.
 type CloudControllerManagerConfig struct {
 	// FeatureGates contains information about enabled feature gates.
 	// +optional
 	FeatureGates map[string]bool `json:"featureGates,omitempty"`
+	// Image is the image reference of the cloud-controller-manager, e.g. of a copy in a mirror registry. It takes
+	// precedence over the image vector of the extension and must be pinned to a tag or digest.
+	// +optional
+	Image *string `json:"image,omitempty"`
+}
+
+// CSIConfig contains configuration settings for the CSI driver.
+type CSIConfig struct {
+	// ImmediateVolumeBinding specifies whether the disks of the managed storage classes are provisioned as soon as a
+	// PersistentVolumeClaim is created instead of in the zone of the first pod consuming it.
+	// +optional
+	ImmediateVolumeBinding bool `json:"immediateVolumeBinding,omitempty"`
+	// Images are the image references of the components of the CSI driver by their names in the image vector of the
+	// extension, i.e. csi-attacher, csi-node-driver-registrar, csi-plugin-alicloud, csi-provisioner, csi-resizer, and
+	// csi-snapshotter. They take precedence over the image vector and must be pinned to a tag or digest.
+	// +optional
+	Images map[string]string `json:"images,omitempty"`
+}
+
+// StorageClass contains configuration for an additional storage class of the Alicloud disk driver.
+type StorageClass struct {
+	// Name is the name of the storage class.
+	Name string `json:"name"`
+	// Default specifies whether the storage class is the default storage class of the shoot cluster instead of the
+	// `default` storage class.
+	// +optional
+	Default bool `json:"default,omitempty"`
+	// Type is the disk category of the volumes, e.g. cloud_efficiency, cloud_ssd, or cloud_essd. Defaults to cloud_ssd.
+	// +optional
+	Type *string `json:"type,omitempty"`
+	// PerformanceLevel is the performance level of ESSD volumes, one of PL0, PL1, PL2, or PL3.
+	// +optional
+	PerformanceLevel *string `json:"performanceLevel,omitempty"`
+	// ReclaimPolicy is the reclaim policy of the volumes, either Delete or Retain. Defaults to Delete.
+	// +optional
+	ReclaimPolicy *corev1.PersistentVolumeReclaimPolicy `json:"reclaimPolicy,omitempty"`
+}
+
+// LoadBalancerDefaults contains default settings for the load balancers of services of type LoadBalancer. They are
+// applied as annotations to all such services in the shoot cluster which do not already specify them.
+type LoadBalancerDefaults struct {
+	// Spec is the SLB instance specification, e.g. slb.s1.small.
+	// +optional
+	Spec *string `json:"spec,omitempty"`
+	// ChargeType is the charge type of the SLB instance, either paybytraffic or paybybandwidth.
+	// +optional
+	ChargeType *string `json:"chargeType,omitempty"`
+	// Bandwidth is the peak bandwidth of the SLB instance in Mbps.
+	// +optional
+	Bandwidth *int32 `json:"bandwidth,omitempty"`
 }
@@ -27,6 +27,10 @@ type InfrastructureConfig struct {
 
 	// Networks specifies the networks for an infrastructure.
 	Networks Networks `json:"networks"`
+
+	// Tags are additional tags which are applied to all Alicloud resources created for the infrastructure.
+	// +optional
+	Tags map[string]string `json:"tags,omitempty"`
 }
 
 // Networks specifies the networks for an infrastructure.
@@ -36,6 +40,175 @@ type Networks struct {
 
 	// Zones are the network zones for an infrastructure.
 	Zones []Zone `json:"zones"`
+
+	// DualStack contains the IPv6 dual-stack settings for an infrastructure.
+	// +optional
+	DualStack *DualStack `json:"dualStack,omitempty"`
+
+	// NatGateway contains settings for the NAT gateway of the VPC.
+	// +optional
+	NatGateway *NatGateway `json:"natGateway,omitempty"`
+
+	// SecurityGroupID is the ID of an existing security group of the VPC which is used for the nodes instead of
+	// creating one. The security group is not managed, i.e. neither its rules are changed nor it is deleted. It can
+	// only be used together with an existing VPC.
+	// +optional
+	SecurityGroupID *string `json:"securityGroupID,omitempty"`
+
+	// SecurityGroupRules are additional rules which are added to the security group of the nodes.
+	// +optional
+	SecurityGroupRules []SecurityGroupRule `json:"securityGroupRules,omitempty"`
+
+	// Routes are custom entries which are added to the route table of the VPC, or to the route table given by
+	// RouteTableID.
+	// +optional
+	Routes []Route `json:"routes,omitempty"`
+
+	// RouteTableID is the ID of an existing route table of the VPC which the vswitches are associated with instead of
+	// the system route table of the VPC. It can only be used together with an existing VPC.
+	// +optional
+	RouteTableID *string `json:"routeTableID,omitempty"`
+
+	// EnableFlowLogs specifies whether a flow log capturing the traffic of the VPC is created.
+	// +optional
+	EnableFlowLogs bool `json:"enableFlowLogs,omitempty"`
+
+	// FlowLogTarget is the Log Service (SLS) logstore which the flow log delivers to. It is required if flow logs are
+	// enabled.
+	// +optional
+	FlowLogTarget *FlowLogTarget `json:"flowLogTarget,omitempty"`
+
+	// DHCPOptions are the DHCP options of the VPC, e.g. custom DNS servers for the nodes. If set, a DHCP options set is
+	// created and associated with the VPC.
+	// +optional
+	DHCPOptions *DHCPOptions `json:"dhcpOptions,omitempty"`
+}
+
+// DHCPOptions are the DHCP options which the instances of the VPC receive.
+type DHCPOptions struct {
+	// DomainNameServers are the IP addresses of at most four DNS servers. If not set, the DNS servers of Alicloud are
+	// used.
+	// +optional
+	DomainNameServers []string `json:"domainNameServers,omitempty"`
+	// DomainName is the domain name which is appended to unqualified host names.
+	// +optional
+	DomainName *string `json:"domainName,omitempty"`
+}
+
+// FlowLogTarget is an existing Log Service (SLS) logstore. It is not managed by the extension.
+type FlowLogTarget struct {
+	// ProjectName is the name of the SLS project.
+	ProjectName string `json:"projectName"`
+	// LogStoreName is the name of the logstore in the SLS project.
+	LogStoreName string `json:"logStoreName"`
+}
+
+// SecurityGroupRuleDirection is the direction of a security group rule.
+type SecurityGroupRuleDirection string
+
+const (
+	// SecurityGroupRuleDirectionIngress is the direction of rules for inbound traffic.
+	SecurityGroupRuleDirectionIngress SecurityGroupRuleDirection = "ingress"
+	// SecurityGroupRuleDirectionEgress is the direction of rules for outbound traffic.
+	SecurityGroupRuleDirectionEgress SecurityGroupRuleDirection = "egress"
+)
+
+// SecurityGroupRule is a rule which accepts traffic in the security group of the nodes.
+type SecurityGroupRule struct {
+	// Direction is the direction of the traffic, either ingress or egress.
+	Direction SecurityGroupRuleDirection `json:"direction"`
+	// Protocol is the IP protocol of the traffic, one of tcp, udp, icmp, gre, or all.
+	Protocol string `json:"protocol"`
+	// PortRange is the range of ports in the format `<from>/<to>`, e.g. `22/22`. It must be `-1/-1` for
+	// protocols without ports.
+	PortRange string `json:"portRange"`
+	// CIDR is the source CIDR for ingress rules and the destination CIDR for egress rules.
+	CIDR string `json:"cidr"`
+}
+
+// RouteNextHopType is the type of the next hop of a route.
+type RouteNextHopType string
+
+const (
+	// RouteNextHopTypeInstance is the next hop type of routes to an ECS instance.
+	RouteNextHopTypeInstance RouteNextHopType = "Instance"
+	// RouteNextHopTypeHaVip is the next hop type of routes to a high-availability virtual IP address.
+	RouteNextHopTypeHaVip RouteNextHopType = "HaVip"
+	// RouteNextHopTypeNetworkInterface is the next hop type of routes to an elastic network interface.
+	RouteNextHopTypeNetworkInterface RouteNextHopType = "NetworkInterface"
+	// RouteNextHopTypeRouterInterface is the next hop type of routes to a router interface, e.g. of a VPC peering.
+	RouteNextHopTypeRouterInterface RouteNextHopType = "RouterInterface"
+	// RouteNextHopTypeVpnGateway is the next hop type of routes to a VPN gateway.
+	RouteNextHopTypeVpnGateway RouteNextHopType = "VpnGateway"
+)
+
+// Route is a custom entry of the route table of the VPC.
+type Route struct {
+	// DestinationCIDR is the destination CIDR of the route.
+	DestinationCIDR string `json:"destinationCIDR"`
+	// NextHopType is the type of the next hop, one of Instance, HaVip, NetworkInterface, RouterInterface, or VpnGateway.
+	NextHopType RouteNextHopType `json:"nextHopType"`
+	// NextHopID is the ID of the next hop.
+	NextHopID string `json:"nextHopID"`
+}
+
+// NatGateway contains information about the NAT gateway of the VPC.
+type NatGateway struct {
+	// ID is the ID of an existing NAT gateway in the VPC. It can only be used together with an existing VPC.
+	// +optional
+	ID *string `json:"id,omitempty"`
+	// EIPAllocation contains settings for the EIPs allocated for the NAT gateway.
+	// +optional
+	EIPAllocation *EIPAllocation `json:"eipAllocation,omitempty"`
+	// EIPAllocationID is the allocation ID of an existing EIP which is associated with the NAT gateway instead of
+	// allocating new EIPs. The EIP is owned by the user, i.e., it is unassociated but not released on deletion. It
+	// cannot be used together with NAT gateways per zone.
+	// +optional
+	EIPAllocationID *string `json:"eipAllocationID,omitempty"`
+	// PerZone specifies whether a NAT gateway is created in every zone instead of a single one for the VPC. The
+	// vswitches of a zone route their internet traffic through the NAT gateway of the zone. It can only be used
+	// together with a new VPC.
+	// +optional
+	PerZone bool `json:"perZone,omitempty"`
+	// DNATEntries are DNAT entries of the NAT gateway which forward inbound traffic to ports of its EIPs to internal
+	// IP addresses and ports, e.g. to node ports of the nodes. They are reconciled declaratively, entries which are
+	// removed are also deleted.
+	// +optional
+	DNATEntries []DNATEntry `json:"dnatEntries,omitempty"`
+}
+
+// DNATEntry is a DNAT entry of the NAT gateway.
+type DNATEntry struct {
+	// ExternalIP is the IP address of an EIP of the NAT gateway.
+	ExternalIP string `json:"externalIP"`
+	// ExternalPort is the port of the EIP which the traffic is received on.
+	ExternalPort int32 `json:"externalPort"`
+	// InternalIP is the private IP address in the VPC which the traffic is forwarded to.
+	InternalIP string `json:"internalIP"`
+	// InternalPort is the port which the traffic is forwarded to.
+	InternalPort int32 `json:"internalPort"`
+	// Protocol is the IP protocol of the traffic, either tcp or udp.
+	Protocol string `json:"protocol"`
+}
+
+// EIPAllocation contains settings for the EIPs allocated for the NAT gateway.
+type EIPAllocation struct {
+	// Bandwidth is the peak bandwidth of the EIPs in Mbps.
+	// +optional
+	Bandwidth *int32 `json:"bandwidth,omitempty"`
+	// InternetChargeType is the billing method of the EIPs, either PayByTraffic or PayByBandwidth.
+	// +optional
+	InternetChargeType *string `json:"internetChargeType,omitempty"`
+	// Count is the number of EIPs allocated for the NAT gateway of every zone, which are all used by its SNAT entries.
+	// More than one EIP can only be allocated for NAT gateways per zone. Defaults to 1.
+	// +optional
+	Count *int32 `json:"count,omitempty"`
+}
+
+// DualStack contains information about whether the VPC and its vswitches should get IPv6 CIDRs.
+type DualStack struct {
+	// Enabled specifies whether IPv6 should be enabled in addition to IPv4.
+	Enabled bool `json:"enabled"`
 }
 
 // VPC contains information about whether to create a new or use an existing VPC.
@@ -56,6 +229,12 @@ type VPCStatus struct {
 	VSwitches []VSwitch `json:"vswitches"`
 	// SecurityGroups is a list of security groups.
 	SecurityGroups []SecurityGroup `json:"securityGroups"`
+	// IPv6CIDR is the IPv6 CIDR allocated to the VPC if dual-stack is enabled.
+	// +optional
+	IPv6CIDR string `json:"ipv6CIDR,omitempty"`
+	// NatGateways is a list of the NAT gateways of the zones. It is only set if a NAT gateway is created per zone.
+	// +optional
+	NatGateways []NatGatewayStatus `json:"natGateways,omitempty"`
 }
 
 // Purpose is a purpose of a subnet.
@@ -66,6 +245,8 @@ const (
 	PurposeNodes Purpose = "nodes"
 	// PurposeInternal is a Purpose for internal use.
 	PurposeInternal Purpose = "internal"
+	// PurposePods is a Purpose for the secondary ENIs of pods.
+	PurposePods Purpose = "pods"
 )
 
 // VSwitch contains information about a vswitch.
@@ -76,6 +257,9 @@ type VSwitch struct {
 	ID string `json:"id"`
 	// Zone is the name of the zone.
 	Zone string `json:"zone"`
+	// IPv6CIDR is the IPv6 CIDR allocated to the vswitch if dual-stack is enabled.
+	// +optional
+	IPv6CIDR string `json:"ipv6CIDR,omitempty"`
 }
 
 // SecurityGroup contains information about a security group.
@@ -86,6 +270,36 @@ type SecurityGroup struct {
 	ID string `json:"id"`
 }
 
+// ZoneStatus contains the availability zone which a zone of the infrastructure is resolved to.
+type ZoneStatus struct {
+	// Name is the name of the zone in the InfrastructureConfig.
+	Name string `json:"name"`
+	// ZoneID is the ID of the availability zone which Alicloud reports for the vswitches of the zone.
+	ZoneID string `json:"zoneID"`
+}
+
+// NatGatewayStatus contains information about the NAT gateway of a zone.
+type NatGatewayStatus struct {
+	// ID is the id of the NAT gateway.
+	ID string `json:"id"`
+	// Zone is the name of the zone.
+	Zone string `json:"zone"`
+	// VSwitchID is the id of the dedicated vswitch of the NAT gateway if the zone has one.
+	// +optional
+	VSwitchID string `json:"vswitchID,omitempty"`
+	// EIPs is a list of the EIPs allocated for the NAT gateway.
+	// +optional
+	EIPs []EIPStatus `json:"eips,omitempty"`
+}
+
+// EIPStatus contains information about an EIP allocated for a NAT gateway.
+type EIPStatus struct {
+	// AllocationID is the allocation id of the EIP.
+	AllocationID string `json:"allocationID"`
+	// IPAddress is the public IP address of the EIP.
+	IPAddress string `json:"ipAddress"`
+}
+
 // Zone is a zone with a name and worker CIDR.
 type Zone struct {
 	// Name is the name of a zone.
@@ -95,6 +309,18 @@ type Zone struct {
 	Worker string `json:"worker"`
 	// Workers specifies the worker CIDR to use.
 	Workers string `json:"workers"`
+	// AdditionalWorkers specifies additional worker CIDRs. For every CIDR an additional vswitch is created in the zone.
+	// +optional
+	AdditionalWorkers []string `json:"additionalWorkers,omitempty"`
+	// NatGatewayCIDR specifies the CIDR of a dedicated vswitch for the NAT gateway of the zone. It can only be used
+	// together with NAT gateways per zone. If it is not set then the NAT gateway is placed in the first worker vswitch.
+	// +optional
+	NatGatewayCIDR *string `json:"natGatewayCIDR,omitempty"`
+	// PodsCIDR specifies the CIDR of a dedicated vswitch for the secondary ENIs of the zone's instances. Pods get their
+	// IP addresses from this vswitch if the Terway network plugin runs in ENI mode. It must either be set for all zones
+	// or for none.
+	// +optional
+	PodsCIDR *string `json:"podsCIDR,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -113,4 +339,40 @@ type InfrastructureStatus struct {
 	// the used versions in the provider status to ensure reconciliation is possible.
 	// +optional
 	MachineImages []MachineImage `json:"machineImages,omitempty"`
+
+	// Zones contains the availability zones which the zones of the infrastructure are resolved to.
+	// +optional
+	Zones []ZoneStatus `json:"zones,omitempty"`
+
+	// Plan contains the changes which a reconciliation would apply to the infrastructure. It is only set if the
+	// Infrastructure is reconciled in dry-run mode, in which case no infrastructure resources are changed.
+	// +optional
+	Plan *InfrastructurePlan `json:"plan,omitempty"`
+}
+
+// InfrastructurePlan contains the changes which a reconciliation would apply to the infrastructure.
+type InfrastructurePlan struct {
+	// Changes is the list of changes to the infrastructure resources.
+	// +optional
+	Changes []InfrastructureChange `json:"changes,omitempty"`
+}
+
+// InfrastructureChangeAction is the action of an infrastructure change.
+type InfrastructureChangeAction string
+
+const (
+	// InfrastructureChangeActionCreate is the action of resources which would be created.
+	InfrastructureChangeActionCreate InfrastructureChangeAction = "Create"
+	// InfrastructureChangeActionUpdate is the action of resources which would be updated.
+	InfrastructureChangeActionUpdate InfrastructureChangeAction = "Update"
+	// InfrastructureChangeActionDelete is the action of resources which would be deleted.
+	InfrastructureChangeActionDelete InfrastructureChangeAction = "Delete"
+)
+
+// InfrastructureChange is a change to an infrastructure resource.
+type InfrastructureChange struct {
+	// Action is the action which would be applied to the resource.
+	Action InfrastructureChangeAction `json:"action"`
+	// Resource describes the affected resource.
+	Resource string `json:"resource"`
 }
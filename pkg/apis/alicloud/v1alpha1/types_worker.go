@@ -16,11 +16,198 @@ package v1alpha1
 
 import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // +genclient
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
+// WorkerConfig contains configuration settings for the worker nodes.
+type WorkerConfig struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// ImageID is the ID of a custom image which is used for the worker nodes instead of the machine image of the
+	// worker pool.
+	// +optional
+	ImageID *string `json:"imageID,omitempty"`
+	// SystemDisk contains configuration for the system disk of the worker nodes.
+	// +optional
+	SystemDisk *SystemDisk `json:"systemDisk,omitempty"`
+	// DataVolumes contains configuration for additional data disks of the worker nodes.
+	// +optional
+	DataVolumes []DataVolume `json:"dataVolumes,omitempty"`
+	// Tags are additional tags which are applied to the ECS instances of the worker pool.
+	// +optional
+	Tags map[string]string `json:"tags,omitempty"`
+	// SpotStrategy is the spot strategy of the ECS instances of the worker pool. If not set, no spot instances are used.
+	// +optional
+	SpotStrategy *SpotStrategy `json:"spotStrategy,omitempty"`
+	// SpotPriceLimit is the maximum hourly price of a spot instance. It is required for the SpotWithPriceLimit strategy.
+	// +optional
+	SpotPriceLimit *string `json:"spotPriceLimit,omitempty"`
+	// DeploymentSetID is the ID of a deployment set which the ECS instances of the worker pool join, i.e., their
+	// placement group. It cannot be used together with spot instances.
+	// +optional
+	DeploymentSetID *string `json:"deploymentSetID,omitempty"`
+	// PlacementStrategy is the strategy of the deployment set given by DeploymentSetID, i.e., whether it spreads the
+	// ECS instances of the worker pool across physical hosts for availability (Spread) or packs them close to each
+	// other for a low network latency (Pack). If set, the strategy of the deployment set is checked against it.
+	// +optional
+	PlacementStrategy *PlacementStrategy `json:"placementStrategy,omitempty"`
+	// SecurityGroupIDs are the IDs of existing security groups of the VPC of the shoot which are attached to the ECS
+	// instances of the worker pool in addition to the security group managed by Gardener.
+	// +optional
+	SecurityGroupIDs []string `json:"securityGroupIDs,omitempty"`
+	// RAMRoleName is the name of a RAM role which is attached to the ECS instances of the worker pool, so that
+	// workloads can obtain temporary credentials of the role from the instance metadata.
+	// +optional
+	RAMRoleName *string `json:"ramRoleName,omitempty"`
+	// UseLocalDisk specifies whether the local NVMe disks of the ECS instances of the worker pool are formatted and
+	// mounted below /mnt/local-disks. It is only supported for instance families with local NVMe disks.
+	// +optional
+	UseLocalDisk bool `json:"useLocalDisk,omitempty"`
+	// GPUDriverVersion is the version of the NVIDIA driver which is installed on the ECS instances of the worker pool.
+	// It must only be set for GPU instance types, if not set a default version is installed on them.
+	// +optional
+	GPUDriverVersion *string `json:"gpuDriverVersion,omitempty"`
+	// SecondaryENIs specifies whether the ECS instances of the worker pool get secondary elastic network interfaces
+	// from the pods vswitches of their zones, e.g. for the Terway network plugin in ENI mode. It requires pods
+	// vswitches in the InfrastructureConfig.
+	// +optional
+	SecondaryENIs bool `json:"secondaryENIs,omitempty"`
+	// UserData is an additional cloud-config or shell script which cloud-init runs on the ECS instances of the worker
+	// pool before the user data generated by Gardener, e.g. to configure registry mirrors before the kubelet starts.
+	// +optional
+	UserData *string `json:"userData,omitempty"`
+	// InstanceChargeType is the billing method of the ECS instances of the worker pool. If not set, PostPaid
+	// (pay-as-you-go) is used.
+	// +optional
+	InstanceChargeType *InstanceChargeType `json:"instanceChargeType,omitempty"`
+	// Period is the subscription period of PrePaid ECS instances in units of the period unit. It must only be set for
+	// PrePaid instances, if not set a period of one month is used.
+	// +optional
+	Period *int32 `json:"period,omitempty"`
+	// PeriodUnit is the unit of the subscription period, either Week or Month. It must only be set for PrePaid
+	// instances, if not set Month is used.
+	// +optional
+	PeriodUnit *PeriodUnit `json:"periodUnit,omitempty"`
+	// AutoRenew specifies whether the subscription of PrePaid ECS instances is renewed automatically when it ends. It
+	// must only be set for PrePaid instances.
+	// +optional
+	AutoRenew bool `json:"autoRenew,omitempty"`
+	// AutoRenewPeriod is the period of the automatic renewals in units of the period unit. It must only be set if
+	// AutoRenew is enabled, if not set the subscription is renewed for one period unit.
+	// +optional
+	AutoRenewPeriod *int32 `json:"autoRenewPeriod,omitempty"`
+	// DrainTimeout is the maximum duration for which the nodes of the worker pool are drained before their machines
+	// are deleted, if not set the default drain timeout of the machine-controller-manager is used.
+	// +optional
+	DrainTimeout *metav1.Duration `json:"drainTimeout,omitempty"`
+	// MaxSurge is the maximum number of machines of the worker pool which are created in addition to the desired
+	// number during a rolling update, as number or percentage. If set, it overwrites the max surge of the worker pool.
+	// +optional
+	MaxSurge *intstr.IntOrString `json:"maxSurge,omitempty"`
+	// MaxUnavailable is the maximum number of machines of the worker pool which may be unavailable during a rolling
+	// update, as number or percentage. If set, it overwrites the max unavailable of the worker pool.
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+	// KubeletConfig contains kubelet settings of the worker pool. They are passed as command line arguments to the
+	// kubelets of its nodes and must not be set in the kubelet configuration of the shoot as well.
+	// +optional
+	KubeletConfig *KubeletConfig `json:"kubeletConfig,omitempty"`
+}
+
+// KubeletConfig contains kubelet settings of a worker pool.
+type KubeletConfig struct {
+	// MaxPods is the maximum number of pods on a node.
+	// +optional
+	MaxPods *int32 `json:"maxPods,omitempty"`
+	// EvictionHard are the hard eviction thresholds by eviction signal, e.g. memory.available: 500Mi or
+	// nodefs.available: 10%.
+	// +optional
+	EvictionHard map[string]string `json:"evictionHard,omitempty"`
+	// EvictionSoft are the soft eviction thresholds by eviction signal. Every soft eviction threshold requires a grace
+	// period.
+	// +optional
+	EvictionSoft map[string]string `json:"evictionSoft,omitempty"`
+	// EvictionSoftGracePeriod are the grace periods of the soft eviction thresholds by eviction signal, e.g.
+	// memory.available: 1m30s.
+	// +optional
+	EvictionSoftGracePeriod map[string]string `json:"evictionSoftGracePeriod,omitempty"`
+	// KubeReserved are the resources reserved for the Kubernetes components of a node by resource name, e.g.
+	// memory: 1Gi.
+	// +optional
+	KubeReserved map[string]string `json:"kubeReserved,omitempty"`
+}
+
+// DataVolume contains configuration for an additional data disk of the worker nodes.
+type DataVolume struct {
+	// Name is the name of the data volume.
+	Name string `json:"name"`
+	// Type is the disk category of the data volume, e.g. cloud_efficiency, cloud_ssd, or cloud_essd.
+	// +optional
+	Type *string `json:"type,omitempty"`
+	// Size is the size of the data volume, e.g. 100Gi.
+	Size string `json:"size"`
+	// PerformanceLevel is the performance level of an ESSD data volume, one of PL0, PL1, PL2, or PL3.
+	// +optional
+	PerformanceLevel *string `json:"performanceLevel,omitempty"`
+}
+
+// SpotStrategy is the strategy for spot instances.
+type SpotStrategy string
+
+const (
+	// SpotStrategyAsPriceGo uses spot instances whose price is the current market price.
+	SpotStrategyAsPriceGo SpotStrategy = "SpotAsPriceGo"
+	// SpotStrategyWithPriceLimit uses spot instances whose price is at most the spot price limit.
+	SpotStrategyWithPriceLimit SpotStrategy = "SpotWithPriceLimit"
+)
+
+// PlacementStrategy is the strategy by which a deployment set places ECS instances.
+type PlacementStrategy string
+
+const (
+	// PlacementStrategySpread spreads the instances across physical hosts, so that a host failure affects few of them.
+	PlacementStrategySpread PlacementStrategy = "Spread"
+	// PlacementStrategyPack places the instances close to each other within one zone for a low network latency.
+	PlacementStrategyPack PlacementStrategy = "Pack"
+)
+
+// InstanceChargeType is the billing method of ECS instances.
+type InstanceChargeType string
+
+const (
+	// InstanceChargeTypePrePaid bills the instances by subscription. Subscribed instances cannot be released before
+	// the end of their subscription period.
+	InstanceChargeTypePrePaid InstanceChargeType = "PrePaid"
+	// InstanceChargeTypePostPaid bills the instances pay-as-you-go.
+	InstanceChargeTypePostPaid InstanceChargeType = "PostPaid"
+)
+
+// PeriodUnit is the unit of the subscription period of PrePaid instances.
+type PeriodUnit string
+
+const (
+	// PeriodUnitWeek specifies the subscription period in weeks.
+	PeriodUnitWeek PeriodUnit = "Week"
+	// PeriodUnitMonth specifies the subscription period in months.
+	PeriodUnitMonth PeriodUnit = "Month"
+)
+
+// SystemDisk contains configuration for the system disk of the worker nodes.
+type SystemDisk struct {
+	// Encrypted specifies whether the system disk is encrypted.
+	// +optional
+	Encrypted bool `json:"encrypted,omitempty"`
+	// KMSKeyID is the ID of the KMS key used to encrypt the system disk. If not set, the default service key is used.
+	// +optional
+	KMSKeyID *string `json:"kmsKeyID,omitempty"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
 // WorkerStatus contains information about created worker resources.
 type WorkerStatus struct {
 	metav1.TypeMeta `json:",inline"`
@@ -42,4 +229,7 @@ type MachineImage struct {
 	Version string `json:"version"`
 	// ID is the id of the image.
 	ID string `json:"id"`
+	// Encrypted specifies whether the image is an encrypted copy used for encrypted system disks.
+	// +optional
+	Encrypted *bool `json:"encrypted,omitempty"`
 }
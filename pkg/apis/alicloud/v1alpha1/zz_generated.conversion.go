@@ -24,8 +24,11 @@ import (
 	unsafe "unsafe"
 
 	alicloud "github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/alicloud"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	conversion "k8s.io/apimachinery/pkg/conversion"
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	intstr "k8s.io/apimachinery/pkg/util/intstr"
 )
 
 func init() {
@@ -35,6 +38,26 @@ func init() {
 // RegisterConversions adds conversion functions to the given scheme.
 // Public to allow building arbitrary schemes.
 func RegisterConversions(s *runtime.Scheme) error {
+	if err := s.AddGeneratedConversionFunc((*APIServerLoadBalancer)(nil), (*alicloud.APIServerLoadBalancer)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_APIServerLoadBalancer_To_alicloud_APIServerLoadBalancer(a.(*APIServerLoadBalancer), b.(*alicloud.APIServerLoadBalancer), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*alicloud.APIServerLoadBalancer)(nil), (*APIServerLoadBalancer)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_alicloud_APIServerLoadBalancer_To_v1alpha1_APIServerLoadBalancer(a.(*alicloud.APIServerLoadBalancer), b.(*APIServerLoadBalancer), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*CSIConfig)(nil), (*alicloud.CSIConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_CSIConfig_To_alicloud_CSIConfig(a.(*CSIConfig), b.(*alicloud.CSIConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*alicloud.CSIConfig)(nil), (*CSIConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_alicloud_CSIConfig_To_v1alpha1_CSIConfig(a.(*alicloud.CSIConfig), b.(*CSIConfig), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*CloudControllerManagerConfig)(nil), (*alicloud.CloudControllerManagerConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_CloudControllerManagerConfig_To_alicloud_CloudControllerManagerConfig(a.(*CloudControllerManagerConfig), b.(*alicloud.CloudControllerManagerConfig), scope)
 	}); err != nil {
@@ -65,6 +88,96 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*DHCPOptions)(nil), (*alicloud.DHCPOptions)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_DHCPOptions_To_alicloud_DHCPOptions(a.(*DHCPOptions), b.(*alicloud.DHCPOptions), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*alicloud.DHCPOptions)(nil), (*DHCPOptions)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_alicloud_DHCPOptions_To_v1alpha1_DHCPOptions(a.(*alicloud.DHCPOptions), b.(*DHCPOptions), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*DNATEntry)(nil), (*alicloud.DNATEntry)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_DNATEntry_To_alicloud_DNATEntry(a.(*DNATEntry), b.(*alicloud.DNATEntry), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*alicloud.DNATEntry)(nil), (*DNATEntry)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_alicloud_DNATEntry_To_v1alpha1_DNATEntry(a.(*alicloud.DNATEntry), b.(*DNATEntry), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*DataVolume)(nil), (*alicloud.DataVolume)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_DataVolume_To_alicloud_DataVolume(a.(*DataVolume), b.(*alicloud.DataVolume), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*alicloud.DataVolume)(nil), (*DataVolume)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_alicloud_DataVolume_To_v1alpha1_DataVolume(a.(*alicloud.DataVolume), b.(*DataVolume), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*DefaultMachineImage)(nil), (*alicloud.DefaultMachineImage)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_DefaultMachineImage_To_alicloud_DefaultMachineImage(a.(*DefaultMachineImage), b.(*alicloud.DefaultMachineImage), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*alicloud.DefaultMachineImage)(nil), (*DefaultMachineImage)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_alicloud_DefaultMachineImage_To_v1alpha1_DefaultMachineImage(a.(*alicloud.DefaultMachineImage), b.(*DefaultMachineImage), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*DualStack)(nil), (*alicloud.DualStack)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_DualStack_To_alicloud_DualStack(a.(*DualStack), b.(*alicloud.DualStack), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*alicloud.DualStack)(nil), (*DualStack)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_alicloud_DualStack_To_v1alpha1_DualStack(a.(*alicloud.DualStack), b.(*DualStack), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*EIPAllocation)(nil), (*alicloud.EIPAllocation)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_EIPAllocation_To_alicloud_EIPAllocation(a.(*EIPAllocation), b.(*alicloud.EIPAllocation), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*alicloud.EIPAllocation)(nil), (*EIPAllocation)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_alicloud_EIPAllocation_To_v1alpha1_EIPAllocation(a.(*alicloud.EIPAllocation), b.(*EIPAllocation), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*EIPStatus)(nil), (*alicloud.EIPStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_EIPStatus_To_alicloud_EIPStatus(a.(*EIPStatus), b.(*alicloud.EIPStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*alicloud.EIPStatus)(nil), (*EIPStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_alicloud_EIPStatus_To_v1alpha1_EIPStatus(a.(*alicloud.EIPStatus), b.(*EIPStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*FlowLogTarget)(nil), (*alicloud.FlowLogTarget)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_FlowLogTarget_To_alicloud_FlowLogTarget(a.(*FlowLogTarget), b.(*alicloud.FlowLogTarget), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*alicloud.FlowLogTarget)(nil), (*FlowLogTarget)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_alicloud_FlowLogTarget_To_v1alpha1_FlowLogTarget(a.(*alicloud.FlowLogTarget), b.(*FlowLogTarget), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*InfrastructureChange)(nil), (*alicloud.InfrastructureChange)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_InfrastructureChange_To_alicloud_InfrastructureChange(a.(*InfrastructureChange), b.(*alicloud.InfrastructureChange), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*alicloud.InfrastructureChange)(nil), (*InfrastructureChange)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_alicloud_InfrastructureChange_To_v1alpha1_InfrastructureChange(a.(*alicloud.InfrastructureChange), b.(*InfrastructureChange), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*InfrastructureConfig)(nil), (*alicloud.InfrastructureConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_InfrastructureConfig_To_alicloud_InfrastructureConfig(a.(*InfrastructureConfig), b.(*alicloud.InfrastructureConfig), scope)
 	}); err != nil {
@@ -75,6 +188,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*InfrastructurePlan)(nil), (*alicloud.InfrastructurePlan)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_InfrastructurePlan_To_alicloud_InfrastructurePlan(a.(*InfrastructurePlan), b.(*alicloud.InfrastructurePlan), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*alicloud.InfrastructurePlan)(nil), (*InfrastructurePlan)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_alicloud_InfrastructurePlan_To_v1alpha1_InfrastructurePlan(a.(*alicloud.InfrastructurePlan), b.(*InfrastructurePlan), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*InfrastructureStatus)(nil), (*alicloud.InfrastructureStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_InfrastructureStatus_To_alicloud_InfrastructureStatus(a.(*InfrastructureStatus), b.(*alicloud.InfrastructureStatus), scope)
 	}); err != nil {
@@ -85,6 +208,26 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*KubeletConfig)(nil), (*alicloud.KubeletConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_KubeletConfig_To_alicloud_KubeletConfig(a.(*KubeletConfig), b.(*alicloud.KubeletConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*alicloud.KubeletConfig)(nil), (*KubeletConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_alicloud_KubeletConfig_To_v1alpha1_KubeletConfig(a.(*alicloud.KubeletConfig), b.(*KubeletConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*LoadBalancerDefaults)(nil), (*alicloud.LoadBalancerDefaults)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_LoadBalancerDefaults_To_alicloud_LoadBalancerDefaults(a.(*LoadBalancerDefaults), b.(*alicloud.LoadBalancerDefaults), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*alicloud.LoadBalancerDefaults)(nil), (*LoadBalancerDefaults)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_alicloud_LoadBalancerDefaults_To_v1alpha1_LoadBalancerDefaults(a.(*alicloud.LoadBalancerDefaults), b.(*LoadBalancerDefaults), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*MachineImage)(nil), (*alicloud.MachineImage)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_MachineImage_To_alicloud_MachineImage(a.(*MachineImage), b.(*alicloud.MachineImage), scope)
 	}); err != nil {
@@ -115,6 +258,26 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*NatGateway)(nil), (*alicloud.NatGateway)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_NatGateway_To_alicloud_NatGateway(a.(*NatGateway), b.(*alicloud.NatGateway), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*alicloud.NatGateway)(nil), (*NatGateway)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_alicloud_NatGateway_To_v1alpha1_NatGateway(a.(*alicloud.NatGateway), b.(*NatGateway), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*NatGatewayStatus)(nil), (*alicloud.NatGatewayStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_NatGatewayStatus_To_alicloud_NatGatewayStatus(a.(*NatGatewayStatus), b.(*alicloud.NatGatewayStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*alicloud.NatGatewayStatus)(nil), (*NatGatewayStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_alicloud_NatGatewayStatus_To_v1alpha1_NatGatewayStatus(a.(*alicloud.NatGatewayStatus), b.(*NatGatewayStatus), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*Networks)(nil), (*alicloud.Networks)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_Networks_To_alicloud_Networks(a.(*Networks), b.(*alicloud.Networks), scope)
 	}); err != nil {
@@ -135,6 +298,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*Route)(nil), (*alicloud.Route)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_Route_To_alicloud_Route(a.(*Route), b.(*alicloud.Route), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*alicloud.Route)(nil), (*Route)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_alicloud_Route_To_v1alpha1_Route(a.(*alicloud.Route), b.(*Route), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*SecurityGroup)(nil), (*alicloud.SecurityGroup)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_SecurityGroup_To_alicloud_SecurityGroup(a.(*SecurityGroup), b.(*alicloud.SecurityGroup), scope)
 	}); err != nil {
@@ -145,6 +318,36 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*SecurityGroupRule)(nil), (*alicloud.SecurityGroupRule)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_SecurityGroupRule_To_alicloud_SecurityGroupRule(a.(*SecurityGroupRule), b.(*alicloud.SecurityGroupRule), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*alicloud.SecurityGroupRule)(nil), (*SecurityGroupRule)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_alicloud_SecurityGroupRule_To_v1alpha1_SecurityGroupRule(a.(*alicloud.SecurityGroupRule), b.(*SecurityGroupRule), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*StorageClass)(nil), (*alicloud.StorageClass)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_StorageClass_To_alicloud_StorageClass(a.(*StorageClass), b.(*alicloud.StorageClass), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*alicloud.StorageClass)(nil), (*StorageClass)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_alicloud_StorageClass_To_v1alpha1_StorageClass(a.(*alicloud.StorageClass), b.(*StorageClass), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*SystemDisk)(nil), (*alicloud.SystemDisk)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_SystemDisk_To_alicloud_SystemDisk(a.(*SystemDisk), b.(*alicloud.SystemDisk), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*alicloud.SystemDisk)(nil), (*SystemDisk)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_alicloud_SystemDisk_To_v1alpha1_SystemDisk(a.(*alicloud.SystemDisk), b.(*SystemDisk), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*VPC)(nil), (*alicloud.VPC)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_VPC_To_alicloud_VPC(a.(*VPC), b.(*alicloud.VPC), scope)
 	}); err != nil {
@@ -175,6 +378,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*WorkerConfig)(nil), (*alicloud.WorkerConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_WorkerConfig_To_alicloud_WorkerConfig(a.(*WorkerConfig), b.(*alicloud.WorkerConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*alicloud.WorkerConfig)(nil), (*WorkerConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_alicloud_WorkerConfig_To_v1alpha1_WorkerConfig(a.(*alicloud.WorkerConfig), b.(*WorkerConfig), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*WorkerStatus)(nil), (*alicloud.WorkerStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_WorkerStatus_To_alicloud_WorkerStatus(a.(*WorkerStatus), b.(*alicloud.WorkerStatus), scope)
 	}); err != nil {
@@ -195,11 +408,72 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*ZoneStatus)(nil), (*alicloud.ZoneStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_ZoneStatus_To_alicloud_ZoneStatus(a.(*ZoneStatus), b.(*alicloud.ZoneStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*alicloud.ZoneStatus)(nil), (*ZoneStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_alicloud_ZoneStatus_To_v1alpha1_ZoneStatus(a.(*alicloud.ZoneStatus), b.(*ZoneStatus), scope)
+	}); err != nil {
+		return err
+	}
 	return nil
 }
 
+func autoConvert_v1alpha1_APIServerLoadBalancer_To_alicloud_APIServerLoadBalancer(in *APIServerLoadBalancer, out *alicloud.APIServerLoadBalancer, s conversion.Scope) error {
+	out.AddressType = (*alicloud.LoadBalancerAddressType)(unsafe.Pointer(in.AddressType))
+	out.IdleTimeout = (*int32)(unsafe.Pointer(in.IdleTimeout))
+	out.ConnectionDrainTimeout = (*int32)(unsafe.Pointer(in.ConnectionDrainTimeout))
+	out.HealthCheckInterval = (*int32)(unsafe.Pointer(in.HealthCheckInterval))
+	out.AllowedCIDRs = *(*[]string)(unsafe.Pointer(&in.AllowedCIDRs))
+	return nil
+}
+
+// Convert_v1alpha1_APIServerLoadBalancer_To_alicloud_APIServerLoadBalancer is an autogenerated conversion function.
+func Convert_v1alpha1_APIServerLoadBalancer_To_alicloud_APIServerLoadBalancer(in *APIServerLoadBalancer, out *alicloud.APIServerLoadBalancer, s conversion.Scope) error {
+	return autoConvert_v1alpha1_APIServerLoadBalancer_To_alicloud_APIServerLoadBalancer(in, out, s)
+}
+
+func autoConvert_alicloud_APIServerLoadBalancer_To_v1alpha1_APIServerLoadBalancer(in *alicloud.APIServerLoadBalancer, out *APIServerLoadBalancer, s conversion.Scope) error {
+	out.AddressType = (*LoadBalancerAddressType)(unsafe.Pointer(in.AddressType))
+	out.IdleTimeout = (*int32)(unsafe.Pointer(in.IdleTimeout))
+	out.ConnectionDrainTimeout = (*int32)(unsafe.Pointer(in.ConnectionDrainTimeout))
+	out.HealthCheckInterval = (*int32)(unsafe.Pointer(in.HealthCheckInterval))
+	out.AllowedCIDRs = *(*[]string)(unsafe.Pointer(&in.AllowedCIDRs))
+	return nil
+}
+
+// Convert_alicloud_APIServerLoadBalancer_To_v1alpha1_APIServerLoadBalancer is an autogenerated conversion function.
+func Convert_alicloud_APIServerLoadBalancer_To_v1alpha1_APIServerLoadBalancer(in *alicloud.APIServerLoadBalancer, out *APIServerLoadBalancer, s conversion.Scope) error {
+	return autoConvert_alicloud_APIServerLoadBalancer_To_v1alpha1_APIServerLoadBalancer(in, out, s)
+}
+
+func autoConvert_v1alpha1_CSIConfig_To_alicloud_CSIConfig(in *CSIConfig, out *alicloud.CSIConfig, s conversion.Scope) error {
+	out.ImmediateVolumeBinding = in.ImmediateVolumeBinding
+	out.Images = *(*map[string]string)(unsafe.Pointer(&in.Images))
+	return nil
+}
+
+// Convert_v1alpha1_CSIConfig_To_alicloud_CSIConfig is an autogenerated conversion function.
+func Convert_v1alpha1_CSIConfig_To_alicloud_CSIConfig(in *CSIConfig, out *alicloud.CSIConfig, s conversion.Scope) error {
+	return autoConvert_v1alpha1_CSIConfig_To_alicloud_CSIConfig(in, out, s)
+}
+
+func autoConvert_alicloud_CSIConfig_To_v1alpha1_CSIConfig(in *alicloud.CSIConfig, out *CSIConfig, s conversion.Scope) error {
+	out.ImmediateVolumeBinding = in.ImmediateVolumeBinding
+	out.Images = *(*map[string]string)(unsafe.Pointer(&in.Images))
+	return nil
+}
+
+// Convert_alicloud_CSIConfig_To_v1alpha1_CSIConfig is an autogenerated conversion function.
+func Convert_alicloud_CSIConfig_To_v1alpha1_CSIConfig(in *alicloud.CSIConfig, out *CSIConfig, s conversion.Scope) error {
+	return autoConvert_alicloud_CSIConfig_To_v1alpha1_CSIConfig(in, out, s)
+}
+
 func autoConvert_v1alpha1_CloudControllerManagerConfig_To_alicloud_CloudControllerManagerConfig(in *CloudControllerManagerConfig, out *alicloud.CloudControllerManagerConfig, s conversion.Scope) error {
 	out.FeatureGates = *(*map[string]bool)(unsafe.Pointer(&in.FeatureGates))
+	out.Image = (*string)(unsafe.Pointer(in.Image))
 	return nil
 }
 
@@ -210,6 +484,7 @@ func Convert_v1alpha1_CloudControllerManagerConfig_To_alicloud_CloudControllerMa
 
 func autoConvert_alicloud_CloudControllerManagerConfig_To_v1alpha1_CloudControllerManagerConfig(in *alicloud.CloudControllerManagerConfig, out *CloudControllerManagerConfig, s conversion.Scope) error {
 	out.FeatureGates = *(*map[string]bool)(unsafe.Pointer(&in.FeatureGates))
+	out.Image = (*string)(unsafe.Pointer(in.Image))
 	return nil
 }
 
@@ -220,6 +495,8 @@ func Convert_alicloud_CloudControllerManagerConfig_To_v1alpha1_CloudControllerMa
 
 func autoConvert_v1alpha1_CloudProfileConfig_To_alicloud_CloudProfileConfig(in *CloudProfileConfig, out *alicloud.CloudProfileConfig, s conversion.Scope) error {
 	out.MachineImages = *(*[]alicloud.MachineImages)(unsafe.Pointer(&in.MachineImages))
+	out.DeprecatedMachineImagePolicy = (*alicloud.DeprecatedMachineImagePolicy)(unsafe.Pointer(in.DeprecatedMachineImagePolicy))
+	out.DefaultMachineImages = *(*[]alicloud.DefaultMachineImage)(unsafe.Pointer(&in.DefaultMachineImages))
 	return nil
 }
 
@@ -230,6 +507,8 @@ func Convert_v1alpha1_CloudProfileConfig_To_alicloud_CloudProfileConfig(in *Clou
 
 func autoConvert_alicloud_CloudProfileConfig_To_v1alpha1_CloudProfileConfig(in *alicloud.CloudProfileConfig, out *CloudProfileConfig, s conversion.Scope) error {
 	out.MachineImages = *(*[]MachineImages)(unsafe.Pointer(&in.MachineImages))
+	out.DeprecatedMachineImagePolicy = (*DeprecatedMachineImagePolicy)(unsafe.Pointer(in.DeprecatedMachineImagePolicy))
+	out.DefaultMachineImages = *(*[]DefaultMachineImage)(unsafe.Pointer(&in.DefaultMachineImages))
 	return nil
 }
 
@@ -241,6 +520,11 @@ func Convert_alicloud_CloudProfileConfig_To_v1alpha1_CloudProfileConfig(in *alic
 func autoConvert_v1alpha1_ControlPlaneConfig_To_alicloud_ControlPlaneConfig(in *ControlPlaneConfig, out *alicloud.ControlPlaneConfig, s conversion.Scope) error {
 	out.Zone = in.Zone
 	out.CloudControllerManager = (*alicloud.CloudControllerManagerConfig)(unsafe.Pointer(in.CloudControllerManager))
+	out.LoadBalancerDefaults = (*alicloud.LoadBalancerDefaults)(unsafe.Pointer(in.LoadBalancerDefaults))
+	out.CSI = (*alicloud.CSIConfig)(unsafe.Pointer(in.CSI))
+	out.StorageClasses = *(*[]alicloud.StorageClass)(unsafe.Pointer(&in.StorageClasses))
+	out.RestrictMetadataServiceAccess = in.RestrictMetadataServiceAccess
+	out.APIServerLoadBalancer = (*alicloud.APIServerLoadBalancer)(unsafe.Pointer(in.APIServerLoadBalancer))
 	return nil
 }
 
@@ -252,6 +536,11 @@ func Convert_v1alpha1_ControlPlaneConfig_To_alicloud_ControlPlaneConfig(in *Cont
 func autoConvert_alicloud_ControlPlaneConfig_To_v1alpha1_ControlPlaneConfig(in *alicloud.ControlPlaneConfig, out *ControlPlaneConfig, s conversion.Scope) error {
 	out.Zone = in.Zone
 	out.CloudControllerManager = (*CloudControllerManagerConfig)(unsafe.Pointer(in.CloudControllerManager))
+	out.LoadBalancerDefaults = (*LoadBalancerDefaults)(unsafe.Pointer(in.LoadBalancerDefaults))
+	out.CSI = (*CSIConfig)(unsafe.Pointer(in.CSI))
+	out.StorageClasses = *(*[]StorageClass)(unsafe.Pointer(&in.StorageClasses))
+	out.RestrictMetadataServiceAccess = in.RestrictMetadataServiceAccess
+	out.APIServerLoadBalancer = (*APIServerLoadBalancer)(unsafe.Pointer(in.APIServerLoadBalancer))
 	return nil
 }
 
@@ -260,10 +549,221 @@ func Convert_alicloud_ControlPlaneConfig_To_v1alpha1_ControlPlaneConfig(in *alic
 	return autoConvert_alicloud_ControlPlaneConfig_To_v1alpha1_ControlPlaneConfig(in, out, s)
 }
 
+func autoConvert_v1alpha1_DHCPOptions_To_alicloud_DHCPOptions(in *DHCPOptions, out *alicloud.DHCPOptions, s conversion.Scope) error {
+	out.DomainNameServers = *(*[]string)(unsafe.Pointer(&in.DomainNameServers))
+	out.DomainName = (*string)(unsafe.Pointer(in.DomainName))
+	return nil
+}
+
+// Convert_v1alpha1_DHCPOptions_To_alicloud_DHCPOptions is an autogenerated conversion function.
+func Convert_v1alpha1_DHCPOptions_To_alicloud_DHCPOptions(in *DHCPOptions, out *alicloud.DHCPOptions, s conversion.Scope) error {
+	return autoConvert_v1alpha1_DHCPOptions_To_alicloud_DHCPOptions(in, out, s)
+}
+
+func autoConvert_alicloud_DHCPOptions_To_v1alpha1_DHCPOptions(in *alicloud.DHCPOptions, out *DHCPOptions, s conversion.Scope) error {
+	out.DomainNameServers = *(*[]string)(unsafe.Pointer(&in.DomainNameServers))
+	out.DomainName = (*string)(unsafe.Pointer(in.DomainName))
+	return nil
+}
+
+// Convert_alicloud_DHCPOptions_To_v1alpha1_DHCPOptions is an autogenerated conversion function.
+func Convert_alicloud_DHCPOptions_To_v1alpha1_DHCPOptions(in *alicloud.DHCPOptions, out *DHCPOptions, s conversion.Scope) error {
+	return autoConvert_alicloud_DHCPOptions_To_v1alpha1_DHCPOptions(in, out, s)
+}
+
+func autoConvert_v1alpha1_DNATEntry_To_alicloud_DNATEntry(in *DNATEntry, out *alicloud.DNATEntry, s conversion.Scope) error {
+	out.ExternalIP = in.ExternalIP
+	out.ExternalPort = in.ExternalPort
+	out.InternalIP = in.InternalIP
+	out.InternalPort = in.InternalPort
+	out.Protocol = in.Protocol
+	return nil
+}
+
+// Convert_v1alpha1_DNATEntry_To_alicloud_DNATEntry is an autogenerated conversion function.
+func Convert_v1alpha1_DNATEntry_To_alicloud_DNATEntry(in *DNATEntry, out *alicloud.DNATEntry, s conversion.Scope) error {
+	return autoConvert_v1alpha1_DNATEntry_To_alicloud_DNATEntry(in, out, s)
+}
+
+func autoConvert_alicloud_DNATEntry_To_v1alpha1_DNATEntry(in *alicloud.DNATEntry, out *DNATEntry, s conversion.Scope) error {
+	out.ExternalIP = in.ExternalIP
+	out.ExternalPort = in.ExternalPort
+	out.InternalIP = in.InternalIP
+	out.InternalPort = in.InternalPort
+	out.Protocol = in.Protocol
+	return nil
+}
+
+// Convert_alicloud_DNATEntry_To_v1alpha1_DNATEntry is an autogenerated conversion function.
+func Convert_alicloud_DNATEntry_To_v1alpha1_DNATEntry(in *alicloud.DNATEntry, out *DNATEntry, s conversion.Scope) error {
+	return autoConvert_alicloud_DNATEntry_To_v1alpha1_DNATEntry(in, out, s)
+}
+
+func autoConvert_v1alpha1_DataVolume_To_alicloud_DataVolume(in *DataVolume, out *alicloud.DataVolume, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Type = (*string)(unsafe.Pointer(in.Type))
+	out.Size = in.Size
+	out.PerformanceLevel = (*string)(unsafe.Pointer(in.PerformanceLevel))
+	return nil
+}
+
+// Convert_v1alpha1_DataVolume_To_alicloud_DataVolume is an autogenerated conversion function.
+func Convert_v1alpha1_DataVolume_To_alicloud_DataVolume(in *DataVolume, out *alicloud.DataVolume, s conversion.Scope) error {
+	return autoConvert_v1alpha1_DataVolume_To_alicloud_DataVolume(in, out, s)
+}
+
+func autoConvert_alicloud_DataVolume_To_v1alpha1_DataVolume(in *alicloud.DataVolume, out *DataVolume, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Type = (*string)(unsafe.Pointer(in.Type))
+	out.Size = in.Size
+	out.PerformanceLevel = (*string)(unsafe.Pointer(in.PerformanceLevel))
+	return nil
+}
+
+// Convert_alicloud_DataVolume_To_v1alpha1_DataVolume is an autogenerated conversion function.
+func Convert_alicloud_DataVolume_To_v1alpha1_DataVolume(in *alicloud.DataVolume, out *DataVolume, s conversion.Scope) error {
+	return autoConvert_alicloud_DataVolume_To_v1alpha1_DataVolume(in, out, s)
+}
+
+func autoConvert_v1alpha1_DefaultMachineImage_To_alicloud_DefaultMachineImage(in *DefaultMachineImage, out *alicloud.DefaultMachineImage, s conversion.Scope) error {
+	out.Region = in.Region
+	out.Name = in.Name
+	out.Version = in.Version
+	return nil
+}
+
+// Convert_v1alpha1_DefaultMachineImage_To_alicloud_DefaultMachineImage is an autogenerated conversion function.
+func Convert_v1alpha1_DefaultMachineImage_To_alicloud_DefaultMachineImage(in *DefaultMachineImage, out *alicloud.DefaultMachineImage, s conversion.Scope) error {
+	return autoConvert_v1alpha1_DefaultMachineImage_To_alicloud_DefaultMachineImage(in, out, s)
+}
+
+func autoConvert_alicloud_DefaultMachineImage_To_v1alpha1_DefaultMachineImage(in *alicloud.DefaultMachineImage, out *DefaultMachineImage, s conversion.Scope) error {
+	out.Region = in.Region
+	out.Name = in.Name
+	out.Version = in.Version
+	return nil
+}
+
+// Convert_alicloud_DefaultMachineImage_To_v1alpha1_DefaultMachineImage is an autogenerated conversion function.
+func Convert_alicloud_DefaultMachineImage_To_v1alpha1_DefaultMachineImage(in *alicloud.DefaultMachineImage, out *DefaultMachineImage, s conversion.Scope) error {
+	return autoConvert_alicloud_DefaultMachineImage_To_v1alpha1_DefaultMachineImage(in, out, s)
+}
+
+func autoConvert_v1alpha1_DualStack_To_alicloud_DualStack(in *DualStack, out *alicloud.DualStack, s conversion.Scope) error {
+	out.Enabled = in.Enabled
+	return nil
+}
+
+// Convert_v1alpha1_DualStack_To_alicloud_DualStack is an autogenerated conversion function.
+func Convert_v1alpha1_DualStack_To_alicloud_DualStack(in *DualStack, out *alicloud.DualStack, s conversion.Scope) error {
+	return autoConvert_v1alpha1_DualStack_To_alicloud_DualStack(in, out, s)
+}
+
+func autoConvert_alicloud_DualStack_To_v1alpha1_DualStack(in *alicloud.DualStack, out *DualStack, s conversion.Scope) error {
+	out.Enabled = in.Enabled
+	return nil
+}
+
+// Convert_alicloud_DualStack_To_v1alpha1_DualStack is an autogenerated conversion function.
+func Convert_alicloud_DualStack_To_v1alpha1_DualStack(in *alicloud.DualStack, out *DualStack, s conversion.Scope) error {
+	return autoConvert_alicloud_DualStack_To_v1alpha1_DualStack(in, out, s)
+}
+
+func autoConvert_v1alpha1_EIPAllocation_To_alicloud_EIPAllocation(in *EIPAllocation, out *alicloud.EIPAllocation, s conversion.Scope) error {
+	out.Bandwidth = (*int32)(unsafe.Pointer(in.Bandwidth))
+	out.InternetChargeType = (*string)(unsafe.Pointer(in.InternetChargeType))
+	out.Count = (*int32)(unsafe.Pointer(in.Count))
+	return nil
+}
+
+// Convert_v1alpha1_EIPAllocation_To_alicloud_EIPAllocation is an autogenerated conversion function.
+func Convert_v1alpha1_EIPAllocation_To_alicloud_EIPAllocation(in *EIPAllocation, out *alicloud.EIPAllocation, s conversion.Scope) error {
+	return autoConvert_v1alpha1_EIPAllocation_To_alicloud_EIPAllocation(in, out, s)
+}
+
+func autoConvert_alicloud_EIPAllocation_To_v1alpha1_EIPAllocation(in *alicloud.EIPAllocation, out *EIPAllocation, s conversion.Scope) error {
+	out.Bandwidth = (*int32)(unsafe.Pointer(in.Bandwidth))
+	out.InternetChargeType = (*string)(unsafe.Pointer(in.InternetChargeType))
+	out.Count = (*int32)(unsafe.Pointer(in.Count))
+	return nil
+}
+
+// Convert_alicloud_EIPAllocation_To_v1alpha1_EIPAllocation is an autogenerated conversion function.
+func Convert_alicloud_EIPAllocation_To_v1alpha1_EIPAllocation(in *alicloud.EIPAllocation, out *EIPAllocation, s conversion.Scope) error {
+	return autoConvert_alicloud_EIPAllocation_To_v1alpha1_EIPAllocation(in, out, s)
+}
+
+func autoConvert_v1alpha1_EIPStatus_To_alicloud_EIPStatus(in *EIPStatus, out *alicloud.EIPStatus, s conversion.Scope) error {
+	out.AllocationID = in.AllocationID
+	out.IPAddress = in.IPAddress
+	return nil
+}
+
+// Convert_v1alpha1_EIPStatus_To_alicloud_EIPStatus is an autogenerated conversion function.
+func Convert_v1alpha1_EIPStatus_To_alicloud_EIPStatus(in *EIPStatus, out *alicloud.EIPStatus, s conversion.Scope) error {
+	return autoConvert_v1alpha1_EIPStatus_To_alicloud_EIPStatus(in, out, s)
+}
+
+func autoConvert_alicloud_EIPStatus_To_v1alpha1_EIPStatus(in *alicloud.EIPStatus, out *EIPStatus, s conversion.Scope) error {
+	out.AllocationID = in.AllocationID
+	out.IPAddress = in.IPAddress
+	return nil
+}
+
+// Convert_alicloud_EIPStatus_To_v1alpha1_EIPStatus is an autogenerated conversion function.
+func Convert_alicloud_EIPStatus_To_v1alpha1_EIPStatus(in *alicloud.EIPStatus, out *EIPStatus, s conversion.Scope) error {
+	return autoConvert_alicloud_EIPStatus_To_v1alpha1_EIPStatus(in, out, s)
+}
+
+func autoConvert_v1alpha1_FlowLogTarget_To_alicloud_FlowLogTarget(in *FlowLogTarget, out *alicloud.FlowLogTarget, s conversion.Scope) error {
+	out.ProjectName = in.ProjectName
+	out.LogStoreName = in.LogStoreName
+	return nil
+}
+
+// Convert_v1alpha1_FlowLogTarget_To_alicloud_FlowLogTarget is an autogenerated conversion function.
+func Convert_v1alpha1_FlowLogTarget_To_alicloud_FlowLogTarget(in *FlowLogTarget, out *alicloud.FlowLogTarget, s conversion.Scope) error {
+	return autoConvert_v1alpha1_FlowLogTarget_To_alicloud_FlowLogTarget(in, out, s)
+}
+
+func autoConvert_alicloud_FlowLogTarget_To_v1alpha1_FlowLogTarget(in *alicloud.FlowLogTarget, out *FlowLogTarget, s conversion.Scope) error {
+	out.ProjectName = in.ProjectName
+	out.LogStoreName = in.LogStoreName
+	return nil
+}
+
+// Convert_alicloud_FlowLogTarget_To_v1alpha1_FlowLogTarget is an autogenerated conversion function.
+func Convert_alicloud_FlowLogTarget_To_v1alpha1_FlowLogTarget(in *alicloud.FlowLogTarget, out *FlowLogTarget, s conversion.Scope) error {
+	return autoConvert_alicloud_FlowLogTarget_To_v1alpha1_FlowLogTarget(in, out, s)
+}
+
+func autoConvert_v1alpha1_InfrastructureChange_To_alicloud_InfrastructureChange(in *InfrastructureChange, out *alicloud.InfrastructureChange, s conversion.Scope) error {
+	out.Action = alicloud.InfrastructureChangeAction(in.Action)
+	out.Resource = in.Resource
+	return nil
+}
+
+// Convert_v1alpha1_InfrastructureChange_To_alicloud_InfrastructureChange is an autogenerated conversion function.
+func Convert_v1alpha1_InfrastructureChange_To_alicloud_InfrastructureChange(in *InfrastructureChange, out *alicloud.InfrastructureChange, s conversion.Scope) error {
+	return autoConvert_v1alpha1_InfrastructureChange_To_alicloud_InfrastructureChange(in, out, s)
+}
+
+func autoConvert_alicloud_InfrastructureChange_To_v1alpha1_InfrastructureChange(in *alicloud.InfrastructureChange, out *InfrastructureChange, s conversion.Scope) error {
+	out.Action = InfrastructureChangeAction(in.Action)
+	out.Resource = in.Resource
+	return nil
+}
+
+// Convert_alicloud_InfrastructureChange_To_v1alpha1_InfrastructureChange is an autogenerated conversion function.
+func Convert_alicloud_InfrastructureChange_To_v1alpha1_InfrastructureChange(in *alicloud.InfrastructureChange, out *InfrastructureChange, s conversion.Scope) error {
+	return autoConvert_alicloud_InfrastructureChange_To_v1alpha1_InfrastructureChange(in, out, s)
+}
+
 func autoConvert_v1alpha1_InfrastructureConfig_To_alicloud_InfrastructureConfig(in *InfrastructureConfig, out *alicloud.InfrastructureConfig, s conversion.Scope) error {
 	if err := Convert_v1alpha1_Networks_To_alicloud_Networks(&in.Networks, &out.Networks, s); err != nil {
 		return err
 	}
+	out.Tags = *(*map[string]string)(unsafe.Pointer(&in.Tags))
 	return nil
 }
 
@@ -276,6 +776,7 @@ func autoConvert_alicloud_InfrastructureConfig_To_v1alpha1_InfrastructureConfig(
 	if err := Convert_alicloud_Networks_To_v1alpha1_Networks(&in.Networks, &out.Networks, s); err != nil {
 		return err
 	}
+	out.Tags = *(*map[string]string)(unsafe.Pointer(&in.Tags))
 	return nil
 }
 
@@ -284,12 +785,34 @@ func Convert_alicloud_InfrastructureConfig_To_v1alpha1_InfrastructureConfig(in *
 	return autoConvert_alicloud_InfrastructureConfig_To_v1alpha1_InfrastructureConfig(in, out, s)
 }
 
+func autoConvert_v1alpha1_InfrastructurePlan_To_alicloud_InfrastructurePlan(in *InfrastructurePlan, out *alicloud.InfrastructurePlan, s conversion.Scope) error {
+	out.Changes = *(*[]alicloud.InfrastructureChange)(unsafe.Pointer(&in.Changes))
+	return nil
+}
+
+// Convert_v1alpha1_InfrastructurePlan_To_alicloud_InfrastructurePlan is an autogenerated conversion function.
+func Convert_v1alpha1_InfrastructurePlan_To_alicloud_InfrastructurePlan(in *InfrastructurePlan, out *alicloud.InfrastructurePlan, s conversion.Scope) error {
+	return autoConvert_v1alpha1_InfrastructurePlan_To_alicloud_InfrastructurePlan(in, out, s)
+}
+
+func autoConvert_alicloud_InfrastructurePlan_To_v1alpha1_InfrastructurePlan(in *alicloud.InfrastructurePlan, out *InfrastructurePlan, s conversion.Scope) error {
+	out.Changes = *(*[]InfrastructureChange)(unsafe.Pointer(&in.Changes))
+	return nil
+}
+
+// Convert_alicloud_InfrastructurePlan_To_v1alpha1_InfrastructurePlan is an autogenerated conversion function.
+func Convert_alicloud_InfrastructurePlan_To_v1alpha1_InfrastructurePlan(in *alicloud.InfrastructurePlan, out *InfrastructurePlan, s conversion.Scope) error {
+	return autoConvert_alicloud_InfrastructurePlan_To_v1alpha1_InfrastructurePlan(in, out, s)
+}
+
 func autoConvert_v1alpha1_InfrastructureStatus_To_alicloud_InfrastructureStatus(in *InfrastructureStatus, out *alicloud.InfrastructureStatus, s conversion.Scope) error {
 	if err := Convert_v1alpha1_VPCStatus_To_alicloud_VPCStatus(&in.VPC, &out.VPC, s); err != nil {
 		return err
 	}
 	out.KeyPairName = in.KeyPairName
 	out.MachineImages = *(*[]alicloud.MachineImage)(unsafe.Pointer(&in.MachineImages))
+	out.Zones = *(*[]alicloud.ZoneStatus)(unsafe.Pointer(&in.Zones))
+	out.Plan = (*alicloud.InfrastructurePlan)(unsafe.Pointer(in.Plan))
 	return nil
 }
 
@@ -304,6 +827,8 @@ func autoConvert_alicloud_InfrastructureStatus_To_v1alpha1_InfrastructureStatus(
 	}
 	out.KeyPairName = in.KeyPairName
 	out.MachineImages = *(*[]MachineImage)(unsafe.Pointer(&in.MachineImages))
+	out.Zones = *(*[]ZoneStatus)(unsafe.Pointer(&in.Zones))
+	out.Plan = (*InfrastructurePlan)(unsafe.Pointer(in.Plan))
 	return nil
 }
 
@@ -312,10 +837,63 @@ func Convert_alicloud_InfrastructureStatus_To_v1alpha1_InfrastructureStatus(in *
 	return autoConvert_alicloud_InfrastructureStatus_To_v1alpha1_InfrastructureStatus(in, out, s)
 }
 
+func autoConvert_v1alpha1_KubeletConfig_To_alicloud_KubeletConfig(in *KubeletConfig, out *alicloud.KubeletConfig, s conversion.Scope) error {
+	out.MaxPods = (*int32)(unsafe.Pointer(in.MaxPods))
+	out.EvictionHard = *(*map[string]string)(unsafe.Pointer(&in.EvictionHard))
+	out.EvictionSoft = *(*map[string]string)(unsafe.Pointer(&in.EvictionSoft))
+	out.EvictionSoftGracePeriod = *(*map[string]string)(unsafe.Pointer(&in.EvictionSoftGracePeriod))
+	out.KubeReserved = *(*map[string]string)(unsafe.Pointer(&in.KubeReserved))
+	return nil
+}
+
+// Convert_v1alpha1_KubeletConfig_To_alicloud_KubeletConfig is an autogenerated conversion function.
+func Convert_v1alpha1_KubeletConfig_To_alicloud_KubeletConfig(in *KubeletConfig, out *alicloud.KubeletConfig, s conversion.Scope) error {
+	return autoConvert_v1alpha1_KubeletConfig_To_alicloud_KubeletConfig(in, out, s)
+}
+
+func autoConvert_alicloud_KubeletConfig_To_v1alpha1_KubeletConfig(in *alicloud.KubeletConfig, out *KubeletConfig, s conversion.Scope) error {
+	out.MaxPods = (*int32)(unsafe.Pointer(in.MaxPods))
+	out.EvictionHard = *(*map[string]string)(unsafe.Pointer(&in.EvictionHard))
+	out.EvictionSoft = *(*map[string]string)(unsafe.Pointer(&in.EvictionSoft))
+	out.EvictionSoftGracePeriod = *(*map[string]string)(unsafe.Pointer(&in.EvictionSoftGracePeriod))
+	out.KubeReserved = *(*map[string]string)(unsafe.Pointer(&in.KubeReserved))
+	return nil
+}
+
+// Convert_alicloud_KubeletConfig_To_v1alpha1_KubeletConfig is an autogenerated conversion function.
+func Convert_alicloud_KubeletConfig_To_v1alpha1_KubeletConfig(in *alicloud.KubeletConfig, out *KubeletConfig, s conversion.Scope) error {
+	return autoConvert_alicloud_KubeletConfig_To_v1alpha1_KubeletConfig(in, out, s)
+}
+
+func autoConvert_v1alpha1_LoadBalancerDefaults_To_alicloud_LoadBalancerDefaults(in *LoadBalancerDefaults, out *alicloud.LoadBalancerDefaults, s conversion.Scope) error {
+	out.Spec = (*string)(unsafe.Pointer(in.Spec))
+	out.ChargeType = (*string)(unsafe.Pointer(in.ChargeType))
+	out.Bandwidth = (*int32)(unsafe.Pointer(in.Bandwidth))
+	return nil
+}
+
+// Convert_v1alpha1_LoadBalancerDefaults_To_alicloud_LoadBalancerDefaults is an autogenerated conversion function.
+func Convert_v1alpha1_LoadBalancerDefaults_To_alicloud_LoadBalancerDefaults(in *LoadBalancerDefaults, out *alicloud.LoadBalancerDefaults, s conversion.Scope) error {
+	return autoConvert_v1alpha1_LoadBalancerDefaults_To_alicloud_LoadBalancerDefaults(in, out, s)
+}
+
+func autoConvert_alicloud_LoadBalancerDefaults_To_v1alpha1_LoadBalancerDefaults(in *alicloud.LoadBalancerDefaults, out *LoadBalancerDefaults, s conversion.Scope) error {
+	out.Spec = (*string)(unsafe.Pointer(in.Spec))
+	out.ChargeType = (*string)(unsafe.Pointer(in.ChargeType))
+	out.Bandwidth = (*int32)(unsafe.Pointer(in.Bandwidth))
+	return nil
+}
+
+// Convert_alicloud_LoadBalancerDefaults_To_v1alpha1_LoadBalancerDefaults is an autogenerated conversion function.
+func Convert_alicloud_LoadBalancerDefaults_To_v1alpha1_LoadBalancerDefaults(in *alicloud.LoadBalancerDefaults, out *LoadBalancerDefaults, s conversion.Scope) error {
+	return autoConvert_alicloud_LoadBalancerDefaults_To_v1alpha1_LoadBalancerDefaults(in, out, s)
+}
+
 func autoConvert_v1alpha1_MachineImage_To_alicloud_MachineImage(in *MachineImage, out *alicloud.MachineImage, s conversion.Scope) error {
 	out.Name = in.Name
 	out.Version = in.Version
 	out.ID = in.ID
+	out.Encrypted = (*bool)(unsafe.Pointer(in.Encrypted))
 	return nil
 }
 
@@ -328,6 +906,7 @@ func autoConvert_alicloud_MachineImage_To_v1alpha1_MachineImage(in *alicloud.Mac
 	out.Name = in.Name
 	out.Version = in.Version
 	out.ID = in.ID
+	out.Encrypted = (*bool)(unsafe.Pointer(in.Encrypted))
 	return nil
 }
 
@@ -339,6 +918,12 @@ func Convert_alicloud_MachineImage_To_v1alpha1_MachineImage(in *alicloud.Machine
 func autoConvert_v1alpha1_MachineImageVersion_To_alicloud_MachineImageVersion(in *MachineImageVersion, out *alicloud.MachineImageVersion, s conversion.Scope) error {
 	out.Version = in.Version
 	out.Regions = *(*[]alicloud.RegionIDMapping)(unsafe.Pointer(&in.Regions))
+	out.ImageName = (*string)(unsafe.Pointer(in.ImageName))
+	out.Classification = (*alicloud.MachineImageClassification)(unsafe.Pointer(in.Classification))
+	out.ExpirationDate = (*v1.Time)(unsafe.Pointer(in.ExpirationDate))
+	out.Replacement = (*string)(unsafe.Pointer(in.Replacement))
+	out.SupportsIPVS = (*bool)(unsafe.Pointer(in.SupportsIPVS))
+	out.MinimumVolumeSize = (*string)(unsafe.Pointer(in.MinimumVolumeSize))
 	return nil
 }
 
@@ -350,6 +935,12 @@ func Convert_v1alpha1_MachineImageVersion_To_alicloud_MachineImageVersion(in *Ma
 func autoConvert_alicloud_MachineImageVersion_To_v1alpha1_MachineImageVersion(in *alicloud.MachineImageVersion, out *MachineImageVersion, s conversion.Scope) error {
 	out.Version = in.Version
 	out.Regions = *(*[]RegionIDMapping)(unsafe.Pointer(&in.Regions))
+	out.ImageName = (*string)(unsafe.Pointer(in.ImageName))
+	out.Classification = (*MachineImageClassification)(unsafe.Pointer(in.Classification))
+	out.ExpirationDate = (*v1.Time)(unsafe.Pointer(in.ExpirationDate))
+	out.Replacement = (*string)(unsafe.Pointer(in.Replacement))
+	out.SupportsIPVS = (*bool)(unsafe.Pointer(in.SupportsIPVS))
+	out.MinimumVolumeSize = (*string)(unsafe.Pointer(in.MinimumVolumeSize))
 	return nil
 }
 
@@ -380,11 +971,74 @@ func Convert_alicloud_MachineImages_To_v1alpha1_MachineImages(in *alicloud.Machi
 	return autoConvert_alicloud_MachineImages_To_v1alpha1_MachineImages(in, out, s)
 }
 
+func autoConvert_v1alpha1_NatGateway_To_alicloud_NatGateway(in *NatGateway, out *alicloud.NatGateway, s conversion.Scope) error {
+	out.ID = (*string)(unsafe.Pointer(in.ID))
+	out.EIPAllocation = (*alicloud.EIPAllocation)(unsafe.Pointer(in.EIPAllocation))
+	out.EIPAllocationID = (*string)(unsafe.Pointer(in.EIPAllocationID))
+	out.PerZone = in.PerZone
+	out.DNATEntries = *(*[]alicloud.DNATEntry)(unsafe.Pointer(&in.DNATEntries))
+	return nil
+}
+
+// Convert_v1alpha1_NatGateway_To_alicloud_NatGateway is an autogenerated conversion function.
+func Convert_v1alpha1_NatGateway_To_alicloud_NatGateway(in *NatGateway, out *alicloud.NatGateway, s conversion.Scope) error {
+	return autoConvert_v1alpha1_NatGateway_To_alicloud_NatGateway(in, out, s)
+}
+
+func autoConvert_alicloud_NatGateway_To_v1alpha1_NatGateway(in *alicloud.NatGateway, out *NatGateway, s conversion.Scope) error {
+	out.ID = (*string)(unsafe.Pointer(in.ID))
+	out.EIPAllocation = (*EIPAllocation)(unsafe.Pointer(in.EIPAllocation))
+	out.EIPAllocationID = (*string)(unsafe.Pointer(in.EIPAllocationID))
+	out.PerZone = in.PerZone
+	out.DNATEntries = *(*[]DNATEntry)(unsafe.Pointer(&in.DNATEntries))
+	return nil
+}
+
+// Convert_alicloud_NatGateway_To_v1alpha1_NatGateway is an autogenerated conversion function.
+func Convert_alicloud_NatGateway_To_v1alpha1_NatGateway(in *alicloud.NatGateway, out *NatGateway, s conversion.Scope) error {
+	return autoConvert_alicloud_NatGateway_To_v1alpha1_NatGateway(in, out, s)
+}
+
+func autoConvert_v1alpha1_NatGatewayStatus_To_alicloud_NatGatewayStatus(in *NatGatewayStatus, out *alicloud.NatGatewayStatus, s conversion.Scope) error {
+	out.ID = in.ID
+	out.Zone = in.Zone
+	out.VSwitchID = in.VSwitchID
+	out.EIPs = *(*[]alicloud.EIPStatus)(unsafe.Pointer(&in.EIPs))
+	return nil
+}
+
+// Convert_v1alpha1_NatGatewayStatus_To_alicloud_NatGatewayStatus is an autogenerated conversion function.
+func Convert_v1alpha1_NatGatewayStatus_To_alicloud_NatGatewayStatus(in *NatGatewayStatus, out *alicloud.NatGatewayStatus, s conversion.Scope) error {
+	return autoConvert_v1alpha1_NatGatewayStatus_To_alicloud_NatGatewayStatus(in, out, s)
+}
+
+func autoConvert_alicloud_NatGatewayStatus_To_v1alpha1_NatGatewayStatus(in *alicloud.NatGatewayStatus, out *NatGatewayStatus, s conversion.Scope) error {
+	out.ID = in.ID
+	out.Zone = in.Zone
+	out.VSwitchID = in.VSwitchID
+	out.EIPs = *(*[]EIPStatus)(unsafe.Pointer(&in.EIPs))
+	return nil
+}
+
+// Convert_alicloud_NatGatewayStatus_To_v1alpha1_NatGatewayStatus is an autogenerated conversion function.
+func Convert_alicloud_NatGatewayStatus_To_v1alpha1_NatGatewayStatus(in *alicloud.NatGatewayStatus, out *NatGatewayStatus, s conversion.Scope) error {
+	return autoConvert_alicloud_NatGatewayStatus_To_v1alpha1_NatGatewayStatus(in, out, s)
+}
+
 func autoConvert_v1alpha1_Networks_To_alicloud_Networks(in *Networks, out *alicloud.Networks, s conversion.Scope) error {
 	if err := Convert_v1alpha1_VPC_To_alicloud_VPC(&in.VPC, &out.VPC, s); err != nil {
 		return err
 	}
 	out.Zones = *(*[]alicloud.Zone)(unsafe.Pointer(&in.Zones))
+	out.DualStack = (*alicloud.DualStack)(unsafe.Pointer(in.DualStack))
+	out.NatGateway = (*alicloud.NatGateway)(unsafe.Pointer(in.NatGateway))
+	out.SecurityGroupID = (*string)(unsafe.Pointer(in.SecurityGroupID))
+	out.SecurityGroupRules = *(*[]alicloud.SecurityGroupRule)(unsafe.Pointer(&in.SecurityGroupRules))
+	out.Routes = *(*[]alicloud.Route)(unsafe.Pointer(&in.Routes))
+	out.RouteTableID = (*string)(unsafe.Pointer(in.RouteTableID))
+	out.EnableFlowLogs = in.EnableFlowLogs
+	out.FlowLogTarget = (*alicloud.FlowLogTarget)(unsafe.Pointer(in.FlowLogTarget))
+	out.DHCPOptions = (*alicloud.DHCPOptions)(unsafe.Pointer(in.DHCPOptions))
 	return nil
 }
 
@@ -398,6 +1052,15 @@ func autoConvert_alicloud_Networks_To_v1alpha1_Networks(in *alicloud.Networks, o
 		return err
 	}
 	out.Zones = *(*[]Zone)(unsafe.Pointer(&in.Zones))
+	out.DualStack = (*DualStack)(unsafe.Pointer(in.DualStack))
+	out.NatGateway = (*NatGateway)(unsafe.Pointer(in.NatGateway))
+	out.SecurityGroupID = (*string)(unsafe.Pointer(in.SecurityGroupID))
+	out.SecurityGroupRules = *(*[]SecurityGroupRule)(unsafe.Pointer(&in.SecurityGroupRules))
+	out.Routes = *(*[]Route)(unsafe.Pointer(&in.Routes))
+	out.RouteTableID = (*string)(unsafe.Pointer(in.RouteTableID))
+	out.EnableFlowLogs = in.EnableFlowLogs
+	out.FlowLogTarget = (*FlowLogTarget)(unsafe.Pointer(in.FlowLogTarget))
+	out.DHCPOptions = (*DHCPOptions)(unsafe.Pointer(in.DHCPOptions))
 	return nil
 }
 
@@ -428,6 +1091,30 @@ func Convert_alicloud_RegionIDMapping_To_v1alpha1_RegionIDMapping(in *alicloud.R
 	return autoConvert_alicloud_RegionIDMapping_To_v1alpha1_RegionIDMapping(in, out, s)
 }
 
+func autoConvert_v1alpha1_Route_To_alicloud_Route(in *Route, out *alicloud.Route, s conversion.Scope) error {
+	out.DestinationCIDR = in.DestinationCIDR
+	out.NextHopType = alicloud.RouteNextHopType(in.NextHopType)
+	out.NextHopID = in.NextHopID
+	return nil
+}
+
+// Convert_v1alpha1_Route_To_alicloud_Route is an autogenerated conversion function.
+func Convert_v1alpha1_Route_To_alicloud_Route(in *Route, out *alicloud.Route, s conversion.Scope) error {
+	return autoConvert_v1alpha1_Route_To_alicloud_Route(in, out, s)
+}
+
+func autoConvert_alicloud_Route_To_v1alpha1_Route(in *alicloud.Route, out *Route, s conversion.Scope) error {
+	out.DestinationCIDR = in.DestinationCIDR
+	out.NextHopType = RouteNextHopType(in.NextHopType)
+	out.NextHopID = in.NextHopID
+	return nil
+}
+
+// Convert_alicloud_Route_To_v1alpha1_Route is an autogenerated conversion function.
+func Convert_alicloud_Route_To_v1alpha1_Route(in *alicloud.Route, out *Route, s conversion.Scope) error {
+	return autoConvert_alicloud_Route_To_v1alpha1_Route(in, out, s)
+}
+
 func autoConvert_v1alpha1_SecurityGroup_To_alicloud_SecurityGroup(in *SecurityGroup, out *alicloud.SecurityGroup, s conversion.Scope) error {
 	out.Purpose = alicloud.Purpose(in.Purpose)
 	out.ID = in.ID
@@ -450,6 +1137,82 @@ func Convert_alicloud_SecurityGroup_To_v1alpha1_SecurityGroup(in *alicloud.Secur
 	return autoConvert_alicloud_SecurityGroup_To_v1alpha1_SecurityGroup(in, out, s)
 }
 
+func autoConvert_v1alpha1_SecurityGroupRule_To_alicloud_SecurityGroupRule(in *SecurityGroupRule, out *alicloud.SecurityGroupRule, s conversion.Scope) error {
+	out.Direction = alicloud.SecurityGroupRuleDirection(in.Direction)
+	out.Protocol = in.Protocol
+	out.PortRange = in.PortRange
+	out.CIDR = in.CIDR
+	return nil
+}
+
+// Convert_v1alpha1_SecurityGroupRule_To_alicloud_SecurityGroupRule is an autogenerated conversion function.
+func Convert_v1alpha1_SecurityGroupRule_To_alicloud_SecurityGroupRule(in *SecurityGroupRule, out *alicloud.SecurityGroupRule, s conversion.Scope) error {
+	return autoConvert_v1alpha1_SecurityGroupRule_To_alicloud_SecurityGroupRule(in, out, s)
+}
+
+func autoConvert_alicloud_SecurityGroupRule_To_v1alpha1_SecurityGroupRule(in *alicloud.SecurityGroupRule, out *SecurityGroupRule, s conversion.Scope) error {
+	out.Direction = SecurityGroupRuleDirection(in.Direction)
+	out.Protocol = in.Protocol
+	out.PortRange = in.PortRange
+	out.CIDR = in.CIDR
+	return nil
+}
+
+// Convert_alicloud_SecurityGroupRule_To_v1alpha1_SecurityGroupRule is an autogenerated conversion function.
+func Convert_alicloud_SecurityGroupRule_To_v1alpha1_SecurityGroupRule(in *alicloud.SecurityGroupRule, out *SecurityGroupRule, s conversion.Scope) error {
+	return autoConvert_alicloud_SecurityGroupRule_To_v1alpha1_SecurityGroupRule(in, out, s)
+}
+
+func autoConvert_v1alpha1_StorageClass_To_alicloud_StorageClass(in *StorageClass, out *alicloud.StorageClass, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Default = in.Default
+	out.Type = (*string)(unsafe.Pointer(in.Type))
+	out.PerformanceLevel = (*string)(unsafe.Pointer(in.PerformanceLevel))
+	out.ReclaimPolicy = (*corev1.PersistentVolumeReclaimPolicy)(unsafe.Pointer(in.ReclaimPolicy))
+	return nil
+}
+
+// Convert_v1alpha1_StorageClass_To_alicloud_StorageClass is an autogenerated conversion function.
+func Convert_v1alpha1_StorageClass_To_alicloud_StorageClass(in *StorageClass, out *alicloud.StorageClass, s conversion.Scope) error {
+	return autoConvert_v1alpha1_StorageClass_To_alicloud_StorageClass(in, out, s)
+}
+
+func autoConvert_alicloud_StorageClass_To_v1alpha1_StorageClass(in *alicloud.StorageClass, out *StorageClass, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Default = in.Default
+	out.Type = (*string)(unsafe.Pointer(in.Type))
+	out.PerformanceLevel = (*string)(unsafe.Pointer(in.PerformanceLevel))
+	out.ReclaimPolicy = (*corev1.PersistentVolumeReclaimPolicy)(unsafe.Pointer(in.ReclaimPolicy))
+	return nil
+}
+
+// Convert_alicloud_StorageClass_To_v1alpha1_StorageClass is an autogenerated conversion function.
+func Convert_alicloud_StorageClass_To_v1alpha1_StorageClass(in *alicloud.StorageClass, out *StorageClass, s conversion.Scope) error {
+	return autoConvert_alicloud_StorageClass_To_v1alpha1_StorageClass(in, out, s)
+}
+
+func autoConvert_v1alpha1_SystemDisk_To_alicloud_SystemDisk(in *SystemDisk, out *alicloud.SystemDisk, s conversion.Scope) error {
+	out.Encrypted = in.Encrypted
+	out.KMSKeyID = (*string)(unsafe.Pointer(in.KMSKeyID))
+	return nil
+}
+
+// Convert_v1alpha1_SystemDisk_To_alicloud_SystemDisk is an autogenerated conversion function.
+func Convert_v1alpha1_SystemDisk_To_alicloud_SystemDisk(in *SystemDisk, out *alicloud.SystemDisk, s conversion.Scope) error {
+	return autoConvert_v1alpha1_SystemDisk_To_alicloud_SystemDisk(in, out, s)
+}
+
+func autoConvert_alicloud_SystemDisk_To_v1alpha1_SystemDisk(in *alicloud.SystemDisk, out *SystemDisk, s conversion.Scope) error {
+	out.Encrypted = in.Encrypted
+	out.KMSKeyID = (*string)(unsafe.Pointer(in.KMSKeyID))
+	return nil
+}
+
+// Convert_alicloud_SystemDisk_To_v1alpha1_SystemDisk is an autogenerated conversion function.
+func Convert_alicloud_SystemDisk_To_v1alpha1_SystemDisk(in *alicloud.SystemDisk, out *SystemDisk, s conversion.Scope) error {
+	return autoConvert_alicloud_SystemDisk_To_v1alpha1_SystemDisk(in, out, s)
+}
+
 func autoConvert_v1alpha1_VPC_To_alicloud_VPC(in *VPC, out *alicloud.VPC, s conversion.Scope) error {
 	out.ID = (*string)(unsafe.Pointer(in.ID))
 	out.CIDR = (*string)(unsafe.Pointer(in.CIDR))
@@ -476,6 +1239,8 @@ func autoConvert_v1alpha1_VPCStatus_To_alicloud_VPCStatus(in *VPCStatus, out *al
 	out.ID = in.ID
 	out.VSwitches = *(*[]alicloud.VSwitch)(unsafe.Pointer(&in.VSwitches))
 	out.SecurityGroups = *(*[]alicloud.SecurityGroup)(unsafe.Pointer(&in.SecurityGroups))
+	out.IPv6CIDR = in.IPv6CIDR
+	out.NatGateways = *(*[]alicloud.NatGatewayStatus)(unsafe.Pointer(&in.NatGateways))
 	return nil
 }
 
@@ -488,6 +1253,8 @@ func autoConvert_alicloud_VPCStatus_To_v1alpha1_VPCStatus(in *alicloud.VPCStatus
 	out.ID = in.ID
 	out.VSwitches = *(*[]VSwitch)(unsafe.Pointer(&in.VSwitches))
 	out.SecurityGroups = *(*[]SecurityGroup)(unsafe.Pointer(&in.SecurityGroups))
+	out.IPv6CIDR = in.IPv6CIDR
+	out.NatGateways = *(*[]NatGatewayStatus)(unsafe.Pointer(&in.NatGateways))
 	return nil
 }
 
@@ -500,6 +1267,7 @@ func autoConvert_v1alpha1_VSwitch_To_alicloud_VSwitch(in *VSwitch, out *alicloud
 	out.Purpose = alicloud.Purpose(in.Purpose)
 	out.ID = in.ID
 	out.Zone = in.Zone
+	out.IPv6CIDR = in.IPv6CIDR
 	return nil
 }
 
@@ -512,6 +1280,7 @@ func autoConvert_alicloud_VSwitch_To_v1alpha1_VSwitch(in *alicloud.VSwitch, out
 	out.Purpose = Purpose(in.Purpose)
 	out.ID = in.ID
 	out.Zone = in.Zone
+	out.IPv6CIDR = in.IPv6CIDR
 	return nil
 }
 
@@ -520,6 +1289,70 @@ func Convert_alicloud_VSwitch_To_v1alpha1_VSwitch(in *alicloud.VSwitch, out *VSw
 	return autoConvert_alicloud_VSwitch_To_v1alpha1_VSwitch(in, out, s)
 }
 
+func autoConvert_v1alpha1_WorkerConfig_To_alicloud_WorkerConfig(in *WorkerConfig, out *alicloud.WorkerConfig, s conversion.Scope) error {
+	out.ImageID = (*string)(unsafe.Pointer(in.ImageID))
+	out.SystemDisk = (*alicloud.SystemDisk)(unsafe.Pointer(in.SystemDisk))
+	out.DataVolumes = *(*[]alicloud.DataVolume)(unsafe.Pointer(&in.DataVolumes))
+	out.Tags = *(*map[string]string)(unsafe.Pointer(&in.Tags))
+	out.SpotStrategy = (*alicloud.SpotStrategy)(unsafe.Pointer(in.SpotStrategy))
+	out.SpotPriceLimit = (*string)(unsafe.Pointer(in.SpotPriceLimit))
+	out.DeploymentSetID = (*string)(unsafe.Pointer(in.DeploymentSetID))
+	out.PlacementStrategy = (*alicloud.PlacementStrategy)(unsafe.Pointer(in.PlacementStrategy))
+	out.SecurityGroupIDs = *(*[]string)(unsafe.Pointer(&in.SecurityGroupIDs))
+	out.RAMRoleName = (*string)(unsafe.Pointer(in.RAMRoleName))
+	out.UseLocalDisk = in.UseLocalDisk
+	out.GPUDriverVersion = (*string)(unsafe.Pointer(in.GPUDriverVersion))
+	out.SecondaryENIs = in.SecondaryENIs
+	out.UserData = (*string)(unsafe.Pointer(in.UserData))
+	out.InstanceChargeType = (*alicloud.InstanceChargeType)(unsafe.Pointer(in.InstanceChargeType))
+	out.Period = (*int32)(unsafe.Pointer(in.Period))
+	out.PeriodUnit = (*alicloud.PeriodUnit)(unsafe.Pointer(in.PeriodUnit))
+	out.AutoRenew = in.AutoRenew
+	out.AutoRenewPeriod = (*int32)(unsafe.Pointer(in.AutoRenewPeriod))
+	out.DrainTimeout = (*v1.Duration)(unsafe.Pointer(in.DrainTimeout))
+	out.MaxSurge = (*intstr.IntOrString)(unsafe.Pointer(in.MaxSurge))
+	out.MaxUnavailable = (*intstr.IntOrString)(unsafe.Pointer(in.MaxUnavailable))
+	out.KubeletConfig = (*alicloud.KubeletConfig)(unsafe.Pointer(in.KubeletConfig))
+	return nil
+}
+
+// Convert_v1alpha1_WorkerConfig_To_alicloud_WorkerConfig is an autogenerated conversion function.
+func Convert_v1alpha1_WorkerConfig_To_alicloud_WorkerConfig(in *WorkerConfig, out *alicloud.WorkerConfig, s conversion.Scope) error {
+	return autoConvert_v1alpha1_WorkerConfig_To_alicloud_WorkerConfig(in, out, s)
+}
+
+func autoConvert_alicloud_WorkerConfig_To_v1alpha1_WorkerConfig(in *alicloud.WorkerConfig, out *WorkerConfig, s conversion.Scope) error {
+	out.ImageID = (*string)(unsafe.Pointer(in.ImageID))
+	out.SystemDisk = (*SystemDisk)(unsafe.Pointer(in.SystemDisk))
+	out.DataVolumes = *(*[]DataVolume)(unsafe.Pointer(&in.DataVolumes))
+	out.Tags = *(*map[string]string)(unsafe.Pointer(&in.Tags))
+	out.SpotStrategy = (*SpotStrategy)(unsafe.Pointer(in.SpotStrategy))
+	out.SpotPriceLimit = (*string)(unsafe.Pointer(in.SpotPriceLimit))
+	out.DeploymentSetID = (*string)(unsafe.Pointer(in.DeploymentSetID))
+	out.PlacementStrategy = (*PlacementStrategy)(unsafe.Pointer(in.PlacementStrategy))
+	out.SecurityGroupIDs = *(*[]string)(unsafe.Pointer(&in.SecurityGroupIDs))
+	out.RAMRoleName = (*string)(unsafe.Pointer(in.RAMRoleName))
+	out.UseLocalDisk = in.UseLocalDisk
+	out.GPUDriverVersion = (*string)(unsafe.Pointer(in.GPUDriverVersion))
+	out.SecondaryENIs = in.SecondaryENIs
+	out.UserData = (*string)(unsafe.Pointer(in.UserData))
+	out.InstanceChargeType = (*InstanceChargeType)(unsafe.Pointer(in.InstanceChargeType))
+	out.Period = (*int32)(unsafe.Pointer(in.Period))
+	out.PeriodUnit = (*PeriodUnit)(unsafe.Pointer(in.PeriodUnit))
+	out.AutoRenew = in.AutoRenew
+	out.AutoRenewPeriod = (*int32)(unsafe.Pointer(in.AutoRenewPeriod))
+	out.DrainTimeout = (*v1.Duration)(unsafe.Pointer(in.DrainTimeout))
+	out.MaxSurge = (*intstr.IntOrString)(unsafe.Pointer(in.MaxSurge))
+	out.MaxUnavailable = (*intstr.IntOrString)(unsafe.Pointer(in.MaxUnavailable))
+	out.KubeletConfig = (*KubeletConfig)(unsafe.Pointer(in.KubeletConfig))
+	return nil
+}
+
+// Convert_alicloud_WorkerConfig_To_v1alpha1_WorkerConfig is an autogenerated conversion function.
+func Convert_alicloud_WorkerConfig_To_v1alpha1_WorkerConfig(in *alicloud.WorkerConfig, out *WorkerConfig, s conversion.Scope) error {
+	return autoConvert_alicloud_WorkerConfig_To_v1alpha1_WorkerConfig(in, out, s)
+}
+
 func autoConvert_v1alpha1_WorkerStatus_To_alicloud_WorkerStatus(in *WorkerStatus, out *alicloud.WorkerStatus, s conversion.Scope) error {
 	out.MachineImages = *(*[]alicloud.MachineImage)(unsafe.Pointer(&in.MachineImages))
 	return nil
@@ -544,6 +1377,9 @@ func autoConvert_v1alpha1_Zone_To_alicloud_Zone(in *Zone, out *alicloud.Zone, s
 	out.Name = in.Name
 	out.Worker = in.Worker
 	out.Workers = in.Workers
+	out.AdditionalWorkers = *(*[]string)(unsafe.Pointer(&in.AdditionalWorkers))
+	out.NatGatewayCIDR = (*string)(unsafe.Pointer(in.NatGatewayCIDR))
+	out.PodsCIDR = (*string)(unsafe.Pointer(in.PodsCIDR))
 	return nil
 }
 
@@ -556,6 +1392,9 @@ func autoConvert_alicloud_Zone_To_v1alpha1_Zone(in *alicloud.Zone, out *Zone, s
 	out.Name = in.Name
 	out.Worker = in.Worker
 	out.Workers = in.Workers
+	out.AdditionalWorkers = *(*[]string)(unsafe.Pointer(&in.AdditionalWorkers))
+	out.NatGatewayCIDR = (*string)(unsafe.Pointer(in.NatGatewayCIDR))
+	out.PodsCIDR = (*string)(unsafe.Pointer(in.PodsCIDR))
 	return nil
 }
 
@@ -563,3 +1402,25 @@ func autoConvert_alicloud_Zone_To_v1alpha1_Zone(in *alicloud.Zone, out *Zone, s
 func Convert_alicloud_Zone_To_v1alpha1_Zone(in *alicloud.Zone, out *Zone, s conversion.Scope) error {
 	return autoConvert_alicloud_Zone_To_v1alpha1_Zone(in, out, s)
 }
+
+func autoConvert_v1alpha1_ZoneStatus_To_alicloud_ZoneStatus(in *ZoneStatus, out *alicloud.ZoneStatus, s conversion.Scope) error {
+	out.Name = in.Name
+	out.ZoneID = in.ZoneID
+	return nil
+}
+
+// Convert_v1alpha1_ZoneStatus_To_alicloud_ZoneStatus is an autogenerated conversion function.
+func Convert_v1alpha1_ZoneStatus_To_alicloud_ZoneStatus(in *ZoneStatus, out *alicloud.ZoneStatus, s conversion.Scope) error {
+	return autoConvert_v1alpha1_ZoneStatus_To_alicloud_ZoneStatus(in, out, s)
+}
+
+func autoConvert_alicloud_ZoneStatus_To_v1alpha1_ZoneStatus(in *alicloud.ZoneStatus, out *ZoneStatus, s conversion.Scope) error {
+	out.Name = in.Name
+	out.ZoneID = in.ZoneID
+	return nil
+}
+
+// Convert_alicloud_ZoneStatus_To_v1alpha1_ZoneStatus is an autogenerated conversion function.
+func Convert_alicloud_ZoneStatus_To_v1alpha1_ZoneStatus(in *alicloud.ZoneStatus, out *ZoneStatus, s conversion.Scope) error {
+	return autoConvert_alicloud_ZoneStatus_To_v1alpha1_ZoneStatus(in, out, s)
+}
@@ -21,9 +21,76 @@ limitations under the License.
 package v1alpha1
 
 import (
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	intstr "k8s.io/apimachinery/pkg/util/intstr"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIServerLoadBalancer) DeepCopyInto(out *APIServerLoadBalancer) {
+	*out = *in
+	if in.AddressType != nil {
+		in, out := &in.AddressType, &out.AddressType
+		*out = new(LoadBalancerAddressType)
+		**out = **in
+	}
+	if in.IdleTimeout != nil {
+		in, out := &in.IdleTimeout, &out.IdleTimeout
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ConnectionDrainTimeout != nil {
+		in, out := &in.ConnectionDrainTimeout, &out.ConnectionDrainTimeout
+		*out = new(int32)
+		**out = **in
+	}
+	if in.HealthCheckInterval != nil {
+		in, out := &in.HealthCheckInterval, &out.HealthCheckInterval
+		*out = new(int32)
+		**out = **in
+	}
+	if in.AllowedCIDRs != nil {
+		in, out := &in.AllowedCIDRs, &out.AllowedCIDRs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIServerLoadBalancer.
+func (in *APIServerLoadBalancer) DeepCopy() *APIServerLoadBalancer {
+	if in == nil {
+		return nil
+	}
+	out := new(APIServerLoadBalancer)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CSIConfig) DeepCopyInto(out *CSIConfig) {
+	*out = *in
+	if in.Images != nil {
+		in, out := &in.Images, &out.Images
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CSIConfig.
+func (in *CSIConfig) DeepCopy() *CSIConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(CSIConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CloudControllerManagerConfig) DeepCopyInto(out *CloudControllerManagerConfig) {
 	*out = *in
@@ -34,6 +101,11 @@ func (in *CloudControllerManagerConfig) DeepCopyInto(out *CloudControllerManager
 			(*out)[key] = val
 		}
 	}
+	if in.Image != nil {
+		in, out := &in.Image, &out.Image
+		*out = new(string)
+		**out = **in
+	}
 	return
 }
 
@@ -58,6 +130,16 @@ func (in *CloudProfileConfig) DeepCopyInto(out *CloudProfileConfig) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.DeprecatedMachineImagePolicy != nil {
+		in, out := &in.DeprecatedMachineImagePolicy, &out.DeprecatedMachineImagePolicy
+		*out = new(DeprecatedMachineImagePolicy)
+		**out = **in
+	}
+	if in.DefaultMachineImages != nil {
+		in, out := &in.DefaultMachineImages, &out.DefaultMachineImages
+		*out = make([]DefaultMachineImage, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -88,6 +170,28 @@ func (in *ControlPlaneConfig) DeepCopyInto(out *ControlPlaneConfig) {
 		*out = new(CloudControllerManagerConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.LoadBalancerDefaults != nil {
+		in, out := &in.LoadBalancerDefaults, &out.LoadBalancerDefaults
+		*out = new(LoadBalancerDefaults)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CSI != nil {
+		in, out := &in.CSI, &out.CSI
+		*out = new(CSIConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StorageClasses != nil {
+		in, out := &in.StorageClasses, &out.StorageClasses
+		*out = make([]StorageClass, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.APIServerLoadBalancer != nil {
+		in, out := &in.APIServerLoadBalancer, &out.APIServerLoadBalancer
+		*out = new(APIServerLoadBalancer)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -109,11 +213,197 @@ func (in *ControlPlaneConfig) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DHCPOptions) DeepCopyInto(out *DHCPOptions) {
+	*out = *in
+	if in.DomainNameServers != nil {
+		in, out := &in.DomainNameServers, &out.DomainNameServers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DomainName != nil {
+		in, out := &in.DomainName, &out.DomainName
+		*out = new(string)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DHCPOptions.
+func (in *DHCPOptions) DeepCopy() *DHCPOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(DHCPOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DNATEntry) DeepCopyInto(out *DNATEntry) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DNATEntry.
+func (in *DNATEntry) DeepCopy() *DNATEntry {
+	if in == nil {
+		return nil
+	}
+	out := new(DNATEntry)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataVolume) DeepCopyInto(out *DataVolume) {
+	*out = *in
+	if in.Type != nil {
+		in, out := &in.Type, &out.Type
+		*out = new(string)
+		**out = **in
+	}
+	if in.PerformanceLevel != nil {
+		in, out := &in.PerformanceLevel, &out.PerformanceLevel
+		*out = new(string)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataVolume.
+func (in *DataVolume) DeepCopy() *DataVolume {
+	if in == nil {
+		return nil
+	}
+	out := new(DataVolume)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DefaultMachineImage) DeepCopyInto(out *DefaultMachineImage) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DefaultMachineImage.
+func (in *DefaultMachineImage) DeepCopy() *DefaultMachineImage {
+	if in == nil {
+		return nil
+	}
+	out := new(DefaultMachineImage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DualStack) DeepCopyInto(out *DualStack) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DualStack.
+func (in *DualStack) DeepCopy() *DualStack {
+	if in == nil {
+		return nil
+	}
+	out := new(DualStack)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EIPAllocation) DeepCopyInto(out *EIPAllocation) {
+	*out = *in
+	if in.Bandwidth != nil {
+		in, out := &in.Bandwidth, &out.Bandwidth
+		*out = new(int32)
+		**out = **in
+	}
+	if in.InternetChargeType != nil {
+		in, out := &in.InternetChargeType, &out.InternetChargeType
+		*out = new(string)
+		**out = **in
+	}
+	if in.Count != nil {
+		in, out := &in.Count, &out.Count
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EIPAllocation.
+func (in *EIPAllocation) DeepCopy() *EIPAllocation {
+	if in == nil {
+		return nil
+	}
+	out := new(EIPAllocation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EIPStatus) DeepCopyInto(out *EIPStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EIPStatus.
+func (in *EIPStatus) DeepCopy() *EIPStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(EIPStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FlowLogTarget) DeepCopyInto(out *FlowLogTarget) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FlowLogTarget.
+func (in *FlowLogTarget) DeepCopy() *FlowLogTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(FlowLogTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InfrastructureChange) DeepCopyInto(out *InfrastructureChange) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InfrastructureChange.
+func (in *InfrastructureChange) DeepCopy() *InfrastructureChange {
+	if in == nil {
+		return nil
+	}
+	out := new(InfrastructureChange)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *InfrastructureConfig) DeepCopyInto(out *InfrastructureConfig) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.Networks.DeepCopyInto(&out.Networks)
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	return
 }
 
@@ -135,6 +425,27 @@ func (in *InfrastructureConfig) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InfrastructurePlan) DeepCopyInto(out *InfrastructurePlan) {
+	*out = *in
+	if in.Changes != nil {
+		in, out := &in.Changes, &out.Changes
+		*out = make([]InfrastructureChange, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InfrastructurePlan.
+func (in *InfrastructurePlan) DeepCopy() *InfrastructurePlan {
+	if in == nil {
+		return nil
+	}
+	out := new(InfrastructurePlan)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *InfrastructureStatus) DeepCopyInto(out *InfrastructureStatus) {
 	*out = *in
@@ -143,8 +454,20 @@ func (in *InfrastructureStatus) DeepCopyInto(out *InfrastructureStatus) {
 	if in.MachineImages != nil {
 		in, out := &in.MachineImages, &out.MachineImages
 		*out = make([]MachineImage, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Zones != nil {
+		in, out := &in.Zones, &out.Zones
+		*out = make([]ZoneStatus, len(*in))
 		copy(*out, *in)
 	}
+	if in.Plan != nil {
+		in, out := &in.Plan, &out.Plan
+		*out = new(InfrastructurePlan)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -166,9 +489,94 @@ func (in *InfrastructureStatus) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeletConfig) DeepCopyInto(out *KubeletConfig) {
+	*out = *in
+	if in.MaxPods != nil {
+		in, out := &in.MaxPods, &out.MaxPods
+		*out = new(int32)
+		**out = **in
+	}
+	if in.EvictionHard != nil {
+		in, out := &in.EvictionHard, &out.EvictionHard
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.EvictionSoft != nil {
+		in, out := &in.EvictionSoft, &out.EvictionSoft
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.EvictionSoftGracePeriod != nil {
+		in, out := &in.EvictionSoftGracePeriod, &out.EvictionSoftGracePeriod
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.KubeReserved != nil {
+		in, out := &in.KubeReserved, &out.KubeReserved
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeletConfig.
+func (in *KubeletConfig) DeepCopy() *KubeletConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeletConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoadBalancerDefaults) DeepCopyInto(out *LoadBalancerDefaults) {
+	*out = *in
+	if in.Spec != nil {
+		in, out := &in.Spec, &out.Spec
+		*out = new(string)
+		**out = **in
+	}
+	if in.ChargeType != nil {
+		in, out := &in.ChargeType, &out.ChargeType
+		*out = new(string)
+		**out = **in
+	}
+	if in.Bandwidth != nil {
+		in, out := &in.Bandwidth, &out.Bandwidth
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadBalancerDefaults.
+func (in *LoadBalancerDefaults) DeepCopy() *LoadBalancerDefaults {
+	if in == nil {
+		return nil
+	}
+	out := new(LoadBalancerDefaults)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MachineImage) DeepCopyInto(out *MachineImage) {
 	*out = *in
+	if in.Encrypted != nil {
+		in, out := &in.Encrypted, &out.Encrypted
+		*out = new(bool)
+		**out = **in
+	}
 	return
 }
 
@@ -190,6 +598,35 @@ func (in *MachineImageVersion) DeepCopyInto(out *MachineImageVersion) {
 		*out = make([]RegionIDMapping, len(*in))
 		copy(*out, *in)
 	}
+	if in.ImageName != nil {
+		in, out := &in.ImageName, &out.ImageName
+		*out = new(string)
+		**out = **in
+	}
+	if in.Classification != nil {
+		in, out := &in.Classification, &out.Classification
+		*out = new(MachineImageClassification)
+		**out = **in
+	}
+	if in.ExpirationDate != nil {
+		in, out := &in.ExpirationDate, &out.ExpirationDate
+		*out = (*in).DeepCopy()
+	}
+	if in.Replacement != nil {
+		in, out := &in.Replacement, &out.Replacement
+		*out = new(string)
+		**out = **in
+	}
+	if in.SupportsIPVS != nil {
+		in, out := &in.SupportsIPVS, &out.SupportsIPVS
+		*out = new(bool)
+		**out = **in
+	}
+	if in.MinimumVolumeSize != nil {
+		in, out := &in.MinimumVolumeSize, &out.MinimumVolumeSize
+		*out = new(string)
+		**out = **in
+	}
 	return
 }
 
@@ -226,6 +663,63 @@ func (in *MachineImages) DeepCopy() *MachineImages {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NatGateway) DeepCopyInto(out *NatGateway) {
+	*out = *in
+	if in.ID != nil {
+		in, out := &in.ID, &out.ID
+		*out = new(string)
+		**out = **in
+	}
+	if in.EIPAllocation != nil {
+		in, out := &in.EIPAllocation, &out.EIPAllocation
+		*out = new(EIPAllocation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.EIPAllocationID != nil {
+		in, out := &in.EIPAllocationID, &out.EIPAllocationID
+		*out = new(string)
+		**out = **in
+	}
+	if in.DNATEntries != nil {
+		in, out := &in.DNATEntries, &out.DNATEntries
+		*out = make([]DNATEntry, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NatGateway.
+func (in *NatGateway) DeepCopy() *NatGateway {
+	if in == nil {
+		return nil
+	}
+	out := new(NatGateway)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NatGatewayStatus) DeepCopyInto(out *NatGatewayStatus) {
+	*out = *in
+	if in.EIPs != nil {
+		in, out := &in.EIPs, &out.EIPs
+		*out = make([]EIPStatus, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NatGatewayStatus.
+func (in *NatGatewayStatus) DeepCopy() *NatGatewayStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NatGatewayStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Networks) DeepCopyInto(out *Networks) {
 	*out = *in
@@ -233,8 +727,50 @@ func (in *Networks) DeepCopyInto(out *Networks) {
 	if in.Zones != nil {
 		in, out := &in.Zones, &out.Zones
 		*out = make([]Zone, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DualStack != nil {
+		in, out := &in.DualStack, &out.DualStack
+		*out = new(DualStack)
+		**out = **in
+	}
+	if in.NatGateway != nil {
+		in, out := &in.NatGateway, &out.NatGateway
+		*out = new(NatGateway)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecurityGroupID != nil {
+		in, out := &in.SecurityGroupID, &out.SecurityGroupID
+		*out = new(string)
+		**out = **in
+	}
+	if in.SecurityGroupRules != nil {
+		in, out := &in.SecurityGroupRules, &out.SecurityGroupRules
+		*out = make([]SecurityGroupRule, len(*in))
+		copy(*out, *in)
+	}
+	if in.Routes != nil {
+		in, out := &in.Routes, &out.Routes
+		*out = make([]Route, len(*in))
 		copy(*out, *in)
 	}
+	if in.RouteTableID != nil {
+		in, out := &in.RouteTableID, &out.RouteTableID
+		*out = new(string)
+		**out = **in
+	}
+	if in.FlowLogTarget != nil {
+		in, out := &in.FlowLogTarget, &out.FlowLogTarget
+		*out = new(FlowLogTarget)
+		**out = **in
+	}
+	if in.DHCPOptions != nil {
+		in, out := &in.DHCPOptions, &out.DHCPOptions
+		*out = new(DHCPOptions)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -264,6 +800,22 @@ func (in *RegionIDMapping) DeepCopy() *RegionIDMapping {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Route) DeepCopyInto(out *Route) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Route.
+func (in *Route) DeepCopy() *Route {
+	if in == nil {
+		return nil
+	}
+	out := new(Route)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SecurityGroup) DeepCopyInto(out *SecurityGroup) {
 	*out = *in
@@ -280,6 +832,74 @@ func (in *SecurityGroup) DeepCopy() *SecurityGroup {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecurityGroupRule) DeepCopyInto(out *SecurityGroupRule) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecurityGroupRule.
+func (in *SecurityGroupRule) DeepCopy() *SecurityGroupRule {
+	if in == nil {
+		return nil
+	}
+	out := new(SecurityGroupRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageClass) DeepCopyInto(out *StorageClass) {
+	*out = *in
+	if in.Type != nil {
+		in, out := &in.Type, &out.Type
+		*out = new(string)
+		**out = **in
+	}
+	if in.PerformanceLevel != nil {
+		in, out := &in.PerformanceLevel, &out.PerformanceLevel
+		*out = new(string)
+		**out = **in
+	}
+	if in.ReclaimPolicy != nil {
+		in, out := &in.ReclaimPolicy, &out.ReclaimPolicy
+		*out = new(v1.PersistentVolumeReclaimPolicy)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageClass.
+func (in *StorageClass) DeepCopy() *StorageClass {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageClass)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SystemDisk) DeepCopyInto(out *SystemDisk) {
+	*out = *in
+	if in.KMSKeyID != nil {
+		in, out := &in.KMSKeyID, &out.KMSKeyID
+		*out = new(string)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SystemDisk.
+func (in *SystemDisk) DeepCopy() *SystemDisk {
+	if in == nil {
+		return nil
+	}
+	out := new(SystemDisk)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VPC) DeepCopyInto(out *VPC) {
 	*out = *in
@@ -319,6 +939,13 @@ func (in *VPCStatus) DeepCopyInto(out *VPCStatus) {
 		*out = make([]SecurityGroup, len(*in))
 		copy(*out, *in)
 	}
+	if in.NatGateways != nil {
+		in, out := &in.NatGateways, &out.NatGateways
+		*out = make([]NatGatewayStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -348,6 +975,135 @@ func (in *VSwitch) DeepCopy() *VSwitch {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkerConfig) DeepCopyInto(out *WorkerConfig) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	if in.ImageID != nil {
+		in, out := &in.ImageID, &out.ImageID
+		*out = new(string)
+		**out = **in
+	}
+	if in.SystemDisk != nil {
+		in, out := &in.SystemDisk, &out.SystemDisk
+		*out = new(SystemDisk)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DataVolumes != nil {
+		in, out := &in.DataVolumes, &out.DataVolumes
+		*out = make([]DataVolume, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.SpotStrategy != nil {
+		in, out := &in.SpotStrategy, &out.SpotStrategy
+		*out = new(SpotStrategy)
+		**out = **in
+	}
+	if in.SpotPriceLimit != nil {
+		in, out := &in.SpotPriceLimit, &out.SpotPriceLimit
+		*out = new(string)
+		**out = **in
+	}
+	if in.DeploymentSetID != nil {
+		in, out := &in.DeploymentSetID, &out.DeploymentSetID
+		*out = new(string)
+		**out = **in
+	}
+	if in.PlacementStrategy != nil {
+		in, out := &in.PlacementStrategy, &out.PlacementStrategy
+		*out = new(PlacementStrategy)
+		**out = **in
+	}
+	if in.SecurityGroupIDs != nil {
+		in, out := &in.SecurityGroupIDs, &out.SecurityGroupIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RAMRoleName != nil {
+		in, out := &in.RAMRoleName, &out.RAMRoleName
+		*out = new(string)
+		**out = **in
+	}
+	if in.GPUDriverVersion != nil {
+		in, out := &in.GPUDriverVersion, &out.GPUDriverVersion
+		*out = new(string)
+		**out = **in
+	}
+	if in.UserData != nil {
+		in, out := &in.UserData, &out.UserData
+		*out = new(string)
+		**out = **in
+	}
+	if in.InstanceChargeType != nil {
+		in, out := &in.InstanceChargeType, &out.InstanceChargeType
+		*out = new(InstanceChargeType)
+		**out = **in
+	}
+	if in.Period != nil {
+		in, out := &in.Period, &out.Period
+		*out = new(int32)
+		**out = **in
+	}
+	if in.PeriodUnit != nil {
+		in, out := &in.PeriodUnit, &out.PeriodUnit
+		*out = new(PeriodUnit)
+		**out = **in
+	}
+	if in.AutoRenewPeriod != nil {
+		in, out := &in.AutoRenewPeriod, &out.AutoRenewPeriod
+		*out = new(int32)
+		**out = **in
+	}
+	if in.DrainTimeout != nil {
+		in, out := &in.DrainTimeout, &out.DrainTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.MaxSurge != nil {
+		in, out := &in.MaxSurge, &out.MaxSurge
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.KubeletConfig != nil {
+		in, out := &in.KubeletConfig, &out.KubeletConfig
+		*out = new(KubeletConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkerConfig.
+func (in *WorkerConfig) DeepCopy() *WorkerConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkerConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *WorkerConfig) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WorkerStatus) DeepCopyInto(out *WorkerStatus) {
 	*out = *in
@@ -355,7 +1111,9 @@ func (in *WorkerStatus) DeepCopyInto(out *WorkerStatus) {
 	if in.MachineImages != nil {
 		in, out := &in.MachineImages, &out.MachineImages
 		*out = make([]MachineImage, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	return
 }
@@ -381,6 +1139,21 @@ func (in *WorkerStatus) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Zone) DeepCopyInto(out *Zone) {
 	*out = *in
+	if in.AdditionalWorkers != nil {
+		in, out := &in.AdditionalWorkers, &out.AdditionalWorkers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.NatGatewayCIDR != nil {
+		in, out := &in.NatGatewayCIDR, &out.NatGatewayCIDR
+		*out = new(string)
+		**out = **in
+	}
+	if in.PodsCIDR != nil {
+		in, out := &in.PodsCIDR, &out.PodsCIDR
+		*out = new(string)
+		**out = **in
+	}
 	return
 }
 
@@ -393,3 +1166,19 @@ func (in *Zone) DeepCopy() *Zone {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ZoneStatus) DeepCopyInto(out *ZoneStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ZoneStatus.
+func (in *ZoneStatus) DeepCopy() *ZoneStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ZoneStatus)
+	in.DeepCopyInto(out)
+	return out
+}
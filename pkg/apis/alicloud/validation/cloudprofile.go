@@ -18,14 +18,33 @@ import (
 	"fmt"
 
 	apisalicloud "github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/alicloud"
+	"github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/alicloud/helper"
 
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
+var (
+	availableDeprecatedMachineImagePolicies = sets.NewString(
+		string(apisalicloud.DeprecatedMachineImagePolicyWarn),
+		string(apisalicloud.DeprecatedMachineImagePolicyBlock),
+	)
+	availableMachineImageClassifications = sets.NewString(
+		string(apisalicloud.ClassificationPreview),
+		string(apisalicloud.ClassificationSupported),
+		string(apisalicloud.ClassificationDeprecated),
+	)
+)
+
 // ValidateCloudProfileConfig validates a CloudProfileConfig object.
 func ValidateCloudProfileConfig(cloudProfile *apisalicloud.CloudProfileConfig) field.ErrorList {
 	allErrs := field.ErrorList{}
 
+	if policy := cloudProfile.DeprecatedMachineImagePolicy; policy != nil && !availableDeprecatedMachineImagePolicies.Has(string(*policy)) {
+		allErrs = append(allErrs, field.NotSupported(field.NewPath("deprecatedMachineImagePolicy"), *policy, availableDeprecatedMachineImagePolicies.List()))
+	}
+
 	machineImagesPath := field.NewPath("machineImages")
 	if len(cloudProfile.MachineImages) == 0 {
 		allErrs = append(allErrs, field.Required(machineImagesPath, "must provide at least one machine image"))
@@ -47,8 +66,18 @@ func ValidateCloudProfileConfig(cloudProfile *apisalicloud.CloudProfileConfig) f
 				allErrs = append(allErrs, field.Required(jdxPath.Child("version"), "must provide a version"))
 			}
 
-			if len(version.Regions) == 0 {
-				allErrs = append(allErrs, field.Required(jdxPath.Child("regions"), fmt.Sprintf("must provide at least one region for machine image %q and version %q", machineImage.Name, version.Version)))
+			if version.ImageName != nil && len(*version.ImageName) == 0 {
+				allErrs = append(allErrs, field.Invalid(jdxPath.Child("imageName"), *version.ImageName, "must not be empty"))
+			}
+			if version.MinimumVolumeSize != nil {
+				if size, err := resource.ParseQuantity(*version.MinimumVolumeSize); err != nil {
+					allErrs = append(allErrs, field.Invalid(jdxPath.Child("minimumVolumeSize"), *version.MinimumVolumeSize, fmt.Sprintf("must be a valid quantity: %v", err)))
+				} else if size.Sign() <= 0 {
+					allErrs = append(allErrs, field.Invalid(jdxPath.Child("minimumVolumeSize"), *version.MinimumVolumeSize, "must be positive"))
+				}
+			}
+			if len(version.Regions) == 0 && version.ImageName == nil {
+				allErrs = append(allErrs, field.Required(jdxPath.Child("regions"), fmt.Sprintf("must provide at least one region or an image name for machine image %q and version %q", machineImage.Name, version.Version)))
 			}
 			for k, region := range version.Regions {
 				kdxPath := jdxPath.Child("regions").Index(k)
@@ -59,8 +88,87 @@ func ValidateCloudProfileConfig(cloudProfile *apisalicloud.CloudProfileConfig) f
 					allErrs = append(allErrs, field.Required(kdxPath.Child("id"), "must provide an id"))
 				}
 			}
+
+			allErrs = append(allErrs, validateMachineImageClassification(machineImage, version, jdxPath)...)
+		}
+	}
+
+	allErrs = append(allErrs, validateDefaultMachineImages(cloudProfile, field.NewPath("defaultMachineImages"))...)
+
+	return allErrs
+}
+
+// validateDefaultMachineImages validates that every region has at most one default machine image, which has to be a
+// version of the machine images of the given CloudProfileConfig that is available in the region.
+func validateDefaultMachineImages(cloudProfile *apisalicloud.CloudProfileConfig, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	regions := sets.NewString()
+	for i, defaultMachineImage := range cloudProfile.DefaultMachineImages {
+		idxPath := fldPath.Index(i)
+
+		if len(defaultMachineImage.Region) == 0 {
+			allErrs = append(allErrs, field.Required(idxPath.Child("region"), "must provide a region"))
+		} else if regions.Has(defaultMachineImage.Region) {
+			allErrs = append(allErrs, field.Duplicate(idxPath.Child("region"), defaultMachineImage.Region))
+		}
+		regions.Insert(defaultMachineImage.Region)
+
+		if len(defaultMachineImage.Name) == 0 {
+			allErrs = append(allErrs, field.Required(idxPath.Child("name"), "must provide a name"))
+		}
+		if len(defaultMachineImage.Version) == 0 {
+			allErrs = append(allErrs, field.Required(idxPath.Child("version"), "must provide a version"))
+		}
+		if len(defaultMachineImage.Name) == 0 || len(defaultMachineImage.Version) == 0 {
+			continue
+		}
+
+		value := fmt.Sprintf("%s/%s", defaultMachineImage.Name, defaultMachineImage.Version)
+		if _, err := helper.FindImageForRegionFromCloudProfile(cloudProfile, defaultMachineImage.Name, defaultMachineImage.Version, defaultMachineImage.Region); err == nil {
+			continue
+		}
+		version, err := helper.FindMachineImageVersion(cloudProfile, defaultMachineImage.Name, defaultMachineImage.Version)
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(idxPath, value, "must be a version of the machine images"))
+		} else if version.ImageName == nil {
+			allErrs = append(allErrs, field.Invalid(idxPath, value, fmt.Sprintf("must be available in region %q", defaultMachineImage.Region)))
 		}
 	}
 
 	return allErrs
 }
+
+// validateMachineImageClassification validates the classification of the given version of the given machine image and
+// that only deprecated versions recommend a replacement, which has to be another version of the machine image that is
+// not deprecated itself.
+func validateMachineImageClassification(machineImage apisalicloud.MachineImages, version apisalicloud.MachineImageVersion, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if version.Classification != nil && !availableMachineImageClassifications.Has(string(*version.Classification)) {
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("classification"), *version.Classification, availableMachineImageClassifications.List()))
+	}
+
+	if version.Replacement == nil {
+		return allErrs
+	}
+
+	replacementPath := fldPath.Child("replacement")
+	if !isDeprecated(version) {
+		return append(allErrs, field.Forbidden(replacementPath, "can only be specified for deprecated versions"))
+	}
+	for _, replacement := range machineImage.Versions {
+		if replacement.Version != *version.Replacement {
+			continue
+		}
+		if isDeprecated(replacement) {
+			allErrs = append(allErrs, field.Invalid(replacementPath, *version.Replacement, "must not be deprecated itself"))
+		}
+		return allErrs
+	}
+	return append(allErrs, field.Invalid(replacementPath, *version.Replacement, fmt.Sprintf("must be a version of machine image %q", machineImage.Name)))
+}
+
+func isDeprecated(version apisalicloud.MachineImageVersion) bool {
+	return version.Classification != nil && *version.Classification == apisalicloud.ClassificationDeprecated
+}
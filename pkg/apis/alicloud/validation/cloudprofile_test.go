@@ -117,6 +117,177 @@ var _ = Describe("CloudProfileConfig validation", func() {
 					"Field": Equal("machineImages[0].versions[0].regions[0].id"),
 				}))))
 			})
+
+			It("should allow an image name instead of region mappings", func() {
+				imageName := "ubuntu_18_04_64_20G_alibase_20190624.vhd"
+				cloudProfileConfig.MachineImages[0].Versions[0].Regions = nil
+				cloudProfileConfig.MachineImages[0].Versions[0].ImageName = &imageName
+
+				Expect(ValidateCloudProfileConfig(cloudProfileConfig)).To(BeEmpty())
+			})
+
+			It("should forbid an empty image name", func() {
+				imageName := ""
+				cloudProfileConfig.MachineImages[0].Versions[0].ImageName = &imageName
+
+				errorList := ValidateCloudProfileConfig(cloudProfileConfig)
+
+				Expect(errorList).To(ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeInvalid),
+					"Field": Equal("machineImages[0].versions[0].imageName"),
+				}))))
+			})
+
+			It("should forbid invalid minimum volume sizes", func() {
+				invalid, negative := "foo", "-10Gi"
+				cloudProfileConfig.MachineImages[0].Versions[0].MinimumVolumeSize = &invalid
+				cloudProfileConfig.MachineImages[0].Versions = append(cloudProfileConfig.MachineImages[0].Versions, apisalicloud.MachineImageVersion{
+					Version:           "1.2.4",
+					Regions:           []apisalicloud.RegionIDMapping{{Name: "china", ID: "other-image-id"}},
+					MinimumVolumeSize: &negative,
+				})
+
+				errorList := ValidateCloudProfileConfig(cloudProfileConfig)
+
+				Expect(errorList).To(ConsistOf(
+					PointTo(MatchFields(IgnoreExtras, Fields{
+						"Type":  Equal(field.ErrorTypeInvalid),
+						"Field": Equal("machineImages[0].versions[0].minimumVolumeSize"),
+					})),
+					PointTo(MatchFields(IgnoreExtras, Fields{
+						"Type":  Equal(field.ErrorTypeInvalid),
+						"Field": Equal("machineImages[0].versions[1].minimumVolumeSize"),
+					})),
+				))
+			})
+
+			It("should allow a deprecated version with a replacement", func() {
+				deprecated, supported := apisalicloud.ClassificationDeprecated, apisalicloud.ClassificationSupported
+				replacement := "1.2.4"
+				cloudProfileConfig.MachineImages[0].Versions[0].Classification = &deprecated
+				cloudProfileConfig.MachineImages[0].Versions[0].Replacement = &replacement
+				cloudProfileConfig.MachineImages[0].Versions = append(cloudProfileConfig.MachineImages[0].Versions, apisalicloud.MachineImageVersion{
+					Version:        "1.2.4",
+					Regions:        []apisalicloud.RegionIDMapping{{Name: "china", ID: "other-image-id"}},
+					Classification: &supported,
+				})
+
+				Expect(ValidateCloudProfileConfig(cloudProfileConfig)).To(BeEmpty())
+			})
+
+			It("should forbid unsupported classifications and deprecation policies", func() {
+				classification := apisalicloud.MachineImageClassification("obsolete")
+				policy := apisalicloud.DeprecatedMachineImagePolicy("Ignore")
+				cloudProfileConfig.MachineImages[0].Versions[0].Classification = &classification
+				cloudProfileConfig.DeprecatedMachineImagePolicy = &policy
+
+				errorList := ValidateCloudProfileConfig(cloudProfileConfig)
+
+				Expect(errorList).To(ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeNotSupported),
+					"Field": Equal("deprecatedMachineImagePolicy"),
+				})), PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeNotSupported),
+					"Field": Equal("machineImages[0].versions[0].classification"),
+				}))))
+			})
+
+			It("should forbid a replacement for versions which are not deprecated", func() {
+				replacement := "1.2.4"
+				cloudProfileConfig.MachineImages[0].Versions[0].Replacement = &replacement
+
+				errorList := ValidateCloudProfileConfig(cloudProfileConfig)
+
+				Expect(errorList).To(ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeForbidden),
+					"Field": Equal("machineImages[0].versions[0].replacement"),
+				}))))
+			})
+
+			It("should forbid replacements which are unknown or deprecated themselves", func() {
+				deprecated := apisalicloud.ClassificationDeprecated
+				unknown, self := "2.0.0", "1.2.3"
+				cloudProfileConfig.MachineImages[0].Versions[0].Classification = &deprecated
+				cloudProfileConfig.MachineImages[0].Versions[0].Replacement = &unknown
+
+				errorList := ValidateCloudProfileConfig(cloudProfileConfig)
+				Expect(errorList).To(ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":   Equal(field.ErrorTypeInvalid),
+					"Field":  Equal("machineImages[0].versions[0].replacement"),
+					"Detail": Equal(`must be a version of machine image "ubuntu"`),
+				}))))
+
+				cloudProfileConfig.MachineImages[0].Versions[0].Replacement = &self
+				errorList = ValidateCloudProfileConfig(cloudProfileConfig)
+				Expect(errorList).To(ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":   Equal(field.ErrorTypeInvalid),
+					"Field":  Equal("machineImages[0].versions[0].replacement"),
+					"Detail": Equal("must not be deprecated itself"),
+				}))))
+			})
+		})
+
+		Context("default machine image validation", func() {
+			It("should allow default machine images available in their region", func() {
+				cloudProfileConfig.DefaultMachineImages = []apisalicloud.DefaultMachineImage{
+					{Region: "china", Name: "ubuntu", Version: "1.2.3"},
+				}
+
+				Expect(ValidateCloudProfileConfig(cloudProfileConfig)).To(BeEmpty())
+			})
+
+			It("should allow default machine images which can be looked up by their name", func() {
+				imageName := "ubuntu_1_2_3"
+				cloudProfileConfig.MachineImages[0].Versions[0].ImageName = &imageName
+				cloudProfileConfig.DefaultMachineImages = []apisalicloud.DefaultMachineImage{
+					{Region: "europe", Name: "ubuntu", Version: "1.2.3"},
+				}
+
+				Expect(ValidateCloudProfileConfig(cloudProfileConfig)).To(BeEmpty())
+			})
+
+			It("should forbid incomplete and duplicate default machine images", func() {
+				cloudProfileConfig.DefaultMachineImages = []apisalicloud.DefaultMachineImage{
+					{Region: "china", Name: "ubuntu", Version: "1.2.3"},
+					{Region: "china", Name: "ubuntu", Version: "1.2.3"},
+					{},
+				}
+
+				errorList := ValidateCloudProfileConfig(cloudProfileConfig)
+
+				Expect(errorList).To(ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeDuplicate),
+					"Field": Equal("defaultMachineImages[1].region"),
+				})), PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeRequired),
+					"Field": Equal("defaultMachineImages[2].region"),
+				})), PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeRequired),
+					"Field": Equal("defaultMachineImages[2].name"),
+				})), PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeRequired),
+					"Field": Equal("defaultMachineImages[2].version"),
+				}))))
+			})
+
+			It("should forbid default machine images which are unknown or not available in their region", func() {
+				cloudProfileConfig.DefaultMachineImages = []apisalicloud.DefaultMachineImage{
+					{Region: "china", Name: "ubuntu", Version: "2.0.0"},
+					{Region: "europe", Name: "ubuntu", Version: "1.2.3"},
+				}
+
+				errorList := ValidateCloudProfileConfig(cloudProfileConfig)
+
+				Expect(errorList).To(ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":   Equal(field.ErrorTypeInvalid),
+					"Field":  Equal("defaultMachineImages[0]"),
+					"Detail": Equal("must be a version of the machine images"),
+				})), PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":   Equal(field.ErrorTypeInvalid),
+					"Field":  Equal("defaultMachineImages[1]"),
+					"Detail": Equal(`must be available in region "europe"`),
+				}))))
+			})
 		})
 	})
 })
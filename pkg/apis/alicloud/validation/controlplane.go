@@ -15,13 +15,26 @@
 package validation
 
 import (
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+
+	"github.com/gardener/gardener-extension-provider-alicloud/pkg/alicloud"
 	apisalicloud "github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/alicloud"
 
 	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	"github.com/gardener/gardener/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
 	apivalidation "k8s.io/apimachinery/pkg/api/validation"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
+// defaultStorageClassName is the name of the storage class which is always managed by the extension.
+const defaultStorageClassName = "default"
+
 // ValidateControlPlaneConfig validates a ControlPlaneConfig object.
 func ValidateControlPlaneConfig(controlPlaneConfig *apisalicloud.ControlPlaneConfig, region string, regions []gardencorev1beta1.Region) field.ErrorList {
 	allErrs := field.ErrorList{}
@@ -32,6 +45,187 @@ func ValidateControlPlaneConfig(controlPlaneConfig *apisalicloud.ControlPlaneCon
 		allErrs = append(allErrs, field.NotSupported(field.NewPath("zone"), controlPlaneConfig.Zone, validZones))
 	}
 
+	if controlPlaneConfig.LoadBalancerDefaults != nil {
+		allErrs = append(allErrs, validateLoadBalancerDefaults(controlPlaneConfig.LoadBalancerDefaults, field.NewPath("loadBalancerDefaults"))...)
+	}
+
+	if controlPlaneConfig.APIServerLoadBalancer != nil {
+		allErrs = append(allErrs, validateAPIServerLoadBalancer(controlPlaneConfig.APIServerLoadBalancer, field.NewPath("apiServerLoadBalancer"))...)
+	}
+
+	allErrs = append(allErrs, validateStorageClasses(controlPlaneConfig.StorageClasses, field.NewPath("storageClasses"))...)
+
+	if ccm := controlPlaneConfig.CloudControllerManager; ccm != nil && ccm.Image != nil {
+		allErrs = append(allErrs, validateImageReference(*ccm.Image, field.NewPath("cloudControllerManager", "image"))...)
+	}
+	if csi := controlPlaneConfig.CSI; csi != nil {
+		imagesPath := field.NewPath("csi", "images")
+		for name, image := range csi.Images {
+			if !csiImageNames.Has(name) {
+				allErrs = append(allErrs, field.NotSupported(imagesPath.Key(name), name, csiImageNames.List()))
+				continue
+			}
+			allErrs = append(allErrs, validateImageReference(image, imagesPath.Key(name))...)
+		}
+	}
+
+	return allErrs
+}
+
+// ValidateControlPlaneConfigAgainstInfrastructure validates a ControlPlaneConfig object against the
+// InfrastructureConfig of the shoot. The intranet load balancer of the kube-apiserver lives in the VPC of the seed,
+// hence the nodes can only reach it from an existing VPC which is connected to the network of the seed, e.g. via CEN.
+func ValidateControlPlaneConfigAgainstInfrastructure(controlPlaneConfig *apisalicloud.ControlPlaneConfig, infra *apisalicloud.InfrastructureConfig) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if addressType := apiServerLoadBalancerAddressType(controlPlaneConfig); addressType == apisalicloud.LoadBalancerAddressTypeIntranet && infra.Networks.VPC.ID == nil {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("apiServerLoadBalancer", "addressType"), "an intranet load balancer can only be used together with an existing vpc id"))
+	}
+
+	return allErrs
+}
+
+var (
+	// imageReferenceRegex matches the image references which are pinned to a tag or digest, e.g.
+	// `registry.example.com:5000/mirror/csi-attacher:v2.1.0` or `mirror/csi-attacher@sha256:<digest>`. It follows the
+	// grammar of the references of the docker distribution.
+	imageReferenceRegex = regexp.MustCompile(`^(?:[a-zA-Z0-9](?:[a-zA-Z0-9-]*[a-zA-Z0-9])?(?:\.[a-zA-Z0-9](?:[a-zA-Z0-9-]*[a-zA-Z0-9])?)*(?::[0-9]+)?/)?` +
+		`[a-z0-9]+(?:(?:[._]|__|-+)[a-z0-9]+)*(?:/[a-z0-9]+(?:(?:[._]|__|-+)[a-z0-9]+)*)*` +
+		`(?::[a-zA-Z0-9_][a-zA-Z0-9_.-]{0,127})?(?:@sha256:[a-f0-9]{64})?$`)
+	// csiImageNames are the names of the images of the CSI driver which can be overridden.
+	csiImageNames = sets.NewString(
+		alicloud.CSIAttacherImageName,
+		alicloud.CSINodeDriverRegistrarImageName,
+		alicloud.CSIPluginImageName,
+		alicloud.CSIProvisionerImageName,
+		alicloud.CSIResizerImageName,
+		alicloud.CSISnapshotterImageName,
+	)
+)
+
+func validateImageReference(image string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if !imageReferenceRegex.MatchString(image) {
+		allErrs = append(allErrs, field.Invalid(fldPath, image, "must be a valid image reference"))
+	} else if !strings.Contains(image[strings.LastIndex(image, "/")+1:], ":") && !strings.Contains(image, "@") {
+		allErrs = append(allErrs, field.Invalid(fldPath, image, "must be pinned to a tag or digest"))
+	}
+
+	return allErrs
+}
+
+// validLoadBalancerAddressTypes are the address types supported by the SLB instances.
+var validLoadBalancerAddressTypes = []string{
+	string(apisalicloud.LoadBalancerAddressTypeInternet),
+	string(apisalicloud.LoadBalancerAddressTypeIntranet),
+}
+
+// validLoadBalancerChargeTypes are the charge types supported by the SLB instances.
+var validLoadBalancerChargeTypes = []string{"paybytraffic", "paybybandwidth"}
+
+func validateLoadBalancerDefaults(defaults *apisalicloud.LoadBalancerDefaults, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if defaults.Spec != nil && len(*defaults.Spec) == 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("spec"), *defaults.Spec, "must not be empty"))
+	}
+	if defaults.ChargeType != nil && !utils.ValueExists(*defaults.ChargeType, validLoadBalancerChargeTypes) {
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("chargeType"), *defaults.ChargeType, validLoadBalancerChargeTypes))
+	}
+	if defaults.Bandwidth != nil && (*defaults.Bandwidth < 1 || *defaults.Bandwidth > 5000) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("bandwidth"), *defaults.Bandwidth, "must be between 1 and 5000 Mbps"))
+	}
+
+	return allErrs
+}
+
+func validateAPIServerLoadBalancer(lb *apisalicloud.APIServerLoadBalancer, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if lb.AddressType != nil && !utils.ValueExists(string(*lb.AddressType), validLoadBalancerAddressTypes) {
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("addressType"), *lb.AddressType, validLoadBalancerAddressTypes))
+	}
+	// The ranges are the limits of the TCP listeners of the SLB instances.
+	if lb.IdleTimeout != nil && (*lb.IdleTimeout < 10 || *lb.IdleTimeout > 900) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("idleTimeout"), *lb.IdleTimeout, "must be between 10 and 900 seconds"))
+	}
+	if lb.ConnectionDrainTimeout != nil && (*lb.ConnectionDrainTimeout < 10 || *lb.ConnectionDrainTimeout > 900) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("connectionDrainTimeout"), *lb.ConnectionDrainTimeout, "must be between 10 and 900 seconds"))
+	}
+	if lb.HealthCheckInterval != nil && (*lb.HealthCheckInterval < 1 || *lb.HealthCheckInterval > 50) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("healthCheckInterval"), *lb.HealthCheckInterval, "must be between 1 and 50 seconds"))
+	}
+
+	allowedCIDRs := sets.NewString()
+	for i, cidr := range lb.AllowedCIDRs {
+		idxPath := fldPath.Child("allowedCIDRs").Index(i)
+		if ip, _, err := net.ParseCIDR(cidr); err != nil || ip.To4() == nil {
+			allErrs = append(allErrs, field.Invalid(idxPath, cidr, "must be a valid IPv4 CIDR"))
+		} else if allowedCIDRs.Has(cidr) {
+			allErrs = append(allErrs, field.Duplicate(idxPath, cidr))
+		}
+		allowedCIDRs.Insert(cidr)
+	}
+
+	return allErrs
+}
+
+var (
+	// storageClassDiskCategories are the disk categories which can be provisioned by the Alicloud disk driver.
+	storageClassDiskCategories = sets.NewString("cloud_efficiency", "cloud_ssd", "cloud_essd")
+	// storageClassReclaimPolicies are the reclaim policies supported for the volumes of the Alicloud disk driver.
+	storageClassReclaimPolicies = sets.NewString(string(corev1.PersistentVolumeReclaimDelete), string(corev1.PersistentVolumeReclaimRetain))
+)
+
+func validateStorageClasses(storageClasses []apisalicloud.StorageClass, fldPath *field.Path) field.ErrorList {
+	var (
+		allErrs  = field.ErrorList{}
+		names    = sets.NewString()
+		defaults int
+	)
+
+	for i, storageClass := range storageClasses {
+		idxPath := fldPath.Index(i)
+
+		if len(storageClass.Name) == 0 {
+			allErrs = append(allErrs, field.Required(idxPath.Child("name"), "must provide a name"))
+		} else {
+			for _, msg := range validation.IsDNS1123Subdomain(storageClass.Name) {
+				allErrs = append(allErrs, field.Invalid(idxPath.Child("name"), storageClass.Name, msg))
+			}
+			if storageClass.Name == defaultStorageClassName {
+				allErrs = append(allErrs, field.Forbidden(idxPath.Child("name"), fmt.Sprintf("%q is the name of the storage class managed by default", defaultStorageClassName)))
+			} else if names.Has(storageClass.Name) {
+				allErrs = append(allErrs, field.Duplicate(idxPath.Child("name"), storageClass.Name))
+			}
+			names.Insert(storageClass.Name)
+		}
+
+		if storageClass.Default {
+			if defaults++; defaults > 1 {
+				allErrs = append(allErrs, field.Forbidden(idxPath.Child("default"), "only one storage class can be the default storage class"))
+			}
+		}
+
+		if storageClass.Type != nil && !storageClassDiskCategories.Has(*storageClass.Type) {
+			allErrs = append(allErrs, field.NotSupported(idxPath.Child("type"), *storageClass.Type, storageClassDiskCategories.List()))
+		}
+
+		if storageClass.ReclaimPolicy != nil && !storageClassReclaimPolicies.Has(string(*storageClass.ReclaimPolicy)) {
+			allErrs = append(allErrs, field.NotSupported(idxPath.Child("reclaimPolicy"), *storageClass.ReclaimPolicy, storageClassReclaimPolicies.List()))
+		}
+
+		if storageClass.PerformanceLevel != nil {
+			performanceLevelPath := idxPath.Child("performanceLevel")
+			if storageClass.Type == nil || !essdDiskCategories.Has(*storageClass.Type) {
+				allErrs = append(allErrs, field.Forbidden(performanceLevelPath, fmt.Sprintf("must only be set for the disk categories %v", essdDiskCategories.List())))
+			} else if !performanceLevels.Has(*storageClass.PerformanceLevel) {
+				allErrs = append(allErrs, field.NotSupported(performanceLevelPath, *storageClass.PerformanceLevel, performanceLevels.List()))
+			}
+		}
+	}
+
 	return allErrs
 }
 
@@ -40,10 +234,21 @@ func ValidateControlPlaneConfigUpdate(oldConfig, newConfig *apisalicloud.Control
 	allErrs := field.ErrorList{}
 
 	allErrs = append(allErrs, apivalidation.ValidateImmutableField(newConfig.Zone, oldConfig.Zone, field.NewPath("zone"))...)
+	// Alicloud cannot change the address type of an existing SLB instance.
+	allErrs = append(allErrs, apivalidation.ValidateImmutableField(apiServerLoadBalancerAddressType(newConfig), apiServerLoadBalancerAddressType(oldConfig), field.NewPath("apiServerLoadBalancer", "addressType"))...)
 
 	return allErrs
 }
 
+// apiServerLoadBalancerAddressType returns the address type of the load balancer of the kube-apiserver, which defaults
+// to internet.
+func apiServerLoadBalancerAddressType(controlPlaneConfig *apisalicloud.ControlPlaneConfig) apisalicloud.LoadBalancerAddressType {
+	if lb := controlPlaneConfig.APIServerLoadBalancer; lb != nil && lb.AddressType != nil {
+		return *lb.AddressType
+	}
+	return apisalicloud.LoadBalancerAddressTypeInternet
+}
+
 func validateZoneConstraints(regions []gardencorev1beta1.Region, region, zone, oldZone string) (bool, []string) {
 	if zone == oldZone {
 		return true, nil
@@ -15,6 +15,8 @@
 package validation_test
 
 import (
+	"strings"
+
 	apisalicloud "github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/alicloud"
 	. "github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/alicloud/validation"
 
@@ -22,7 +24,9 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	. "github.com/onsi/gomega/gstruct"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/pointer"
 )
 
 var _ = Describe("ControlPlaneConfig validation", func() {
@@ -74,6 +78,301 @@ var _ = Describe("ControlPlaneConfig validation", func() {
 				"Field": Equal("zone"),
 			}))))
 		})
+
+		It("should accept valid load balancer defaults", func() {
+			controlPlane.LoadBalancerDefaults = &apisalicloud.LoadBalancerDefaults{
+				Spec:       pointer.StringPtr("slb.s1.small"),
+				ChargeType: pointer.StringPtr("paybybandwidth"),
+				Bandwidth:  pointer.Int32Ptr(100),
+			}
+
+			Expect(ValidateControlPlaneConfig(controlPlane, region, regions)).To(BeEmpty())
+		})
+
+		It("should forbid invalid load balancer defaults", func() {
+			controlPlane.LoadBalancerDefaults = &apisalicloud.LoadBalancerDefaults{
+				Spec:       pointer.StringPtr(""),
+				ChargeType: pointer.StringPtr("prepaid"),
+				Bandwidth:  pointer.Int32Ptr(0),
+			}
+
+			errorList := ValidateControlPlaneConfig(controlPlane, region, regions)
+
+			Expect(errorList).To(ConsistOf(
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeInvalid),
+					"Field": Equal("loadBalancerDefaults.spec"),
+				})),
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeNotSupported),
+					"Field": Equal("loadBalancerDefaults.chargeType"),
+				})),
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeInvalid),
+					"Field": Equal("loadBalancerDefaults.bandwidth"),
+				})),
+			))
+		})
+
+		It("should forbid unsupported address types of the kube-apiserver load balancer", func() {
+			addressType := apisalicloud.LoadBalancerAddressType("private")
+			controlPlane.APIServerLoadBalancer = &apisalicloud.APIServerLoadBalancer{AddressType: &addressType}
+
+			errorList := ValidateControlPlaneConfig(controlPlane, region, regions)
+
+			Expect(errorList).To(ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
+				"Type":  Equal(field.ErrorTypeNotSupported),
+				"Field": Equal("apiServerLoadBalancer.addressType"),
+			}))))
+		})
+
+		It("should accept valid listener settings of the kube-apiserver load balancer", func() {
+			controlPlane.APIServerLoadBalancer = &apisalicloud.APIServerLoadBalancer{
+				IdleTimeout:            pointer.Int32Ptr(900),
+				ConnectionDrainTimeout: pointer.Int32Ptr(10),
+				HealthCheckInterval:    pointer.Int32Ptr(5),
+			}
+
+			Expect(ValidateControlPlaneConfig(controlPlane, region, regions)).To(BeEmpty())
+		})
+
+		It("should forbid listener settings of the kube-apiserver load balancer outside of the Alicloud limits", func() {
+			controlPlane.APIServerLoadBalancer = &apisalicloud.APIServerLoadBalancer{
+				IdleTimeout:            pointer.Int32Ptr(3600),
+				ConnectionDrainTimeout: pointer.Int32Ptr(5),
+				HealthCheckInterval:    pointer.Int32Ptr(0),
+			}
+
+			errorList := ValidateControlPlaneConfig(controlPlane, region, regions)
+
+			Expect(errorList).To(ConsistOf(
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeInvalid),
+					"Field": Equal("apiServerLoadBalancer.idleTimeout"),
+				})),
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeInvalid),
+					"Field": Equal("apiServerLoadBalancer.connectionDrainTimeout"),
+				})),
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeInvalid),
+					"Field": Equal("apiServerLoadBalancer.healthCheckInterval"),
+				})),
+			))
+		})
+
+		It("should accept allowed CIDRs of the kube-apiserver load balancer", func() {
+			controlPlane.APIServerLoadBalancer = &apisalicloud.APIServerLoadBalancer{
+				AllowedCIDRs: []string{"10.0.0.0/8", "192.168.1.1/32"},
+			}
+
+			Expect(ValidateControlPlaneConfig(controlPlane, region, regions)).To(BeEmpty())
+		})
+
+		It("should forbid invalid and duplicate allowed CIDRs of the kube-apiserver load balancer", func() {
+			controlPlane.APIServerLoadBalancer = &apisalicloud.APIServerLoadBalancer{
+				AllowedCIDRs: []string{"10.0.0.0/8", "10.0.0.0", "2001:db8::/32", "10.0.0.0/8"},
+			}
+
+			errorList := ValidateControlPlaneConfig(controlPlane, region, regions)
+
+			Expect(errorList).To(ConsistOf(
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeInvalid),
+					"Field": Equal("apiServerLoadBalancer.allowedCIDRs[1]"),
+				})),
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeInvalid),
+					"Field": Equal("apiServerLoadBalancer.allowedCIDRs[2]"),
+				})),
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeDuplicate),
+					"Field": Equal("apiServerLoadBalancer.allowedCIDRs[3]"),
+				})),
+			))
+		})
+
+		It("should accept valid storage classes", func() {
+			retain := corev1.PersistentVolumeReclaimRetain
+			controlPlane.StorageClasses = []apisalicloud.StorageClass{
+				{Name: "efficiency", Type: pointer.StringPtr("cloud_efficiency")},
+				{Name: "essd-pl1", Type: pointer.StringPtr("cloud_essd"), PerformanceLevel: pointer.StringPtr("PL1"), Default: true},
+				{Name: "ssd"},
+				{Name: "ssd-retain", ReclaimPolicy: &retain},
+			}
+
+			Expect(ValidateControlPlaneConfig(controlPlane, region, regions)).To(BeEmpty())
+		})
+
+		It("should forbid unsupported reclaim policies", func() {
+			recycle := corev1.PersistentVolumeReclaimRecycle
+			controlPlane.StorageClasses = []apisalicloud.StorageClass{{Name: "ssd", ReclaimPolicy: &recycle}}
+
+			errorList := ValidateControlPlaneConfig(controlPlane, region, regions)
+
+			Expect(errorList).To(ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
+				"Type":  Equal(field.ErrorTypeNotSupported),
+				"Field": Equal("storageClasses[0].reclaimPolicy"),
+			}))))
+		})
+
+		It("should forbid invalid storage class names", func() {
+			controlPlane.StorageClasses = []apisalicloud.StorageClass{
+				{Name: ""},
+				{Name: "Foo_Bar"},
+				{Name: "default"},
+				{Name: "ssd"},
+				{Name: "ssd"},
+			}
+
+			errorList := ValidateControlPlaneConfig(controlPlane, region, regions)
+
+			Expect(errorList).To(ConsistOf(
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeRequired),
+					"Field": Equal("storageClasses[0].name"),
+				})),
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeInvalid),
+					"Field": Equal("storageClasses[1].name"),
+				})),
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeForbidden),
+					"Field": Equal("storageClasses[2].name"),
+				})),
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeDuplicate),
+					"Field": Equal("storageClasses[4].name"),
+				})),
+			))
+		})
+
+		It("should forbid more than one default storage class", func() {
+			controlPlane.StorageClasses = []apisalicloud.StorageClass{
+				{Name: "ssd", Default: true},
+				{Name: "essd", Type: pointer.StringPtr("cloud_essd"), Default: true},
+			}
+
+			errorList := ValidateControlPlaneConfig(controlPlane, region, regions)
+
+			Expect(errorList).To(ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
+				"Type":  Equal(field.ErrorTypeForbidden),
+				"Field": Equal("storageClasses[1].default"),
+			}))))
+		})
+
+		It("should forbid invalid disk categories and performance levels", func() {
+			controlPlane.StorageClasses = []apisalicloud.StorageClass{
+				{Name: "local", Type: pointer.StringPtr("local_ssd")},
+				{Name: "ssd-pl1", Type: pointer.StringPtr("cloud_ssd"), PerformanceLevel: pointer.StringPtr("PL1")},
+				{Name: "essd-pl9", Type: pointer.StringPtr("cloud_essd"), PerformanceLevel: pointer.StringPtr("PL9")},
+			}
+
+			errorList := ValidateControlPlaneConfig(controlPlane, region, regions)
+
+			Expect(errorList).To(ConsistOf(
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeNotSupported),
+					"Field": Equal("storageClasses[0].type"),
+				})),
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeForbidden),
+					"Field": Equal("storageClasses[1].performanceLevel"),
+				})),
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeNotSupported),
+					"Field": Equal("storageClasses[2].performanceLevel"),
+				})),
+			))
+		})
+
+		It("should accept image overrides which are pinned to a tag or digest", func() {
+			controlPlane.CloudControllerManager = &apisalicloud.CloudControllerManagerConfig{
+				Image: pointer.StringPtr("registry.example.com:5000/mirror/alicloud-controller-manager:v1.9.3"),
+			}
+			controlPlane.CSI = &apisalicloud.CSIConfig{
+				Images: map[string]string{
+					"csi-attacher":        "mirror/csi-attacher:v2.1.0",
+					"csi-plugin-alicloud": "mirror/csi-plugin@sha256:" + strings.Repeat("a", 64),
+				},
+			}
+
+			Expect(ValidateControlPlaneConfig(controlPlane, region, regions)).To(BeEmpty())
+		})
+
+		It("should forbid invalid image overrides", func() {
+			controlPlane.CloudControllerManager = &apisalicloud.CloudControllerManagerConfig{
+				Image: pointer.StringPtr("registry.example.com:5000/mirror/alicloud-controller-manager"),
+			}
+			controlPlane.CSI = &apisalicloud.CSIConfig{
+				Images: map[string]string{
+					"csi-attacher":    "mirror/CSI-attacher:v2.1.0",
+					"csi-unknown":     "mirror/csi-unknown:v1.0.0",
+					"csi-provisioner": "mirror/csi-provisioner:",
+				},
+			}
+
+			errorList := ValidateControlPlaneConfig(controlPlane, region, regions)
+
+			Expect(errorList).To(ConsistOf(
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":   Equal(field.ErrorTypeInvalid),
+					"Field":  Equal("cloudControllerManager.image"),
+					"Detail": Equal("must be pinned to a tag or digest"),
+				})),
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":   Equal(field.ErrorTypeInvalid),
+					"Field":  Equal("csi.images[csi-attacher]"),
+					"Detail": Equal("must be a valid image reference"),
+				})),
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeNotSupported),
+					"Field": Equal("csi.images[csi-unknown]"),
+				})),
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeInvalid),
+					"Field": Equal("csi.images[csi-provisioner]"),
+				})),
+			))
+		})
+	})
+
+	Describe("#ValidateControlPlaneConfigAgainstInfrastructure", func() {
+		var (
+			intranet = apisalicloud.LoadBalancerAddressTypeIntranet
+			infra    *apisalicloud.InfrastructureConfig
+		)
+
+		BeforeEach(func() {
+			controlPlane.APIServerLoadBalancer = &apisalicloud.APIServerLoadBalancer{AddressType: &intranet}
+			infra = &apisalicloud.InfrastructureConfig{
+				Networks: apisalicloud.Networks{
+					VPC: apisalicloud.VPC{ID: pointer.StringPtr("vpc-123")},
+				},
+			}
+		})
+
+		It("should allow an intranet load balancer together with an existing VPC", func() {
+			Expect(ValidateControlPlaneConfigAgainstInfrastructure(controlPlane, infra)).To(BeEmpty())
+		})
+
+		It("should forbid an intranet load balancer together with a new VPC", func() {
+			infra.Networks.VPC = apisalicloud.VPC{CIDR: pointer.StringPtr("10.250.0.0/16")}
+
+			errorList := ValidateControlPlaneConfigAgainstInfrastructure(controlPlane, infra)
+
+			Expect(errorList).To(ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
+				"Type":  Equal(field.ErrorTypeForbidden),
+				"Field": Equal("apiServerLoadBalancer.addressType"),
+			}))))
+		})
+
+		It("should allow an internet-facing load balancer together with a new VPC", func() {
+			controlPlane.APIServerLoadBalancer = nil
+			infra.Networks.VPC = apisalicloud.VPC{CIDR: pointer.StringPtr("10.250.0.0/16")}
+
+			Expect(ValidateControlPlaneConfigAgainstInfrastructure(controlPlane, infra)).To(BeEmpty())
+		})
 	})
 
 	Describe("#ValidateControlPlaneConfigUpdate", func() {
@@ -92,5 +391,18 @@ var _ = Describe("ControlPlaneConfig validation", func() {
 				"Field": Equal("zone"),
 			}))))
 		})
+
+		It("should forbid changing the address type of the kube-apiserver load balancer", func() {
+			intranet := apisalicloud.LoadBalancerAddressTypeIntranet
+			newControlPlane := controlPlane.DeepCopy()
+			newControlPlane.APIServerLoadBalancer = &apisalicloud.APIServerLoadBalancer{AddressType: &intranet}
+
+			errorList := ValidateControlPlaneConfigUpdate(controlPlane, newControlPlane, region, regions)
+
+			Expect(errorList).To(ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
+				"Type":  Equal(field.ErrorTypeInvalid),
+				"Field": Equal("apiServerLoadBalancer.addressType"),
+			}))))
+		})
 	})
 })
@@ -15,13 +15,102 @@
 package validation
 
 import (
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gardener/gardener-extension-provider-alicloud/pkg/alicloud"
 	apisalicloud "github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/alicloud"
 
 	cidrvalidation "github.com/gardener/gardener/pkg/utils/validation/cidr"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	apivalidation "k8s.io/apimachinery/pkg/api/validation"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
+const (
+	minEIPBandwidth = 1
+	maxEIPBandwidth = 500
+	// maxEIPsPerNATGateway is the maximum number of EIPs which can be associated with a NAT gateway.
+	maxEIPsPerNATGateway = 20
+
+	maxTagKeyLength   = 128
+	maxTagValueLength = 128
+
+	// vswitchReservedAddresses is the number of addresses of a vswitch which Alicloud reserves, i.e., the first and
+	// the last three addresses of its CIDR.
+	vswitchReservedAddresses = 4
+
+	// maxDomainNameServers is the maximum number of DNS servers of a DHCP options set.
+	maxDomainNameServers = 4
+
+	// maxDualStackZones and maxDualStackAdditionalWorkers are the maximum numbers of zones and of additional vswitches
+	// per zone with dual-stack, as the vswitches get the IPv6 CIDR masks 0-255 of the VPC in fixed blocks per zone.
+	maxDualStackZones             = 15
+	maxDualStackAdditionalWorkers = 16
+)
+
+// securityGroupRuleDirections are the supported directions of security group rules.
+var securityGroupRuleDirections = sets.NewString(
+	string(apisalicloud.SecurityGroupRuleDirectionIngress),
+	string(apisalicloud.SecurityGroupRuleDirectionEgress),
+)
+
+// securityGroupRuleProtocols are the supported protocols of security group rules.
+var securityGroupRuleProtocols = sets.NewString("tcp", "udp", "icmp", "gre", "all")
+
+// securityGroupRuleProtocolsWithPorts are the protocols of security group rules which require a port range.
+var securityGroupRuleProtocolsWithPorts = sets.NewString("tcp", "udp")
+
+// dnatEntryProtocols are the supported protocols of DNAT entries.
+var dnatEntryProtocols = sets.NewString("tcp", "udp")
+
+// routeNextHopIDPrefixes maps the supported next hop types of routes to the prefixes of the IDs of the next hops.
+var routeNextHopIDPrefixes = map[apisalicloud.RouteNextHopType]string{
+	apisalicloud.RouteNextHopTypeInstance:         "i-",
+	apisalicloud.RouteNextHopTypeHaVip:            "havip-",
+	apisalicloud.RouteNextHopTypeNetworkInterface: "eni-",
+	apisalicloud.RouteNextHopTypeRouterInterface:  "ri-",
+	apisalicloud.RouteNextHopTypeVpnGateway:       "vpn-",
+}
+
+// reservedTagKeys are the tag keys which are set by the extension itself.
+var reservedTagKeys = sets.NewString(alicloud.TagKeyShootName, alicloud.TagKeyProjectName, alicloud.TagKeyShootUID)
+
+// reservedTagPrefixes are the prefixes Alicloud does not allow for tag keys and values.
+var reservedTagPrefixes = []string{"aliyun", "acs:"}
+
+// eipInternetChargeTypes are the supported billing methods of EIPs.
+var eipInternetChargeTypes = sets.NewString("PayByTraffic", "PayByBandwidth")
+
+// slsProjectNameRegexp matches the names of Log Service (SLS) projects.
+var slsProjectNameRegexp = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{1,61}[a-z0-9]$`)
+
+// slsLogStoreNameRegexp matches the names of Log Service (SLS) logstores.
+var slsLogStoreNameRegexp = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{1,61}[a-z0-9]$`)
+
+// ipv6Regions are the Alicloud regions which support IPv6 in VPCs.
+var ipv6Regions = sets.NewString(
+	"cn-qingdao",
+	"cn-beijing",
+	"cn-zhangjiakou",
+	"cn-huhehaote",
+	"cn-hangzhou",
+	"cn-shanghai",
+	"cn-shenzhen",
+	"cn-chengdu",
+	"cn-hongkong",
+	"ap-southeast-1",
+	"us-east-1",
+	"us-west-1",
+	"eu-central-1",
+	"eu-west-1",
+)
+
 // ValidateInfrastructureConfig validates a InfrastructureConfig object.
 func ValidateInfrastructureConfig(infra *apisalicloud.InfrastructureConfig, nodesCIDR, podsCIDR, servicesCIDR *string) field.ErrorList {
 	allErrs := field.ErrorList{}
@@ -48,11 +137,24 @@ func ValidateInfrastructureConfig(infra *apisalicloud.InfrastructureConfig, node
 	}
 
 	var (
-		cidrs       = make([]cidrvalidation.CIDR, 0, len(infra.Networks.Zones))
-		workerCIDRs = make([]cidrvalidation.CIDR, 0, len(infra.Networks.Zones))
+		cidrs         = make([]cidrvalidation.CIDR, 0, len(infra.Networks.Zones))
+		workerCIDRs   = make([]cidrvalidation.CIDR, 0, len(infra.Networks.Zones))
+		podsCIDRs     = make([]cidrvalidation.CIDR, 0, len(infra.Networks.Zones))
+		zonePodsCIDRs int
 	)
 
+	dualStack := infra.Networks.DualStack != nil && infra.Networks.DualStack.Enabled
+	if dualStack && len(infra.Networks.Zones) > maxDualStackZones {
+		allErrs = append(allErrs, field.TooMany(networksPath.Child("zones"), len(infra.Networks.Zones), maxDualStackZones))
+	}
+
 	for i, zone := range infra.Networks.Zones {
+		zoneWorkerCIDRs := len(workerCIDRs)
+
+		if dualStack && len(zone.AdditionalWorkers) > maxDualStackAdditionalWorkers {
+			allErrs = append(allErrs, field.TooMany(networksPath.Child("zones").Index(i).Child("additionalWorkers"), len(zone.AdditionalWorkers), maxDualStackAdditionalWorkers))
+		}
+
 		if zone.Worker != "" {
 			workerPath := networksPath.Child("zones").Index(i).Child("worker")
 			cidrs = append(cidrs, cidrvalidation.NewCIDR(zone.Worker, workerPath))
@@ -66,6 +168,39 @@ func ValidateInfrastructureConfig(infra *apisalicloud.InfrastructureConfig, node
 			allErrs = append(allErrs, cidrvalidation.ValidateCIDRIsCanonical(workerPath, zone.Workers)...)
 			workerCIDRs = append(workerCIDRs, cidrvalidation.NewCIDR(zone.Workers, workerPath))
 		}
+
+		for j, additionalWorkers := range zone.AdditionalWorkers {
+			workerPath := networksPath.Child("zones").Index(i).Child("additionalWorkers").Index(j)
+			cidrs = append(cidrs, cidrvalidation.NewCIDR(additionalWorkers, workerPath))
+			allErrs = append(allErrs, cidrvalidation.ValidateCIDRIsCanonical(workerPath, additionalWorkers)...)
+			workerCIDRs = append(workerCIDRs, cidrvalidation.NewCIDR(additionalWorkers, workerPath))
+		}
+
+		// The vswitch of the NAT gateway is not used by the workers, hence its CIDR is not checked against the nodes CIDR.
+		if zone.NatGatewayCIDR != nil {
+			natGatewayCIDRPath := networksPath.Child("zones").Index(i).Child("natGatewayCIDR")
+			cidrs = append(cidrs, cidrvalidation.NewCIDR(*zone.NatGatewayCIDR, natGatewayCIDRPath))
+			allErrs = append(allErrs, cidrvalidation.ValidateCIDRIsCanonical(natGatewayCIDRPath, *zone.NatGatewayCIDR)...)
+			if infra.Networks.NatGateway == nil || !infra.Networks.NatGateway.PerZone {
+				allErrs = append(allErrs, field.Forbidden(natGatewayCIDRPath, "can only be specified together with NAT gateways per zone"))
+			}
+		}
+
+		// The vswitch of the pods is not used by the nodes but its addresses are handed out to the pods, hence its CIDR
+		// is checked against the pods CIDR instead of the nodes CIDR.
+		if zone.PodsCIDR != nil {
+			zonePodsCIDRs++
+			podsCIDRPath := networksPath.Child("zones").Index(i).Child("podsCIDR")
+			podsCIDR := cidrvalidation.NewCIDR(*zone.PodsCIDR, podsCIDRPath)
+			cidrs = append(cidrs, podsCIDR)
+			podsCIDRs = append(podsCIDRs, podsCIDR)
+			allErrs = append(allErrs, cidrvalidation.ValidateCIDRIsCanonical(podsCIDRPath, *zone.PodsCIDR)...)
+			allErrs = append(allErrs, validatePodsVSwitchSize(podsCIDR, workerCIDRs[zoneWorkerCIDRs:], podsCIDRPath)...)
+		}
+	}
+
+	if zonePodsCIDRs > 0 && zonePodsCIDRs < len(infra.Networks.Zones) {
+		allErrs = append(allErrs, field.Invalid(networksPath.Child("zones"), zonePodsCIDRs, "must specify a pods cidr either for all zones or for none"))
 	}
 
 	allErrs = append(allErrs, cidrvalidation.ValidateCIDRParse(cidrs...)...)
@@ -74,6 +209,21 @@ func ValidateInfrastructureConfig(infra *apisalicloud.InfrastructureConfig, node
 		allErrs = append(allErrs, nodes.ValidateSubset(workerCIDRs...)...)
 	}
 
+	// If the pods get their addresses from dedicated vswitches then the pods CIDR is part of the VPC, hence it must
+	// contain the vswitches of the pods and must not overlap with any other vswitch.
+	nonPodsCIDRs := cidrs
+	if len(podsCIDRs) > 0 {
+		nonPodsCIDRs = make([]cidrvalidation.CIDR, 0, len(cidrs)-len(podsCIDRs))
+		for _, cidr := range cidrs {
+			if !containsCIDR(podsCIDRs, cidr) {
+				nonPodsCIDRs = append(nonPodsCIDRs, cidr)
+			}
+		}
+		if pods != nil {
+			allErrs = append(allErrs, pods.ValidateSubset(podsCIDRs...)...)
+		}
+	}
+
 	if (infra.Networks.VPC.ID == nil && infra.Networks.VPC.CIDR == nil) || (infra.Networks.VPC.ID != nil && infra.Networks.VPC.CIDR != nil) {
 		allErrs = append(allErrs, field.Invalid(networksPath.Child("vpc"), infra.Networks.VPC, "must specify either a vpc id or a cidr"))
 	} else if infra.Networks.VPC.CIDR != nil && infra.Networks.VPC.ID == nil {
@@ -83,21 +233,446 @@ func ValidateInfrastructureConfig(infra *apisalicloud.InfrastructureConfig, node
 		allErrs = append(allErrs, vpcCIDR.ValidateParse()...)
 		allErrs = append(allErrs, vpcCIDR.ValidateSubset(nodes)...)
 		allErrs = append(allErrs, vpcCIDR.ValidateSubset(cidrs...)...)
-		allErrs = append(allErrs, vpcCIDR.ValidateNotSubset(pods, services)...)
+		if len(podsCIDRs) > 0 {
+			allErrs = append(allErrs, vpcCIDR.ValidateNotSubset(services)...)
+		} else {
+			allErrs = append(allErrs, vpcCIDR.ValidateNotSubset(pods, services)...)
+		}
+	}
+
+	if infra.Networks.NatGateway != nil && infra.Networks.NatGateway.ID != nil {
+		natGatewayIDPath := networksPath.Child("natGateway", "id")
+		if len(*infra.Networks.NatGateway.ID) == 0 {
+			allErrs = append(allErrs, field.Invalid(natGatewayIDPath, *infra.Networks.NatGateway.ID, "must not be empty"))
+		}
+		if infra.Networks.VPC.ID == nil {
+			allErrs = append(allErrs, field.Forbidden(natGatewayIDPath, "can only be specified together with an existing vpc id"))
+		}
+	}
+
+	if infra.Networks.NatGateway != nil && infra.Networks.NatGateway.PerZone {
+		perZonePath := networksPath.Child("natGateway", "perZone")
+		if infra.Networks.VPC.ID != nil {
+			allErrs = append(allErrs, field.Forbidden(perZonePath, "can only be specified together with a new vpc"))
+		}
+		if len(infra.Networks.Routes) > 0 {
+			allErrs = append(allErrs, field.Forbidden(perZonePath, "cannot be specified together with custom routes"))
+		}
+	}
+
+	if infra.Networks.NatGateway != nil && infra.Networks.NatGateway.EIPAllocationID != nil {
+		eipAllocationIDPath := networksPath.Child("natGateway", "eipAllocationID")
+		if !strings.HasPrefix(*infra.Networks.NatGateway.EIPAllocationID, "eip-") {
+			allErrs = append(allErrs, field.Invalid(eipAllocationIDPath, *infra.Networks.NatGateway.EIPAllocationID, "must be the allocation id of an EIP"))
+		}
+		if infra.Networks.NatGateway.PerZone {
+			allErrs = append(allErrs, field.Forbidden(eipAllocationIDPath, "cannot be specified together with NAT gateways per zone"))
+		}
+		if infra.Networks.NatGateway.EIPAllocation != nil {
+			allErrs = append(allErrs, field.Forbidden(eipAllocationIDPath, "cannot be specified together with eipAllocation"))
+		}
+	}
+
+	if infra.Networks.RouteTableID != nil {
+		routeTableIDPath := networksPath.Child("routeTableID")
+		if !strings.HasPrefix(*infra.Networks.RouteTableID, "vtb-") {
+			allErrs = append(allErrs, field.Invalid(routeTableIDPath, *infra.Networks.RouteTableID, "must be the id of a route table"))
+		}
+		if infra.Networks.VPC.ID == nil {
+			allErrs = append(allErrs, field.Forbidden(routeTableIDPath, "can only be specified together with an existing vpc id"))
+		}
+	}
+
+	if infra.Networks.SecurityGroupID != nil {
+		securityGroupIDPath := networksPath.Child("securityGroupID")
+		if !strings.HasPrefix(*infra.Networks.SecurityGroupID, "sg-") {
+			allErrs = append(allErrs, field.Invalid(securityGroupIDPath, *infra.Networks.SecurityGroupID, "must be the id of a security group"))
+		}
+		if infra.Networks.VPC.ID == nil {
+			allErrs = append(allErrs, field.Forbidden(securityGroupIDPath, "can only be specified together with an existing vpc id"))
+		}
+		if len(infra.Networks.SecurityGroupRules) > 0 {
+			allErrs = append(allErrs, field.Forbidden(networksPath.Child("securityGroupRules"), "cannot be specified together with an existing security group"))
+		}
+	}
+
+	if infra.Networks.NatGateway != nil && infra.Networks.NatGateway.EIPAllocation != nil {
+		allErrs = append(allErrs, validateEIPAllocation(infra.Networks.NatGateway.EIPAllocation, infra.Networks.NatGateway.PerZone, networksPath.Child("natGateway", "eipAllocation"))...)
+	}
+	if infra.Networks.NatGateway != nil {
+		allErrs = append(allErrs, validateDNATEntries(infra.Networks.NatGateway.DNATEntries, infra.Networks.VPC.CIDR, networksPath.Child("natGateway", "dnatEntries"))...)
 	}
 
 	// make sure that VPC cidrs don't overlap with each other
 	allErrs = append(allErrs, cidrvalidation.ValidateCIDROverlap(cidrs, cidrs, false)...)
-	allErrs = append(allErrs, cidrvalidation.ValidateCIDROverlap([]cidrvalidation.CIDR{pods, services}, cidrs, false)...)
+	allErrs = append(allErrs, cidrvalidation.ValidateCIDROverlap([]cidrvalidation.CIDR{pods}, nonPodsCIDRs, false)...)
+	allErrs = append(allErrs, cidrvalidation.ValidateCIDROverlap([]cidrvalidation.CIDR{services}, cidrs, false)...)
+
+	allErrs = append(allErrs, validateSecurityGroupRules(infra.Networks.SecurityGroupRules, networksPath.Child("securityGroupRules"))...)
+	allErrs = append(allErrs, validateRoutes(infra.Networks.Routes, infra.Networks.VPC.CIDR, networksPath.Child("routes"))...)
+	allErrs = append(allErrs, validateFlowLogs(infra.Networks.EnableFlowLogs, infra.Networks.FlowLogTarget, networksPath)...)
+	if dhcpOptions := infra.Networks.DHCPOptions; dhcpOptions != nil {
+		allErrs = append(allErrs, validateDHCPOptions(dhcpOptions, networksPath.Child("dhcpOptions"))...)
+	}
+	allErrs = append(allErrs, validateTags(infra.Tags, field.NewPath("tags"))...)
+
+	return allErrs
+}
+
+// validatePodsVSwitchSize validates that the vswitch of the pods of a zone provides at least as many addresses as the
+// worker vswitches of the zone, so that every node can get at least one address for its pods.
+func validatePodsVSwitchSize(podsCIDR cidrvalidation.CIDR, workerCIDRs []cidrvalidation.CIDR, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if errs := podsCIDR.ValidateParse(); len(errs) > 0 {
+		return allErrs
+	}
+
+	var nodeAddresses int64
+	for _, workerCIDR := range workerCIDRs {
+		if errs := workerCIDR.ValidateParse(); len(errs) > 0 {
+			return allErrs
+		}
+		nodeAddresses += vswitchAddresses(workerCIDR.GetIPNet())
+	}
+
+	if podAddresses := vswitchAddresses(podsCIDR.GetIPNet()); podAddresses < nodeAddresses {
+		allErrs = append(allErrs, field.Invalid(fldPath, podsCIDR.GetCIDR(), fmt.Sprintf("provides only %d addresses for pods but the worker vswitches of the zone provide %d addresses for nodes", podAddresses, nodeAddresses)))
+	}
 
 	return allErrs
 }
 
+// vswitchAddresses returns the number of addresses of a vswitch with the given CIDR which can be assigned to instances
+// or network interfaces.
+func vswitchAddresses(cidr *net.IPNet) int64 {
+	ones, bits := cidr.Mask.Size()
+	if addresses := int64(1)<<uint(bits-ones) - vswitchReservedAddresses; addresses > 0 {
+		return addresses
+	}
+	return 0
+}
+
+func containsCIDR(cidrs []cidrvalidation.CIDR, cidr cidrvalidation.CIDR) bool {
+	for _, c := range cidrs {
+		if c == cidr {
+			return true
+		}
+	}
+	return false
+}
+
+func validateSecurityGroupRules(rules []apisalicloud.SecurityGroupRule, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	type portRange struct {
+		index    int
+		from, to int
+	}
+	// portRanges contains the valid port ranges of all rules with the same direction, protocol, and CIDR.
+	portRanges := map[string][]portRange{}
+
+	for i, rule := range rules {
+		rulePath := fldPath.Index(i)
+
+		if !securityGroupRuleDirections.Has(string(rule.Direction)) {
+			allErrs = append(allErrs, field.NotSupported(rulePath.Child("direction"), rule.Direction, securityGroupRuleDirections.List()))
+		}
+		if !securityGroupRuleProtocols.Has(rule.Protocol) {
+			allErrs = append(allErrs, field.NotSupported(rulePath.Child("protocol"), rule.Protocol, securityGroupRuleProtocols.List()))
+		}
+		allErrs = append(allErrs, cidrvalidation.NewCIDR(rule.CIDR, rulePath.Child("cidr")).ValidateParse()...)
+
+		portRangePath := rulePath.Child("portRange")
+		from, to, err := parsePortRange(rule.PortRange)
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(portRangePath, rule.PortRange, err.Error()))
+			continue
+		}
+		if securityGroupRuleProtocolsWithPorts.Has(rule.Protocol) {
+			if from < 1 || to > 65535 || from > to {
+				allErrs = append(allErrs, field.Invalid(portRangePath, rule.PortRange, "must be a range of ports between 1 and 65535"))
+				continue
+			}
+		} else if from != -1 || to != -1 {
+			allErrs = append(allErrs, field.Invalid(portRangePath, rule.PortRange, fmt.Sprintf("must be -1/-1 for protocol %q", rule.Protocol)))
+			continue
+		}
+
+		key := fmt.Sprintf("%s/%s/%s", rule.Direction, rule.Protocol, rule.CIDR)
+		for _, other := range portRanges[key] {
+			if from <= other.to && other.from <= to {
+				allErrs = append(allErrs, field.Invalid(portRangePath, rule.PortRange, fmt.Sprintf("must not overlap with the port range of %s", fldPath.Index(other.index))))
+			}
+		}
+		portRanges[key] = append(portRanges[key], portRange{i, from, to})
+	}
+
+	return allErrs
+}
+
+func validateRoutes(routes []apisalicloud.Route, vpcCIDR *string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	var vpcNet *net.IPNet
+	if vpcCIDR != nil {
+		_, vpcNet, _ = net.ParseCIDR(*vpcCIDR)
+	}
+
+	destinationCIDRs := sets.NewString()
+	for i, route := range routes {
+		routePath := fldPath.Index(i)
+
+		destinationCIDRPath := routePath.Child("destinationCIDR")
+		destinationCIDR := cidrvalidation.NewCIDR(route.DestinationCIDR, destinationCIDRPath)
+		if errs := destinationCIDR.ValidateParse(); len(errs) > 0 {
+			allErrs = append(allErrs, errs...)
+		} else {
+			allErrs = append(allErrs, cidrvalidation.ValidateCIDRIsCanonical(destinationCIDRPath, route.DestinationCIDR)...)
+			destinationNet := destinationCIDR.GetIPNet()
+			if ones, _ := destinationNet.Mask.Size(); ones == 0 {
+				allErrs = append(allErrs, field.Forbidden(destinationCIDRPath, "the default route is managed by the NAT gateway"))
+			} else if vpcNet != nil && (vpcNet.Contains(destinationNet.IP) || destinationNet.Contains(vpcNet.IP)) {
+				allErrs = append(allErrs, field.Invalid(destinationCIDRPath, route.DestinationCIDR, "must not overlap with the vpc cidr"))
+			}
+		}
+		if destinationCIDRs.Has(route.DestinationCIDR) {
+			allErrs = append(allErrs, field.Duplicate(destinationCIDRPath, route.DestinationCIDR))
+		}
+		destinationCIDRs.Insert(route.DestinationCIDR)
+
+		prefix, ok := routeNextHopIDPrefixes[route.NextHopType]
+		if !ok {
+			supported := make([]string, 0, len(routeNextHopIDPrefixes))
+			for nextHopType := range routeNextHopIDPrefixes {
+				supported = append(supported, string(nextHopType))
+			}
+			allErrs = append(allErrs, field.NotSupported(routePath.Child("nextHopType"), route.NextHopType, sets.NewString(supported...).List()))
+		}
+
+		nextHopIDPath := routePath.Child("nextHopID")
+		if len(route.NextHopID) == 0 {
+			allErrs = append(allErrs, field.Required(nextHopIDPath, "must specify the id of the next hop"))
+		} else if ok && !strings.HasPrefix(route.NextHopID, prefix) {
+			allErrs = append(allErrs, field.Invalid(nextHopIDPath, route.NextHopID, fmt.Sprintf("must be the id of a %s, i.e., start with %q", route.NextHopType, prefix)))
+		}
+	}
+
+	return allErrs
+}
+
+func validateDNATEntries(entries []apisalicloud.DNATEntry, vpcCIDR *string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	var vpcNet *net.IPNet
+	if vpcCIDR != nil {
+		_, vpcNet, _ = net.ParseCIDR(*vpcCIDR)
+	}
+
+	externalAddresses := sets.NewString()
+	for i, entry := range entries {
+		entryPath := fldPath.Index(i)
+
+		if !dnatEntryProtocols.Has(entry.Protocol) {
+			allErrs = append(allErrs, field.NotSupported(entryPath.Child("protocol"), entry.Protocol, dnatEntryProtocols.List()))
+		}
+
+		externalIPPath := entryPath.Child("externalIP")
+		if ip := net.ParseIP(entry.ExternalIP); ip == nil || ip.To4() == nil {
+			allErrs = append(allErrs, field.Invalid(externalIPPath, entry.ExternalIP, "must be an IPv4 address"))
+		}
+		internalIPPath := entryPath.Child("internalIP")
+		if ip := net.ParseIP(entry.InternalIP); ip == nil || ip.To4() == nil {
+			allErrs = append(allErrs, field.Invalid(internalIPPath, entry.InternalIP, "must be an IPv4 address"))
+		} else if vpcNet != nil && !vpcNet.Contains(ip) {
+			allErrs = append(allErrs, field.Invalid(internalIPPath, entry.InternalIP, "must be in the vpc cidr"))
+		}
+
+		if entry.ExternalPort < 1 || entry.ExternalPort > 65535 {
+			allErrs = append(allErrs, field.Invalid(entryPath.Child("externalPort"), entry.ExternalPort, "must be a port between 1 and 65535"))
+		}
+		if entry.InternalPort < 1 || entry.InternalPort > 65535 {
+			allErrs = append(allErrs, field.Invalid(entryPath.Child("internalPort"), entry.InternalPort, "must be a port between 1 and 65535"))
+		}
+
+		// Every port of an EIP can only be forwarded once per protocol.
+		externalAddress := fmt.Sprintf("%s:%d/%s", entry.ExternalIP, entry.ExternalPort, entry.Protocol)
+		if externalAddresses.Has(externalAddress) {
+			allErrs = append(allErrs, field.Duplicate(entryPath.Child("externalPort"), entry.ExternalPort))
+		}
+		externalAddresses.Insert(externalAddress)
+	}
+
+	return allErrs
+}
+
+// parsePortRange parses a port range in the format `<from>/<to>`.
+func parsePortRange(portRange string) (int, int, error) {
+	parts := strings.Split(portRange, "/")
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("must have the format <from>/<to>")
+	}
+	from, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid start port: %v", err)
+	}
+	to, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid end port: %v", err)
+	}
+	return from, to, nil
+}
+
+func validateFlowLogs(enabled bool, target *apisalicloud.FlowLogTarget, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	targetPath := fldPath.Child("flowLogTarget")
+	if target == nil {
+		if enabled {
+			allErrs = append(allErrs, field.Required(targetPath, "must be specified if flow logs are enabled"))
+		}
+		return allErrs
+	}
+	if !enabled {
+		allErrs = append(allErrs, field.Forbidden(targetPath, "can only be specified if flow logs are enabled"))
+	}
+
+	if !slsProjectNameRegexp.MatchString(target.ProjectName) {
+		allErrs = append(allErrs, field.Invalid(targetPath.Child("projectName"), target.ProjectName, fmt.Sprintf("must match %s", slsProjectNameRegexp)))
+	}
+	if !slsLogStoreNameRegexp.MatchString(target.LogStoreName) {
+		allErrs = append(allErrs, field.Invalid(targetPath.Child("logStoreName"), target.LogStoreName, fmt.Sprintf("must match %s", slsLogStoreNameRegexp)))
+	}
+
+	return allErrs
+}
+
+func validateDHCPOptions(dhcpOptions *apisalicloud.DHCPOptions, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if len(dhcpOptions.DomainNameServers) == 0 && dhcpOptions.DomainName == nil {
+		return append(allErrs, field.Required(fldPath, "must specify domain name servers or a domain name"))
+	}
+
+	serversPath := fldPath.Child("domainNameServers")
+	if len(dhcpOptions.DomainNameServers) > maxDomainNameServers {
+		allErrs = append(allErrs, field.TooMany(serversPath, len(dhcpOptions.DomainNameServers), maxDomainNameServers))
+	}
+	servers := sets.NewString()
+	for i, server := range dhcpOptions.DomainNameServers {
+		if ip := net.ParseIP(server); ip == nil || ip.To4() == nil {
+			allErrs = append(allErrs, field.Invalid(serversPath.Index(i), server, "must be an IPv4 address"))
+		}
+		if servers.Has(server) {
+			allErrs = append(allErrs, field.Duplicate(serversPath.Index(i), server))
+		}
+		servers.Insert(server)
+	}
+
+	if domainName := dhcpOptions.DomainName; domainName != nil {
+		for _, msg := range validation.IsDNS1123Subdomain(*domainName) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("domainName"), *domainName, msg))
+		}
+	}
+
+	return allErrs
+}
+
+func validateTags(tags map[string]string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if maxTags := alicloud.MaxTagsPerResource - reservedTagKeys.Len(); len(tags) > maxTags {
+		allErrs = append(allErrs, field.TooMany(fldPath, len(tags), maxTags))
+	}
+
+	for key, value := range tags {
+		keyPath := fldPath.Key(key)
+
+		if len(key) == 0 || len(key) > maxTagKeyLength {
+			allErrs = append(allErrs, field.Invalid(keyPath, key, fmt.Sprintf("key must be between 1 and %d characters long", maxTagKeyLength)))
+		}
+		if len(value) > maxTagValueLength {
+			allErrs = append(allErrs, field.Invalid(keyPath, value, fmt.Sprintf("value must be at most %d characters long", maxTagValueLength)))
+		}
+		if reservedTagKeys.Has(key) {
+			allErrs = append(allErrs, field.Forbidden(keyPath, "key is reserved for the tags managed by Gardener"))
+		}
+		for _, prefix := range reservedTagPrefixes {
+			if strings.HasPrefix(key, prefix) {
+				allErrs = append(allErrs, field.Invalid(keyPath, key, fmt.Sprintf("key must not start with %q", prefix)))
+			}
+			if strings.HasPrefix(value, prefix) {
+				allErrs = append(allErrs, field.Invalid(keyPath, value, fmt.Sprintf("value must not start with %q", prefix)))
+			}
+		}
+	}
+
+	return allErrs
+}
+
+func validateEIPAllocation(allocation *apisalicloud.EIPAllocation, perZone bool, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if allocation.Bandwidth != nil && (*allocation.Bandwidth < minEIPBandwidth || *allocation.Bandwidth > maxEIPBandwidth) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("bandwidth"), *allocation.Bandwidth, fmt.Sprintf("must be between %d and %d", minEIPBandwidth, maxEIPBandwidth)))
+	}
+
+	if allocation.InternetChargeType != nil && !eipInternetChargeTypes.Has(*allocation.InternetChargeType) {
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("internetChargeType"), *allocation.InternetChargeType, eipInternetChargeTypes.List()))
+	}
+
+	if allocation.Count != nil {
+		countPath := fldPath.Child("count")
+		if *allocation.Count < 1 || *allocation.Count > maxEIPsPerNATGateway {
+			allErrs = append(allErrs, field.Invalid(countPath, *allocation.Count, fmt.Sprintf("must be between 1 and %d", maxEIPsPerNATGateway)))
+		}
+		if *allocation.Count > 1 && !perZone {
+			allErrs = append(allErrs, field.Forbidden(countPath, "more than one EIP can only be allocated for NAT gateways per zone"))
+		}
+	}
+
+	return allErrs
+}
+
+// ValidateInfrastructureConfigAgainstRegion validates the given InfrastructureConfig against the region of the infrastructure.
+func ValidateInfrastructureConfigAgainstRegion(infra *apisalicloud.InfrastructureConfig, region string) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if infra.Networks.DualStack != nil && infra.Networks.DualStack.Enabled && !ipv6Regions.Has(region) {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("networks", "dualStack", "enabled"), fmt.Sprintf("dual-stack is not supported in region %q", region)))
+	}
+
+	return allErrs
+}
+
+// withoutDNATEntries returns a copy of the given NAT gateway settings without the DNAT entries. Nil is returned if no
+// other settings remain, so that adding the first DNAT entry does not change the NAT gateway settings.
+func withoutDNATEntries(natGateway *apisalicloud.NatGateway) *apisalicloud.NatGateway {
+	if natGateway == nil {
+		return nil
+	}
+	natGatewayCopy := *natGateway
+	natGatewayCopy.DNATEntries = nil
+	if apiequality.Semantic.DeepEqual(natGatewayCopy, apisalicloud.NatGateway{}) {
+		return nil
+	}
+	return &natGatewayCopy
+}
+
 // ValidateInfrastructureConfigUpdate validates a InfrastructureConfig object.
 func ValidateInfrastructureConfigUpdate(oldConfig, newConfig *apisalicloud.InfrastructureConfig, nodesCIDR, podsCIDR, servicesCIDR *string) field.ErrorList {
 	allErrs := field.ErrorList{}
 
-	allErrs = append(allErrs, apivalidation.ValidateImmutableField(newConfig.Networks, oldConfig.Networks, field.NewPath("networks"))...)
+	// The security group rules, routes, DNAT entries, flow logs, and DHCP options are reconciled declaratively, hence
+	// they may be changed.
+	oldNetworks, newNetworks := oldConfig.Networks, newConfig.Networks
+	oldNetworks.NatGateway, newNetworks.NatGateway = withoutDNATEntries(oldNetworks.NatGateway), withoutDNATEntries(newNetworks.NatGateway)
+	oldNetworks.SecurityGroupRules, newNetworks.SecurityGroupRules = nil, nil
+	oldNetworks.Routes, newNetworks.Routes = nil, nil
+	oldNetworks.EnableFlowLogs, newNetworks.EnableFlowLogs = false, false
+	oldNetworks.FlowLogTarget, newNetworks.FlowLogTarget = nil, nil
+	oldNetworks.DHCPOptions, newNetworks.DHCPOptions = nil, nil
+	allErrs = append(allErrs, apivalidation.ValidateImmutableField(newNetworks, oldNetworks, field.NewPath("networks"))...)
 
 	return allErrs
 }
@@ -15,6 +15,8 @@
 package validation_test
 
 import (
+	"fmt"
+
 	apisalicloud "github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/alicloud"
 	. "github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/alicloud/validation"
 
@@ -23,6 +25,7 @@ import (
 	. "github.com/onsi/gomega"
 	. "github.com/onsi/gomega/gstruct"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/pointer"
 )
 
 var _ = Describe("InfrastructureConfig validation", func() {
@@ -160,6 +163,786 @@ var _ = Describe("InfrastructureConfig validation", func() {
 				}))
 			})
 		})
+
+		Context("additional workers", func() {
+			It("should allow additional workers CIDRs", func() {
+				infrastructureConfig.Networks.Zones[0].AdditionalWorkers = []string{"10.250.4.0/24"}
+
+				errorList := ValidateInfrastructureConfig(infrastructureConfig, &nodes, &pods, &services)
+
+				Expect(errorList).To(BeEmpty())
+			})
+
+			It("should forbid additional workers CIDRs overlapping with the workers CIDR", func() {
+				infrastructureConfig.Networks.Zones[0].AdditionalWorkers = []string{"10.250.3.128/25"}
+
+				errorList := ValidateInfrastructureConfig(infrastructureConfig, &nodes, &pods, &services)
+
+				Expect(errorList).To(ConsistOfFields(Fields{
+					"Type":  Equal(field.ErrorTypeInvalid),
+					"Field": Equal("networks.zones[0].additionalWorkers[0]"),
+				}))
+			})
+		})
+
+		Context("security group rules", func() {
+			It("should allow valid security group rules", func() {
+				infrastructureConfig.Networks.SecurityGroupRules = []apisalicloud.SecurityGroupRule{
+					{Direction: apisalicloud.SecurityGroupRuleDirectionIngress, Protocol: "tcp", PortRange: "22/22", CIDR: "10.0.0.0/8"},
+					{Direction: apisalicloud.SecurityGroupRuleDirectionIngress, Protocol: "tcp", PortRange: "80/443", CIDR: "10.0.0.0/8"},
+					{Direction: apisalicloud.SecurityGroupRuleDirectionEgress, Protocol: "icmp", PortRange: "-1/-1", CIDR: "0.0.0.0/0"},
+				}
+
+				errorList := ValidateInfrastructureConfig(infrastructureConfig, &nodes, &pods, &services)
+
+				Expect(errorList).To(BeEmpty())
+			})
+
+			It("should forbid malformed security group rules", func() {
+				infrastructureConfig.Networks.SecurityGroupRules = []apisalicloud.SecurityGroupRule{
+					{Direction: "inbound", Protocol: "sctp", PortRange: "22", CIDR: invalidCIDR},
+					{Direction: apisalicloud.SecurityGroupRuleDirectionIngress, Protocol: "tcp", PortRange: "443/80", CIDR: "10.0.0.0/8"},
+					{Direction: apisalicloud.SecurityGroupRuleDirectionIngress, Protocol: "icmp", PortRange: "1/1", CIDR: "10.0.0.0/8"},
+				}
+
+				errorList := ValidateInfrastructureConfig(infrastructureConfig, &nodes, &pods, &services)
+
+				Expect(errorList).To(ConsistOfFields(Fields{
+					"Type":  Equal(field.ErrorTypeNotSupported),
+					"Field": Equal("networks.securityGroupRules[0].direction"),
+				}, Fields{
+					"Type":  Equal(field.ErrorTypeNotSupported),
+					"Field": Equal("networks.securityGroupRules[0].protocol"),
+				}, Fields{
+					"Type":  Equal(field.ErrorTypeInvalid),
+					"Field": Equal("networks.securityGroupRules[0].cidr"),
+				}, Fields{
+					"Type":  Equal(field.ErrorTypeInvalid),
+					"Field": Equal("networks.securityGroupRules[0].portRange"),
+				}, Fields{
+					"Type":  Equal(field.ErrorTypeInvalid),
+					"Field": Equal("networks.securityGroupRules[1].portRange"),
+				}, Fields{
+					"Type":  Equal(field.ErrorTypeInvalid),
+					"Field": Equal("networks.securityGroupRules[2].portRange"),
+				}))
+			})
+
+			It("should forbid overlapping port ranges", func() {
+				infrastructureConfig.Networks.SecurityGroupRules = []apisalicloud.SecurityGroupRule{
+					{Direction: apisalicloud.SecurityGroupRuleDirectionIngress, Protocol: "tcp", PortRange: "80/443", CIDR: "10.0.0.0/8"},
+					{Direction: apisalicloud.SecurityGroupRuleDirectionIngress, Protocol: "tcp", PortRange: "443/8443", CIDR: "10.0.0.0/8"},
+					{Direction: apisalicloud.SecurityGroupRuleDirectionIngress, Protocol: "udp", PortRange: "80/443", CIDR: "10.0.0.0/8"},
+				}
+
+				errorList := ValidateInfrastructureConfig(infrastructureConfig, &nodes, &pods, &services)
+
+				Expect(errorList).To(ConsistOfFields(Fields{
+					"Type":   Equal(field.ErrorTypeInvalid),
+					"Field":  Equal("networks.securityGroupRules[1].portRange"),
+					"Detail": Equal("must not overlap with the port range of networks.securityGroupRules[0]"),
+				}))
+			})
+		})
+
+		Context("routes", func() {
+			It("should allow valid routes", func() {
+				infrastructureConfig.Networks.Routes = []apisalicloud.Route{
+					{DestinationCIDR: "192.168.0.0/16", NextHopType: apisalicloud.RouteNextHopTypeRouterInterface, NextHopID: "ri-2ze7fbuohm6jd9a1xxxxx"},
+					{DestinationCIDR: "172.16.0.0/24", NextHopType: apisalicloud.RouteNextHopTypeInstance, NextHopID: "i-2ze7fbuohm6jd9a1xxxxx"},
+				}
+
+				errorList := ValidateInfrastructureConfig(infrastructureConfig, &nodes, &pods, &services)
+
+				Expect(errorList).To(BeEmpty())
+			})
+
+			It("should forbid invalid routes", func() {
+				infrastructureConfig.Networks.Routes = []apisalicloud.Route{
+					{DestinationCIDR: invalidCIDR, NextHopType: "Peering", NextHopID: ""},
+					{DestinationCIDR: "0.0.0.0/0", NextHopType: apisalicloud.RouteNextHopTypeInstance, NextHopID: "eni-2ze7fbuohm6jd9a1xxxxx"},
+					{DestinationCIDR: "10.1.0.0/16", NextHopType: apisalicloud.RouteNextHopTypeVpnGateway, NextHopID: "vpn-2ze7fbuohm6jd9a1xxxxx"},
+					{DestinationCIDR: "192.168.0.1/16", NextHopType: apisalicloud.RouteNextHopTypeHaVip, NextHopID: "havip-2ze7fbuohm6jd9a1xxxxx"},
+				}
+
+				errorList := ValidateInfrastructureConfig(infrastructureConfig, &nodes, &pods, &services)
+
+				Expect(errorList).To(ConsistOfFields(Fields{
+					"Type":  Equal(field.ErrorTypeInvalid),
+					"Field": Equal("networks.routes[0].destinationCIDR"),
+				}, Fields{
+					"Type":  Equal(field.ErrorTypeNotSupported),
+					"Field": Equal("networks.routes[0].nextHopType"),
+				}, Fields{
+					"Type":  Equal(field.ErrorTypeRequired),
+					"Field": Equal("networks.routes[0].nextHopID"),
+				}, Fields{
+					"Type":  Equal(field.ErrorTypeForbidden),
+					"Field": Equal("networks.routes[1].destinationCIDR"),
+				}, Fields{
+					"Type":  Equal(field.ErrorTypeInvalid),
+					"Field": Equal("networks.routes[1].nextHopID"),
+				}, Fields{
+					"Type":   Equal(field.ErrorTypeInvalid),
+					"Field":  Equal("networks.routes[2].destinationCIDR"),
+					"Detail": Equal("must not overlap with the vpc cidr"),
+				}, Fields{
+					"Type":   Equal(field.ErrorTypeInvalid),
+					"Field":  Equal("networks.routes[3].destinationCIDR"),
+					"Detail": Equal("must be valid canonical CIDR"),
+				}))
+			})
+
+			It("should forbid duplicate destination CIDRs", func() {
+				infrastructureConfig.Networks.Routes = []apisalicloud.Route{
+					{DestinationCIDR: "192.168.0.0/16", NextHopType: apisalicloud.RouteNextHopTypeInstance, NextHopID: "i-1"},
+					{DestinationCIDR: "192.168.0.0/16", NextHopType: apisalicloud.RouteNextHopTypeInstance, NextHopID: "i-2"},
+				}
+
+				errorList := ValidateInfrastructureConfig(infrastructureConfig, &nodes, &pods, &services)
+
+				Expect(errorList).To(ConsistOfFields(Fields{
+					"Type":  Equal(field.ErrorTypeDuplicate),
+					"Field": Equal("networks.routes[1].destinationCIDR"),
+				}))
+			})
+		})
+
+		Context("DNAT entries", func() {
+			It("should allow valid DNAT entries", func() {
+				infrastructureConfig.Networks.NatGateway = &apisalicloud.NatGateway{
+					DNATEntries: []apisalicloud.DNATEntry{
+						{ExternalIP: "47.95.1.1", ExternalPort: 22, InternalIP: "10.250.3.10", InternalPort: 30022, Protocol: "tcp"},
+						{ExternalIP: "47.95.1.1", ExternalPort: 22, InternalIP: "10.250.3.10", InternalPort: 30022, Protocol: "udp"},
+						{ExternalIP: "47.95.1.2", ExternalPort: 22, InternalIP: "10.250.3.11", InternalPort: 30022, Protocol: "tcp"},
+					},
+				}
+
+				Expect(ValidateInfrastructureConfig(infrastructureConfig, &nodes, &pods, &services)).To(BeEmpty())
+			})
+
+			It("should forbid invalid DNAT entries", func() {
+				infrastructureConfig.Networks.NatGateway = &apisalicloud.NatGateway{
+					DNATEntries: []apisalicloud.DNATEntry{
+						{ExternalIP: "eip-1", ExternalPort: 0, InternalIP: "fd00::1", InternalPort: 65536, Protocol: "icmp"},
+						{ExternalIP: "47.95.1.1", ExternalPort: 22, InternalIP: "192.168.0.1", InternalPort: 22, Protocol: "tcp"},
+					},
+				}
+
+				errorList := ValidateInfrastructureConfig(infrastructureConfig, &nodes, &pods, &services)
+
+				Expect(errorList).To(ConsistOfFields(Fields{
+					"Type":  Equal(field.ErrorTypeNotSupported),
+					"Field": Equal("networks.natGateway.dnatEntries[0].protocol"),
+				}, Fields{
+					"Type":   Equal(field.ErrorTypeInvalid),
+					"Field":  Equal("networks.natGateway.dnatEntries[0].externalIP"),
+					"Detail": Equal("must be an IPv4 address"),
+				}, Fields{
+					"Type":   Equal(field.ErrorTypeInvalid),
+					"Field":  Equal("networks.natGateway.dnatEntries[0].internalIP"),
+					"Detail": Equal("must be an IPv4 address"),
+				}, Fields{
+					"Type":  Equal(field.ErrorTypeInvalid),
+					"Field": Equal("networks.natGateway.dnatEntries[0].externalPort"),
+				}, Fields{
+					"Type":  Equal(field.ErrorTypeInvalid),
+					"Field": Equal("networks.natGateway.dnatEntries[0].internalPort"),
+				}, Fields{
+					"Type":   Equal(field.ErrorTypeInvalid),
+					"Field":  Equal("networks.natGateway.dnatEntries[1].internalIP"),
+					"Detail": Equal("must be in the vpc cidr"),
+				}))
+			})
+
+			It("should forbid forwarding the same port of an EIP twice", func() {
+				infrastructureConfig.Networks.NatGateway = &apisalicloud.NatGateway{
+					DNATEntries: []apisalicloud.DNATEntry{
+						{ExternalIP: "47.95.1.1", ExternalPort: 22, InternalIP: "10.250.3.10", InternalPort: 30022, Protocol: "tcp"},
+						{ExternalIP: "47.95.1.1", ExternalPort: 22, InternalIP: "10.250.3.11", InternalPort: 30022, Protocol: "tcp"},
+					},
+				}
+
+				errorList := ValidateInfrastructureConfig(infrastructureConfig, &nodes, &pods, &services)
+
+				Expect(errorList).To(ConsistOfFields(Fields{
+					"Type":  Equal(field.ErrorTypeDuplicate),
+					"Field": Equal("networks.natGateway.dnatEntries[1].externalPort"),
+				}))
+			})
+		})
+
+		Context("flow logs", func() {
+			It("should allow enabling flow logs with a valid target", func() {
+				infrastructureConfig.Networks.EnableFlowLogs = true
+				infrastructureConfig.Networks.FlowLogTarget = &apisalicloud.FlowLogTarget{ProjectName: "my-project", LogStoreName: "vpc_flow-logs"}
+
+				Expect(ValidateInfrastructureConfig(infrastructureConfig, &nodes, &pods, &services)).To(BeEmpty())
+			})
+
+			It("should require a target if flow logs are enabled", func() {
+				infrastructureConfig.Networks.EnableFlowLogs = true
+
+				errorList := ValidateInfrastructureConfig(infrastructureConfig, &nodes, &pods, &services)
+
+				Expect(errorList).To(ConsistOfFields(Fields{
+					"Type":  Equal(field.ErrorTypeRequired),
+					"Field": Equal("networks.flowLogTarget"),
+				}))
+			})
+
+			It("should forbid a target if flow logs are disabled and invalid names", func() {
+				infrastructureConfig.Networks.FlowLogTarget = &apisalicloud.FlowLogTarget{ProjectName: "My_Project", LogStoreName: "-"}
+
+				errorList := ValidateInfrastructureConfig(infrastructureConfig, &nodes, &pods, &services)
+
+				Expect(errorList).To(ConsistOfFields(Fields{
+					"Type":  Equal(field.ErrorTypeForbidden),
+					"Field": Equal("networks.flowLogTarget"),
+				}, Fields{
+					"Type":  Equal(field.ErrorTypeInvalid),
+					"Field": Equal("networks.flowLogTarget.projectName"),
+				}, Fields{
+					"Type":  Equal(field.ErrorTypeInvalid),
+					"Field": Equal("networks.flowLogTarget.logStoreName"),
+				}))
+			})
+		})
+
+		Context("DHCP options", func() {
+			It("should allow valid DNS servers and a domain name", func() {
+				infrastructureConfig.Networks.DHCPOptions = &apisalicloud.DHCPOptions{
+					DomainNameServers: []string{"10.0.0.2", "10.0.1.2"},
+					DomainName:        pointer.StringPtr("corp.example.com"),
+				}
+
+				Expect(ValidateInfrastructureConfig(infrastructureConfig, &nodes, &pods, &services)).To(BeEmpty())
+			})
+
+			It("should require DNS servers or a domain name", func() {
+				infrastructureConfig.Networks.DHCPOptions = &apisalicloud.DHCPOptions{}
+
+				errorList := ValidateInfrastructureConfig(infrastructureConfig, &nodes, &pods, &services)
+
+				Expect(errorList).To(ConsistOfFields(Fields{
+					"Type":  Equal(field.ErrorTypeRequired),
+					"Field": Equal("networks.dhcpOptions"),
+				}))
+			})
+
+			It("should forbid invalid, duplicate, and too many DNS servers and an invalid domain name", func() {
+				infrastructureConfig.Networks.DHCPOptions = &apisalicloud.DHCPOptions{
+					DomainNameServers: []string{"10.0.0.2", "dns.example.com", "fd00::2", "10.0.0.2", "10.0.0.3"},
+					DomainName:        pointer.StringPtr("Corp_Example"),
+				}
+
+				errorList := ValidateInfrastructureConfig(infrastructureConfig, &nodes, &pods, &services)
+
+				Expect(errorList).To(ConsistOfFields(Fields{
+					"Type":  Equal(field.ErrorTypeTooMany),
+					"Field": Equal("networks.dhcpOptions.domainNameServers"),
+				}, Fields{
+					"Type":  Equal(field.ErrorTypeInvalid),
+					"Field": Equal("networks.dhcpOptions.domainNameServers[1]"),
+				}, Fields{
+					"Type":  Equal(field.ErrorTypeInvalid),
+					"Field": Equal("networks.dhcpOptions.domainNameServers[2]"),
+				}, Fields{
+					"Type":  Equal(field.ErrorTypeDuplicate),
+					"Field": Equal("networks.dhcpOptions.domainNameServers[3]"),
+				}, Fields{
+					"Type":  Equal(field.ErrorTypeInvalid),
+					"Field": Equal("networks.dhcpOptions.domainName"),
+				}))
+			})
+		})
+
+		Context("tags", func() {
+			It("should allow valid tags", func() {
+				infrastructureConfig.Tags = map[string]string{"cost-center": "1234"}
+
+				errorList := ValidateInfrastructureConfig(infrastructureConfig, &nodes, &pods, &services)
+
+				Expect(errorList).To(BeEmpty())
+			})
+
+			It("should forbid reserved and invalid tags", func() {
+				infrastructureConfig.Tags = map[string]string{
+					"gardener.cloud/shoot-name": "foo",
+					"aliyun-foo":                "bar",
+					"foo":                       "acs:bar",
+				}
+
+				errorList := ValidateInfrastructureConfig(infrastructureConfig, &nodes, &pods, &services)
+
+				Expect(errorList).To(ConsistOfFields(Fields{
+					"Type":  Equal(field.ErrorTypeForbidden),
+					"Field": Equal("tags[gardener.cloud/shoot-name]"),
+				}, Fields{
+					"Type":  Equal(field.ErrorTypeInvalid),
+					"Field": Equal("tags[aliyun-foo]"),
+				}, Fields{
+					"Type":  Equal(field.ErrorTypeInvalid),
+					"Field": Equal("tags[foo]"),
+				}))
+			})
+
+			It("should forbid too many tags", func() {
+				infrastructureConfig.Tags = map[string]string{}
+				for i := 0; i < 19; i++ {
+					infrastructureConfig.Tags[fmt.Sprintf("key-%d", i)] = "value"
+				}
+
+				errorList := ValidateInfrastructureConfig(infrastructureConfig, &nodes, &pods, &services)
+
+				Expect(errorList).To(ConsistOfFields(Fields{
+					"Type":  Equal(field.ErrorTypeTooMany),
+					"Field": Equal("tags"),
+				}))
+			})
+		})
+
+		Context("route table", func() {
+			var routeTableID = "vtb-123"
+
+			It("should allow a route table id together with an existing VPC", func() {
+				vpcID := "vpc-123"
+				infrastructureConfig.Networks.VPC = apisalicloud.VPC{ID: &vpcID}
+				infrastructureConfig.Networks.RouteTableID = &routeTableID
+
+				Expect(ValidateInfrastructureConfig(infrastructureConfig, &nodes, &pods, &services)).To(BeEmpty())
+			})
+
+			It("should forbid a route table id if the VPC is created", func() {
+				infrastructureConfig.Networks.RouteTableID = &routeTableID
+
+				errorList := ValidateInfrastructureConfig(infrastructureConfig, &nodes, &pods, &services)
+
+				Expect(errorList).To(ConsistOfFields(Fields{
+					"Type":  Equal(field.ErrorTypeForbidden),
+					"Field": Equal("networks.routeTableID"),
+				}))
+			})
+
+			It("should forbid invalid route table ids", func() {
+				vpcID := "vpc-123"
+				invalidID := "rtb-123"
+				infrastructureConfig.Networks.VPC = apisalicloud.VPC{ID: &vpcID}
+				infrastructureConfig.Networks.RouteTableID = &invalidID
+
+				errorList := ValidateInfrastructureConfig(infrastructureConfig, &nodes, &pods, &services)
+
+				Expect(errorList).To(ConsistOfFields(Fields{
+					"Type":  Equal(field.ErrorTypeInvalid),
+					"Field": Equal("networks.routeTableID"),
+				}))
+			})
+		})
+
+		Context("security group", func() {
+			var (
+				securityGroupID = "sg-123"
+				vpcID           = "vpc-123"
+			)
+
+			It("should allow a security group id together with an existing VPC", func() {
+				infrastructureConfig.Networks.VPC = apisalicloud.VPC{ID: &vpcID}
+				infrastructureConfig.Networks.SecurityGroupID = &securityGroupID
+
+				Expect(ValidateInfrastructureConfig(infrastructureConfig, &nodes, &pods, &services)).To(BeEmpty())
+			})
+
+			It("should forbid a security group id if the VPC is created", func() {
+				infrastructureConfig.Networks.SecurityGroupID = &securityGroupID
+
+				errorList := ValidateInfrastructureConfig(infrastructureConfig, &nodes, &pods, &services)
+
+				Expect(errorList).To(ConsistOfFields(Fields{
+					"Type":  Equal(field.ErrorTypeForbidden),
+					"Field": Equal("networks.securityGroupID"),
+				}))
+			})
+
+			It("should forbid invalid security group ids and custom security group rules", func() {
+				invalidID := "vpc-123"
+				infrastructureConfig.Networks.VPC = apisalicloud.VPC{ID: &vpcID}
+				infrastructureConfig.Networks.SecurityGroupID = &invalidID
+				infrastructureConfig.Networks.SecurityGroupRules = []apisalicloud.SecurityGroupRule{
+					{Direction: apisalicloud.SecurityGroupRuleDirectionIngress, Protocol: "tcp", PortRange: "22/22", CIDR: "10.0.0.0/8"},
+				}
+
+				errorList := ValidateInfrastructureConfig(infrastructureConfig, &nodes, &pods, &services)
+
+				Expect(errorList).To(ConsistOfFields(Fields{
+					"Type":  Equal(field.ErrorTypeInvalid),
+					"Field": Equal("networks.securityGroupID"),
+				}, Fields{
+					"Type":  Equal(field.ErrorTypeForbidden),
+					"Field": Equal("networks.securityGroupRules"),
+				}))
+			})
+		})
+
+		Context("EIP allocation id", func() {
+			var eipAllocationID = "eip-123"
+
+			It("should allow an existing EIP for the NAT gateway", func() {
+				infrastructureConfig.Networks.NatGateway = &apisalicloud.NatGateway{EIPAllocationID: &eipAllocationID}
+
+				Expect(ValidateInfrastructureConfig(infrastructureConfig, &nodes, &pods, &services)).To(BeEmpty())
+			})
+
+			It("should forbid invalid EIP allocation ids", func() {
+				invalidID := "vpc-123"
+				infrastructureConfig.Networks.NatGateway = &apisalicloud.NatGateway{EIPAllocationID: &invalidID}
+
+				errorList := ValidateInfrastructureConfig(infrastructureConfig, &nodes, &pods, &services)
+
+				Expect(errorList).To(ConsistOfFields(Fields{
+					"Type":  Equal(field.ErrorTypeInvalid),
+					"Field": Equal("networks.natGateway.eipAllocationID"),
+				}))
+			})
+
+			It("should forbid an existing EIP together with NAT gateways per zone or EIP allocation settings", func() {
+				infrastructureConfig.Networks.NatGateway = &apisalicloud.NatGateway{
+					EIPAllocationID: &eipAllocationID,
+					EIPAllocation:   &apisalicloud.EIPAllocation{},
+					PerZone:         true,
+				}
+
+				errorList := ValidateInfrastructureConfig(infrastructureConfig, &nodes, &pods, &services)
+
+				Expect(errorList).To(ConsistOfFields(Fields{
+					"Type":  Equal(field.ErrorTypeForbidden),
+					"Field": Equal("networks.natGateway.eipAllocationID"),
+				}, Fields{
+					"Type":  Equal(field.ErrorTypeForbidden),
+					"Field": Equal("networks.natGateway.eipAllocationID"),
+				}))
+			})
+		})
+
+		Context("pods vswitches", func() {
+			var eniPods string
+
+			BeforeEach(func() {
+				eniPods = "10.252.0.0/16"
+			})
+
+			It("should allow a pods vswitch which is large enough and part of the pods CIDR", func() {
+				podsCIDR := "10.252.0.0/24"
+				infrastructureConfig.Networks.Zones[0].PodsCIDR = &podsCIDR
+
+				Expect(ValidateInfrastructureConfig(infrastructureConfig, &nodes, &eniPods, &services)).To(BeEmpty())
+			})
+
+			It("should forbid a pods vswitch providing less addresses than the worker vswitches of the zone", func() {
+				podsCIDR := "10.252.0.0/25"
+				infrastructureConfig.Networks.Zones[0].PodsCIDR = &podsCIDR
+
+				errorList := ValidateInfrastructureConfig(infrastructureConfig, &nodes, &eniPods, &services)
+
+				Expect(errorList).To(ConsistOfFields(Fields{
+					"Type":   Equal(field.ErrorTypeInvalid),
+					"Field":  Equal("networks.zones[0].podsCIDR"),
+					"Detail": Equal("provides only 124 addresses for pods but the worker vswitches of the zone provide 252 addresses for nodes"),
+				}))
+			})
+
+			It("should take the additional worker vswitches of the zone into account", func() {
+				podsCIDR := "10.252.0.0/24"
+				infrastructureConfig.Networks.Zones[0].AdditionalWorkers = []string{"10.250.4.0/26"}
+				infrastructureConfig.Networks.Zones[0].PodsCIDR = &podsCIDR
+
+				errorList := ValidateInfrastructureConfig(infrastructureConfig, &nodes, &eniPods, &services)
+
+				Expect(errorList).To(ConsistOfFields(Fields{
+					"Type":   Equal(field.ErrorTypeInvalid),
+					"Field":  Equal("networks.zones[0].podsCIDR"),
+					"Detail": Equal("provides only 252 addresses for pods but the worker vswitches of the zone provide 312 addresses for nodes"),
+				}))
+			})
+
+			It("should forbid a pods vswitch outside of the pods CIDR", func() {
+				podsCIDR := "10.253.0.0/24"
+				infrastructureConfig.Networks.Zones[0].PodsCIDR = &podsCIDR
+
+				errorList := ValidateInfrastructureConfig(infrastructureConfig, &nodes, &eniPods, &services)
+
+				Expect(errorList).To(ConsistOfFields(Fields{
+					"Type":  Equal(field.ErrorTypeInvalid),
+					"Field": Equal("networks.zones[0].podsCIDR"),
+				}))
+			})
+
+			It("should forbid a pods vswitch overlapping with the worker CIDRs", func() {
+				podsCIDR := "10.250.0.0/22"
+				infrastructureConfig.Networks.Zones[0].PodsCIDR = &podsCIDR
+
+				eniPods = "10.250.0.0/22"
+
+				errorList := ValidateInfrastructureConfig(infrastructureConfig, &nodes, &eniPods, &services)
+
+				Expect(errorList).To(ConsistOfFields(Fields{
+					"Type":   Equal(field.ErrorTypeInvalid),
+					"Field":  Equal("networks.zones[0].workers"),
+					"Detail": Equal(`must not be a subset of "networks.zones[0].podsCIDR" ("10.250.0.0/22")`),
+				}, Fields{
+					"Type":  Equal(field.ErrorTypeInvalid),
+					"Field": Equal("networks.zones[0].workers"),
+				}))
+			})
+
+			It("should forbid pods vswitches in only some of the zones", func() {
+				podsCIDR := "10.252.0.0/24"
+				infrastructureConfig.Networks.Zones[0].PodsCIDR = &podsCIDR
+				infrastructureConfig.Networks.Zones = append(infrastructureConfig.Networks.Zones, apisalicloud.Zone{
+					Name:    "zone2",
+					Workers: "10.250.4.0/24",
+				})
+
+				errorList := ValidateInfrastructureConfig(infrastructureConfig, &nodes, &eniPods, &services)
+
+				Expect(errorList).To(ConsistOfFields(Fields{
+					"Type":   Equal(field.ErrorTypeInvalid),
+					"Field":  Equal("networks.zones"),
+					"Detail": Equal("must specify a pods cidr either for all zones or for none"),
+				}))
+			})
+		})
+
+		Context("NAT gateway", func() {
+			var natGatewayID = "ngw-123"
+
+			It("should allow a NAT gateway id together with an existing VPC", func() {
+				vpcID := "vpc-123"
+				infrastructureConfig.Networks.VPC = apisalicloud.VPC{ID: &vpcID}
+				infrastructureConfig.Networks.NatGateway = &apisalicloud.NatGateway{ID: &natGatewayID}
+
+				Expect(ValidateInfrastructureConfig(infrastructureConfig, &nodes, &pods, &services)).To(BeEmpty())
+			})
+
+			It("should allow NAT gateways per zone together with a new VPC", func() {
+				infrastructureConfig.Networks.NatGateway = &apisalicloud.NatGateway{PerZone: true}
+
+				Expect(ValidateInfrastructureConfig(infrastructureConfig, &nodes, &pods, &services)).To(BeEmpty())
+			})
+
+			It("should forbid NAT gateways per zone together with an existing VPC or custom routes", func() {
+				vpcID := "vpc-123"
+				infrastructureConfig.Networks.VPC = apisalicloud.VPC{ID: &vpcID}
+				infrastructureConfig.Networks.NatGateway = &apisalicloud.NatGateway{PerZone: true}
+				infrastructureConfig.Networks.Routes = []apisalicloud.Route{
+					{DestinationCIDR: "172.16.0.0/16", NextHopType: apisalicloud.RouteNextHopTypeInstance, NextHopID: "i-123"},
+				}
+
+				errorList := ValidateInfrastructureConfig(infrastructureConfig, &nodes, &pods, &services)
+
+				Expect(errorList).To(ConsistOfFields(Fields{
+					"Type":   Equal(field.ErrorTypeForbidden),
+					"Field":  Equal("networks.natGateway.perZone"),
+					"Detail": Equal("can only be specified together with a new vpc"),
+				}, Fields{
+					"Type":   Equal(field.ErrorTypeForbidden),
+					"Field":  Equal("networks.natGateway.perZone"),
+					"Detail": Equal("cannot be specified together with custom routes"),
+				}))
+			})
+
+			It("should allow a dedicated NAT gateway vswitch outside of the worker CIDRs", func() {
+				natGatewayCIDR := "10.251.0.0/28"
+				infrastructureConfig.Networks.NatGateway = &apisalicloud.NatGateway{PerZone: true}
+				infrastructureConfig.Networks.Zones[0].NatGatewayCIDR = &natGatewayCIDR
+
+				Expect(ValidateInfrastructureConfig(infrastructureConfig, &nodes, &pods, &services)).To(BeEmpty())
+			})
+
+			It("should forbid a dedicated NAT gateway vswitch overlapping with the worker CIDRs", func() {
+				natGatewayCIDR := "10.250.3.0/28"
+				infrastructureConfig.Networks.NatGateway = &apisalicloud.NatGateway{PerZone: true}
+				infrastructureConfig.Networks.Zones[0].NatGatewayCIDR = &natGatewayCIDR
+
+				errorList := ValidateInfrastructureConfig(infrastructureConfig, &nodes, &pods, &services)
+
+				Expect(errorList).To(ConsistOfFields(Fields{
+					"Type":  Equal(field.ErrorTypeInvalid),
+					"Field": Equal("networks.zones[0].natGatewayCIDR"),
+				}, Fields{
+					"Type":  Equal(field.ErrorTypeInvalid),
+					"Field": Equal("networks.zones[0].workers"),
+				}))
+			})
+
+			It("should forbid a dedicated NAT gateway vswitch without NAT gateways per zone", func() {
+				natGatewayCIDR := "10.251.0.0/28"
+				infrastructureConfig.Networks.Zones[0].NatGatewayCIDR = &natGatewayCIDR
+
+				errorList := ValidateInfrastructureConfig(infrastructureConfig, &nodes, &pods, &services)
+
+				Expect(errorList).To(ConsistOfFields(Fields{
+					"Type":   Equal(field.ErrorTypeForbidden),
+					"Field":  Equal("networks.zones[0].natGatewayCIDR"),
+					"Detail": Equal("can only be specified together with NAT gateways per zone"),
+				}))
+			})
+
+			It("should forbid a NAT gateway id if the VPC is created", func() {
+				infrastructureConfig.Networks.NatGateway = &apisalicloud.NatGateway{ID: &natGatewayID}
+
+				errorList := ValidateInfrastructureConfig(infrastructureConfig, &nodes, &pods, &services)
+
+				Expect(errorList).To(ConsistOfFields(Fields{
+					"Type":  Equal(field.ErrorTypeForbidden),
+					"Field": Equal("networks.natGateway.id"),
+				}))
+			})
+
+			It("should allow valid EIP allocation settings", func() {
+				bandwidth := int32(500)
+				internetChargeType := "PayByBandwidth"
+				infrastructureConfig.Networks.NatGateway = &apisalicloud.NatGateway{
+					EIPAllocation: &apisalicloud.EIPAllocation{
+						Bandwidth:          &bandwidth,
+						InternetChargeType: &internetChargeType,
+					},
+				}
+
+				Expect(ValidateInfrastructureConfig(infrastructureConfig, &nodes, &pods, &services)).To(BeEmpty())
+			})
+
+			It("should forbid invalid EIP allocation settings", func() {
+				bandwidth := int32(501)
+				internetChargeType := "foo"
+				infrastructureConfig.Networks.NatGateway = &apisalicloud.NatGateway{
+					EIPAllocation: &apisalicloud.EIPAllocation{
+						Bandwidth:          &bandwidth,
+						InternetChargeType: &internetChargeType,
+					},
+				}
+
+				errorList := ValidateInfrastructureConfig(infrastructureConfig, &nodes, &pods, &services)
+
+				Expect(errorList).To(ConsistOfFields(Fields{
+					"Type":  Equal(field.ErrorTypeInvalid),
+					"Field": Equal("networks.natGateway.eipAllocation.bandwidth"),
+				}, Fields{
+					"Type":  Equal(field.ErrorTypeNotSupported),
+					"Field": Equal("networks.natGateway.eipAllocation.internetChargeType"),
+				}))
+			})
+
+			It("should allow multiple EIPs for NAT gateways per zone", func() {
+				count := int32(20)
+				infrastructureConfig.Networks.NatGateway = &apisalicloud.NatGateway{
+					PerZone:       true,
+					EIPAllocation: &apisalicloud.EIPAllocation{Count: &count},
+				}
+
+				Expect(ValidateInfrastructureConfig(infrastructureConfig, &nodes, &pods, &services)).To(BeEmpty())
+			})
+
+			It("should forbid multiple EIPs for a shared NAT gateway", func() {
+				count := int32(2)
+				infrastructureConfig.Networks.NatGateway = &apisalicloud.NatGateway{
+					EIPAllocation: &apisalicloud.EIPAllocation{Count: &count},
+				}
+
+				errorList := ValidateInfrastructureConfig(infrastructureConfig, &nodes, &pods, &services)
+
+				Expect(errorList).To(ConsistOfFields(Fields{
+					"Type":  Equal(field.ErrorTypeForbidden),
+					"Field": Equal("networks.natGateway.eipAllocation.count"),
+				}))
+			})
+
+			It("should forbid an invalid number of EIPs", func() {
+				for _, count := range []int32{0, 21} {
+					c := count
+					infrastructureConfig.Networks.NatGateway = &apisalicloud.NatGateway{
+						PerZone:       true,
+						EIPAllocation: &apisalicloud.EIPAllocation{Count: &c},
+					}
+
+					errorList := ValidateInfrastructureConfig(infrastructureConfig, &nodes, &pods, &services)
+
+					Expect(errorList).To(ConsistOfFields(Fields{
+						"Type":  Equal(field.ErrorTypeInvalid),
+						"Field": Equal("networks.natGateway.eipAllocation.count"),
+					}))
+				}
+			})
+		})
+	})
+
+	Describe("#ValidateInfrastructureConfig with dual-stack", func() {
+		BeforeEach(func() {
+			infrastructureConfig.Networks.DualStack = &apisalicloud.DualStack{Enabled: true}
+		})
+
+		It("should forbid more zones than IPv6 CIDR masks are reserved for", func() {
+			for i := 1; i < 16; i++ {
+				infrastructureConfig.Networks.Zones = append(infrastructureConfig.Networks.Zones, apisalicloud.Zone{Name: fmt.Sprintf("zone%d", i+1), Workers: fmt.Sprintf("10.250.%d.0/24", 3+i)})
+			}
+
+			errorList := ValidateInfrastructureConfig(infrastructureConfig, &nodes, &pods, &services)
+
+			Expect(errorList).To(ContainElement(PointTo(MatchFields(IgnoreExtras, Fields{
+				"Type":  Equal(field.ErrorTypeTooMany),
+				"Field": Equal("networks.zones"),
+			}))))
+		})
+
+		It("should forbid more additional vswitches per zone than IPv6 CIDR masks are reserved for", func() {
+			for i := 0; i < 17; i++ {
+				infrastructureConfig.Networks.Zones[0].AdditionalWorkers = append(infrastructureConfig.Networks.Zones[0].AdditionalWorkers, fmt.Sprintf("10.250.%d.0/24", 4+i))
+			}
+
+			errorList := ValidateInfrastructureConfig(infrastructureConfig, &nodes, &pods, &services)
+
+			Expect(errorList).To(ContainElement(PointTo(MatchFields(IgnoreExtras, Fields{
+				"Type":  Equal(field.ErrorTypeTooMany),
+				"Field": Equal("networks.zones[0].additionalWorkers"),
+			}))))
+		})
+
+		It("should allow as many zones and additional vswitches without dual-stack", func() {
+			infrastructureConfig.Networks.DualStack = nil
+			for i := 0; i < 17; i++ {
+				infrastructureConfig.Networks.Zones[0].AdditionalWorkers = append(infrastructureConfig.Networks.Zones[0].AdditionalWorkers, fmt.Sprintf("10.250.%d.0/24", 4+i))
+			}
+
+			errorList := ValidateInfrastructureConfig(infrastructureConfig, &nodes, &pods, &services)
+
+			Expect(errorList).NotTo(ContainElement(PointTo(MatchFields(IgnoreExtras, Fields{
+				"Type": Equal(field.ErrorTypeTooMany),
+			}))))
+		})
+	})
+
+	Describe("#ValidateInfrastructureConfigAgainstRegion", func() {
+		It("should allow dual-stack in supported regions", func() {
+			infrastructureConfig.Networks.DualStack = &apisalicloud.DualStack{Enabled: true}
+
+			Expect(ValidateInfrastructureConfigAgainstRegion(infrastructureConfig, "cn-beijing")).To(BeEmpty())
+		})
+
+		It("should allow disabled dual-stack in any region", func() {
+			infrastructureConfig.Networks.DualStack = &apisalicloud.DualStack{Enabled: false}
+
+			Expect(ValidateInfrastructureConfigAgainstRegion(infrastructureConfig, "ap-south-1")).To(BeEmpty())
+		})
+
+		It("should forbid dual-stack in regions without IPv6 support", func() {
+			infrastructureConfig.Networks.DualStack = &apisalicloud.DualStack{Enabled: true}
+
+			errorList := ValidateInfrastructureConfigAgainstRegion(infrastructureConfig, "ap-south-1")
+
+			Expect(errorList).To(ConsistOfFields(Fields{
+				"Type":  Equal(field.ErrorTypeForbidden),
+				"Field": Equal("networks.dualStack.enabled"),
+			}))
+		})
 	})
 
 	Describe("#ValidateInfrastructureConfigUpdate", func() {
@@ -179,5 +962,64 @@ var _ = Describe("InfrastructureConfig validation", func() {
 				"Field": Equal("networks"),
 			}))))
 		})
+
+		It("should allow changing the security group rules", func() {
+			newInfrastructureConfig := infrastructureConfig.DeepCopy()
+			newInfrastructureConfig.Networks.SecurityGroupRules = []apisalicloud.SecurityGroupRule{
+				{Direction: apisalicloud.SecurityGroupRuleDirectionIngress, Protocol: "tcp", PortRange: "22/22", CIDR: "10.0.0.0/8"},
+			}
+
+			Expect(ValidateInfrastructureConfigUpdate(infrastructureConfig, newInfrastructureConfig, &nodes, &pods, &services)).To(BeEmpty())
+		})
+
+		It("should allow changing the routes", func() {
+			newInfrastructureConfig := infrastructureConfig.DeepCopy()
+			newInfrastructureConfig.Networks.Routes = []apisalicloud.Route{
+				{DestinationCIDR: "192.168.0.0/16", NextHopType: apisalicloud.RouteNextHopTypeRouterInterface, NextHopID: "ri-2ze7fbuohm6jd9a1xxxxx"},
+			}
+
+			Expect(ValidateInfrastructureConfigUpdate(infrastructureConfig, newInfrastructureConfig, &nodes, &pods, &services)).To(BeEmpty())
+		})
+
+		It("should allow changing the DNAT entries", func() {
+			newInfrastructureConfig := infrastructureConfig.DeepCopy()
+			newInfrastructureConfig.Networks.NatGateway = &apisalicloud.NatGateway{
+				DNATEntries: []apisalicloud.DNATEntry{
+					{ExternalIP: "47.95.1.1", ExternalPort: 22, InternalIP: "10.250.3.10", InternalPort: 30022, Protocol: "tcp"},
+				},
+			}
+
+			Expect(ValidateInfrastructureConfigUpdate(infrastructureConfig, newInfrastructureConfig, &nodes, &pods, &services)).To(BeEmpty())
+		})
+
+		It("should forbid changing the other NAT gateway settings together with the DNAT entries", func() {
+			newInfrastructureConfig := infrastructureConfig.DeepCopy()
+			newInfrastructureConfig.Networks.NatGateway = &apisalicloud.NatGateway{
+				PerZone: true,
+				DNATEntries: []apisalicloud.DNATEntry{
+					{ExternalIP: "47.95.1.1", ExternalPort: 22, InternalIP: "10.250.3.10", InternalPort: 30022, Protocol: "tcp"},
+				},
+			}
+
+			Expect(ValidateInfrastructureConfigUpdate(infrastructureConfig, newInfrastructureConfig, &nodes, &pods, &services)).To(ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
+				"Type":  Equal(field.ErrorTypeInvalid),
+				"Field": Equal("networks"),
+			}))))
+		})
+
+		It("should allow changing the DHCP options", func() {
+			newInfrastructureConfig := infrastructureConfig.DeepCopy()
+			newInfrastructureConfig.Networks.DHCPOptions = &apisalicloud.DHCPOptions{DomainNameServers: []string{"10.0.0.2"}}
+
+			Expect(ValidateInfrastructureConfigUpdate(infrastructureConfig, newInfrastructureConfig, &nodes, &pods, &services)).To(BeEmpty())
+		})
+
+		It("should allow enabling flow logs", func() {
+			newInfrastructureConfig := infrastructureConfig.DeepCopy()
+			newInfrastructureConfig.Networks.EnableFlowLogs = true
+			newInfrastructureConfig.Networks.FlowLogTarget = &apisalicloud.FlowLogTarget{ProjectName: "my-project", LogStoreName: "flow-logs"}
+
+			Expect(ValidateInfrastructureConfigUpdate(infrastructureConfig, newInfrastructureConfig, &nodes, &pods, &services)).To(BeEmpty())
+		})
 	})
 })
@@ -0,0 +1,661 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validation
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gardener/gardener-extension-provider-alicloud/pkg/alicloud"
+	apisalicloud "github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/alicloud"
+	"github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/alicloud/helper"
+
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	minDataVolumeSize = "20Gi"
+	// maxAdditionalSecurityGroups is the maximum number of security groups an instance can join in addition to the
+	// security group managed by Gardener.
+	maxAdditionalSecurityGroups = 4
+	// maxDrainTimeout is the maximum drain timeout of a worker pool.
+	maxDrainTimeout = 24 * time.Hour
+)
+
+var (
+	spotStrategies      = sets.NewString(string(apisalicloud.SpotStrategyAsPriceGo), string(apisalicloud.SpotStrategyWithPriceLimit))
+	placementStrategies = sets.NewString(string(apisalicloud.PlacementStrategySpread), string(apisalicloud.PlacementStrategyPack))
+
+	// essdDiskCategories are the disk categories which support performance levels.
+	essdDiskCategories = sets.NewString("cloud_essd")
+	performanceLevels  = sets.NewString("PL0", "PL1", "PL2", "PL3")
+
+	ramRoleNameRegex      = regexp.MustCompile(`^[a-zA-Z0-9.-]{1,64}$`)
+	gpuDriverVersionRegex = regexp.MustCompile(`^[0-9]+\.[0-9]+(\.[0-9]+)?$`)
+	percentRegex          = regexp.MustCompile(`^([0-9]+)%$`)
+
+	instanceChargeTypes = sets.NewString(string(apisalicloud.InstanceChargeTypePrePaid), string(apisalicloud.InstanceChargeTypePostPaid))
+	// periods are the subscription periods Alicloud supports per period unit.
+	periods = map[apisalicloud.PeriodUnit]sets.Int32{
+		apisalicloud.PeriodUnitWeek:  sets.NewInt32(1, 2, 3, 4),
+		apisalicloud.PeriodUnitMonth: sets.NewInt32(1, 2, 3, 4, 5, 6, 7, 8, 9, 12, 24, 36, 48, 60),
+	}
+
+	// defaultSystemDiskCategory is the disk category of system disks without a volume type.
+	defaultSystemDiskCategory = "cloud_efficiency"
+	// maxSystemDiskSizes are the maximum sizes of system disks Alicloud supports per disk category.
+	maxSystemDiskSizes = map[string]resource.Quantity{
+		"cloud":            resource.MustParse("500Gi"),
+		"cloud_efficiency": resource.MustParse("2048Gi"),
+		"cloud_ssd":        resource.MustParse("2048Gi"),
+		"cloud_essd":       resource.MustParse("2048Gi"),
+	}
+
+	// evictionSignals are the eviction signals of the kubelet which can be configured per worker pool.
+	evictionSignals = sets.NewString("memory.available", "nodefs.available", "nodefs.inodesFree", "imagefs.available", "imagefs.inodesFree", "pid.available")
+	// kubeReservedResources are the resources which can be reserved for the Kubernetes components per worker pool.
+	kubeReservedResources = sets.NewString("cpu", "memory", "ephemeral-storage", "pid")
+
+	autoRenewPeriods = map[apisalicloud.PeriodUnit]sets.Int32{
+		apisalicloud.PeriodUnitWeek:  sets.NewInt32(1, 2, 3),
+		apisalicloud.PeriodUnitMonth: sets.NewInt32(1, 2, 3, 6, 12, 24, 36, 48, 60),
+	}
+)
+
+// reservedWorkerTagPrefixes are the prefixes of the tags which Gardener uses to identify the machines of a cluster.
+var reservedWorkerTagPrefixes = []string{"kubernetes.io/cluster/", "kubernetes.io/role/"}
+
+// ValidateWorkerConfig validates a WorkerConfig object.
+func ValidateWorkerConfig(workerConfig *apisalicloud.WorkerConfig) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if workerConfig.ImageID != nil && len(*workerConfig.ImageID) == 0 {
+		allErrs = append(allErrs, field.Required(field.NewPath("imageID"), "must not be empty if set"))
+	}
+
+	if systemDisk := workerConfig.SystemDisk; systemDisk != nil {
+		systemDiskPath := field.NewPath("systemDisk")
+		if systemDisk.KMSKeyID != nil {
+			if !systemDisk.Encrypted {
+				allErrs = append(allErrs, field.Forbidden(systemDiskPath.Child("kmsKeyID"), "must only be set if the system disk is encrypted"))
+			} else if len(*systemDisk.KMSKeyID) == 0 {
+				allErrs = append(allErrs, field.Required(systemDiskPath.Child("kmsKeyID"), "must not be empty if set"))
+			}
+		}
+	}
+
+	allErrs = append(allErrs, validateDataVolumes(workerConfig.DataVolumes, field.NewPath("dataVolumes"))...)
+	allErrs = append(allErrs, validateSpotStrategy(workerConfig.SpotStrategy, workerConfig.SpotPriceLimit)...)
+	allErrs = append(allErrs, validateInstanceChargeType(workerConfig)...)
+
+	if deploymentSetID := workerConfig.DeploymentSetID; deploymentSetID != nil {
+		deploymentSetIDPath := field.NewPath("deploymentSetID")
+		if len(*deploymentSetID) == 0 {
+			allErrs = append(allErrs, field.Required(deploymentSetIDPath, "must not be empty if set"))
+		}
+		if workerConfig.SpotStrategy != nil {
+			allErrs = append(allErrs, field.Forbidden(deploymentSetIDPath, "spot instances cannot join deployment sets"))
+		}
+	}
+
+	if placementStrategy := workerConfig.PlacementStrategy; placementStrategy != nil {
+		placementStrategyPath := field.NewPath("placementStrategy")
+		if !placementStrategies.Has(string(*placementStrategy)) {
+			allErrs = append(allErrs, field.NotSupported(placementStrategyPath, *placementStrategy, placementStrategies.List()))
+		}
+		if workerConfig.DeploymentSetID == nil {
+			allErrs = append(allErrs, field.Forbidden(placementStrategyPath, "must only be set together with a deployment set"))
+		}
+	}
+
+	allErrs = append(allErrs, validateSecurityGroupIDs(workerConfig.SecurityGroupIDs, field.NewPath("securityGroupIDs"))...)
+
+	if userData := workerConfig.UserData; userData != nil {
+		allErrs = append(allErrs, validateUserData(*userData, field.NewPath("userData"))...)
+	}
+
+	if ramRoleName := workerConfig.RAMRoleName; ramRoleName != nil && !ramRoleNameRegex.MatchString(*ramRoleName) {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("ramRoleName"), *ramRoleName, "must be 1 to 64 characters long and consist of letters, digits, periods, and hyphens"))
+	}
+
+	if gpuDriverVersion := workerConfig.GPUDriverVersion; gpuDriverVersion != nil && !gpuDriverVersionRegex.MatchString(*gpuDriverVersion) {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("gpuDriverVersion"), *gpuDriverVersion, "must be a version of the NVIDIA driver, e.g. 470.57.02"))
+	}
+
+	if drainTimeout := workerConfig.DrainTimeout; drainTimeout != nil && (drainTimeout.Duration <= 0 || drainTimeout.Duration > maxDrainTimeout) {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("drainTimeout"), drainTimeout.Duration.String(), fmt.Sprintf("must be positive and at most %s", maxDrainTimeout)))
+	}
+
+	if maxSurge := workerConfig.MaxSurge; maxSurge != nil {
+		allErrs = append(allErrs, validateIntOrPercent(*maxSurge, false, field.NewPath("maxSurge"))...)
+	}
+	if maxUnavailable := workerConfig.MaxUnavailable; maxUnavailable != nil {
+		allErrs = append(allErrs, validateIntOrPercent(*maxUnavailable, true, field.NewPath("maxUnavailable"))...)
+	}
+
+	if kubeletConfig := workerConfig.KubeletConfig; kubeletConfig != nil {
+		allErrs = append(allErrs, validateKubeletConfig(kubeletConfig, field.NewPath("kubeletConfig"))...)
+	}
+
+	tagsPath := field.NewPath("tags")
+	allErrs = append(allErrs, validateTags(workerConfig.Tags, tagsPath)...)
+	for key := range workerConfig.Tags {
+		for _, prefix := range reservedWorkerTagPrefixes {
+			if strings.HasPrefix(key, prefix) {
+				allErrs = append(allErrs, field.Invalid(tagsPath.Key(key), key, fmt.Sprintf("key must not start with %q", prefix)))
+			}
+		}
+	}
+
+	return allErrs
+}
+
+// ValidateWorkerMachineImage validates that the image of a worker pool can be resolved, either by the image ID of the
+// given WorkerConfig or by the given machine image name and version in the CloudProfileConfig. Without version, the
+// default machine image of the region is used. The version has to map the region to an image ID or carry an image name
+// which can be looked up in the region.
+func ValidateWorkerMachineImage(workerConfig *apisalicloud.WorkerConfig, imageName, imageVersion, region string, cloudProfileConfig *apisalicloud.CloudProfileConfig, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if workerConfig != nil && workerConfig.ImageID != nil {
+		return allErrs
+	}
+
+	imageName, imageVersion = helper.ResolveMachineImage(cloudProfileConfig, imageName, imageVersion, region)
+	if len(imageName) == 0 || len(imageVersion) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath, fmt.Sprintf("must provide a machine image name and version or an image ID in the provider config unless the cloud profile has a default machine image for region %q", region)))
+	} else if _, err := helper.FindImageForRegionFromCloudProfile(cloudProfileConfig, imageName, imageVersion, region); err != nil {
+		if version, err := helper.FindMachineImageVersion(cloudProfileConfig, imageName, imageVersion); err == nil && version.ImageName != nil {
+			return allErrs
+		}
+		allErrs = append(allErrs, field.Invalid(fldPath, fmt.Sprintf("%s/%s", imageName, imageVersion), fmt.Sprintf("must be available in region %q of the cloud profile or an image ID must be provided in the provider config", region)))
+	}
+
+	return allErrs
+}
+
+// ValidateWorkerMachineImageDeprecation checks whether the machine image version of a worker pool is deprecated or
+// expired according to the given CloudProfileConfig. Deprecated versions only result in a warning unless the
+// CloudProfileConfig blocks them, expired versions are always forbidden. The messages name the recommended
+// replacement, if any. Worker pools which use an image ID instead of a machine image of the cloud profile are not
+// checked.
+func ValidateWorkerMachineImageDeprecation(workerConfig *apisalicloud.WorkerConfig, imageName, imageVersion string, cloudProfileConfig *apisalicloud.CloudProfileConfig, now time.Time, fldPath *field.Path) ([]string, field.ErrorList) {
+	var (
+		warnings []string
+		allErrs  = field.ErrorList{}
+	)
+
+	if workerConfig != nil && workerConfig.ImageID != nil {
+		return warnings, allErrs
+	}
+
+	version, err := helper.FindMachineImageVersion(cloudProfileConfig, imageName, imageVersion)
+	if err != nil {
+		return warnings, allErrs
+	}
+
+	var recommendation string
+	if version.Replacement != nil {
+		recommendation = fmt.Sprintf(", please update to version %s", *version.Replacement)
+	}
+
+	value := fmt.Sprintf("%s/%s", imageName, imageVersion)
+	if version.ExpirationDate != nil && !now.Before(version.ExpirationDate.Time) {
+		return warnings, append(allErrs, field.Forbidden(fldPath, fmt.Sprintf("machine image %s has expired on %s%s", value, version.ExpirationDate.Format("2006-01-02"), recommendation)))
+	}
+
+	if isDeprecated(*version) {
+		message := fmt.Sprintf("machine image %s is deprecated%s", value, recommendation)
+		if policy := cloudProfileConfig.DeprecatedMachineImagePolicy; policy != nil && *policy == apisalicloud.DeprecatedMachineImagePolicyBlock {
+			allErrs = append(allErrs, field.Forbidden(fldPath, message))
+		} else {
+			warnings = append(warnings, fmt.Sprintf("%s: %s", fldPath, message))
+		}
+	}
+
+	return warnings, allErrs
+}
+
+// ValidateWorkerMachineImageIPVSSupport validates that the machine image version of a worker pool supports the IPVS
+// proxy mode of kube-proxy according to the given CloudProfileConfig. Worker pools which use an image ID instead of a
+// machine image of the cloud profile are not checked.
+func ValidateWorkerMachineImageIPVSSupport(workerConfig *apisalicloud.WorkerConfig, imageName, imageVersion string, cloudProfileConfig *apisalicloud.CloudProfileConfig, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if workerConfig != nil && workerConfig.ImageID != nil {
+		return allErrs
+	}
+
+	version, err := helper.FindMachineImageVersion(cloudProfileConfig, imageName, imageVersion)
+	if err != nil {
+		return allErrs
+	}
+
+	if version.SupportsIPVS != nil && !*version.SupportsIPVS {
+		allErrs = append(allErrs, field.Forbidden(fldPath, fmt.Sprintf("machine image %s/%s does not support the IPVS proxy mode of kube-proxy", imageName, imageVersion)))
+	}
+
+	return allErrs
+}
+
+// ValidateWorkerMachineType validates that the settings of the given WorkerConfig are supported by the given machine
+// type of the worker pool.
+func ValidateWorkerMachineType(workerConfig *apisalicloud.WorkerConfig, machineType string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if workerConfig != nil && workerConfig.UseLocalDisk && !helper.HasLocalNVMeDisks(machineType) {
+		allErrs = append(allErrs, field.Forbidden(fldPath, fmt.Sprintf("machine type %q has no local NVMe disks which could be used", machineType)))
+	}
+	if workerConfig != nil && workerConfig.PlacementStrategy != nil && *workerConfig.PlacementStrategy == apisalicloud.PlacementStrategyPack && helper.IsSharedInstanceType(machineType) {
+		allErrs = append(allErrs, field.Forbidden(fldPath, fmt.Sprintf("machine type %q is a shared instance type which cannot be packed into a deployment set", machineType)))
+	}
+	if workerConfig != nil && workerConfig.GPUDriverVersion != nil && !helper.IsGPUInstanceType(machineType) {
+		allErrs = append(allErrs, field.Forbidden(fldPath, fmt.Sprintf("machine type %q has no GPUs which a driver could be installed for", machineType)))
+	}
+
+	return allErrs
+}
+
+// ValidateWorkerRollingUpdate validates that the given max surge and max unavailable of a worker pool, which the
+// WorkerConfig may overwrite, allow rolling updates of the machines of the pool with the given minimum and maximum.
+// The rolling update would be stuck if neither a machine could be created in addition nor be unavailable with the
+// least number of machines of the pool.
+func ValidateWorkerRollingUpdate(maxSurge, maxUnavailable intstr.IntOrString, minimum, maximum int, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if maxUnavailable.Type == intstr.Int && int(maxUnavailable.IntVal) > maximum {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("maxUnavailable"), maxUnavailable.String(), fmt.Sprintf("must not be greater than the maximum %d of the worker pool", maximum)))
+	}
+
+	replicas := minimum
+	if replicas < 1 {
+		replicas = 1
+	}
+	surge, err := intstr.GetValueFromIntOrPercent(&maxSurge, replicas, true)
+	if err != nil {
+		return append(allErrs, field.Invalid(fldPath.Child("maxSurge"), maxSurge.String(), err.Error()))
+	}
+	unavailable, err := intstr.GetValueFromIntOrPercent(&maxUnavailable, replicas, false)
+	if err != nil {
+		return append(allErrs, field.Invalid(fldPath.Child("maxUnavailable"), maxUnavailable.String(), err.Error()))
+	}
+	if surge == 0 && unavailable == 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("maxUnavailable"), maxUnavailable.String(), fmt.Sprintf("must not be 0 for %d machines if maxSurge is 0", replicas)))
+	}
+
+	return allErrs
+}
+
+// ValidateWorkerVolume validates the size of the system disk of a worker pool. It must not be smaller than the given
+// minimum size, which is the size its machine image requires, and not larger than the maximum size Alicloud supports
+// for its disk category.
+func ValidateWorkerVolume(volumeType *string, volumeSize string, minimumSize resource.Quantity, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	sizePath := fldPath.Child("size")
+
+	size, err := resource.ParseQuantity(volumeSize)
+	if err != nil {
+		return append(allErrs, field.Invalid(sizePath, volumeSize, fmt.Sprintf("must be a quantity: %v", err)))
+	}
+
+	if size.Cmp(minimumSize) < 0 {
+		allErrs = append(allErrs, field.Invalid(sizePath, volumeSize, fmt.Sprintf("must be at least %s as required by the machine image", minimumSize.String())))
+	}
+
+	category := defaultSystemDiskCategory
+	if volumeType != nil {
+		category = *volumeType
+	}
+	if maxSize, ok := maxSystemDiskSizes[category]; ok && size.Cmp(maxSize) > 0 {
+		allErrs = append(allErrs, field.Invalid(sizePath, volumeSize, fmt.Sprintf("must not be larger than %s for disk category %s", maxSize.String(), category)))
+	}
+
+	return allErrs
+}
+
+func validateDataVolumes(dataVolumes []apisalicloud.DataVolume, fldPath *field.Path) field.ErrorList {
+	var (
+		allErrs = field.ErrorList{}
+		names   = sets.NewString()
+		minSize = resource.MustParse(minDataVolumeSize)
+	)
+
+	for i, dataVolume := range dataVolumes {
+		idxPath := fldPath.Index(i)
+
+		if len(dataVolume.Name) == 0 {
+			allErrs = append(allErrs, field.Required(idxPath.Child("name"), "must provide a name"))
+		} else {
+			for _, msg := range validation.IsDNS1123Label(dataVolume.Name) {
+				allErrs = append(allErrs, field.Invalid(idxPath.Child("name"), dataVolume.Name, msg))
+			}
+			if names.Has(dataVolume.Name) {
+				allErrs = append(allErrs, field.Duplicate(idxPath.Child("name"), dataVolume.Name))
+			}
+			names.Insert(dataVolume.Name)
+		}
+
+		if size, err := resource.ParseQuantity(dataVolume.Size); err != nil {
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("size"), dataVolume.Size, fmt.Sprintf("must be a quantity: %v", err)))
+		} else if size.Cmp(minSize) < 0 {
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("size"), dataVolume.Size, fmt.Sprintf("must be at least %s", minDataVolumeSize)))
+		}
+
+		if dataVolume.PerformanceLevel != nil {
+			performanceLevelPath := idxPath.Child("performanceLevel")
+			if dataVolume.Type == nil || !essdDiskCategories.Has(*dataVolume.Type) {
+				allErrs = append(allErrs, field.Forbidden(performanceLevelPath, fmt.Sprintf("must only be set for the disk categories %v", essdDiskCategories.List())))
+			} else if !performanceLevels.Has(*dataVolume.PerformanceLevel) {
+				allErrs = append(allErrs, field.NotSupported(performanceLevelPath, *dataVolume.PerformanceLevel, performanceLevels.List()))
+			}
+		}
+	}
+
+	return allErrs
+}
+
+func validateSecurityGroupIDs(securityGroupIDs []string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if len(securityGroupIDs) > maxAdditionalSecurityGroups {
+		allErrs = append(allErrs, field.TooMany(fldPath, len(securityGroupIDs), maxAdditionalSecurityGroups))
+	}
+
+	ids := sets.NewString()
+	for i, id := range securityGroupIDs {
+		if len(id) == 0 {
+			allErrs = append(allErrs, field.Required(fldPath.Index(i), "must not be empty"))
+			continue
+		}
+		if ids.Has(id) {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Index(i), id))
+		}
+		ids.Insert(id)
+	}
+
+	return allErrs
+}
+
+func validateInstanceChargeType(workerConfig *apisalicloud.WorkerConfig) field.ErrorList {
+	var (
+		allErrs                = field.ErrorList{}
+		instanceChargeTypePath = field.NewPath("instanceChargeType")
+		periodPath             = field.NewPath("period")
+		periodUnitPath         = field.NewPath("periodUnit")
+		autoRenewPath          = field.NewPath("autoRenew")
+		autoRenewPeriodPath    = field.NewPath("autoRenewPeriod")
+	)
+
+	prePaid := workerConfig.InstanceChargeType != nil && *workerConfig.InstanceChargeType == apisalicloud.InstanceChargeTypePrePaid
+	if instanceChargeType := workerConfig.InstanceChargeType; instanceChargeType != nil && !instanceChargeTypes.Has(string(*instanceChargeType)) {
+		allErrs = append(allErrs, field.NotSupported(instanceChargeTypePath, *instanceChargeType, instanceChargeTypes.List()))
+	}
+	if prePaid && workerConfig.SpotStrategy != nil {
+		allErrs = append(allErrs, field.Forbidden(instanceChargeTypePath, "spot instances must be PostPaid"))
+	}
+
+	if !prePaid {
+		if workerConfig.Period != nil {
+			allErrs = append(allErrs, field.Forbidden(periodPath, fmt.Sprintf("must only be set for instance charge type %q", apisalicloud.InstanceChargeTypePrePaid)))
+		}
+		if workerConfig.PeriodUnit != nil {
+			allErrs = append(allErrs, field.Forbidden(periodUnitPath, fmt.Sprintf("must only be set for instance charge type %q", apisalicloud.InstanceChargeTypePrePaid)))
+		}
+		if workerConfig.AutoRenew {
+			allErrs = append(allErrs, field.Forbidden(autoRenewPath, fmt.Sprintf("must only be set for instance charge type %q", apisalicloud.InstanceChargeTypePrePaid)))
+		}
+		if workerConfig.AutoRenewPeriod != nil {
+			allErrs = append(allErrs, field.Forbidden(autoRenewPeriodPath, fmt.Sprintf("must only be set for instance charge type %q", apisalicloud.InstanceChargeTypePrePaid)))
+		}
+		return allErrs
+	}
+
+	periodUnit := apisalicloud.PeriodUnitMonth
+	if workerConfig.PeriodUnit != nil {
+		periodUnit = *workerConfig.PeriodUnit
+	}
+	supportedPeriods, ok := periods[periodUnit]
+	if !ok {
+		return append(allErrs, field.NotSupported(periodUnitPath, periodUnit, []string{string(apisalicloud.PeriodUnitWeek), string(apisalicloud.PeriodUnitMonth)}))
+	}
+	if period := workerConfig.Period; period != nil && !supportedPeriods.Has(*period) {
+		allErrs = append(allErrs, field.Invalid(periodPath, *period, fmt.Sprintf("must be one of %v for period unit %q", supportedPeriods.List(), periodUnit)))
+	}
+
+	if autoRenewPeriod := workerConfig.AutoRenewPeriod; autoRenewPeriod != nil {
+		if !workerConfig.AutoRenew {
+			allErrs = append(allErrs, field.Forbidden(autoRenewPeriodPath, "must only be set if autoRenew is enabled"))
+		} else if supportedAutoRenewPeriods := autoRenewPeriods[periodUnit]; !supportedAutoRenewPeriods.Has(*autoRenewPeriod) {
+			allErrs = append(allErrs, field.Invalid(autoRenewPeriodPath, *autoRenewPeriod, fmt.Sprintf("must be one of %v for period unit %q", supportedAutoRenewPeriods.List(), periodUnit)))
+		}
+	}
+
+	return allErrs
+}
+
+func validateIntOrPercent(value intstr.IntOrString, maxHundredPercent bool, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if value.Type == intstr.Int {
+		if value.IntVal < 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath, value.String(), "must not be negative"))
+		}
+		return allErrs
+	}
+
+	match := percentRegex.FindStringSubmatch(value.StrVal)
+	if match == nil {
+		return append(allErrs, field.Invalid(fldPath, value.String(), "must be a non-negative number or a percentage, e.g. 10%"))
+	}
+	if percent, _ := strconv.Atoi(match[1]); maxHundredPercent && percent > 100 {
+		allErrs = append(allErrs, field.Invalid(fldPath, value.String(), "must not be greater than 100%"))
+	}
+
+	return allErrs
+}
+
+// ValidateWorkerKubeletConfig validates the kubelet settings of a worker pool. None of them must be set in one of the
+// given kubelet configurations of the shoot as well, as it would be ambiguous which value applies to the pool.
+func ValidateWorkerKubeletConfig(kubeletConfig *apisalicloud.KubeletConfig, fldPath *field.Path, shootKubeletConfigs ...*gardencorev1beta1.KubeletConfig) field.ErrorList {
+	allErrs := validateKubeletConfig(kubeletConfig, fldPath)
+
+	conflicts := sets.NewString()
+	for _, shootKubeletConfig := range shootKubeletConfigs {
+		if shootKubeletConfig == nil {
+			continue
+		}
+		if kubeletConfig.MaxPods != nil && shootKubeletConfig.MaxPods != nil {
+			conflicts.Insert(fldPath.Child("maxPods").String())
+		}
+		for signal := range kubeletConfig.EvictionHard {
+			if shootEvictionSignals(shootKubeletConfig.EvictionHard).Has(signal) {
+				conflicts.Insert(fldPath.Child("evictionHard").Key(signal).String())
+			}
+		}
+		for signal := range kubeletConfig.EvictionSoft {
+			if shootEvictionSignals(shootKubeletConfig.EvictionSoft).Has(signal) {
+				conflicts.Insert(fldPath.Child("evictionSoft").Key(signal).String())
+			}
+		}
+		for signal := range kubeletConfig.EvictionSoftGracePeriod {
+			if shootEvictionSoftGracePeriodSignals(shootKubeletConfig.EvictionSoftGracePeriod).Has(signal) {
+				conflicts.Insert(fldPath.Child("evictionSoftGracePeriod").Key(signal).String())
+			}
+		}
+	}
+	for _, conflict := range conflicts.List() {
+		allErrs = append(allErrs, &field.Error{Type: field.ErrorTypeForbidden, Field: conflict, Detail: "must not be set as it is also set in the kubelet configuration of the shoot"})
+	}
+
+	return allErrs
+}
+
+func validateKubeletConfig(kubeletConfig *apisalicloud.KubeletConfig, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if maxPods := kubeletConfig.MaxPods; maxPods != nil && *maxPods <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("maxPods"), *maxPods, "must be positive"))
+	}
+
+	allErrs = append(allErrs, validateEvictionThresholds(kubeletConfig.EvictionHard, fldPath.Child("evictionHard"))...)
+	allErrs = append(allErrs, validateEvictionThresholds(kubeletConfig.EvictionSoft, fldPath.Child("evictionSoft"))...)
+
+	gracePeriodPath := fldPath.Child("evictionSoftGracePeriod")
+	for _, signal := range sets.StringKeySet(kubeletConfig.EvictionSoftGracePeriod).List() {
+		value := kubeletConfig.EvictionSoftGracePeriod[signal]
+		if !evictionSignals.Has(signal) {
+			allErrs = append(allErrs, field.NotSupported(gracePeriodPath.Key(signal), signal, evictionSignals.List()))
+		} else if gracePeriod, err := time.ParseDuration(value); err != nil || gracePeriod <= 0 {
+			allErrs = append(allErrs, field.Invalid(gracePeriodPath.Key(signal), value, "must be a positive duration, e.g. 1m30s"))
+		}
+	}
+	for _, signal := range sets.StringKeySet(kubeletConfig.EvictionSoft).List() {
+		if _, ok := kubeletConfig.EvictionSoftGracePeriod[signal]; !ok && evictionSignals.Has(signal) {
+			allErrs = append(allErrs, field.Required(gracePeriodPath.Key(signal), "must be set for every soft eviction threshold"))
+		}
+	}
+
+	kubeReservedPath := fldPath.Child("kubeReserved")
+	for _, name := range sets.StringKeySet(kubeletConfig.KubeReserved).List() {
+		value := kubeletConfig.KubeReserved[name]
+		if !kubeReservedResources.Has(name) {
+			allErrs = append(allErrs, field.NotSupported(kubeReservedPath.Key(name), name, kubeReservedResources.List()))
+		} else if quantity, err := resource.ParseQuantity(value); err != nil || quantity.Sign() < 0 {
+			allErrs = append(allErrs, field.Invalid(kubeReservedPath.Key(name), value, "must be a non-negative quantity"))
+		}
+	}
+
+	return allErrs
+}
+
+// validateEvictionThresholds validates eviction thresholds by eviction signal. A threshold is either a quantity or a
+// percentage.
+func validateEvictionThresholds(thresholds map[string]string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	for _, signal := range sets.StringKeySet(thresholds).List() {
+		value := thresholds[signal]
+		if !evictionSignals.Has(signal) {
+			allErrs = append(allErrs, field.NotSupported(fldPath.Key(signal), signal, evictionSignals.List()))
+			continue
+		}
+		if match := percentRegex.FindStringSubmatch(value); match != nil {
+			if percent, _ := strconv.Atoi(match[1]); percent > 100 {
+				allErrs = append(allErrs, field.Invalid(fldPath.Key(signal), value, "must not be greater than 100%"))
+			}
+		} else if quantity, err := resource.ParseQuantity(value); err != nil || quantity.Sign() < 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Key(signal), value, "must be a non-negative quantity or a percentage, e.g. 500Mi or 10%"))
+		}
+	}
+
+	return allErrs
+}
+
+// shootEvictionSignals returns the eviction signals with thresholds in the given kubelet eviction config of the shoot.
+func shootEvictionSignals(eviction *gardencorev1beta1.KubeletConfigEviction) sets.String {
+	signals := sets.NewString()
+	if eviction == nil {
+		return signals
+	}
+	for signal, value := range map[string]*string{
+		"memory.available":   eviction.MemoryAvailable,
+		"nodefs.available":   eviction.NodeFSAvailable,
+		"nodefs.inodesFree":  eviction.NodeFSInodesFree,
+		"imagefs.available":  eviction.ImageFSAvailable,
+		"imagefs.inodesFree": eviction.ImageFSInodesFree,
+	} {
+		if value != nil {
+			signals.Insert(signal)
+		}
+	}
+	return signals
+}
+
+// shootEvictionSoftGracePeriodSignals returns the eviction signals with grace periods in the given kubelet config of
+// the shoot.
+func shootEvictionSoftGracePeriodSignals(gracePeriod *gardencorev1beta1.KubeletConfigEvictionSoftGracePeriod) sets.String {
+	signals := sets.NewString()
+	if gracePeriod == nil {
+		return signals
+	}
+	for signal, value := range map[string]*metav1.Duration{
+		"memory.available":   gracePeriod.MemoryAvailable,
+		"nodefs.available":   gracePeriod.NodeFSAvailable,
+		"nodefs.inodesFree":  gracePeriod.NodeFSInodesFree,
+		"imagefs.available":  gracePeriod.ImageFSAvailable,
+		"imagefs.inodesFree": gracePeriod.ImageFSInodesFree,
+	} {
+		if value != nil {
+			signals.Insert(signal)
+		}
+	}
+	return signals
+}
+
+func validateUserData(userData string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	switch {
+	case len(userData) == 0:
+		allErrs = append(allErrs, field.Required(fldPath, "must not be empty if set"))
+	case len(userData) > alicloud.MaxUserDataSize:
+		allErrs = append(allErrs, field.TooLong(fldPath, "", alicloud.MaxUserDataSize))
+	case strings.HasPrefix(userData, "#cloud-config"):
+		var cloudConfig map[string]interface{}
+		if err := yaml.Unmarshal([]byte(userData), &cloudConfig); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath, "", fmt.Sprintf("must be a valid cloud-config: %v", err)))
+		}
+	case !strings.HasPrefix(userData, "#!"):
+		allErrs = append(allErrs, field.Invalid(fldPath, "", "must be a cloud-config starting with '#cloud-config' or a shell script starting with '#!'"))
+	}
+
+	return allErrs
+}
+
+func validateSpotStrategy(spotStrategy *apisalicloud.SpotStrategy, spotPriceLimit *string) field.ErrorList {
+	var (
+		allErrs            = field.ErrorList{}
+		spotStrategyPath   = field.NewPath("spotStrategy")
+		spotPriceLimitPath = field.NewPath("spotPriceLimit")
+	)
+
+	if spotStrategy != nil && !spotStrategies.Has(string(*spotStrategy)) {
+		allErrs = append(allErrs, field.NotSupported(spotStrategyPath, *spotStrategy, spotStrategies.List()))
+	}
+
+	withPriceLimit := spotStrategy != nil && *spotStrategy == apisalicloud.SpotStrategyWithPriceLimit
+	switch {
+	case spotPriceLimit == nil && withPriceLimit:
+		allErrs = append(allErrs, field.Required(spotPriceLimitPath, fmt.Sprintf("must be set for spot strategy %q", apisalicloud.SpotStrategyWithPriceLimit)))
+	case spotPriceLimit != nil && !withPriceLimit:
+		allErrs = append(allErrs, field.Forbidden(spotPriceLimitPath, fmt.Sprintf("must only be set for spot strategy %q", apisalicloud.SpotStrategyWithPriceLimit)))
+	case spotPriceLimit != nil:
+		if price, err := strconv.ParseFloat(*spotPriceLimit, 64); err != nil || price <= 0 {
+			allErrs = append(allErrs, field.Invalid(spotPriceLimitPath, *spotPriceLimit, "must be a positive decimal number"))
+		}
+	}
+
+	return allErrs
+}
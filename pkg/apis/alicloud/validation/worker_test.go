@@ -0,0 +1,1049 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validation_test
+
+import (
+	"strings"
+	"time"
+
+	apisalicloud "github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/alicloud"
+	. "github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/alicloud/validation"
+
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+	"github.com/onsi/gomega/types"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/pointer"
+)
+
+var _ = Describe("WorkerConfig validation", func() {
+	var (
+		kmsKeyID = "kms-key"
+
+		workerConfig *apisalicloud.WorkerConfig
+	)
+
+	BeforeEach(func() {
+		workerConfig = &apisalicloud.WorkerConfig{
+			SystemDisk: &apisalicloud.SystemDisk{
+				Encrypted: true,
+				KMSKeyID:  &kmsKeyID,
+			},
+		}
+	})
+
+	Describe("#ValidateWorkerMachineImage", func() {
+		var (
+			fldPath            = field.NewPath("machine", "image")
+			imageID            = "m-custom"
+			cloudProfileConfig = &apisalicloud.CloudProfileConfig{
+				MachineImages: []apisalicloud.MachineImages{
+					{
+						Name: "coreos",
+						Versions: []apisalicloud.MachineImageVersion{
+							{
+								Version: "2023.4.0",
+								Regions: []apisalicloud.RegionIDMapping{{Name: "cn-shanghai", ID: "coreos_2023_4_0_64_30G_alibase_20190319.vhd"}},
+							},
+						},
+					},
+				},
+			}
+		)
+
+		It("should allow a machine image of the cloud profile", func() {
+			Expect(ValidateWorkerMachineImage(workerConfig, "coreos", "2023.4.0", "cn-shanghai", cloudProfileConfig, fldPath)).To(BeEmpty())
+		})
+
+		It("should allow an image ID instead of a machine image of the cloud profile", func() {
+			workerConfig.ImageID = &imageID
+
+			Expect(ValidateWorkerMachineImage(workerConfig, "", "", "cn-shanghai", cloudProfileConfig, fldPath)).To(BeEmpty())
+			Expect(ValidateWorkerMachineImage(workerConfig, "coreos", "2023.4.0", "eu-central-1", cloudProfileConfig, fldPath)).To(BeEmpty())
+		})
+
+		It("should require a machine image or an image ID", func() {
+			errorList := ValidateWorkerMachineImage(nil, "", "", "cn-shanghai", cloudProfileConfig, fldPath)
+
+			Expect(errorList).To(ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
+				"Type":  Equal(field.ErrorTypeRequired),
+				"Field": Equal("machine.image"),
+			}))))
+		})
+
+		It("should forbid machine images which are not available in the region", func() {
+			errorList := ValidateWorkerMachineImage(workerConfig, "coreos", "2023.4.0", "eu-central-1", cloudProfileConfig, fldPath)
+
+			Expect(errorList).To(ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
+				"Type":  Equal(field.ErrorTypeInvalid),
+				"Field": Equal("machine.image"),
+			}))))
+		})
+
+		It("should allow machine images which can be looked up by their name in the region", func() {
+			imageName := "coreos_2023_4_0_64_30G_alibase_20190319.vhd"
+			config := cloudProfileConfig.DeepCopy()
+			config.MachineImages[0].Versions[0].ImageName = &imageName
+
+			Expect(ValidateWorkerMachineImage(workerConfig, "coreos", "2023.4.0", "eu-central-1", config, fldPath)).To(BeEmpty())
+		})
+
+		It("should allow worker pools without machine image in regions with a default machine image", func() {
+			config := cloudProfileConfig.DeepCopy()
+			config.DefaultMachineImages = []apisalicloud.DefaultMachineImage{{Region: "cn-shanghai", Name: "coreos", Version: "2023.4.0"}}
+
+			Expect(ValidateWorkerMachineImage(nil, "", "", "cn-shanghai", config, fldPath)).To(BeEmpty())
+			Expect(ValidateWorkerMachineImage(nil, "coreos", "", "cn-shanghai", config, fldPath)).To(BeEmpty())
+		})
+
+		It("should require a machine image in regions without a matching default machine image", func() {
+			config := cloudProfileConfig.DeepCopy()
+			config.DefaultMachineImages = []apisalicloud.DefaultMachineImage{{Region: "cn-shanghai", Name: "coreos", Version: "2023.4.0"}}
+
+			Expect(ValidateWorkerMachineImage(nil, "", "", "eu-central-1", config, fldPath)).To(ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
+				"Type":  Equal(field.ErrorTypeRequired),
+				"Field": Equal("machine.image"),
+			}))))
+			Expect(ValidateWorkerMachineImage(nil, "ubuntu", "", "cn-shanghai", config, fldPath)).To(ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
+				"Type":  Equal(field.ErrorTypeRequired),
+				"Field": Equal("machine.image"),
+			}))))
+		})
+	})
+
+	Describe("#ValidateWorkerMachineImageDeprecation", func() {
+		var (
+			fldPath            = field.NewPath("machine", "image")
+			now                = time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+			replacement        = "2191.5.0"
+			cloudProfileConfig *apisalicloud.CloudProfileConfig
+		)
+
+		BeforeEach(func() {
+			deprecated := apisalicloud.ClassificationDeprecated
+			cloudProfileConfig = &apisalicloud.CloudProfileConfig{
+				MachineImages: []apisalicloud.MachineImages{
+					{
+						Name: "coreos",
+						Versions: []apisalicloud.MachineImageVersion{
+							{
+								Version:        "2023.4.0",
+								Regions:        []apisalicloud.RegionIDMapping{{Name: "cn-shanghai", ID: "coreos_2023_4_0"}},
+								Classification: &deprecated,
+								Replacement:    &replacement,
+							},
+							{
+								Version: replacement,
+								Regions: []apisalicloud.RegionIDMapping{{Name: "cn-shanghai", ID: "coreos_2191_5_0"}},
+							},
+						},
+					},
+				},
+			}
+		})
+
+		It("should accept supported versions", func() {
+			warnings, errorList := ValidateWorkerMachineImageDeprecation(workerConfig, "coreos", replacement, cloudProfileConfig, now, fldPath)
+			Expect(warnings).To(BeEmpty())
+			Expect(errorList).To(BeEmpty())
+		})
+
+		It("should warn about deprecated versions by default", func() {
+			warnings, errorList := ValidateWorkerMachineImageDeprecation(workerConfig, "coreos", "2023.4.0", cloudProfileConfig, now, fldPath)
+			Expect(warnings).To(ConsistOf("machine.image: machine image coreos/2023.4.0 is deprecated, please update to version 2191.5.0"))
+			Expect(errorList).To(BeEmpty())
+		})
+
+		It("should warn about deprecated versions with the Warn policy", func() {
+			policy := apisalicloud.DeprecatedMachineImagePolicyWarn
+			cloudProfileConfig.DeprecatedMachineImagePolicy = &policy
+
+			warnings, errorList := ValidateWorkerMachineImageDeprecation(workerConfig, "coreos", "2023.4.0", cloudProfileConfig, now, fldPath)
+			Expect(warnings).To(HaveLen(1))
+			Expect(errorList).To(BeEmpty())
+		})
+
+		It("should forbid deprecated versions with the Block policy", func() {
+			policy := apisalicloud.DeprecatedMachineImagePolicyBlock
+			cloudProfileConfig.DeprecatedMachineImagePolicy = &policy
+
+			warnings, errorList := ValidateWorkerMachineImageDeprecation(workerConfig, "coreos", "2023.4.0", cloudProfileConfig, now, fldPath)
+			Expect(warnings).To(BeEmpty())
+			Expect(errorList).To(ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
+				"Type":   Equal(field.ErrorTypeForbidden),
+				"Field":  Equal("machine.image"),
+				"Detail": Equal("machine image coreos/2023.4.0 is deprecated, please update to version 2191.5.0"),
+			}))))
+		})
+
+		It("should forbid expired versions regardless of the policy", func() {
+			cloudProfileConfig.MachineImages[0].Versions[0].ExpirationDate = &metav1.Time{Time: now.Add(-time.Hour)}
+
+			warnings, errorList := ValidateWorkerMachineImageDeprecation(workerConfig, "coreos", "2023.4.0", cloudProfileConfig, now, fldPath)
+			Expect(warnings).To(BeEmpty())
+			Expect(errorList).To(ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
+				"Type":   Equal(field.ErrorTypeForbidden),
+				"Field":  Equal("machine.image"),
+				"Detail": Equal("machine image coreos/2023.4.0 has expired on 2020-05-31, please update to version 2191.5.0"),
+			}))))
+		})
+
+		It("should not check image IDs", func() {
+			imageID := "m-custom"
+			workerConfig.ImageID = &imageID
+
+			warnings, errorList := ValidateWorkerMachineImageDeprecation(workerConfig, "coreos", "2023.4.0", cloudProfileConfig, now, fldPath)
+			Expect(warnings).To(BeEmpty())
+			Expect(errorList).To(BeEmpty())
+		})
+	})
+
+	Describe("#ValidateWorkerMachineImageIPVSSupport", func() {
+		var (
+			fldPath            = field.NewPath("machine", "image")
+			cloudProfileConfig *apisalicloud.CloudProfileConfig
+		)
+
+		BeforeEach(func() {
+			cloudProfileConfig = &apisalicloud.CloudProfileConfig{
+				MachineImages: []apisalicloud.MachineImages{
+					{
+						Name: "coreos",
+						Versions: []apisalicloud.MachineImageVersion{
+							{
+								Version:      "2023.4.0",
+								Regions:      []apisalicloud.RegionIDMapping{{Name: "cn-shanghai", ID: "coreos_2023_4_0"}},
+								SupportsIPVS: pointer.BoolPtr(false),
+							},
+							{
+								Version: "2191.5.0",
+								Regions: []apisalicloud.RegionIDMapping{{Name: "cn-shanghai", ID: "coreos_2191_5_0"}},
+							},
+						},
+					},
+				},
+			}
+		})
+
+		It("should accept versions supporting IPVS by default", func() {
+			Expect(ValidateWorkerMachineImageIPVSSupport(workerConfig, "coreos", "2191.5.0", cloudProfileConfig, fldPath)).To(BeEmpty())
+		})
+
+		It("should forbid versions not supporting IPVS", func() {
+			errorList := ValidateWorkerMachineImageIPVSSupport(workerConfig, "coreos", "2023.4.0", cloudProfileConfig, fldPath)
+
+			Expect(errorList).To(ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
+				"Type":   Equal(field.ErrorTypeForbidden),
+				"Field":  Equal("machine.image"),
+				"Detail": Equal("machine image coreos/2023.4.0 does not support the IPVS proxy mode of kube-proxy"),
+			}))))
+		})
+
+		It("should not check image IDs", func() {
+			imageID := "m-custom"
+			workerConfig.ImageID = &imageID
+
+			Expect(ValidateWorkerMachineImageIPVSSupport(workerConfig, "coreos", "2023.4.0", cloudProfileConfig, fldPath)).To(BeEmpty())
+		})
+	})
+
+	Describe("#ValidateWorkerMachineType", func() {
+		var fldPath = field.NewPath("providerConfig", "useLocalDisk")
+
+		It("should allow local disks for instance families with local NVMe disks", func() {
+			workerConfig.UseLocalDisk = true
+
+			Expect(ValidateWorkerMachineType(workerConfig, "ecs.i2.xlarge", fldPath)).To(BeEmpty())
+		})
+
+		It("should forbid local disks for instance families without local disks", func() {
+			workerConfig.UseLocalDisk = true
+
+			errorList := ValidateWorkerMachineType(workerConfig, "ecs.g6.large", fldPath)
+
+			Expect(errorList).To(ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
+				"Type":  Equal(field.ErrorTypeForbidden),
+				"Field": Equal("providerConfig.useLocalDisk"),
+			}))))
+		})
+
+		It("should forbid shared instance types for packed deployment sets", func() {
+			placementStrategy := apisalicloud.PlacementStrategyPack
+			workerConfig.PlacementStrategy = &placementStrategy
+
+			Expect(ValidateWorkerMachineType(workerConfig, "ecs.g6.large", fldPath)).To(BeEmpty())
+			Expect(ValidateWorkerMachineType(workerConfig, "ecs.t6-c1m2.large", fldPath)).To(ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
+				"Type":   Equal(field.ErrorTypeForbidden),
+				"Detail": ContainSubstring("shared instance type"),
+			}))))
+
+			placementStrategy = apisalicloud.PlacementStrategySpread
+			Expect(ValidateWorkerMachineType(workerConfig, "ecs.t6-c1m2.large", fldPath)).To(BeEmpty())
+		})
+
+		It("should forbid GPU driver versions for instance types without GPUs", func() {
+			workerConfig.GPUDriverVersion = pointer.StringPtr("470.57.02")
+
+			Expect(ValidateWorkerMachineType(workerConfig, "ecs.gn6i-c4g1.xlarge", fldPath)).To(BeEmpty())
+			Expect(ValidateWorkerMachineType(workerConfig, "ecs.g6.large", fldPath)).To(ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
+				"Type":   Equal(field.ErrorTypeForbidden),
+				"Detail": ContainSubstring("has no GPUs"),
+			}))))
+		})
+
+		It("should allow instance families without local disks if local disks are not used", func() {
+			Expect(ValidateWorkerMachineType(workerConfig, "ecs.g6.large", fldPath)).To(BeEmpty())
+			Expect(ValidateWorkerMachineType(nil, "ecs.g6.large", fldPath)).To(BeEmpty())
+		})
+	})
+
+	Describe("#ValidateWorkerRollingUpdate", func() {
+		var fldPath = field.NewPath("pools").Key("pool")
+
+		It("should allow rolling updates which can make progress", func() {
+			Expect(ValidateWorkerRollingUpdate(intstr.FromInt(1), intstr.FromInt(0), 2, 5, fldPath)).To(BeEmpty())
+			Expect(ValidateWorkerRollingUpdate(intstr.FromInt(0), intstr.FromString("50%"), 2, 5, fldPath)).To(BeEmpty())
+			Expect(ValidateWorkerRollingUpdate(intstr.FromString("10%"), intstr.FromInt(0), 0, 5, fldPath)).To(BeEmpty())
+		})
+
+		It("should forbid rolling updates which cannot make progress with the minimum of the worker pool", func() {
+			errorList := ValidateWorkerRollingUpdate(intstr.FromString("0%"), intstr.FromString("40%"), 2, 5, fldPath)
+
+			Expect(errorList).To(ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
+				"Type":   Equal(field.ErrorTypeInvalid),
+				"Field":  Equal("pools[pool].maxUnavailable"),
+				"Detail": Equal("must not be 0 for 2 machines if maxSurge is 0"),
+			}))))
+		})
+
+		It("should forbid a max unavailable greater than the maximum of the worker pool", func() {
+			errorList := ValidateWorkerRollingUpdate(intstr.FromInt(1), intstr.FromInt(6), 2, 5, fldPath)
+
+			Expect(errorList).To(ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
+				"Type":  Equal(field.ErrorTypeInvalid),
+				"Field": Equal("pools[pool].maxUnavailable"),
+			}))))
+		})
+	})
+
+	Describe("#ValidateWorkerKubeletConfig", func() {
+		var (
+			fldPath       = field.NewPath("pools").Key("pool").Child("kubeletConfig")
+			kubeletConfig *apisalicloud.KubeletConfig
+		)
+
+		BeforeEach(func() {
+			kubeletConfig = &apisalicloud.KubeletConfig{
+				MaxPods:                 pointer.Int32Ptr(64),
+				EvictionHard:            map[string]string{"memory.available": "500Mi"},
+				EvictionSoft:            map[string]string{"nodefs.available": "15%"},
+				EvictionSoftGracePeriod: map[string]string{"nodefs.available": "1m30s"},
+			}
+		})
+
+		It("should allow settings which are not set in the kubelet configurations of the shoot", func() {
+			Expect(ValidateWorkerKubeletConfig(kubeletConfig, fldPath)).To(BeEmpty())
+			Expect(ValidateWorkerKubeletConfig(kubeletConfig, fldPath, nil, &gardencorev1beta1.KubeletConfig{
+				PodPIDsLimit: pointer.Int64Ptr(1024),
+				EvictionHard: &gardencorev1beta1.KubeletConfigEviction{NodeFSAvailable: pointer.StringPtr("5%")},
+			})).To(BeEmpty())
+		})
+
+		It("should forbid settings which are also set in the kubelet configurations of the shoot", func() {
+			errorList := ValidateWorkerKubeletConfig(kubeletConfig, fldPath,
+				&gardencorev1beta1.KubeletConfig{
+					MaxPods:      pointer.Int32Ptr(110),
+					EvictionHard: &gardencorev1beta1.KubeletConfigEviction{MemoryAvailable: pointer.StringPtr("100Mi")},
+				},
+				&gardencorev1beta1.KubeletConfig{
+					MaxPods:                 pointer.Int32Ptr(32),
+					EvictionSoft:            &gardencorev1beta1.KubeletConfigEviction{NodeFSAvailable: pointer.StringPtr("10%")},
+					EvictionSoftGracePeriod: &gardencorev1beta1.KubeletConfigEvictionSoftGracePeriod{NodeFSAvailable: &metav1.Duration{Duration: time.Minute}},
+				},
+			)
+
+			Expect(errorList).To(ConsistOf(
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeForbidden),
+					"Field": Equal("pools[pool].kubeletConfig.maxPods"),
+				})),
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeForbidden),
+					"Field": Equal("pools[pool].kubeletConfig.evictionHard[memory.available]"),
+				})),
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeForbidden),
+					"Field": Equal("pools[pool].kubeletConfig.evictionSoft[nodefs.available]"),
+				})),
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeForbidden),
+					"Field": Equal("pools[pool].kubeletConfig.evictionSoftGracePeriod[nodefs.available]"),
+				})),
+			))
+		})
+	})
+
+	Describe("#ValidateWorkerVolume", func() {
+		var (
+			fldPath     = field.NewPath("volume")
+			minimumSize = resource.MustParse("40Gi")
+		)
+
+		It("should allow sizes between the minimum size and the maximum size of the disk category", func() {
+			Expect(ValidateWorkerVolume(nil, "40Gi", minimumSize, fldPath)).To(BeEmpty())
+			Expect(ValidateWorkerVolume(pointer.StringPtr("cloud_essd"), "2048Gi", minimumSize, fldPath)).To(BeEmpty())
+		})
+
+		It("should forbid sizes below the minimum size", func() {
+			errorList := ValidateWorkerVolume(nil, "30Gi", minimumSize, fldPath)
+
+			Expect(errorList).To(ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
+				"Type":   Equal(field.ErrorTypeInvalid),
+				"Field":  Equal("volume.size"),
+				"Detail": Equal("must be at least 40Gi as required by the machine image"),
+			}))))
+		})
+
+		It("should forbid sizes above the maximum size of the disk category", func() {
+			Expect(ValidateWorkerVolume(pointer.StringPtr("cloud_ssd"), "600Gi", minimumSize, fldPath)).To(BeEmpty())
+
+			errorList := ValidateWorkerVolume(pointer.StringPtr("cloud"), "600Gi", minimumSize, fldPath)
+
+			Expect(errorList).To(ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
+				"Type":   Equal(field.ErrorTypeInvalid),
+				"Field":  Equal("volume.size"),
+				"Detail": Equal("must not be larger than 500Gi for disk category cloud"),
+			}))))
+		})
+
+		It("should forbid invalid sizes", func() {
+			errorList := ValidateWorkerVolume(nil, "foo", minimumSize, fldPath)
+
+			Expect(errorList).To(ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
+				"Type":  Equal(field.ErrorTypeInvalid),
+				"Field": Equal("volume.size"),
+			}))))
+		})
+	})
+
+	Describe("#ValidateWorkerConfig", func() {
+		It("should return no errors for a valid configuration", func() {
+			Expect(ValidateWorkerConfig(workerConfig)).To(BeEmpty())
+		})
+
+		It("should return no errors for an empty configuration", func() {
+			Expect(ValidateWorkerConfig(&apisalicloud.WorkerConfig{})).To(BeEmpty())
+		})
+
+		It("should allow encryption without a KMS key", func() {
+			workerConfig.SystemDisk.KMSKeyID = nil
+
+			Expect(ValidateWorkerConfig(workerConfig)).To(BeEmpty())
+		})
+
+		It("should forbid a KMS key if the system disk is not encrypted", func() {
+			workerConfig.SystemDisk.Encrypted = false
+
+			errorList := ValidateWorkerConfig(workerConfig)
+
+			Expect(errorList).To(ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
+				"Type":  Equal(field.ErrorTypeForbidden),
+				"Field": Equal("systemDisk.kmsKeyID"),
+			}))))
+		})
+
+		It("should forbid tags with a key reserved for the tags identifying the machines", func() {
+			workerConfig.Tags = map[string]string{
+				"cmdb-id":                "4711",
+				"kubernetes.io/cluster/": "1",
+			}
+
+			errorList := ValidateWorkerConfig(workerConfig)
+
+			Expect(errorList).To(ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
+				"Type":  Equal(field.ErrorTypeInvalid),
+				"Field": Equal("tags[kubernetes.io/cluster/]"),
+			}))))
+		})
+
+		Context("data volumes", func() {
+			var (
+				essd             = "cloud_essd"
+				cloudSSD         = "cloud_ssd"
+				performanceLevel = "PL2"
+			)
+
+			BeforeEach(func() {
+				workerConfig.DataVolumes = []apisalicloud.DataVolume{
+					{Name: "data", Type: &essd, Size: "100Gi", PerformanceLevel: &performanceLevel},
+					{Name: "scratch", Size: "20Gi"},
+				}
+			})
+
+			It("should allow valid data volumes", func() {
+				Expect(ValidateWorkerConfig(workerConfig)).To(BeEmpty())
+			})
+
+			It("should forbid invalid and duplicate names", func() {
+				workerConfig.DataVolumes[0].Name = "Data_1"
+				workerConfig.DataVolumes[1].Name = "Data_1"
+
+				errorList := ValidateWorkerConfig(workerConfig)
+
+				Expect(errorList).To(ConsistOf(
+					PointTo(MatchFields(IgnoreExtras, Fields{
+						"Type":  Equal(field.ErrorTypeInvalid),
+						"Field": Equal("dataVolumes[0].name"),
+					})),
+					PointTo(MatchFields(IgnoreExtras, Fields{
+						"Type":  Equal(field.ErrorTypeInvalid),
+						"Field": Equal("dataVolumes[1].name"),
+					})),
+					PointTo(MatchFields(IgnoreExtras, Fields{
+						"Type":  Equal(field.ErrorTypeDuplicate),
+						"Field": Equal("dataVolumes[1].name"),
+					})),
+				))
+			})
+
+			It("should forbid sizes below the minimum", func() {
+				workerConfig.DataVolumes[1].Size = "10Gi"
+
+				errorList := ValidateWorkerConfig(workerConfig)
+
+				Expect(errorList).To(ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeInvalid),
+					"Field": Equal("dataVolumes[1].size"),
+				}))))
+			})
+
+			It("should forbid a performance level for non-ESSD disk categories", func() {
+				workerConfig.DataVolumes[0].Type = &cloudSSD
+				workerConfig.DataVolumes[1].PerformanceLevel = &performanceLevel
+
+				errorList := ValidateWorkerConfig(workerConfig)
+
+				Expect(errorList).To(ConsistOf(
+					PointTo(MatchFields(IgnoreExtras, Fields{
+						"Type":  Equal(field.ErrorTypeForbidden),
+						"Field": Equal("dataVolumes[0].performanceLevel"),
+					})),
+					PointTo(MatchFields(IgnoreExtras, Fields{
+						"Type":  Equal(field.ErrorTypeForbidden),
+						"Field": Equal("dataVolumes[1].performanceLevel"),
+					})),
+				))
+			})
+
+			It("should forbid unsupported performance levels", func() {
+				unsupported := "PL4"
+				workerConfig.DataVolumes[0].PerformanceLevel = &unsupported
+
+				errorList := ValidateWorkerConfig(workerConfig)
+
+				Expect(errorList).To(ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeNotSupported),
+					"Field": Equal("dataVolumes[0].performanceLevel"),
+				}))))
+			})
+		})
+
+		Context("spot instances", func() {
+			var (
+				asPriceGo      = apisalicloud.SpotStrategyAsPriceGo
+				withPriceLimit = apisalicloud.SpotStrategyWithPriceLimit
+				priceLimit     = "0.05"
+			)
+
+			It("should allow spot instances with a price limit", func() {
+				workerConfig.SpotStrategy = &withPriceLimit
+				workerConfig.SpotPriceLimit = &priceLimit
+
+				Expect(ValidateWorkerConfig(workerConfig)).To(BeEmpty())
+			})
+
+			It("should allow spot instances at the market price", func() {
+				workerConfig.SpotStrategy = &asPriceGo
+
+				Expect(ValidateWorkerConfig(workerConfig)).To(BeEmpty())
+			})
+
+			It("should forbid unsupported spot strategies", func() {
+				unsupported := apisalicloud.SpotStrategy("NoSpot")
+				workerConfig.SpotStrategy = &unsupported
+
+				errorList := ValidateWorkerConfig(workerConfig)
+
+				Expect(errorList).To(ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeNotSupported),
+					"Field": Equal("spotStrategy"),
+				}))))
+			})
+
+			It("should require a price limit for the SpotWithPriceLimit strategy", func() {
+				workerConfig.SpotStrategy = &withPriceLimit
+
+				errorList := ValidateWorkerConfig(workerConfig)
+
+				Expect(errorList).To(ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeRequired),
+					"Field": Equal("spotPriceLimit"),
+				}))))
+			})
+
+			It("should forbid a price limit for other strategies", func() {
+				workerConfig.SpotStrategy = &asPriceGo
+				workerConfig.SpotPriceLimit = &priceLimit
+
+				errorList := ValidateWorkerConfig(workerConfig)
+
+				Expect(errorList).To(ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeForbidden),
+					"Field": Equal("spotPriceLimit"),
+				}))))
+			})
+
+			It("should forbid invalid price limits", func() {
+				invalidPriceLimit := "-1"
+				workerConfig.SpotStrategy = &withPriceLimit
+				workerConfig.SpotPriceLimit = &invalidPriceLimit
+
+				errorList := ValidateWorkerConfig(workerConfig)
+
+				Expect(errorList).To(ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeInvalid),
+					"Field": Equal("spotPriceLimit"),
+				}))))
+			})
+		})
+
+		Context("user data", func() {
+			DescribeTable("#userData",
+				func(userData string, matcher types.GomegaMatcher) {
+					workerConfig.UserData = &userData
+
+					Expect(ValidateWorkerConfig(workerConfig)).To(matcher)
+				},
+				Entry("should allow shell scripts", "#!/bin/bash\necho foo\n", BeEmpty()),
+				Entry("should allow cloud-configs", "#cloud-config\nwrite_files:\n- path: /etc/foo\n  content: bar\n", BeEmpty()),
+				Entry("should forbid empty user data", "", ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeRequired),
+					"Field": Equal("userData"),
+				})))),
+				Entry("should forbid invalid cloud-configs", "#cloud-config\nwrite_files: [\n", ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeInvalid),
+					"Field": Equal("userData"),
+				})))),
+				Entry("should forbid other formats", "echo foo", ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeInvalid),
+					"Field": Equal("userData"),
+				})))),
+				Entry("should forbid too large user data", "#!/bin/bash\n"+strings.Repeat("#", 16*1024), ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeTooLong),
+					"Field": Equal("userData"),
+				})))),
+			)
+		})
+
+		Context("instance charge type", func() {
+			var (
+				prePaid  = apisalicloud.InstanceChargeTypePrePaid
+				postPaid = apisalicloud.InstanceChargeTypePostPaid
+				week     = apisalicloud.PeriodUnitWeek
+			)
+
+			It("should allow PrePaid instances with a supported period", func() {
+				period := int32(12)
+				workerConfig.InstanceChargeType = &prePaid
+				workerConfig.Period = &period
+
+				Expect(ValidateWorkerConfig(workerConfig)).To(BeEmpty())
+			})
+
+			It("should forbid unsupported instance charge types", func() {
+				unsupported := apisalicloud.InstanceChargeType("Free")
+				workerConfig.InstanceChargeType = &unsupported
+
+				errorList := ValidateWorkerConfig(workerConfig)
+
+				Expect(errorList).To(ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeNotSupported),
+					"Field": Equal("instanceChargeType"),
+				}))))
+			})
+
+			It("should forbid a period for PostPaid instances", func() {
+				period := int32(1)
+				workerConfig.InstanceChargeType = &postPaid
+				workerConfig.Period = &period
+				workerConfig.PeriodUnit = &week
+
+				errorList := ValidateWorkerConfig(workerConfig)
+
+				Expect(errorList).To(ConsistOf(
+					PointTo(MatchFields(IgnoreExtras, Fields{
+						"Type":  Equal(field.ErrorTypeForbidden),
+						"Field": Equal("period"),
+					})),
+					PointTo(MatchFields(IgnoreExtras, Fields{
+						"Type":  Equal(field.ErrorTypeForbidden),
+						"Field": Equal("periodUnit"),
+					})),
+				))
+			})
+
+			It("should forbid periods which are not supported for the period unit", func() {
+				period := int32(12)
+				workerConfig.InstanceChargeType = &prePaid
+				workerConfig.Period = &period
+				workerConfig.PeriodUnit = &week
+
+				errorList := ValidateWorkerConfig(workerConfig)
+
+				Expect(errorList).To(ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeInvalid),
+					"Field": Equal("period"),
+				}))))
+			})
+
+			It("should allow the automatic renewal of PrePaid instances", func() {
+				autoRenewPeriod := int32(3)
+				workerConfig.InstanceChargeType = &prePaid
+				workerConfig.PeriodUnit = &week
+				workerConfig.AutoRenew = true
+				workerConfig.AutoRenewPeriod = &autoRenewPeriod
+
+				Expect(ValidateWorkerConfig(workerConfig)).To(BeEmpty())
+			})
+
+			It("should forbid the automatic renewal of PostPaid instances", func() {
+				autoRenewPeriod := int32(1)
+				workerConfig.InstanceChargeType = &postPaid
+				workerConfig.AutoRenew = true
+				workerConfig.AutoRenewPeriod = &autoRenewPeriod
+
+				errorList := ValidateWorkerConfig(workerConfig)
+
+				Expect(errorList).To(ConsistOf(
+					PointTo(MatchFields(IgnoreExtras, Fields{
+						"Type":  Equal(field.ErrorTypeForbidden),
+						"Field": Equal("autoRenew"),
+					})),
+					PointTo(MatchFields(IgnoreExtras, Fields{
+						"Type":  Equal(field.ErrorTypeForbidden),
+						"Field": Equal("autoRenewPeriod"),
+					})),
+				))
+			})
+
+			It("should forbid an auto-renew period without automatic renewal", func() {
+				autoRenewPeriod := int32(1)
+				workerConfig.InstanceChargeType = &prePaid
+				workerConfig.AutoRenewPeriod = &autoRenewPeriod
+
+				errorList := ValidateWorkerConfig(workerConfig)
+
+				Expect(errorList).To(ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeForbidden),
+					"Field": Equal("autoRenewPeriod"),
+				}))))
+			})
+
+			It("should forbid auto-renew periods which are not supported for the period unit", func() {
+				autoRenewPeriod := int32(4)
+				workerConfig.InstanceChargeType = &prePaid
+				workerConfig.PeriodUnit = &week
+				workerConfig.AutoRenew = true
+				workerConfig.AutoRenewPeriod = &autoRenewPeriod
+
+				errorList := ValidateWorkerConfig(workerConfig)
+
+				Expect(errorList).To(ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeInvalid),
+					"Field": Equal("autoRenewPeriod"),
+				}))))
+			})
+
+			It("should forbid PrePaid spot instances", func() {
+				spotStrategy := apisalicloud.SpotStrategyAsPriceGo
+				workerConfig.InstanceChargeType = &prePaid
+				workerConfig.SpotStrategy = &spotStrategy
+
+				errorList := ValidateWorkerConfig(workerConfig)
+
+				Expect(errorList).To(ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeForbidden),
+					"Field": Equal("instanceChargeType"),
+				}))))
+			})
+		})
+
+		It("should forbid deployment sets for spot instances", func() {
+			deploymentSetID := "ds-1234"
+			spotStrategy := apisalicloud.SpotStrategyAsPriceGo
+			workerConfig.DeploymentSetID = &deploymentSetID
+			workerConfig.SpotStrategy = &spotStrategy
+
+			errorList := ValidateWorkerConfig(workerConfig)
+
+			Expect(errorList).To(ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
+				"Type":  Equal(field.ErrorTypeForbidden),
+				"Field": Equal("deploymentSetID"),
+			}))))
+		})
+
+		It("should forbid invalid GPU driver versions", func() {
+			workerConfig.GPUDriverVersion = pointer.StringPtr("latest")
+
+			Expect(ValidateWorkerConfig(workerConfig)).To(ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
+				"Type":  Equal(field.ErrorTypeInvalid),
+				"Field": Equal("gpuDriverVersion"),
+			}))))
+
+			workerConfig.GPUDriverVersion = pointer.StringPtr("470.57.02")
+			Expect(ValidateWorkerConfig(workerConfig)).To(BeEmpty())
+		})
+
+		It("should allow placement strategies for deployment sets", func() {
+			deploymentSetID := "ds-1234"
+			placementStrategy := apisalicloud.PlacementStrategyPack
+			workerConfig.DeploymentSetID = &deploymentSetID
+			workerConfig.PlacementStrategy = &placementStrategy
+
+			Expect(ValidateWorkerConfig(workerConfig)).To(BeEmpty())
+		})
+
+		It("should forbid unknown placement strategies and placement strategies without deployment set", func() {
+			placementStrategy := apisalicloud.PlacementStrategy("Cluster")
+			workerConfig.PlacementStrategy = &placementStrategy
+
+			errorList := ValidateWorkerConfig(workerConfig)
+
+			Expect(errorList).To(ConsistOf(
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeNotSupported),
+					"Field": Equal("placementStrategy"),
+				})),
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeForbidden),
+					"Field": Equal("placementStrategy"),
+				})),
+			))
+		})
+
+		It("should forbid too many, empty, and duplicate security groups", func() {
+			workerConfig.SecurityGroupIDs = []string{"sg-1", "", "sg-1", "sg-2", "sg-3"}
+
+			errorList := ValidateWorkerConfig(workerConfig)
+
+			Expect(errorList).To(ConsistOf(
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeTooMany),
+					"Field": Equal("securityGroupIDs"),
+				})),
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeRequired),
+					"Field": Equal("securityGroupIDs[1]"),
+				})),
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeDuplicate),
+					"Field": Equal("securityGroupIDs[2]"),
+				})),
+			))
+		})
+
+		It("should allow a valid RAM role name", func() {
+			ramRoleName := "ecs-role.worker-1"
+			workerConfig.RAMRoleName = &ramRoleName
+
+			Expect(ValidateWorkerConfig(workerConfig)).To(BeEmpty())
+		})
+
+		It("should forbid invalid RAM role names", func() {
+			ramRoleName := "role_with_underscores"
+			workerConfig.RAMRoleName = &ramRoleName
+
+			errorList := ValidateWorkerConfig(workerConfig)
+
+			Expect(errorList).To(ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
+				"Type":  Equal(field.ErrorTypeInvalid),
+				"Field": Equal("ramRoleName"),
+			}))))
+		})
+
+		It("should allow a valid drain timeout", func() {
+			workerConfig.DrainTimeout = &metav1.Duration{Duration: 4 * time.Hour}
+
+			Expect(ValidateWorkerConfig(workerConfig)).To(BeEmpty())
+		})
+
+		DescribeTable("should forbid invalid drain timeouts",
+			func(drainTimeout time.Duration) {
+				workerConfig.DrainTimeout = &metav1.Duration{Duration: drainTimeout}
+
+				errorList := ValidateWorkerConfig(workerConfig)
+
+				Expect(errorList).To(ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeInvalid),
+					"Field": Equal("drainTimeout"),
+				}))))
+			},
+			Entry("zero", time.Duration(0)),
+			Entry("negative", -time.Minute),
+			Entry("too long", 25*time.Hour),
+		)
+
+		It("should allow a valid max surge and max unavailable", func() {
+			maxSurge, maxUnavailable := intstr.FromString("150%"), intstr.FromInt(0)
+			workerConfig.MaxSurge = &maxSurge
+			workerConfig.MaxUnavailable = &maxUnavailable
+
+			Expect(ValidateWorkerConfig(workerConfig)).To(BeEmpty())
+		})
+
+		DescribeTable("should forbid an invalid max surge and max unavailable",
+			func(value intstr.IntOrString) {
+				workerConfig.MaxSurge = &value
+				workerConfig.MaxUnavailable = &value
+
+				errorList := ValidateWorkerConfig(workerConfig)
+
+				Expect(errorList).To(ConsistOf(
+					PointTo(MatchFields(IgnoreExtras, Fields{
+						"Type":  Equal(field.ErrorTypeInvalid),
+						"Field": Equal("maxSurge"),
+					})),
+					PointTo(MatchFields(IgnoreExtras, Fields{
+						"Type":  Equal(field.ErrorTypeInvalid),
+						"Field": Equal("maxUnavailable"),
+					})),
+				))
+			},
+			Entry("negative number", intstr.FromInt(-1)),
+			Entry("no percentage", intstr.FromString("10")),
+			Entry("negative percentage", intstr.FromString("-10%")),
+		)
+
+		It("should forbid a max unavailable greater than 100%", func() {
+			maxUnavailable := intstr.FromString("110%")
+			workerConfig.MaxUnavailable = &maxUnavailable
+
+			errorList := ValidateWorkerConfig(workerConfig)
+
+			Expect(errorList).To(ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
+				"Type":   Equal(field.ErrorTypeInvalid),
+				"Field":  Equal("maxUnavailable"),
+				"Detail": Equal("must not be greater than 100%"),
+			}))))
+		})
+
+		Context("kubelet config", func() {
+			It("should allow valid kubelet settings", func() {
+				workerConfig.KubeletConfig = &apisalicloud.KubeletConfig{
+					MaxPods:                 pointer.Int32Ptr(64),
+					EvictionHard:            map[string]string{"memory.available": "500Mi", "nodefs.available": "10%"},
+					EvictionSoft:            map[string]string{"imagefs.available": "20%"},
+					EvictionSoftGracePeriod: map[string]string{"imagefs.available": "1m30s"},
+					KubeReserved:            map[string]string{"cpu": "100m", "memory": "1Gi"},
+				}
+
+				Expect(ValidateWorkerConfig(workerConfig)).To(BeEmpty())
+			})
+
+			It("should forbid unsupported keys and invalid values", func() {
+				workerConfig.KubeletConfig = &apisalicloud.KubeletConfig{
+					MaxPods:                 pointer.Int32Ptr(0),
+					EvictionHard:            map[string]string{"memory.free": "500Mi", "nodefs.available": "110%", "imagefs.available": "many"},
+					EvictionSoftGracePeriod: map[string]string{"memory.available": "-1m"},
+					KubeReserved:            map[string]string{"gpu": "1", "memory": "-1Gi"},
+				}
+
+				errorList := ValidateWorkerConfig(workerConfig)
+
+				Expect(errorList).To(ConsistOf(
+					PointTo(MatchFields(IgnoreExtras, Fields{
+						"Type":  Equal(field.ErrorTypeInvalid),
+						"Field": Equal("kubeletConfig.maxPods"),
+					})),
+					PointTo(MatchFields(IgnoreExtras, Fields{
+						"Type":  Equal(field.ErrorTypeNotSupported),
+						"Field": Equal("kubeletConfig.evictionHard[memory.free]"),
+					})),
+					PointTo(MatchFields(IgnoreExtras, Fields{
+						"Type":  Equal(field.ErrorTypeInvalid),
+						"Field": Equal("kubeletConfig.evictionHard[nodefs.available]"),
+					})),
+					PointTo(MatchFields(IgnoreExtras, Fields{
+						"Type":  Equal(field.ErrorTypeInvalid),
+						"Field": Equal("kubeletConfig.evictionHard[imagefs.available]"),
+					})),
+					PointTo(MatchFields(IgnoreExtras, Fields{
+						"Type":  Equal(field.ErrorTypeInvalid),
+						"Field": Equal("kubeletConfig.evictionSoftGracePeriod[memory.available]"),
+					})),
+					PointTo(MatchFields(IgnoreExtras, Fields{
+						"Type":  Equal(field.ErrorTypeNotSupported),
+						"Field": Equal("kubeletConfig.kubeReserved[gpu]"),
+					})),
+					PointTo(MatchFields(IgnoreExtras, Fields{
+						"Type":  Equal(field.ErrorTypeInvalid),
+						"Field": Equal("kubeletConfig.kubeReserved[memory]"),
+					})),
+				))
+			})
+
+			It("should require a grace period for every soft eviction threshold", func() {
+				workerConfig.KubeletConfig = &apisalicloud.KubeletConfig{
+					EvictionSoft: map[string]string{"memory.available": "1Gi"},
+				}
+
+				errorList := ValidateWorkerConfig(workerConfig)
+
+				Expect(errorList).To(ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeRequired),
+					"Field": Equal("kubeletConfig.evictionSoftGracePeriod[memory.available]"),
+				}))))
+			})
+		})
+
+		It("should forbid an empty image ID", func() {
+			workerConfig.ImageID = new(string)
+
+			errorList := ValidateWorkerConfig(workerConfig)
+
+			Expect(errorList).To(ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
+				"Type":  Equal(field.ErrorTypeRequired),
+				"Field": Equal("imageID"),
+			}))))
+		})
+
+		It("should forbid an empty KMS key", func() {
+			workerConfig.SystemDisk.KMSKeyID = new(string)
+
+			errorList := ValidateWorkerConfig(workerConfig)
+
+			Expect(errorList).To(ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
+				"Type":  Equal(field.ErrorTypeRequired),
+				"Field": Equal("systemDisk.kmsKeyID"),
+			}))))
+		})
+	})
+})
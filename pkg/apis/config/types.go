@@ -20,6 +20,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	componentbaseconfig "k8s.io/component-base/config"
 )
 
@@ -35,10 +36,120 @@ type ControllerConfiguration struct {
 	// MachineImageOwnerSecretRef is the secret reference which contains credential of AliCloud subaccount for customized images.
 	// We currently assume multiple customized images should always be under this account.
 	MachineImageOwnerSecretRef *corev1.SecretReference
+	// SeedCredentialsSecretRef is the secret reference which contains the credentials of the Alicloud account of the
+	// seed. It is needed to manage the access control lists restricting the access to the load balancers of the
+	// kube-apiservers, as they are created in this account.
+	SeedCredentialsSecretRef *corev1.SecretReference
 	// ETCD is the etcd configuration.
 	ETCD ETCD
 	// HealthCheckConfig is the config for the health check controller
 	HealthCheckConfig *healthcheckconfig.HealthCheckConfig
+	// NodeConditionsHealthCheck is the configuration of the health check of the node conditions of the workers.
+	NodeConditionsHealthCheck *NodeConditionsHealthCheck
+	// Infrastructure is the configuration of the infrastructure controller.
+	Infrastructure *InfrastructureControllerConfig
+	// BackupBucket is the configuration of the backup buckets.
+	BackupBucket *BackupBucketConfig
+	// BackupEntry is the configuration of the backup entries.
+	BackupEntry *BackupEntryConfig
+	// Endpoints are custom endpoints of the Alicloud APIs, e.g., for the regions of the Alibaba Finance Cloud. The
+	// endpoints resolved by the Alicloud SDK are used for all regions and APIs without custom endpoint.
+	Endpoints []RegionEndpoints
+	// UseInternalOSSEndpoint specifies whether the backup controllers and the etcd backups use the internal OSS
+	// endpoint `oss-<region>-internal.aliyuncs.com` of the region of the backup buckets instead of the public one. The
+	// internal endpoint can only be reached from within the VPCs of this region, hence the public endpoint is used if
+	// it cannot be reached. Custom OSS endpoints take precedence.
+	UseInternalOSSEndpoint bool
+}
+
+// NodeConditionsHealthCheck is the configuration of the health check of the node conditions of the workers.
+type NodeConditionsHealthCheck struct {
+	// ConditionTypes are the types of the node conditions which mark a node as unhealthy if their status is True.
+	// Defaults to NetworkUnavailable and DiskPressure.
+	ConditionTypes []corev1.NodeConditionType
+	// MaxUnhealthyNodes is the number or percentage of the nodes of a worker which may be unhealthy before the worker
+	// is reported unhealthy. Defaults to 10%.
+	MaxUnhealthyNodes *intstr.IntOrString
+}
+
+// InfrastructureControllerConfig is the configuration of the infrastructure controller.
+type InfrastructureControllerConfig struct {
+	// ErrorBackoff is the backoff of the requeues of Infrastructures whose reconciliation or deletion failed, e.g.
+	// because a quota of the account is exhausted. It does not affect the periodic resync of Infrastructures which
+	// have been reconciled successfully. If not set, Infrastructures are requeued after 30s if Terraform or the flow
+	// reconciler failed, and with the rate limiting of the controller otherwise.
+	ErrorBackoff *Backoff
+}
+
+// Backoff is an exponential backoff.
+type Backoff struct {
+	// Base is the duration of the requeue after the first failure.
+	Base metav1.Duration
+	// Max is the maximum duration of a requeue.
+	Max metav1.Duration
+	// Factor is the factor by which the duration of the requeue is multiplied after each further consecutive failure.
+	Factor float64
+}
+
+// RegionEndpoints are the custom endpoints of the Alicloud APIs in a region.
+type RegionEndpoints struct {
+	// Region is the region of the endpoints.
+	Region string
+	// ECS is the host name of the ECS endpoint.
+	ECS *string
+	// VPC is the host name of the VPC endpoint.
+	VPC *string
+	// OSS is the host name of the OSS endpoint.
+	OSS *string
+	// STS is the host name of the STS endpoint.
+	STS *string
+	// SLB is the host name of the SLB endpoint.
+	SLB *string
+}
+
+// BackupEntryConfig is the configuration of the backup entries.
+type BackupEntryConfig struct {
+	// RetentionPeriod is the period for which the backups of a deleted backup entry are retained. The backups are
+	// deleted once they are older than this period. If not set, the backups are deleted together with the entry.
+	RetentionPeriod *metav1.Duration
+}
+
+// BackupBucketConfig is the configuration of the backup buckets.
+type BackupBucketConfig struct {
+	// Versioning specifies whether object versioning is enabled for the backup buckets, so that overwritten and deleted
+	// backups can be restored. Once enabled, versioning can only be suspended but not disabled for a bucket.
+	Versioning bool
+	// Encryption is the server-side encryption of the backup buckets. If not set, the default encryption of OSS is used.
+	Encryption *BackupBucketEncryption
+	// Logging is the access logging of the backup buckets. If not set, the access logging of the buckets is disabled.
+	Logging *BackupBucketLogging
+	// StorageClassTransition transitions the objects of the backup buckets to another storage class some days after
+	// their last modification. If not set, the objects keep the Standard storage class.
+	StorageClassTransition *BackupBucketStorageClassTransition
+}
+
+// BackupBucketEncryption is the server-side encryption of the backup buckets.
+type BackupBucketEncryption struct {
+	// KMSKeyID is the ID of the customer-managed KMS key which is used to encrypt the objects of the backup buckets.
+	KMSKeyID string
+}
+
+// BackupBucketLogging is the access logging of the backup buckets.
+type BackupBucketLogging struct {
+	// TargetBucket is the name of the OSS bucket which stores the access logs. It has to exist in the region of the
+	// backup buckets.
+	TargetBucket string
+	// TargetPrefix is the prefix of the access log objects in the target bucket.
+	TargetPrefix string
+}
+
+// BackupBucketStorageClassTransition transitions the objects of the backup buckets to another storage class.
+type BackupBucketStorageClassTransition struct {
+	// StorageClass is the storage class the objects are transitioned to, either IA (infrequent access) or Archive.
+	// Archived objects have to be restored before they can be read.
+	StorageClass string
+	// Days is the number of days after their last modification after which the objects are transitioned.
+	Days int
 }
 
 // ETCD is an etcd configuration.
@@ -26,10 +26,12 @@ import (
 	config "github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/config"
 	healthcheckconfig "github.com/gardener/gardener-extensions/pkg/controller/healthcheck/config"
 	healthcheckconfigv1alpha1 "github.com/gardener/gardener-extensions/pkg/controller/healthcheck/config/v1alpha1"
-	v1 "k8s.io/api/core/v1"
+	corev1 "k8s.io/api/core/v1"
 	resource "k8s.io/apimachinery/pkg/api/resource"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	conversion "k8s.io/apimachinery/pkg/conversion"
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	intstr "k8s.io/apimachinery/pkg/util/intstr"
 	componentbaseconfig "k8s.io/component-base/config"
 	configv1alpha1 "k8s.io/component-base/config/v1alpha1"
 )
@@ -41,6 +43,66 @@ func init() {
 // RegisterConversions adds conversion functions to the given scheme.
 // Public to allow building arbitrary schemes.
 func RegisterConversions(s *runtime.Scheme) error {
+	if err := s.AddGeneratedConversionFunc((*Backoff)(nil), (*config.Backoff)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_Backoff_To_config_Backoff(a.(*Backoff), b.(*config.Backoff), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.Backoff)(nil), (*Backoff)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_Backoff_To_v1alpha1_Backoff(a.(*config.Backoff), b.(*Backoff), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*BackupBucketConfig)(nil), (*config.BackupBucketConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_BackupBucketConfig_To_config_BackupBucketConfig(a.(*BackupBucketConfig), b.(*config.BackupBucketConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.BackupBucketConfig)(nil), (*BackupBucketConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_BackupBucketConfig_To_v1alpha1_BackupBucketConfig(a.(*config.BackupBucketConfig), b.(*BackupBucketConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*BackupBucketEncryption)(nil), (*config.BackupBucketEncryption)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_BackupBucketEncryption_To_config_BackupBucketEncryption(a.(*BackupBucketEncryption), b.(*config.BackupBucketEncryption), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.BackupBucketEncryption)(nil), (*BackupBucketEncryption)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_BackupBucketEncryption_To_v1alpha1_BackupBucketEncryption(a.(*config.BackupBucketEncryption), b.(*BackupBucketEncryption), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*BackupBucketLogging)(nil), (*config.BackupBucketLogging)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_BackupBucketLogging_To_config_BackupBucketLogging(a.(*BackupBucketLogging), b.(*config.BackupBucketLogging), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.BackupBucketLogging)(nil), (*BackupBucketLogging)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_BackupBucketLogging_To_v1alpha1_BackupBucketLogging(a.(*config.BackupBucketLogging), b.(*BackupBucketLogging), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*BackupBucketStorageClassTransition)(nil), (*config.BackupBucketStorageClassTransition)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_BackupBucketStorageClassTransition_To_config_BackupBucketStorageClassTransition(a.(*BackupBucketStorageClassTransition), b.(*config.BackupBucketStorageClassTransition), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.BackupBucketStorageClassTransition)(nil), (*BackupBucketStorageClassTransition)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_BackupBucketStorageClassTransition_To_v1alpha1_BackupBucketStorageClassTransition(a.(*config.BackupBucketStorageClassTransition), b.(*BackupBucketStorageClassTransition), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*BackupEntryConfig)(nil), (*config.BackupEntryConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_BackupEntryConfig_To_config_BackupEntryConfig(a.(*BackupEntryConfig), b.(*config.BackupEntryConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.BackupEntryConfig)(nil), (*BackupEntryConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_BackupEntryConfig_To_v1alpha1_BackupEntryConfig(a.(*config.BackupEntryConfig), b.(*BackupEntryConfig), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*ControllerConfiguration)(nil), (*config.ControllerConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_ControllerConfiguration_To_config_ControllerConfiguration(a.(*ControllerConfiguration), b.(*config.ControllerConfiguration), scope)
 	}); err != nil {
@@ -81,16 +143,187 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*InfrastructureControllerConfig)(nil), (*config.InfrastructureControllerConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_InfrastructureControllerConfig_To_config_InfrastructureControllerConfig(a.(*InfrastructureControllerConfig), b.(*config.InfrastructureControllerConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.InfrastructureControllerConfig)(nil), (*InfrastructureControllerConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_InfrastructureControllerConfig_To_v1alpha1_InfrastructureControllerConfig(a.(*config.InfrastructureControllerConfig), b.(*InfrastructureControllerConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*NodeConditionsHealthCheck)(nil), (*config.NodeConditionsHealthCheck)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_NodeConditionsHealthCheck_To_config_NodeConditionsHealthCheck(a.(*NodeConditionsHealthCheck), b.(*config.NodeConditionsHealthCheck), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.NodeConditionsHealthCheck)(nil), (*NodeConditionsHealthCheck)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_NodeConditionsHealthCheck_To_v1alpha1_NodeConditionsHealthCheck(a.(*config.NodeConditionsHealthCheck), b.(*NodeConditionsHealthCheck), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*RegionEndpoints)(nil), (*config.RegionEndpoints)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_RegionEndpoints_To_config_RegionEndpoints(a.(*RegionEndpoints), b.(*config.RegionEndpoints), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.RegionEndpoints)(nil), (*RegionEndpoints)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_RegionEndpoints_To_v1alpha1_RegionEndpoints(a.(*config.RegionEndpoints), b.(*RegionEndpoints), scope)
+	}); err != nil {
+		return err
+	}
+	return nil
+}
+
+func autoConvert_v1alpha1_Backoff_To_config_Backoff(in *Backoff, out *config.Backoff, s conversion.Scope) error {
+	out.Base = in.Base
+	out.Max = in.Max
+	out.Factor = in.Factor
+	return nil
+}
+
+// Convert_v1alpha1_Backoff_To_config_Backoff is an autogenerated conversion function.
+func Convert_v1alpha1_Backoff_To_config_Backoff(in *Backoff, out *config.Backoff, s conversion.Scope) error {
+	return autoConvert_v1alpha1_Backoff_To_config_Backoff(in, out, s)
+}
+
+func autoConvert_config_Backoff_To_v1alpha1_Backoff(in *config.Backoff, out *Backoff, s conversion.Scope) error {
+	out.Base = in.Base
+	out.Max = in.Max
+	out.Factor = in.Factor
+	return nil
+}
+
+// Convert_config_Backoff_To_v1alpha1_Backoff is an autogenerated conversion function.
+func Convert_config_Backoff_To_v1alpha1_Backoff(in *config.Backoff, out *Backoff, s conversion.Scope) error {
+	return autoConvert_config_Backoff_To_v1alpha1_Backoff(in, out, s)
+}
+
+func autoConvert_v1alpha1_BackupBucketConfig_To_config_BackupBucketConfig(in *BackupBucketConfig, out *config.BackupBucketConfig, s conversion.Scope) error {
+	out.Versioning = in.Versioning
+	out.Encryption = (*config.BackupBucketEncryption)(unsafe.Pointer(in.Encryption))
+	out.Logging = (*config.BackupBucketLogging)(unsafe.Pointer(in.Logging))
+	out.StorageClassTransition = (*config.BackupBucketStorageClassTransition)(unsafe.Pointer(in.StorageClassTransition))
+	return nil
+}
+
+// Convert_v1alpha1_BackupBucketConfig_To_config_BackupBucketConfig is an autogenerated conversion function.
+func Convert_v1alpha1_BackupBucketConfig_To_config_BackupBucketConfig(in *BackupBucketConfig, out *config.BackupBucketConfig, s conversion.Scope) error {
+	return autoConvert_v1alpha1_BackupBucketConfig_To_config_BackupBucketConfig(in, out, s)
+}
+
+func autoConvert_config_BackupBucketConfig_To_v1alpha1_BackupBucketConfig(in *config.BackupBucketConfig, out *BackupBucketConfig, s conversion.Scope) error {
+	out.Versioning = in.Versioning
+	out.Encryption = (*BackupBucketEncryption)(unsafe.Pointer(in.Encryption))
+	out.Logging = (*BackupBucketLogging)(unsafe.Pointer(in.Logging))
+	out.StorageClassTransition = (*BackupBucketStorageClassTransition)(unsafe.Pointer(in.StorageClassTransition))
 	return nil
 }
 
+// Convert_config_BackupBucketConfig_To_v1alpha1_BackupBucketConfig is an autogenerated conversion function.
+func Convert_config_BackupBucketConfig_To_v1alpha1_BackupBucketConfig(in *config.BackupBucketConfig, out *BackupBucketConfig, s conversion.Scope) error {
+	return autoConvert_config_BackupBucketConfig_To_v1alpha1_BackupBucketConfig(in, out, s)
+}
+
+func autoConvert_v1alpha1_BackupBucketEncryption_To_config_BackupBucketEncryption(in *BackupBucketEncryption, out *config.BackupBucketEncryption, s conversion.Scope) error {
+	out.KMSKeyID = in.KMSKeyID
+	return nil
+}
+
+// Convert_v1alpha1_BackupBucketEncryption_To_config_BackupBucketEncryption is an autogenerated conversion function.
+func Convert_v1alpha1_BackupBucketEncryption_To_config_BackupBucketEncryption(in *BackupBucketEncryption, out *config.BackupBucketEncryption, s conversion.Scope) error {
+	return autoConvert_v1alpha1_BackupBucketEncryption_To_config_BackupBucketEncryption(in, out, s)
+}
+
+func autoConvert_config_BackupBucketEncryption_To_v1alpha1_BackupBucketEncryption(in *config.BackupBucketEncryption, out *BackupBucketEncryption, s conversion.Scope) error {
+	out.KMSKeyID = in.KMSKeyID
+	return nil
+}
+
+// Convert_config_BackupBucketEncryption_To_v1alpha1_BackupBucketEncryption is an autogenerated conversion function.
+func Convert_config_BackupBucketEncryption_To_v1alpha1_BackupBucketEncryption(in *config.BackupBucketEncryption, out *BackupBucketEncryption, s conversion.Scope) error {
+	return autoConvert_config_BackupBucketEncryption_To_v1alpha1_BackupBucketEncryption(in, out, s)
+}
+
+func autoConvert_v1alpha1_BackupBucketLogging_To_config_BackupBucketLogging(in *BackupBucketLogging, out *config.BackupBucketLogging, s conversion.Scope) error {
+	out.TargetBucket = in.TargetBucket
+	out.TargetPrefix = in.TargetPrefix
+	return nil
+}
+
+// Convert_v1alpha1_BackupBucketLogging_To_config_BackupBucketLogging is an autogenerated conversion function.
+func Convert_v1alpha1_BackupBucketLogging_To_config_BackupBucketLogging(in *BackupBucketLogging, out *config.BackupBucketLogging, s conversion.Scope) error {
+	return autoConvert_v1alpha1_BackupBucketLogging_To_config_BackupBucketLogging(in, out, s)
+}
+
+func autoConvert_config_BackupBucketLogging_To_v1alpha1_BackupBucketLogging(in *config.BackupBucketLogging, out *BackupBucketLogging, s conversion.Scope) error {
+	out.TargetBucket = in.TargetBucket
+	out.TargetPrefix = in.TargetPrefix
+	return nil
+}
+
+// Convert_config_BackupBucketLogging_To_v1alpha1_BackupBucketLogging is an autogenerated conversion function.
+func Convert_config_BackupBucketLogging_To_v1alpha1_BackupBucketLogging(in *config.BackupBucketLogging, out *BackupBucketLogging, s conversion.Scope) error {
+	return autoConvert_config_BackupBucketLogging_To_v1alpha1_BackupBucketLogging(in, out, s)
+}
+
+func autoConvert_v1alpha1_BackupBucketStorageClassTransition_To_config_BackupBucketStorageClassTransition(in *BackupBucketStorageClassTransition, out *config.BackupBucketStorageClassTransition, s conversion.Scope) error {
+	out.StorageClass = in.StorageClass
+	out.Days = in.Days
+	return nil
+}
+
+// Convert_v1alpha1_BackupBucketStorageClassTransition_To_config_BackupBucketStorageClassTransition is an autogenerated conversion function.
+func Convert_v1alpha1_BackupBucketStorageClassTransition_To_config_BackupBucketStorageClassTransition(in *BackupBucketStorageClassTransition, out *config.BackupBucketStorageClassTransition, s conversion.Scope) error {
+	return autoConvert_v1alpha1_BackupBucketStorageClassTransition_To_config_BackupBucketStorageClassTransition(in, out, s)
+}
+
+func autoConvert_config_BackupBucketStorageClassTransition_To_v1alpha1_BackupBucketStorageClassTransition(in *config.BackupBucketStorageClassTransition, out *BackupBucketStorageClassTransition, s conversion.Scope) error {
+	out.StorageClass = in.StorageClass
+	out.Days = in.Days
+	return nil
+}
+
+// Convert_config_BackupBucketStorageClassTransition_To_v1alpha1_BackupBucketStorageClassTransition is an autogenerated conversion function.
+func Convert_config_BackupBucketStorageClassTransition_To_v1alpha1_BackupBucketStorageClassTransition(in *config.BackupBucketStorageClassTransition, out *BackupBucketStorageClassTransition, s conversion.Scope) error {
+	return autoConvert_config_BackupBucketStorageClassTransition_To_v1alpha1_BackupBucketStorageClassTransition(in, out, s)
+}
+
+func autoConvert_v1alpha1_BackupEntryConfig_To_config_BackupEntryConfig(in *BackupEntryConfig, out *config.BackupEntryConfig, s conversion.Scope) error {
+	out.RetentionPeriod = (*v1.Duration)(unsafe.Pointer(in.RetentionPeriod))
+	return nil
+}
+
+// Convert_v1alpha1_BackupEntryConfig_To_config_BackupEntryConfig is an autogenerated conversion function.
+func Convert_v1alpha1_BackupEntryConfig_To_config_BackupEntryConfig(in *BackupEntryConfig, out *config.BackupEntryConfig, s conversion.Scope) error {
+	return autoConvert_v1alpha1_BackupEntryConfig_To_config_BackupEntryConfig(in, out, s)
+}
+
+func autoConvert_config_BackupEntryConfig_To_v1alpha1_BackupEntryConfig(in *config.BackupEntryConfig, out *BackupEntryConfig, s conversion.Scope) error {
+	out.RetentionPeriod = (*v1.Duration)(unsafe.Pointer(in.RetentionPeriod))
+	return nil
+}
+
+// Convert_config_BackupEntryConfig_To_v1alpha1_BackupEntryConfig is an autogenerated conversion function.
+func Convert_config_BackupEntryConfig_To_v1alpha1_BackupEntryConfig(in *config.BackupEntryConfig, out *BackupEntryConfig, s conversion.Scope) error {
+	return autoConvert_config_BackupEntryConfig_To_v1alpha1_BackupEntryConfig(in, out, s)
+}
+
 func autoConvert_v1alpha1_ControllerConfiguration_To_config_ControllerConfiguration(in *ControllerConfiguration, out *config.ControllerConfiguration, s conversion.Scope) error {
 	out.ClientConnection = (*componentbaseconfig.ClientConnectionConfiguration)(unsafe.Pointer(in.ClientConnection))
-	out.MachineImageOwnerSecretRef = (*v1.SecretReference)(unsafe.Pointer(in.MachineImageOwnerSecretRef))
+	out.MachineImageOwnerSecretRef = (*corev1.SecretReference)(unsafe.Pointer(in.MachineImageOwnerSecretRef))
+	out.SeedCredentialsSecretRef = (*corev1.SecretReference)(unsafe.Pointer(in.SeedCredentialsSecretRef))
 	if err := Convert_v1alpha1_ETCD_To_config_ETCD(&in.ETCD, &out.ETCD, s); err != nil {
 		return err
 	}
 	out.HealthCheckConfig = (*healthcheckconfig.HealthCheckConfig)(unsafe.Pointer(in.HealthCheckConfig))
+	out.NodeConditionsHealthCheck = (*config.NodeConditionsHealthCheck)(unsafe.Pointer(in.NodeConditionsHealthCheck))
+	out.Infrastructure = (*config.InfrastructureControllerConfig)(unsafe.Pointer(in.Infrastructure))
+	out.BackupBucket = (*config.BackupBucketConfig)(unsafe.Pointer(in.BackupBucket))
+	out.BackupEntry = (*config.BackupEntryConfig)(unsafe.Pointer(in.BackupEntry))
+	out.Endpoints = *(*[]config.RegionEndpoints)(unsafe.Pointer(&in.Endpoints))
+	out.UseInternalOSSEndpoint = in.UseInternalOSSEndpoint
 	return nil
 }
 
@@ -101,11 +334,18 @@ func Convert_v1alpha1_ControllerConfiguration_To_config_ControllerConfiguration(
 
 func autoConvert_config_ControllerConfiguration_To_v1alpha1_ControllerConfiguration(in *config.ControllerConfiguration, out *ControllerConfiguration, s conversion.Scope) error {
 	out.ClientConnection = (*configv1alpha1.ClientConnectionConfiguration)(unsafe.Pointer(in.ClientConnection))
-	out.MachineImageOwnerSecretRef = (*v1.SecretReference)(unsafe.Pointer(in.MachineImageOwnerSecretRef))
+	out.MachineImageOwnerSecretRef = (*corev1.SecretReference)(unsafe.Pointer(in.MachineImageOwnerSecretRef))
+	out.SeedCredentialsSecretRef = (*corev1.SecretReference)(unsafe.Pointer(in.SeedCredentialsSecretRef))
 	if err := Convert_config_ETCD_To_v1alpha1_ETCD(&in.ETCD, &out.ETCD, s); err != nil {
 		return err
 	}
 	out.HealthCheckConfig = (*healthcheckconfigv1alpha1.HealthCheckConfig)(unsafe.Pointer(in.HealthCheckConfig))
+	out.NodeConditionsHealthCheck = (*NodeConditionsHealthCheck)(unsafe.Pointer(in.NodeConditionsHealthCheck))
+	out.Infrastructure = (*InfrastructureControllerConfig)(unsafe.Pointer(in.Infrastructure))
+	out.BackupBucket = (*BackupBucketConfig)(unsafe.Pointer(in.BackupBucket))
+	out.BackupEntry = (*BackupEntryConfig)(unsafe.Pointer(in.BackupEntry))
+	out.Endpoints = *(*[]RegionEndpoints)(unsafe.Pointer(&in.Endpoints))
+	out.UseInternalOSSEndpoint = in.UseInternalOSSEndpoint
 	return nil
 }
 
@@ -185,3 +425,75 @@ func autoConvert_config_ETCDStorage_To_v1alpha1_ETCDStorage(in *config.ETCDStora
 func Convert_config_ETCDStorage_To_v1alpha1_ETCDStorage(in *config.ETCDStorage, out *ETCDStorage, s conversion.Scope) error {
 	return autoConvert_config_ETCDStorage_To_v1alpha1_ETCDStorage(in, out, s)
 }
+
+func autoConvert_v1alpha1_InfrastructureControllerConfig_To_config_InfrastructureControllerConfig(in *InfrastructureControllerConfig, out *config.InfrastructureControllerConfig, s conversion.Scope) error {
+	out.ErrorBackoff = (*config.Backoff)(unsafe.Pointer(in.ErrorBackoff))
+	return nil
+}
+
+// Convert_v1alpha1_InfrastructureControllerConfig_To_config_InfrastructureControllerConfig is an autogenerated conversion function.
+func Convert_v1alpha1_InfrastructureControllerConfig_To_config_InfrastructureControllerConfig(in *InfrastructureControllerConfig, out *config.InfrastructureControllerConfig, s conversion.Scope) error {
+	return autoConvert_v1alpha1_InfrastructureControllerConfig_To_config_InfrastructureControllerConfig(in, out, s)
+}
+
+func autoConvert_config_InfrastructureControllerConfig_To_v1alpha1_InfrastructureControllerConfig(in *config.InfrastructureControllerConfig, out *InfrastructureControllerConfig, s conversion.Scope) error {
+	out.ErrorBackoff = (*Backoff)(unsafe.Pointer(in.ErrorBackoff))
+	return nil
+}
+
+// Convert_config_InfrastructureControllerConfig_To_v1alpha1_InfrastructureControllerConfig is an autogenerated conversion function.
+func Convert_config_InfrastructureControllerConfig_To_v1alpha1_InfrastructureControllerConfig(in *config.InfrastructureControllerConfig, out *InfrastructureControllerConfig, s conversion.Scope) error {
+	return autoConvert_config_InfrastructureControllerConfig_To_v1alpha1_InfrastructureControllerConfig(in, out, s)
+}
+
+func autoConvert_v1alpha1_NodeConditionsHealthCheck_To_config_NodeConditionsHealthCheck(in *NodeConditionsHealthCheck, out *config.NodeConditionsHealthCheck, s conversion.Scope) error {
+	out.ConditionTypes = *(*[]corev1.NodeConditionType)(unsafe.Pointer(&in.ConditionTypes))
+	out.MaxUnhealthyNodes = (*intstr.IntOrString)(unsafe.Pointer(in.MaxUnhealthyNodes))
+	return nil
+}
+
+// Convert_v1alpha1_NodeConditionsHealthCheck_To_config_NodeConditionsHealthCheck is an autogenerated conversion function.
+func Convert_v1alpha1_NodeConditionsHealthCheck_To_config_NodeConditionsHealthCheck(in *NodeConditionsHealthCheck, out *config.NodeConditionsHealthCheck, s conversion.Scope) error {
+	return autoConvert_v1alpha1_NodeConditionsHealthCheck_To_config_NodeConditionsHealthCheck(in, out, s)
+}
+
+func autoConvert_config_NodeConditionsHealthCheck_To_v1alpha1_NodeConditionsHealthCheck(in *config.NodeConditionsHealthCheck, out *NodeConditionsHealthCheck, s conversion.Scope) error {
+	out.ConditionTypes = *(*[]corev1.NodeConditionType)(unsafe.Pointer(&in.ConditionTypes))
+	out.MaxUnhealthyNodes = (*intstr.IntOrString)(unsafe.Pointer(in.MaxUnhealthyNodes))
+	return nil
+}
+
+// Convert_config_NodeConditionsHealthCheck_To_v1alpha1_NodeConditionsHealthCheck is an autogenerated conversion function.
+func Convert_config_NodeConditionsHealthCheck_To_v1alpha1_NodeConditionsHealthCheck(in *config.NodeConditionsHealthCheck, out *NodeConditionsHealthCheck, s conversion.Scope) error {
+	return autoConvert_config_NodeConditionsHealthCheck_To_v1alpha1_NodeConditionsHealthCheck(in, out, s)
+}
+
+func autoConvert_v1alpha1_RegionEndpoints_To_config_RegionEndpoints(in *RegionEndpoints, out *config.RegionEndpoints, s conversion.Scope) error {
+	out.Region = in.Region
+	out.ECS = (*string)(unsafe.Pointer(in.ECS))
+	out.VPC = (*string)(unsafe.Pointer(in.VPC))
+	out.OSS = (*string)(unsafe.Pointer(in.OSS))
+	out.STS = (*string)(unsafe.Pointer(in.STS))
+	out.SLB = (*string)(unsafe.Pointer(in.SLB))
+	return nil
+}
+
+// Convert_v1alpha1_RegionEndpoints_To_config_RegionEndpoints is an autogenerated conversion function.
+func Convert_v1alpha1_RegionEndpoints_To_config_RegionEndpoints(in *RegionEndpoints, out *config.RegionEndpoints, s conversion.Scope) error {
+	return autoConvert_v1alpha1_RegionEndpoints_To_config_RegionEndpoints(in, out, s)
+}
+
+func autoConvert_config_RegionEndpoints_To_v1alpha1_RegionEndpoints(in *config.RegionEndpoints, out *RegionEndpoints, s conversion.Scope) error {
+	out.Region = in.Region
+	out.ECS = (*string)(unsafe.Pointer(in.ECS))
+	out.VPC = (*string)(unsafe.Pointer(in.VPC))
+	out.OSS = (*string)(unsafe.Pointer(in.OSS))
+	out.STS = (*string)(unsafe.Pointer(in.STS))
+	out.SLB = (*string)(unsafe.Pointer(in.SLB))
+	return nil
+}
+
+// Convert_config_RegionEndpoints_To_v1alpha1_RegionEndpoints is an autogenerated conversion function.
+func Convert_config_RegionEndpoints_To_v1alpha1_RegionEndpoints(in *config.RegionEndpoints, out *RegionEndpoints, s conversion.Scope) error {
+	return autoConvert_config_RegionEndpoints_To_v1alpha1_RegionEndpoints(in, out, s)
+}
@@ -22,11 +22,131 @@ package v1alpha1
 
 import (
 	healthcheckconfigv1alpha1 "github.com/gardener/gardener-extensions/pkg/controller/healthcheck/config/v1alpha1"
-	v1 "k8s.io/api/core/v1"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	intstr "k8s.io/apimachinery/pkg/util/intstr"
 	configv1alpha1 "k8s.io/component-base/config/v1alpha1"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Backoff) DeepCopyInto(out *Backoff) {
+	*out = *in
+	out.Base = in.Base
+	out.Max = in.Max
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Backoff.
+func (in *Backoff) DeepCopy() *Backoff {
+	if in == nil {
+		return nil
+	}
+	out := new(Backoff)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupBucketConfig) DeepCopyInto(out *BackupBucketConfig) {
+	*out = *in
+	if in.Encryption != nil {
+		in, out := &in.Encryption, &out.Encryption
+		*out = new(BackupBucketEncryption)
+		**out = **in
+	}
+	if in.Logging != nil {
+		in, out := &in.Logging, &out.Logging
+		*out = new(BackupBucketLogging)
+		**out = **in
+	}
+	if in.StorageClassTransition != nil {
+		in, out := &in.StorageClassTransition, &out.StorageClassTransition
+		*out = new(BackupBucketStorageClassTransition)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupBucketConfig.
+func (in *BackupBucketConfig) DeepCopy() *BackupBucketConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupBucketConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupBucketEncryption) DeepCopyInto(out *BackupBucketEncryption) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupBucketEncryption.
+func (in *BackupBucketEncryption) DeepCopy() *BackupBucketEncryption {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupBucketEncryption)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupBucketLogging) DeepCopyInto(out *BackupBucketLogging) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupBucketLogging.
+func (in *BackupBucketLogging) DeepCopy() *BackupBucketLogging {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupBucketLogging)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupBucketStorageClassTransition) DeepCopyInto(out *BackupBucketStorageClassTransition) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupBucketStorageClassTransition.
+func (in *BackupBucketStorageClassTransition) DeepCopy() *BackupBucketStorageClassTransition {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupBucketStorageClassTransition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupEntryConfig) DeepCopyInto(out *BackupEntryConfig) {
+	*out = *in
+	if in.RetentionPeriod != nil {
+		in, out := &in.RetentionPeriod, &out.RetentionPeriod
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupEntryConfig.
+func (in *BackupEntryConfig) DeepCopy() *BackupEntryConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupEntryConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ControllerConfiguration) DeepCopyInto(out *ControllerConfiguration) {
 	*out = *in
@@ -38,7 +158,12 @@ func (in *ControllerConfiguration) DeepCopyInto(out *ControllerConfiguration) {
 	}
 	if in.MachineImageOwnerSecretRef != nil {
 		in, out := &in.MachineImageOwnerSecretRef, &out.MachineImageOwnerSecretRef
-		*out = new(v1.SecretReference)
+		*out = new(corev1.SecretReference)
+		**out = **in
+	}
+	if in.SeedCredentialsSecretRef != nil {
+		in, out := &in.SeedCredentialsSecretRef, &out.SeedCredentialsSecretRef
+		*out = new(corev1.SecretReference)
 		**out = **in
 	}
 	in.ETCD.DeepCopyInto(&out.ETCD)
@@ -47,6 +172,33 @@ func (in *ControllerConfiguration) DeepCopyInto(out *ControllerConfiguration) {
 		*out = new(healthcheckconfigv1alpha1.HealthCheckConfig)
 		**out = **in
 	}
+	if in.NodeConditionsHealthCheck != nil {
+		in, out := &in.NodeConditionsHealthCheck, &out.NodeConditionsHealthCheck
+		*out = new(NodeConditionsHealthCheck)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Infrastructure != nil {
+		in, out := &in.Infrastructure, &out.Infrastructure
+		*out = new(InfrastructureControllerConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BackupBucket != nil {
+		in, out := &in.BackupBucket, &out.BackupBucket
+		*out = new(BackupBucketConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BackupEntry != nil {
+		in, out := &in.BackupEntry, &out.BackupEntry
+		*out = new(BackupEntryConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Endpoints != nil {
+		in, out := &in.Endpoints, &out.Endpoints
+		*out = make([]RegionEndpoints, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -132,3 +284,91 @@ func (in *ETCDStorage) DeepCopy() *ETCDStorage {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InfrastructureControllerConfig) DeepCopyInto(out *InfrastructureControllerConfig) {
+	*out = *in
+	if in.ErrorBackoff != nil {
+		in, out := &in.ErrorBackoff, &out.ErrorBackoff
+		*out = new(Backoff)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InfrastructureControllerConfig.
+func (in *InfrastructureControllerConfig) DeepCopy() *InfrastructureControllerConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(InfrastructureControllerConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeConditionsHealthCheck) DeepCopyInto(out *NodeConditionsHealthCheck) {
+	*out = *in
+	if in.ConditionTypes != nil {
+		in, out := &in.ConditionTypes, &out.ConditionTypes
+		*out = make([]corev1.NodeConditionType, len(*in))
+		copy(*out, *in)
+	}
+	if in.MaxUnhealthyNodes != nil {
+		in, out := &in.MaxUnhealthyNodes, &out.MaxUnhealthyNodes
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeConditionsHealthCheck.
+func (in *NodeConditionsHealthCheck) DeepCopy() *NodeConditionsHealthCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeConditionsHealthCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RegionEndpoints) DeepCopyInto(out *RegionEndpoints) {
+	*out = *in
+	if in.ECS != nil {
+		in, out := &in.ECS, &out.ECS
+		*out = new(string)
+		**out = **in
+	}
+	if in.VPC != nil {
+		in, out := &in.VPC, &out.VPC
+		*out = new(string)
+		**out = **in
+	}
+	if in.OSS != nil {
+		in, out := &in.OSS, &out.OSS
+		*out = new(string)
+		**out = **in
+	}
+	if in.STS != nil {
+		in, out := &in.STS, &out.STS
+		*out = new(string)
+		**out = **in
+	}
+	if in.SLB != nil {
+		in, out := &in.SLB, &out.SLB
+		*out = new(string)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RegionEndpoints.
+func (in *RegionEndpoints) DeepCopy() *RegionEndpoints {
+	if in == nil {
+		return nil
+	}
+	out := new(RegionEndpoints)
+	in.DeepCopyInto(out)
+	return out
+}
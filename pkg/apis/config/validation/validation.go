@@ -0,0 +1,48 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validation
+
+import (
+	"github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/config"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// ValidateControllerConfiguration validates a ControllerConfiguration object.
+func ValidateControllerConfiguration(cfg *config.ControllerConfiguration) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if cfg.Infrastructure != nil && cfg.Infrastructure.ErrorBackoff != nil {
+		allErrs = append(allErrs, validateBackoff(cfg.Infrastructure.ErrorBackoff, field.NewPath("infrastructure", "errorBackoff"))...)
+	}
+
+	return allErrs
+}
+
+func validateBackoff(backoff *config.Backoff, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if backoff.Base.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("base"), backoff.Base.Duration.String(), "must be positive"))
+	}
+	if backoff.Max.Duration < backoff.Base.Duration {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("max"), backoff.Max.Duration.String(), "must not be less than the base"))
+	}
+	if backoff.Factor < 1 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("factor"), backoff.Factor, "must be at least 1"))
+	}
+
+	return allErrs
+}
@@ -0,0 +1,86 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validation_test
+
+import (
+	"time"
+
+	"github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/config"
+	. "github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/config/validation"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+var _ = Describe("ControllerConfiguration validation", func() {
+	var cfg *config.ControllerConfiguration
+
+	BeforeEach(func() {
+		cfg = &config.ControllerConfiguration{
+			Infrastructure: &config.InfrastructureControllerConfig{
+				ErrorBackoff: &config.Backoff{
+					Base:   metav1.Duration{Duration: 10 * time.Second},
+					Max:    metav1.Duration{Duration: 5 * time.Minute},
+					Factor: 2,
+				},
+			},
+		}
+	})
+
+	It("should allow a configuration without an error backoff", func() {
+		Expect(ValidateControllerConfiguration(&config.ControllerConfiguration{})).To(BeEmpty())
+	})
+
+	It("should allow a valid error backoff", func() {
+		Expect(ValidateControllerConfiguration(cfg)).To(BeEmpty())
+	})
+
+	It("should allow a constant error backoff", func() {
+		cfg.Infrastructure.ErrorBackoff.Max = cfg.Infrastructure.ErrorBackoff.Base
+		cfg.Infrastructure.ErrorBackoff.Factor = 1
+
+		Expect(ValidateControllerConfiguration(cfg)).To(BeEmpty())
+	})
+
+	It("should forbid a non-positive base", func() {
+		cfg.Infrastructure.ErrorBackoff.Base = metav1.Duration{}
+
+		Expect(ValidateControllerConfiguration(cfg)).To(ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
+			"Type":  Equal(field.ErrorTypeInvalid),
+			"Field": Equal("infrastructure.errorBackoff.base"),
+		}))))
+	})
+
+	It("should forbid a max less than the base", func() {
+		cfg.Infrastructure.ErrorBackoff.Max = metav1.Duration{Duration: 5 * time.Second}
+
+		Expect(ValidateControllerConfiguration(cfg)).To(ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
+			"Type":  Equal(field.ErrorTypeInvalid),
+			"Field": Equal("infrastructure.errorBackoff.max"),
+		}))))
+	})
+
+	It("should forbid a factor less than 1", func() {
+		cfg.Infrastructure.ErrorBackoff.Factor = 0.5
+
+		Expect(ValidateControllerConfiguration(cfg)).To(ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
+			"Type":  Equal(field.ErrorTypeInvalid),
+			"Field": Equal("infrastructure.errorBackoff.factor"),
+		}))))
+	})
+})
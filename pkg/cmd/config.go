@@ -17,8 +17,10 @@ package cmd
 import (
 	"fmt"
 
+	alicloudclient "github.com/gardener/gardener-extension-provider-alicloud/pkg/alicloud/client"
 	"github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/config"
 	configloader "github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/config/loader"
+	configvalidation "github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/config/validation"
 	healthcheckconfig "github.com/gardener/gardener-extensions/pkg/controller/healthcheck/config"
 
 	"github.com/spf13/pflag"
@@ -43,7 +45,14 @@ func (c *ConfigOptions) buildConfig() (*config.ControllerConfiguration, error) {
 	if len(c.ConfigFilePath) == 0 {
 		return nil, fmt.Errorf("config file path not set")
 	}
-	return configloader.LoadFromFile(c.ConfigFilePath)
+	cfg, err := configloader.LoadFromFile(c.ConfigFilePath)
+	if err != nil {
+		return nil, err
+	}
+	if errs := configvalidation.ValidateControllerConfiguration(cfg); len(errs) > 0 {
+		return nil, fmt.Errorf("invalid controller configuration: %v", errs.ToAggregate())
+	}
+	return cfg, nil
 }
 
 // Complete implements RESTCompleter.Complete.
@@ -82,6 +91,16 @@ func (c *Config) ApplyMachineImageOwnerSecretRef(secretRef **corev1.SecretRefere
 	}
 }
 
+// ApplySeedCredentialsSecretRef sets the given seed credentials secret reference to that of this Config.
+func (c *Config) ApplySeedCredentialsSecretRef(secretRef **corev1.SecretReference) {
+	if c.Config.SeedCredentialsSecretRef != nil {
+		*secretRef = &corev1.SecretReference{
+			Name:      c.Config.SeedCredentialsSecretRef.Name,
+			Namespace: c.Config.SeedCredentialsSecretRef.Namespace,
+		}
+	}
+}
+
 // ApplyETCDStorage sets the given etcd storage configuration to that of this Config.
 func (c *Config) ApplyETCDStorage(etcdStorage *config.ETCDStorage) {
 	*etcdStorage = c.Config.ETCD.Storage
@@ -92,6 +111,58 @@ func (c *Config) ApplyETCDBackup(etcdBackup *config.ETCDBackup) {
 	*etcdBackup = c.Config.ETCD.Backup
 }
 
+// ApplyBackupBucketConfig sets the given backup bucket configuration to that of this Config.
+func (c *Config) ApplyBackupBucketConfig(backupBucketConfig *config.BackupBucketConfig) {
+	if c.Config.BackupBucket != nil {
+		*backupBucketConfig = *c.Config.BackupBucket
+	}
+}
+
+// ApplyBackupEntryConfig sets the given backup entry configuration to that of this Config.
+func (c *Config) ApplyBackupEntryConfig(backupEntryConfig *config.BackupEntryConfig) {
+	if c.Config.BackupEntry != nil {
+		*backupEntryConfig = *c.Config.BackupEntry
+	}
+}
+
+// ApplyInfrastructureErrorBackoff sets the given backoff of failed Infrastructures to that of this Config.
+func (c *Config) ApplyInfrastructureErrorBackoff(backoff **config.Backoff) {
+	if c.Config.Infrastructure != nil && c.Config.Infrastructure.ErrorBackoff != nil {
+		errorBackoff := *c.Config.Infrastructure.ErrorBackoff
+		*backoff = &errorBackoff
+	}
+}
+
+// ApplyEndpoints sets the given custom endpoints of the Alicloud APIs to those of this Config.
+func (c *Config) ApplyEndpoints(endpoints *map[string]alicloudclient.RegionEndpoints) {
+	if len(c.Config.Endpoints) == 0 {
+		return
+	}
+
+	*endpoints = make(map[string]alicloudclient.RegionEndpoints, len(c.Config.Endpoints))
+	for _, regionEndpoints := range c.Config.Endpoints {
+		(*endpoints)[regionEndpoints.Region] = alicloudclient.RegionEndpoints{
+			ECS: stringValue(regionEndpoints.ECS),
+			VPC: stringValue(regionEndpoints.VPC),
+			OSS: stringValue(regionEndpoints.OSS),
+			STS: stringValue(regionEndpoints.STS),
+			SLB: stringValue(regionEndpoints.SLB),
+		}
+	}
+}
+
+// ApplyUseInternalOSSEndpoint sets whether the internal OSS endpoints are used to that of this Config.
+func (c *Config) ApplyUseInternalOSSEndpoint(useInternalOSSEndpoint *bool) {
+	*useInternalOSSEndpoint = c.Config.UseInternalOSSEndpoint
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
 // Options initializes empty config.ControllerConfiguration, applies the set values and returns it.
 func (c *Config) Options() config.ControllerConfiguration {
 	var cfg config.ControllerConfiguration
@@ -105,3 +176,10 @@ func (c *Config) ApplyHealthCheckConfig(config *healthcheckconfig.HealthCheckCon
 		*config = *c.Config.HealthCheckConfig
 	}
 }
+
+// ApplyNodeConditionsHealthCheck sets the given configuration of the node conditions health check to that of this Config.
+func (c *Config) ApplyNodeConditionsHealthCheck(nodeConditionsHealthCheck *config.NodeConditionsHealthCheck) {
+	if c.Config.NodeConditionsHealthCheck != nil {
+		*nodeConditionsHealthCheck = *c.Config.NodeConditionsHealthCheck
+	}
+}
@@ -24,7 +24,10 @@ import (
 	controlplanewebhook "github.com/gardener/gardener-extension-provider-alicloud/pkg/webhook/controlplane"
 	controlplanebackupwebhook "github.com/gardener/gardener-extension-provider-alicloud/pkg/webhook/controlplanebackup"
 	controlplaneexposurewebhook "github.com/gardener/gardener-extension-provider-alicloud/pkg/webhook/controlplaneexposure"
+	loadbalancerannotationswebhook "github.com/gardener/gardener-extension-provider-alicloud/pkg/webhook/loadbalancerannotations"
+	loadbalancerdefaultswebhook "github.com/gardener/gardener-extension-provider-alicloud/pkg/webhook/loadbalancerdefaults"
 	shootwebhook "github.com/gardener/gardener-extension-provider-alicloud/pkg/webhook/shoot"
+	workervolumewebhook "github.com/gardener/gardener-extension-provider-alicloud/pkg/webhook/workervolume"
 	extensionsbackupbucketcontroller "github.com/gardener/gardener-extensions/pkg/controller/backupbucket"
 	extensionsbackupentrycontroller "github.com/gardener/gardener-extensions/pkg/controller/backupentry"
 	controllercmd "github.com/gardener/gardener-extensions/pkg/controller/cmd"
@@ -56,5 +59,8 @@ func WebhookSwitchOptions() *webhookcmd.SwitchOptions {
 		webhookcmd.Switch(extensioncontrolplanewebhook.ExposureWebhookName, controlplaneexposurewebhook.AddToManager),
 		webhookcmd.Switch(extensioncontrolplanewebhook.BackupWebhookName, controlplanebackupwebhook.AddToManager),
 		webhookcmd.Switch(extensionshootwebhook.WebhookName, shootwebhook.AddToManager),
+		webhookcmd.Switch(loadbalancerdefaultswebhook.WebhookName, loadbalancerdefaultswebhook.AddToManager),
+		webhookcmd.Switch(loadbalancerannotationswebhook.WebhookName, loadbalancerannotationswebhook.AddToManager),
+		webhookcmd.Switch(workervolumewebhook.WebhookName, workervolumewebhook.AddToManager),
 	)
 }
@@ -16,25 +16,49 @@ package backupbucket
 
 import (
 	"context"
+	"fmt"
+	"regexp"
 
 	alicloudclient "github.com/gardener/gardener-extension-provider-alicloud/pkg/alicloud/client"
+	"github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/config"
 	"github.com/gardener/gardener-extensions/pkg/controller/backupbucket"
 
+	"github.com/aliyun/aliyun-oss-go-sdk/oss"
 	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
 	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 )
 
+// EventReasonBucketACLReverted is the reason of the event which is recorded when the ACL of a bucket has been changed
+// out-of-band and is reverted to private.
+const EventReasonBucketACLReverted = "BucketACLReverted"
+
+var (
+	// regionRegex matches the IDs of Alicloud regions, e.g. `cn-shanghai` or `eu-central-1`.
+	regionRegex = regexp.MustCompile(`^[a-z]{2}(-[a-z0-9]+)+$`)
+	// storageClasses are the storage classes the objects of the backup buckets can be transitioned to.
+	storageClasses = sets.NewString(string(oss.StorageIA), string(oss.StorageArchive))
+)
+
 type actuator struct {
 	backupbucket.Actuator
-	client client.Client
-	logger logr.Logger
+	client           client.Client
+	logger           logr.Logger
+	recorder         record.EventRecorder
+	config           config.BackupBucketConfig
+	newStorageClient func(ctx context.Context, client client.Client, secretRef *corev1.SecretReference, region string) (alicloudclient.Storage, error)
 }
 
-func newActuator() backupbucket.Actuator {
+func newActuator(config config.BackupBucketConfig, recorder record.EventRecorder) backupbucket.Actuator {
 	return &actuator{
-		logger: log.Log.WithName("alicloud-backupbucket-actuator"),
+		logger:           log.Log.WithName("alicloud-backupbucket-actuator"),
+		recorder:         recorder,
+		config:           config,
+		newStorageClient: alicloudclient.NewStorageClientFromSecretRef,
 	}
 }
 
@@ -44,16 +68,82 @@ func (a *actuator) InjectClient(client client.Client) error {
 }
 
 func (a *actuator) Reconcile(ctx context.Context, bb *extensionsv1alpha1.BackupBucket) error {
-	alicloudClient, err := alicloudclient.NewStorageClientFromSecretRef(ctx, a.client, &bb.Spec.SecretRef, bb.Spec.Region)
+	// The region of the bucket may differ from the region of the seed, it is part of the OSS endpoint.
+	if !regionRegex.MatchString(bb.Spec.Region) {
+		return fmt.Errorf("region %q of bucket %s is not a valid Alicloud region", bb.Spec.Region, bb.Name)
+	}
+
+	if transition := a.config.StorageClassTransition; transition != nil && (!storageClasses.Has(transition.StorageClass) || transition.Days < 1) {
+		return fmt.Errorf("invalid storage class transition of the backup buckets to storage class %q after %d days, the storage class must be one of %v and the days must be positive", transition.StorageClass, transition.Days, storageClasses.List())
+	}
+
+	alicloudClient, err := a.newStorageClient(ctx, a.client, &bb.Spec.SecretRef, bb.Spec.Region)
 	if err != nil {
 		return err
 	}
 
-	return alicloudClient.CreateBucketIfNotExists(ctx, bb.Name)
+	// Bucket names are global, hence an existing bucket may have been created in another region.
+	region, err := alicloudClient.GetBucketRegion(ctx, bb.Name)
+	if err != nil {
+		return fmt.Errorf("could not get the region of bucket %s, the credentials may not allow access to region %s: %v", bb.Name, bb.Spec.Region, err)
+	}
+	if region != "" && region != bb.Spec.Region {
+		return fmt.Errorf("bucket %s exists in region %s instead of region %s", bb.Name, region, bb.Spec.Region)
+	}
+
+	if err := alicloudClient.CreateBucketIfNotExists(ctx, bb.Name); err != nil {
+		return fmt.Errorf("could not create bucket %s in region %s: %v", bb.Name, bb.Spec.Region, err)
+	}
+
+	// The bucket is private when it is created, but its ACL may have been changed out-of-band since then.
+	acl, err := alicloudClient.GetBucketACL(ctx, bb.Name)
+	if err != nil {
+		return fmt.Errorf("could not get the ACL of bucket %s: %v", bb.Name, err)
+	}
+	if acl != string(oss.ACLPrivate) {
+		if err := alicloudClient.SetBucketACL(ctx, bb.Name, string(oss.ACLPrivate)); err != nil {
+			return fmt.Errorf("could not make bucket %s private: %v", bb.Name, err)
+		}
+		a.logger.Info("Reverted the ACL of the bucket to private", "bucket", bb.Name, "acl", acl)
+		a.recorder.Eventf(bb, corev1.EventTypeWarning, EventReasonBucketACLReverted, "Reverted the ACL of bucket %s from %s to private", bb.Name, acl)
+	}
+
+	// The lifecycle of the bucket is reset when it is ensured above, hence the transition is removed from the existing
+	// buckets once it is not configured anymore.
+	if transition := a.config.StorageClassTransition; transition != nil {
+		if err := alicloudClient.SetBucketStorageClassTransition(ctx, bb.Name, transition.StorageClass, transition.Days); err != nil {
+			return fmt.Errorf("could not set the storage class transition of bucket %s: %v", bb.Name, err)
+		}
+	}
+
+	if a.config.Versioning {
+		if err := alicloudClient.EnableBucketVersioning(ctx, bb.Name); err != nil {
+			return err
+		}
+	}
+
+	// The encryption is set on every reconciliation, so that a KMS key which has been deleted or disabled in the
+	// meantime is reported.
+	if encryption := a.config.Encryption; encryption != nil {
+		if err := alicloudClient.SetBucketKMSEncryption(ctx, bb.Name, encryption.KMSKeyID); err != nil {
+			return fmt.Errorf("could not encrypt bucket %s with KMS key %s, the key may have been deleted or disabled: %v", bb.Name, encryption.KMSKeyID, err)
+		}
+	}
+
+	// The logging is removed if it is not configured, so that disabling it in the configuration takes effect for the
+	// existing buckets.
+	if logging := a.config.Logging; logging != nil {
+		if err := alicloudClient.SetBucketLogging(ctx, bb.Name, logging.TargetBucket, logging.TargetPrefix); err != nil {
+			return fmt.Errorf("could not enable the access logging of bucket %s to bucket %s: %v", bb.Name, logging.TargetBucket, err)
+		}
+	} else if err := alicloudClient.DeleteBucketLogging(ctx, bb.Name); err != nil {
+		return fmt.Errorf("could not disable the access logging of bucket %s: %v", bb.Name, err)
+	}
+	return nil
 }
 
 func (a *actuator) Delete(ctx context.Context, bb *extensionsv1alpha1.BackupBucket) error {
-	alicloudClient, err := alicloudclient.NewStorageClientFromSecretRef(ctx, a.client, &bb.Spec.SecretRef, bb.Spec.Region)
+	alicloudClient, err := a.newStorageClient(ctx, a.client, &bb.Spec.SecretRef, bb.Spec.Region)
 	if err != nil {
 		return err
 	}
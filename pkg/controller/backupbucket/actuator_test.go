@@ -0,0 +1,265 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backupbucket
+
+import (
+	"context"
+	"fmt"
+
+	alicloudclient "github.com/gardener/gardener-extension-provider-alicloud/pkg/alicloud/client"
+	"github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/config"
+	mockalicloudclient "github.com/gardener/gardener-extension-provider-alicloud/pkg/mock/provider-alicloud/alicloud/client"
+
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("Actuator", func() {
+	var (
+		ctrl          *gomock.Controller
+		ctx           = context.TODO()
+		storageClient *mockalicloudclient.MockStorage
+		backupBucket  *extensionsv1alpha1.BackupBucket
+		recorder      *record.FakeRecorder
+		a             *actuator
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		storageClient = mockalicloudclient.NewMockStorage(ctrl)
+		backupBucket = &extensionsv1alpha1.BackupBucket{
+			ObjectMeta: metav1.ObjectMeta{Name: "bucket"},
+			Spec: extensionsv1alpha1.BackupBucketSpec{
+				Region:    "cn-shanghai",
+				SecretRef: corev1.SecretReference{Namespace: "garden", Name: "backup"},
+			},
+		}
+
+		recorder = record.NewFakeRecorder(1)
+		a = newActuator(config.BackupBucketConfig{}, recorder).(*actuator)
+		a.newStorageClient = func(_ context.Context, _ client.Client, secretRef *corev1.SecretReference, region string) (alicloudclient.Storage, error) {
+			Expect(secretRef).To(Equal(&backupBucket.Spec.SecretRef))
+			Expect(region).To(Equal(backupBucket.Spec.Region))
+			return storageClient, nil
+		}
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Describe("#Reconcile", func() {
+		It("should create the bucket", func() {
+			storageClient.EXPECT().GetBucketRegion(ctx, backupBucket.Name)
+			storageClient.EXPECT().CreateBucketIfNotExists(ctx, backupBucket.Name)
+			storageClient.EXPECT().GetBucketACL(ctx, backupBucket.Name).Return("private", nil)
+			storageClient.EXPECT().DeleteBucketLogging(ctx, backupBucket.Name)
+
+			Expect(a.Reconcile(ctx, backupBucket)).To(Succeed())
+		})
+
+		It("should enable the versioning of the bucket if configured", func() {
+			a.config.Versioning = true
+			gomock.InOrder(
+				storageClient.EXPECT().GetBucketRegion(ctx, backupBucket.Name),
+				storageClient.EXPECT().CreateBucketIfNotExists(ctx, backupBucket.Name),
+				storageClient.EXPECT().GetBucketACL(ctx, backupBucket.Name).Return("private", nil),
+				storageClient.EXPECT().EnableBucketVersioning(ctx, backupBucket.Name),
+				storageClient.EXPECT().DeleteBucketLogging(ctx, backupBucket.Name),
+			)
+
+			Expect(a.Reconcile(ctx, backupBucket)).To(Succeed())
+		})
+
+		It("should encrypt the bucket with the configured KMS key", func() {
+			a.config.Encryption = &config.BackupBucketEncryption{KMSKeyID: "key-1234"}
+			gomock.InOrder(
+				storageClient.EXPECT().GetBucketRegion(ctx, backupBucket.Name),
+				storageClient.EXPECT().CreateBucketIfNotExists(ctx, backupBucket.Name),
+				storageClient.EXPECT().GetBucketACL(ctx, backupBucket.Name).Return("private", nil),
+				storageClient.EXPECT().SetBucketKMSEncryption(ctx, backupBucket.Name, "key-1234"),
+				storageClient.EXPECT().DeleteBucketLogging(ctx, backupBucket.Name),
+			)
+
+			Expect(a.Reconcile(ctx, backupBucket)).To(Succeed())
+		})
+
+		It("should fail if the bucket cannot be encrypted with the configured KMS key", func() {
+			a.config.Encryption = &config.BackupBucketEncryption{KMSKeyID: "key-1234"}
+			gomock.InOrder(
+				storageClient.EXPECT().GetBucketRegion(ctx, backupBucket.Name),
+				storageClient.EXPECT().CreateBucketIfNotExists(ctx, backupBucket.Name),
+				storageClient.EXPECT().GetBucketACL(ctx, backupBucket.Name).Return("private", nil),
+				storageClient.EXPECT().SetBucketKMSEncryption(ctx, backupBucket.Name, "key-1234").Return(fmt.Errorf("KMS key not found")),
+			)
+
+			err := a.Reconcile(ctx, backupBucket)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("key-1234"))
+		})
+
+		It("should enable the access logging of the bucket if configured", func() {
+			a.config.Logging = &config.BackupBucketLogging{TargetBucket: "audit-logs", TargetPrefix: "backup/"}
+			gomock.InOrder(
+				storageClient.EXPECT().GetBucketRegion(ctx, backupBucket.Name),
+				storageClient.EXPECT().CreateBucketIfNotExists(ctx, backupBucket.Name),
+				storageClient.EXPECT().GetBucketACL(ctx, backupBucket.Name).Return("private", nil),
+				storageClient.EXPECT().SetBucketLogging(ctx, backupBucket.Name, "audit-logs", "backup/"),
+			)
+
+			Expect(a.Reconcile(ctx, backupBucket)).To(Succeed())
+		})
+
+		It("should fail if the access logging cannot be enabled", func() {
+			a.config.Logging = &config.BackupBucketLogging{TargetBucket: "audit-logs"}
+			gomock.InOrder(
+				storageClient.EXPECT().GetBucketRegion(ctx, backupBucket.Name),
+				storageClient.EXPECT().CreateBucketIfNotExists(ctx, backupBucket.Name),
+				storageClient.EXPECT().GetBucketACL(ctx, backupBucket.Name).Return("private", nil),
+				storageClient.EXPECT().SetBucketLogging(ctx, backupBucket.Name, "audit-logs", "").Return(fmt.Errorf("target bucket audit-logs for the access logs does not exist")),
+			)
+
+			err := a.Reconcile(ctx, backupBucket)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("target bucket audit-logs for the access logs does not exist"))
+		})
+
+		It("should fail if the access logging cannot be disabled", func() {
+			gomock.InOrder(
+				storageClient.EXPECT().GetBucketRegion(ctx, backupBucket.Name),
+				storageClient.EXPECT().CreateBucketIfNotExists(ctx, backupBucket.Name),
+				storageClient.EXPECT().GetBucketACL(ctx, backupBucket.Name).Return("private", nil),
+				storageClient.EXPECT().DeleteBucketLogging(ctx, backupBucket.Name).Return(fmt.Errorf("error")),
+			)
+
+			Expect(a.Reconcile(ctx, backupBucket)).NotTo(Succeed())
+		})
+
+		It("should transition the objects of the bucket to the configured storage class", func() {
+			a.config.StorageClassTransition = &config.BackupBucketStorageClassTransition{StorageClass: "Archive", Days: 30}
+			gomock.InOrder(
+				storageClient.EXPECT().GetBucketRegion(ctx, backupBucket.Name),
+				storageClient.EXPECT().CreateBucketIfNotExists(ctx, backupBucket.Name),
+				storageClient.EXPECT().GetBucketACL(ctx, backupBucket.Name).Return("private", nil),
+				storageClient.EXPECT().SetBucketStorageClassTransition(ctx, backupBucket.Name, "Archive", 30),
+				storageClient.EXPECT().DeleteBucketLogging(ctx, backupBucket.Name),
+			)
+
+			Expect(a.Reconcile(ctx, backupBucket)).To(Succeed())
+		})
+
+		It("should fail for unsupported storage class transitions", func() {
+			a.config.StorageClassTransition = &config.BackupBucketStorageClassTransition{StorageClass: "ColdArchive", Days: 30}
+
+			err := a.Reconcile(ctx, backupBucket)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(`storage class "ColdArchive"`))
+		})
+
+		It("should create the bucket in a region other than the one of the seed", func() {
+			backupBucket.Spec.Region = "eu-central-1"
+			gomock.InOrder(
+				storageClient.EXPECT().GetBucketRegion(ctx, backupBucket.Name).Return("eu-central-1", nil),
+				storageClient.EXPECT().CreateBucketIfNotExists(ctx, backupBucket.Name),
+				storageClient.EXPECT().GetBucketACL(ctx, backupBucket.Name).Return("private", nil),
+				storageClient.EXPECT().DeleteBucketLogging(ctx, backupBucket.Name),
+			)
+
+			Expect(a.Reconcile(ctx, backupBucket)).To(Succeed())
+		})
+
+		It("should fail for invalid regions", func() {
+			backupBucket.Spec.Region = "Shanghai"
+
+			Expect(a.Reconcile(ctx, backupBucket)).NotTo(Succeed())
+		})
+
+		It("should revert the ACL of the bucket to private", func() {
+			gomock.InOrder(
+				storageClient.EXPECT().GetBucketRegion(ctx, backupBucket.Name),
+				storageClient.EXPECT().CreateBucketIfNotExists(ctx, backupBucket.Name),
+				storageClient.EXPECT().GetBucketACL(ctx, backupBucket.Name).Return("public-read", nil),
+				storageClient.EXPECT().SetBucketACL(ctx, backupBucket.Name, "private"),
+				storageClient.EXPECT().DeleteBucketLogging(ctx, backupBucket.Name),
+			)
+
+			Expect(a.Reconcile(ctx, backupBucket)).To(Succeed())
+			Expect(recorder.Events).To(Receive(ContainSubstring("Reverted the ACL of bucket bucket from public-read to private")))
+		})
+
+		It("should not record an event if the bucket is private", func() {
+			gomock.InOrder(
+				storageClient.EXPECT().GetBucketRegion(ctx, backupBucket.Name),
+				storageClient.EXPECT().CreateBucketIfNotExists(ctx, backupBucket.Name),
+				storageClient.EXPECT().GetBucketACL(ctx, backupBucket.Name).Return("private", nil),
+				storageClient.EXPECT().DeleteBucketLogging(ctx, backupBucket.Name),
+			)
+
+			Expect(a.Reconcile(ctx, backupBucket)).To(Succeed())
+			Expect(recorder.Events).NotTo(Receive())
+		})
+
+		It("should fail if the ACL of the bucket cannot be reverted", func() {
+			gomock.InOrder(
+				storageClient.EXPECT().GetBucketRegion(ctx, backupBucket.Name),
+				storageClient.EXPECT().CreateBucketIfNotExists(ctx, backupBucket.Name),
+				storageClient.EXPECT().GetBucketACL(ctx, backupBucket.Name).Return("public-read-write", nil),
+				storageClient.EXPECT().SetBucketACL(ctx, backupBucket.Name, "private").Return(fmt.Errorf("access denied")),
+			)
+
+			err := a.Reconcile(ctx, backupBucket)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("could not make bucket bucket private"))
+			Expect(recorder.Events).NotTo(Receive())
+		})
+
+		It("should fail if the bucket exists in another region", func() {
+			storageClient.EXPECT().GetBucketRegion(ctx, backupBucket.Name).Return("eu-central-1", nil)
+
+			err := a.Reconcile(ctx, backupBucket)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("exists in region eu-central-1"))
+		})
+
+		It("should fail if the bucket cannot be created", func() {
+			a.config.Versioning = true
+			storageClient.EXPECT().GetBucketRegion(ctx, backupBucket.Name)
+			storageClient.EXPECT().CreateBucketIfNotExists(ctx, backupBucket.Name).Return(fmt.Errorf("error"))
+
+			Expect(a.Reconcile(ctx, backupBucket)).NotTo(Succeed())
+		})
+	})
+
+	Describe("#Delete", func() {
+		It("should delete the bucket", func() {
+			storageClient.EXPECT().DeleteBucketIfExists(ctx, backupBucket.Name)
+
+			Expect(a.Delete(ctx, backupBucket)).To(Succeed())
+		})
+
+		It("should return the error if the bucket cannot be deleted", func() {
+			err := fmt.Errorf("bucket %s still contains objects after deleting all of them", backupBucket.Name)
+			storageClient.EXPECT().DeleteBucketIfExists(ctx, backupBucket.Name).Return(err)
+
+			Expect(a.Delete(ctx, backupBucket)).To(MatchError(err))
+		})
+	})
+})
@@ -16,12 +16,16 @@ package backupbucket
 
 import (
 	"github.com/gardener/gardener-extension-provider-alicloud/pkg/alicloud"
+	"github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/config"
 	"github.com/gardener/gardener-extensions/pkg/controller/backupbucket"
 
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 )
 
+// EventRecorderName is the name of the event recorder of the backupbucket controller.
+const EventRecorderName = "alicloud-backupbucket-controller"
+
 var (
 	// DefaultAddOptions are the default options for AddToManager.
 	DefaultAddOptions = AddOptions{}
@@ -33,13 +37,15 @@ type AddOptions struct {
 	Controller controller.Options
 	// IgnoreOperationAnnotation specifies whether to ignore the operation annotation or not.
 	IgnoreOperationAnnotation bool
+	// BackupBucketConfig is the configuration of the backup buckets.
+	BackupBucketConfig config.BackupBucketConfig
 }
 
 // AddToManagerWithOptions adds a controller with the given Options to the given manager.
 // The opts.Reconciler is being set with a newly instantiated actuator.
 func AddToManagerWithOptions(mgr manager.Manager, opts AddOptions) error {
 	return backupbucket.Add(mgr, backupbucket.AddArgs{
-		Actuator:          newActuator(),
+		Actuator:          newActuator(opts.BackupBucketConfig, mgr.GetEventRecorderFor(EventRecorderName)),
 		ControllerOptions: opts.Controller,
 		Predicates:        backupbucket.DefaultPredicates(opts.IgnoreOperationAnnotation),
 		Type:              alicloud.Type,
@@ -17,24 +17,44 @@ package backupentry
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/gardener/gardener-extension-provider-alicloud/pkg/alicloud"
 	alicloudclient "github.com/gardener/gardener-extension-provider-alicloud/pkg/alicloud/client"
+	"github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/config"
+	extensioncontroller "github.com/gardener/gardener-extensions/pkg/controller"
 	"github.com/gardener/gardener-extensions/pkg/controller/backupentry/genericactuator"
 
+	"github.com/aliyun/aliyun-oss-go-sdk/oss"
 	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
 	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/util/retry"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+// AnnotationKeyRestoreSource is the key of the annotation of BackupEntries which specifies existing backups in the
+// form `<bucket>/<prefix>`, e.g. those of a shoot which is recovered from a disaster. They are copied to the prefix of
+// the BackupEntry, so that etcd is restored from them. The annotation is removed once the backups have been copied.
+const AnnotationKeyRestoreSource = "alicloud.provider.extensions.gardener.cloud/restore-source"
+
 type actuator struct {
-	client client.Client
-	logger logr.Logger
+	client           client.Client
+	logger           logr.Logger
+	config           config.BackupEntryConfig
+	bucketConfig     config.BackupBucketConfig
+	newStorageClient func(ctx context.Context, client client.Client, secretRef *corev1.SecretReference, region string) (alicloudclient.Storage, error)
+	now              func() time.Time
 }
 
-func newActuator() genericactuator.BackupEntryDelegate {
+func newActuator(config config.BackupEntryConfig, bucketConfig config.BackupBucketConfig) genericactuator.BackupEntryDelegate {
 	return &actuator{
-		logger: logger,
+		logger:           logger,
+		config:           config,
+		bucketConfig:     bucketConfig,
+		newStorageClient: alicloudclient.NewStorageClientFromSecretRef,
+		now:              time.Now,
 	}
 }
 
@@ -44,15 +64,95 @@ func (a *actuator) InjectClient(client client.Client) error {
 }
 
 func (a *actuator) GetETCDSecretData(ctx context.Context, be *extensionsv1alpha1.BackupEntry, backupSecretData map[string][]byte) (map[string][]byte, error) {
-	backupSecretData[alicloud.StorageEndpoint] = []byte(alicloudclient.ComputeStorageEndpoint(be.Spec.Region))
+	if source, ok := be.Annotations[AnnotationKeyRestoreSource]; ok {
+		if err := a.copyRestoreSource(ctx, be, source); err != nil {
+			return nil, err
+		}
+	}
+
+	// etcd-backup-restore cannot read archived objects, hence the latest backup is restored before etcd is started,
+	// e.g. when a shoot which has been hibernated for a long time is woken up.
+	if transition := a.bucketConfig.StorageClassTransition; transition != nil && transition.StorageClass == string(oss.StorageArchive) {
+		if err := a.restoreLatestBackup(ctx, be); err != nil {
+			return nil, err
+		}
+	}
+
+	backupSecretData[alicloud.StorageEndpoint] = []byte(alicloudclient.ResolveStorageEndpoint(ctx, be.Spec.Region))
 	return backupSecretData, nil
 }
 
+// copyRestoreSource copies the backups of the given source `<bucket>/<prefix>` to the prefix of the given backup
+// entry. The backups have to be readable with the credentials of the backup entry.
+func (a *actuator) copyRestoreSource(ctx context.Context, be *extensionsv1alpha1.BackupEntry, source string) error {
+	parts := strings.SplitN(source, "/", 2)
+	if len(parts) != 2 || len(parts[0]) == 0 || len(strings.Trim(parts[1], "/")) == 0 {
+		return fmt.Errorf("restore source %q of backup entry %s must be of the form <bucket>/<prefix>", source, be.Name)
+	}
+	srcBucketName, srcPrefix := parts[0], strings.Trim(parts[1], "/")+"/"
+
+	cli, err := a.newStorageClient(ctx, a.client, &be.Spec.SecretRef, be.Spec.Region)
+	if err != nil {
+		return err
+	}
+
+	copied, err := cli.CopyObjectsWithPrefix(ctx, srcBucketName, srcPrefix, be.Spec.BucketName, fmt.Sprintf("%s/", be.Name))
+	if err != nil {
+		return fmt.Errorf("could not copy the backups of restore source %s to backup entry %s: %v", source, be.Name, err)
+	}
+	if copied > 0 {
+		a.logger.Info("Copied the backups of the restore source", "backupEntry", be.Name, "source", source, "objects", copied)
+	}
+
+	// etcd takes its own backups once it has been restored and garbage collects the copied ones eventually, hence the
+	// backups are only copied once.
+	if err := extensioncontroller.TryUpdate(ctx, retry.DefaultBackoff, a.client, be, func() error {
+		delete(be.Annotations, AnnotationKeyRestoreSource)
+		return nil
+	}); err != nil {
+		return fmt.Errorf("could not remove the restore source annotation of backup entry %s: %v", be.Name, err)
+	}
+	return nil
+}
+
+// restoreLatestBackup restores the archived objects of the latest backup of the given backup entry. It fails while
+// they are being restored, so that etcd is not started before it can read them.
+func (a *actuator) restoreLatestBackup(ctx context.Context, be *extensionsv1alpha1.BackupEntry) error {
+	cli, err := a.newStorageClient(ctx, a.client, &be.Spec.SecretRef, be.Spec.Region)
+	if err != nil {
+		return err
+	}
+
+	restoring, err := cli.RestoreLatestArchivedSnapshots(ctx, be.Spec.BucketName, fmt.Sprintf("%s/", be.Name))
+	if err != nil {
+		return fmt.Errorf("could not restore the archived objects of the latest backup of backup entry %s: %v", be.Name, err)
+	}
+	if restoring > 0 {
+		return fmt.Errorf("%d archived objects of the latest backup of backup entry %s are being restored, etcd can only read them once they are restored", restoring, be.Name)
+	}
+	return nil
+}
+
 func (a *actuator) Delete(ctx context.Context, be *extensionsv1alpha1.BackupEntry) error {
-	cli, err := alicloudclient.NewStorageClientFromSecretRef(ctx, a.client, &be.Spec.SecretRef, be.Spec.Region)
+	cli, err := a.newStorageClient(ctx, a.client, &be.Spec.SecretRef, be.Spec.Region)
 	if err != nil {
 		return err
 	}
 
-	return cli.DeleteObjectsWithPrefix(ctx, be.Spec.BucketName, fmt.Sprintf("%s/", be.Name))
+	prefix := fmt.Sprintf("%s/", be.Name)
+
+	retentionPeriod := a.config.RetentionPeriod
+	if retentionPeriod == nil {
+		return cli.DeleteObjectsWithPrefix(ctx, be.Spec.BucketName, prefix)
+	}
+
+	remaining, err := cli.DeleteObjectsWithPrefixOlderThan(ctx, be.Spec.BucketName, prefix, a.now().Add(-retentionPeriod.Duration))
+	if err != nil {
+		return err
+	}
+	if remaining > 0 {
+		// The deletion is retried until all backups have expired.
+		return fmt.Errorf("%d backups of backup entry %s are younger than the retention period of %s and are deleted once they expire", remaining, be.Name, retentionPeriod.Duration)
+	}
+	return nil
 }
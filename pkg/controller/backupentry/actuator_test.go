@@ -0,0 +1,198 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backupentry
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	alicloudclient "github.com/gardener/gardener-extension-provider-alicloud/pkg/alicloud/client"
+	"github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/config"
+	mockalicloudclient "github.com/gardener/gardener-extension-provider-alicloud/pkg/mock/provider-alicloud/alicloud/client"
+	mockclient "github.com/gardener/gardener-extensions/pkg/mock/controller-runtime/client"
+
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("Actuator", func() {
+	var (
+		ctrl          *gomock.Controller
+		ctx           = context.TODO()
+		now           = time.Date(2020, 5, 1, 0, 0, 0, 0, time.UTC)
+		c             *mockclient.MockClient
+		storageClient *mockalicloudclient.MockStorage
+		backupEntry   *extensionsv1alpha1.BackupEntry
+		a             *actuator
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		c = mockclient.NewMockClient(ctrl)
+		storageClient = mockalicloudclient.NewMockStorage(ctrl)
+		backupEntry = &extensionsv1alpha1.BackupEntry{
+			ObjectMeta: metav1.ObjectMeta{Name: "shoot--foo--bar"},
+			Spec: extensionsv1alpha1.BackupEntrySpec{
+				BucketName: "bucket",
+				Region:     "cn-shanghai",
+				SecretRef:  corev1.SecretReference{Namespace: "garden", Name: "backup"},
+			},
+		}
+
+		a = newActuator(config.BackupEntryConfig{}, config.BackupBucketConfig{}).(*actuator)
+		Expect(a.InjectClient(c)).To(Succeed())
+		a.now = func() time.Time { return now }
+		a.newStorageClient = func(_ context.Context, _ client.Client, _ *corev1.SecretReference, _ string) (alicloudclient.Storage, error) {
+			return storageClient, nil
+		}
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Describe("#GetETCDSecretData", func() {
+		It("should not restore archived objects if the objects are not archived", func() {
+			a.bucketConfig.StorageClassTransition = &config.BackupBucketStorageClassTransition{StorageClass: "IA", Days: 30}
+
+			_, err := a.GetETCDSecretData(ctx, backupEntry, map[string][]byte{})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("restore source", func() {
+			expectRemoveAnnotation := func() *gomock.Call {
+				c.EXPECT().Get(ctx, client.ObjectKey{Name: "shoot--foo--bar"}, backupEntry)
+				return c.EXPECT().Update(ctx, gomock.Any()).Do(func(_ context.Context, obj runtime.Object, _ ...client.UpdateOption) {
+					Expect(obj.(*extensionsv1alpha1.BackupEntry).Annotations).NotTo(HaveKey(AnnotationKeyRestoreSource))
+				})
+			}
+
+			It("should copy the backups of the restore source to the prefix of the backup entry", func() {
+				backupEntry.Annotations = map[string]string{AnnotationKeyRestoreSource: "old-bucket/shoot--foo--old/"}
+				gomock.InOrder(
+					storageClient.EXPECT().CopyObjectsWithPrefix(ctx, "old-bucket", "shoot--foo--old/", "bucket", "shoot--foo--bar/").Return(2, nil),
+					expectRemoveAnnotation(),
+				)
+
+				data, err := a.GetETCDSecretData(ctx, backupEntry, map[string][]byte{})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(data).To(HaveKey("storageEndpoint"))
+			})
+
+			It("should only copy the backups once", func() {
+				backupEntry.Annotations = map[string]string{AnnotationKeyRestoreSource: "old-bucket/shoot--foo--old/"}
+				storageClient.EXPECT().CopyObjectsWithPrefix(ctx, "old-bucket", "shoot--foo--old/", "bucket", "shoot--foo--bar/").Return(2, nil)
+				expectRemoveAnnotation()
+
+				_, err := a.GetETCDSecretData(ctx, backupEntry, map[string][]byte{})
+				Expect(err).NotTo(HaveOccurred())
+				_, err = a.GetETCDSecretData(ctx, backupEntry, map[string][]byte{})
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("should fail if the restore source annotation cannot be removed", func() {
+				backupEntry.Annotations = map[string]string{AnnotationKeyRestoreSource: "old-bucket/shoot--foo--old/"}
+				storageClient.EXPECT().CopyObjectsWithPrefix(ctx, "old-bucket", "shoot--foo--old/", "bucket", "shoot--foo--bar/").Return(2, nil)
+				c.EXPECT().Get(ctx, client.ObjectKey{Name: "shoot--foo--bar"}, backupEntry).Return(fmt.Errorf("fake"))
+
+				_, err := a.GetETCDSecretData(ctx, backupEntry, map[string][]byte{})
+				Expect(err).To(MatchError("could not remove the restore source annotation of backup entry shoot--foo--bar: fake"))
+			})
+
+			It("should copy the backups before the latest backup is restored from the archive", func() {
+				a.bucketConfig.StorageClassTransition = &config.BackupBucketStorageClassTransition{StorageClass: "Archive", Days: 30}
+				backupEntry.Annotations = map[string]string{AnnotationKeyRestoreSource: "old-bucket/shoot--foo--old"}
+				gomock.InOrder(
+					storageClient.EXPECT().CopyObjectsWithPrefix(ctx, "old-bucket", "shoot--foo--old/", "bucket", "shoot--foo--bar/"),
+					expectRemoveAnnotation(),
+					storageClient.EXPECT().RestoreLatestArchivedSnapshots(ctx, "bucket", "shoot--foo--bar/"),
+				)
+
+				_, err := a.GetETCDSecretData(ctx, backupEntry, map[string][]byte{})
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("should fail if the backups of the restore source cannot be copied", func() {
+				backupEntry.Annotations = map[string]string{AnnotationKeyRestoreSource: "old-bucket/shoot--foo--old"}
+				storageClient.EXPECT().CopyObjectsWithPrefix(ctx, "old-bucket", "shoot--foo--old/", "bucket", "shoot--foo--bar/").Return(0, fmt.Errorf("there is no full snapshot with prefix shoot--foo--old/ in bucket old-bucket"))
+
+				_, err := a.GetETCDSecretData(ctx, backupEntry, map[string][]byte{})
+				Expect(err).To(MatchError(ContainSubstring("there is no full snapshot")))
+			})
+
+			It("should fail for invalid restore sources", func() {
+				for _, source := range []string{"", "old-bucket", "old-bucket/", "/shoot--foo--old"} {
+					backupEntry.Annotations = map[string]string{AnnotationKeyRestoreSource: source}
+
+					_, err := a.GetETCDSecretData(ctx, backupEntry, map[string][]byte{})
+					Expect(err).To(MatchError(ContainSubstring("must be of the form <bucket>/<prefix>")), source)
+				}
+			})
+		})
+
+		Context("archive", func() {
+			BeforeEach(func() {
+				a.bucketConfig.StorageClassTransition = &config.BackupBucketStorageClassTransition{StorageClass: "Archive", Days: 30}
+			})
+
+			It("should succeed if the latest backup can be read", func() {
+				storageClient.EXPECT().RestoreLatestArchivedSnapshots(ctx, "bucket", "shoot--foo--bar/")
+
+				_, err := a.GetETCDSecretData(ctx, backupEntry, map[string][]byte{})
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("should fail while the latest backup is being restored", func() {
+				storageClient.EXPECT().RestoreLatestArchivedSnapshots(ctx, "bucket", "shoot--foo--bar/").Return(3, nil)
+
+				_, err := a.GetETCDSecretData(ctx, backupEntry, map[string][]byte{})
+				Expect(err).To(MatchError("3 archived objects of the latest backup of backup entry shoot--foo--bar are being restored, etcd can only read them once they are restored"))
+			})
+		})
+	})
+
+	Describe("#Delete", func() {
+		It("should delete all objects of the backup entry", func() {
+			storageClient.EXPECT().DeleteObjectsWithPrefix(ctx, "bucket", "shoot--foo--bar/")
+
+			Expect(a.Delete(ctx, backupEntry)).To(Succeed())
+		})
+
+		Context("retention period", func() {
+			BeforeEach(func() {
+				a.config.RetentionPeriod = &metav1.Duration{Duration: 24 * time.Hour}
+			})
+
+			It("should delete the expired objects of the backup entry", func() {
+				storageClient.EXPECT().DeleteObjectsWithPrefixOlderThan(ctx, "bucket", "shoot--foo--bar/", now.Add(-24*time.Hour))
+
+				Expect(a.Delete(ctx, backupEntry)).To(Succeed())
+			})
+
+			It("should fail while objects of the backup entry have not yet expired", func() {
+				storageClient.EXPECT().DeleteObjectsWithPrefixOlderThan(ctx, "bucket", "shoot--foo--bar/", now.Add(-24*time.Hour)).Return(3, nil)
+
+				Expect(a.Delete(ctx, backupEntry)).To(MatchError(ContainSubstring("3 backups")))
+			})
+		})
+	})
+})
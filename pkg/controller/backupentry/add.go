@@ -16,6 +16,7 @@ package backupentry
 
 import (
 	"github.com/gardener/gardener-extension-provider-alicloud/pkg/alicloud"
+	"github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/config"
 	"github.com/gardener/gardener-extensions/pkg/controller/backupentry"
 	"github.com/gardener/gardener-extensions/pkg/controller/backupentry/genericactuator"
 
@@ -37,13 +38,17 @@ type AddOptions struct {
 	Controller controller.Options
 	// IgnoreOperationAnnotation specifies whether to ignore the operation annotation or not.
 	IgnoreOperationAnnotation bool
+	// BackupEntryConfig is the configuration of the backup entries.
+	BackupEntryConfig config.BackupEntryConfig
+	// BackupBucketConfig is the configuration of the backup buckets which contain the backup entries.
+	BackupBucketConfig config.BackupBucketConfig
 }
 
 // AddToManagerWithOptions adds a controller with the given Options to the given manager.
 // The opts.Reconciler is being set with a newly instantiated actuator.
 func AddToManagerWithOptions(mgr manager.Manager, opts AddOptions) error {
 	return backupentry.Add(mgr, backupentry.AddArgs{
-		Actuator:          genericactuator.NewActuator(newActuator(), logger),
+		Actuator:          genericactuator.NewActuator(newActuator(opts.BackupEntryConfig, opts.BackupBucketConfig), logger),
 		ControllerOptions: opts.Controller,
 		Predicates:        backupentry.DefaultPredicates(opts.IgnoreOperationAnnotation),
 		Type:              alicloud.Type,
@@ -28,6 +28,7 @@ import (
 const (
 	TerraformVarAccessKeyID     = "TF_VAR_ACCESS_KEY_ID"
 	TerraformVarAccessKeySecret = "TF_VAR_ACCESS_KEY_SECRET"
+	TerraformVarSecurityToken   = "TF_VAR_SECURITY_TOKEN"
 )
 
 // NewTerraformer creates a new Terraformer and initializes it with the credentials.
@@ -41,6 +42,9 @@ func NewTerraformer(factory terraformer.Factory, config *rest.Config, credential
 		TerraformVarAccessKeyID:     credentials.AccessKeyID,
 		TerraformVarAccessKeySecret: credentials.AccessKeySecret,
 	}
+	if len(credentials.SecurityToken) > 0 {
+		variablesEnvironment[TerraformVarSecurityToken] = credentials.SecurityToken
+	}
 
 	return tf.
 		SetVariablesEnvironment(variablesEnvironment).
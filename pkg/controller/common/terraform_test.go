@@ -72,5 +72,36 @@ var _ = Describe("Terraform", func() {
 			Expect(err).NotTo(HaveOccurred())
 			Expect(actual).To(BeIdenticalTo(tf))
 		})
+
+		It("should pass the security token of temporary credentials", func() {
+			var (
+				factory     = mockterraformer.NewMockFactory(ctrl)
+				tf          = mockterraformer.NewMockTerraformer(ctrl)
+				config      rest.Config
+				credentials = alicloud.Credentials{
+					AccessKeyID:     "STS.accessKeyID",
+					AccessKeySecret: "accessKeySecret",
+					SecurityToken:   "securityToken",
+				}
+			)
+
+			gomock.InOrder(
+				factory.EXPECT().
+					NewForConfig(gomock.Any(), &config, "purpose", "namespace", "name", imagevector.TerraformerImage()).
+					Return(tf, nil),
+				tf.EXPECT().SetVariablesEnvironment(map[string]string{
+					TerraformVarAccessKeyID:     "STS.accessKeyID",
+					TerraformVarAccessKeySecret: "accessKeySecret",
+					TerraformVarSecurityToken:   "securityToken",
+				}).Return(tf),
+				tf.EXPECT().SetActiveDeadlineSeconds(int64(630)).Return(tf),
+				tf.EXPECT().SetDeadlineCleaning(5*time.Minute).Return(tf),
+				tf.EXPECT().SetDeadlinePod(15*time.Minute).Return(tf),
+			)
+
+			actual, err := NewTerraformer(factory, &config, &credentials, "purpose", "namespace", "name")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(actual).To(BeIdenticalTo(tf))
+		})
 	})
 })
@@ -0,0 +1,242 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controlplane
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gardener/gardener-extension-provider-alicloud/pkg/alicloud"
+	alicloudclient "github.com/gardener/gardener-extension-provider-alicloud/pkg/alicloud/client"
+	"github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/alicloud/helper"
+	extensionscontroller "github.com/gardener/gardener-extensions/pkg/controller"
+	"github.com/gardener/gardener-extensions/pkg/controller/controlplane"
+
+	v1beta1constants "github.com/gardener/gardener/pkg/apis/core/v1beta1/constants"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/inject"
+)
+
+// NewActuator creates a new Actuator which restricts the access to the load balancer of the kube-apiserver with an
+// access control list once the given actuator has reconciled the ControlPlane.
+func NewActuator(a controlplane.Actuator, newClientFactory alicloudclient.ClientFactory, seedCredentialsSecretRef *corev1.SecretReference, logger logr.Logger) controlplane.Actuator {
+	return &actuator{
+		Actuator:                 a,
+		newClientFactory:         newClientFactory,
+		seedCredentialsSecretRef: seedCredentialsSecretRef,
+		logger:                   logger,
+	}
+}
+
+// actuator manages the access control list of the load balancer of the kube-apiserver. The load balancer is created
+// by the cloud-controller-manager of the seed, hence the access control list is managed in the account of the seed.
+type actuator struct {
+	controlplane.Actuator
+	client                   client.Client
+	newClientFactory         alicloudclient.ClientFactory
+	seedCredentialsSecretRef *corev1.SecretReference
+	logger                   logr.Logger
+}
+
+// InjectFunc enables dependency injection into the generic actuator.
+func (a *actuator) InjectFunc(f inject.Func) error {
+	return f(a.Actuator)
+}
+
+// InjectClient injects the given client into the actuator.
+func (a *actuator) InjectClient(client client.Client) error {
+	a.client = client
+	return nil
+}
+
+// Reconcile reconciles the ControlPlane and the access control list of the load balancer of the kube-apiserver.
+func (a *actuator) Reconcile(ctx context.Context, cp *extensionsv1alpha1.ControlPlane, cluster *extensionscontroller.Cluster) (bool, error) {
+	requeue, err := a.Actuator.Reconcile(ctx, cp, cluster)
+	if err != nil || isExposure(cp) {
+		return requeue, err
+	}
+
+	cpConfig, err := helper.ControlPlaneConfigFromCluster(cluster)
+	if err != nil {
+		return false, err
+	}
+	var allowedCIDRs []string
+	if lb := cpConfig.APIServerLoadBalancer; lb != nil {
+		allowedCIDRs = lb.AllowedCIDRs
+	}
+
+	if err := a.reconcileAPIServerAccessControlList(ctx, cp, cluster, allowedCIDRs); err != nil {
+		return false, errors.Wrapf(err, "failed to reconcile the access control list of the kube-apiserver load balancer")
+	}
+	return requeue, nil
+}
+
+// Delete deletes the access control list of the load balancer of the kube-apiserver and the ControlPlane.
+func (a *actuator) Delete(ctx context.Context, cp *extensionsv1alpha1.ControlPlane, cluster *extensionscontroller.Cluster) error {
+	if !isExposure(cp) {
+		if err := a.deleteAPIServerAccessControlList(ctx, cp, cluster); err != nil {
+			return errors.Wrapf(err, "failed to delete the access control list of the kube-apiserver load balancer")
+		}
+	}
+	return a.Actuator.Delete(ctx, cp, cluster)
+}
+
+func isExposure(cp *extensionsv1alpha1.ControlPlane) bool {
+	return cp.Spec.Purpose != nil && *cp.Spec.Purpose == extensionsv1alpha1.Exposure
+}
+
+// apiServerAccessControlListName returns the name of the access control list of the kube-apiserver load balancer of
+// the shoot in the given namespace of the seed.
+func apiServerAccessControlListName(namespace string) string {
+	return fmt.Sprintf("%s-kube-apiserver", namespace)
+}
+
+// reconcileAPIServerAccessControlList ensures that an access control list with exactly the given CIDRs restricts the
+// access to the kube-apiserver load balancer. If no CIDRs are given, a previously created access control list is
+// detached and deleted.
+func (a *actuator) reconcileAPIServerAccessControlList(ctx context.Context, cp *extensionsv1alpha1.ControlPlane, cluster *extensionscontroller.Cluster, allowedCIDRs []string) error {
+	svc := &corev1.Service{}
+	if err := a.client.Get(ctx, client.ObjectKey{Namespace: cp.Namespace, Name: v1beta1constants.DeploymentNameKubeAPIServer}, svc); err != nil {
+		return err
+	}
+
+	if len(allowedCIDRs) == 0 {
+		if _, ok := svc.Annotations[alicloud.AnnotationLoadBalancerACLID]; !ok {
+			return nil
+		}
+		return a.deleteAPIServerAccessControlList(ctx, cp, cluster)
+	}
+
+	if a.seedCredentialsSecretRef == nil {
+		return fmt.Errorf("the access to the kube-apiserver cannot be restricted as no credentials of the seed are configured, please contact your Gardener administrator")
+	}
+	region, slbClient, err := a.newSeedSLBClient(ctx, cluster)
+	if err != nil {
+		return err
+	}
+
+	name := apiServerAccessControlListName(cp.Namespace)
+	aclID, err := slbClient.GetAccessControlListID(ctx, region, name)
+	if err != nil {
+		return err
+	}
+	if aclID == "" {
+		if aclID, err = slbClient.CreateAccessControlList(ctx, region, name); err != nil {
+			return err
+		}
+		a.logger.Info("Created access control list of the kube-apiserver load balancer", "namespace", cp.Namespace, "acl", aclID)
+	}
+
+	entries, err := slbClient.GetAccessControlListEntries(ctx, region, aclID)
+	if err != nil {
+		return err
+	}
+	desired, current := sets.NewString(allowedCIDRs...), sets.NewString(entries...)
+	// New entries are added before the removed ones are deleted, so that clients which are allowed before and after
+	// the change are never rejected.
+	if toAdd := desired.Difference(current); toAdd.Len() > 0 {
+		if err := slbClient.AddAccessControlListEntries(ctx, region, aclID, toAdd.List()); err != nil {
+			return err
+		}
+	}
+	if toRemove := current.Difference(desired); toRemove.Len() > 0 {
+		if err := slbClient.RemoveAccessControlListEntries(ctx, region, aclID, toRemove.List()); err != nil {
+			return err
+		}
+	}
+
+	return a.enableAccessControl(ctx, svc, aclID)
+}
+
+// deleteAPIServerAccessControlList deletes the access control list of the kube-apiserver load balancer if it exists.
+// It can only be deleted once the cloud-controller-manager of the seed has detached it from the listener.
+func (a *actuator) deleteAPIServerAccessControlList(ctx context.Context, cp *extensionsv1alpha1.ControlPlane, cluster *extensionscontroller.Cluster) error {
+	svc := &corev1.Service{}
+	if err := a.client.Get(ctx, client.ObjectKey{Namespace: cp.Namespace, Name: v1beta1constants.DeploymentNameKubeAPIServer}, svc); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+	} else if err := a.disableAccessControl(ctx, svc); err != nil {
+		return err
+	}
+
+	if a.seedCredentialsSecretRef == nil {
+		return nil
+	}
+
+	region, slbClient, err := a.newSeedSLBClient(ctx, cluster)
+	if err != nil {
+		return err
+	}
+
+	aclID, err := slbClient.GetAccessControlListID(ctx, region, apiServerAccessControlListName(cp.Namespace))
+	if err != nil || aclID == "" {
+		return err
+	}
+	if err := slbClient.DeleteAccessControlList(ctx, region, aclID); err != nil {
+		return err
+	}
+	a.logger.Info("Deleted access control list of the kube-apiserver load balancer", "namespace", cp.Namespace, "acl", aclID)
+	return nil
+}
+
+// newSeedSLBClient creates an SLB client for the account and the region of the seed.
+func (a *actuator) newSeedSLBClient(ctx context.Context, cluster *extensionscontroller.Cluster) (string, alicloudclient.SLB, error) {
+	credentials, err := alicloud.ReadCredentialsFromSecretRef(ctx, a.client, a.seedCredentialsSecretRef)
+	if err != nil {
+		return "", nil, err
+	}
+	region := cluster.Seed.Spec.Provider.Region
+	slbClient, err := a.newClientFactory.NewSLBClient(ctx, region, credentials)
+	if err != nil {
+		return "", nil, err
+	}
+	return region, slbClient, nil
+}
+
+// enableAccessControl annotates the kube-apiserver service so that the cloud-controller-manager of the seed attaches the
+// access control list with the given ID as white list to the listener.
+func (a *actuator) enableAccessControl(ctx context.Context, svc *corev1.Service, aclID string) error {
+	if svc.Annotations[alicloud.AnnotationLoadBalancerACLStatus] == "on" && svc.Annotations[alicloud.AnnotationLoadBalancerACLID] == aclID && svc.Annotations[alicloud.AnnotationLoadBalancerACLType] == "white" {
+		return nil
+	}
+
+	if svc.Annotations == nil {
+		svc.Annotations = map[string]string{}
+	}
+	svc.Annotations[alicloud.AnnotationLoadBalancerACLStatus] = "on"
+	svc.Annotations[alicloud.AnnotationLoadBalancerACLID] = aclID
+	svc.Annotations[alicloud.AnnotationLoadBalancerACLType] = "white"
+	return a.client.Update(ctx, svc)
+}
+
+// disableAccessControl annotates the kube-apiserver service so that the cloud-controller-manager of the seed detaches
+// the access control list from the listener.
+func (a *actuator) disableAccessControl(ctx context.Context, svc *corev1.Service) error {
+	if _, ok := svc.Annotations[alicloud.AnnotationLoadBalancerACLID]; !ok {
+		return nil
+	}
+
+	svc.Annotations[alicloud.AnnotationLoadBalancerACLStatus] = "off"
+	delete(svc.Annotations, alicloud.AnnotationLoadBalancerACLID)
+	delete(svc.Annotations, alicloud.AnnotationLoadBalancerACLType)
+	return a.client.Update(ctx, svc)
+}
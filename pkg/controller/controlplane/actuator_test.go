@@ -0,0 +1,164 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controlplane
+
+import (
+	"context"
+
+	"github.com/gardener/gardener-extension-provider-alicloud/pkg/alicloud"
+	mockalicloudclient "github.com/gardener/gardener-extension-provider-alicloud/pkg/mock/provider-alicloud/alicloud/client"
+	extensionscontroller "github.com/gardener/gardener-extensions/pkg/controller"
+	mockclient "github.com/gardener/gardener-extensions/pkg/mock/controller-runtime/client"
+
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var _ = Describe("Actuator", func() {
+	const (
+		namespace  = "shoot--foo--bar"
+		seedRegion = "cn-shanghai"
+		aclName    = "shoot--foo--bar-kube-apiserver"
+	)
+
+	var (
+		ctrl      *gomock.Controller
+		c         *mockclient.MockClient
+		slbClient *mockalicloudclient.MockSLB
+
+		ctx         = context.TODO()
+		secretRef   = &corev1.SecretReference{Namespace: "garden", Name: "seed-credentials"}
+		credentials = &alicloud.Credentials{AccessKeyID: "id", AccessKeySecret: "secret"}
+		cp          = &extensionsv1alpha1.ControlPlane{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "control-plane"}}
+		cluster     = &extensionscontroller.Cluster{
+			Seed: &gardencorev1beta1.Seed{Spec: gardencorev1beta1.SeedSpec{Provider: gardencorev1beta1.SeedProvider{Region: seedRegion}}},
+		}
+
+		a *actuator
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		c = mockclient.NewMockClient(ctrl)
+		slbClient = mockalicloudclient.NewMockSLB(ctrl)
+
+		newClientFactory := mockalicloudclient.NewMockClientFactory(ctrl)
+		newClientFactory.EXPECT().NewSLBClient(ctx, seedRegion, credentials).Return(slbClient, nil).AnyTimes()
+
+		a = NewActuator(nil, newClientFactory, secretRef, log.Log).(*actuator)
+		Expect(a.InjectClient(c)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	expectService := func(annotations map[string]string) {
+		c.EXPECT().Get(ctx, client.ObjectKey{Namespace: namespace, Name: "kube-apiserver"}, gomock.AssignableToTypeOf(&corev1.Service{})).
+			DoAndReturn(func(_ context.Context, _ client.ObjectKey, obj runtime.Object) error {
+				obj.(*corev1.Service).Annotations = annotations
+				return nil
+			})
+	}
+	expectSeedCredentials := func() {
+		c.EXPECT().Get(ctx, client.ObjectKey{Namespace: secretRef.Namespace, Name: secretRef.Name}, gomock.AssignableToTypeOf(&corev1.Secret{})).
+			DoAndReturn(func(_ context.Context, _ client.ObjectKey, obj runtime.Object) error {
+				obj.(*corev1.Secret).Data = map[string][]byte{
+					alicloud.AccessKeyID:     []byte(credentials.AccessKeyID),
+					alicloud.AccessKeySecret: []byte(credentials.AccessKeySecret),
+				}
+				return nil
+			})
+	}
+	expectServiceUpdate := func(annotations map[string]string) {
+		c.EXPECT().Update(ctx, gomock.AssignableToTypeOf(&corev1.Service{})).DoAndReturn(func(_ context.Context, obj runtime.Object, _ ...client.UpdateOption) error {
+			Expect(obj.(*corev1.Service).Annotations).To(Equal(annotations))
+			return nil
+		})
+	}
+
+	aclAnnotations := map[string]string{
+		alicloud.AnnotationLoadBalancerACLStatus: "on",
+		alicloud.AnnotationLoadBalancerACLID:     "acl-1",
+		alicloud.AnnotationLoadBalancerACLType:   "white",
+	}
+
+	Describe("#reconcileAPIServerAccessControlList", func() {
+		It("should create the access control list and attach it to the load balancer", func() {
+			expectService(nil)
+			expectSeedCredentials()
+			slbClient.EXPECT().GetAccessControlListID(ctx, seedRegion, aclName).Return("", nil)
+			slbClient.EXPECT().CreateAccessControlList(ctx, seedRegion, aclName).Return("acl-1", nil)
+			slbClient.EXPECT().GetAccessControlListEntries(ctx, seedRegion, "acl-1").Return(nil, nil)
+			slbClient.EXPECT().AddAccessControlListEntries(ctx, seedRegion, "acl-1", []string{"10.0.0.0/8", "192.168.0.0/16"})
+			expectServiceUpdate(aclAnnotations)
+
+			Expect(a.reconcileAPIServerAccessControlList(ctx, cp, cluster, []string{"192.168.0.0/16", "10.0.0.0/8"})).To(Succeed())
+		})
+
+		It("should reconcile the entries of the existing access control list", func() {
+			expectService(aclAnnotations)
+			expectSeedCredentials()
+			slbClient.EXPECT().GetAccessControlListID(ctx, seedRegion, aclName).Return("acl-1", nil)
+			slbClient.EXPECT().GetAccessControlListEntries(ctx, seedRegion, "acl-1").Return([]string{"10.0.0.0/8", "172.16.0.0/12"}, nil)
+			gomock.InOrder(
+				slbClient.EXPECT().AddAccessControlListEntries(ctx, seedRegion, "acl-1", []string{"192.168.0.0/16"}),
+				slbClient.EXPECT().RemoveAccessControlListEntries(ctx, seedRegion, "acl-1", []string{"172.16.0.0/12"}),
+			)
+
+			Expect(a.reconcileAPIServerAccessControlList(ctx, cp, cluster, []string{"10.0.0.0/8", "192.168.0.0/16"})).To(Succeed())
+		})
+
+		It("should detach and delete the access control list if no CIDRs are allowed anymore", func() {
+			expectService(map[string]string{
+				alicloud.AnnotationLoadBalancerACLStatus: "on",
+				alicloud.AnnotationLoadBalancerACLID:     "acl-1",
+				alicloud.AnnotationLoadBalancerACLType:   "white",
+			})
+			expectService(map[string]string{
+				alicloud.AnnotationLoadBalancerACLStatus: "on",
+				alicloud.AnnotationLoadBalancerACLID:     "acl-1",
+				alicloud.AnnotationLoadBalancerACLType:   "white",
+			})
+			expectServiceUpdate(map[string]string{alicloud.AnnotationLoadBalancerACLStatus: "off"})
+			expectSeedCredentials()
+			slbClient.EXPECT().GetAccessControlListID(ctx, seedRegion, aclName).Return("acl-1", nil)
+			slbClient.EXPECT().DeleteAccessControlList(ctx, seedRegion, "acl-1")
+
+			Expect(a.reconcileAPIServerAccessControlList(ctx, cp, cluster, nil)).To(Succeed())
+		})
+
+		It("should not call the Alicloud API if the access was never restricted", func() {
+			expectService(nil)
+
+			Expect(a.reconcileAPIServerAccessControlList(ctx, cp, cluster, nil)).To(Succeed())
+		})
+
+		It("should fail if no seed credentials are configured", func() {
+			a.seedCredentialsSecretRef = nil
+			expectService(nil)
+
+			Expect(a.reconcileAPIServerAccessControlList(ctx, cp, cluster, []string{"10.0.0.0/8"})).To(MatchError(ContainSubstring("no credentials of the seed are configured")))
+		})
+	})
+})
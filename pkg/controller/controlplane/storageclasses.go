@@ -0,0 +1,189 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controlplane
+
+import (
+	"context"
+	"reflect"
+
+	apisalicloud "github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/alicloud"
+
+	kutil "github.com/gardener/gardener/pkg/utils/kubernetes"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	apiextensionsscheme "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/scheme"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	kubernetesscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// defaultStorageClassName is the name of the default storage class managed by the extension.
+	defaultStorageClassName = "default"
+	// defaultDiskCategory is the disk category of the volumes of storage classes which do not specify one.
+	defaultDiskCategory = "cloud_ssd"
+	// isDefaultStorageClassAnnotation marks the default storage class of a cluster.
+	isDefaultStorageClassAnnotation = "storageclass.kubernetes.io/is-default-class"
+	// volumeSnapshotClassesCRDName is the name of the CRD of VolumeSnapshotClasses, which the csi-snapshotter
+	// registers in the shoot once it has started.
+	volumeSnapshotClassesCRDName = "volumesnapshotclasses.snapshot.storage.k8s.io"
+)
+
+// shootScheme is the scheme of the client for the shoot, which reads storage classes and CRDs.
+var shootScheme = func() *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	utilruntime.Must(kubernetesscheme.AddToScheme(scheme))
+	utilruntime.Must(apiextensionsscheme.AddToScheme(scheme))
+	return scheme
+}()
+
+// getStorageClasses returns the storage classes managed by the extension for the given control plane configuration,
+// i.e. the `default` storage class and the additional storage classes of the configuration. The `default` storage
+// class is the default storage class of the cluster unless another one is marked as default.
+func getStorageClasses(cpConfig *apisalicloud.ControlPlaneConfig) []*storagev1.StorageClass {
+	volumeBindingMode := storagev1.VolumeBindingWaitForFirstConsumer
+	if cpConfig.CSI != nil && cpConfig.CSI.ImmediateVolumeBinding {
+		volumeBindingMode = storagev1.VolumeBindingImmediate
+	}
+
+	isDefault := true
+	for _, storageClass := range cpConfig.StorageClasses {
+		if storageClass.Default {
+			isDefault = false
+		}
+	}
+
+	storageClasses := []*storagev1.StorageClass{
+		newStorageClass(defaultStorageClassName, isDefault, volumeBindingMode, corev1.PersistentVolumeReclaimDelete, defaultDiskCategory, nil),
+	}
+	for _, storageClass := range cpConfig.StorageClasses {
+		diskCategory := defaultDiskCategory
+		if storageClass.Type != nil {
+			diskCategory = *storageClass.Type
+		}
+		reclaimPolicy := corev1.PersistentVolumeReclaimDelete
+		if storageClass.ReclaimPolicy != nil {
+			reclaimPolicy = *storageClass.ReclaimPolicy
+		}
+		storageClasses = append(storageClasses, newStorageClass(storageClass.Name, storageClass.Default, volumeBindingMode, reclaimPolicy, diskCategory, storageClass.PerformanceLevel))
+	}
+	return storageClasses
+}
+
+func newStorageClass(name string, isDefault bool, volumeBindingMode storagev1.VolumeBindingMode, reclaimPolicy corev1.PersistentVolumeReclaimPolicy, diskCategory string, performanceLevel *string) *storagev1.StorageClass {
+	storageClass := &storagev1.StorageClass{
+		ObjectMeta:        metav1.ObjectMeta{Name: name},
+		VolumeBindingMode: &volumeBindingMode,
+		ReclaimPolicy:     &reclaimPolicy,
+		Parameters: map[string]string{
+			"csi.storage.k8s.io/fstype": "ext4",
+			"type":                      diskCategory,
+			"readOnly":                  "false",
+			"encrypted":                 "true",
+		},
+	}
+	if isDefault {
+		storageClass.Annotations = map[string]string{isDefaultStorageClassAnnotation: "true"}
+	}
+	if performanceLevel != nil {
+		storageClass.Parameters["performanceLevel"] = *performanceLevel
+	}
+	return storageClass
+}
+
+// getStorageClassesChartValues returns the values for the storage classes chart for the given storage classes. The
+// default VolumeSnapshotClass is only rendered if its CRD exists in the shoot, as the managed resource of the chart
+// cannot be applied otherwise.
+func getStorageClassesChartValues(storageClasses []*storagev1.StorageClass, volumeSnapshotClassesCRDExists bool) map[string]interface{} {
+	values := make([]interface{}, 0, len(storageClasses))
+	for _, storageClass := range storageClasses {
+		parameters := map[string]interface{}{}
+		for key, value := range storageClass.Parameters {
+			parameters[key] = value
+		}
+		values = append(values, map[string]interface{}{
+			"name":              storageClass.Name,
+			"default":           storageClass.Annotations[isDefaultStorageClassAnnotation] == "true",
+			"volumeBindingMode": string(*storageClass.VolumeBindingMode),
+			"reclaimPolicy":     string(*storageClass.ReclaimPolicy),
+			"parameters":        parameters,
+		})
+	}
+	return map[string]interface{}{
+		"storageClasses": values,
+		"volumeSnapshotClass": map[string]interface{}{
+			"enabled": volumeSnapshotClassesCRDExists,
+		},
+	}
+}
+
+// hasVolumeSnapshotClassesCRD returns whether the CRD of VolumeSnapshotClasses exists in the shoot.
+func hasVolumeSnapshotClassesCRD(ctx context.Context, c client.Client) (bool, error) {
+	if err := c.Get(ctx, kutil.Key(volumeSnapshotClassesCRDName), &apiextensionsv1beta1.CustomResourceDefinition{}); err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// deleteChangedStorageClasses deletes the storage classes in the shoot whose immutable fields differ from the given
+// ones, so that they are recreated with the desired settings when the storage classes chart is applied. Existing
+// persistent volumes are not affected by the deletion of their storage class.
+func deleteChangedStorageClasses(ctx context.Context, c client.Client, storageClasses []*storagev1.StorageClass) error {
+	for _, storageClass := range storageClasses {
+		existing := &storagev1.StorageClass{}
+		if err := c.Get(ctx, kutil.Key(storageClass.Name), existing); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+
+		if !storageClassChanged(existing, storageClass) {
+			continue
+		}
+		if err := c.Delete(ctx, existing); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// storageClassChanged checks whether the immutable fields of the existing storage class differ from the desired ones.
+func storageClassChanged(existing, desired *storagev1.StorageClass) bool {
+	return volumeBindingMode(existing) != volumeBindingMode(desired) ||
+		reclaimPolicy(existing) != reclaimPolicy(desired) ||
+		!reflect.DeepEqual(existing.Parameters, desired.Parameters)
+}
+
+func reclaimPolicy(storageClass *storagev1.StorageClass) corev1.PersistentVolumeReclaimPolicy {
+	if storageClass.ReclaimPolicy == nil {
+		return corev1.PersistentVolumeReclaimDelete
+	}
+	return *storageClass.ReclaimPolicy
+}
+
+func volumeBindingMode(storageClass *storagev1.StorageClass) storagev1.VolumeBindingMode {
+	if storageClass.VolumeBindingMode == nil {
+		return storagev1.VolumeBindingImmediate
+	}
+	return *storageClass.VolumeBindingMode
+}
@@ -0,0 +1,262 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controlplane
+
+import (
+	"context"
+	"fmt"
+
+	apisalicloud "github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/alicloud"
+	"github.com/gardener/gardener-extension-provider-alicloud/pkg/imagevector"
+	mockclient "github.com/gardener/gardener-extensions/pkg/mock/controller-runtime/client"
+
+	"github.com/gardener/gardener/pkg/chartrenderer"
+	kutil "github.com/gardener/gardener/pkg/utils/kubernetes"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/engine"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("StorageClasses", func() {
+	var (
+		ctrl *gomock.Controller
+		c    *mockclient.MockClient
+
+		ctx = context.TODO()
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		c = mockclient.NewMockClient(ctrl)
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Describe("#getStorageClassesChartValues", func() {
+		var defaultParameters = map[string]interface{}{
+			"csi.storage.k8s.io/fstype": "ext4",
+			"type":                      "cloud_ssd",
+			"readOnly":                  "false",
+			"encrypted":                 "true",
+		}
+
+		It("should bind volumes when they are consumed by default", func() {
+			values := getStorageClassesChartValues(getStorageClasses(&apisalicloud.ControlPlaneConfig{}), false)
+
+			Expect(values).To(Equal(map[string]interface{}{
+				"storageClasses": []interface{}{
+					map[string]interface{}{
+						"name":              "default",
+						"default":           true,
+						"volumeBindingMode": "WaitForFirstConsumer",
+						"reclaimPolicy":     "Delete",
+						"parameters":        defaultParameters,
+					},
+				},
+				"volumeSnapshotClass": map[string]interface{}{"enabled": false},
+			}))
+		})
+
+		It("should bind volumes immediately if configured", func() {
+			values := getStorageClassesChartValues(getStorageClasses(&apisalicloud.ControlPlaneConfig{
+				CSI: &apisalicloud.CSIConfig{ImmediateVolumeBinding: true},
+			}), false)
+
+			Expect(values).To(Equal(map[string]interface{}{
+				"storageClasses": []interface{}{
+					map[string]interface{}{
+						"name":              "default",
+						"default":           true,
+						"volumeBindingMode": "Immediate",
+						"reclaimPolicy":     "Delete",
+						"parameters":        defaultParameters,
+					},
+				},
+				"volumeSnapshotClass": map[string]interface{}{"enabled": false},
+			}))
+		})
+
+		It("should render the additional storage classes with their disk categories", func() {
+			retain := corev1.PersistentVolumeReclaimRetain
+			values := getStorageClassesChartValues(getStorageClasses(&apisalicloud.ControlPlaneConfig{
+				StorageClasses: []apisalicloud.StorageClass{
+					{Name: "efficiency", Type: pointer.StringPtr("cloud_efficiency")},
+					{Name: "essd-pl1", Type: pointer.StringPtr("cloud_essd"), PerformanceLevel: pointer.StringPtr("PL1"), Default: true, ReclaimPolicy: &retain},
+				},
+			}), false)
+
+			Expect(values).To(Equal(map[string]interface{}{
+				"storageClasses": []interface{}{
+					map[string]interface{}{
+						"name":              "default",
+						"default":           false,
+						"volumeBindingMode": "WaitForFirstConsumer",
+						"reclaimPolicy":     "Delete",
+						"parameters":        defaultParameters,
+					},
+					map[string]interface{}{
+						"name":              "efficiency",
+						"default":           false,
+						"volumeBindingMode": "WaitForFirstConsumer",
+						"reclaimPolicy":     "Delete",
+						"parameters": map[string]interface{}{
+							"csi.storage.k8s.io/fstype": "ext4",
+							"type":                      "cloud_efficiency",
+							"readOnly":                  "false",
+							"encrypted":                 "true",
+						},
+					},
+					map[string]interface{}{
+						"name":              "essd-pl1",
+						"default":           true,
+						"volumeBindingMode": "WaitForFirstConsumer",
+						"reclaimPolicy":     "Retain",
+						"parameters": map[string]interface{}{
+							"csi.storage.k8s.io/fstype": "ext4",
+							"type":                      "cloud_essd",
+							"performanceLevel":          "PL1",
+							"readOnly":                  "false",
+							"encrypted":                 "true",
+						},
+					},
+				},
+				"volumeSnapshotClass": map[string]interface{}{"enabled": false},
+			}))
+		})
+	})
+
+	Describe("#storageClassChart", func() {
+		var chartRenderer chartrenderer.Interface
+
+		BeforeEach(func() {
+			chartRenderer = chartrenderer.New(engine.New(), &chartutil.Capabilities{KubeVersion: &version.Info{GitVersion: "v1.16.0"}})
+		})
+
+		It("should not render the VolumeSnapshotClass if its CRD does not exist", func() {
+			values := getStorageClassesChartValues(getStorageClasses(&apisalicloud.ControlPlaneConfig{}), false)
+
+			_, manifest, err := chartWithRepositoryPath(storageClassChart).Render(chartRenderer, metav1.NamespaceSystem, imagevector.ImageVector(), "1.16.0", "1.16.0", values)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(manifest)).To(ContainSubstring("kind: StorageClass"))
+			Expect(string(manifest)).NotTo(ContainSubstring("kind: VolumeSnapshotClass"))
+		})
+
+		It("should render the VolumeSnapshotClass if its CRD exists", func() {
+			values := getStorageClassesChartValues(getStorageClasses(&apisalicloud.ControlPlaneConfig{}), true)
+
+			_, manifest, err := chartWithRepositoryPath(storageClassChart).Render(chartRenderer, metav1.NamespaceSystem, imagevector.ImageVector(), "1.16.0", "1.16.0", values)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(manifest)).To(ContainSubstring("kind: StorageClass"))
+			Expect(string(manifest)).To(ContainSubstring("kind: VolumeSnapshotClass"))
+		})
+	})
+
+	Describe("#hasVolumeSnapshotClassesCRD", func() {
+		It("should return true if the CRD exists", func() {
+			c.EXPECT().Get(ctx, kutil.Key(volumeSnapshotClassesCRDName), &apiextensionsv1beta1.CustomResourceDefinition{})
+
+			Expect(hasVolumeSnapshotClassesCRD(ctx, c)).To(BeTrue())
+		})
+
+		It("should return false if the CRD does not exist", func() {
+			c.EXPECT().Get(ctx, kutil.Key(volumeSnapshotClassesCRDName), &apiextensionsv1beta1.CustomResourceDefinition{}).Return(apierrors.NewNotFound(schema.GroupResource{Resource: "customresourcedefinitions"}, volumeSnapshotClassesCRDName))
+
+			Expect(hasVolumeSnapshotClassesCRD(ctx, c)).To(BeFalse())
+		})
+
+		It("should fail if the CRD cannot be read", func() {
+			c.EXPECT().Get(ctx, kutil.Key(volumeSnapshotClassesCRDName), &apiextensionsv1beta1.CustomResourceDefinition{}).Return(fmt.Errorf("fake"))
+
+			_, err := hasVolumeSnapshotClassesCRD(ctx, c)
+			Expect(err).To(MatchError("fake"))
+		})
+	})
+
+	Describe("#deleteChangedStorageClasses", func() {
+		var (
+			storageClasses = getStorageClasses(&apisalicloud.ControlPlaneConfig{})
+			existing       *storagev1.StorageClass
+		)
+
+		BeforeEach(func() {
+			existing = &storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: defaultStorageClassName}}
+		})
+
+		expectGet := func() {
+			c.EXPECT().Get(ctx, kutil.Key(defaultStorageClassName), &storagev1.StorageClass{}).DoAndReturn(func(_ context.Context, _ client.ObjectKey, obj runtime.Object) error {
+				*obj.(*storagev1.StorageClass) = *existing
+				return nil
+			})
+		}
+
+		It("should delete a storage class with a different volume binding mode", func() {
+			expectGet()
+			c.EXPECT().Delete(ctx, existing)
+
+			Expect(deleteChangedStorageClasses(ctx, c, storageClasses)).To(Succeed())
+		})
+
+		It("should delete a storage class with a different reclaim policy", func() {
+			mode := storagev1.VolumeBindingWaitForFirstConsumer
+			retain := corev1.PersistentVolumeReclaimRetain
+			existing.VolumeBindingMode = &mode
+			existing.ReclaimPolicy = &retain
+			existing.Parameters = storageClasses[0].Parameters
+			expectGet()
+			c.EXPECT().Delete(ctx, existing)
+
+			Expect(deleteChangedStorageClasses(ctx, c, storageClasses)).To(Succeed())
+		})
+
+		It("should delete a storage class with different parameters", func() {
+			mode := storagev1.VolumeBindingWaitForFirstConsumer
+			existing.VolumeBindingMode = &mode
+			existing.Parameters = map[string]string{"type": "cloud_efficiency"}
+			expectGet()
+			c.EXPECT().Delete(ctx, existing)
+
+			Expect(deleteChangedStorageClasses(ctx, c, storageClasses)).To(Succeed())
+		})
+
+		It("should not delete an unchanged storage class", func() {
+			mode := storagev1.VolumeBindingWaitForFirstConsumer
+			existing.VolumeBindingMode = &mode
+			existing.Parameters = storageClasses[0].Parameters
+			expectGet()
+
+			Expect(deleteChangedStorageClasses(ctx, c, storageClasses)).To(Succeed())
+		})
+
+		It("should ignore storage classes which do not exist yet", func() {
+			c.EXPECT().Get(ctx, kutil.Key(defaultStorageClassName), &storagev1.StorageClass{}).Return(apierrors.NewNotFound(schema.GroupResource{Resource: "storageclasses"}, defaultStorageClassName))
+
+			Expect(deleteChangedStorageClasses(ctx, c, storageClasses)).To(Succeed())
+		})
+	})
+})
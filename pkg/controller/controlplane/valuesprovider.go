@@ -20,10 +20,12 @@ import (
 	"encoding/json"
 	"github.com/gardener/gardener-extensions/pkg/controller/common"
 	"path/filepath"
+	"strconv"
 
 	"github.com/gardener/gardener-extension-provider-alicloud/pkg/alicloud"
 	apisalicloud "github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/alicloud"
 	"github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/alicloud/helper"
+	"github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/alicloud/validation"
 	extensionscontroller "github.com/gardener/gardener-extensions/pkg/controller"
 	"github.com/gardener/gardener-extensions/pkg/controller/controlplane/genericactuator"
 	"github.com/gardener/gardener-extensions/pkg/util"
@@ -38,7 +40,9 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	policyv1beta1 "k8s.io/api/policy/v1beta1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/apiserver/pkg/authentication/user"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 var controlPlaneSecrets = &secrets.Secrets{
@@ -163,6 +167,7 @@ var controlPlaneShootChart = &chart.Chart{
 			Objects: []*chart.Object{
 				{Type: &rbacv1.ClusterRole{}, Name: "system:controller:cloud-node-controller"},
 				{Type: &rbacv1.ClusterRoleBinding{}, Name: "system:controller:cloud-node-controller"},
+				{Type: &corev1.ConfigMap{}, Name: alicloud.LoadBalancerDefaultsName},
 			},
 		},
 		{
@@ -202,6 +207,17 @@ var controlPlaneShootChart = &chart.Chart{
 				{Type: &rbacv1.RoleBinding{}, Name: "csi-resizer"},
 			},
 		},
+		{
+			Name:   "nvidia-device-plugin",
+			Images: []string{alicloud.NvidiaDevicePluginImageName},
+			Objects: []*chart.Object{
+				{Type: &appsv1.DaemonSet{}, Name: "nvidia-device-plugin"},
+				{Type: &corev1.ServiceAccount{}, Name: "nvidia-device-plugin"},
+				{Type: &rbacv1.ClusterRole{}, Name: "garden.sapcloud.io:psp:kube-system:nvidia-device-plugin"},
+				{Type: &rbacv1.ClusterRoleBinding{}, Name: "garden.sapcloud.io:psp:nvidia-device-plugin"},
+				{Type: &policyv1beta1.PodSecurityPolicy{}, Name: "gardener.kube-system.nvidia-device-plugin"},
+			},
+		},
 	},
 }
 
@@ -281,6 +297,14 @@ func (vp *valuesProvider) GetControlPlaneShootChartValues(
 	cluster *extensionscontroller.Cluster,
 	checksums map[string]string,
 ) (map[string]interface{}, error) {
+	// Decode providerConfig
+	cpConfig := &apisalicloud.ControlPlaneConfig{}
+	if cp.Spec.ProviderConfig != nil {
+		if _, _, err := vp.Decoder().Decode(cp.Spec.ProviderConfig.Raw, nil, cpConfig); err != nil {
+			return nil, errors.Wrapf(err, "could not decode providerConfig of controlplane '%s'", util.ObjectName(cp))
+		}
+	}
+
 	// Get credentials from the referenced secret
 	credentials, err := alicloud.ReadCredentialsFromSecretRef(ctx, vp.Client(), &cp.Spec.SecretRef)
 	if err != nil {
@@ -288,7 +312,44 @@ func (vp *valuesProvider) GetControlPlaneShootChartValues(
 	}
 
 	// Get control plane shoot chart values
-	return getControlPlaneShootChartValues(cluster, credentials)
+	return getControlPlaneShootChartValues(cpConfig, cluster, credentials)
+}
+
+// GetStorageClassesChartValues returns the values for the storage classes chart applied by the generic actuator.
+func (vp *valuesProvider) GetStorageClassesChartValues(
+	ctx context.Context,
+	cp *extensionsv1alpha1.ControlPlane,
+	cluster *extensionscontroller.Cluster,
+) (map[string]interface{}, error) {
+	// Decode providerConfig
+	cpConfig := &apisalicloud.ControlPlaneConfig{}
+	if cp.Spec.ProviderConfig != nil {
+		if _, _, err := vp.Decoder().Decode(cp.Spec.ProviderConfig.Raw, nil, cpConfig); err != nil {
+			return nil, errors.Wrapf(err, "could not decode providerConfig of controlplane '%s'", util.ObjectName(cp))
+		}
+	}
+
+	storageClasses := getStorageClasses(cpConfig)
+
+	// This is best effort as the shoot cluster might not be reachable yet, changed storage classes are deleted and
+	// the default VolumeSnapshotClass is deployed with the next reconciliation.
+	volumeSnapshotClassesCRDExists := false
+	if !extensionscontroller.IsHibernated(cluster) {
+		_, shootClient, err := util.NewClientForShoot(ctx, vp.Client(), cp.Namespace, client.Options{Scheme: shootScheme})
+		if err != nil {
+			vp.logger.Error(err, "Could not create shoot client", "controlplane", util.ObjectName(cp))
+		} else {
+			// Storage classes cannot be updated, hence changed ones are deleted first.
+			if err := deleteChangedStorageClasses(ctx, shootClient, storageClasses); err != nil {
+				vp.logger.Error(err, "Could not delete changed storage classes", "controlplane", util.ObjectName(cp))
+			}
+			if volumeSnapshotClassesCRDExists, err = hasVolumeSnapshotClassesCRD(ctx, shootClient); err != nil {
+				vp.logger.Error(err, "Could not check whether the VolumeSnapshotClass CRD exists", "controlplane", util.ObjectName(cp))
+			}
+		}
+	}
+
+	return getStorageClassesChartValues(storageClasses, volumeSnapshotClassesCRDExists), nil
 }
 
 // cloudConfig wraps the settings for the Alicloud provider.
@@ -381,6 +442,18 @@ func getControlPlaneChartValues(
 
 	if cpConfig.CloudControllerManager != nil {
 		values["alicloud-cloud-controller-manager"].(map[string]interface{})["featureGates"] = cpConfig.CloudControllerManager.FeatureGates
+
+		if image := cpConfig.CloudControllerManager.Image; image != nil {
+			values["alicloud-cloud-controller-manager"].(map[string]interface{})["images"] = map[string]interface{}{
+				alicloud.CloudControllerManagerImageName: *image,
+			}
+		}
+	}
+
+	if cpConfig.CSI != nil {
+		if images := getImageOverrides(cpConfig.CSI.Images, alicloud.CSIAttacherImageName, alicloud.CSIProvisionerImageName, alicloud.CSISnapshotterImageName, alicloud.CSIResizerImageName, alicloud.CSIPluginImageName); len(images) > 0 {
+			values["csi-alicloud"].(map[string]interface{})["images"] = images
+		}
 	}
 
 	return values, nil
@@ -388,10 +461,16 @@ func getControlPlaneChartValues(
 
 // getControlPlaneShootChartValues collects and returns the control plane shoot chart values.
 func getControlPlaneShootChartValues(
+	cpConfig *apisalicloud.ControlPlaneConfig,
 	cluster *extensionscontroller.Cluster,
 	credentials *alicloud.Credentials,
 ) (map[string]interface{}, error) {
+	if err := validateKubeProxyMode(cluster); err != nil {
+		return nil, err
+	}
+
 	values := map[string]interface{}{
+		"alicloud-cloud-controller-manager": map[string]interface{}{},
 		"csi-alicloud": map[string]interface{}{
 			"credential": map[string]interface{}{
 				"accessKeyID":     base64.StdEncoding.EncodeToString([]byte(credentials.AccessKeyID)),
@@ -399,7 +478,96 @@ func getControlPlaneShootChartValues(
 			},
 			"kubernetesVersion": cluster.Shoot.Spec.Kubernetes.Version,
 		},
+		"nvidia-device-plugin": map[string]interface{}{
+			"enabled": hasGPUWorkers(cluster),
+		},
+	}
+
+	if defaults := getLoadBalancerDefaults(cpConfig.LoadBalancerDefaults); len(defaults) > 0 {
+		values["alicloud-cloud-controller-manager"].(map[string]interface{})["loadBalancerDefaults"] = defaults
+	}
+
+	if cpConfig.CSI != nil {
+		if images := getImageOverrides(cpConfig.CSI.Images, alicloud.CSINodeDriverRegistrarImageName, alicloud.CSIPluginImageName); len(images) > 0 {
+			values["csi-alicloud"].(map[string]interface{})["images"] = images
+		}
 	}
 
 	return values, nil
 }
+
+// getImageOverrides returns the image references of the given overrides for the given image names. The result is
+// merged over the images found in the image vector when the chart is rendered, hence it takes precedence over them.
+func getImageOverrides(overrides map[string]string, names ...string) map[string]interface{} {
+	images := map[string]interface{}{}
+	for _, name := range names {
+		if image, ok := overrides[name]; ok {
+			images[name] = image
+		}
+	}
+	return images
+}
+
+// hasGPUWorkers returns true if any worker pool of the shoot of the given cluster uses a GPU instance type. The NVIDIA
+// device plugin is only deployed in this case, and it only runs on the nodes of these worker pools.
+func hasGPUWorkers(cluster *extensionscontroller.Cluster) bool {
+	for _, worker := range cluster.Shoot.Spec.Provider.Workers {
+		if helper.IsGPUInstanceType(worker.Machine.Type) {
+			return true
+		}
+	}
+	return false
+}
+
+// validateKubeProxyMode checks that the machine images of all worker pools support the IPVS proxy mode of kube-proxy
+// if the shoot of the given cluster requests it. The kernel modules themselves are loaded on the nodes by the
+// controlplane webhook.
+func validateKubeProxyMode(cluster *extensionscontroller.Cluster) error {
+	if !helper.UsesIPVSProxyMode(cluster.Shoot) {
+		return nil
+	}
+
+	cloudProfileConfig, err := helper.CloudProfileConfigFromCluster(cluster)
+	if err != nil {
+		return err
+	}
+	workerConfigs, err := helper.WorkerConfigsFromCluster(cluster)
+	if err != nil {
+		return err
+	}
+
+	allErrs := field.ErrorList{}
+	for i, worker := range cluster.Shoot.Spec.Provider.Workers {
+		var imageName, imageVersion string
+		if worker.Machine.Image != nil {
+			imageName, imageVersion = worker.Machine.Image.Name, worker.Machine.Image.Version
+		}
+		imageName, imageVersion = helper.ResolveMachineImage(cloudProfileConfig, imageName, imageVersion, cluster.Shoot.Spec.Region)
+		fldPath := field.NewPath("spec", "provider", "workers").Index(i).Child("machine", "image")
+		allErrs = append(allErrs, validation.ValidateWorkerMachineImageIPVSSupport(workerConfigs[worker.Name], imageName, imageVersion, cloudProfileConfig, fldPath)...)
+	}
+	if len(allErrs) > 0 {
+		return errors.Wrapf(allErrs.ToAggregate(), "shoot '%s' cannot use the IPVS proxy mode", util.ObjectName(cluster.Shoot))
+	}
+	return nil
+}
+
+// getLoadBalancerDefaults returns the data of the load balancer defaults configmap for the given defaults.
+func getLoadBalancerDefaults(defaults *apisalicloud.LoadBalancerDefaults) map[string]interface{} {
+	data := map[string]interface{}{}
+	if defaults == nil {
+		return data
+	}
+
+	if defaults.Spec != nil {
+		data[alicloud.LoadBalancerDefaultSpec] = *defaults.Spec
+	}
+	if defaults.ChargeType != nil {
+		data[alicloud.LoadBalancerDefaultChargeType] = *defaults.ChargeType
+	}
+	if defaults.Bandwidth != nil {
+		data[alicloud.LoadBalancerDefaultBandwidth] = strconv.Itoa(int(*defaults.Bandwidth))
+	}
+
+	return data
+}
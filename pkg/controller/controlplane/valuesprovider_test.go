@@ -17,21 +17,32 @@ package controlplane
 import (
 	"context"
 	"encoding/json"
+	"path/filepath"
+	"strings"
 
 	"github.com/gardener/gardener-extension-provider-alicloud/pkg/alicloud"
 	apisalicloud "github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/alicloud"
+	apisalicloudv1alpha1 "github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/alicloud/v1alpha1"
+	"github.com/gardener/gardener-extension-provider-alicloud/pkg/imagevector"
 	extensionscontroller "github.com/gardener/gardener-extensions/pkg/controller"
 	mockclient "github.com/gardener/gardener-extensions/pkg/mock/controller-runtime/client"
 
 	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
 	v1beta1constants "github.com/gardener/gardener/pkg/apis/core/v1beta1/constants"
 	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"github.com/gardener/gardener/pkg/chartrenderer"
+	"github.com/gardener/gardener/pkg/utils/chart"
+	gardenerimagevector "github.com/gardener/gardener/pkg/utils/imagevector"
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/engine"
+	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/runtime/inject"
@@ -68,6 +79,10 @@ var _ = Describe("ValuesProvider", func() {
 								"CustomResourceValidation": true,
 							},
 						},
+						LoadBalancerDefaults: &apisalicloud.LoadBalancerDefaults{
+							Spec:      pointer.StringPtr("slb.s1.small"),
+							Bandwidth: pointer.Int32Ptr(100),
+						},
 					}),
 				},
 				InfrastructureProviderStatus: &runtime.RawExtension{
@@ -163,6 +178,12 @@ var _ = Describe("ValuesProvider", func() {
 		}
 
 		controlPlaneShootChartValues = map[string]interface{}{
+			"alicloud-cloud-controller-manager": map[string]interface{}{
+				"loadBalancerDefaults": map[string]interface{}{
+					"spec":      "slb.s1.small",
+					"bandwidth": "100",
+				},
+			},
 			"csi-alicloud": map[string]interface{}{
 				"credential": map[string]interface{}{
 					"accessKeyID":     "Zm9v",
@@ -170,6 +191,9 @@ var _ = Describe("ValuesProvider", func() {
 				},
 				"kubernetesVersion": "1.14.0",
 			},
+			"nvidia-device-plugin": map[string]interface{}{
+				"enabled": false,
+			},
 		}
 
 		logger = log.Log.WithName("test")
@@ -225,7 +249,9 @@ var _ = Describe("ValuesProvider", func() {
 
 			// Create valuesProvider
 			vp := NewValuesProvider(logger)
-			err := vp.(inject.Client).InjectClient(client)
+			err := vp.(inject.Scheme).InjectScheme(scheme)
+			Expect(err).NotTo(HaveOccurred())
+			err = vp.(inject.Client).InjectClient(client)
 			Expect(err).NotTo(HaveOccurred())
 
 			// Call GetControlPlaneChartValues method and check the result
@@ -233,9 +259,147 @@ var _ = Describe("ValuesProvider", func() {
 			Expect(err).NotTo(HaveOccurred())
 			Expect(values).To(Equal(controlPlaneShootChartValues))
 		})
+
+		It("should enable the NVIDIA device plugin if a worker pool uses a GPU instance type", func() {
+			// Create mock client
+			client := mockclient.NewMockClient(ctrl)
+			client.EXPECT().Get(context.TODO(), cpSecretKey, &corev1.Secret{}).DoAndReturn(clientGet(cpSecret))
+
+			// Create valuesProvider
+			vp := NewValuesProvider(logger)
+			err := vp.(inject.Scheme).InjectScheme(scheme)
+			Expect(err).NotTo(HaveOccurred())
+			err = vp.(inject.Client).InjectClient(client)
+			Expect(err).NotTo(HaveOccurred())
+
+			gpuCluster := &extensionscontroller.Cluster{
+				Shoot: cluster.Shoot.DeepCopy(),
+			}
+			gpuCluster.Shoot.Spec.Provider.Workers = []gardencorev1beta1.Worker{
+				{Name: "cpu", Machine: gardencorev1beta1.Machine{Type: "ecs.g6.large"}},
+				{Name: "gpu", Machine: gardencorev1beta1.Machine{Type: "ecs.gn6i-c4g1.xlarge"}},
+			}
+
+			values, err := vp.GetControlPlaneShootChartValues(context.TODO(), cp, gpuCluster, checksums)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(values).To(HaveKeyWithValue("nvidia-device-plugin", map[string]interface{}{"enabled": true}))
+		})
+
+		It("should fail if the shoot uses the IPVS proxy mode with a machine image not supporting it", func() {
+			// Create mock client
+			client := mockclient.NewMockClient(ctrl)
+			client.EXPECT().Get(context.TODO(), cpSecretKey, &corev1.Secret{}).DoAndReturn(clientGet(cpSecret))
+
+			// Create valuesProvider
+			vp := NewValuesProvider(logger)
+			err := vp.(inject.Scheme).InjectScheme(scheme)
+			Expect(err).NotTo(HaveOccurred())
+			err = vp.(inject.Client).InjectClient(client)
+			Expect(err).NotTo(HaveOccurred())
+
+			ipvs := gardencorev1beta1.ProxyModeIPVS
+			ipvsCluster := &extensionscontroller.Cluster{
+				CloudProfile: &gardencorev1beta1.CloudProfile{
+					Spec: gardencorev1beta1.CloudProfileSpec{
+						ProviderConfig: &gardencorev1beta1.ProviderConfig{RawExtension: runtime.RawExtension{
+							Raw: encode(&apisalicloudv1alpha1.CloudProfileConfig{
+								TypeMeta: metav1.TypeMeta{
+									APIVersion: apisalicloudv1alpha1.SchemeGroupVersion.String(),
+									Kind:       "CloudProfileConfig",
+								},
+								MachineImages: []apisalicloudv1alpha1.MachineImages{{
+									Name:     "coreos",
+									Versions: []apisalicloudv1alpha1.MachineImageVersion{{Version: "2023.4.0", SupportsIPVS: pointer.BoolPtr(false)}},
+								}},
+							}),
+						}},
+					},
+				},
+				Shoot: &gardencorev1beta1.Shoot{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "myshoot",
+					},
+					Spec: gardencorev1beta1.ShootSpec{
+						Kubernetes: gardencorev1beta1.Kubernetes{
+							Version:   "1.14.0",
+							KubeProxy: &gardencorev1beta1.KubeProxyConfig{Mode: &ipvs},
+						},
+						Provider: gardencorev1beta1.Provider{
+							Workers: []gardencorev1beta1.Worker{{
+								Name:    "pool",
+								Machine: gardencorev1beta1.Machine{Image: &gardencorev1beta1.ShootMachineImage{Name: "coreos", Version: "2023.4.0"}},
+							}},
+						},
+					},
+				},
+			}
+
+			_, err = vp.GetControlPlaneShootChartValues(context.TODO(), cp, ipvsCluster, checksums)
+			Expect(err).To(MatchError(ContainSubstring("does not support the IPVS proxy mode of kube-proxy")))
+		})
+	})
+
+	Describe("#image overrides", func() {
+		var (
+			chartRenderer chartrenderer.Interface
+			cpConfig      *apisalicloud.ControlPlaneConfig
+		)
+
+		BeforeEach(func() {
+			chartRenderer = chartrenderer.New(engine.New(), &chartutil.Capabilities{KubeVersion: &version.Info{GitVersion: "v1.16.0"}})
+			cpConfig = &apisalicloud.ControlPlaneConfig{
+				CloudControllerManager: &apisalicloud.CloudControllerManagerConfig{
+					Image: pointer.StringPtr("mirror.example.com/alicloud-controller-manager:v1.9.3-pinned"),
+				},
+				CSI: &apisalicloud.CSIConfig{
+					Images: map[string]string{
+						alicloud.CSIAttacherImageName:            "mirror.example.com/csi-attacher:v1.2.0",
+						alicloud.CSINodeDriverRegistrarImageName: "mirror.example.com/csi-node-driver-registrar:v1.1.0",
+						alicloud.CSIPluginImageName:              "mirror.example.com/csi-plugin@sha256:" + strings.Repeat("a", 64),
+					},
+				},
+			}
+		})
+
+		It("should render the overridden images into the control plane deployments", func() {
+			values, err := getControlPlaneChartValues(cpConfig, cp, cluster, checksums, false)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, manifest, err := chartWithRepositoryPath(controlPlaneChart).Render(chartRenderer, namespace, imagevector.ImageVector(), "1.16.0", "1.14.0", values)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(manifest)).To(And(
+				ContainSubstring("image: mirror.example.com/alicloud-controller-manager:v1.9.3-pinned"),
+				ContainSubstring("image: mirror.example.com/csi-attacher:v1.2.0"),
+				ContainSubstring("image: mirror.example.com/csi-plugin@sha256:"+strings.Repeat("a", 64)),
+			))
+
+			provisioner, err := imagevector.ImageVector().FindImage(alicloud.CSIProvisionerImageName, gardenerimagevector.RuntimeVersion("1.16.0"), gardenerimagevector.TargetVersion("1.14.0"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(manifest)).To(ContainSubstring("image: " + provisioner.String()))
+		})
+
+		It("should render the overridden images into the control plane shoot daemonset", func() {
+			values, err := getControlPlaneShootChartValues(cpConfig, cluster, &alicloud.Credentials{AccessKeyID: "foo", AccessKeySecret: "bar"})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, manifest, err := chartWithRepositoryPath(controlPlaneShootChart).Render(chartRenderer, metav1.NamespaceSystem, imagevector.ImageVector(), "1.14.0", "1.14.0", values)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(manifest)).To(And(
+				ContainSubstring("image: mirror.example.com/csi-node-driver-registrar:v1.1.0"),
+				ContainSubstring("image: mirror.example.com/csi-plugin@sha256:"+strings.Repeat("a", 64)),
+			))
+		})
 	})
 })
 
+// chartWithRepositoryPath returns a copy of the given chart whose path is relative to the directory of this package
+// instead of the root of the repository.
+func chartWithRepositoryPath(c *chart.Chart) *chart.Chart {
+	cc := *c
+	cc.Path = filepath.Join("..", "..", "..", c.Path)
+	return &cc
+}
+
 func encode(obj runtime.Object) []byte {
 	data, _ := json.Marshal(obj)
 	return data
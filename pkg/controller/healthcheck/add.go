@@ -18,6 +18,8 @@ import (
 	"time"
 
 	"github.com/gardener/gardener-extension-provider-alicloud/pkg/alicloud"
+	alicloudclient "github.com/gardener/gardener-extension-provider-alicloud/pkg/alicloud/client"
+	"github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/config"
 	genericcontrolplaneactuator "github.com/gardener/gardener-extensions/pkg/controller/controlplane/genericactuator"
 	"github.com/gardener/gardener-extensions/pkg/controller/healthcheck"
 	healthcheckconfig "github.com/gardener/gardener-extensions/pkg/controller/healthcheck/config"
@@ -28,24 +30,55 @@ import (
 
 	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
 	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 )
 
 var (
 	defaultSyncPeriod = time.Second * 30
+	// spotInstancesGracePeriod is the duration for which missing or unready nodes of workers with spot instances are
+	// tolerated. It covers the time the machine-controller-manager needs to replace reclaimed spot instances.
+	spotInstancesGracePeriod = 10 * time.Minute
+	// csiDiskPluginMaxUnavailable is the share of nodes on which the CSI disk plugin may be unready, e.g. while new
+	// nodes join the cluster.
+	csiDiskPluginMaxUnavailable = intstr.FromString("10%")
+	// defaultNodeConditionTypes are the node conditions which mark a node as unhealthy by default.
+	defaultNodeConditionTypes = []corev1.NodeConditionType{corev1.NodeNetworkUnavailable, corev1.NodeDiskPressure}
+	// apiServerLoadBalancerCheckInterval is the minimum duration between two calls of the SLB API checking the load
+	// balancer of the kube-apiserver of a shoot.
+	apiServerLoadBalancerCheckInterval = 5 * time.Minute
+	// defaultMaxUnhealthyNodes is the default share of the nodes of a worker which may have one of the node conditions.
+	defaultMaxUnhealthyNodes = intstr.FromString("10%")
 	// DefaultAddOptions are the default DefaultAddArgs for AddToManager.
 	DefaultAddOptions = healthcheck.DefaultAddArgs{
 		HealthCheckConfig: healthcheckconfig.HealthCheckConfig{SyncPeriod: metav1.Duration{Duration: defaultSyncPeriod}},
 	}
+	// NodeConditionsHealthCheck is the configuration of the health check of the node conditions of the workers.
+	NodeConditionsHealthCheck config.NodeConditionsHealthCheck
+	// SeedCredentialsSecretRef is the secret reference which contains the credentials of the Alicloud account of the
+	// seed. The load balancers of the kube-apiservers are only checked if it is set, as they are created in this account.
+	SeedCredentialsSecretRef *corev1.SecretReference
 )
 
 // RegisterHealthChecks registers health checks for each extension resource
 // HealthChecks are grouped by extension (e.g worker), extension.type (e.g alicloud) and  Health Check Type (e.g SystemComponentsHealthy)
 func RegisterHealthChecks(mgr manager.Manager, opts healthcheck.DefaultAddArgs) error {
 	normalPredicates := []predicate.Predicate{extensionspredicate.HasPurpose(extensionsv1alpha1.Normal)}
+	controlPlaneHealthChecks := map[healthcheck.HealthCheck]string{
+		NewSeedDeploymentHealthChecker(alicloud.CsiPluginController):                                 string(gardencorev1beta1.ShootControlPlaneHealthy),
+		NewSeedDeploymentHealthChecker(alicloud.CloudControllerManagerName):                          string(gardencorev1beta1.ShootControlPlaneHealthy),
+		general.CheckManagedResource(genericcontrolplaneactuator.ControlPlaneShootChartResourceName): string(gardencorev1beta1.ShootSystemComponentsHealthy),
+		general.CheckManagedResource(genericcontrolplaneactuator.StorageClassesChartResourceName):    string(gardencorev1beta1.ShootSystemComponentsHealthy),
+		NewShootDaemonSetHealthChecker(alicloud.CSIDiskPluginName, csiDiskPluginMaxUnavailable):      string(gardencorev1beta1.ShootSystemComponentsHealthy),
+	}
+	if SeedCredentialsSecretRef != nil {
+		controlPlaneHealthChecks[NewAPIServerLoadBalancerHealthChecker(alicloudclient.NewClientFactory(), SeedCredentialsSecretRef, apiServerLoadBalancerCheckInterval)] = string(gardencorev1beta1.ShootControlPlaneHealthy)
+	}
+
 	if err := healthcheck.DefaultRegistration(
 		alicloud.Type,
 		extensionsv1alpha1.SchemeGroupVersion.WithKind(extensionsv1alpha1.ControlPlaneResource),
@@ -53,15 +86,32 @@ func RegisterHealthChecks(mgr manager.Manager, opts healthcheck.DefaultAddArgs)
 		mgr,
 		opts,
 		normalPredicates,
+		controlPlaneHealthChecks,
+	); err != nil {
+		return err
+	}
+
+	if err := healthcheck.DefaultRegistration(
+		alicloud.Type,
+		extensionsv1alpha1.SchemeGroupVersion.WithKind(extensionsv1alpha1.InfrastructureResource),
+		func() runtime.Object { return &extensionsv1alpha1.Infrastructure{} },
+		mgr,
+		opts,
+		nil,
 		map[healthcheck.HealthCheck]string{
-			general.NewSeedDeploymentHealthChecker(alicloud.CsiPluginController):                         string(gardencorev1beta1.ShootControlPlaneHealthy),
-			general.NewSeedDeploymentHealthChecker(alicloud.CloudControllerManagerName):                  string(gardencorev1beta1.ShootControlPlaneHealthy),
-			general.CheckManagedResource(genericcontrolplaneactuator.ControlPlaneShootChartResourceName): string(gardencorev1beta1.ShootSystemComponentsHealthy),
-			general.CheckManagedResource(genericcontrolplaneactuator.StorageClassesChartResourceName):    string(gardencorev1beta1.ShootSystemComponentsHealthy),
+			NewNATEgressHealthChecker(alicloudclient.DefaultFactory()): string(gardencorev1beta1.ShootSystemComponentsHealthy),
 		}); err != nil {
 		return err
 	}
 
+	nodeConditionTypes, maxUnhealthyNodes := defaultNodeConditionTypes, defaultMaxUnhealthyNodes
+	if len(NodeConditionsHealthCheck.ConditionTypes) > 0 {
+		nodeConditionTypes = NodeConditionsHealthCheck.ConditionTypes
+	}
+	if NodeConditionsHealthCheck.MaxUnhealthyNodes != nil {
+		maxUnhealthyNodes = *NodeConditionsHealthCheck.MaxUnhealthyNodes
+	}
+
 	return healthcheck.DefaultRegistration(
 		alicloud.Type,
 		extensionsv1alpha1.SchemeGroupVersion.WithKind(extensionsv1alpha1.WorkerResource),
@@ -70,9 +120,10 @@ func RegisterHealthChecks(mgr manager.Manager, opts healthcheck.DefaultAddArgs)
 		opts,
 		nil,
 		map[healthcheck.HealthCheck]string{
-			general.CheckManagedResource(genericworkeractuator.McmShootResourceName):      string(gardencorev1beta1.ShootSystemComponentsHealthy),
-			general.NewSeedDeploymentHealthChecker(alicloud.MachineControllerManagerName): string(gardencorev1beta1.ShootControlPlaneHealthy),
-			worker.NewSufficientNodesChecker():                                            string(gardencorev1beta1.ShootEveryNodeReady),
+			general.CheckManagedResource(genericworkeractuator.McmShootResourceName):                    string(gardencorev1beta1.ShootSystemComponentsHealthy),
+			NewSeedDeploymentHealthChecker(alicloud.MachineControllerManagerName):                       string(gardencorev1beta1.ShootControlPlaneHealthy),
+			NewSpotInstancesHealthChecker(worker.NewSufficientNodesChecker(), spotInstancesGracePeriod): string(gardencorev1beta1.ShootEveryNodeReady),
+			NewNodeConditionsHealthChecker(nodeConditionTypes, maxUnhealthyNodes):                       string(gardencorev1beta1.ShootEveryNodeReady),
 		})
 }
 
@@ -0,0 +1,150 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package healthcheck
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gardener/gardener-extension-provider-alicloud/pkg/alicloud"
+	alicloudclient "github.com/gardener/gardener-extension-provider-alicloud/pkg/alicloud/client"
+	extensionscontroller "github.com/gardener/gardener-extensions/pkg/controller"
+	"github.com/gardener/gardener-extensions/pkg/controller/healthcheck"
+
+	v1beta1constants "github.com/gardener/gardener/pkg/apis/core/v1beta1/constants"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// ReasonAPIServerLoadBalancerMissing is the reason of unhealthy results of the APIServerLoadBalancerHealthChecker.
+const ReasonAPIServerLoadBalancerMissing = "APIServerLoadBalancerMissing"
+
+// APIServerLoadBalancerHealthChecker checks whether the load balancer of the kube-apiserver of a shoot still exists.
+// The load balancer is created by the cloud-controller-manager of the seed in the account of the seed. If it has been
+// deleted manually then the shoot is unreachable although all control plane components are healthy. The check only
+// reads from the SLB API, and at most once per check interval for every shoot, the last result is reported in between.
+type APIServerLoadBalancerHealthChecker struct {
+	logger                   logr.Logger
+	seedClient               client.Client
+	slbClientFactory         alicloudclient.ClientFactory
+	seedCredentialsSecretRef *corev1.SecretReference
+	checkInterval            time.Duration
+	now                      func() time.Time
+
+	// lastResults is shared between all copies of the health check.
+	lastResults *sync.Map
+}
+
+// apiServerLoadBalancerResult is the result of the last check of the load balancer with the given address.
+type apiServerLoadBalancerResult struct {
+	address   string
+	checkedAt time.Time
+	result    *healthcheck.SingleCheckResult
+}
+
+// NewAPIServerLoadBalancerHealthChecker returns a health check for the load balancer of the kube-apiserver which uses
+// SLB clients created by the given factory with the credentials of the seed in the given secret. The SLB API is called
+// at most once per the given check interval for every shoot.
+func NewAPIServerLoadBalancerHealthChecker(slbClientFactory alicloudclient.ClientFactory, seedCredentialsSecretRef *corev1.SecretReference, checkInterval time.Duration) healthcheck.HealthCheck {
+	return &APIServerLoadBalancerHealthChecker{
+		slbClientFactory:         slbClientFactory,
+		seedCredentialsSecretRef: seedCredentialsSecretRef,
+		checkInterval:            checkInterval,
+		now:                      time.Now,
+		lastResults:              &sync.Map{},
+	}
+}
+
+// InjectSeedClient injects the seed client
+func (h *APIServerLoadBalancerHealthChecker) InjectSeedClient(seedClient client.Client) {
+	h.seedClient = seedClient
+}
+
+// InjectShootClient injects the shoot client
+func (h *APIServerLoadBalancerHealthChecker) InjectShootClient(_ client.Client) {}
+
+// SetLoggerSuffix injects the logger
+func (h *APIServerLoadBalancerHealthChecker) SetLoggerSuffix(provider, extension string) {
+	h.logger = log.Log.WithName(fmt.Sprintf("%s-%s-healthcheck-apiserver-load-balancer", provider, extension))
+}
+
+// DeepCopy clones the healthCheck struct by making a copy and returning the pointer to that new copy
+func (h *APIServerLoadBalancerHealthChecker) DeepCopy() healthcheck.HealthCheck {
+	copy := *h
+	return &copy
+}
+
+// Check executes the health check
+func (h *APIServerLoadBalancerHealthChecker) Check(ctx context.Context, request types.NamespacedName) (*healthcheck.SingleCheckResult, error) {
+	svc := &corev1.Service{}
+	if err := h.seedClient.Get(ctx, client.ObjectKey{Namespace: request.Namespace, Name: v1beta1constants.DeploymentNameKubeAPIServer}, svc); err != nil {
+		if apierrors.IsNotFound(err) {
+			return &healthcheck.SingleCheckResult{IsHealthy: true}, nil
+		}
+		err := fmt.Errorf("failed to retrieve kube-apiserver service in namespace %s: %v", request.Namespace, err)
+		h.logger.Error(err, "Health check failed")
+		return nil, err
+	}
+	// There is nothing to check before the cloud-controller-manager of the seed has provisioned the load balancer.
+	if len(svc.Status.LoadBalancer.Ingress) == 0 || svc.Status.LoadBalancer.Ingress[0].IP == "" {
+		return &healthcheck.SingleCheckResult{IsHealthy: true}, nil
+	}
+	address := svc.Status.LoadBalancer.Ingress[0].IP
+
+	key := request.String()
+	if last, ok := h.lastResults.Load(key); ok {
+		if last := last.(apiServerLoadBalancerResult); last.address == address && h.now().Sub(last.checkedAt) < h.checkInterval {
+			return last.result, nil
+		}
+	}
+
+	cluster, err := extensionscontroller.GetCluster(ctx, h.seedClient, request.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	credentials, err := alicloud.ReadCredentialsFromSecretRef(ctx, h.seedClient, h.seedCredentialsSecretRef)
+	if err != nil {
+		return nil, err
+	}
+	region := cluster.Seed.Spec.Provider.Region
+	slbClient, err := h.slbClientFactory.NewSLBClient(ctx, region, credentials)
+	if err != nil {
+		return nil, err
+	}
+
+	loadBalancerID, err := slbClient.GetLoadBalancerIDByAddress(ctx, region, address)
+	if err != nil {
+		err := fmt.Errorf("failed to check the load balancer of the kube-apiserver in namespace %s: %v", request.Namespace, err)
+		h.logger.Error(err, "Health check failed")
+		return nil, err
+	}
+
+	result := &healthcheck.SingleCheckResult{IsHealthy: true}
+	if loadBalancerID == "" {
+		result = &healthcheck.SingleCheckResult{
+			IsHealthy: false,
+			Detail:    fmt.Sprintf("load balancer %s of the kube-apiserver does not exist, the cluster is unreachable until it is recreated", address),
+			Reason:    ReasonAPIServerLoadBalancerMissing,
+		}
+	}
+	h.lastResults.Store(key, apiServerLoadBalancerResult{address: address, checkedAt: h.now(), result: result})
+	return result, nil
+}
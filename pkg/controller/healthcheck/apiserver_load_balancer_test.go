@@ -0,0 +1,169 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package healthcheck
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	mockalicloudclient "github.com/gardener/gardener-extension-provider-alicloud/pkg/mock/provider-alicloud/alicloud/client"
+	"github.com/gardener/gardener-extensions/pkg/controller/healthcheck"
+	mockclient "github.com/gardener/gardener-extensions/pkg/mock/controller-runtime/client"
+
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("APIServerLoadBalancerHealthChecker", func() {
+	const address = "47.95.1.1"
+
+	var (
+		ctrl      *gomock.Controller
+		c         *mockclient.MockClient
+		factory   *mockalicloudclient.MockClientFactory
+		slbClient *mockalicloudclient.MockSLB
+
+		ctx     = context.TODO()
+		request = types.NamespacedName{Namespace: "shoot--foo--bar", Name: "control-plane"}
+
+		now     time.Time
+		checker *APIServerLoadBalancerHealthChecker
+	)
+
+	expectService := func(ip string) {
+		c.EXPECT().
+			Get(ctx, client.ObjectKey{Namespace: request.Namespace, Name: "kube-apiserver"}, gomock.AssignableToTypeOf(&corev1.Service{})).
+			DoAndReturn(func(_ context.Context, _ client.ObjectKey, obj runtime.Object) error {
+				if ip != "" {
+					obj.(*corev1.Service).Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{IP: ip}}
+				}
+				return nil
+			})
+	}
+
+	expectSLBClient := func() {
+		seed, err := json.Marshal(&gardencorev1beta1.Seed{
+			TypeMeta: metav1.TypeMeta{APIVersion: gardencorev1beta1.SchemeGroupVersion.String(), Kind: "Seed"},
+			Spec:     gardencorev1beta1.SeedSpec{Provider: gardencorev1beta1.SeedProvider{Region: "cn-shanghai"}},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		c.EXPECT().
+			Get(ctx, client.ObjectKey{Name: request.Namespace}, gomock.AssignableToTypeOf(&extensionsv1alpha1.Cluster{})).
+			DoAndReturn(func(_ context.Context, _ client.ObjectKey, obj runtime.Object) error {
+				obj.(*extensionsv1alpha1.Cluster).Spec.Seed = runtime.RawExtension{Raw: seed}
+				return nil
+			})
+		c.EXPECT().
+			Get(ctx, client.ObjectKey{Namespace: "garden", Name: "seed-credentials"}, gomock.AssignableToTypeOf(&corev1.Secret{})).
+			DoAndReturn(func(_ context.Context, _ client.ObjectKey, obj runtime.Object) error {
+				obj.(*corev1.Secret).Data = map[string][]byte{"accessKeyID": []byte("id"), "accessKeySecret": []byte("secret")}
+				return nil
+			})
+		factory.EXPECT().NewSLBClient(ctx, "cn-shanghai", gomock.Any()).Return(slbClient, nil)
+	}
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		c = mockclient.NewMockClient(ctrl)
+		factory = mockalicloudclient.NewMockClientFactory(ctrl)
+		slbClient = mockalicloudclient.NewMockSLB(ctrl)
+
+		now = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+		checker = NewAPIServerLoadBalancerHealthChecker(factory, &corev1.SecretReference{Namespace: "garden", Name: "seed-credentials"}, 5*time.Minute).DeepCopy().(*APIServerLoadBalancerHealthChecker)
+		checker.now = func() time.Time { return now }
+		checker.SetLoggerSuffix("alicloud", "controlplane")
+		checker.InjectSeedClient(c)
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	It("should be healthy if the load balancer exists", func() {
+		expectService(address)
+		expectSLBClient()
+		slbClient.EXPECT().GetLoadBalancerIDByAddress(ctx, "cn-shanghai", address).Return("lb-1", nil)
+
+		Expect(checker.Check(ctx, request)).To(Equal(&healthcheck.SingleCheckResult{IsHealthy: true}))
+	})
+
+	It("should be unhealthy if the load balancer does not exist", func() {
+		expectService(address)
+		expectSLBClient()
+		slbClient.EXPECT().GetLoadBalancerIDByAddress(ctx, "cn-shanghai", address).Return("", nil)
+
+		result, err := checker.Check(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.IsHealthy).To(BeFalse())
+		Expect(result.Reason).To(Equal(ReasonAPIServerLoadBalancerMissing))
+		Expect(result.Detail).To(ContainSubstring("load balancer 47.95.1.1 of the kube-apiserver does not exist"))
+	})
+
+	It("should not call the SLB API again within the check interval", func() {
+		expectService(address)
+		expectSLBClient()
+		slbClient.EXPECT().GetLoadBalancerIDByAddress(ctx, "cn-shanghai", address).Return("", nil)
+
+		result, err := checker.Check(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.IsHealthy).To(BeFalse())
+
+		now = now.Add(time.Minute)
+		expectService(address)
+		Expect(checker.Check(ctx, request)).To(Equal(result))
+
+		now = now.Add(5 * time.Minute)
+		expectService(address)
+		expectSLBClient()
+		slbClient.EXPECT().GetLoadBalancerIDByAddress(ctx, "cn-shanghai", address).Return("lb-1", nil)
+		Expect(checker.Check(ctx, request)).To(Equal(&healthcheck.SingleCheckResult{IsHealthy: true}))
+	})
+
+	It("should be healthy if the load balancer has not been provisioned yet", func() {
+		expectService("")
+
+		Expect(checker.Check(ctx, request)).To(Equal(&healthcheck.SingleCheckResult{IsHealthy: true}))
+	})
+
+	It("should be healthy if the kube-apiserver service does not exist", func() {
+		c.EXPECT().
+			Get(ctx, client.ObjectKey{Namespace: request.Namespace, Name: "kube-apiserver"}, gomock.AssignableToTypeOf(&corev1.Service{})).
+			Return(apierrors.NewNotFound(schema.GroupResource{Resource: "services"}, "kube-apiserver"))
+
+		Expect(checker.Check(ctx, request)).To(Equal(&healthcheck.SingleCheckResult{IsHealthy: true}))
+	})
+
+	It("should fail if the SLB API cannot be called", func() {
+		expectService(address)
+		expectSLBClient()
+		slbClient.EXPECT().GetLoadBalancerIDByAddress(ctx, "cn-shanghai", address).Return("", fmt.Errorf("throttled"))
+
+		_, err := checker.Check(ctx, request)
+		Expect(err).To(MatchError(ContainSubstring("throttled")))
+	})
+})
@@ -0,0 +1,112 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package healthcheck
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gardener/gardener-extensions/pkg/controller/healthcheck"
+
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// ReasonDaemonSetUnhealthy is the reason of unhealthy results of the DaemonSetHealthChecker.
+const ReasonDaemonSetUnhealthy = "DaemonSetUnhealthy"
+
+// DaemonSetHealthChecker checks a daemon set in the kube-system namespace of the shoot. It is unhealthy if more pods
+// than tolerated are not ready, e.g. because they crash on some of the nodes.
+type DaemonSetHealthChecker struct {
+	logger         logr.Logger
+	seedClient     client.Client
+	shootClient    client.Client
+	name           string
+	maxUnavailable intstr.IntOrString
+}
+
+// NewShootDaemonSetHealthChecker returns a health check for the daemon set with the given name in the kube-system
+// namespace of the shoot. The given maximum number or percentage of the desired pods may be unready, it can be
+// overwritten per control plane with the AnnotationMaxUnavailable annotation.
+func NewShootDaemonSetHealthChecker(name string, maxUnavailable intstr.IntOrString) healthcheck.HealthCheck {
+	return &DaemonSetHealthChecker{
+		name:           name,
+		maxUnavailable: maxUnavailable,
+	}
+}
+
+// InjectSeedClient injects the seed client
+func (h *DaemonSetHealthChecker) InjectSeedClient(seedClient client.Client) {
+	h.seedClient = seedClient
+}
+
+// InjectShootClient injects the shoot client
+func (h *DaemonSetHealthChecker) InjectShootClient(shootClient client.Client) {
+	h.shootClient = shootClient
+}
+
+// SetLoggerSuffix injects the logger
+func (h *DaemonSetHealthChecker) SetLoggerSuffix(provider, extension string) {
+	h.logger = log.Log.WithName(fmt.Sprintf("%s-%s-healthcheck-daemonset", provider, extension))
+}
+
+// DeepCopy clones the healthCheck struct by making a copy and returning the pointer to that new copy
+func (h *DaemonSetHealthChecker) DeepCopy() healthcheck.HealthCheck {
+	copy := *h
+	return &copy
+}
+
+// Check executes the health check
+func (h *DaemonSetHealthChecker) Check(ctx context.Context, request types.NamespacedName) (*healthcheck.SingleCheckResult, error) {
+	controlPlane := &extensionsv1alpha1.ControlPlane{}
+	if err := h.seedClient.Get(ctx, request, controlPlane); err != nil {
+		return nil, fmt.Errorf("failed to retrieve controlplane %s: %v", request, err)
+	}
+	maxUnavailableValue, err := maxUnavailableFromAnnotations(controlPlane.Annotations, h.maxUnavailable)
+	if err != nil {
+		return nil, err
+	}
+
+	daemonSet := &appsv1.DaemonSet{}
+	if err := h.shootClient.Get(ctx, client.ObjectKey{Namespace: metav1.NamespaceSystem, Name: h.name}, daemonSet); err != nil {
+		err := fmt.Errorf("failed to retrieve daemon set '%s' in namespace '%s': %v", h.name, metav1.NamespaceSystem, err)
+		h.logger.Error(err, "Health check failed")
+		return nil, err
+	}
+
+	desired := int(daemonSet.Status.DesiredNumberScheduled)
+	maxUnavailable, err := intstr.GetValueFromIntOrPercent(&maxUnavailableValue, desired, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if ready := int(daemonSet.Status.NumberReady); desired-ready > maxUnavailable {
+		return &healthcheck.SingleCheckResult{
+			IsHealthy: false,
+			Detail:    fmt.Sprintf("daemon set '%s' in namespace '%s' has only %d of %d pods ready (at most %d may be unready)", h.name, metav1.NamespaceSystem, ready, desired, maxUnavailable),
+			Reason:    ReasonDaemonSetUnhealthy,
+		}, nil
+	}
+
+	return &healthcheck.SingleCheckResult{
+		IsHealthy: true,
+	}, nil
+}
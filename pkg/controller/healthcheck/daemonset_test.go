@@ -0,0 +1,116 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package healthcheck
+
+import (
+	"context"
+
+	"github.com/gardener/gardener-extensions/pkg/controller/healthcheck"
+	mockclient "github.com/gardener/gardener-extensions/pkg/mock/controller-runtime/client"
+
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("DaemonSetHealthChecker", func() {
+	var (
+		ctrl *gomock.Controller
+		c    *mockclient.MockClient
+
+		ctx     = context.TODO()
+		request = types.NamespacedName{Namespace: "shoot--foo--bar", Name: "controlplane"}
+
+		checker healthcheck.HealthCheck
+	)
+
+	expectControlPlane := func(annotations map[string]string) {
+		c.EXPECT().
+			Get(ctx, request, &extensionsv1alpha1.ControlPlane{}).
+			DoAndReturn(func(_ context.Context, _ client.ObjectKey, obj runtime.Object) error {
+				obj.(*extensionsv1alpha1.ControlPlane).Annotations = annotations
+				return nil
+			})
+	}
+
+	expectDaemonSet := func(desired, ready int32) {
+		c.EXPECT().
+			Get(ctx, client.ObjectKey{Namespace: metav1.NamespaceSystem, Name: "csi-disk-plugin-alicloud"}, &appsv1.DaemonSet{}).
+			DoAndReturn(func(_ context.Context, _ client.ObjectKey, obj runtime.Object) error {
+				obj.(*appsv1.DaemonSet).Status = appsv1.DaemonSetStatus{DesiredNumberScheduled: desired, NumberReady: ready}
+				return nil
+			})
+	}
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		c = mockclient.NewMockClient(ctrl)
+
+		checker = NewShootDaemonSetHealthChecker("csi-disk-plugin-alicloud", intstr.FromString("10%")).DeepCopy()
+		checker.SetLoggerSuffix("alicloud", "controlplane")
+		checker.InjectSeedClient(c)
+		checker.InjectShootClient(c)
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	It("should be healthy if all pods are ready", func() {
+		expectControlPlane(nil)
+		expectDaemonSet(3, 3)
+
+		Expect(checker.Check(ctx, request)).To(Equal(&healthcheck.SingleCheckResult{IsHealthy: true}))
+	})
+
+	It("should be healthy if the unready pods are within the threshold", func() {
+		expectControlPlane(nil)
+		expectDaemonSet(20, 18)
+
+		Expect(checker.Check(ctx, request)).To(Equal(&healthcheck.SingleCheckResult{IsHealthy: true}))
+	})
+
+	It("should be unhealthy if more pods than tolerated are unready", func() {
+		expectControlPlane(nil)
+		expectDaemonSet(20, 17)
+
+		Expect(checker.Check(ctx, request)).To(Equal(&healthcheck.SingleCheckResult{
+			IsHealthy: false,
+			Detail:    "daemon set 'csi-disk-plugin-alicloud' in namespace 'kube-system' has only 17 of 20 pods ready (at most 2 may be unready)",
+			Reason:    ReasonDaemonSetUnhealthy,
+		}))
+	})
+
+	It("should use the threshold of the controlplane annotation", func() {
+		expectControlPlane(map[string]string{AnnotationMaxUnavailable: "3"})
+		expectDaemonSet(20, 17)
+
+		Expect(checker.Check(ctx, request)).To(Equal(&healthcheck.SingleCheckResult{IsHealthy: true}))
+	})
+
+	It("should fail for a malformed threshold annotation", func() {
+		expectControlPlane(map[string]string{AnnotationMaxUnavailable: "3 pods"})
+
+		_, err := checker.Check(ctx, request)
+		Expect(err).To(MatchError(ContainSubstring(AnnotationMaxUnavailable)))
+	})
+})
@@ -0,0 +1,103 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package healthcheck
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gardener/gardener-extensions/pkg/controller/healthcheck"
+	"github.com/gardener/gardener-extensions/pkg/controller/healthcheck/general"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Reasons of the Progressing condition of deployments, see
+// https://kubernetes.io/docs/concepts/workloads/controllers/deployment/#deployment-status
+const (
+	deploymentReasonNewReplicaSetAvailable   = "NewReplicaSetAvailable"
+	deploymentReasonProgressDeadlineExceeded = "ProgressDeadlineExceeded"
+)
+
+// RolloutAwareDeploymentHealthChecker wraps the health check of a deployment in the seed. Replicas of a deployment may
+// be unavailable while it is rolled out, e.g. after an image update. An unhealthy result of the wrapped check is
+// therefore only reported if the deployment is not being rolled out or its rollout exceeded the progress deadline.
+type RolloutAwareDeploymentHealthChecker struct {
+	healthcheck.HealthCheck
+
+	seedClient client.Client
+	name       string
+}
+
+// NewSeedDeploymentHealthChecker returns a health check for the deployment with the given name in the seed which
+// tolerates unavailable replicas during rollouts.
+func NewSeedDeploymentHealthChecker(name string) healthcheck.HealthCheck {
+	return &RolloutAwareDeploymentHealthChecker{
+		HealthCheck: general.NewSeedDeploymentHealthChecker(name),
+		name:        name,
+	}
+}
+
+// InjectSeedClient injects the seed client
+func (h *RolloutAwareDeploymentHealthChecker) InjectSeedClient(seedClient client.Client) {
+	h.seedClient = seedClient
+	h.HealthCheck.InjectSeedClient(seedClient)
+}
+
+// DeepCopy clones the healthCheck struct by making a copy and returning the pointer to that new copy
+func (h *RolloutAwareDeploymentHealthChecker) DeepCopy() healthcheck.HealthCheck {
+	copy := *h
+	copy.HealthCheck = h.HealthCheck.DeepCopy()
+	return &copy
+}
+
+// Check executes the wrapped health check and tolerates unhealthy results while the deployment is rolled out.
+func (h *RolloutAwareDeploymentHealthChecker) Check(ctx context.Context, request types.NamespacedName) (*healthcheck.SingleCheckResult, error) {
+	result, err := h.HealthCheck.Check(ctx, request)
+	if err != nil || result.IsHealthy {
+		return result, err
+	}
+
+	deployment := &appsv1.Deployment{}
+	if err := h.seedClient.Get(ctx, client.ObjectKey{Namespace: request.Namespace, Name: h.name}, deployment); err != nil {
+		return nil, fmt.Errorf("failed to retrieve deployment '%s' in namespace '%s': %v", h.name, request.Namespace, err)
+	}
+
+	if rolloutInProgress(deployment) {
+		return &healthcheck.SingleCheckResult{IsHealthy: true}, nil
+	}
+	return result, nil
+}
+
+// rolloutInProgress checks whether the given deployment is being rolled out and has not exceeded its progress deadline.
+func rolloutInProgress(deployment *appsv1.Deployment) bool {
+	if deployment.Status.ObservedGeneration < deployment.Generation {
+		return true
+	}
+
+	for _, condition := range deployment.Status.Conditions {
+		if condition.Type != appsv1.DeploymentProgressing {
+			continue
+		}
+		if condition.Status == corev1.ConditionFalse && condition.Reason == deploymentReasonProgressDeadlineExceeded {
+			return false
+		}
+		return condition.Status == corev1.ConditionTrue && condition.Reason != deploymentReasonNewReplicaSetAvailable
+	}
+	return false
+}
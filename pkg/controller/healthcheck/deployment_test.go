@@ -0,0 +1,105 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package healthcheck
+
+import (
+	"context"
+
+	"github.com/gardener/gardener-extensions/pkg/controller/healthcheck"
+	mockclient "github.com/gardener/gardener-extensions/pkg/mock/controller-runtime/client"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("RolloutAwareDeploymentHealthChecker", func() {
+	var (
+		ctrl *gomock.Controller
+		c    *mockclient.MockClient
+
+		ctx     = context.TODO()
+		request = types.NamespacedName{Namespace: "shoot--foo--bar", Name: "controlplane"}
+
+		unhealthy = &healthcheck.SingleCheckResult{IsHealthy: false, Detail: "deployment cloud-controller-manager is unhealthy", Reason: "DeploymentUnhealthy"}
+
+		inner   *fakeHealthCheck
+		checker healthcheck.HealthCheck
+	)
+
+	expectDeployment := func(generation, observedGeneration int64, conditions ...appsv1.DeploymentCondition) {
+		c.EXPECT().
+			Get(ctx, client.ObjectKey{Namespace: request.Namespace, Name: "cloud-controller-manager"}, &appsv1.Deployment{}).
+			DoAndReturn(func(_ context.Context, _ client.ObjectKey, obj runtime.Object) error {
+				deployment := obj.(*appsv1.Deployment)
+				deployment.Generation = generation
+				deployment.Status.ObservedGeneration = observedGeneration
+				deployment.Status.Conditions = conditions
+				return nil
+			})
+	}
+
+	progressing := func(status corev1.ConditionStatus, reason string) appsv1.DeploymentCondition {
+		return appsv1.DeploymentCondition{Type: appsv1.DeploymentProgressing, Status: status, Reason: reason}
+	}
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		c = mockclient.NewMockClient(ctrl)
+
+		inner = &fakeHealthCheck{result: unhealthy}
+		checker = (&RolloutAwareDeploymentHealthChecker{HealthCheck: inner, name: "cloud-controller-manager"}).DeepCopy()
+		checker.InjectSeedClient(c)
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	It("should return healthy results of the wrapped check", func() {
+		inner.result = &healthcheck.SingleCheckResult{IsHealthy: true}
+
+		Expect(checker.Check(ctx, request)).To(Equal(inner.result))
+	})
+
+	It("should tolerate unavailable replicas while the rollout is progressing", func() {
+		expectDeployment(2, 2, progressing(corev1.ConditionTrue, "ReplicaSetUpdated"))
+
+		Expect(checker.Check(ctx, request)).To(Equal(&healthcheck.SingleCheckResult{IsHealthy: true}))
+	})
+
+	It("should tolerate unavailable replicas if the deployment has not been observed yet", func() {
+		expectDeployment(3, 2, progressing(corev1.ConditionTrue, "NewReplicaSetAvailable"))
+
+		Expect(checker.Check(ctx, request)).To(Equal(&healthcheck.SingleCheckResult{IsHealthy: true}))
+	})
+
+	It("should report unavailable replicas if the rollout exceeded its progress deadline", func() {
+		expectDeployment(2, 2, progressing(corev1.ConditionFalse, "ProgressDeadlineExceeded"))
+
+		Expect(checker.Check(ctx, request)).To(Equal(unhealthy))
+	})
+
+	It("should report unavailable replicas if the rollout is complete", func() {
+		expectDeployment(2, 2, progressing(corev1.ConditionTrue, "NewReplicaSetAvailable"))
+
+		Expect(checker.Check(ctx, request)).To(Equal(unhealthy))
+	})
+})
@@ -0,0 +1,256 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package healthcheck
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gardener/gardener-extension-provider-alicloud/pkg/alicloud"
+	alicloudclient "github.com/gardener/gardener-extension-provider-alicloud/pkg/alicloud/client"
+	apisalicloud "github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/alicloud"
+	"github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/alicloud/helper"
+	"github.com/gardener/gardener-extensions/pkg/controller/healthcheck"
+
+	"github.com/aliyun/alibaba-cloud-sdk-go/sdk/requests"
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/vpc"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// ReasonNATEgressUnavailable is the reason of unhealthy results of the NATEgressHealthChecker.
+	ReasonNATEgressUnavailable = "NATEgressUnavailable"
+
+	statusAvailable = "Available"
+	pageSize        = 50
+)
+
+// NATEgressHealthChecker checks the internet egress of the nodes of an infrastructure. The nodes lose their egress if
+// the NAT gateway or the SNAT entry of their vswitch is missing, which is otherwise only noticed once image pulls fail.
+// The infrastructure is unhealthy if a NAT gateway is not available or if a vswitch of the nodes or pods has no
+// available SNAT entry in the NAT gateway of its zone. The check only reads from the VPC API.
+type NATEgressHealthChecker struct {
+	logger           logr.Logger
+	seedClient       client.Client
+	vpcClientFactory alicloudclient.Factory
+}
+
+// NewNATEgressHealthChecker returns a health check for the NAT gateways and SNAT entries of an infrastructure which
+// uses VPC clients created by the given factory.
+func NewNATEgressHealthChecker(vpcClientFactory alicloudclient.Factory) healthcheck.HealthCheck {
+	return &NATEgressHealthChecker{
+		vpcClientFactory: vpcClientFactory,
+	}
+}
+
+// InjectSeedClient injects the seed client
+func (h *NATEgressHealthChecker) InjectSeedClient(seedClient client.Client) {
+	h.seedClient = seedClient
+}
+
+// InjectShootClient injects the shoot client
+func (h *NATEgressHealthChecker) InjectShootClient(_ client.Client) {}
+
+// SetLoggerSuffix injects the logger
+func (h *NATEgressHealthChecker) SetLoggerSuffix(provider, extension string) {
+	h.logger = log.Log.WithName(fmt.Sprintf("%s-%s-healthcheck-nat-egress", provider, extension))
+}
+
+// DeepCopy clones the healthCheck struct by making a copy and returning the pointer to that new copy
+func (h *NATEgressHealthChecker) DeepCopy() healthcheck.HealthCheck {
+	copy := *h
+	return &copy
+}
+
+// Check executes the health check
+func (h *NATEgressHealthChecker) Check(ctx context.Context, request types.NamespacedName) (*healthcheck.SingleCheckResult, error) {
+	infra := &extensionsv1alpha1.Infrastructure{}
+	if err := h.seedClient.Get(ctx, request, infra); err != nil {
+		err := fmt.Errorf("failed to retrieve infrastructure %s: %v", request, err)
+		h.logger.Error(err, "Health check failed")
+		return nil, err
+	}
+
+	status, err := helper.InfrastructureStatusFromInfrastructure(infra)
+	if err != nil {
+		return nil, err
+	}
+	// There is nothing to check before the infrastructure has been reconciled.
+	if status == nil || status.VPC.ID == "" {
+		return &healthcheck.SingleCheckResult{IsHealthy: true}, nil
+	}
+
+	config, err := helper.InfrastructureConfigFromInfrastructure(infra)
+	if err != nil {
+		return nil, err
+	}
+
+	credentials, err := alicloud.ReadCredentialsFromSecretRef(ctx, h.seedClient, &infra.Spec.SecretRef)
+	if err != nil {
+		return nil, err
+	}
+	vpcClient, err := h.vpcClientFactory.NewVPC(ctx, infra.Spec.Region, credentials)
+	if err != nil {
+		return nil, err
+	}
+
+	problems, err := checkNATEgress(vpcClient, config, status)
+	if err != nil {
+		err := fmt.Errorf("failed to check the NAT egress of infrastructure %s: %v", request, err)
+		h.logger.Error(err, "Health check failed")
+		return nil, err
+	}
+
+	if len(problems) > 0 {
+		return &healthcheck.SingleCheckResult{
+			IsHealthy: false,
+			Detail:    fmt.Sprintf("nodes may have no internet egress: %s", strings.Join(problems, ", ")),
+			Reason:    ReasonNATEgressUnavailable,
+		}, nil
+	}
+
+	return &healthcheck.SingleCheckResult{
+		IsHealthy: true,
+	}, nil
+}
+
+// checkNATEgress returns the problems of the NAT gateways and SNAT entries of the given infrastructure. If NAT gateways
+// are created per zone then the vswitches of a zone must have SNAT entries in the NAT gateway of the zone, otherwise in
+// the NAT gateway of the VPC.
+func checkNATEgress(vpcClient alicloudclient.VPC, config *apisalicloud.InfrastructureConfig, status *apisalicloud.InfrastructureStatus) ([]string, error) {
+	var (
+		problems []string
+		// zoneNATGatewayIDs maps the zones to the NAT gateways their vswitches are expected to use. The NAT gateway of
+		// the VPC is stored with the empty zone.
+		zoneNATGatewayIDs = map[string]string{}
+		natGateways       []vpc.NatGateway
+	)
+
+	if config.Networks.NatGateway != nil && config.Networks.NatGateway.PerZone {
+		for _, natGatewayStatus := range status.VPC.NatGateways {
+			zoneNATGatewayIDs[natGatewayStatus.Zone] = natGatewayStatus.ID
+			zoneNATGateways, err := describeNATGateways(vpcClient, status.VPC.ID, natGatewayStatus.ID)
+			if err != nil {
+				return nil, err
+			}
+			if len(zoneNATGateways) == 0 {
+				problems = append(problems, fmt.Sprintf("NAT gateway %s of zone %s does not exist", natGatewayStatus.ID, natGatewayStatus.Zone))
+				continue
+			}
+			natGateways = append(natGateways, zoneNATGateways[0])
+		}
+	} else {
+		var natGatewayID string
+		if config.Networks.NatGateway != nil && config.Networks.NatGateway.ID != nil {
+			natGatewayID = *config.Networks.NatGateway.ID
+		}
+		vpcNATGateways, err := describeNATGateways(vpcClient, status.VPC.ID, natGatewayID)
+		if err != nil {
+			return nil, err
+		}
+		if len(vpcNATGateways) != 1 {
+			return []string{fmt.Sprintf("expected one NAT gateway in VPC %s but found %d", status.VPC.ID, len(vpcNATGateways))}, nil
+		}
+		zoneNATGatewayIDs[""] = vpcNATGateways[0].NatGatewayId
+		natGateways = vpcNATGateways
+	}
+
+	// snatVSwitchIDs maps the existing NAT gateways to the vswitches which have an available SNAT entry in them.
+	snatVSwitchIDs := map[string]sets.String{}
+	for _, natGateway := range natGateways {
+		if natGateway.Status != statusAvailable {
+			problems = append(problems, fmt.Sprintf("NAT gateway %s is %s", natGateway.NatGatewayId, natGateway.Status))
+		}
+
+		vswitchIDs := sets.NewString()
+		for _, snatTableID := range natGateway.SnatTableIds.SnatTableId {
+			entries, err := describeSNATEntries(vpcClient, snatTableID)
+			if err != nil {
+				return nil, err
+			}
+			for _, entry := range entries {
+				if entry.Status == statusAvailable && entry.SourceVSwitchId != "" {
+					vswitchIDs.Insert(entry.SourceVSwitchId)
+				}
+			}
+		}
+		snatVSwitchIDs[natGateway.NatGatewayId] = vswitchIDs
+	}
+
+	for _, vswitch := range status.VPC.VSwitches {
+		if vswitch.Purpose != apisalicloud.PurposeNodes && vswitch.Purpose != apisalicloud.PurposePods {
+			continue
+		}
+
+		natGatewayID, ok := zoneNATGatewayIDs[""]
+		if !ok {
+			if natGatewayID, ok = zoneNATGatewayIDs[vswitch.Zone]; !ok {
+				problems = append(problems, fmt.Sprintf("zone %s of vswitch %s has no NAT gateway", vswitch.Zone, vswitch.ID))
+				continue
+			}
+		}
+		// Missing NAT gateways have already been reported.
+		if vswitchIDs, ok := snatVSwitchIDs[natGatewayID]; ok && !vswitchIDs.Has(vswitch.ID) {
+			problems = append(problems, fmt.Sprintf("vswitch %s has no available SNAT entry in NAT gateway %s", vswitch.ID, natGatewayID))
+		}
+	}
+
+	sort.Strings(problems)
+	return problems, nil
+}
+
+func describeNATGateways(vpcClient alicloudclient.VPC, vpcID, natGatewayID string) ([]vpc.NatGateway, error) {
+	req := vpc.CreateDescribeNatGatewaysRequest()
+	req.VpcId = vpcID
+	req.NatGatewayId = natGatewayID
+	req.PageSize = requests.NewInteger(pageSize)
+	res, err := vpcClient.DescribeNatGateways(req)
+	if err != nil {
+		return nil, err
+	}
+	return res.NatGateways.NatGateway, nil
+}
+
+func describeSNATEntries(vpcClient alicloudclient.VPC, snatTableID string) ([]vpc.SnatTableEntry, error) {
+	var (
+		entries    []vpc.SnatTableEntry
+		pageNumber = 1
+		req        = vpc.CreateDescribeSnatTableEntriesRequest()
+	)
+	req.SnatTableId = snatTableID
+	req.PageSize = requests.NewInteger(pageSize)
+
+	for {
+		req.PageNumber = requests.NewInteger(pageNumber)
+		res, err := vpcClient.DescribeSnatTableEntries(req)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, res.SnatTableEntries.SnatTableEntry...)
+
+		if pageNumber*pageSize >= res.TotalCount {
+			break
+		}
+		pageNumber++
+	}
+	return entries, nil
+}
@@ -0,0 +1,230 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package healthcheck
+
+import (
+	"context"
+	"encoding/json"
+
+	alicloudv1alpha1 "github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/alicloud/v1alpha1"
+	mockalicloudclient "github.com/gardener/gardener-extension-provider-alicloud/pkg/mock/provider-alicloud/alicloud/client"
+	"github.com/gardener/gardener-extensions/pkg/controller/healthcheck"
+	mockclient "github.com/gardener/gardener-extensions/pkg/mock/controller-runtime/client"
+
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/vpc"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("NATEgressHealthChecker", func() {
+	var (
+		ctrl      *gomock.Controller
+		c         *mockclient.MockClient
+		factory   *mockalicloudclient.MockFactory
+		vpcClient *mockalicloudclient.MockVPC
+
+		ctx     = context.TODO()
+		request = types.NamespacedName{Namespace: "shoot--foo--bar", Name: "infra"}
+
+		config  *alicloudv1alpha1.InfrastructureConfig
+		status  *alicloudv1alpha1.InfrastructureStatus
+		checker healthcheck.HealthCheck
+	)
+
+	encode := func(obj runtime.Object) *runtime.RawExtension {
+		raw, err := json.Marshal(obj)
+		Expect(err).NotTo(HaveOccurred())
+		return &runtime.RawExtension{Raw: raw}
+	}
+
+	expectInfrastructure := func() {
+		c.EXPECT().
+			Get(ctx, request, gomock.AssignableToTypeOf(&extensionsv1alpha1.Infrastructure{})).
+			DoAndReturn(func(_ context.Context, _ client.ObjectKey, obj runtime.Object) error {
+				infra := obj.(*extensionsv1alpha1.Infrastructure)
+				infra.Spec.Region = "cn-beijing"
+				infra.Spec.SecretRef = corev1.SecretReference{Namespace: request.Namespace, Name: "cloudprovider"}
+				infra.Spec.ProviderConfig = encode(config)
+				if status != nil {
+					infra.Status.ProviderStatus = encode(status)
+				}
+				return nil
+			})
+	}
+
+	expectVPCClient := func() {
+		c.EXPECT().
+			Get(ctx, client.ObjectKey{Namespace: request.Namespace, Name: "cloudprovider"}, gomock.AssignableToTypeOf(&corev1.Secret{})).
+			DoAndReturn(func(_ context.Context, _ client.ObjectKey, obj runtime.Object) error {
+				obj.(*corev1.Secret).Data = map[string][]byte{"accessKeyID": []byte("id"), "accessKeySecret": []byte("secret")}
+				return nil
+			})
+		factory.EXPECT().NewVPC(ctx, "cn-beijing", gomock.Any()).Return(vpcClient, nil)
+	}
+
+	expectNATGateways := func(natGatewayID string, natGateways ...vpc.NatGateway) {
+		vpcClient.EXPECT().DescribeNatGateways(gomock.Any()).DoAndReturn(func(req *vpc.DescribeNatGatewaysRequest) (*vpc.DescribeNatGatewaysResponse, error) {
+			Expect(req.VpcId).To(Equal("vpc-1"))
+			Expect(req.NatGatewayId).To(Equal(natGatewayID))
+			return &vpc.DescribeNatGatewaysResponse{NatGateways: vpc.NatGateways{NatGateway: natGateways}}, nil
+		})
+	}
+
+	expectSNATEntries := func(snatTableID string, vswitchIDs ...string) {
+		vpcClient.EXPECT().DescribeSnatTableEntries(gomock.Any()).DoAndReturn(func(req *vpc.DescribeSnatTableEntriesRequest) (*vpc.DescribeSnatTableEntriesResponse, error) {
+			Expect(req.SnatTableId).To(Equal(snatTableID))
+			res := &vpc.DescribeSnatTableEntriesResponse{TotalCount: len(vswitchIDs)}
+			for _, vswitchID := range vswitchIDs {
+				res.SnatTableEntries.SnatTableEntry = append(res.SnatTableEntries.SnatTableEntry, vpc.SnatTableEntry{
+					SnatTableId:     snatTableID,
+					SourceVSwitchId: vswitchID,
+					Status:          statusAvailable,
+				})
+			}
+			return res, nil
+		})
+	}
+
+	natGateway := func(id, snatTableID string) vpc.NatGateway {
+		return vpc.NatGateway{
+			NatGatewayId: id,
+			Status:       statusAvailable,
+			SnatTableIds: vpc.SnatTableIdsInDescribeNatGateways{SnatTableId: []string{snatTableID}},
+		}
+	}
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		c = mockclient.NewMockClient(ctrl)
+		factory = mockalicloudclient.NewMockFactory(ctrl)
+		vpcClient = mockalicloudclient.NewMockVPC(ctrl)
+
+		config = &alicloudv1alpha1.InfrastructureConfig{
+			TypeMeta: metav1.TypeMeta{APIVersion: alicloudv1alpha1.SchemeGroupVersion.String(), Kind: "InfrastructureConfig"},
+		}
+		status = &alicloudv1alpha1.InfrastructureStatus{
+			TypeMeta: metav1.TypeMeta{APIVersion: alicloudv1alpha1.SchemeGroupVersion.String(), Kind: "InfrastructureStatus"},
+			VPC: alicloudv1alpha1.VPCStatus{
+				ID: "vpc-1",
+				VSwitches: []alicloudv1alpha1.VSwitch{
+					{Purpose: alicloudv1alpha1.PurposeNodes, ID: "vsw-a", Zone: "cn-beijing-a"},
+					{Purpose: alicloudv1alpha1.PurposeNodes, ID: "vsw-b", Zone: "cn-beijing-b"},
+					{Purpose: alicloudv1alpha1.PurposePods, ID: "vsw-pods-b", Zone: "cn-beijing-b"},
+				},
+			},
+		}
+
+		checker = NewNATEgressHealthChecker(factory).DeepCopy()
+		checker.SetLoggerSuffix("alicloud", "infrastructure")
+		checker.InjectSeedClient(c)
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	It("should be healthy if the infrastructure has not been reconciled yet", func() {
+		status = nil
+		expectInfrastructure()
+
+		Expect(checker.Check(ctx, request)).To(Equal(&healthcheck.SingleCheckResult{IsHealthy: true}))
+	})
+
+	It("should be healthy if all vswitches have an SNAT entry in the NAT gateway of the VPC", func() {
+		expectInfrastructure()
+		expectVPCClient()
+		expectNATGateways("", natGateway("ngw-1", "stb-1"))
+		expectSNATEntries("stb-1", "vsw-a", "vsw-b", "vsw-pods-b")
+
+		Expect(checker.Check(ctx, request)).To(Equal(&healthcheck.SingleCheckResult{IsHealthy: true}))
+	})
+
+	It("should be unhealthy if a vswitch has no SNAT entry", func() {
+		expectInfrastructure()
+		expectVPCClient()
+		expectNATGateways("", natGateway("ngw-1", "stb-1"))
+		expectSNATEntries("stb-1", "vsw-a", "vsw-pods-b")
+
+		Expect(checker.Check(ctx, request)).To(Equal(&healthcheck.SingleCheckResult{
+			IsHealthy: false,
+			Detail:    "nodes may have no internet egress: vswitch vsw-b has no available SNAT entry in NAT gateway ngw-1",
+			Reason:    ReasonNATEgressUnavailable,
+		}))
+	})
+
+	It("should be unhealthy if the VPC has no NAT gateway", func() {
+		expectInfrastructure()
+		expectVPCClient()
+		expectNATGateways("")
+
+		result, err := checker.Check(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.IsHealthy).To(BeFalse())
+		Expect(result.Detail).To(ContainSubstring("expected one NAT gateway in VPC vpc-1 but found 0"))
+	})
+
+	It("should only check the configured NAT gateway of an existing VPC", func() {
+		natGatewayID := "ngw-2"
+		config.Networks.NatGateway = &alicloudv1alpha1.NatGateway{ID: &natGatewayID}
+		expectInfrastructure()
+		expectVPCClient()
+		expectNATGateways("ngw-2", natGateway("ngw-2", "stb-2"))
+		expectSNATEntries("stb-2", "vsw-a", "vsw-b", "vsw-pods-b")
+
+		Expect(checker.Check(ctx, request)).To(Equal(&healthcheck.SingleCheckResult{IsHealthy: true}))
+	})
+
+	Context("NAT gateways per zone", func() {
+		BeforeEach(func() {
+			config.Networks.NatGateway = &alicloudv1alpha1.NatGateway{PerZone: true}
+			status.VPC.NatGateways = []alicloudv1alpha1.NatGatewayStatus{
+				{ID: "ngw-a", Zone: "cn-beijing-a"},
+				{ID: "ngw-b", Zone: "cn-beijing-b"},
+			}
+		})
+
+		It("should be healthy if the vswitches have SNAT entries in the NAT gateways of their zones", func() {
+			expectInfrastructure()
+			expectVPCClient()
+			expectNATGateways("ngw-a", natGateway("ngw-a", "stb-a"))
+			expectNATGateways("ngw-b", natGateway("ngw-b", "stb-b"))
+			expectSNATEntries("stb-a", "vsw-a")
+			expectSNATEntries("stb-b", "vsw-b", "vsw-pods-b")
+
+			Expect(checker.Check(ctx, request)).To(Equal(&healthcheck.SingleCheckResult{IsHealthy: true}))
+		})
+
+		It("should be unhealthy if the NAT gateway of a zone is missing or the SNAT entry is in another zone", func() {
+			expectInfrastructure()
+			expectVPCClient()
+			expectNATGateways("ngw-a", natGateway("ngw-a", "stb-a"))
+			expectNATGateways("ngw-b")
+			expectSNATEntries("stb-a", "vsw-a", "vsw-b")
+
+			Expect(checker.Check(ctx, request)).To(Equal(&healthcheck.SingleCheckResult{
+				IsHealthy: false,
+				Detail:    "nodes may have no internet egress: NAT gateway ngw-b of zone cn-beijing-b does not exist",
+				Reason:    ReasonNATEgressUnavailable,
+			}))
+		})
+	})
+})
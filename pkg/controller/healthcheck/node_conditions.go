@@ -0,0 +1,146 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package healthcheck
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gardener/gardener-extensions/pkg/controller/healthcheck"
+
+	machinev1alpha1 "github.com/gardener/machine-controller-manager/pkg/apis/machine/v1alpha1"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// ReasonNodeConditionsUnhealthy is the reason of unhealthy results of the NodeConditionsHealthChecker.
+const ReasonNodeConditionsUnhealthy = "NodeConditionsUnhealthy"
+
+// NodeConditionsHealthChecker checks the conditions of the nodes of a worker. Nodes may wedge with a ready kubelet,
+// e.g. because their networking is broken, which is not noticed by the machine-controller-manager. The worker is
+// unhealthy if more nodes than tolerated have one of the given conditions.
+type NodeConditionsHealthChecker struct {
+	logger         logr.Logger
+	seedClient     client.Client
+	shootClient    client.Client
+	conditionTypes []corev1.NodeConditionType
+	maxUnhealthy   intstr.IntOrString
+}
+
+// NewNodeConditionsHealthChecker returns a health check for the nodes of the machines of a worker. A node is unhealthy
+// if one of the given condition types has status True, the given maximum number or percentage of the nodes may be
+// unhealthy.
+func NewNodeConditionsHealthChecker(conditionTypes []corev1.NodeConditionType, maxUnhealthy intstr.IntOrString) healthcheck.HealthCheck {
+	return &NodeConditionsHealthChecker{
+		conditionTypes: conditionTypes,
+		maxUnhealthy:   maxUnhealthy,
+	}
+}
+
+// InjectSeedClient injects the seed client
+func (h *NodeConditionsHealthChecker) InjectSeedClient(seedClient client.Client) {
+	h.seedClient = seedClient
+}
+
+// InjectShootClient injects the shoot client
+func (h *NodeConditionsHealthChecker) InjectShootClient(shootClient client.Client) {
+	h.shootClient = shootClient
+}
+
+// SetLoggerSuffix injects the logger
+func (h *NodeConditionsHealthChecker) SetLoggerSuffix(provider, extension string) {
+	h.logger = log.Log.WithName(fmt.Sprintf("%s-%s-healthcheck-node-conditions", provider, extension))
+}
+
+// DeepCopy clones the healthCheck struct by making a copy and returning the pointer to that new copy
+func (h *NodeConditionsHealthChecker) DeepCopy() healthcheck.HealthCheck {
+	copy := *h
+	return &copy
+}
+
+// Check executes the health check
+func (h *NodeConditionsHealthChecker) Check(ctx context.Context, request types.NamespacedName) (*healthcheck.SingleCheckResult, error) {
+	machineList := &machinev1alpha1.MachineList{}
+	if err := h.seedClient.List(ctx, machineList, client.InNamespace(request.Namespace)); err != nil {
+		err := fmt.Errorf("failed to list machines in namespace '%s': %v", request.Namespace, err)
+		h.logger.Error(err, "Health check failed")
+		return nil, err
+	}
+	machineNodes := sets.NewString()
+	for _, machine := range machineList.Items {
+		if machine.Status.Node != "" {
+			machineNodes.Insert(machine.Status.Node)
+		}
+	}
+
+	nodeList := &corev1.NodeList{}
+	if err := h.shootClient.List(ctx, nodeList); err != nil {
+		err := fmt.Errorf("failed to list nodes: %v", err)
+		h.logger.Error(err, "Health check failed")
+		return nil, err
+	}
+
+	var (
+		nodes     int
+		unhealthy []string
+	)
+	for _, node := range nodeList.Items {
+		if !machineNodes.Has(node.Name) {
+			continue
+		}
+		nodes++
+		if conditionTypes := h.unhealthyConditionTypes(node); len(conditionTypes) > 0 {
+			unhealthy = append(unhealthy, fmt.Sprintf("%s (%s)", node.Name, strings.Join(conditionTypes, ", ")))
+		}
+	}
+
+	maxUnhealthy, err := intstr.GetValueFromIntOrPercent(&h.maxUnhealthy, nodes, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(unhealthy) > maxUnhealthy {
+		sort.Strings(unhealthy)
+		return &healthcheck.SingleCheckResult{
+			IsHealthy: false,
+			Detail:    fmt.Sprintf("%d of %d nodes are unhealthy (at most %d may be unhealthy): %s", len(unhealthy), nodes, maxUnhealthy, strings.Join(unhealthy, ", ")),
+			Reason:    ReasonNodeConditionsUnhealthy,
+		}, nil
+	}
+
+	return &healthcheck.SingleCheckResult{
+		IsHealthy: true,
+	}, nil
+}
+
+// unhealthyConditionTypes returns the checked condition types of the given node whose status is True.
+func (h *NodeConditionsHealthChecker) unhealthyConditionTypes(node corev1.Node) []string {
+	var conditionTypes []string
+	for _, conditionType := range h.conditionTypes {
+		for _, condition := range node.Status.Conditions {
+			if condition.Type == conditionType && condition.Status == corev1.ConditionTrue {
+				conditionTypes = append(conditionTypes, string(conditionType))
+			}
+		}
+	}
+	return conditionTypes
+}
@@ -0,0 +1,133 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package healthcheck
+
+import (
+	"context"
+
+	"github.com/gardener/gardener-extensions/pkg/controller/healthcheck"
+	mockclient "github.com/gardener/gardener-extensions/pkg/mock/controller-runtime/client"
+
+	machinev1alpha1 "github.com/gardener/machine-controller-manager/pkg/apis/machine/v1alpha1"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("NodeConditionsHealthChecker", func() {
+	var (
+		ctrl        *gomock.Controller
+		seedClient  *mockclient.MockClient
+		shootClient *mockclient.MockClient
+
+		ctx     = context.TODO()
+		request = types.NamespacedName{Namespace: "shoot--foo--bar", Name: "worker"}
+
+		conditionTypes = []corev1.NodeConditionType{corev1.NodeNetworkUnavailable, corev1.NodeDiskPressure}
+	)
+
+	node := func(name string, conditions ...corev1.NodeCondition) corev1.Node {
+		return corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status:     corev1.NodeStatus{Conditions: conditions},
+		}
+	}
+	condition := func(conditionType corev1.NodeConditionType, status corev1.ConditionStatus) corev1.NodeCondition {
+		return corev1.NodeCondition{Type: conditionType, Status: status}
+	}
+
+	expectNodes := func(nodes ...corev1.Node) {
+		seedClient.EXPECT().
+			List(ctx, &machinev1alpha1.MachineList{}, client.InNamespace(request.Namespace)).
+			DoAndReturn(func(_ context.Context, list runtime.Object, _ ...client.ListOption) error {
+				machineList := list.(*machinev1alpha1.MachineList)
+				for _, node := range nodes {
+					machineList.Items = append(machineList.Items, machinev1alpha1.Machine{Status: machinev1alpha1.MachineStatus{Node: node.Name}})
+				}
+				return nil
+			})
+		shootClient.EXPECT().
+			List(ctx, &corev1.NodeList{}).
+			DoAndReturn(func(_ context.Context, list runtime.Object, _ ...client.ListOption) error {
+				// Nodes without machine do not belong to the worker and are ignored.
+				list.(*corev1.NodeList).Items = append(nodes, node("foreign", condition(corev1.NodeNetworkUnavailable, corev1.ConditionTrue)))
+				return nil
+			})
+	}
+
+	newChecker := func(maxUnhealthy intstr.IntOrString) healthcheck.HealthCheck {
+		checker := NewNodeConditionsHealthChecker(conditionTypes, maxUnhealthy).DeepCopy()
+		checker.SetLoggerSuffix("alicloud", "worker")
+		checker.InjectSeedClient(seedClient)
+		checker.InjectShootClient(shootClient)
+		return checker
+	}
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		seedClient = mockclient.NewMockClient(ctrl)
+		shootClient = mockclient.NewMockClient(ctrl)
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	It("should be healthy if no node has one of the conditions", func() {
+		expectNodes(
+			node("node-1", condition(corev1.NodeNetworkUnavailable, corev1.ConditionFalse), condition(corev1.NodeReady, corev1.ConditionTrue)),
+			node("node-2", condition(corev1.NodeMemoryPressure, corev1.ConditionTrue)),
+		)
+
+		result, err := newChecker(intstr.FromInt(0)).Check(ctx, request)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.IsHealthy).To(BeTrue())
+	})
+
+	It("should tolerate unhealthy nodes up to the threshold", func() {
+		expectNodes(
+			node("node-1", condition(corev1.NodeDiskPressure, corev1.ConditionTrue)),
+			node("node-2"),
+			node("node-3"),
+		)
+
+		result, err := newChecker(intstr.FromString("10%")).Check(ctx, request)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.IsHealthy).To(BeTrue())
+	})
+
+	It("should be unhealthy if more nodes than tolerated have one of the conditions", func() {
+		expectNodes(
+			node("node-1", condition(corev1.NodeDiskPressure, corev1.ConditionTrue)),
+			node("node-2", condition(corev1.NodeNetworkUnavailable, corev1.ConditionTrue), condition(corev1.NodeDiskPressure, corev1.ConditionTrue)),
+			node("node-3", condition(corev1.NodeNetworkUnavailable, corev1.ConditionUnknown)),
+		)
+
+		result, err := newChecker(intstr.FromInt(1)).Check(ctx, request)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.IsHealthy).To(BeFalse())
+		Expect(result.Reason).To(Equal(ReasonNodeConditionsUnhealthy))
+		Expect(result.Detail).To(Equal("2 of 3 nodes are unhealthy (at most 1 may be unhealthy): node-1 (DiskPressure), node-2 (NetworkUnavailable, DiskPressure)"))
+	})
+})
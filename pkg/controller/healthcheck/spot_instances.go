@@ -0,0 +1,127 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package healthcheck
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gardener/gardener-extensions/pkg/controller/healthcheck"
+
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	machinev1alpha1 "github.com/gardener/machine-controller-manager/pkg/apis/machine/v1alpha1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// SpotInstancesHealthChecker wraps a health check of the nodes of a worker. Spot instances can be reclaimed by Alicloud
+// at any time, hence nodes of worker pools with spot instances may go missing or NotReady until the
+// machine-controller-manager has replaced them. If the worker has spot instances, an unhealthy result of the wrapped
+// check is therefore only reported once it persists longer than the grace period.
+type SpotInstancesHealthChecker struct {
+	healthcheck.HealthCheck
+
+	seedClient  client.Client
+	gracePeriod time.Duration
+	now         func() time.Time
+
+	// unhealthySince is shared between all copies of the health check.
+	unhealthySince *sync.Map
+}
+
+// NewSpotInstancesHealthChecker returns a health check which tolerates unhealthy results of the given health check for
+// the given grace period if the worker has spot instances. The grace period can be overwritten per worker with the
+// AnnotationGracePeriod annotation.
+func NewSpotInstancesHealthChecker(healthCheck healthcheck.HealthCheck, gracePeriod time.Duration) healthcheck.HealthCheck {
+	return &SpotInstancesHealthChecker{
+		HealthCheck:    healthCheck,
+		gracePeriod:    gracePeriod,
+		now:            time.Now,
+		unhealthySince: &sync.Map{},
+	}
+}
+
+// InjectSeedClient injects the seed client
+func (h *SpotInstancesHealthChecker) InjectSeedClient(seedClient client.Client) {
+	h.seedClient = seedClient
+	h.HealthCheck.InjectSeedClient(seedClient)
+}
+
+// DeepCopy clones the healthCheck struct by making a copy and returning the pointer to that new copy
+func (h *SpotInstancesHealthChecker) DeepCopy() healthcheck.HealthCheck {
+	copy := *h
+	copy.HealthCheck = h.HealthCheck.DeepCopy()
+	return &copy
+}
+
+// Check executes the wrapped health check and tolerates unhealthy results for the grace period if the worker has
+// spot instances.
+func (h *SpotInstancesHealthChecker) Check(ctx context.Context, request types.NamespacedName) (*healthcheck.SingleCheckResult, error) {
+	result, err := h.HealthCheck.Check(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	key := request.String()
+	if result.IsHealthy {
+		h.unhealthySince.Delete(key)
+		return result, nil
+	}
+
+	hasSpotInstances, err := h.hasSpotInstances(ctx, request.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	if !hasSpotInstances {
+		h.unhealthySince.Delete(key)
+		return result, nil
+	}
+
+	worker := &extensionsv1alpha1.Worker{}
+	if err := h.seedClient.Get(ctx, request, worker); err != nil {
+		return nil, fmt.Errorf("failed to retrieve worker %s: %v", key, err)
+	}
+	gracePeriod, err := gracePeriodFromAnnotations(worker.Annotations, h.gracePeriod)
+	if err != nil {
+		return nil, err
+	}
+
+	since, _ := h.unhealthySince.LoadOrStore(key, h.now())
+	if h.now().Sub(since.(time.Time)) < gracePeriod {
+		return &healthcheck.SingleCheckResult{IsHealthy: true}, nil
+	}
+
+	return &healthcheck.SingleCheckResult{
+		IsHealthy: false,
+		Detail:    fmt.Sprintf("%s (tolerated for spot instances for %s)", result.Detail, gracePeriod),
+		Reason:    result.Reason,
+	}, nil
+}
+
+func (h *SpotInstancesHealthChecker) hasSpotInstances(ctx context.Context, namespace string) (bool, error) {
+	machineClassList := &machinev1alpha1.AlicloudMachineClassList{}
+	if err := h.seedClient.List(ctx, machineClassList, client.InNamespace(namespace)); err != nil {
+		return false, fmt.Errorf("failed to list machine classes in namespace %s: %v", namespace, err)
+	}
+
+	for _, machineClass := range machineClassList.Items {
+		if spotStrategy := machineClass.Spec.SpotStrategy; spotStrategy != "" && spotStrategy != "NoSpot" {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,166 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package healthcheck
+
+import (
+	"context"
+	"time"
+
+	"github.com/gardener/gardener-extensions/pkg/controller/healthcheck"
+	mockclient "github.com/gardener/gardener-extensions/pkg/mock/controller-runtime/client"
+
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	machinev1alpha1 "github.com/gardener/machine-controller-manager/pkg/apis/machine/v1alpha1"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type fakeHealthCheck struct {
+	healthcheck.HealthCheck
+	result *healthcheck.SingleCheckResult
+}
+
+func (f *fakeHealthCheck) InjectSeedClient(client.Client) {}
+
+func (f *fakeHealthCheck) DeepCopy() healthcheck.HealthCheck { return f }
+
+func (f *fakeHealthCheck) Check(context.Context, types.NamespacedName) (*healthcheck.SingleCheckResult, error) {
+	return f.result, nil
+}
+
+var _ = Describe("SpotInstancesHealthChecker", func() {
+	var (
+		ctrl *gomock.Controller
+		c    *mockclient.MockClient
+
+		ctx         = context.TODO()
+		request     = types.NamespacedName{Namespace: "shoot--foo--bar", Name: "worker"}
+		gracePeriod = 10 * time.Minute
+		now         time.Time
+
+		unhealthy = &healthcheck.SingleCheckResult{IsHealthy: false, Detail: "not enough worker nodes registered in the cluster (1/2)", Reason: "MissingNodes"}
+
+		inner   *fakeHealthCheck
+		checker *SpotInstancesHealthChecker
+	)
+
+	expectMachineClasses := func(spotStrategy string) {
+		c.EXPECT().
+			List(ctx, gomock.AssignableToTypeOf(&machinev1alpha1.AlicloudMachineClassList{}), client.InNamespace(request.Namespace)).
+			DoAndReturn(func(_ context.Context, list runtime.Object, _ ...client.ListOption) error {
+				list.(*machinev1alpha1.AlicloudMachineClassList).Items = []machinev1alpha1.AlicloudMachineClass{
+					{Spec: machinev1alpha1.AlicloudMachineClassSpec{SpotStrategy: "NoSpot"}},
+					{Spec: machinev1alpha1.AlicloudMachineClassSpec{SpotStrategy: spotStrategy}},
+				}
+				return nil
+			})
+	}
+
+	expectWorker := func(annotations map[string]string) {
+		c.EXPECT().
+			Get(ctx, request, &extensionsv1alpha1.Worker{}).
+			DoAndReturn(func(_ context.Context, _ client.ObjectKey, obj runtime.Object) error {
+				obj.(*extensionsv1alpha1.Worker).Annotations = annotations
+				return nil
+			})
+	}
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		c = mockclient.NewMockClient(ctrl)
+
+		now = time.Now()
+		inner = &fakeHealthCheck{result: unhealthy}
+		checker = NewSpotInstancesHealthChecker(inner, gracePeriod).DeepCopy().(*SpotInstancesHealthChecker)
+		checker.now = func() time.Time { return now }
+		checker.InjectSeedClient(c)
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	It("should return healthy results of the wrapped check", func() {
+		inner.result = &healthcheck.SingleCheckResult{IsHealthy: true}
+
+		Expect(checker.Check(ctx, request)).To(Equal(inner.result))
+	})
+
+	It("should return unhealthy results if the worker has no spot instances", func() {
+		expectMachineClasses("NoSpot")
+
+		Expect(checker.Check(ctx, request)).To(Equal(unhealthy))
+	})
+
+	It("should tolerate unhealthy results of workers with spot instances for the grace period", func() {
+		expectMachineClasses("SpotAsPriceGo")
+		expectWorker(nil)
+		Expect(checker.Check(ctx, request)).To(Equal(&healthcheck.SingleCheckResult{IsHealthy: true}))
+
+		now = now.Add(gracePeriod - time.Second)
+		expectMachineClasses("SpotAsPriceGo")
+		expectWorker(nil)
+		Expect(checker.Check(ctx, request)).To(Equal(&healthcheck.SingleCheckResult{IsHealthy: true}))
+
+		now = now.Add(time.Second)
+		expectMachineClasses("SpotAsPriceGo")
+		expectWorker(nil)
+		result, err := checker.Check(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.IsHealthy).To(BeFalse())
+		Expect(result.Reason).To(Equal("MissingNodes"))
+	})
+
+	It("should restart the grace period once the wrapped check was healthy", func() {
+		expectMachineClasses("SpotWithPriceLimit")
+		expectWorker(nil)
+		Expect(checker.Check(ctx, request)).To(Equal(&healthcheck.SingleCheckResult{IsHealthy: true}))
+
+		inner.result = &healthcheck.SingleCheckResult{IsHealthy: true}
+		Expect(checker.Check(ctx, request)).To(Equal(inner.result))
+
+		now = now.Add(gracePeriod)
+		inner.result = unhealthy
+		expectMachineClasses("SpotWithPriceLimit")
+		expectWorker(nil)
+		Expect(checker.Check(ctx, request)).To(Equal(&healthcheck.SingleCheckResult{IsHealthy: true}))
+	})
+
+	It("should use the grace period of the worker annotation", func() {
+		expectMachineClasses("SpotAsPriceGo")
+		expectWorker(map[string]string{AnnotationGracePeriod: "1m"})
+		Expect(checker.Check(ctx, request)).To(Equal(&healthcheck.SingleCheckResult{IsHealthy: true}))
+
+		now = now.Add(time.Minute)
+		expectMachineClasses("SpotAsPriceGo")
+		expectWorker(map[string]string{AnnotationGracePeriod: "1m"})
+		result, err := checker.Check(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.IsHealthy).To(BeFalse())
+		Expect(result.Detail).To(ContainSubstring("tolerated for spot instances for 1m0s"))
+	})
+
+	It("should fail for a malformed grace period annotation", func() {
+		expectMachineClasses("SpotAsPriceGo")
+		expectWorker(map[string]string{AnnotationGracePeriod: "ten minutes"})
+
+		_, err := checker.Check(ctx, request)
+		Expect(err).To(MatchError(ContainSubstring(AnnotationGracePeriod)))
+	})
+})
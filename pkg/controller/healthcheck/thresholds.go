@@ -0,0 +1,68 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package healthcheck
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	// AnnotationGracePeriod is the annotation of Worker resources overwriting the duration for which unhealthy nodes of
+	// workers with spot instances are tolerated, e.g. "15m".
+	AnnotationGracePeriod = "healthcheck.alicloud.provider.extensions.gardener.cloud/grace-period"
+	// AnnotationMaxUnavailable is the annotation of ControlPlane resources overwriting the number or percentage of
+	// unready pods tolerated for the daemon sets in the shoot, e.g. "2" or "25%".
+	AnnotationMaxUnavailable = "healthcheck.alicloud.provider.extensions.gardener.cloud/max-unavailable"
+)
+
+// gracePeriodFromAnnotations returns the grace period of the given annotations, or the given default if it is not set.
+func gracePeriodFromAnnotations(annotations map[string]string, defaultGracePeriod time.Duration) (time.Duration, error) {
+	value, ok := annotations[AnnotationGracePeriod]
+	if !ok {
+		return defaultGracePeriod, nil
+	}
+
+	gracePeriod, err := time.ParseDuration(value)
+	if err != nil || gracePeriod < 0 {
+		return 0, fmt.Errorf("invalid value %q of annotation %s: must be a non-negative duration", value, AnnotationGracePeriod)
+	}
+	return gracePeriod, nil
+}
+
+// maxUnavailableFromAnnotations returns the maximum number or percentage of unavailable replicas of the given
+// annotations, or the given default if it is not set.
+func maxUnavailableFromAnnotations(annotations map[string]string, defaultMaxUnavailable intstr.IntOrString) (intstr.IntOrString, error) {
+	value, ok := annotations[AnnotationMaxUnavailable]
+	if !ok {
+		return defaultMaxUnavailable, nil
+	}
+
+	invalid := fmt.Errorf("invalid value %q of annotation %s: must be a non-negative number or a percentage", value, AnnotationMaxUnavailable)
+	if percentage := strings.TrimSuffix(value, "%"); percentage != value {
+		if p, err := strconv.Atoi(percentage); err != nil || p < 0 || p > 100 {
+			return intstr.IntOrString{}, invalid
+		}
+		return intstr.FromString(value), nil
+	}
+	if n, err := strconv.Atoi(value); err == nil && n >= 0 {
+		return intstr.FromInt(n), nil
+	}
+	return intstr.IntOrString{}, invalid
+}
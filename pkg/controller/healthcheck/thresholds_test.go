@@ -0,0 +1,62 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package healthcheck
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+var _ = Describe("Thresholds", func() {
+	DescribeTable("#gracePeriodFromAnnotations",
+		func(annotations map[string]string, expected time.Duration, expectErr bool) {
+			gracePeriod, err := gracePeriodFromAnnotations(annotations, 10*time.Minute)
+			if expectErr {
+				Expect(err).To(HaveOccurred())
+				return
+			}
+			Expect(err).NotTo(HaveOccurred())
+			Expect(gracePeriod).To(Equal(expected))
+		},
+		Entry("default", nil, 10*time.Minute, false),
+		Entry("valid", map[string]string{AnnotationGracePeriod: "15m"}, 15*time.Minute, false),
+		Entry("zero", map[string]string{AnnotationGracePeriod: "0s"}, time.Duration(0), false),
+		Entry("malformed", map[string]string{AnnotationGracePeriod: "15 minutes"}, time.Duration(0), true),
+		Entry("negative", map[string]string{AnnotationGracePeriod: "-1m"}, time.Duration(0), true),
+	)
+
+	DescribeTable("#maxUnavailableFromAnnotations",
+		func(annotations map[string]string, expected intstr.IntOrString, expectErr bool) {
+			maxUnavailable, err := maxUnavailableFromAnnotations(annotations, intstr.FromString("10%"))
+			if expectErr {
+				Expect(err).To(HaveOccurred())
+				return
+			}
+			Expect(err).NotTo(HaveOccurred())
+			Expect(maxUnavailable).To(Equal(expected))
+		},
+		Entry("default", nil, intstr.FromString("10%"), false),
+		Entry("number", map[string]string{AnnotationMaxUnavailable: "2"}, intstr.FromInt(2), false),
+		Entry("percentage", map[string]string{AnnotationMaxUnavailable: "25%"}, intstr.FromString("25%"), false),
+		Entry("malformed", map[string]string{AnnotationMaxUnavailable: "two"}, intstr.IntOrString{}, true),
+		Entry("negative", map[string]string{AnnotationMaxUnavailable: "-1"}, intstr.IntOrString{}, true),
+		Entry("malformed percentage", map[string]string{AnnotationMaxUnavailable: "a%"}, intstr.IntOrString{}, true),
+		Entry("percentage above 100", map[string]string{AnnotationMaxUnavailable: "150%"}, intstr.IntOrString{}, true),
+	)
+})
@@ -17,7 +17,6 @@ package infrastructure
 import (
 	"context"
 	"fmt"
-	"strconv"
 	"strings"
 	"time"
 
@@ -26,6 +25,7 @@ import (
 	apisalicloud "github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/alicloud"
 	"github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/alicloud/helper"
 	alicloudv1alpha1 "github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/alicloud/v1alpha1"
+	"github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/alicloud/validation"
 	"github.com/gardener/gardener-extension-provider-alicloud/pkg/controller/common"
 	extensioncontroller "github.com/gardener/gardener-extensions/pkg/controller"
 	commonext "github.com/gardener/gardener-extensions/pkg/controller/common"
@@ -44,6 +44,7 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/retry"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
@@ -59,9 +60,10 @@ var StatusTypeMeta = func() metav1.TypeMeta {
 }()
 
 // NewActuator instantiates an actuator with the default dependencies.
-func NewActuator(machineImageOwnerSecretRef *corev1.SecretReference) infrastructure.Actuator {
+func NewActuator(recorder record.EventRecorder, machineImageOwnerSecretRef *corev1.SecretReference) infrastructure.Actuator {
 	return NewActuatorWithDeps(
 		log.Log.WithName("infrastructure-actuator"),
+		recorder,
 		alicloudclient.NewClientFactory(),
 		alicloudclient.DefaultFactory(),
 		terraformer.DefaultFactory(),
@@ -74,6 +76,7 @@ func NewActuator(machineImageOwnerSecretRef *corev1.SecretReference) infrastruct
 // NewActuatorWithDeps instantiates an actuator with the given dependencies.
 func NewActuatorWithDeps(
 	logger logr.Logger,
+	recorder record.EventRecorder,
 	newClientFactory alicloudclient.ClientFactory,
 	alicloudClientFactory alicloudclient.Factory,
 	terraformerFactory terraformer.Factory,
@@ -83,6 +86,7 @@ func NewActuatorWithDeps(
 ) infrastructure.Actuator {
 	a := &actuator{
 		logger:                     logger,
+		recorder:                   recorder,
 		ChartRendererContext:       commonext.NewChartRendererContext(chartRendererFactory),
 		newClientFactory:           newClientFactory,
 		alicloudClientFactory:      alicloudClientFactory,
@@ -95,10 +99,11 @@ func NewActuatorWithDeps(
 }
 
 type actuator struct {
-	logger logr.Logger
+	logger   logr.Logger
+	recorder record.EventRecorder
 	commonext.ChartRendererContext
 
-	alicloudECSClient     alicloudclient.ECS
+	apiReader             client.Reader
 	newClientFactory      alicloudclient.ClientFactory
 	alicloudClientFactory alicloudclient.Factory
 	terraformerFactory    terraformer.Factory
@@ -107,27 +112,33 @@ type actuator struct {
 	machineImageOwnerSecretRef *corev1.SecretReference
 }
 
-// InjectAPIReader implements inject.APIReader and instantiates actuator.alicloudECSClient.
+// InjectAPIReader implements inject.APIReader.
 func (a *actuator) InjectAPIReader(reader client.Reader) error {
-	if a.machineImageOwnerSecretRef != nil {
-		machineImageOwnerSecret := &corev1.Secret{}
-		err := reader.Get(context.Background(), client.ObjectKey{
-			Name:      a.machineImageOwnerSecretRef.Name,
-			Namespace: a.machineImageOwnerSecretRef.Namespace,
-		}, machineImageOwnerSecret)
-		if err != nil {
-			return err
-		}
-		seedCloudProviderCredentials, err := alicloud.ReadSecretCredentials(machineImageOwnerSecret)
-		if err != nil {
-			return err
-		}
-		a.alicloudECSClient, err = a.newClientFactory.NewECSClient(context.Background(), "", seedCloudProviderCredentials.AccessKeyID, seedCloudProviderCredentials.AccessKeySecret)
-		return err
-	}
+	a.apiReader = reader
 	return nil
 }
 
+// newMachineImageOwnerECSClient creates an ECS client for the account owning the customized machine images. The
+// credentials are read on every call, so that rotated credentials are used without restarting the extension.
+func (a *actuator) newMachineImageOwnerECSClient(ctx context.Context) (alicloudclient.ECS, error) {
+	if a.machineImageOwnerSecretRef == nil {
+		return nil, fmt.Errorf("image sharing is not enabled or configured correctly and Alicloud ECS client is not instantiated in Seed. Please contact Gardener administrator")
+	}
+
+	machineImageOwnerSecret := &corev1.Secret{}
+	if err := a.apiReader.Get(ctx, client.ObjectKey{
+		Name:      a.machineImageOwnerSecretRef.Name,
+		Namespace: a.machineImageOwnerSecretRef.Namespace,
+	}, machineImageOwnerSecret); err != nil {
+		return nil, err
+	}
+	seedCloudProviderCredentials, err := alicloud.ReadSecretCredentials(machineImageOwnerSecret)
+	if err != nil {
+		return nil, err
+	}
+	return a.newClientFactory.NewECSClient(ctx, "", seedCloudProviderCredentials)
+}
+
 func (a *actuator) getConfigAndCredentialsForInfra(ctx context.Context, infra *extensionsv1alpha1.Infrastructure) (*alicloudv1alpha1.InfrastructureConfig, *alicloud.Credentials, error) {
 	config := &alicloudv1alpha1.InfrastructureConfig{}
 	if _, _, err := a.Decoder().Decode(infra.Spec.ProviderConfig.Raw, nil, config); err != nil {
@@ -142,6 +153,20 @@ func (a *actuator) getConfigAndCredentialsForInfra(ctx context.Context, infra *e
 	return config, credentials, nil
 }
 
+// validateConfigAgainstRegion validates the InfrastructureConfig of the given infrastructure against its region, which
+// is not known when the InfrastructureConfig is validated on its own.
+func (a *actuator) validateConfigAgainstRegion(infra *extensionsv1alpha1.Infrastructure) error {
+	config := &apisalicloud.InfrastructureConfig{}
+	if _, _, err := a.Decoder().Decode(infra.Spec.ProviderConfig.Raw, nil, config); err != nil {
+		return err
+	}
+
+	if errs := validation.ValidateInfrastructureConfigAgainstRegion(config, infra.Spec.Region); len(errs) > 0 {
+		return fmt.Errorf("invalid infrastructure config for region %s: %v", infra.Spec.Region, errs.ToAggregate())
+	}
+	return nil
+}
+
 func (a *actuator) fetchEIPInternetChargeType(vpcClient alicloudclient.VPC, tf terraformer.Terraformer) (string, error) {
 	stateVariables, err := tf.GetStateOutputVariables(TerraformerOutputKeyVPCID)
 	if err != nil {
@@ -155,12 +180,13 @@ func (a *actuator) fetchEIPInternetChargeType(vpcClient alicloudclient.VPC, tf t
 }
 
 func (a *actuator) getInitializerValues(
+	ctx context.Context,
 	tf terraformer.Terraformer,
 	infra *extensionsv1alpha1.Infrastructure,
 	config *alicloudv1alpha1.InfrastructureConfig,
 	credentials *alicloud.Credentials,
 ) (*InitializerValues, error) {
-	vpcClient, err := a.alicloudClientFactory.NewVPC(infra.Spec.Region, credentials.AccessKeyID, credentials.AccessKeySecret)
+	vpcClient, err := a.alicloudClientFactory.NewVPC(ctx, infra.Spec.Region, credentials)
 	if err != nil {
 		return nil, err
 	}
@@ -176,11 +202,15 @@ func (a *actuator) getInitializerValues(
 
 	vpcID := *config.Networks.VPC.ID
 
-	vpcInfo, err := GetVPCInfo(vpcClient, vpcID)
+	vpcInfo, err := getExistingVPCInfo(vpcClient, config)
 	if err != nil {
 		return nil, err
 	}
 
+	if isDualStackEnabled(config) && vpcInfo.IPv6CIDR == "" {
+		return nil, fmt.Errorf("dual-stack is enabled but VPC %s has no IPv6 CIDR assigned", vpcID)
+	}
+
 	return a.terraformChartOps.ComputeUseVPCInitializerValues(config, vpcInfo), nil
 }
 
@@ -204,7 +234,12 @@ func (a *actuator) newInitializer(infra *extensionsv1alpha1.Infrastructure, conf
 	return a.terraformerFactory.DefaultInitializer(a.Client(), files.Main, files.Variables, files.TFVars, terraformState.Data), nil
 }
 
-func (a *actuator) newTerraformer(infra *extensionsv1alpha1.Infrastructure, credentials *alicloud.Credentials) (terraformer.Terraformer, error) {
+func (a *actuator) newTerraformer(ctx context.Context, infra *extensionsv1alpha1.Infrastructure, credentials *alicloud.Credentials) (terraformer.Terraformer, error) {
+	credentials, err := alicloudclient.ResolveCredentials(ctx, infra.Spec.Region, credentials)
+	if err != nil {
+		return nil, err
+	}
+
 	return common.NewTerraformer(a.terraformerFactory, a.RESTConfig(), credentials, TerraformerPurpose, infra.Namespace, infra.Name)
 }
 
@@ -216,8 +251,35 @@ func (a *actuator) extractStatus(tf terraformer.Terraformer, infraConfig *aliclo
 		TerraformerOutputKeyKeyPairName,
 	}
 
-	for zoneIndex := range infraConfig.Networks.Zones {
-		outputVarKeys = append(outputVarKeys, fmt.Sprintf("%s%d", TerraformerOutputKeyVSwitchNodesPrefix, zoneIndex))
+	dualStack := isDualStackEnabled(infraConfig)
+	if dualStack {
+		outputVarKeys = append(outputVarKeys, TerraformerOutputKeyVPCIPv6CIDR)
+	}
+
+	natGatewayPerZone := isNATGatewayPerZone(infraConfig)
+	for zoneIndex, zone := range infraConfig.Networks.Zones {
+		for vswitchIndex := 0; vswitchIndex <= len(zone.AdditionalWorkers); vswitchIndex++ {
+			suffix := VSwitchOutputKeySuffix(zoneIndex, vswitchIndex)
+			outputVarKeys = append(outputVarKeys, TerraformerOutputKeyVSwitchNodesPrefix+suffix)
+			if dualStack {
+				outputVarKeys = append(outputVarKeys, TerraformerOutputKeyVSwitchNodesIPv6Prefix+suffix)
+			}
+		}
+		if natGatewayPerZone {
+			outputVarKeys = append(outputVarKeys, fmt.Sprintf("%s%d", TerraformerOutputKeyNATGatewayPrefix, zoneIndex))
+			if zone.NatGatewayCIDR != nil {
+				outputVarKeys = append(outputVarKeys, fmt.Sprintf("%s%d", TerraformerOutputKeyNATGatewayVSwitchPrefix, zoneIndex))
+			}
+			for eipIndex := 0; eipIndex < eipCount(infraConfig); eipIndex++ {
+				outputVarKeys = append(outputVarKeys,
+					fmt.Sprintf("%s%d_%d", TerraformerOutputKeyNATGatewayEIPPrefix, zoneIndex, eipIndex),
+					fmt.Sprintf("%s%d_%d", TerraformerOutputKeyNATGatewayEIPIPAddressPrefix, zoneIndex, eipIndex),
+				)
+			}
+		}
+		if zone.PodsCIDR != nil {
+			outputVarKeys = append(outputVarKeys, fmt.Sprintf("%s%d", TerraformerOutputKeyVSwitchPodsPrefix, zoneIndex))
+		}
 	}
 
 	vars, err := tf.GetStateOutputVariables(outputVarKeys...)
@@ -230,11 +292,37 @@ func (a *actuator) extractStatus(tf terraformer.Terraformer, infraConfig *aliclo
 		return nil, err
 	}
 
+	zones, err := extractZones(tf, infraConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	var natGateways []alicloudv1alpha1.NatGatewayStatus
+	if natGatewayPerZone {
+		for zoneIndex, zone := range infraConfig.Networks.Zones {
+			var eips []alicloudv1alpha1.EIPStatus
+			for eipIndex := 0; eipIndex < eipCount(infraConfig); eipIndex++ {
+				eips = append(eips, alicloudv1alpha1.EIPStatus{
+					AllocationID: vars[fmt.Sprintf("%s%d_%d", TerraformerOutputKeyNATGatewayEIPPrefix, zoneIndex, eipIndex)],
+					IPAddress:    vars[fmt.Sprintf("%s%d_%d", TerraformerOutputKeyNATGatewayEIPIPAddressPrefix, zoneIndex, eipIndex)],
+				})
+			}
+			natGateways = append(natGateways, alicloudv1alpha1.NatGatewayStatus{
+				ID:        vars[fmt.Sprintf("%s%d", TerraformerOutputKeyNATGatewayPrefix, zoneIndex)],
+				Zone:      zone.Name,
+				VSwitchID: vars[fmt.Sprintf("%s%d", TerraformerOutputKeyNATGatewayVSwitchPrefix, zoneIndex)],
+				EIPs:      eips,
+			})
+		}
+	}
+
 	return &alicloudv1alpha1.InfrastructureStatus{
 		TypeMeta: StatusTypeMeta,
 		VPC: alicloudv1alpha1.VPCStatus{
-			ID:        vars[TerraformerOutputKeyVPCID],
-			VSwitches: vswitches,
+			ID:          vars[TerraformerOutputKeyVPCID],
+			IPv6CIDR:    vars[TerraformerOutputKeyVPCIPv6CIDR],
+			VSwitches:   vswitches,
+			NatGateways: natGateways,
 			SecurityGroups: []alicloudv1alpha1.SecurityGroup{
 				{
 					Purpose: alicloudv1alpha1.PurposeNodes,
@@ -244,41 +332,85 @@ func (a *actuator) extractStatus(tf terraformer.Terraformer, infraConfig *aliclo
 		},
 		KeyPairName:   vars[TerraformerOutputKeyKeyPairName],
 		MachineImages: machineImages,
+		Zones:         zones,
 	}, nil
 }
 
+// extractZones returns the availability zones which Alicloud reports for the vswitches of the zones. States which
+// were applied before these outputs existed have none, the zones are then omitted until Terraform is applied again.
+func extractZones(tf terraformer.Terraformer, infraConfig *alicloudv1alpha1.InfrastructureConfig) ([]alicloudv1alpha1.ZoneStatus, error) {
+	if len(infraConfig.Networks.Zones) == 0 {
+		return nil, nil
+	}
+
+	var outputVarKeys []string
+	for zoneIndex := range infraConfig.Networks.Zones {
+		outputVarKeys = append(outputVarKeys, fmt.Sprintf("%s%d", TerraformerOutputKeyZoneIDPrefix, zoneIndex))
+	}
+
+	vars, err := tf.GetStateOutputVariables(outputVarKeys...)
+	if err != nil {
+		if terraformer.IsVariablesNotFoundError(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var zones []alicloudv1alpha1.ZoneStatus
+	for zoneIndex, zone := range infraConfig.Networks.Zones {
+		zones = append(zones, alicloudv1alpha1.ZoneStatus{
+			Name:   zone.Name,
+			ZoneID: vars[fmt.Sprintf("%s%d", TerraformerOutputKeyZoneIDPrefix, zoneIndex)],
+		})
+	}
+	return zones, nil
+}
+
 func computeProviderStatusVSwitches(infrastructure *alicloudv1alpha1.InfrastructureConfig, values map[string]string) ([]alicloudv1alpha1.VSwitch, error) {
 	var vswitchesToReturn []alicloudv1alpha1.VSwitch
 
-	for key, value := range values {
-		var (
-			prefix  string
-			purpose alicloudv1alpha1.Purpose
-		)
+	for zoneIndex, zone := range infrastructure.Networks.Zones {
+		for vswitchIndex := 0; vswitchIndex <= len(zone.AdditionalWorkers); vswitchIndex++ {
+			suffix := VSwitchOutputKeySuffix(zoneIndex, vswitchIndex)
 
-		if strings.HasPrefix(key, TerraformerOutputKeyVSwitchNodesPrefix) {
-			prefix = TerraformerOutputKeyVSwitchNodesPrefix
-			purpose = alicloudv1alpha1.PurposeNodes
-		}
+			id, ok := values[TerraformerOutputKeyVSwitchNodesPrefix+suffix]
+			if !ok {
+				return nil, fmt.Errorf("no output found for vswitch %d in zone %q", vswitchIndex, zone.Name)
+			}
 
-		if len(prefix) == 0 {
-			continue
+			vswitchesToReturn = append(vswitchesToReturn, alicloudv1alpha1.VSwitch{
+				ID:       id,
+				Purpose:  alicloudv1alpha1.PurposeNodes,
+				Zone:     zone.Name,
+				IPv6CIDR: values[TerraformerOutputKeyVSwitchNodesIPv6Prefix+suffix],
+			})
 		}
 
-		zoneID, err := strconv.Atoi(strings.TrimPrefix(key, prefix))
-		if err != nil {
-			return nil, err
+		if zone.PodsCIDR != nil {
+			id, ok := values[fmt.Sprintf("%s%d", TerraformerOutputKeyVSwitchPodsPrefix, zoneIndex)]
+			if !ok {
+				return nil, fmt.Errorf("no output found for pods vswitch in zone %q", zone.Name)
+			}
+
+			vswitchesToReturn = append(vswitchesToReturn, alicloudv1alpha1.VSwitch{
+				ID:      id,
+				Purpose: alicloudv1alpha1.PurposePods,
+				Zone:    zone.Name,
+			})
 		}
-		vswitchesToReturn = append(vswitchesToReturn, alicloudv1alpha1.VSwitch{
-			ID:      value,
-			Purpose: purpose,
-			Zone:    infrastructure.Networks.Zones[zoneID].Name,
-		})
 	}
 
 	return vswitchesToReturn, nil
 }
 
+func isDualStackEnabled(config *alicloudv1alpha1.InfrastructureConfig) bool {
+	return config.Networks.DualStack != nil && config.Networks.DualStack.Enabled
+}
+
+func isNATGatewayPerZone(config *alicloudv1alpha1.InfrastructureConfig) bool {
+	return config.Networks.NatGateway != nil && config.Networks.NatGateway.PerZone
+}
+
 // findMachineImage takes a list of machine images and tries to find the first entry
 // whose name and version matches with the given name and version. If no such entry is
 // found then an error will be returned.
@@ -303,7 +435,8 @@ func appendMachineImage(machineImages []alicloudv1alpha1.MachineImage, machineIm
 // returned.
 func (a *actuator) shareCustomizedImages(ctx context.Context, infra *extensionsv1alpha1.Infrastructure, cluster *extensioncontroller.Cluster) ([]alicloudv1alpha1.MachineImage, error) {
 	var (
-		machineImages []alicloudv1alpha1.MachineImage
+		machineImages              []alicloudv1alpha1.MachineImage
+		machineImageOwnerECSClient alicloudclient.ECS
 	)
 
 	_, shootCloudProviderCredentials, err := a.getConfigAndCredentialsForInfra(ctx, infra)
@@ -311,12 +444,12 @@ func (a *actuator) shareCustomizedImages(ctx context.Context, infra *extensionsv
 		return nil, err
 	}
 	a.logger.Info("Creating Alicloud ECS client for Shoot", "infrastructure", infra.Name)
-	shootAlicloudECSClient, err := a.newClientFactory.NewECSClient(ctx, infra.Spec.Region, shootCloudProviderCredentials.AccessKeyID, shootCloudProviderCredentials.AccessKeySecret)
+	shootAlicloudECSClient, err := a.newClientFactory.NewECSClient(ctx, infra.Spec.Region, shootCloudProviderCredentials)
 	if err != nil {
 		return nil, err
 	}
 	a.logger.Info("Creating Alicloud STS client for Shoot", "infrastructure", infra.Name)
-	shootAlicloudSTSClient, err := a.newClientFactory.NewSTSClient(ctx, infra.Spec.Region, shootCloudProviderCredentials.AccessKeyID, shootCloudProviderCredentials.AccessKeySecret)
+	shootAlicloudSTSClient, err := a.newClientFactory.NewSTSClient(ctx, infra.Spec.Region, shootCloudProviderCredentials)
 	if err != nil {
 		return nil, err
 	}
@@ -331,17 +464,32 @@ func (a *actuator) shareCustomizedImages(ctx context.Context, infra *extensionsv
 		return nil, err
 	}
 
+	workerConfigs, err := helper.WorkerConfigsFromCluster(cluster)
+	if err != nil {
+		return nil, err
+	}
+
 	a.logger.Info("Sharing customized image with Shoot's Alicloud account from Seed", "infrastructure", infra.Name)
 	for _, worker := range cluster.Shoot.Spec.Provider.Workers {
+		// Custom images of a worker pool are not taken from the cloud profile and hence need not be shared.
+		if workerConfig, ok := workerConfigs[worker.Name]; ok && workerConfig.ImageID != nil {
+			continue
+		}
+
 		imageID, err := helper.FindImageForRegionFromCloudProfile(cloudProfileConfig, worker.Machine.Image.Name, worker.Machine.Image.Version, infra.Spec.Region)
 		if err != nil {
+			// Images without mapping for the region are looked up by their name in the shoot's account by the worker
+			// controller, hence they need not be shared.
+			if version, err := helper.FindMachineImageVersion(cloudProfileConfig, worker.Machine.Image.Name, worker.Machine.Image.Version); err == nil && version.ImageName != nil {
+				continue
+			}
 			if providerStatus := infra.Status.ProviderStatus; providerStatus != nil {
 				infrastructureStatus := &apisalicloud.InfrastructureStatus{}
 				if _, _, err := a.Decoder().Decode(providerStatus.Raw, nil, infrastructureStatus); err != nil {
 					return nil, errors.Wrapf(err, "could not decode infrastructure status of infrastructure '%s'", util.ObjectName(infra))
 				}
 
-				machineImage, err := helper.FindMachineImage(infrastructureStatus.MachineImages, worker.Machine.Image.Name, worker.Machine.Image.Version)
+				machineImage, err := helper.FindMachineImage(infrastructureStatus.MachineImages, worker.Machine.Image.Name, worker.Machine.Image.Version, false)
 				if err != nil {
 					return nil, err
 				}
@@ -363,10 +511,12 @@ func (a *actuator) shareCustomizedImages(ctx context.Context, infra *extensionsv
 		if exists {
 			continue
 		}
-		if a.alicloudECSClient == nil {
-			return nil, fmt.Errorf("image sharing is not enabled or configured correctly and Alicloud ECS client is not instantiated in Seed. Please contact Gardener administrator")
+		if machineImageOwnerECSClient == nil {
+			if machineImageOwnerECSClient, err = a.newMachineImageOwnerECSClient(ctx); err != nil {
+				return nil, err
+			}
 		}
-		if err := a.alicloudECSClient.ShareImageToAccount(ctx, infra.Spec.Region, imageID, shootCloudProviderAccountID); err != nil {
+		if err := machineImageOwnerECSClient.ShareImageToAccount(ctx, infra.Spec.Region, imageID, shootCloudProviderAccountID); err != nil {
 			return nil, err
 		}
 	}
@@ -374,19 +524,127 @@ func (a *actuator) shareCustomizedImages(ctx context.Context, infra *extensionsv
 	return machineImages, nil
 }
 
+// checkWorkerSecurityGroups checks that the additional security groups of all worker pools exist in the VPC of the
+// infrastructure, as instances can only join security groups of their VPC.
+func (a *actuator) checkWorkerSecurityGroups(ctx context.Context, infra *extensionsv1alpha1.Infrastructure, cluster *extensioncontroller.Cluster, credentials *alicloud.Credentials, vpcID string) error {
+	workerConfigs, err := helper.WorkerConfigsFromCluster(cluster)
+	if err != nil {
+		return err
+	}
+
+	var ecsClient alicloudclient.ECS
+	for _, worker := range cluster.Shoot.Spec.Provider.Workers {
+		workerConfig, ok := workerConfigs[worker.Name]
+		if !ok {
+			continue
+		}
+
+		for _, securityGroupID := range workerConfig.SecurityGroupIDs {
+			if ecsClient == nil {
+				if ecsClient, err = a.newClientFactory.NewECSClient(ctx, infra.Spec.Region, credentials); err != nil {
+					return err
+				}
+			}
+
+			securityGroup, err := ecsClient.GetSecurityGroup(ctx, securityGroupID)
+			if err != nil {
+				return err
+			}
+			if securityGroup == nil {
+				return fmt.Errorf("security group %s of worker pool %s does not exist", securityGroupID, worker.Name)
+			}
+			if securityGroup.VpcId != vpcID {
+				return fmt.Errorf("security group %s of worker pool %s belongs to VPC %s instead of the VPC %s of the shoot", securityGroupID, worker.Name, securityGroup.VpcId, vpcID)
+			}
+		}
+	}
+
+	return nil
+}
+
 // Reconcile implements infrastructure.Actuator.
 func (a *actuator) Reconcile(ctx context.Context, infra *extensionsv1alpha1.Infrastructure, cluster *extensioncontroller.Cluster) error {
+	if err := a.validateConfigAgainstRegion(infra); err != nil {
+		return err
+	}
+
 	config, credentials, err := a.getConfigAndCredentialsForInfra(ctx, infra)
 	if err != nil {
 		return err
 	}
 
-	tf, err := a.newTerraformer(infra, credentials)
+	if IsDryRun(infra) {
+		return a.planWithFlow(ctx, infra, cluster, config, credentials)
+	}
+
+	if ShouldExport(infra) {
+		if err := a.exportInfrastructure(ctx, infra, config); err != nil {
+			return errors.Wrapf(err, "failed to export the infrastructure")
+		}
+	}
+
+	if ShouldAdopt(infra) {
+		if err := a.adoptWithFlow(ctx, infra, cluster, config, credentials); err != nil {
+			return err
+		}
+	}
+
+	specHash, err := ComputeSpecHash(infra, config, credentials, cluster)
 	if err != nil {
 		return err
 	}
+	if ShouldSkipReconcile(infra, specHash) {
+		a.logger.Info("Infrastructure is unchanged since the last successful reconciliation, only refreshing the status", "infrastructure", infra.Name)
+		return a.refreshStatus(ctx, infra, config, credentials)
+	}
 
-	initializerValues, err := a.getInitializerValues(tf, infra, config, credentials)
+	if ShouldUseFlow(infra) {
+		err = a.reconcileWithFlow(ctx, infra, cluster, config, credentials)
+	} else {
+		err = a.reconcileWithTerraform(ctx, infra, cluster, config, credentials)
+	}
+	if updateErr := a.updateQuotaExceededCondition(ctx, infra, err); updateErr != nil {
+		a.logger.Error(updateErr, "failed to update the QuotaExceeded condition", "infrastructure", infra.Name)
+	}
+	if err != nil {
+		return err
+	}
+
+	return a.persistSpecHash(ctx, infra, specHash)
+}
+
+// errOnlySupportedByFlow returns an error stating that the given settings are only supported by the flow reconciler.
+func errOnlySupportedByFlow(settings string) error {
+	return fmt.Errorf("%s are only supported by the flow reconciler, which is enabled with the annotation %s=true", settings, AnnotationKeyUseFlow)
+}
+
+func (a *actuator) reconcileWithTerraform(ctx context.Context, infra *extensionsv1alpha1.Infrastructure, cluster *extensioncontroller.Cluster, config *alicloudv1alpha1.InfrastructureConfig, credentials *alicloud.Credentials) error {
+	// The alicloud provider of the Terraformer image does not support DHCP options sets, enhanced NAT gateways, flow logs
+	// and IPv6.
+	if config.Networks.DHCPOptions != nil {
+		return errOnlySupportedByFlow("DHCP options")
+	}
+	if isDualStackEnabled(config) {
+		return errOnlySupportedByFlow("dual-stack VPCs")
+	}
+	if config.Networks.EnableFlowLogs {
+		return errOnlySupportedByFlow("flow logs")
+	}
+	if config.Networks.NatGateway != nil && config.Networks.NatGateway.PerZone {
+		return errOnlySupportedByFlow("NAT gateways per zone")
+	}
+	for _, zone := range config.Networks.Zones {
+		if zone.NatGatewayCIDR != nil {
+			return errOnlySupportedByFlow("dedicated vswitches of NAT gateways")
+		}
+	}
+
+	tf, err := a.newTerraformer(ctx, infra, credentials)
+	if err != nil {
+		return err
+	}
+
+	initializerValues, err := a.getInitializerValues(ctx, tf, infra, config, credentials)
 	if err != nil {
 		return err
 	}
@@ -396,14 +654,37 @@ func (a *actuator) Reconcile(ctx context.Context, infra *extensionsv1alpha1.Infr
 		return err
 	}
 
-	if err := tf.InitializeWith(initializer).Apply(); err != nil {
+	if err := instrumentPhase(PhaseTerraform, func(_ context.Context) error {
+		return tf.InitializeWith(initializer).Apply()
+	})(ctx); err != nil {
 		a.logger.Error(err, "failed to apply the terraform config", "infrastructure", infra.Name)
+		if persistErr := a.persistTerraformState(ctx, tf, infra); persistErr != nil {
+			a.logger.Error(persistErr, "failed to persist the terraform state", "infrastructure", infra.Name)
+		}
 		return &controllererrors.RequeueAfterError{
 			Cause:        err,
 			RequeueAfter: 30 * time.Second,
 		}
 	}
 
+	state, err := tf.GetRawState(ctx)
+	if err != nil {
+		return err
+	}
+
+	resourceState, err := importTerraformState(state.Data)
+	if err != nil {
+		return err
+	}
+
+	if err := a.tagTerraformResources(ctx, infra, cluster, config, credentials, resourceState); err != nil {
+		return errors.Wrapf(err, "failed to tag the infrastructure resources")
+	}
+
+	if err := a.reconcileTerraformDNATEntries(ctx, infra, config, credentials, resourceState, dnatEntries(config)); err != nil {
+		return errors.Wrapf(err, "failed to reconcile the DNAT entries")
+	}
+
 	machineImages, err := a.shareCustomizedImages(ctx, infra, cluster)
 	if err != nil {
 		return errors.Wrapf(err, "failed to share the machine images")
@@ -414,10 +695,22 @@ func (a *actuator) Reconcile(ctx context.Context, infra *extensionsv1alpha1.Infr
 		return err
 	}
 
-	state, err := tf.GetRawState(ctx)
-	if err != nil {
-		return err
+	if err := a.checkSecurityGroup(ctx, infra, config, credentials, status.VPC.ID); err != nil {
+		return errors.Wrapf(err, "failed to check the security group")
+	}
+	if err := a.checkWorkerSecurityGroups(ctx, infra, cluster, credentials, status.VPC.ID); err != nil {
+		return errors.Wrapf(err, "failed to check the security groups of the worker pools")
 	}
+	if err := a.correctSecurityGroupDrift(ctx, infra, config, credentials, status); err != nil {
+		return errors.Wrapf(err, "failed to correct the drifted security group rules")
+	}
+
+	natGatewayID := resourceState.Get(IdentifierNATGateway)
+	if !initializerValues.CreateVPC {
+		natGatewayID = initializerValues.NATGatewayID
+	}
+	a.recordReconcileEvents(infra, status, natGatewayID)
+
 	stateByte, err := state.Marshal()
 	if err != nil {
 		return err
@@ -430,13 +723,85 @@ func (a *actuator) Reconcile(ctx context.Context, infra *extensionsv1alpha1.Infr
 	})
 }
 
+// persistTerraformState records the resources which Terraform has created so far in the state of the given
+// Infrastructure, e.g. if the Terraform config could only be applied partially. This way, the state always contains
+// the resources which have to be deleted, also if the Infrastructure is migrated to the flow reconciler before the
+// next successful reconciliation.
+func (a *actuator) persistTerraformState(ctx context.Context, tf terraformer.Terraformer, infra *extensionsv1alpha1.Infrastructure) error {
+	state, err := tf.GetRawState(ctx)
+	if err != nil {
+		return err
+	}
+	if len(state.Data) == 0 {
+		return nil
+	}
+
+	stateBytes, err := state.Marshal()
+	if err != nil {
+		return err
+	}
+
+	return extensioncontroller.TryUpdateStatus(ctx, retry.DefaultBackoff, a.Client(), infra, func() error {
+		infra.Status.State = &runtime.RawExtension{Raw: stateBytes}
+		return nil
+	})
+}
+
+// tagTerraformResources tags the resources created by Terraform. The given state contains the IDs imported from the Terraform state.
+func (a *actuator) tagTerraformResources(
+	ctx context.Context,
+	infra *extensionsv1alpha1.Infrastructure,
+	cluster *extensioncontroller.Cluster,
+	config *alicloudv1alpha1.InfrastructureConfig,
+	credentials *alicloud.Credentials,
+	state *FlowState,
+) error {
+	vpcClient, err := a.alicloudClientFactory.NewVPC(ctx, infra.Spec.Region, credentials)
+	if err != nil {
+		return err
+	}
+
+	ecsClient, err := a.newClientFactory.NewECSClient(ctx, infra.Spec.Region, credentials)
+	if err != nil {
+		return err
+	}
+
+	return tagResources(ctx, vpcClient, ecsClient, config, state, ComputeTags(config, cluster))
+}
+
+// reconcileTerraformDNATEntries reconciles the given DNAT entries of the NAT gateways used by Terraform. The given state
+// contains the IDs imported from the Terraform state, the NAT gateway of an existing VPC is looked up.
+func (a *actuator) reconcileTerraformDNATEntries(
+	ctx context.Context,
+	infra *extensionsv1alpha1.Infrastructure,
+	config *alicloudv1alpha1.InfrastructureConfig,
+	credentials *alicloud.Credentials,
+	state *FlowState,
+	entries []alicloudv1alpha1.DNATEntry,
+) error {
+	vpcClient, err := a.alicloudClientFactory.NewVPC(ctx, infra.Spec.Region, credentials)
+	if err != nil {
+		return err
+	}
+
+	if config.Networks.VPC.ID != nil {
+		vpcInfo, err := getExistingVPCInfo(vpcClient, config)
+		if err != nil {
+			return err
+		}
+		state.Set(IdentifierNATGateway, vpcInfo.NATGatewayID)
+	}
+
+	return reconcileDNATEntries(vpcClient, dnatEntryName(infra.Namespace), dnatNATGatewayIDs(config, state), entries)
+}
+
 func (a *actuator) cleanupServiceLoadBalancers(ctx context.Context, infra *extensionsv1alpha1.Infrastructure) error {
 	_, shootCloudProviderCredentials, err := a.getConfigAndCredentialsForInfra(ctx, infra)
 	if err != nil {
 		return err
 	}
 	a.logger.Info("Creating Alicloud SLB client for Shoot", "infrastructure", infra.Name)
-	shootAlicloudSLBClient, err := a.newClientFactory.NewSLBClient(ctx, infra.Spec.Region, shootCloudProviderCredentials.AccessKeyID, shootCloudProviderCredentials.AccessKeySecret)
+	shootAlicloudSLBClient, err := a.newClientFactory.NewSLBClient(ctx, infra.Spec.Region, shootCloudProviderCredentials)
 	if err != nil {
 		return err
 	}
@@ -471,12 +836,16 @@ func (a *actuator) cleanupServiceLoadBalancers(ctx context.Context, infra *exten
 
 // Delete implements infrastructure.Actuator.
 func (a *actuator) Delete(ctx context.Context, infra *extensionsv1alpha1.Infrastructure, cluster *extensioncontroller.Cluster) error {
-	_, credentials, err := a.getConfigAndCredentialsForInfra(ctx, infra)
+	config, credentials, err := a.getConfigAndCredentialsForInfra(ctx, infra)
 	if err != nil {
 		return err
 	}
 
-	tf, err := a.newTerraformer(infra, credentials)
+	if ShouldUseFlow(infra) {
+		return a.deleteWithFlow(ctx, infra, cluster, config, credentials)
+	}
+
+	tf, err := a.newTerraformer(ctx, infra, credentials)
 	if err != nil {
 		return err
 	}
@@ -489,6 +858,11 @@ func (a *actuator) Delete(ctx context.Context, infra *extensionsv1alpha1.Infrast
 		return nil
 	}
 
+	resourceState, err := FlowStateFromInfrastructureState(infra.Status.State)
+	if err != nil {
+		return err
+	}
+
 	var (
 		g = flow.NewGraph("Alicloud infrastructure destruction")
 
@@ -499,10 +873,18 @@ func (a *actuator) Delete(ctx context.Context, infra *extensionsv1alpha1.Infrast
 			}).RetryUntilTimeout(10*time.Second, 5*time.Minute),
 		})
 
+		// The EIPs cannot be unassociated from the NAT gateway as long as DNAT entries use them.
+		deleteDNATEntries = g.Add(flow.Task{
+			Name: "Deleting DNAT entries",
+			Fn: flow.TaskFn(func(ctx context.Context) error {
+				return a.reconcileTerraformDNATEntries(ctx, infra, config, credentials, resourceState, nil)
+			}).RetryUntilTimeout(10*time.Second, 5*time.Minute),
+		})
+
 		_ = g.Add(flow.Task{
 			Name:         "Destroying Shoot infrastructure",
 			Fn:           flow.SimpleTaskFn(tf.Destroy),
-			Dependencies: flow.NewTaskIDs(destroyServiceLoadBalancers),
+			Dependencies: flow.NewTaskIDs(destroyServiceLoadBalancers, deleteDNATEntries),
 		})
 
 		f = g.Compile()
@@ -511,5 +893,84 @@ func (a *actuator) Delete(ctx context.Context, infra *extensionsv1alpha1.Infrast
 	if err := f.Run(flow.Opts{Context: ctx}); err != nil {
 		return flow.Causes(err)
 	}
+
+	a.recordDeleteEvent(infra, managedResourceIDs(config, resourceState))
+	return nil
+}
+
+func (a *actuator) newFlowReconciler(ctx context.Context, infra *extensionsv1alpha1.Infrastructure, cluster *extensioncontroller.Cluster, config *alicloudv1alpha1.InfrastructureConfig, credentials *alicloud.Credentials) (*flowReconciler, error) {
+	vpcClient, err := a.alicloudClientFactory.NewVPC(ctx, infra.Spec.Region, credentials)
+	if err != nil {
+		return nil, err
+	}
+
+	ecsClient, err := a.newClientFactory.NewECSClient(ctx, infra.Spec.Region, credentials)
+	if err != nil {
+		return nil, err
+	}
+
+	return newFlowReconciler(a.Client(), infra, config, vpcClient, ecsClient, ComputeTags(config, cluster))
+}
+
+func (a *actuator) reconcileWithFlow(ctx context.Context, infra *extensionsv1alpha1.Infrastructure, cluster *extensioncontroller.Cluster, config *alicloudv1alpha1.InfrastructureConfig, credentials *alicloud.Credentials) error {
+	reconciler, err := a.newFlowReconciler(ctx, infra, cluster, config, credentials)
+	if err != nil {
+		return err
+	}
+
+	status, err := reconciler.Reconcile(ctx)
+	if err != nil {
+		a.logger.Error(err, "failed to reconcile the infrastructure with the flow reconciler", "infrastructure", infra.Name)
+		return &controllererrors.RequeueAfterError{
+			Cause:        err,
+			RequeueAfter: 30 * time.Second,
+		}
+	}
+	a.recordReconcileEvents(infra, status, reconciler.state.Get(IdentifierNATGateway))
+
+	machineImages, err := a.shareCustomizedImages(ctx, infra, cluster)
+	if err != nil {
+		return errors.Wrapf(err, "failed to share the machine images")
+	}
+	status.MachineImages = machineImages
+
+	if err := a.checkSecurityGroup(ctx, infra, config, credentials, status.VPC.ID); err != nil {
+		return errors.Wrapf(err, "failed to check the security group")
+	}
+	if err := a.checkWorkerSecurityGroups(ctx, infra, cluster, credentials, status.VPC.ID); err != nil {
+		return errors.Wrapf(err, "failed to check the security groups of the worker pools")
+	}
+	if err := a.correctSecurityGroupDrift(ctx, infra, config, credentials, status); err != nil {
+		return errors.Wrapf(err, "failed to correct the drifted security group rules")
+	}
+
+	stateBytes, err := reconciler.state.Marshal()
+	if err != nil {
+		return err
+	}
+
+	return extensioncontroller.TryUpdateStatus(ctx, retry.DefaultBackoff, a.Client(), infra, func() error {
+		infra.Status.ProviderStatus = &runtime.RawExtension{Object: status}
+		infra.Status.State = &runtime.RawExtension{Raw: stateBytes}
+		return nil
+	})
+}
+
+func (a *actuator) deleteWithFlow(ctx context.Context, infra *extensionsv1alpha1.Infrastructure, cluster *extensioncontroller.Cluster, config *alicloudv1alpha1.InfrastructureConfig, credentials *alicloud.Credentials) error {
+	reconciler, err := a.newFlowReconciler(ctx, infra, cluster, config, credentials)
+	if err != nil {
+		return err
+	}
+
+	// The state is emptied during the deletion, hence the IDs of the deleted resources are remembered beforehand.
+	resourceIDs := managedResourceIDs(config, reconciler.state)
+
+	if err := reconciler.Delete(ctx, func(ctx context.Context) error {
+		return a.cleanupServiceLoadBalancers(ctx, infra)
+	}); err != nil {
+		return err
+	}
+
+	a.recordDeleteEvent(infra, resourceIDs)
 	return nil
 }
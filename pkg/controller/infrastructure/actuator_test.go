@@ -1,4 +1,4 @@
-// Copyright (c) 2019 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
 //
 // Licensed under the Apache License, Version 2.0 (the "License");
 // you may not use this file except in compliance with the License.
@@ -38,6 +38,7 @@ import (
 	"github.com/gardener/gardener-extensions/pkg/util/chart"
 	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
 
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/ecs"
 	"github.com/aliyun/alibaba-cloud-sdk-go/services/vpc"
 	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
 	"github.com/gardener/gardener/pkg/chartrenderer"
@@ -45,11 +46,14 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/serializer/json"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/helm/pkg/manifest"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
 	"sigs.k8s.io/controller-runtime/pkg/runtime/inject"
 )
 
@@ -58,6 +62,29 @@ func ExpectInject(ok bool, err error) {
 	Expect(ok).To(BeTrue(), "no injection happened")
 }
 
+// phaseCount returns the number of runs of the given reconcile phase with the given result recorded in the
+// controller-runtime metrics registry.
+func phaseCount(phase, result string) uint64 {
+	families, err := metrics.Registry.Gather()
+	Expect(err).NotTo(HaveOccurred())
+
+	for _, family := range families {
+		if family.GetName() != "alicloud_infrastructure_reconcile_phase_duration_seconds" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			labels := map[string]string{}
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			if labels["phase"] == phase && labels["result"] == result {
+				return metric.GetHistogram().GetSampleCount()
+			}
+		}
+	}
+	return 0
+}
+
 func ExpectEncode(data []byte, err error) []byte {
 	Expect(err).NotTo(HaveOccurred())
 	Expect(data).NotTo(BeNil())
@@ -102,11 +129,14 @@ var _ = Describe("Actuator", func() {
 					terraformerFactory       = mockterraformer.NewMockFactory(ctrl)
 					terraformer              = mockterraformer.NewMockTerraformer(ctrl)
 					shootECSClient           = mockalicloudclient.NewMockECS(ctrl)
+					ecsClient                = mockalicloudclient.NewMockECS(ctrl)
 					shootSTSClient           = mockalicloudclient.NewMockSTS(ctrl)
 					chartRendererFactory     = mockchartrenderer.NewMockFactory(ctrl)
 					terraformChartOps        = mockinfrastructure.NewMockTerraformChartOps(ctrl)
+					recorder                 = record.NewFakeRecorder(10)
 					actuator                 = NewActuatorWithDeps(
 						logger,
+						recorder,
 						newAlicloudClientFactory,
 						alicloudClientFactory,
 						terraformerFactory,
@@ -148,6 +178,10 @@ var _ = Describe("Actuator", func() {
 					accessKeySecret = "accessKeySecret"
 					cluster         = controller.Cluster{
 						Shoot: &gardencorev1beta1.Shoot{
+							ObjectMeta: metav1.ObjectMeta{
+								Namespace: "garden-project",
+								Name:      "shoot",
+							},
 							Spec: gardencorev1beta1.ShootSpec{
 								Region: region,
 							},
@@ -167,11 +201,26 @@ var _ = Describe("Actuator", func() {
 					securityGroupID = "sgID"
 					keyPairName     = "keyPairName"
 					rawState        = &realterraformer.RawState{
-						Data:     "",
+						Data: `{"version": 4, "resources": [
+  {"mode": "managed", "type": "alicloud_vpc", "name": "vpc", "instances": [{"attributes": {"id": "vpcID"}}]},
+  {"mode": "managed", "type": "alicloud_security_group", "name": "sg", "instances": [{"attributes": {"id": "sgID"}}]}
+]}`,
 						Encoding: "none",
 					}
+					tags = map[string]string{
+						alicloud.TagKeyShootName:   "shoot",
+						alicloud.TagKeyProjectName: "project",
+					}
 				)
 
+				tagVPCReq := vpc.CreateTagResourcesRequest()
+				tagVPCReq.ResourceType = "VPC"
+				tagVPCReq.ResourceId = &[]string{vpcID}
+				tagVPCReq.Tag = &[]vpc.TagResourcesTag{
+					{Key: alicloud.TagKeyProjectName, Value: "project"},
+					{Key: alicloud.TagKeyShootName, Value: "shoot"},
+				}
+
 				describeNATGatewaysReq := vpc.CreateDescribeNatGatewaysRequest()
 				describeNATGatewaysReq.VpcId = vpcID
 
@@ -197,7 +246,7 @@ var _ = Describe("Actuator", func() {
 					terraformer.EXPECT().SetDeadlineCleaning(5*time.Minute).Return(terraformer),
 					terraformer.EXPECT().SetDeadlinePod(15*time.Minute).Return(terraformer),
 
-					alicloudClientFactory.EXPECT().NewVPC(region, accessKeyID, accessKeySecret).Return(vpcClient, nil),
+					alicloudClientFactory.EXPECT().NewVPC(ctx, region, &alicloud.Credentials{AccessKeyID: accessKeyID, AccessKeySecret: accessKeySecret}).Return(vpcClient, nil),
 
 					terraformer.EXPECT().GetStateOutputVariables(TerraformerOutputKeyVPCID).
 						Return(map[string]string{
@@ -236,6 +285,13 @@ var _ = Describe("Actuator", func() {
 
 					terraformer.EXPECT().Apply(),
 
+					terraformer.EXPECT().GetRawState(ctx).Return(rawState, nil),
+					alicloudClientFactory.EXPECT().NewVPC(ctx, region, &alicloud.Credentials{AccessKeyID: accessKeyID, AccessKeySecret: accessKeySecret}).Return(vpcClient, nil),
+					newAlicloudClientFactory.EXPECT().NewECSClient(ctx, region, &alicloud.Credentials{AccessKeyID: accessKeyID, AccessKeySecret: accessKeySecret}).Return(ecsClient, nil),
+					vpcClient.EXPECT().TagResources(tagVPCReq),
+					ecsClient.EXPECT().TagResources(ctx, "securitygroup", []string{securityGroupID}, tags),
+					alicloudClientFactory.EXPECT().NewVPC(ctx, region, &alicloud.Credentials{AccessKeyID: accessKeyID, AccessKeySecret: accessKeySecret}).Return(vpcClient, nil),
+
 					c.EXPECT().Get(ctx, client.ObjectKey{Namespace: secretNamespace, Name: secretName}, gomock.AssignableToTypeOf(&corev1.Secret{})).
 						SetArg(2, corev1.Secret{
 							Data: map[string][]byte{
@@ -244,9 +300,9 @@ var _ = Describe("Actuator", func() {
 							},
 						}),
 					logger.EXPECT().Info("Creating Alicloud ECS client for Shoot", "infrastructure", infra.Name),
-					newAlicloudClientFactory.EXPECT().NewECSClient(ctx, region, accessKeyID, accessKeySecret).Return(shootECSClient, nil),
+					newAlicloudClientFactory.EXPECT().NewECSClient(ctx, region, &alicloud.Credentials{AccessKeyID: accessKeyID, AccessKeySecret: accessKeySecret}).Return(shootECSClient, nil),
 					logger.EXPECT().Info("Creating Alicloud STS client for Shoot", "infrastructure", infra.Name),
-					newAlicloudClientFactory.EXPECT().NewSTSClient(ctx, region, accessKeyID, accessKeySecret).Return(shootSTSClient, nil),
+					newAlicloudClientFactory.EXPECT().NewSTSClient(ctx, region, &alicloud.Credentials{AccessKeyID: accessKeyID, AccessKeySecret: accessKeySecret}).Return(shootSTSClient, nil),
 					shootSTSClient.EXPECT().GetAccountIDFromCallerIdentity(ctx).Return("", nil),
 					logger.EXPECT().Info("Sharing customized image with Shoot's Alicloud account from Seed", "infrastructure", infra.Name),
 
@@ -257,18 +313,29 @@ var _ = Describe("Actuator", func() {
 							TerraformerOutputKeySecurityGroupID: securityGroupID,
 							TerraformerOutputKeyKeyPairName:     keyPairName,
 						}, nil),
-					terraformer.EXPECT().GetRawState(context.TODO()).Return(rawState, nil),
+					newAlicloudClientFactory.EXPECT().NewECSClient(ctx, region, &alicloud.Credentials{AccessKeyID: accessKeyID, AccessKeySecret: accessKeySecret}).Return(ecsClient, nil),
+					ecsClient.EXPECT().GetSecurityGroupRules(ctx, securityGroupID).Return([]ecs.Permission{
+						{Direction: "ingress", IpProtocol: "TCP", PortRange: "30000/32767", SourceCidrIp: "0.0.0.0/0", Policy: "Accept"},
+						{Direction: "ingress", IpProtocol: "TCP", PortRange: "1/65535", SourceCidrIp: cidr, Policy: "Accept"},
+						{Direction: "ingress", IpProtocol: "UDP", PortRange: "1/65535", SourceCidrIp: cidr, Policy: "Accept"},
+					}, nil),
 					c.EXPECT().Status().Return(c),
 					c.EXPECT().Get(ctx, client.ObjectKey{Namespace: infra.Namespace, Name: infra.Name}, &infra),
 
 					c.EXPECT().Update(ctx, &infra),
+
+					c.EXPECT().Get(ctx, client.ObjectKey{Namespace: infra.Namespace, Name: infra.Name}, &infra),
+					c.EXPECT().Update(ctx, &infra),
 				)
 
 				ExpectInject(inject.ClientInto(c, actuator))
 				ExpectInject(inject.SchemeInto(scheme, actuator))
 				ExpectInject(inject.ConfigInto(&restConfig, actuator))
 
+				terraformRuns := phaseCount(PhaseTerraform, alicloudclient.RequestResultSuccess)
+
 				Expect(actuator.Reconcile(ctx, &infra, &cluster)).To(Succeed())
+				Expect(phaseCount(PhaseTerraform, alicloudclient.RequestResultSuccess)).To(Equal(terraformRuns + 1))
 				Expect(infra.Status.ProviderStatus.Object).To(Equal(&alicloudv1alpha1.InfrastructureStatus{
 					TypeMeta: StatusTypeMeta,
 					VPC: alicloudv1alpha1.VPCStatus{
@@ -282,6 +349,177 @@ var _ = Describe("Actuator", func() {
 					},
 					KeyPairName: keyPairName,
 				}))
+				Expect(recorder.Events).To(Receive(Equal("Normal VPCReady VPC vpcID is ready")))
+
+				specHash, err := ComputeSpecHash(&infra, &config, &alicloud.Credentials{AccessKeyID: accessKeyID, AccessKeySecret: accessKeySecret}, &cluster)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(infra.Annotations).To(HaveKeyWithValue(AnnotationKeySpecHash, specHash))
+			})
+
+			It("should only refresh the status if the infrastructure is unchanged", func() {
+				var (
+					ctx                  = context.TODO()
+					logger               = logr.NewMockLogger(ctrl)
+					terraformerFactory   = mockterraformer.NewMockFactory(ctrl)
+					terraformer          = mockterraformer.NewMockTerraformer(ctrl)
+					chartRendererFactory = mockchartrenderer.NewMockFactory(ctrl)
+					newClientFactory     = mockalicloudclient.NewMockClientFactory(ctrl)
+					ecsClient            = mockalicloudclient.NewMockECS(ctrl)
+					actuator             = NewActuatorWithDeps(
+						logger,
+						record.NewFakeRecorder(10),
+						newClientFactory,
+						mockalicloudclient.NewMockFactory(ctrl),
+						terraformerFactory,
+						chartRendererFactory,
+						mockinfrastructure.NewMockTerraformChartOps(ctrl),
+						nil,
+					)
+					c          = mockclient.NewMockClient(ctrl)
+					restConfig rest.Config
+
+					cidr   = "192.168.0.0/16"
+					config = alicloudv1alpha1.InfrastructureConfig{
+						Networks: alicloudv1alpha1.Networks{
+							VPC: alicloudv1alpha1.VPC{
+								CIDR: &cidr,
+							},
+							Zones: []alicloudv1alpha1.Zone{{Name: "cn-beijing-f", Workers: "192.168.0.0/24"}},
+						},
+					}
+					secretRef    = corev1.SecretReference{Namespace: "secretns", Name: "secret"}
+					credentials  = &alicloud.Credentials{AccessKeyID: "accessKeyID", AccessKeySecret: "accessKeySecret"}
+					machineImage = alicloudv1alpha1.MachineImage{Name: "coreos", Version: "2303.3.0", ID: "m-1234"}
+					infra        = extensionsv1alpha1.Infrastructure{
+						Spec: extensionsv1alpha1.InfrastructureSpec{
+							ProviderConfig: &runtime.RawExtension{
+								Raw: ExpectEncode(runtime.Encode(serializer, &config)),
+							},
+							Region:    "region",
+							SecretRef: secretRef,
+						},
+						Status: extensionsv1alpha1.InfrastructureStatus{
+							ProviderStatus: &runtime.RawExtension{
+								Raw: ExpectEncode(runtime.Encode(serializer, &alicloudv1alpha1.InfrastructureStatus{
+									TypeMeta:      StatusTypeMeta,
+									MachineImages: []alicloudv1alpha1.MachineImage{machineImage},
+								})),
+							},
+						},
+					}
+					cluster = controller.Cluster{
+						Shoot: &gardencorev1beta1.Shoot{
+							ObjectMeta: metav1.ObjectMeta{Namespace: "garden-project", Name: "shoot"},
+						},
+					}
+				)
+
+				specHash, err := ComputeSpecHash(&infra, &config, credentials, &cluster)
+				Expect(err).NotTo(HaveOccurred())
+				infra.Annotations = map[string]string{AnnotationKeySpecHash: specHash}
+
+				gomock.InOrder(
+					chartRendererFactory.EXPECT().NewForConfig(&restConfig),
+					c.EXPECT().Get(ctx, client.ObjectKey{Namespace: secretRef.Namespace, Name: secretRef.Name}, gomock.AssignableToTypeOf(&corev1.Secret{})).
+						SetArg(2, corev1.Secret{
+							Data: map[string][]byte{
+								alicloud.AccessKeyID:     []byte(credentials.AccessKeyID),
+								alicloud.AccessKeySecret: []byte(credentials.AccessKeySecret),
+							},
+						}),
+					logger.EXPECT().Info("Infrastructure is unchanged since the last successful reconciliation, only refreshing the status", "infrastructure", infra.Name),
+					terraformerFactory.EXPECT().NewForConfig(gomock.Any(), &restConfig, TerraformerPurpose, infra.Namespace, infra.Name, imagevector.TerraformerImage()).
+						Return(terraformer, nil),
+					terraformer.EXPECT().SetVariablesEnvironment(gomock.Any()).Return(terraformer),
+					terraformer.EXPECT().SetActiveDeadlineSeconds(gomock.Any()).Return(terraformer),
+					terraformer.EXPECT().SetDeadlineCleaning(gomock.Any()).Return(terraformer),
+					terraformer.EXPECT().SetDeadlinePod(gomock.Any()).Return(terraformer),
+					terraformer.EXPECT().GetStateOutputVariables(TerraformerOutputKeyVPCID, TerraformerOutputKeyVPCCIDR, TerraformerOutputKeySecurityGroupID, TerraformerOutputKeyKeyPairName, TerraformerOutputKeyVSwitchNodesPrefix+"0").
+						Return(map[string]string{
+							TerraformerOutputKeyVPCID:                    "vpcID",
+							TerraformerOutputKeySecurityGroupID:          "sgID",
+							TerraformerOutputKeyKeyPairName:              "keyPairName",
+							TerraformerOutputKeyVSwitchNodesPrefix + "0": "vsw-f",
+						}, nil),
+					terraformer.EXPECT().GetStateOutputVariables(TerraformerOutputKeyZoneIDPrefix+"0").
+						Return(map[string]string{
+							TerraformerOutputKeyZoneIDPrefix + "0": "cn-beijing-f",
+						}, nil),
+					newClientFactory.EXPECT().NewECSClient(ctx, "region", credentials).Return(ecsClient, nil),
+					ecsClient.EXPECT().GetSecurityGroupRules(ctx, "sgID").Return([]ecs.Permission{
+						{Direction: "ingress", IpProtocol: "TCP", PortRange: "30000/32767", SourceCidrIp: "0.0.0.0/0", Policy: "Accept"},
+						{Direction: "ingress", IpProtocol: "TCP", PortRange: "1/65535", SourceCidrIp: cidr, Policy: "Accept"},
+						{Direction: "ingress", IpProtocol: "UDP", PortRange: "1/65535", SourceCidrIp: cidr, Policy: "Accept"},
+					}, nil),
+					c.EXPECT().Status().Return(c),
+					c.EXPECT().Get(ctx, client.ObjectKey{Namespace: infra.Namespace, Name: infra.Name}, &infra),
+					c.EXPECT().Update(ctx, &infra),
+				)
+
+				ExpectInject(inject.ClientInto(c, actuator))
+				ExpectInject(inject.SchemeInto(scheme, actuator))
+				ExpectInject(inject.ConfigInto(&restConfig, actuator))
+
+				Expect(actuator.Reconcile(ctx, &infra, &cluster)).To(Succeed())
+				Expect(infra.Status.ProviderStatus.Object).To(Equal(&alicloudv1alpha1.InfrastructureStatus{
+					TypeMeta: StatusTypeMeta,
+					VPC: alicloudv1alpha1.VPCStatus{
+						ID: "vpcID",
+						VSwitches: []alicloudv1alpha1.VSwitch{
+							{Purpose: alicloudv1alpha1.PurposeNodes, ID: "vsw-f", Zone: "cn-beijing-f"},
+						},
+						SecurityGroups: []alicloudv1alpha1.SecurityGroup{
+							{
+								Purpose: alicloudv1alpha1.PurposeNodes,
+								ID:      "sgID",
+							},
+						},
+					},
+					KeyPairName:   "keyPairName",
+					MachineImages: []alicloudv1alpha1.MachineImage{machineImage},
+					Zones:         []alicloudv1alpha1.ZoneStatus{{Name: "cn-beijing-f", ZoneID: "cn-beijing-f"}},
+				}))
+			})
+
+			It("should fail if dual-stack is enabled in a region without IPv6 support", func() {
+				var (
+					ctx      = context.TODO()
+					actuator = NewActuatorWithDeps(
+						logr.NewMockLogger(ctrl),
+						record.NewFakeRecorder(10),
+						mockalicloudclient.NewMockClientFactory(ctrl),
+						mockalicloudclient.NewMockFactory(ctrl),
+						mockterraformer.NewMockFactory(ctrl),
+						mockchartrenderer.NewMockFactory(ctrl),
+						mockinfrastructure.NewMockTerraformChartOps(ctrl),
+						nil,
+					)
+
+					cidr   = "192.168.0.0/16"
+					config = alicloudv1alpha1.InfrastructureConfig{
+						TypeMeta: metav1.TypeMeta{
+							APIVersion: alicloudv1alpha1.SchemeGroupVersion.String(),
+							Kind:       "InfrastructureConfig",
+						},
+						Networks: alicloudv1alpha1.Networks{
+							VPC:       alicloudv1alpha1.VPC{CIDR: &cidr},
+							DualStack: &alicloudv1alpha1.DualStack{Enabled: true},
+						},
+					}
+					infra = extensionsv1alpha1.Infrastructure{
+						Spec: extensionsv1alpha1.InfrastructureSpec{
+							ProviderConfig: &runtime.RawExtension{
+								Raw: ExpectEncode(runtime.Encode(serializer, &config)),
+							},
+							Region: "ap-south-1",
+						},
+					}
+				)
+
+				ExpectInject(inject.ClientInto(mockclient.NewMockClient(ctrl), actuator))
+				ExpectInject(inject.SchemeInto(scheme, actuator))
+
+				Expect(actuator.Reconcile(ctx, &infra, &controller.Cluster{})).To(MatchError(ContainSubstring(`invalid infrastructure config for region ap-south-1: networks.dualStack.enabled: Forbidden: dual-stack is not supported in region "ap-south-1"`)))
 			})
 		})
 	})
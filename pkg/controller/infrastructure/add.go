@@ -16,6 +16,7 @@ package infrastructure
 
 import (
 	"github.com/gardener/gardener-extension-provider-alicloud/pkg/alicloud"
+	"github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/config"
 	"github.com/gardener/gardener-extensions/pkg/controller/infrastructure"
 
 	corev1 "k8s.io/api/core/v1"
@@ -23,6 +24,9 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 )
 
+// EventRecorderName is the name of the event recorder of the infrastructure controller.
+const EventRecorderName = "alicloud-infrastructure-controller"
+
 var (
 	// DefaultAddOptions are the default AddOptions for AddToManager.
 	DefaultAddOptions = AddOptions{}
@@ -36,13 +40,20 @@ type AddOptions struct {
 	IgnoreOperationAnnotation bool
 	// MachineImageOwnerSecretRef is the secret reference which contains credential of AliCloud subaccount for customized images.
 	MachineImageOwnerSecretRef *corev1.SecretReference
+	// ErrorBackoff is the backoff of the requeues of Infrastructures whose reconciliation or deletion failed.
+	ErrorBackoff *config.Backoff
 }
 
 // AddToManagerWithOptions adds a controller with the given AddOptions to the given manager.
 // The opts.Reconciler is being set with a newly instantiated actuator.
 func AddToManagerWithOptions(mgr manager.Manager, options AddOptions) error {
+	actuator := NewActuator(mgr.GetEventRecorderFor(EventRecorderName), options.MachineImageOwnerSecretRef)
+	if options.ErrorBackoff != nil {
+		actuator = NewErrorBackoffActuator(actuator, options.ErrorBackoff)
+	}
+
 	return infrastructure.Add(mgr, infrastructure.AddArgs{
-		Actuator:          NewActuator(options.MachineImageOwnerSecretRef),
+		Actuator:          actuator,
 		ControllerOptions: options.Controller,
 		Predicates:        infrastructure.DefaultPredicates(options.IgnoreOperationAnnotation),
 		Type:              alicloud.Type,
@@ -0,0 +1,195 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package infrastructure
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/gardener/gardener-extension-provider-alicloud/pkg/alicloud"
+	alicloudv1alpha1 "github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/alicloud/v1alpha1"
+	extensioncontroller "github.com/gardener/gardener-extensions/pkg/controller"
+
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"github.com/pkg/errors"
+)
+
+// AnnotationKeyAdopt is the annotation key on Infrastructure resources that contains the IDs of existing resources
+// which are adopted by the flow reconciler instead of creating new ones. The value is the JSON representation of
+// AdoptedResources. The annotation is only considered for Infrastructures which have not been reconciled yet.
+const AnnotationKeyAdopt = "alicloud.provider.extensions.gardener.cloud/adopt"
+
+// AdoptedResources contains the IDs of existing resources, e.g. created by the in-tree Alicloud provider, which are
+// adopted as managed resources of an Infrastructure.
+type AdoptedResources struct {
+	// VPC is the ID of the VPC. It must have the CIDR of the InfrastructureConfig.
+	VPC string `json:"vpc,omitempty"`
+	// NATGateway is the ID of the NAT gateway of the VPC.
+	NATGateway string `json:"natGateway,omitempty"`
+	// VSwitches maps the zone names to the IDs of the vswitches of the zones. The vswitches must have the CIDRs of
+	// the workers and additional workers of the zone in the same order.
+	VSwitches map[string][]string `json:"vswitches,omitempty"`
+	// SecurityGroup is the ID of the security group of the nodes.
+	SecurityGroup string `json:"securityGroup,omitempty"`
+	// Discover specifies whether the resources which are not given explicitly are discovered by the tag containing
+	// the UID of the shoot.
+	Discover bool `json:"discover,omitempty"`
+}
+
+// ShouldAdopt checks whether the flow reconciler should adopt existing resources for the given Infrastructure.
+func ShouldAdopt(infra *extensionsv1alpha1.Infrastructure) bool {
+	_, ok := infra.Annotations[AnnotationKeyAdopt]
+	return ok && (infra.Status.State == nil || len(infra.Status.State.Raw) == 0)
+}
+
+// Adopt verifies that the given resources exist and match the InfrastructureConfig and records them in the state,
+// so that the next reconciliation manages them instead of creating new resources. The state is left untouched if
+// any of the resources does not match.
+func (r *flowReconciler) Adopt(ctx context.Context, resources *AdoptedResources) error {
+	state := NewFlowState()
+
+	vpcID := resources.VPC
+	if !r.isVPCManaged() {
+		if resources.VPC != "" || resources.NATGateway != "" {
+			return fmt.Errorf("the VPC and NAT gateway cannot be adopted if an existing VPC is used")
+		}
+		vpcID = *r.config.Networks.VPC.ID
+	}
+
+	if r.isVPCManaged() && resources.VPC != "" {
+		existing, err := r.describeVPC(resources.VPC)
+		if err != nil {
+			return err
+		}
+		if existing == nil {
+			return fmt.Errorf("VPC %s not found", resources.VPC)
+		}
+		if existing.CidrBlock != *r.config.Networks.VPC.CIDR {
+			return fmt.Errorf("VPC %s has CIDR %s but %s is declared", existing.VpcId, existing.CidrBlock, *r.config.Networks.VPC.CIDR)
+		}
+		state.Set(IdentifierVPC, existing.VpcId)
+		state.Set(IdentifierVPCIPv6CIDR, existing.Ipv6CidrBlock)
+		state.Set(IdentifierRouteTable, vpcRouteTableID(*existing))
+	}
+
+	if resources.NATGateway != "" {
+		if isNATGatewayPerZone(r.config) {
+			return fmt.Errorf("NAT gateway %s cannot be adopted if the NAT gateways are created per zone", resources.NATGateway)
+		}
+		if resources.VPC == "" {
+			return fmt.Errorf("NAT gateway %s can only be adopted together with its VPC", resources.NATGateway)
+		}
+		r.state.Set(IdentifierVPC, vpcID)
+		existing, err := r.describeNATGateway(resources.NATGateway)
+		if err != nil {
+			return err
+		}
+		if existing == nil {
+			return fmt.Errorf("NAT gateway %s not found in VPC %s", resources.NATGateway, vpcID)
+		}
+		state.Set(IdentifierNATGateway, existing.NatGatewayId)
+		if len(existing.SnatTableIds.SnatTableId) > 0 {
+			state.Set(IdentifierSNATTable, existing.SnatTableIds.SnatTableId[0])
+		}
+	}
+
+	if len(resources.VSwitches) > 0 && vpcID == "" {
+		return fmt.Errorf("vswitches can only be adopted together with their VPC")
+	}
+	for zoneName, vswitchIDs := range resources.VSwitches {
+		zoneIndex, zone := findZone(r.config.Networks.Zones, zoneName)
+		if zone == nil {
+			return fmt.Errorf("zone %s is not declared", zoneName)
+		}
+		workersCIDRs := zoneWorkerCIDRs(*zone)
+		if len(vswitchIDs) > len(workersCIDRs) {
+			return fmt.Errorf("%d vswitches are given for zone %s but only %d are declared", len(vswitchIDs), zoneName, len(workersCIDRs))
+		}
+
+		for vswitchIndex, vswitchID := range vswitchIDs {
+			existing, err := r.describeVSwitch(vswitchID)
+			if err != nil {
+				return err
+			}
+			if existing == nil {
+				return fmt.Errorf("vswitch %s not found", vswitchID)
+			}
+			if existing.VpcId != vpcID || existing.ZoneId != zoneName {
+				return fmt.Errorf("vswitch %s is in VPC %s and zone %s but VPC %s and zone %s are declared", vswitchID, existing.VpcId, existing.ZoneId, vpcID, zoneName)
+			}
+			if existing.CidrBlock != workersCIDRs[vswitchIndex] {
+				return fmt.Errorf("vswitch %s has CIDR %s but %s is declared", vswitchID, existing.CidrBlock, workersCIDRs[vswitchIndex])
+			}
+			state.Set(VSwitchIdentifier(zoneIndex, vswitchIndex, IdentifierZoneVSwitch), existing.VSwitchId)
+			state.Set(VSwitchIdentifier(zoneIndex, vswitchIndex, IdentifierZoneVSwitchIPv6CIDR), existing.Ipv6CidrBlock)
+			if vswitchIndex == 0 {
+				state.Set(ZoneIdentifier(zoneIndex, IdentifierZoneID), existing.ZoneId)
+			}
+		}
+	}
+
+	if resources.SecurityGroup != "" {
+		if !r.isSecurityGroupManaged() {
+			return fmt.Errorf("security group %s cannot be adopted as the existing security group %s is used", resources.SecurityGroup, *r.config.Networks.SecurityGroupID)
+		}
+		exists, err := r.ecsClient.CheckIfSecurityGroupExists(ctx, resources.SecurityGroup)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return fmt.Errorf("security group %s not found", resources.SecurityGroup)
+		}
+		state.Set(IdentifierSecurityGroup, resources.SecurityGroup)
+	}
+
+	r.state = state
+	return r.persistState(ctx)
+}
+
+func findZone(zones []alicloudv1alpha1.Zone, name string) (int, *alicloudv1alpha1.Zone) {
+	for index, zone := range zones {
+		if zone.Name == name {
+			return index, &zones[index]
+		}
+	}
+	return -1, nil
+}
+
+// adoptWithFlow adopts the resources given in the annotation of the Infrastructure, or discovered by their tags, and
+// persists them in the state.
+// The following reconciliation then continues with the flow reconciler.
+func (a *actuator) adoptWithFlow(ctx context.Context, infra *extensionsv1alpha1.Infrastructure, cluster *extensioncontroller.Cluster, config *alicloudv1alpha1.InfrastructureConfig, credentials *alicloud.Credentials) error {
+	resources := &AdoptedResources{}
+	if err := json.Unmarshal([]byte(infra.Annotations[AnnotationKeyAdopt]), resources); err != nil {
+		return errors.Wrapf(err, "could not decode the resources to adopt")
+	}
+
+	reconciler, err := a.newFlowReconciler(ctx, infra, cluster, config, credentials)
+	if err != nil {
+		return err
+	}
+
+	if resources.Discover {
+		if err := reconciler.Discover(ctx, resources); err != nil {
+			return errors.Wrapf(err, "failed to discover the existing resources")
+		}
+	}
+
+	if err := reconciler.Adopt(ctx, resources); err != nil {
+		return errors.Wrapf(err, "failed to adopt the existing resources")
+	}
+	return nil
+}
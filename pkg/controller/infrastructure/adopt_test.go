@@ -0,0 +1,242 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package infrastructure
+
+import (
+	"context"
+
+	"github.com/gardener/gardener-extension-provider-alicloud/pkg/alicloud"
+	alicloudv1alpha1 "github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/alicloud/v1alpha1"
+	mockalicloudclient "github.com/gardener/gardener-extension-provider-alicloud/pkg/mock/provider-alicloud/alicloud/client"
+
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/ecs"
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/vpc"
+	mockclient "github.com/gardener/gardener-extensions/pkg/mock/controller-runtime/client"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
+)
+
+var _ = Describe("Adopt", func() {
+	var (
+		ctrl      *gomock.Controller
+		c         *mockclient.MockClient
+		vpcClient *mockalicloudclient.MockVPC
+		ecsClient *mockalicloudclient.MockECS
+
+		ctx = context.TODO()
+
+		infra      *extensionsv1alpha1.Infrastructure
+		reconciler *flowReconciler
+		resources  *AdoptedResources
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		c = mockclient.NewMockClient(ctrl)
+		vpcClient = mockalicloudclient.NewMockVPC(ctrl)
+		ecsClient = mockalicloudclient.NewMockECS(ctrl)
+
+		infra = &extensionsv1alpha1.Infrastructure{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "shoot--foo--bar", Name: "infra"},
+		}
+		config := &alicloudv1alpha1.InfrastructureConfig{
+			Networks: alicloudv1alpha1.Networks{
+				VPC: alicloudv1alpha1.VPC{CIDR: pointer.StringPtr("10.250.0.0/16")},
+				Zones: []alicloudv1alpha1.Zone{
+					{Name: "cn-beijing-f", Workers: "10.250.0.0/19", AdditionalWorkers: []string{"10.250.64.0/19"}},
+				},
+			},
+		}
+		resources = &AdoptedResources{
+			VPC:           "vpc-1",
+			NATGateway:    "ngw-1",
+			VSwitches:     map[string][]string{"cn-beijing-f": {"vsw-1", "vsw-2"}},
+			SecurityGroup: "sg-1",
+		}
+
+		var err error
+		reconciler, err = newFlowReconciler(c, infra, config, vpcClient, ecsClient, nil)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	describeVPC := func(cidr string) {
+		vpcClient.EXPECT().DescribeVpcs(gomock.Any()).Return(&vpc.DescribeVpcsResponse{
+			Vpcs: vpc.Vpcs{Vpc: []vpc.Vpc{{
+				VpcId:          "vpc-1",
+				CidrBlock:      cidr,
+				RouterTableIds: vpc.RouterTableIds{RouterTableIds: []string{"vtb-1"}},
+			}}},
+		}, nil)
+	}
+
+	describeVSwitch := func(id, cidr string) {
+		vpcClient.EXPECT().DescribeVSwitches(gomock.Any()).DoAndReturn(func(req *vpc.DescribeVSwitchesRequest) (*vpc.DescribeVSwitchesResponse, error) {
+			Expect(req.VSwitchId).To(Equal(id))
+			return &vpc.DescribeVSwitchesResponse{
+				VSwitches: vpc.VSwitches{VSwitch: []vpc.VSwitch{{VSwitchId: id, VpcId: "vpc-1", ZoneId: "cn-beijing-f", CidrBlock: cidr}}},
+			}, nil
+		})
+	}
+
+	It("should adopt matching resources and persist them in the state", func() {
+		describeVPC("10.250.0.0/16")
+		vpcClient.EXPECT().DescribeNatGateways(gomock.Any()).Return(&vpc.DescribeNatGatewaysResponse{
+			NatGateways: vpc.NatGateways{NatGateway: []vpc.NatGateway{{
+				NatGatewayId: "ngw-1",
+				SnatTableIds: vpc.SnatTableIdsInDescribeNatGateways{SnatTableId: []string{"stb-1"}},
+			}}},
+		}, nil)
+		describeVSwitch("vsw-1", "10.250.0.0/19")
+		describeVSwitch("vsw-2", "10.250.64.0/19")
+		ecsClient.EXPECT().CheckIfSecurityGroupExists(ctx, "sg-1").Return(true, nil)
+		c.EXPECT().Status().Return(c)
+		c.EXPECT().Get(ctx, gomock.Any(), infra)
+		c.EXPECT().Update(ctx, infra)
+
+		Expect(reconciler.Adopt(ctx, resources)).To(Succeed())
+
+		Expect(reconciler.state.Whiteboard).To(Equal(map[string]string{
+			IdentifierVPC:                                  "vpc-1",
+			IdentifierRouteTable:                           "vtb-1",
+			IdentifierNATGateway:                           "ngw-1",
+			IdentifierSNATTable:                            "stb-1",
+			ZoneIdentifier(0, IdentifierZoneVSwitch):       "vsw-1",
+			ZoneIdentifier(0, IdentifierZoneID):            "cn-beijing-f",
+			VSwitchIdentifier(0, 1, IdentifierZoneVSwitch): "vsw-2",
+			IdentifierSecurityGroup:                        "sg-1",
+		}))
+		Expect(IsFlowState(infra.Status.State)).To(BeTrue())
+		Expect(ShouldUseFlow(infra)).To(BeTrue())
+	})
+
+	It("should reject a VPC with a different CIDR", func() {
+		describeVPC("10.251.0.0/16")
+
+		Expect(reconciler.Adopt(ctx, resources)).To(MatchError("VPC vpc-1 has CIDR 10.251.0.0/16 but 10.250.0.0/16 is declared"))
+		Expect(infra.Status.State).To(BeNil())
+	})
+
+	It("should reject a vswitch with a different CIDR", func() {
+		resources.NATGateway = ""
+		resources.SecurityGroup = ""
+		describeVPC("10.250.0.0/16")
+		describeVSwitch("vsw-1", "10.250.0.0/19")
+		describeVSwitch("vsw-2", "10.250.96.0/19")
+
+		Expect(reconciler.Adopt(ctx, resources)).To(MatchError("vswitch vsw-2 has CIDR 10.250.96.0/19 but 10.250.64.0/19 is declared"))
+		Expect(infra.Status.State).To(BeNil())
+	})
+
+	Describe("#Discover", func() {
+		const uid = "2f5e3a4b-1c2d-4e5f-8a9b-0c1d2e3f4a5b"
+
+		BeforeEach(func() {
+			reconciler.tags = map[string]string{alicloud.TagKeyShootUID: uid}
+		})
+
+		listTagResources := func(resourceType string, tagResources ...vpc.TagResource) {
+			vpcClient.EXPECT().ListTagResources(gomock.Any()).DoAndReturn(func(req *vpc.ListTagResourcesRequest) (*vpc.ListTagResourcesResponse, error) {
+				Expect(req.ResourceType).To(Equal(resourceType))
+				Expect(*req.Tag).To(ConsistOf(vpc.ListTagResourcesTag{Key: alicloud.TagKeyShootUID, Value: uid}))
+				return &vpc.ListTagResourcesResponse{TagResources: vpc.TagResources{TagResource: tagResources}}, nil
+			})
+		}
+
+		tagged := func(id string) vpc.TagResource {
+			return vpc.TagResource{ResourcId: id, TagKey: alicloud.TagKeyShootUID, TagValue: uid}
+		}
+
+		It("should only discover the resources with the UID tag of the shoot", func() {
+			listTagResources("VPC", tagged("vpc-1"), vpc.TagResource{ResourcId: "vpc-2", TagKey: alicloud.TagKeyShootUID, TagValue: "other"})
+			listTagResources("NATGATEWAY", tagged("ngw-1"))
+			listTagResources("VSWITCH", tagged("vsw-1"), tagged("vsw-2"), tagged("vsw-pods"))
+			describeVSwitch("vsw-1", "10.250.0.0/19")
+			describeVSwitch("vsw-2", "10.250.64.0/19")
+			describeVSwitch("vsw-pods", "10.250.128.0/19")
+			ecsClient.EXPECT().GetSecurityGroupsByTags(ctx, "vpc-1", map[string]string{alicloud.TagKeyShootUID: uid}).Return([]ecs.SecurityGroup{
+				{SecurityGroupId: "sg-1", Tags: ecs.TagsInDescribeSecurityGroups{Tag: []ecs.Tag{{TagKey: alicloud.TagKeyShootUID, TagValue: uid}}}},
+				{SecurityGroupId: "sg-2"},
+			}, nil)
+
+			resources = &AdoptedResources{Discover: true}
+			Expect(reconciler.Discover(ctx, resources)).To(Succeed())
+
+			Expect(resources).To(Equal(&AdoptedResources{
+				VPC:           "vpc-1",
+				NATGateway:    "ngw-1",
+				VSwitches:     map[string][]string{"cn-beijing-f": {"vsw-1", "vsw-2"}},
+				SecurityGroup: "sg-1",
+				Discover:      true,
+			}))
+		})
+
+		It("should not discover anything if no VPC carries the UID tag of the shoot", func() {
+			listTagResources("VPC", vpc.TagResource{ResourcId: "vpc-2", TagKey: alicloud.TagKeyShootUID, TagValue: "other"})
+
+			resources = &AdoptedResources{Discover: true}
+			Expect(reconciler.Discover(ctx, resources)).To(Succeed())
+
+			Expect(resources).To(Equal(&AdoptedResources{Discover: true}))
+		})
+
+		It("should keep the given resources and discover vswitches only in the order of their CIDRs", func() {
+			listTagResources("VSWITCH", tagged("vsw-3"))
+			describeVSwitch("vsw-3", "10.250.64.0/19")
+
+			resources.VSwitches = nil
+			Expect(reconciler.Discover(ctx, resources)).To(Succeed())
+
+			Expect(resources).To(Equal(&AdoptedResources{
+				VPC:           "vpc-1",
+				NATGateway:    "ngw-1",
+				VSwitches:     map[string][]string{},
+				SecurityGroup: "sg-1",
+			}))
+		})
+
+		It("should fail if several resources carry the UID tag of the shoot", func() {
+			listTagResources("VPC", tagged("vpc-1"), tagged("vpc-2"))
+
+			Expect(reconciler.Discover(ctx, &AdoptedResources{})).To(MatchError(ContainSubstring("2 VPCs [vpc-1 vpc-2] carry the UID tag of the shoot")))
+		})
+
+		It("should fail without the UID of the shoot", func() {
+			reconciler.tags = nil
+
+			Expect(reconciler.Discover(ctx, &AdoptedResources{})).To(MatchError(ContainSubstring("without the UID of the shoot")))
+		})
+	})
+
+	Describe("#ShouldAdopt", func() {
+		It("should only adopt resources for Infrastructures without state", func() {
+			Expect(ShouldAdopt(infra)).To(BeFalse())
+
+			infra.Annotations = map[string]string{AnnotationKeyAdopt: "{}"}
+			Expect(ShouldAdopt(infra)).To(BeTrue())
+
+			infra.Status.State = &runtime.RawExtension{Raw: []byte(`{"kind":"FlowState"}`)}
+			Expect(ShouldAdopt(infra)).To(BeFalse())
+		})
+	})
+})
@@ -0,0 +1,119 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package infrastructure
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/config"
+	extensioncontroller "github.com/gardener/gardener-extensions/pkg/controller"
+	controllererrors "github.com/gardener/gardener-extensions/pkg/controller/error"
+	"github.com/gardener/gardener-extensions/pkg/controller/infrastructure"
+
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/inject"
+)
+
+// errorBackoff computes the requeue durations of Infrastructures with consecutive failures.
+type errorBackoff struct {
+	base   time.Duration
+	max    time.Duration
+	factor float64
+
+	lock     sync.Mutex
+	failures map[string]int
+}
+
+func newErrorBackoff(backoff *config.Backoff) *errorBackoff {
+	return &errorBackoff{
+		base:     backoff.Base.Duration,
+		max:      backoff.Max.Duration,
+		factor:   backoff.Factor,
+		failures: make(map[string]int),
+	}
+}
+
+// next records a failure of the given Infrastructure and returns the duration after which it shall be requeued.
+func (b *errorBackoff) next(infra *extensionsv1alpha1.Infrastructure) time.Duration {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	key := infrastructureKey(infra)
+	failures := b.failures[key]
+	b.failures[key] = failures + 1
+
+	duration := float64(b.base) * math.Pow(b.factor, float64(failures))
+	if duration > float64(b.max) {
+		return b.max
+	}
+	return time.Duration(duration)
+}
+
+// reset forgets the failures of the given Infrastructure.
+func (b *errorBackoff) reset(infra *extensionsv1alpha1.Infrastructure) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	delete(b.failures, infrastructureKey(infra))
+}
+
+func infrastructureKey(infra *extensionsv1alpha1.Infrastructure) string {
+	return infra.Namespace + "/" + infra.Name
+}
+
+// NewErrorBackoffActuator returns an actuator which requeues Infrastructures whose reconciliation or deletion
+// by the given actuator failed according to the given backoff. Any requeue duration of the given actuator is
+// overridden.
+func NewErrorBackoffActuator(a infrastructure.Actuator, backoff *config.Backoff) infrastructure.Actuator {
+	return &errorBackoffActuator{
+		Actuator: a,
+		backoff:  newErrorBackoff(backoff),
+	}
+}
+
+type errorBackoffActuator struct {
+	infrastructure.Actuator
+	backoff *errorBackoff
+}
+
+// InjectFunc implements inject.Injector.
+func (a *errorBackoffActuator) InjectFunc(f inject.Func) error {
+	return f(a.Actuator)
+}
+
+// Reconcile implements infrastructure.Actuator.
+func (a *errorBackoffActuator) Reconcile(ctx context.Context, infra *extensionsv1alpha1.Infrastructure, cluster *extensioncontroller.Cluster) error {
+	return a.requeueOnError(infra, a.Actuator.Reconcile(ctx, infra, cluster))
+}
+
+// Delete implements infrastructure.Actuator.
+func (a *errorBackoffActuator) Delete(ctx context.Context, infra *extensionsv1alpha1.Infrastructure, cluster *extensioncontroller.Cluster) error {
+	return a.requeueOnError(infra, a.Actuator.Delete(ctx, infra, cluster))
+}
+
+func (a *errorBackoffActuator) requeueOnError(infra *extensionsv1alpha1.Infrastructure, err error) error {
+	if err == nil {
+		a.backoff.reset(infra)
+		return nil
+	}
+
+	return &controllererrors.RequeueAfterError{
+		Cause:        extensioncontroller.ReconcileErrCauseOrErr(err),
+		RequeueAfter: a.backoff.next(infra),
+	}
+}
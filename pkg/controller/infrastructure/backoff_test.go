@@ -0,0 +1,121 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package infrastructure_test
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/config"
+	. "github.com/gardener/gardener-extension-provider-alicloud/pkg/controller/infrastructure"
+	extensioncontroller "github.com/gardener/gardener-extensions/pkg/controller"
+	controllererrors "github.com/gardener/gardener-extensions/pkg/controller/error"
+
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// fakeActuator returns the next of its errors on each call.
+type fakeActuator struct {
+	errs []error
+}
+
+func (f *fakeActuator) next() error {
+	err := f.errs[0]
+	f.errs = f.errs[1:]
+	return err
+}
+
+func (f *fakeActuator) Reconcile(context.Context, *extensionsv1alpha1.Infrastructure, *extensioncontroller.Cluster) error {
+	return f.next()
+}
+
+func (f *fakeActuator) Delete(context.Context, *extensionsv1alpha1.Infrastructure, *extensioncontroller.Cluster) error {
+	return f.next()
+}
+
+var _ = Describe("ErrorBackoff", func() {
+	var (
+		ctx = context.TODO()
+
+		errTransient = fmt.Errorf("quota exceeded")
+
+		backoff = &config.Backoff{
+			Base:   metav1.Duration{Duration: 10 * time.Second},
+			Max:    metav1.Duration{Duration: time.Minute},
+			Factor: 2,
+		}
+
+		infra *extensionsv1alpha1.Infrastructure
+	)
+
+	BeforeEach(func() {
+		infra = &extensionsv1alpha1.Infrastructure{ObjectMeta: metav1.ObjectMeta{Namespace: "shoot--foo--bar", Name: "bar"}}
+	})
+
+	requeueAfter := func(err error) time.Duration {
+		requeueErr, ok := err.(*controllererrors.RequeueAfterError)
+		ExpectWithOffset(1, ok).To(BeTrue())
+		ExpectWithOffset(1, requeueErr.Cause).To(Equal(errTransient))
+		return requeueErr.RequeueAfter
+	}
+
+	It("should requeue failed reconciliations following the backoff curve", func() {
+		a := NewErrorBackoffActuator(&fakeActuator{errs: []error{errTransient, errTransient, errTransient, errTransient, errTransient}}, backoff)
+
+		var durations []time.Duration
+		for i := 0; i < 5; i++ {
+			durations = append(durations, requeueAfter(a.Reconcile(ctx, infra, nil)))
+		}
+		Expect(durations).To(Equal([]time.Duration{10 * time.Second, 20 * time.Second, 40 * time.Second, time.Minute, time.Minute}))
+	})
+
+	It("should override the requeue duration of the actuator", func() {
+		a := NewErrorBackoffActuator(&fakeActuator{errs: []error{&controllererrors.RequeueAfterError{Cause: errTransient, RequeueAfter: 30 * time.Second}}}, backoff)
+
+		Expect(requeueAfter(a.Reconcile(ctx, infra, nil))).To(Equal(10 * time.Second))
+	})
+
+	It("should reset the backoff after a successful reconciliation", func() {
+		a := NewErrorBackoffActuator(&fakeActuator{errs: []error{errTransient, errTransient, nil, errTransient}}, backoff)
+
+		Expect(requeueAfter(a.Reconcile(ctx, infra, nil))).To(Equal(10 * time.Second))
+		Expect(requeueAfter(a.Reconcile(ctx, infra, nil))).To(Equal(20 * time.Second))
+		Expect(a.Reconcile(ctx, infra, nil)).To(Succeed())
+		Expect(requeueAfter(a.Reconcile(ctx, infra, nil))).To(Equal(10 * time.Second))
+	})
+
+	It("should track the failures of each infrastructure separately", func() {
+		var (
+			a     = NewErrorBackoffActuator(&fakeActuator{errs: []error{errTransient, errTransient, errTransient}}, backoff)
+			other = infra.DeepCopy()
+		)
+		other.Namespace = "shoot--foo--baz"
+
+		Expect(requeueAfter(a.Reconcile(ctx, infra, nil))).To(Equal(10 * time.Second))
+		Expect(requeueAfter(a.Reconcile(ctx, infra, nil))).To(Equal(20 * time.Second))
+		Expect(requeueAfter(a.Reconcile(ctx, other, nil))).To(Equal(10 * time.Second))
+	})
+
+	It("should requeue failed deletions following the backoff curve", func() {
+		a := NewErrorBackoffActuator(&fakeActuator{errs: []error{errTransient, errTransient}}, backoff)
+
+		Expect(requeueAfter(a.Delete(ctx, infra, nil))).To(Equal(10 * time.Second))
+		Expect(requeueAfter(a.Delete(ctx, infra, nil))).To(Equal(20 * time.Second))
+	})
+})
@@ -19,12 +19,15 @@ import (
 	"strings"
 
 	alicloudclient "github.com/gardener/gardener-extension-provider-alicloud/pkg/alicloud/client"
+	alicloudv1alpha1 "github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/alicloud/v1alpha1"
 
 	"github.com/aliyun/alibaba-cloud-sdk-go/services/vpc"
 )
 
-// GetVPCInfo gets info of an existing VPC.
-func GetVPCInfo(vpcClient alicloudclient.VPC, vpcID string) (*VPCInfo, error) {
+// GetVPCInfo gets info of an existing VPC. If natGatewayID is non-empty, the NAT gateway with this ID is used,
+// otherwise the VPC is expected to contain exactly one NAT gateway. If routeTableID is non-empty, the route table with
+// this ID is used instead of the system route table of the VPC.
+func GetVPCInfo(vpcClient alicloudclient.VPC, vpcID, natGatewayID, routeTableID string) (*VPCInfo, error) {
 	describeVPCsReq := vpc.CreateDescribeVpcsRequest()
 	describeVPCsReq.VpcId = vpcID
 	describeVPCsRes, err := vpcClient.DescribeVpcs(describeVPCsReq)
@@ -37,35 +40,94 @@ func GetVPCInfo(vpcClient alicloudclient.VPC, vpcID string) (*VPCInfo, error) {
 	}
 
 	vpcCIDR := describeVPCsRes.Vpcs.Vpc[0].CidrBlock
+	vpcIPv6CIDR := describeVPCsRes.Vpcs.Vpc[0].Ipv6CidrBlock
+	if routeTableID == "" {
+		routeTableID = vpcRouteTableID(describeVPCsRes.Vpcs.Vpc[0])
+	} else {
+		describeRouteTablesReq := vpc.CreateDescribeRouteTableListRequest()
+		describeRouteTablesReq.VpcId = vpcID
+		describeRouteTablesReq.RouteTableId = routeTableID
+		describeRouteTablesRes, err := vpcClient.DescribeRouteTableList(describeRouteTablesReq)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(describeRouteTablesRes.RouterTableList.RouterTableListType) == 0 {
+			return nil, fmt.Errorf("route table %s not found in VPC %s", routeTableID, vpcID)
+		}
+	}
 
 	describeNATGatewaysReq := vpc.CreateDescribeNatGatewaysRequest()
 	describeNATGatewaysReq.VpcId = vpcID
+	describeNATGatewaysReq.NatGatewayId = natGatewayID
 	describeNatGatewaysRes, err := vpcClient.DescribeNatGateways(describeNATGatewaysReq)
 	if err != nil {
 		return nil, err
 	}
 
+	if natGatewayID != "" && len(describeNatGatewaysRes.NatGateways.NatGateway) == 0 {
+		return nil, fmt.Errorf("NAT Gateway %s not found in VPC %s", natGatewayID, vpcID)
+	}
+
 	if len(describeNatGatewaysRes.NatGateways.NatGateway) != 1 {
 		return nil, fmt.Errorf("ambiguous NAT Gateway response: expected 1 NAT Gateway but got %v", describeNatGatewaysRes.NatGateways.NatGateway)
 	}
 
 	natGateway := describeNatGatewaysRes.NatGateways.NatGateway[0]
-	natGatewayID := natGateway.NatGatewayId
 	sNATTableIDs := strings.Join(natGateway.SnatTableIds.SnatTableId, ",")
 
-	internetChargeType, err := FetchEIPInternetChargeType(vpcClient, vpcID)
+	internetChargeType, err := fetchNATGatewayEIPInternetChargeType(vpcClient, natGateway)
 	if err != nil {
 		return nil, err
 	}
 
 	return &VPCInfo{
 		CIDR:               vpcCIDR,
-		NATGatewayID:       natGatewayID,
+		IPv6CIDR:           vpcIPv6CIDR,
+		NATGatewayID:       natGateway.NatGatewayId,
 		SNATTableIDs:       sNATTableIDs,
+		RouteTableID:       routeTableID,
 		InternetChargeType: internetChargeType,
 	}, nil
 }
 
+// getVPCCIDR returns the CIDR of the VPC with the given ID.
+func getVPCCIDR(vpcClient alicloudclient.VPC, vpcID string) (string, error) {
+	describeVPCsReq := vpc.CreateDescribeVpcsRequest()
+	describeVPCsReq.VpcId = vpcID
+	describeVPCsRes, err := vpcClient.DescribeVpcs(describeVPCsReq)
+	if err != nil {
+		return "", err
+	}
+	if len(describeVPCsRes.Vpcs.Vpc) != 1 {
+		return "", fmt.Errorf("ambiguous VPC response: expected 1 VPC but got %v", describeVPCsRes.Vpcs.Vpc)
+	}
+	return describeVPCsRes.Vpcs.Vpc[0].CidrBlock, nil
+}
+
+// getExistingVPCInfo gets info of the existing VPC of the given InfrastructureConfig.
+func getExistingVPCInfo(vpcClient alicloudclient.VPC, config *alicloudv1alpha1.InfrastructureConfig) (*VPCInfo, error) {
+	var natGatewayID string
+	if config.Networks.NatGateway != nil && config.Networks.NatGateway.ID != nil {
+		natGatewayID = *config.Networks.NatGateway.ID
+	}
+
+	var routeTableID string
+	if config.Networks.RouteTableID != nil {
+		routeTableID = *config.Networks.RouteTableID
+	}
+
+	return GetVPCInfo(vpcClient, *config.Networks.VPC.ID, natGatewayID, routeTableID)
+}
+
+// vpcRouteTableID returns the ID of the system route table of the given VPC.
+func vpcRouteTableID(v vpc.Vpc) string {
+	if len(v.RouterTableIds.RouterTableIds) == 0 {
+		return ""
+	}
+	return v.RouterTableIds.RouterTableIds[0]
+}
+
 // FetchEIPInternetChargeType fetches the internet charge type for the VPC's EIP.
 func FetchEIPInternetChargeType(vpcClient alicloudclient.VPC, vpcID string) (string, error) {
 	describeNATGatewaysReq := vpc.CreateDescribeNatGatewaysRequest()
@@ -80,8 +142,10 @@ func FetchEIPInternetChargeType(vpcClient alicloudclient.VPC, vpcID string) (str
 		return alicloudclient.DefaultInternetChargeType, nil
 	}
 
-	natGateway := describeNatGatewaysRes.NatGateways.NatGateway[0]
+	return fetchNATGatewayEIPInternetChargeType(vpcClient, describeNatGatewaysRes.NatGateways.NatGateway[0])
+}
 
+func fetchNATGatewayEIPInternetChargeType(vpcClient alicloudclient.VPC, natGateway vpc.NatGateway) (string, error) {
 	if len(natGateway.IpLists.IpList) == 0 {
 		return alicloudclient.DefaultInternetChargeType, nil
 	}
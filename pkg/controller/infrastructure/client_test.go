@@ -44,6 +44,7 @@ var _ = Describe("Client", func() {
 				client       = mockclient.NewMockVPC(ctrl)
 				vpcID        = "vpcID"
 				vpcCIDR      = "vpcCIDR"
+				vpcIPv6CIDR  = "vpcIPv6CIDR"
 				natGatewayID = "natGatewayID"
 				sNATTableID1 = "sNATTableID1"
 				sNATTableID2 = "sNATTableID2"
@@ -60,7 +61,7 @@ var _ = Describe("Client", func() {
 				client.EXPECT().DescribeVpcs(describeVPCsReq).Return(&vpc.DescribeVpcsResponse{
 					Vpcs: vpc.Vpcs{
 						Vpc: []vpc.Vpc{
-							{CidrBlock: vpcCIDR},
+							{CidrBlock: vpcCIDR, Ipv6CidrBlock: vpcIPv6CIDR},
 						},
 					},
 				}, nil),
@@ -76,17 +77,175 @@ var _ = Describe("Client", func() {
 							},
 						},
 					},
-				}, nil).Times(2),
+				}, nil),
 			)
 
-			info, err := GetVPCInfo(client, vpcID)
+			info, err := GetVPCInfo(client, vpcID, "", "")
 			Expect(err).NotTo(HaveOccurred())
 			Expect(info).To(Equal(&VPCInfo{
 				CIDR:               vpcCIDR,
+				IPv6CIDR:           vpcIPv6CIDR,
 				NATGatewayID:       natGatewayID,
 				SNATTableIDs:       sNATTableIDs,
 				InternetChargeType: alicloudclient.DefaultInternetChargeType,
 			}))
 		})
+
+		It("should use the specified NAT gateway", func() {
+			var (
+				client       = mockclient.NewMockVPC(ctrl)
+				vpcID        = "vpcID"
+				vpcCIDR      = "vpcCIDR"
+				natGatewayID = "natGatewayID"
+				sNATTableID  = "sNATTableID"
+			)
+
+			describeVPCsReq := vpc.CreateDescribeVpcsRequest()
+			describeVPCsReq.VpcId = vpcID
+
+			describeNATGatewaysReq := vpc.CreateDescribeNatGatewaysRequest()
+			describeNATGatewaysReq.VpcId = vpcID
+			describeNATGatewaysReq.NatGatewayId = natGatewayID
+
+			gomock.InOrder(
+				client.EXPECT().DescribeVpcs(describeVPCsReq).Return(&vpc.DescribeVpcsResponse{
+					Vpcs: vpc.Vpcs{
+						Vpc: []vpc.Vpc{
+							{CidrBlock: vpcCIDR},
+						},
+					},
+				}, nil),
+
+				client.EXPECT().DescribeNatGateways(describeNATGatewaysReq).Return(&vpc.DescribeNatGatewaysResponse{
+					NatGateways: vpc.NatGateways{
+						NatGateway: []vpc.NatGateway{
+							{
+								NatGatewayId: natGatewayID,
+								SnatTableIds: vpc.SnatTableIdsInDescribeNatGateways{
+									SnatTableId: []string{sNATTableID},
+								},
+							},
+						},
+					},
+				}, nil),
+			)
+
+			info, err := GetVPCInfo(client, vpcID, natGatewayID, "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(info).To(Equal(&VPCInfo{
+				CIDR:               vpcCIDR,
+				NATGatewayID:       natGatewayID,
+				SNATTableIDs:       sNATTableID,
+				InternetChargeType: alicloudclient.DefaultInternetChargeType,
+			}))
+		})
+
+		It("should fail if the specified NAT gateway is not in the VPC", func() {
+			var (
+				client       = mockclient.NewMockVPC(ctrl)
+				vpcID        = "vpcID"
+				natGatewayID = "natGatewayID"
+			)
+
+			describeVPCsReq := vpc.CreateDescribeVpcsRequest()
+			describeVPCsReq.VpcId = vpcID
+
+			describeNATGatewaysReq := vpc.CreateDescribeNatGatewaysRequest()
+			describeNATGatewaysReq.VpcId = vpcID
+			describeNATGatewaysReq.NatGatewayId = natGatewayID
+
+			gomock.InOrder(
+				client.EXPECT().DescribeVpcs(describeVPCsReq).Return(&vpc.DescribeVpcsResponse{
+					Vpcs: vpc.Vpcs{
+						Vpc: []vpc.Vpc{
+							{CidrBlock: "vpcCIDR"},
+						},
+					},
+				}, nil),
+
+				client.EXPECT().DescribeNatGateways(describeNATGatewaysReq).Return(&vpc.DescribeNatGatewaysResponse{}, nil),
+			)
+
+			_, err := GetVPCInfo(client, vpcID, natGatewayID, "")
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should use the specified route table", func() {
+			var (
+				client       = mockclient.NewMockVPC(ctrl)
+				vpcID        = "vpcID"
+				routeTableID = "routeTableID"
+			)
+
+			describeVPCsReq := vpc.CreateDescribeVpcsRequest()
+			describeVPCsReq.VpcId = vpcID
+
+			describeRouteTablesReq := vpc.CreateDescribeRouteTableListRequest()
+			describeRouteTablesReq.VpcId = vpcID
+			describeRouteTablesReq.RouteTableId = routeTableID
+
+			describeNATGatewaysReq := vpc.CreateDescribeNatGatewaysRequest()
+			describeNATGatewaysReq.VpcId = vpcID
+
+			gomock.InOrder(
+				client.EXPECT().DescribeVpcs(describeVPCsReq).Return(&vpc.DescribeVpcsResponse{
+					Vpcs: vpc.Vpcs{
+						Vpc: []vpc.Vpc{
+							{CidrBlock: "vpcCIDR", RouterTableIds: vpc.RouterTableIds{RouterTableIds: []string{"systemRouteTableID"}}},
+						},
+					},
+				}, nil),
+
+				client.EXPECT().DescribeRouteTableList(describeRouteTablesReq).Return(&vpc.DescribeRouteTableListResponse{
+					RouterTableList: vpc.RouterTableList{
+						RouterTableListType: []vpc.RouterTableListType{
+							{VpcId: vpcID, RouteTableId: routeTableID},
+						},
+					},
+				}, nil),
+
+				client.EXPECT().DescribeNatGateways(describeNATGatewaysReq).Return(&vpc.DescribeNatGatewaysResponse{
+					NatGateways: vpc.NatGateways{
+						NatGateway: []vpc.NatGateway{
+							{NatGatewayId: "natGatewayID"},
+						},
+					},
+				}, nil),
+			)
+
+			info, err := GetVPCInfo(client, vpcID, "", routeTableID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(info.RouteTableID).To(Equal(routeTableID))
+		})
+
+		It("should fail if the specified route table is not in the VPC", func() {
+			var (
+				client       = mockclient.NewMockVPC(ctrl)
+				vpcID        = "vpcID"
+				routeTableID = "routeTableID"
+			)
+
+			describeVPCsReq := vpc.CreateDescribeVpcsRequest()
+			describeVPCsReq.VpcId = vpcID
+
+			describeRouteTablesReq := vpc.CreateDescribeRouteTableListRequest()
+			describeRouteTablesReq.VpcId = vpcID
+			describeRouteTablesReq.RouteTableId = routeTableID
+
+			gomock.InOrder(
+				client.EXPECT().DescribeVpcs(describeVPCsReq).Return(&vpc.DescribeVpcsResponse{
+					Vpcs: vpc.Vpcs{
+						Vpc: []vpc.Vpc{
+							{CidrBlock: "vpcCIDR"},
+						},
+					},
+				}, nil),
+
+				client.EXPECT().DescribeRouteTableList(describeRouteTablesReq).Return(&vpc.DescribeRouteTableListResponse{}, nil),
+			)
+
+			_, err := GetVPCInfo(client, vpcID, "", routeTableID)
+			Expect(err).To(MatchError("route table routeTableID not found in VPC vpcID"))
+		})
 	})
 })
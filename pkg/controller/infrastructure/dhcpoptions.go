@@ -0,0 +1,164 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	alicloudclient "github.com/gardener/gardener-extension-provider-alicloud/pkg/alicloud/client"
+	alicloudv1alpha1 "github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/alicloud/v1alpha1"
+
+	"github.com/aliyun/alibaba-cloud-sdk-go/sdk/errors"
+)
+
+const (
+	dhcpOptionsSetNotFoundCode         = "InvalidDhcpOptionsSetId.NotFound"
+	dhcpOptionsSetAssociateStatusInUse = "InUse"
+)
+
+// dhcpOptions returns the domain name and the comma-separated DNS servers of the given DHCP options.
+func dhcpOptions(options *alicloudv1alpha1.DHCPOptions) (string, string) {
+	var domainName string
+	if options.DomainName != nil {
+		domainName = *options.DomainName
+	}
+	return domainName, strings.Join(options.DomainNameServers, ",")
+}
+
+func (r *flowReconciler) getDHCPOptionsSet(dhcpOptionsSetID string) (*alicloudclient.GetDhcpOptionsSetResponse, error) {
+	if dhcpOptionsSetID == "" {
+		return nil, nil
+	}
+
+	req := alicloudclient.CreateGetDhcpOptionsSetRequest()
+	req.DhcpOptionsSetId = dhcpOptionsSetID
+	res, err := r.vpcClient.GetDhcpOptionsSet(req)
+	if err != nil {
+		if sdkErr, ok := err.(errors.Error); ok && sdkErr.ErrorCode() == dhcpOptionsSetNotFoundCode {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return res, nil
+}
+
+// dhcpOptionsSetAssociation returns the association of the given DHCP options set with the VPC, if any.
+func (r *flowReconciler) dhcpOptionsSetAssociation(dhcpOptionsSet *alicloudclient.GetDhcpOptionsSetResponse) *alicloudclient.AssociateVpc {
+	for _, associateVPC := range dhcpOptionsSet.AssociateVpcs {
+		if associateVPC.VpcId == r.state.Get(IdentifierVPC) {
+			return &associateVPC
+		}
+	}
+	return nil
+}
+
+// ensureDHCPOptions ensures a DHCP options set with the configured options which is associated with the VPC if DHCP
+// options are configured, and deletes it otherwise.
+func (r *flowReconciler) ensureDHCPOptions(ctx context.Context) error {
+	options := r.config.Networks.DHCPOptions
+	if options == nil {
+		return r.deleteDHCPOptions(ctx)
+	}
+	domainName, domainNameServers := dhcpOptions(options)
+
+	existing, err := r.getDHCPOptionsSet(r.state.Get(IdentifierDHCPOptionsSet))
+	if err != nil {
+		return err
+	}
+	// Options cannot be removed by an update of the DHCP options set, hence it is replaced in that case.
+	if existing != nil && ((domainName == "" && existing.DhcpOptions.DomainName != "") || (domainNameServers == "" && existing.DhcpOptions.DomainNameServers != "")) {
+		if err := r.deleteDHCPOptions(ctx); err != nil {
+			return err
+		}
+		existing = nil
+	}
+
+	if existing == nil {
+		req := alicloudclient.CreateCreateDhcpOptionsSetRequest()
+		req.DhcpOptionsSetName = r.name("dhcp-options")
+		req.DomainName = domainName
+		req.DomainNameServers = domainNameServers
+		res, err := r.vpcClient.CreateDhcpOptionsSet(req)
+		if err != nil {
+			return err
+		}
+		if err := r.setAndPersist(ctx, IdentifierDHCPOptionsSet, res.DhcpOptionsSetId); err != nil {
+			return err
+		}
+		return r.attachDHCPOptionsSet(res.DhcpOptionsSetId)
+	}
+
+	if existing.DhcpOptions.DomainName != domainName || existing.DhcpOptions.DomainNameServers != domainNameServers {
+		req := alicloudclient.CreateUpdateDhcpOptionsSetAttributeRequest()
+		req.DhcpOptionsSetId = existing.DhcpOptionsSetId
+		req.DomainName = domainName
+		req.DomainNameServers = domainNameServers
+		if _, err := r.vpcClient.UpdateDhcpOptionsSetAttribute(req); err != nil {
+			return err
+		}
+	}
+
+	if r.dhcpOptionsSetAssociation(existing) == nil {
+		return r.attachDHCPOptionsSet(existing.DhcpOptionsSetId)
+	}
+	return nil
+}
+
+func (r *flowReconciler) attachDHCPOptionsSet(dhcpOptionsSetID string) error {
+	req := alicloudclient.CreateAttachDhcpOptionsSetToVpcRequest()
+	req.DhcpOptionsSetId = dhcpOptionsSetID
+	req.VpcId = r.state.Get(IdentifierVPC)
+	if _, err := r.vpcClient.AttachDhcpOptionsSetToVpc(req); err != nil {
+		return fmt.Errorf("could not associate DHCP options set %s with VPC %s, please make sure that the VPC has no other DHCP options set: %v", dhcpOptionsSetID, req.VpcId, err)
+	}
+	return nil
+}
+
+// deleteDHCPOptions unassociates the DHCP options set from the VPC and deletes it. Alicloud unassociates DHCP options
+// sets asynchronously, hence the deletion is retried until the DHCP options set is no longer associated.
+func (r *flowReconciler) deleteDHCPOptions(ctx context.Context) error {
+	if r.state.Get(IdentifierDHCPOptionsSet) == "" {
+		return nil
+	}
+
+	dhcpOptionsSet, err := r.getDHCPOptionsSet(r.state.Get(IdentifierDHCPOptionsSet))
+	if err != nil {
+		return err
+	}
+
+	if dhcpOptionsSet != nil {
+		if association := r.dhcpOptionsSetAssociation(dhcpOptionsSet); association != nil {
+			if association.AssociateStatus == dhcpOptionsSetAssociateStatusInUse {
+				req := alicloudclient.CreateDetachDhcpOptionsSetFromVpcRequest()
+				req.DhcpOptionsSetId = dhcpOptionsSet.DhcpOptionsSetId
+				req.VpcId = association.VpcId
+				if _, err := r.vpcClient.DetachDhcpOptionsSetFromVpc(req); err != nil {
+					return err
+				}
+			}
+			return fmt.Errorf("waiting until DHCP options set %s is unassociated from VPC %s", dhcpOptionsSet.DhcpOptionsSetId, association.VpcId)
+		}
+
+		req := alicloudclient.CreateDeleteDhcpOptionsSetRequest()
+		req.DhcpOptionsSetId = dhcpOptionsSet.DhcpOptionsSetId
+		if _, err := r.vpcClient.DeleteDhcpOptionsSet(req); err != nil {
+			return err
+		}
+	}
+
+	return r.setAndPersist(ctx, IdentifierDHCPOptionsSet, "")
+}
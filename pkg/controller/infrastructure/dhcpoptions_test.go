@@ -0,0 +1,219 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package infrastructure
+
+import (
+	"context"
+	"net/http"
+
+	alicloudclient "github.com/gardener/gardener-extension-provider-alicloud/pkg/alicloud/client"
+	alicloudv1alpha1 "github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/alicloud/v1alpha1"
+	mockalicloudclient "github.com/gardener/gardener-extension-provider-alicloud/pkg/mock/provider-alicloud/alicloud/client"
+
+	"github.com/aliyun/alibaba-cloud-sdk-go/sdk/errors"
+	mockclient "github.com/gardener/gardener-extensions/pkg/mock/controller-runtime/client"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+)
+
+var _ = Describe("DHCP options", func() {
+	var (
+		ctrl      *gomock.Controller
+		c         *mockclient.MockClient
+		vpcClient *mockalicloudclient.MockVPC
+
+		ctx = context.TODO()
+
+		config     *alicloudv1alpha1.InfrastructureConfig
+		reconciler *flowReconciler
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		c = mockclient.NewMockClient(ctrl)
+		vpcClient = mockalicloudclient.NewMockVPC(ctrl)
+
+		c.EXPECT().Status().Return(c).AnyTimes()
+		c.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).AnyTimes()
+		c.EXPECT().Update(ctx, gomock.Any()).AnyTimes()
+
+		infra := &extensionsv1alpha1.Infrastructure{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "shoot--foo--bar", Name: "infra"},
+		}
+		config = &alicloudv1alpha1.InfrastructureConfig{
+			Networks: alicloudv1alpha1.Networks{
+				VPC: alicloudv1alpha1.VPC{CIDR: pointer.StringPtr("10.250.0.0/16")},
+				DHCPOptions: &alicloudv1alpha1.DHCPOptions{
+					DomainNameServers: []string{"10.0.0.2", "10.0.1.2"},
+					DomainName:        pointer.StringPtr("corp.example.com"),
+				},
+			},
+		}
+
+		var err error
+		reconciler, err = newFlowReconciler(c, infra, config, vpcClient, nil, nil)
+		Expect(err).NotTo(HaveOccurred())
+		reconciler.state.Set(IdentifierVPC, "vpc-1")
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	getDHCPOptionsSet := func(res *alicloudclient.GetDhcpOptionsSetResponse) {
+		vpcClient.EXPECT().GetDhcpOptionsSet(gomock.Any()).DoAndReturn(func(req *alicloudclient.GetDhcpOptionsSetRequest) (*alicloudclient.GetDhcpOptionsSetResponse, error) {
+			Expect(req.DhcpOptionsSetId).To(Equal(res.DhcpOptionsSetId))
+			return res, nil
+		})
+	}
+
+	inUse := []alicloudclient.AssociateVpc{{VpcId: "vpc-1", AssociateStatus: "InUse"}}
+
+	Describe("#ensureDHCPOptions", func() {
+		It("should create a DHCP options set and associate it with the VPC", func() {
+			gomock.InOrder(
+				vpcClient.EXPECT().CreateDhcpOptionsSet(gomock.Any()).DoAndReturn(func(req *alicloudclient.CreateDhcpOptionsSetRequest) (*alicloudclient.CreateDhcpOptionsSetResponse, error) {
+					Expect(req.DhcpOptionsSetName).To(Equal("shoot--foo--bar-dhcp-options"))
+					Expect(req.DomainName).To(Equal("corp.example.com"))
+					Expect(req.DomainNameServers).To(Equal("10.0.0.2,10.0.1.2"))
+					return &alicloudclient.CreateDhcpOptionsSetResponse{DhcpOptionsSetId: "dopt-1"}, nil
+				}),
+				vpcClient.EXPECT().AttachDhcpOptionsSetToVpc(gomock.Any()).DoAndReturn(func(req *alicloudclient.AttachDhcpOptionsSetToVpcRequest) (*alicloudclient.DhcpOptionsSetResponse, error) {
+					Expect(req.DhcpOptionsSetId).To(Equal("dopt-1"))
+					Expect(req.VpcId).To(Equal("vpc-1"))
+					return &alicloudclient.DhcpOptionsSetResponse{}, nil
+				}),
+			)
+
+			Expect(reconciler.ensureDHCPOptions(ctx)).To(Succeed())
+			Expect(reconciler.state.Get(IdentifierDHCPOptionsSet)).To(Equal("dopt-1"))
+		})
+
+		It("should recreate a DHCP options set which has been deleted", func() {
+			reconciler.state.Set(IdentifierDHCPOptionsSet, "dopt-1")
+			vpcClient.EXPECT().GetDhcpOptionsSet(gomock.Any()).Return(nil, errors.NewServerError(http.StatusNotFound, `{"Code":"InvalidDhcpOptionsSetId.NotFound"}`, ""))
+			vpcClient.EXPECT().CreateDhcpOptionsSet(gomock.Any()).Return(&alicloudclient.CreateDhcpOptionsSetResponse{DhcpOptionsSetId: "dopt-2"}, nil)
+			vpcClient.EXPECT().AttachDhcpOptionsSetToVpc(gomock.Any()).Return(&alicloudclient.DhcpOptionsSetResponse{}, nil)
+
+			Expect(reconciler.ensureDHCPOptions(ctx)).To(Succeed())
+			Expect(reconciler.state.Get(IdentifierDHCPOptionsSet)).To(Equal("dopt-2"))
+		})
+
+		It("should keep an existing DHCP options set with the configured options", func() {
+			reconciler.state.Set(IdentifierDHCPOptionsSet, "dopt-1")
+			getDHCPOptionsSet(&alicloudclient.GetDhcpOptionsSetResponse{
+				DhcpOptionsSetId: "dopt-1",
+				DhcpOptions:      alicloudclient.DhcpOptions{DomainName: "corp.example.com", DomainNameServers: "10.0.0.2,10.0.1.2"},
+				AssociateVpcs:    inUse,
+			})
+
+			Expect(reconciler.ensureDHCPOptions(ctx)).To(Succeed())
+		})
+
+		It("should update the options of the DHCP options set and associate it again", func() {
+			reconciler.state.Set(IdentifierDHCPOptionsSet, "dopt-1")
+			getDHCPOptionsSet(&alicloudclient.GetDhcpOptionsSetResponse{
+				DhcpOptionsSetId: "dopt-1",
+				DhcpOptions:      alicloudclient.DhcpOptions{DomainName: "old.example.com", DomainNameServers: "10.0.0.2"},
+			})
+			vpcClient.EXPECT().UpdateDhcpOptionsSetAttribute(gomock.Any()).DoAndReturn(func(req *alicloudclient.UpdateDhcpOptionsSetAttributeRequest) (*alicloudclient.DhcpOptionsSetResponse, error) {
+				Expect(req.DhcpOptionsSetId).To(Equal("dopt-1"))
+				Expect(req.DomainName).To(Equal("corp.example.com"))
+				Expect(req.DomainNameServers).To(Equal("10.0.0.2,10.0.1.2"))
+				return &alicloudclient.DhcpOptionsSetResponse{}, nil
+			})
+			vpcClient.EXPECT().AttachDhcpOptionsSetToVpc(gomock.Any()).Return(&alicloudclient.DhcpOptionsSetResponse{}, nil)
+
+			Expect(reconciler.ensureDHCPOptions(ctx)).To(Succeed())
+		})
+
+		It("should replace the DHCP options set if an option is removed", func() {
+			config.Networks.DHCPOptions.DomainName = nil
+			reconciler.state.Set(IdentifierDHCPOptionsSet, "dopt-1")
+			existing := &alicloudclient.GetDhcpOptionsSetResponse{
+				DhcpOptionsSetId: "dopt-1",
+				DhcpOptions:      alicloudclient.DhcpOptions{DomainName: "corp.example.com", DomainNameServers: "10.0.0.2,10.0.1.2"},
+			}
+			getDHCPOptionsSet(existing)
+			getDHCPOptionsSet(existing)
+			gomock.InOrder(
+				vpcClient.EXPECT().DeleteDhcpOptionsSet(gomock.Any()).Return(&alicloudclient.DhcpOptionsSetResponse{}, nil),
+				vpcClient.EXPECT().CreateDhcpOptionsSet(gomock.Any()).DoAndReturn(func(req *alicloudclient.CreateDhcpOptionsSetRequest) (*alicloudclient.CreateDhcpOptionsSetResponse, error) {
+					Expect(req.DomainName).To(BeEmpty())
+					return &alicloudclient.CreateDhcpOptionsSetResponse{DhcpOptionsSetId: "dopt-2"}, nil
+				}),
+				vpcClient.EXPECT().AttachDhcpOptionsSetToVpc(gomock.Any()).Return(&alicloudclient.DhcpOptionsSetResponse{}, nil),
+			)
+
+			Expect(reconciler.ensureDHCPOptions(ctx)).To(Succeed())
+			Expect(reconciler.state.Get(IdentifierDHCPOptionsSet)).To(Equal("dopt-2"))
+		})
+
+		It("should unassociate the DHCP options set if DHCP options are no longer configured", func() {
+			config.Networks.DHCPOptions = nil
+			reconciler.state.Set(IdentifierDHCPOptionsSet, "dopt-1")
+			getDHCPOptionsSet(&alicloudclient.GetDhcpOptionsSetResponse{DhcpOptionsSetId: "dopt-1", AssociateVpcs: inUse})
+			vpcClient.EXPECT().DetachDhcpOptionsSetFromVpc(gomock.Any()).DoAndReturn(func(req *alicloudclient.DetachDhcpOptionsSetFromVpcRequest) (*alicloudclient.DhcpOptionsSetResponse, error) {
+				Expect(req.DhcpOptionsSetId).To(Equal("dopt-1"))
+				Expect(req.VpcId).To(Equal("vpc-1"))
+				return &alicloudclient.DhcpOptionsSetResponse{}, nil
+			})
+
+			Expect(reconciler.ensureDHCPOptions(ctx)).To(MatchError(ContainSubstring("waiting until DHCP options set dopt-1 is unassociated")))
+			Expect(reconciler.state.Get(IdentifierDHCPOptionsSet)).To(Equal("dopt-1"))
+		})
+	})
+
+	Describe("#deleteDHCPOptions", func() {
+		It("should do nothing if no DHCP options set has been created", func() {
+			Expect(reconciler.deleteDHCPOptions(ctx)).To(Succeed())
+		})
+
+		It("should wait while the DHCP options set is being unassociated", func() {
+			reconciler.state.Set(IdentifierDHCPOptionsSet, "dopt-1")
+			getDHCPOptionsSet(&alicloudclient.GetDhcpOptionsSetResponse{
+				DhcpOptionsSetId: "dopt-1",
+				AssociateVpcs:    []alicloudclient.AssociateVpc{{VpcId: "vpc-1", AssociateStatus: "Pending"}},
+			})
+
+			Expect(reconciler.deleteDHCPOptions(ctx)).To(HaveOccurred())
+		})
+
+		It("should delete the unassociated DHCP options set", func() {
+			reconciler.state.Set(IdentifierDHCPOptionsSet, "dopt-1")
+			getDHCPOptionsSet(&alicloudclient.GetDhcpOptionsSetResponse{DhcpOptionsSetId: "dopt-1"})
+			vpcClient.EXPECT().DeleteDhcpOptionsSet(gomock.Any()).DoAndReturn(func(req *alicloudclient.DeleteDhcpOptionsSetRequest) (*alicloudclient.DhcpOptionsSetResponse, error) {
+				Expect(req.DhcpOptionsSetId).To(Equal("dopt-1"))
+				return &alicloudclient.DhcpOptionsSetResponse{}, nil
+			})
+
+			Expect(reconciler.deleteDHCPOptions(ctx)).To(Succeed())
+			Expect(reconciler.state.Get(IdentifierDHCPOptionsSet)).To(BeEmpty())
+		})
+	})
+
+	Describe("#reconcileWithTerraform", func() {
+		It("should reject DHCP options as they are only supported by the flow reconciler", func() {
+			a := &actuator{}
+
+			err := a.reconcileWithTerraform(ctx, &extensionsv1alpha1.Infrastructure{}, nil, config, nil)
+			Expect(err).To(MatchError(ContainSubstring("only supported by the flow reconciler")))
+		})
+	})
+})
@@ -0,0 +1,170 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gardener/gardener-extension-provider-alicloud/pkg/alicloud"
+
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/ecs"
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/vpc"
+)
+
+// Discover completes the given resources with the IDs of existing resources which carry the UID tag of the shoot,
+// e.g. if the state of an Infrastructure got lost. Only resources whose tag exactly matches the UID of the shoot are
+// discovered, so that the resources of other shoots, even with the same name, are never adopted. Resources which are
+// given explicitly are not discovered, and the discovered resources are verified by Adopt like the given ones.
+func (r *flowReconciler) Discover(ctx context.Context, resources *AdoptedResources) error {
+	uid := r.tags[alicloud.TagKeyShootUID]
+	if uid == "" {
+		return fmt.Errorf("resources cannot be discovered without the UID of the shoot")
+	}
+
+	vpcID := resources.VPC
+	if !r.isVPCManaged() {
+		vpcID = *r.config.Networks.VPC.ID
+	} else if vpcID == "" {
+		ids, err := r.listTaggedResources(tagResourceTypeVPC, uid)
+		if err != nil {
+			return err
+		}
+		if vpcID, err = singleDiscoveredResource("VPC", ids); err != nil {
+			return err
+		}
+		resources.VPC = vpcID
+	}
+	if vpcID == "" {
+		// All other resources belong to the VPC, hence there is nothing else to discover.
+		return nil
+	}
+
+	if r.isVPCManaged() && resources.NATGateway == "" && !isNATGatewayPerZone(r.config) {
+		ids, err := r.listTaggedResources(tagResourceTypeNATGateway, uid)
+		if err != nil {
+			return err
+		}
+		if resources.NATGateway, err = singleDiscoveredResource("NAT gateway", ids); err != nil {
+			return err
+		}
+	}
+
+	if len(resources.VSwitches) == 0 {
+		vswitches, err := r.discoverVSwitches(vpcID, uid)
+		if err != nil {
+			return err
+		}
+		resources.VSwitches = vswitches
+	}
+
+	if resources.SecurityGroup == "" && r.isSecurityGroupManaged() {
+		securityGroups, err := r.ecsClient.GetSecurityGroupsByTags(ctx, vpcID, map[string]string{alicloud.TagKeyShootUID: uid})
+		if err != nil {
+			return err
+		}
+		var ids []string
+		for _, securityGroup := range securityGroups {
+			if hasShootUIDTag(securityGroup.Tags.Tag, uid) {
+				ids = append(ids, securityGroup.SecurityGroupId)
+			}
+		}
+		if resources.SecurityGroup, err = singleDiscoveredResource("security group", ids); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// discoverVSwitches returns the IDs of the worker vswitches of the given VPC which carry the UID tag of the shoot
+// keyed by their zones. As the vswitches of a zone are adopted in the order of their CIDRs, they are only discovered
+// up to the first CIDR without vswitch.
+func (r *flowReconciler) discoverVSwitches(vpcID, uid string) (map[string][]string, error) {
+	ids, err := r.listTaggedResources(tagResourceTypeVSwitch, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	discovered := map[string]map[string]string{}
+	for _, id := range ids {
+		existing, err := r.describeVSwitch(id)
+		if err != nil {
+			return nil, err
+		}
+		if existing == nil || existing.VpcId != vpcID {
+			continue
+		}
+		if discovered[existing.ZoneId] == nil {
+			discovered[existing.ZoneId] = map[string]string{}
+		}
+		discovered[existing.ZoneId][existing.CidrBlock] = existing.VSwitchId
+	}
+
+	vswitches := map[string][]string{}
+	for _, zone := range r.config.Networks.Zones {
+		for _, cidr := range zoneWorkerCIDRs(zone) {
+			id, ok := discovered[zone.Name][cidr]
+			if !ok {
+				break
+			}
+			vswitches[zone.Name] = append(vswitches[zone.Name], id)
+		}
+	}
+	return vswitches, nil
+}
+
+// listTaggedResources returns the IDs of the VPC resources of the given type which carry the UID tag of the shoot.
+func (r *flowReconciler) listTaggedResources(resourceType, uid string) ([]string, error) {
+	var ids []string
+	req := vpc.CreateListTagResourcesRequest()
+	req.ResourceType = resourceType
+	req.Tag = &[]vpc.ListTagResourcesTag{{Key: alicloud.TagKeyShootUID, Value: uid}}
+	for {
+		res, err := r.vpcClient.ListTagResources(req)
+		if err != nil {
+			return nil, err
+		}
+		for _, tagResource := range res.TagResources.TagResource {
+			if tagResource.TagKey == alicloud.TagKeyShootUID && tagResource.TagValue == uid {
+				ids = append(ids, tagResource.ResourcId)
+			}
+		}
+		if res.NextToken == "" {
+			return ids, nil
+		}
+		req.NextToken = res.NextToken
+	}
+}
+
+func hasShootUIDTag(tags []ecs.Tag, uid string) bool {
+	for _, tag := range tags {
+		if tag.TagKey == alicloud.TagKeyShootUID && tag.TagValue == uid {
+			return true
+		}
+	}
+	return false
+}
+
+func singleDiscoveredResource(kind string, ids []string) (string, error) {
+	switch len(ids) {
+	case 0:
+		return "", nil
+	case 1:
+		return ids[0], nil
+	default:
+		return "", fmt.Errorf("%d %ss %v carry the UID tag of the shoot, the one to adopt must be given explicitly", len(ids), kind, ids)
+	}
+}
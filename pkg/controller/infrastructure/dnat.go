@@ -0,0 +1,232 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	alicloudclient "github.com/gardener/gardener-extension-provider-alicloud/pkg/alicloud/client"
+	alicloudv1alpha1 "github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/alicloud/v1alpha1"
+
+	"github.com/aliyun/alibaba-cloud-sdk-go/sdk/requests"
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/vpc"
+)
+
+const forwardTableEntriesPageSize = 50
+
+// dnatEntryName returns the name of the DNAT entries managed for the Infrastructure in the given namespace. Only
+// entries with this name are updated or deleted, DNAT entries added to an existing NAT gateway by other means are
+// left untouched.
+func dnatEntryName(namespace string) string {
+	return fmt.Sprintf("%s-dnat", namespace)
+}
+
+// dnatEntries returns the configured DNAT entries.
+func dnatEntries(config *alicloudv1alpha1.InfrastructureConfig) []alicloudv1alpha1.DNATEntry {
+	if config.Networks.NatGateway == nil {
+		return nil
+	}
+	return config.Networks.NatGateway.DNATEntries
+}
+
+// dnatNATGatewayIDs returns the IDs of the NAT gateways in the given state which DNAT entries can be added to.
+func dnatNATGatewayIDs(config *alicloudv1alpha1.InfrastructureConfig, state *FlowState) []string {
+	var identifiers []string
+	if isNATGatewayPerZone(config) {
+		for zoneIndex := range config.Networks.Zones {
+			identifiers = append(identifiers, ZoneIdentifier(zoneIndex, IdentifierZoneNATGateway))
+		}
+	} else {
+		identifiers = append(identifiers, IdentifierNATGateway)
+	}
+
+	var natGatewayIDs []string
+	for _, identifier := range identifiers {
+		if natGatewayID := state.Get(identifier); natGatewayID != "" {
+			natGatewayIDs = append(natGatewayIDs, natGatewayID)
+		}
+	}
+	return natGatewayIDs
+}
+
+// forwardTable is the forward table of a NAT gateway together with the IP addresses of the NAT gateway's EIPs.
+type forwardTable struct {
+	id          string
+	ipAddresses []string
+}
+
+func (t forwardTable) hasIPAddress(ipAddress string) bool {
+	for _, a := range t.ipAddresses {
+		if a == ipAddress {
+			return true
+		}
+	}
+	return false
+}
+
+func describeForwardTables(vpcClient alicloudclient.VPC, natGatewayIDs []string) ([]forwardTable, error) {
+	var tables []forwardTable
+	for _, natGatewayID := range natGatewayIDs {
+		req := vpc.CreateDescribeNatGatewaysRequest()
+		req.NatGatewayId = natGatewayID
+		res, err := vpcClient.DescribeNatGateways(req)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, natGateway := range res.NatGateways.NatGateway {
+			if len(natGateway.ForwardTableIds.ForwardTableId) == 0 {
+				continue
+			}
+
+			table := forwardTable{id: natGateway.ForwardTableIds.ForwardTableId[0]}
+			for _, ip := range natGateway.IpLists.IpList {
+				table.ipAddresses = append(table.ipAddresses, ip.IpAddress)
+			}
+			tables = append(tables, table)
+		}
+	}
+	return tables, nil
+}
+
+func describeForwardTableEntries(vpcClient alicloudclient.VPC, forwardTableID string) ([]vpc.ForwardTableEntry, error) {
+	var (
+		entries    []vpc.ForwardTableEntry
+		pageNumber = 1
+		req        = vpc.CreateDescribeForwardTableEntriesRequest()
+	)
+	req.ForwardTableId = forwardTableID
+	req.PageSize = requests.NewInteger(forwardTableEntriesPageSize)
+
+	for {
+		req.PageNumber = requests.NewInteger(pageNumber)
+		res, err := vpcClient.DescribeForwardTableEntries(req)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, res.ForwardTableEntries.ForwardTableEntry...)
+
+		if pageNumber*forwardTableEntriesPageSize >= res.TotalCount {
+			break
+		}
+		pageNumber++
+	}
+	return entries, nil
+}
+
+// matchesExternal checks whether the given forward table entry receives the traffic of the given DNAT entry.
+func matchesExternal(existing vpc.ForwardTableEntry, entry alicloudv1alpha1.DNATEntry) bool {
+	return existing.ExternalIp == entry.ExternalIP &&
+		existing.ExternalPort == strconv.Itoa(int(entry.ExternalPort)) &&
+		strings.EqualFold(existing.IpProtocol, entry.Protocol)
+}
+
+// matchesInternal checks whether the given forward table entry forwards the traffic to the target of the given DNAT entry.
+func matchesInternal(existing vpc.ForwardTableEntry, entry alicloudv1alpha1.DNATEntry) bool {
+	return existing.InternalIp == entry.InternalIP && existing.InternalPort == strconv.Itoa(int(entry.InternalPort))
+}
+
+// reconcileDNATEntries ensures that the forward tables of the NAT gateways with the given IDs contain exactly the given
+// DNAT entries with the given name. Every entry is added to the NAT gateway which its external IP belongs to. Entries
+// with the given name which are no longer desired are deleted, entries with a different target are replaced.
+func reconcileDNATEntries(vpcClient alicloudclient.VPC, name string, natGatewayIDs []string, entries []alicloudv1alpha1.DNATEntry) error {
+	tables, err := describeForwardTables(vpcClient, natGatewayIDs)
+	if err != nil {
+		return err
+	}
+
+	desired := make(map[string][]alicloudv1alpha1.DNATEntry, len(tables))
+	for _, entry := range entries {
+		var found bool
+		for _, table := range tables {
+			if table.hasIPAddress(entry.ExternalIP) {
+				desired[table.id] = append(desired[table.id], entry)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("external IP %s of DNAT entry is not an EIP of the NAT gateway", entry.ExternalIP)
+		}
+	}
+
+	for _, table := range tables {
+		existingEntries, err := describeForwardTableEntries(vpcClient, table.id)
+		if err != nil {
+			return err
+		}
+
+		missing := desired[table.id]
+		for _, existing := range existingEntries {
+			owned := existing.ForwardEntryName == name
+
+			index := -1
+			for i, entry := range missing {
+				if matchesExternal(existing, entry) {
+					index = i
+					break
+				}
+			}
+
+			if index >= 0 {
+				if !owned {
+					return fmt.Errorf("port %s/%s of IP %s is already forwarded by DNAT entry %s", existing.ExternalPort, existing.IpProtocol, existing.ExternalIp, existing.ForwardEntryId)
+				}
+				if matchesInternal(existing, missing[index]) {
+					missing = append(missing[:index:index], missing[index+1:]...)
+					continue
+				}
+			}
+
+			if owned {
+				req := vpc.CreateDeleteForwardEntryRequest()
+				req.ForwardTableId = table.id
+				req.ForwardEntryId = existing.ForwardEntryId
+				if _, err := vpcClient.DeleteForwardEntry(req); ignoreNotFoundError(err) != nil {
+					return err
+				}
+			}
+		}
+
+		for _, entry := range missing {
+			req := vpc.CreateCreateForwardEntryRequest()
+			req.ForwardTableId = table.id
+			req.ForwardEntryName = name
+			req.ExternalIp = entry.ExternalIP
+			req.ExternalPort = strconv.Itoa(int(entry.ExternalPort))
+			req.InternalIp = entry.InternalIP
+			req.InternalPort = strconv.Itoa(int(entry.InternalPort))
+			req.IpProtocol = strings.ToUpper(entry.Protocol)
+			if _, err := vpcClient.CreateForwardEntry(req); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (r *flowReconciler) ensureDNATEntries(_ context.Context) error {
+	return reconcileDNATEntries(r.vpcClient, dnatEntryName(r.infra.Namespace), dnatNATGatewayIDs(r.config, r.state), dnatEntries(r.config))
+}
+
+// deleteDNATEntries deletes the managed DNAT entries. They have to be deleted before the EIPs can be unassociated from
+// the NAT gateway.
+func (r *flowReconciler) deleteDNATEntries(_ context.Context) error {
+	return reconcileDNATEntries(r.vpcClient, dnatEntryName(r.infra.Namespace), dnatNATGatewayIDs(r.config, r.state), nil)
+}
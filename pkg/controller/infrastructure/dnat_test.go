@@ -0,0 +1,168 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package infrastructure
+
+import (
+	"context"
+
+	alicloudv1alpha1 "github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/alicloud/v1alpha1"
+	mockalicloudclient "github.com/gardener/gardener-extension-provider-alicloud/pkg/mock/provider-alicloud/alicloud/client"
+
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/vpc"
+	mockclient "github.com/gardener/gardener-extensions/pkg/mock/controller-runtime/client"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+)
+
+var _ = Describe("DNAT entries", func() {
+	const name = "shoot--foo--bar-dnat"
+
+	var (
+		ctrl      *gomock.Controller
+		c         *mockclient.MockClient
+		vpcClient *mockalicloudclient.MockVPC
+
+		ctx = context.TODO()
+
+		config     *alicloudv1alpha1.InfrastructureConfig
+		reconciler *flowReconciler
+
+		sshEntry = alicloudv1alpha1.DNATEntry{ExternalIP: "47.95.1.1", ExternalPort: 22, InternalIP: "10.250.0.10", InternalPort: 30022, Protocol: "tcp"}
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		c = mockclient.NewMockClient(ctrl)
+		vpcClient = mockalicloudclient.NewMockVPC(ctrl)
+
+		infra := &extensionsv1alpha1.Infrastructure{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "shoot--foo--bar", Name: "infra"},
+		}
+		config = &alicloudv1alpha1.InfrastructureConfig{
+			Networks: alicloudv1alpha1.Networks{
+				VPC:        alicloudv1alpha1.VPC{CIDR: pointer.StringPtr("10.250.0.0/16")},
+				NatGateway: &alicloudv1alpha1.NatGateway{DNATEntries: []alicloudv1alpha1.DNATEntry{sshEntry}},
+			},
+		}
+
+		var err error
+		reconciler, err = newFlowReconciler(c, infra, config, vpcClient, nil, nil)
+		Expect(err).NotTo(HaveOccurred())
+		reconciler.state.Set(IdentifierNATGateway, "ngw-1")
+
+		vpcClient.EXPECT().DescribeNatGateways(gomock.Any()).DoAndReturn(func(req *vpc.DescribeNatGatewaysRequest) (*vpc.DescribeNatGatewaysResponse, error) {
+			Expect(req.NatGatewayId).To(Equal("ngw-1"))
+			return &vpc.DescribeNatGatewaysResponse{NatGateways: vpc.NatGateways{NatGateway: []vpc.NatGateway{{
+				NatGatewayId:    "ngw-1",
+				ForwardTableIds: vpc.ForwardTableIdsInDescribeNatGateways{ForwardTableId: []string{"ftb-1"}},
+				IpLists:         vpc.IpLists{IpList: []vpc.IpList{{IpAddress: "47.95.1.1"}}},
+			}}}}, nil
+		})
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	describeForwardTableEntries := func(entries ...vpc.ForwardTableEntry) {
+		vpcClient.EXPECT().DescribeForwardTableEntries(gomock.Any()).DoAndReturn(func(req *vpc.DescribeForwardTableEntriesRequest) (*vpc.DescribeForwardTableEntriesResponse, error) {
+			Expect(req.ForwardTableId).To(Equal("ftb-1"))
+			return &vpc.DescribeForwardTableEntriesResponse{
+				TotalCount:          len(entries),
+				ForwardTableEntries: vpc.ForwardTableEntries{ForwardTableEntry: entries},
+			}, nil
+		})
+	}
+
+	expectDeleteForwardEntry := func(forwardEntryID string) {
+		vpcClient.EXPECT().DeleteForwardEntry(gomock.Any()).DoAndReturn(func(req *vpc.DeleteForwardEntryRequest) (*vpc.DeleteForwardEntryResponse, error) {
+			Expect(req.ForwardTableId).To(Equal("ftb-1"))
+			Expect(req.ForwardEntryId).To(Equal(forwardEntryID))
+			return &vpc.DeleteForwardEntryResponse{}, nil
+		})
+	}
+
+	expectCreateForwardEntry := func() {
+		vpcClient.EXPECT().CreateForwardEntry(gomock.Any()).DoAndReturn(func(req *vpc.CreateForwardEntryRequest) (*vpc.CreateForwardEntryResponse, error) {
+			Expect(req.ForwardTableId).To(Equal("ftb-1"))
+			Expect(req.ForwardEntryName).To(Equal(name))
+			Expect(req.ExternalIp).To(Equal("47.95.1.1"))
+			Expect(req.ExternalPort).To(Equal("22"))
+			Expect(req.InternalIp).To(Equal("10.250.0.10"))
+			Expect(req.InternalPort).To(Equal("30022"))
+			Expect(req.IpProtocol).To(Equal("TCP"))
+			return &vpc.CreateForwardEntryResponse{ForwardEntryId: "fwd-new"}, nil
+		})
+	}
+
+	Describe("#ensureDNATEntries", func() {
+		It("should add the new DNAT entries", func() {
+			describeForwardTableEntries()
+			expectCreateForwardEntry()
+
+			Expect(reconciler.ensureDNATEntries(ctx)).To(Succeed())
+		})
+
+		It("should not touch DNAT entries which are up to date", func() {
+			describeForwardTableEntries(vpc.ForwardTableEntry{ForwardEntryId: "fwd-1", ForwardEntryName: name, ExternalIp: "47.95.1.1", ExternalPort: "22", InternalIp: "10.250.0.10", InternalPort: "30022", IpProtocol: "TCP"})
+
+			Expect(reconciler.ensureDNATEntries(ctx)).To(Succeed())
+		})
+
+		It("should delete the DNAT entries removed from the config but keep foreign ones", func() {
+			config.Networks.NatGateway.DNATEntries = nil
+			describeForwardTableEntries(
+				vpc.ForwardTableEntry{ForwardEntryId: "fwd-1", ForwardEntryName: name, ExternalIp: "47.95.1.1", ExternalPort: "22", InternalIp: "10.250.0.10", InternalPort: "30022", IpProtocol: "tcp"},
+				vpc.ForwardTableEntry{ForwardEntryId: "fwd-2", ForwardEntryName: "other", ExternalIp: "47.95.1.1", ExternalPort: "80", InternalIp: "10.250.0.11", InternalPort: "8080", IpProtocol: "tcp"},
+			)
+			expectDeleteForwardEntry("fwd-1")
+
+			Expect(reconciler.ensureDNATEntries(ctx)).To(Succeed())
+		})
+
+		It("should replace DNAT entries whose target has changed", func() {
+			describeForwardTableEntries(vpc.ForwardTableEntry{ForwardEntryId: "fwd-1", ForwardEntryName: name, ExternalIp: "47.95.1.1", ExternalPort: "22", InternalIp: "10.250.0.99", InternalPort: "30022", IpProtocol: "tcp"})
+			expectDeleteForwardEntry("fwd-1")
+			expectCreateForwardEntry()
+
+			Expect(reconciler.ensureDNATEntries(ctx)).To(Succeed())
+		})
+
+		It("should fail if the port is already forwarded by a foreign DNAT entry", func() {
+			describeForwardTableEntries(vpc.ForwardTableEntry{ForwardEntryId: "fwd-2", ForwardEntryName: "other", ExternalIp: "47.95.1.1", ExternalPort: "22", InternalIp: "10.250.0.11", InternalPort: "22", IpProtocol: "tcp"})
+
+			Expect(reconciler.ensureDNATEntries(ctx)).To(MatchError(ContainSubstring("already forwarded by DNAT entry fwd-2")))
+		})
+
+		It("should fail if the external IP is not an EIP of the NAT gateway", func() {
+			config.Networks.NatGateway.DNATEntries[0].ExternalIP = "47.95.9.9"
+
+			Expect(reconciler.ensureDNATEntries(ctx)).To(MatchError(ContainSubstring("external IP 47.95.9.9 of DNAT entry is not an EIP of the NAT gateway")))
+		})
+	})
+
+	Describe("#deleteDNATEntries", func() {
+		It("should delete all managed DNAT entries", func() {
+			describeForwardTableEntries(vpc.ForwardTableEntry{ForwardEntryId: "fwd-1", ForwardEntryName: name, ExternalIp: "47.95.1.1", ExternalPort: "22", InternalIp: "10.250.0.10", InternalPort: "30022", IpProtocol: "tcp"})
+			expectDeleteForwardEntry("fwd-1")
+
+			Expect(reconciler.deleteDNATEntries(ctx)).To(Succeed())
+		})
+	})
+})
@@ -0,0 +1,115 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package infrastructure
+
+import (
+	"fmt"
+	"strings"
+
+	alicloudv1alpha1 "github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/alicloud/v1alpha1"
+
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+const (
+	// EventReasonVPCReady is the reason of the event recording the ID of the VPC.
+	EventReasonVPCReady = "VPCReady"
+	// EventReasonNATGatewayReady is the reason of the event recording the ID of the NAT gateway.
+	EventReasonNATGatewayReady = "NATGatewayReady"
+	// EventReasonVSwitchReady is the reason of the events recording the IDs of the vswitches.
+	EventReasonVSwitchReady = "VSwitchReady"
+	// EventReasonInfrastructureDeleted is the reason of the event summarizing the deleted resources.
+	EventReasonInfrastructureDeleted = "InfrastructureDeleted"
+	// EventReasonSecurityGroupRulesMissing is the reason of the event warning about an existing security group which
+	// lacks rules needed for the traffic within the cluster.
+	EventReasonSecurityGroupRulesMissing = "SecurityGroupRulesMissing"
+	// EventReasonSecurityGroupDriftCorrected is the reason of the event listing the rules of the managed security group
+	// which were changed outside of Gardener and have been corrected.
+	EventReasonSecurityGroupDriftCorrected = "SecurityGroupDriftCorrected"
+)
+
+// resourceTypeNames are the human readable names of the resource types used in events.
+var resourceTypeNames = map[string]string{
+	tagResourceTypeVPC:           "VPC",
+	tagResourceTypeNATGateway:    "NAT gateway",
+	tagResourceTypeVSwitch:       "vswitches",
+	tagResourceTypeEIP:           "EIPs",
+	tagResourceTypeSecurityGroup: "security group",
+	tagResourceTypeKeyPair:       "key pair",
+}
+
+// recordReconcileEvents emits events with the IDs of the VPC, the NAT gateways, and the vswitches of the given status
+// which are not in the current status of the infrastructure, i.e. which have been created or replaced.
+func (a *actuator) recordReconcileEvents(infra *extensionsv1alpha1.Infrastructure, status *alicloudv1alpha1.InfrastructureStatus, natGatewayID string) {
+	previous := &alicloudv1alpha1.InfrastructureStatus{}
+	if providerStatus := infra.Status.ProviderStatus; providerStatus != nil {
+		if _, _, err := a.Decoder().Decode(providerStatus.Raw, nil, previous); err != nil {
+			a.logger.Error(err, "could not decode infrastructure status, recording events for all resources", "infrastructure", infra.Name)
+			previous = &alicloudv1alpha1.InfrastructureStatus{}
+		}
+	}
+
+	vpcChanged := status.VPC.ID != previous.VPC.ID
+	if status.VPC.ID != "" && vpcChanged {
+		a.recorder.Eventf(infra, corev1.EventTypeNormal, EventReasonVPCReady, "VPC %s is ready", status.VPC.ID)
+	}
+	// The status does not contain the ID of the NAT gateway which is shared by all zones. It is created or looked up
+	// together with the VPC, or replaces the NAT gateways of the zones.
+	if natGatewayID != "" && (vpcChanged || len(previous.VPC.NatGateways) > 0) {
+		a.recorder.Eventf(infra, corev1.EventTypeNormal, EventReasonNATGatewayReady, "NAT gateway %s is ready", natGatewayID)
+	}
+
+	previousNATGatewayIDs := sets.NewString()
+	for _, natGateway := range previous.VPC.NatGateways {
+		previousNATGatewayIDs.Insert(natGateway.ID)
+	}
+	for _, natGateway := range status.VPC.NatGateways {
+		if !previousNATGatewayIDs.Has(natGateway.ID) {
+			a.recorder.Eventf(infra, corev1.EventTypeNormal, EventReasonNATGatewayReady, "NAT gateway %s in zone %s is ready", natGateway.ID, natGateway.Zone)
+		}
+	}
+
+	previousVSwitchIDs := sets.NewString()
+	for _, vswitch := range previous.VPC.VSwitches {
+		previousVSwitchIDs.Insert(vswitch.ID)
+	}
+	for _, vswitch := range status.VPC.VSwitches {
+		if !previousVSwitchIDs.Has(vswitch.ID) {
+			a.recorder.Eventf(infra, corev1.EventTypeNormal, EventReasonVSwitchReady, "VSwitch %s in zone %s is ready", vswitch.ID, vswitch.Zone)
+		}
+	}
+}
+
+// recordDeleteEvent emits an event summarizing the deleted resources.
+func (a *actuator) recordDeleteEvent(infra *extensionsv1alpha1.Infrastructure, resourceIDs map[string][]string) {
+	a.recorder.Event(infra, corev1.EventTypeNormal, EventReasonInfrastructureDeleted, summarizeDeletedResources(resourceIDs))
+}
+
+// summarizeDeletedResources returns a message listing the given resources.
+func summarizeDeletedResources(resourceIDs map[string][]string) string {
+	var parts []string
+	for _, resourceType := range append(vpcResourceTypes, ecsResourceTypes...) {
+		if ids, ok := resourceIDs[resourceType]; ok {
+			parts = append(parts, fmt.Sprintf("%s %s", resourceTypeNames[resourceType], strings.Join(ids, ", ")))
+		}
+	}
+
+	if len(parts) == 0 {
+		return "Deleted the infrastructure, no resources had been created"
+	}
+	return fmt.Sprintf("Deleted %s", strings.Join(parts, "; "))
+}
@@ -0,0 +1,166 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package infrastructure
+
+import (
+	"encoding/json"
+
+	"github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/alicloud/install"
+	alicloudv1alpha1 "github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/alicloud/v1alpha1"
+
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var _ = Describe("Events", func() {
+	Describe("#recordReconcileEvents", func() {
+		var (
+			recorder *record.FakeRecorder
+			a        *actuator
+			infra    *extensionsv1alpha1.Infrastructure
+			status   *alicloudv1alpha1.InfrastructureStatus
+		)
+
+		setPreviousStatus := func(previous *alicloudv1alpha1.InfrastructureStatus) {
+			previous.TypeMeta = StatusTypeMeta
+			raw, err := json.Marshal(previous)
+			Expect(err).NotTo(HaveOccurred())
+			infra.Status.ProviderStatus = &runtime.RawExtension{Raw: raw}
+		}
+
+		receivedEvents := func() []string {
+			close(recorder.Events)
+			var events []string
+			for event := range recorder.Events {
+				events = append(events, event)
+			}
+			return events
+		}
+
+		BeforeEach(func() {
+			recorder = record.NewFakeRecorder(10)
+
+			scheme := runtime.NewScheme()
+			install.Install(scheme)
+			a = &actuator{logger: log.Log, recorder: recorder}
+			Expect(a.InjectScheme(scheme)).To(Succeed())
+
+			infra = &extensionsv1alpha1.Infrastructure{ObjectMeta: metav1.ObjectMeta{Namespace: "shoot--foo--bar", Name: "infra"}}
+			status = &alicloudv1alpha1.InfrastructureStatus{
+				VPC: alicloudv1alpha1.VPCStatus{
+					ID: "vpc-1",
+					VSwitches: []alicloudv1alpha1.VSwitch{
+						{Purpose: alicloudv1alpha1.PurposeNodes, ID: "vsw-a", Zone: "cn-beijing-a"},
+						{Purpose: alicloudv1alpha1.PurposeNodes, ID: "vsw-b", Zone: "cn-beijing-b"},
+					},
+				},
+			}
+		})
+
+		It("should record the resources of a new infrastructure", func() {
+			a.recordReconcileEvents(infra, status, "ngw-1")
+
+			Expect(receivedEvents()).To(Equal([]string{
+				"Normal VPCReady VPC vpc-1 is ready",
+				"Normal NATGatewayReady NAT gateway ngw-1 is ready",
+				"Normal VSwitchReady VSwitch vsw-a in zone cn-beijing-a is ready",
+				"Normal VSwitchReady VSwitch vsw-b in zone cn-beijing-b is ready",
+			}))
+		})
+
+		It("should not record anything if the resources are unchanged", func() {
+			setPreviousStatus(status.DeepCopy())
+
+			a.recordReconcileEvents(infra, status, "ngw-1")
+
+			Expect(receivedEvents()).To(BeEmpty())
+		})
+
+		It("should only record the new and replaced vswitches", func() {
+			previous := status.DeepCopy()
+			previous.VPC.VSwitches = previous.VPC.VSwitches[:1]
+			setPreviousStatus(previous)
+			status.VPC.VSwitches[0].ID = "vsw-a2"
+
+			a.recordReconcileEvents(infra, status, "ngw-1")
+
+			Expect(receivedEvents()).To(Equal([]string{
+				"Normal VSwitchReady VSwitch vsw-a2 in zone cn-beijing-a is ready",
+				"Normal VSwitchReady VSwitch vsw-b in zone cn-beijing-b is ready",
+			}))
+		})
+
+		It("should record the NAT gateway if the VPC has been replaced", func() {
+			previous := status.DeepCopy()
+			previous.VPC.ID = "vpc-0"
+			setPreviousStatus(previous)
+
+			a.recordReconcileEvents(infra, status, "ngw-1")
+
+			Expect(receivedEvents()).To(Equal([]string{
+				"Normal VPCReady VPC vpc-1 is ready",
+				"Normal NATGatewayReady NAT gateway ngw-1 is ready",
+			}))
+		})
+
+		It("should only record the new NAT gateways of the zones", func() {
+			previous := status.DeepCopy()
+			previous.VPC.NatGateways = []alicloudv1alpha1.NatGatewayStatus{{ID: "ngw-a", Zone: "cn-beijing-a"}}
+			setPreviousStatus(previous)
+			status.VPC.NatGateways = []alicloudv1alpha1.NatGatewayStatus{
+				{ID: "ngw-a", Zone: "cn-beijing-a"},
+				{ID: "ngw-b", Zone: "cn-beijing-b"},
+			}
+
+			a.recordReconcileEvents(infra, status, "")
+
+			Expect(receivedEvents()).To(Equal([]string{
+				"Normal NATGatewayReady NAT gateway ngw-b in zone cn-beijing-b is ready",
+			}))
+		})
+
+		It("should record the shared NAT gateway if it replaces the NAT gateways of the zones", func() {
+			previous := status.DeepCopy()
+			previous.VPC.NatGateways = []alicloudv1alpha1.NatGatewayStatus{{ID: "ngw-a", Zone: "cn-beijing-a"}}
+			setPreviousStatus(previous)
+
+			a.recordReconcileEvents(infra, status, "ngw-1")
+
+			Expect(receivedEvents()).To(Equal([]string{
+				"Normal NATGatewayReady NAT gateway ngw-1 is ready",
+			}))
+		})
+	})
+
+	Describe("#summarizeDeletedResources", func() {
+		It("should list the deleted resources by type", func() {
+			Expect(summarizeDeletedResources(map[string][]string{
+				tagResourceTypeKeyPair:    {"shoot--foo--bar-ssh-publickey"},
+				tagResourceTypeVSwitch:    {"vsw-a", "vsw-b"},
+				tagResourceTypeVPC:        {"vpc-1"},
+				tagResourceTypeNATGateway: {"ngw-1"},
+			})).To(Equal("Deleted VPC vpc-1; NAT gateway ngw-1; vswitches vsw-a, vsw-b; key pair shoot--foo--bar-ssh-publickey"))
+		})
+
+		It("should report that no resources had been created", func() {
+			Expect(summarizeDeletedResources(map[string][]string{})).To(Equal("Deleted the infrastructure, no resources had been created"))
+		})
+	})
+})
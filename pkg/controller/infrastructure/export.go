@@ -0,0 +1,273 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	alicloudv1alpha1 "github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/alicloud/v1alpha1"
+	extensioncontroller "github.com/gardener/gardener-extensions/pkg/controller"
+
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// AnnotationKeyExport is the annotation key on Infrastructure resources that requests an export of the infrastructure
+// resources as Terraform configuration. The export is written to a ConfigMap next to the Infrastructure during the
+// next reconciliation, afterwards the annotation is removed.
+const AnnotationKeyExport = "alicloud.provider.extensions.gardener.cloud/export"
+
+// ExportConfigMapKey is the key of the Terraform configuration in the ConfigMap of the export.
+const ExportConfigMapKey = "infrastructure.tf"
+
+// ShouldExport checks whether the resources of the given Infrastructure should be exported.
+func ShouldExport(infra *extensionsv1alpha1.Infrastructure) bool {
+	return infra.Annotations[AnnotationKeyExport] == "true"
+}
+
+// ExportConfigMapName returns the name of the ConfigMap which contains the export of the given Infrastructure.
+func ExportConfigMapName(infra *extensionsv1alpha1.Infrastructure) string {
+	return infra.Name + "-export"
+}
+
+// hclBlock is a Terraform data source with attributes whose values are already rendered as HCL.
+type hclBlock struct {
+	comment    string
+	typ        string
+	name       string
+	attributes map[string]string
+}
+
+func hclString(s string) string {
+	return strconv.Quote(s)
+}
+
+func hclList(values ...string) string {
+	quoted := make([]string, 0, len(values))
+	for _, value := range values {
+		quoted = append(quoted, hclString(value))
+	}
+	return "[" + strings.Join(quoted, ", ") + "]"
+}
+
+func (b hclBlock) write(sb *strings.Builder) {
+	keys := make([]string, 0, len(b.attributes))
+	width := 0
+	for key := range b.attributes {
+		keys = append(keys, key)
+		if len(key) > width {
+			width = len(key)
+		}
+	}
+	sort.Strings(keys)
+
+	sb.WriteString("\n")
+	if b.comment != "" {
+		fmt.Fprintf(sb, "// %s\n", b.comment)
+	}
+	fmt.Fprintf(sb, "data %q %q {\n", b.typ, b.name)
+	for _, key := range keys {
+		fmt.Fprintf(sb, "  %-*s = %s\n", width, key, b.attributes[key])
+	}
+	sb.WriteString("}\n")
+}
+
+func managedComment(managed bool, resource string) string {
+	if managed {
+		return fmt.Sprintf("The %s is managed by Gardener.", resource)
+	}
+	return fmt.Sprintf("The %s is not managed by Gardener.", resource)
+}
+
+// ExportHCL renders the infrastructure resources of the given status as Terraform configuration. Every resource is a
+// data source selecting it by its ID and the known attributes like the CIDRs, so that the configuration can be read
+// by humans and verified with `terraform plan` without changing any resources.
+func ExportHCL(namespace string, config *alicloudv1alpha1.InfrastructureConfig, status *alicloudv1alpha1.InfrastructureStatus) string {
+	var blocks []hclBlock
+
+	vpcManaged := config.Networks.VPC.ID == nil
+	vpcAttributes := map[string]string{"ids": hclList(status.VPC.ID)}
+	if config.Networks.VPC.CIDR != nil {
+		vpcAttributes["cidr_block"] = hclString(*config.Networks.VPC.CIDR)
+	}
+	blocks = append(blocks, hclBlock{comment: managedComment(vpcManaged, "VPC"), typ: "alicloud_vpcs", name: "vpc", attributes: vpcAttributes})
+
+	natGateways := map[string]alicloudv1alpha1.NatGatewayStatus{}
+	for _, natGateway := range status.VPC.NatGateways {
+		natGateways[natGateway.Zone] = natGateway
+	}
+
+	for zoneIndex, zone := range config.Networks.Zones {
+		workerCIDRs := zoneWorkerCIDRs(zone)
+		vswitchIndex := 0
+		for _, vswitch := range status.VPC.VSwitches {
+			if vswitch.Zone != zone.Name {
+				continue
+			}
+
+			var name, cidr string
+			switch vswitch.Purpose {
+			case alicloudv1alpha1.PurposeNodes:
+				if vswitchIndex >= len(workerCIDRs) {
+					continue
+				}
+				name, cidr = fmt.Sprintf("vsw_z%d", zoneIndex), workerCIDRs[vswitchIndex]
+				if vswitchIndex > 0 {
+					name = fmt.Sprintf("%s_%d", name, vswitchIndex)
+				}
+				vswitchIndex++
+			case alicloudv1alpha1.PurposePods:
+				if zone.PodsCIDR == nil {
+					continue
+				}
+				name, cidr = fmt.Sprintf("vsw_pods_z%d", zoneIndex), *zone.PodsCIDR
+			default:
+				continue
+			}
+
+			blocks = append(blocks, hclBlock{
+				comment: managedComment(true, fmt.Sprintf("%s vswitch in zone %s", vswitch.Purpose, zone.Name)),
+				typ:     "alicloud_vswitches",
+				name:    name,
+				attributes: map[string]string{
+					"ids":        hclList(vswitch.ID),
+					"vpc_id":     hclString(status.VPC.ID),
+					"cidr_block": hclString(cidr),
+					"zone_id":    hclString(zone.Name),
+				},
+			})
+		}
+
+		natGateway, ok := natGateways[zone.Name]
+		if !ok {
+			continue
+		}
+		if natGateway.VSwitchID != "" && zone.NatGatewayCIDR != nil {
+			blocks = append(blocks, hclBlock{
+				comment: managedComment(true, fmt.Sprintf("vswitch of the NAT gateway in zone %s", zone.Name)),
+				typ:     "alicloud_vswitches",
+				name:    fmt.Sprintf("vsw_natgw_z%d", zoneIndex),
+				attributes: map[string]string{
+					"ids":        hclList(natGateway.VSwitchID),
+					"vpc_id":     hclString(status.VPC.ID),
+					"cidr_block": hclString(*zone.NatGatewayCIDR),
+					"zone_id":    hclString(zone.Name),
+				},
+			})
+		}
+		blocks = append(blocks, hclBlock{
+			comment: managedComment(true, fmt.Sprintf("NAT gateway in zone %s", zone.Name)),
+			typ:     "alicloud_nat_gateways",
+			name:    fmt.Sprintf("nat_gateway_z%d", zoneIndex),
+			attributes: map[string]string{
+				"ids":    hclList(natGateway.ID),
+				"vpc_id": hclString(status.VPC.ID),
+			},
+		})
+		for eipIndex, eip := range natGateway.EIPs {
+			blocks = append(blocks, hclBlock{
+				comment: managedComment(true, fmt.Sprintf("EIP %d of the NAT gateway in zone %s", eipIndex, zone.Name)),
+				typ:     "alicloud_eips",
+				name:    fmt.Sprintf("eip_natgw_z%d_%d", zoneIndex, eipIndex),
+				attributes: map[string]string{
+					"ids":          hclList(eip.AllocationID),
+					"ip_addresses": hclList(eip.IPAddress),
+				},
+			})
+		}
+	}
+
+	for _, securityGroup := range status.VPC.SecurityGroups {
+		blocks = append(blocks, hclBlock{
+			comment: managedComment(config.Networks.SecurityGroupID == nil, fmt.Sprintf("security group of the %s", securityGroup.Purpose)),
+			typ:     "alicloud_security_groups",
+			name:    fmt.Sprintf("sg_%s", securityGroup.Purpose),
+			attributes: map[string]string{
+				"ids":    hclList(securityGroup.ID),
+				"vpc_id": hclString(status.VPC.ID),
+			},
+		})
+	}
+
+	if status.KeyPairName != "" {
+		blocks = append(blocks, hclBlock{
+			comment:    managedComment(true, "key pair"),
+			typ:        "alicloud_key_pairs",
+			name:       "publickey",
+			attributes: map[string]string{"name_regex": hclString("^" + status.KeyPairName + "$")},
+		})
+	}
+
+	sb := &strings.Builder{}
+	fmt.Fprintf(sb, "// Infrastructure of %s as reported in the InfrastructureStatus.\n", namespace)
+	for _, block := range blocks {
+		block.write(sb)
+	}
+	return sb.String()
+}
+
+// exportInfrastructure writes the Terraform configuration of the resources in the current InfrastructureStatus to the
+// ConfigMap of the export and removes the annotation which requested it. It neither reads nor changes any
+// infrastructure resources.
+func (a *actuator) exportInfrastructure(ctx context.Context, infra *extensionsv1alpha1.Infrastructure, config *alicloudv1alpha1.InfrastructureConfig) error {
+	if infra.Status.ProviderStatus == nil {
+		a.logger.Info("Infrastructure has not been created yet, nothing to export", "infrastructure", infra.Name)
+	} else {
+		status := &alicloudv1alpha1.InfrastructureStatus{}
+		if _, _, err := a.Decoder().Decode(infra.Status.ProviderStatus.Raw, nil, status); err != nil {
+			return errors.Wrapf(err, "could not decode infrastructure status")
+		}
+
+		if err := a.writeExport(ctx, infra, ExportHCL(infra.Namespace, config, status)); err != nil {
+			return err
+		}
+	}
+
+	return extensioncontroller.TryUpdate(ctx, retry.DefaultBackoff, a.Client(), infra, func() error {
+		delete(infra.Annotations, AnnotationKeyExport)
+		return nil
+	})
+}
+
+func (a *actuator) writeExport(ctx context.Context, infra *extensionsv1alpha1.Infrastructure, hcl string) error {
+	configMap := &corev1.ConfigMap{}
+	if err := a.Client().Get(ctx, client.ObjectKey{Namespace: infra.Namespace, Name: ExportConfigMapName(infra)}, configMap); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+
+		configMap = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:       infra.Namespace,
+				Name:            ExportConfigMapName(infra),
+				OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(infra, extensionsv1alpha1.SchemeGroupVersion.WithKind(extensionsv1alpha1.InfrastructureResource))},
+			},
+			Data: map[string]string{ExportConfigMapKey: hcl},
+		}
+		return a.Client().Create(ctx, configMap)
+	}
+
+	configMap.Data = map[string]string{ExportConfigMapKey: hcl}
+	return a.Client().Update(ctx, configMap)
+}
@@ -0,0 +1,190 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package infrastructure
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/alicloud/install"
+	alicloudv1alpha1 "github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/alicloud/v1alpha1"
+	mockclient "github.com/gardener/gardener-extensions/pkg/mock/controller-runtime/client"
+
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"github.com/golang/mock/gomock"
+	"github.com/hashicorp/hcl"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// exportedDataSource are the attributes of the data sources of an export.
+type exportedDataSource struct {
+	IDs         []string `hcl:"ids"`
+	CIDRBlock   string   `hcl:"cidr_block"`
+	VPCID       string   `hcl:"vpc_id"`
+	ZoneID      string   `hcl:"zone_id"`
+	IPAddresses []string `hcl:"ip_addresses"`
+	NameRegex   string   `hcl:"name_regex"`
+}
+
+func decodeExport(export string) map[string]map[string]exportedDataSource {
+	var parsed struct {
+		Data map[string]map[string]exportedDataSource `hcl:"data"`
+	}
+	ExpectWithOffset(1, hcl.Decode(&parsed, export)).To(Succeed())
+	return parsed.Data
+}
+
+var _ = Describe("Export", func() {
+	var (
+		config *alicloudv1alpha1.InfrastructureConfig
+		status *alicloudv1alpha1.InfrastructureStatus
+	)
+
+	BeforeEach(func() {
+		config = &alicloudv1alpha1.InfrastructureConfig{
+			Networks: alicloudv1alpha1.Networks{
+				VPC:        alicloudv1alpha1.VPC{CIDR: pointer.StringPtr("10.250.0.0/16")},
+				NatGateway: &alicloudv1alpha1.NatGateway{PerZone: true},
+				Zones: []alicloudv1alpha1.Zone{
+					{Name: "cn-beijing-f", Workers: "10.250.0.0/19", AdditionalWorkers: []string{"10.250.32.0/19"}, PodsCIDR: pointer.StringPtr("10.250.64.0/19")},
+					{Name: "cn-beijing-g", Workers: "10.250.96.0/19", NatGatewayCIDR: pointer.StringPtr("10.250.128.0/24")},
+				},
+			},
+		}
+		status = &alicloudv1alpha1.InfrastructureStatus{
+			VPC: alicloudv1alpha1.VPCStatus{
+				ID: "vpc-1",
+				VSwitches: []alicloudv1alpha1.VSwitch{
+					{Purpose: alicloudv1alpha1.PurposeNodes, ID: "vsw-f-0", Zone: "cn-beijing-f"},
+					{Purpose: alicloudv1alpha1.PurposeNodes, ID: "vsw-f-1", Zone: "cn-beijing-f"},
+					{Purpose: alicloudv1alpha1.PurposePods, ID: "vsw-f-pods", Zone: "cn-beijing-f"},
+					{Purpose: alicloudv1alpha1.PurposeNodes, ID: "vsw-g-0", Zone: "cn-beijing-g"},
+				},
+				NatGateways: []alicloudv1alpha1.NatGatewayStatus{
+					{ID: "ngw-f", Zone: "cn-beijing-f", EIPs: []alicloudv1alpha1.EIPStatus{{AllocationID: "eip-f", IPAddress: "1.2.3.4"}}},
+					{ID: "ngw-g", Zone: "cn-beijing-g", VSwitchID: "vsw-g-natgw", EIPs: []alicloudv1alpha1.EIPStatus{{AllocationID: "eip-g", IPAddress: "5.6.7.8"}}},
+				},
+				SecurityGroups: []alicloudv1alpha1.SecurityGroup{{Purpose: alicloudv1alpha1.PurposeNodes, ID: "sg-1"}},
+			},
+			KeyPairName: "shoot--foo--bar-key-pair",
+		}
+	})
+
+	Describe("#ExportHCL", func() {
+		It("should round-trip the IDs and CIDRs of the resources", func() {
+			Expect(decodeExport(ExportHCL("shoot--foo--bar", config, status))).To(Equal(map[string]map[string]exportedDataSource{
+				"alicloud_vpcs": {
+					"vpc": {IDs: []string{"vpc-1"}, CIDRBlock: "10.250.0.0/16"},
+				},
+				"alicloud_vswitches": {
+					"vsw_z0":       {IDs: []string{"vsw-f-0"}, VPCID: "vpc-1", CIDRBlock: "10.250.0.0/19", ZoneID: "cn-beijing-f"},
+					"vsw_z0_1":     {IDs: []string{"vsw-f-1"}, VPCID: "vpc-1", CIDRBlock: "10.250.32.0/19", ZoneID: "cn-beijing-f"},
+					"vsw_pods_z0":  {IDs: []string{"vsw-f-pods"}, VPCID: "vpc-1", CIDRBlock: "10.250.64.0/19", ZoneID: "cn-beijing-f"},
+					"vsw_z1":       {IDs: []string{"vsw-g-0"}, VPCID: "vpc-1", CIDRBlock: "10.250.96.0/19", ZoneID: "cn-beijing-g"},
+					"vsw_natgw_z1": {IDs: []string{"vsw-g-natgw"}, VPCID: "vpc-1", CIDRBlock: "10.250.128.0/24", ZoneID: "cn-beijing-g"},
+				},
+				"alicloud_nat_gateways": {
+					"nat_gateway_z0": {IDs: []string{"ngw-f"}, VPCID: "vpc-1"},
+					"nat_gateway_z1": {IDs: []string{"ngw-g"}, VPCID: "vpc-1"},
+				},
+				"alicloud_eips": {
+					"eip_natgw_z0_0": {IDs: []string{"eip-f"}, IPAddresses: []string{"1.2.3.4"}},
+					"eip_natgw_z1_0": {IDs: []string{"eip-g"}, IPAddresses: []string{"5.6.7.8"}},
+				},
+				"alicloud_security_groups": {
+					"sg_nodes": {IDs: []string{"sg-1"}, VPCID: "vpc-1"},
+				},
+				"alicloud_key_pairs": {
+					"publickey": {NameRegex: "^shoot--foo--bar-key-pair$"},
+				},
+			}))
+		})
+
+		It("should mark the resources which are not managed by Gardener", func() {
+			config.Networks.VPC = alicloudv1alpha1.VPC{ID: pointer.StringPtr("vpc-1")}
+			config.Networks.SecurityGroupID = pointer.StringPtr("sg-1")
+
+			export := ExportHCL("shoot--foo--bar", config, status)
+			Expect(export).To(ContainSubstring("// The VPC is not managed by Gardener.\ndata \"alicloud_vpcs\" \"vpc\" {\n  ids = [\"vpc-1\"]\n}\n"))
+			Expect(export).To(ContainSubstring("// The security group of the nodes is not managed by Gardener.\n"))
+		})
+	})
+
+	Describe("#exportInfrastructure", func() {
+		var (
+			ctrl  *gomock.Controller
+			c     *mockclient.MockClient
+			a     *actuator
+			infra *extensionsv1alpha1.Infrastructure
+
+			ctx = context.TODO()
+		)
+
+		BeforeEach(func() {
+			ctrl = gomock.NewController(GinkgoT())
+			c = mockclient.NewMockClient(ctrl)
+
+			scheme := runtime.NewScheme()
+			install.Install(scheme)
+			a = &actuator{logger: log.Log}
+			Expect(a.InjectScheme(scheme)).To(Succeed())
+			Expect(a.InjectClient(c)).To(Succeed())
+
+			status.TypeMeta = StatusTypeMeta
+			rawStatus, err := json.Marshal(status)
+			Expect(err).NotTo(HaveOccurred())
+			infra = &extensionsv1alpha1.Infrastructure{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:   "shoot--foo--bar",
+					Name:        "infra",
+					Annotations: map[string]string{AnnotationKeyExport: "true"},
+				},
+				Status: extensionsv1alpha1.InfrastructureStatus{
+					ProviderStatus: &runtime.RawExtension{Raw: rawStatus},
+				},
+			}
+		})
+
+		AfterEach(func() {
+			ctrl.Finish()
+		})
+
+		It("should write the export to a ConfigMap and remove the annotation", func() {
+			c.EXPECT().Get(ctx, client.ObjectKey{Namespace: "shoot--foo--bar", Name: "infra-export"}, gomock.AssignableToTypeOf(&corev1.ConfigMap{})).
+				Return(apierrors.NewNotFound(schema.GroupResource{Resource: "configmaps"}, "infra-export"))
+			c.EXPECT().Create(ctx, gomock.AssignableToTypeOf(&corev1.ConfigMap{})).DoAndReturn(func(_ context.Context, obj runtime.Object, _ ...client.CreateOption) error {
+				configMap := obj.(*corev1.ConfigMap)
+				Expect(configMap.OwnerReferences).To(ConsistOf(MatchFields(IgnoreExtras, Fields{"Kind": Equal("Infrastructure"), "Name": Equal("infra")})))
+				Expect(configMap.Data).To(HaveKeyWithValue(ExportConfigMapKey, ExportHCL("shoot--foo--bar", config, status)))
+				return nil
+			})
+			c.EXPECT().Get(ctx, client.ObjectKey{Namespace: "shoot--foo--bar", Name: "infra"}, infra)
+			c.EXPECT().Update(ctx, infra)
+
+			Expect(a.exportInfrastructure(ctx, infra, config)).To(Succeed())
+			Expect(infra.Annotations).NotTo(HaveKey(AnnotationKeyExport))
+		})
+	})
+})
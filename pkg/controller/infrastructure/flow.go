@@ -0,0 +1,1651 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	alicloudclient "github.com/gardener/gardener-extension-provider-alicloud/pkg/alicloud/client"
+	alicloudv1alpha1 "github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/alicloud/v1alpha1"
+	extensioncontroller "github.com/gardener/gardener-extensions/pkg/controller"
+
+	"github.com/aliyun/alibaba-cloud-sdk-go/sdk/requests"
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/vpc"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"github.com/gardener/gardener/pkg/utils/flow"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// AnnotationKeyUseFlow is the annotation key on Infrastructure resources that enables the flow reconciler
+	// instead of the Terraform-based reconciliation.
+	AnnotationKeyUseFlow = "alicloud.provider.extensions.gardener.cloud/use-flow"
+
+	statusAvailable    = "Available"
+	statusInUse        = "InUse"
+	eipInstanceTypeNat = "Nat"
+	natTypeEnhanced    = "Enhanced"
+
+	defaultRouteCIDR           = "0.0.0.0/0"
+	routeNextHopTypeNatGateway = "NatGateway"
+
+	flowRetryTimeout = 5 * time.Minute
+
+	// flowDeletionWorkers is the maximum number of resources of the same kind which are deleted in parallel.
+	flowDeletionWorkers = 5
+)
+
+// flowRetryInterval is the interval in which the tasks of the flow reconciler are retried, e.g. until a created resource
+// is available. It is a variable so that tests do not have to wait for it.
+var flowRetryInterval = 10 * time.Second
+
+// ShouldUseFlow checks whether the given Infrastructure should be reconciled with the flow reconciler.
+// Once an Infrastructure has been reconciled by the flow reconciler it cannot be switched back to Terraform.
+func ShouldUseFlow(infra *extensionsv1alpha1.Infrastructure) bool {
+	return infra.Annotations[AnnotationKeyUseFlow] == "true" || IsFlowState(infra.Status.State)
+}
+
+// flowReconciler reconciles the infrastructure resources natively with the Alicloud SDK.
+type flowReconciler struct {
+	client    client.Client
+	infra     *extensionsv1alpha1.Infrastructure
+	config    *alicloudv1alpha1.InfrastructureConfig
+	vpcClient alicloudclient.VPC
+	ecsClient alicloudclient.ECS
+	state     *FlowState
+	tags      map[string]string
+
+	persistLock        sync.Mutex
+	vpcCIDR            string
+	internetChargeType string
+}
+
+func newFlowReconciler(
+	c client.Client,
+	infra *extensionsv1alpha1.Infrastructure,
+	config *alicloudv1alpha1.InfrastructureConfig,
+	vpcClient alicloudclient.VPC,
+	ecsClient alicloudclient.ECS,
+	tags map[string]string,
+) (*flowReconciler, error) {
+	state, err := FlowStateFromInfrastructureState(infra.Status.State)
+	if err != nil {
+		return nil, err
+	}
+
+	return &flowReconciler{
+		client:    c,
+		infra:     infra,
+		config:    config,
+		vpcClient: vpcClient,
+		ecsClient: ecsClient,
+		state:     state,
+		tags:      tags,
+	}, nil
+}
+
+func (r *flowReconciler) persistState(ctx context.Context) error {
+	r.persistLock.Lock()
+	defer r.persistLock.Unlock()
+
+	stateBytes, err := r.state.Marshal()
+	if err != nil {
+		return err
+	}
+
+	return extensioncontroller.TryUpdateStatus(ctx, retry.DefaultBackoff, r.client, r.infra, func() error {
+		r.infra.Status.State = &runtime.RawExtension{Raw: stateBytes}
+		return nil
+	})
+}
+
+func (r *flowReconciler) setAndPersist(ctx context.Context, identifier, value string) error {
+	r.state.Set(identifier, value)
+	return r.persistState(ctx)
+}
+
+// deleteInParallel calls deletePiece for the given number of pieces with at most flowDeletionWorkers calls in parallel.
+// A failing piece does not stop the deletion of the other pieces, the errors of all pieces are returned together, so
+// that only the failed pieces are deleted again with the next try.
+func deleteInParallel(ctx context.Context, pieces int, deletePiece func(piece int) error) error {
+	var (
+		lock sync.Mutex
+		errs []error
+	)
+
+	workqueue.ParallelizeUntil(ctx, flowDeletionWorkers, pieces, func(piece int) {
+		if err := deletePiece(piece); err != nil {
+			lock.Lock()
+			defer lock.Unlock()
+			errs = append(errs, err)
+		}
+	})
+
+	if err := ctx.Err(); err != nil {
+		errs = append(errs, err)
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+func (r *flowReconciler) isVPCManaged() bool {
+	return r.config.Networks.VPC.ID == nil
+}
+
+func (r *flowReconciler) isSecurityGroupManaged() bool {
+	return r.config.Networks.SecurityGroupID == nil
+}
+
+func (r *flowReconciler) name(suffix string) string {
+	return fmt.Sprintf("%s-%s", r.infra.Namespace, suffix)
+}
+
+// Reconcile creates or updates all infrastructure resources and returns the resulting InfrastructureStatus.
+func (r *flowReconciler) Reconcile(ctx context.Context) (*alicloudv1alpha1.InfrastructureStatus, error) {
+	var (
+		g = flow.NewGraph("Alicloud infrastructure reconciliation")
+
+		ensureVPC = g.Add(flow.Task{
+			Name: "Ensuring VPC",
+			Fn:   instrumentPhase(PhaseVPC, flow.TaskFn(r.ensureVPC).RetryUntilTimeout(flowRetryInterval, flowRetryTimeout)),
+		})
+		ensureVSwitches = g.Add(flow.Task{
+			Name:         "Ensuring vswitches",
+			Fn:           instrumentPhase(PhaseVSwitches, flow.TaskFn(r.ensureVSwitches).RetryUntilTimeout(flowRetryInterval, flowRetryTimeout)),
+			Dependencies: flow.NewTaskIDs(ensureVPC),
+		})
+		ensureNATVSwitches = g.Add(flow.Task{
+			Name:         "Ensuring NAT gateway vswitches",
+			Fn:           flow.TaskFn(r.ensureNATVSwitches).RetryUntilTimeout(flowRetryInterval, flowRetryTimeout),
+			Dependencies: flow.NewTaskIDs(ensureVPC),
+		})
+		ensurePodsVSwitches = g.Add(flow.Task{
+			Name:         "Ensuring pods vswitches",
+			Fn:           flow.TaskFn(r.ensurePodsVSwitches).RetryUntilTimeout(flowRetryInterval, flowRetryTimeout),
+			Dependencies: flow.NewTaskIDs(ensureVPC),
+		})
+		// NAT gateways per zone are placed in their dedicated vswitch or else in the first vswitch of their zone.
+		ensureNATGateway = g.Add(flow.Task{
+			Name:         "Ensuring NAT gateway",
+			Fn:           instrumentPhase(PhaseNATGateway, flow.TaskFn(r.ensureNATGateway).RetryUntilTimeout(flowRetryInterval, flowRetryTimeout)),
+			Dependencies: flow.NewTaskIDs(ensureVPC, ensureNATVSwitches).InsertIf(isNATGatewayPerZone(r.config), ensureVSwitches),
+		})
+		ensureZoneRouteTables = g.Add(flow.Task{
+			Name:         "Ensuring zone route tables",
+			Fn:           flow.TaskFn(r.ensureZoneRouteTables).RetryUntilTimeout(flowRetryInterval, flowRetryTimeout),
+			Dependencies: flow.NewTaskIDs(ensureNATGateway, ensureVSwitches, ensurePodsVSwitches),
+		})
+		ensureEIPsAndSNATEntries = g.Add(flow.Task{
+			Name:         "Ensuring EIPs and SNAT entries",
+			Fn:           flow.TaskFn(r.ensureEIPsAndSNATEntries).RetryUntilTimeout(flowRetryInterval, flowRetryTimeout),
+			Dependencies: flow.NewTaskIDs(ensureNATGateway, ensureVSwitches, ensurePodsVSwitches),
+		})
+		_ = g.Add(flow.Task{
+			Name:         "Ensuring DNAT entries",
+			Fn:           flow.TaskFn(r.ensureDNATEntries).RetryUntilTimeout(flowRetryInterval, flowRetryTimeout),
+			Dependencies: flow.NewTaskIDs(ensureEIPsAndSNATEntries),
+		})
+		ensureSecurityGroup = g.Add(flow.Task{
+			Name:         "Ensuring security group",
+			Fn:           instrumentPhase(PhaseSecurityGroup, flow.TaskFn(r.ensureSecurityGroup).RetryUntilTimeout(flowRetryInterval, flowRetryTimeout)),
+			Dependencies: flow.NewTaskIDs(ensureVPC),
+		})
+		_ = g.Add(flow.Task{
+			Name:         "Ensuring routes",
+			Fn:           flow.TaskFn(r.ensureRoutes).RetryUntilTimeout(flowRetryInterval, flowRetryTimeout),
+			Dependencies: flow.NewTaskIDs(ensureVPC),
+		})
+		_ = g.Add(flow.Task{
+			Name:         "Ensuring flow log",
+			Fn:           flow.TaskFn(r.ensureFlowLog).RetryUntilTimeout(flowRetryInterval, flowRetryTimeout),
+			Dependencies: flow.NewTaskIDs(ensureVPC),
+		})
+		_ = g.Add(flow.Task{
+			Name:         "Ensuring DHCP options",
+			Fn:           flow.TaskFn(r.ensureDHCPOptions).RetryUntilTimeout(flowRetryInterval, flowRetryTimeout),
+			Dependencies: flow.NewTaskIDs(ensureVPC),
+		})
+		ensureKeyPair = g.Add(flow.Task{
+			Name: "Ensuring key pair",
+			Fn:   flow.TaskFn(r.ensureKeyPair).RetryUntilTimeout(flowRetryInterval, flowRetryTimeout),
+		})
+		_ = g.Add(flow.Task{
+			Name: "Tagging resources",
+			Fn: flow.TaskFn(func(ctx context.Context) error {
+				return tagResources(ctx, r.vpcClient, r.ecsClient, r.config, r.state, r.tags)
+			}).RetryUntilTimeout(flowRetryInterval, flowRetryTimeout),
+			Dependencies: flow.NewTaskIDs(ensureEIPsAndSNATEntries, ensureZoneRouteTables, ensureSecurityGroup, ensureKeyPair),
+		})
+
+		f = g.Compile()
+	)
+
+	if err := f.Run(flow.Opts{Context: ctx}); err != nil {
+		return nil, flow.Causes(err)
+	}
+
+	return r.computeStatus(), nil
+}
+
+func (r *flowReconciler) computeStatus() *alicloudv1alpha1.InfrastructureStatus {
+	var vswitches []alicloudv1alpha1.VSwitch
+	for zoneIndex, zone := range r.config.Networks.Zones {
+		for vswitchIndex := range zoneWorkerCIDRs(zone) {
+			vswitches = append(vswitches, alicloudv1alpha1.VSwitch{
+				Purpose:  alicloudv1alpha1.PurposeNodes,
+				ID:       r.state.Get(VSwitchIdentifier(zoneIndex, vswitchIndex, IdentifierZoneVSwitch)),
+				Zone:     zone.Name,
+				IPv6CIDR: r.state.Get(VSwitchIdentifier(zoneIndex, vswitchIndex, IdentifierZoneVSwitchIPv6CIDR)),
+			})
+		}
+		if zone.PodsCIDR != nil {
+			vswitches = append(vswitches, alicloudv1alpha1.VSwitch{
+				Purpose: alicloudv1alpha1.PurposePods,
+				ID:      r.state.Get(ZoneIdentifier(zoneIndex, IdentifierZonePodsVSwitch)),
+				Zone:    zone.Name,
+			})
+		}
+	}
+
+	// The availability zones are only known once the vswitches have been created or described, states of earlier
+	// versions have none until the next reconciliation.
+	var zones []alicloudv1alpha1.ZoneStatus
+	for zoneIndex, zone := range r.config.Networks.Zones {
+		if zoneID := r.state.Get(ZoneIdentifier(zoneIndex, IdentifierZoneID)); zoneID != "" {
+			zones = append(zones, alicloudv1alpha1.ZoneStatus{Name: zone.Name, ZoneID: zoneID})
+		}
+	}
+
+	var natGateways []alicloudv1alpha1.NatGatewayStatus
+	if isNATGatewayPerZone(r.config) {
+		for zoneIndex, zone := range r.config.Networks.Zones {
+			natGatewayIdentifier, _ := r.natGatewayIdentifiers(zoneIndex)
+			var eips []alicloudv1alpha1.EIPStatus
+			for eipIndex := 0; eipIndex < eipCount(r.config); eipIndex++ {
+				if allocationID := r.state.Get(EIPIdentifier(zoneIndex, eipIndex, IdentifierZoneEIP)); allocationID != "" {
+					eips = append(eips, alicloudv1alpha1.EIPStatus{
+						AllocationID: allocationID,
+						IPAddress:    r.state.Get(EIPIdentifier(zoneIndex, eipIndex, IdentifierZoneEIPIPAddress)),
+					})
+				}
+			}
+			natGateways = append(natGateways, alicloudv1alpha1.NatGatewayStatus{
+				ID:        r.state.Get(natGatewayIdentifier),
+				Zone:      zone.Name,
+				VSwitchID: r.state.Get(ZoneIdentifier(zoneIndex, IdentifierZoneNATVSwitch)),
+				EIPs:      eips,
+			})
+		}
+	}
+
+	return &alicloudv1alpha1.InfrastructureStatus{
+		TypeMeta: StatusTypeMeta,
+		VPC: alicloudv1alpha1.VPCStatus{
+			ID:          r.state.Get(IdentifierVPC),
+			IPv6CIDR:    r.state.Get(IdentifierVPCIPv6CIDR),
+			VSwitches:   vswitches,
+			NatGateways: natGateways,
+			SecurityGroups: []alicloudv1alpha1.SecurityGroup{
+				{
+					Purpose: alicloudv1alpha1.PurposeNodes,
+					ID:      r.state.Get(IdentifierSecurityGroup),
+				},
+			},
+		},
+		KeyPairName: r.state.Get(IdentifierKeyPair),
+		Zones:       zones,
+	}
+}
+
+func (r *flowReconciler) describeVPC(vpcID string) (*vpc.Vpc, error) {
+	if vpcID == "" {
+		return nil, nil
+	}
+
+	req := vpc.CreateDescribeVpcsRequest()
+	req.VpcId = vpcID
+	res, err := r.vpcClient.DescribeVpcs(req)
+	if err != nil {
+		return nil, err
+	}
+	if len(res.Vpcs.Vpc) == 0 {
+		return nil, nil
+	}
+	return &res.Vpcs.Vpc[0], nil
+}
+
+func (r *flowReconciler) ensureVPC(ctx context.Context) error {
+	if !r.isVPCManaged() {
+		vpcID := *r.config.Networks.VPC.ID
+		vpcInfo, err := getExistingVPCInfo(r.vpcClient, r.config)
+		if err != nil {
+			return err
+		}
+		if isDualStackEnabled(r.config) && vpcInfo.IPv6CIDR == "" {
+			return fmt.Errorf("dual-stack is enabled but VPC %s has no IPv6 CIDR assigned", vpcID)
+		}
+
+		r.vpcCIDR = vpcInfo.CIDR
+		r.internetChargeType = eipInternetChargeType(r.config, vpcInfo.InternetChargeType)
+		r.state.Set(IdentifierVPC, vpcID)
+		r.state.Set(IdentifierVPCIPv6CIDR, vpcInfo.IPv6CIDR)
+		r.state.Set(IdentifierRouteTable, vpcInfo.RouteTableID)
+		r.state.Set(IdentifierNATGateway, vpcInfo.NATGatewayID)
+		r.state.Set(IdentifierSNATTable, strings.Split(vpcInfo.SNATTableIDs, ",")[0])
+		return r.persistState(ctx)
+	}
+
+	r.vpcCIDR = *r.config.Networks.VPC.CIDR
+	r.internetChargeType = eipInternetChargeType(r.config, alicloudclient.DefaultInternetChargeType)
+
+	if err := r.recoverVPC(ctx); err != nil {
+		return err
+	}
+	existing, err := r.describeVPC(r.state.Get(IdentifierVPC))
+	if err != nil {
+		return err
+	}
+
+	if existing == nil {
+		req := vpc.CreateCreateVpcRequest()
+		req.VpcName = r.name("vpc")
+		req.CidrBlock = r.vpcCIDR
+		if isDualStackEnabled(r.config) {
+			req.EnableIpv6 = requests.NewBoolean(true)
+		}
+		res, err := r.vpcClient.CreateVpc(req)
+		if err != nil {
+			return err
+		}
+		if err := r.setAndPersist(ctx, IdentifierVPC, res.VpcId); err != nil {
+			return err
+		}
+		return fmt.Errorf("VPC %s has been created but is not yet available", res.VpcId)
+	}
+
+	if existing.Status != statusAvailable {
+		return fmt.Errorf("VPC %s is not yet available, status is %s", existing.VpcId, existing.Status)
+	}
+
+	r.state.Set(IdentifierVPCIPv6CIDR, existing.Ipv6CidrBlock)
+	return r.setAndPersist(ctx, IdentifierRouteTable, vpcRouteTableID(*existing))
+}
+
+func (r *flowReconciler) describeRouteEntry(routeTableID, destinationCIDR string) (*vpc.RouteEntry, error) {
+	req := vpc.CreateDescribeRouteEntryListRequest()
+	req.RouteTableId = routeTableID
+	req.DestinationCidrBlock = destinationCIDR
+	res, err := r.vpcClient.DescribeRouteEntryList(req)
+	if err != nil {
+		return nil, err
+	}
+	if len(res.RouteEntrys.RouteEntry) == 0 {
+		return nil, nil
+	}
+	return &res.RouteEntrys.RouteEntry[0], nil
+}
+
+// ensureRoutes reconciles the custom routes of the InfrastructureConfig declaratively. The routes applied by the last
+// reconciliation are remembered in the state so that routes removed from the config are deleted. Route entries which
+// have not been applied by the flow reconciler, e.g. the default route of the NAT gateway, are never touched.
+func (r *flowReconciler) ensureRoutes(ctx context.Context) error {
+	appliedRoutes, err := getRoutes(r.state)
+	if err != nil {
+		return err
+	}
+	desiredRoutes := r.config.Networks.Routes
+
+	for _, route := range appliedRoutes {
+		if containsRoute(desiredRoutes, route) {
+			continue
+		}
+		if err := r.deleteRouteEntry(route); err != nil {
+			return err
+		}
+	}
+
+	for _, route := range desiredRoutes {
+		existing, err := r.describeRouteEntry(r.state.Get(IdentifierRouteTable), route.DestinationCIDR)
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			if existing.InstanceId != route.NextHopID {
+				return fmt.Errorf("route table %s already contains a route to %s via %s", existing.RouteTableId, route.DestinationCIDR, existing.InstanceId)
+			}
+			continue
+		}
+
+		req := vpc.CreateCreateRouteEntryRequest()
+		req.RouteTableId = r.state.Get(IdentifierRouteTable)
+		req.DestinationCidrBlock = route.DestinationCIDR
+		req.NextHopType = string(route.NextHopType)
+		req.NextHopId = route.NextHopID
+		if _, err := r.vpcClient.CreateRouteEntry(req); err != nil {
+			return err
+		}
+	}
+
+	if err := setRoutes(r.state, desiredRoutes); err != nil {
+		return err
+	}
+	return r.persistState(ctx)
+}
+
+func (r *flowReconciler) deleteRouteEntry(route alicloudv1alpha1.Route) error {
+	existing, err := r.describeRouteEntry(r.state.Get(IdentifierRouteTable), route.DestinationCIDR)
+	if err != nil {
+		return err
+	}
+	if existing == nil || existing.InstanceId != route.NextHopID {
+		return nil
+	}
+
+	req := vpc.CreateDeleteRouteEntryRequest()
+	req.RouteTableId = existing.RouteTableId
+	req.DestinationCidrBlock = existing.DestinationCidrBlock
+	req.NextHopId = existing.InstanceId
+	_, err = r.vpcClient.DeleteRouteEntry(req)
+	return err
+}
+
+func containsRoute(routes []alicloudv1alpha1.Route, route alicloudv1alpha1.Route) bool {
+	for _, r := range routes {
+		if r == route {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *flowReconciler) describeNATGateway(natGatewayID string) (*vpc.NatGateway, error) {
+	if natGatewayID == "" {
+		return nil, nil
+	}
+
+	req := vpc.CreateDescribeNatGatewaysRequest()
+	req.VpcId = r.state.Get(IdentifierVPC)
+	req.NatGatewayId = natGatewayID
+	res, err := r.vpcClient.DescribeNatGateways(req)
+	if err != nil {
+		return nil, err
+	}
+	if len(res.NatGateways.NatGateway) == 0 {
+		return nil, nil
+	}
+	return &res.NatGateways.NatGateway[0], nil
+}
+
+// natGatewayIdentifiers returns the whiteboard keys of the NAT gateway ID and SNAT table ID used by the zone with
+// the given index.
+func (r *flowReconciler) natGatewayIdentifiers(zoneIndex int) (string, string) {
+	if isNATGatewayPerZone(r.config) {
+		return ZoneIdentifier(zoneIndex, IdentifierZoneNATGateway), ZoneIdentifier(zoneIndex, IdentifierZoneSNATTable)
+	}
+	return IdentifierNATGateway, IdentifierSNATTable
+}
+
+// natGatewayName returns the name of the NAT gateway of the VPC or, if the NAT gateways are created per zone, of the
+// zone with the given index.
+func (r *flowReconciler) natGatewayName(zoneIndex int) string {
+	if isNATGatewayPerZone(r.config) {
+		return r.name(r.config.Networks.Zones[zoneIndex].Name + "-natgw")
+	}
+	return r.name("natgw")
+}
+
+func (r *flowReconciler) ensureNATGateway(ctx context.Context) error {
+	if !r.isVPCManaged() {
+		return nil
+	}
+
+	if !isNATGatewayPerZone(r.config) {
+		req := vpc.CreateCreateNatGatewayRequest()
+		req.Spec = "Small"
+		req.Name = r.natGatewayName(0)
+		return r.ensureNATGatewayWithIdentifiers(ctx, IdentifierNATGateway, IdentifierSNATTable, req)
+	}
+
+	for zoneIndex, zone := range r.config.Networks.Zones {
+		natGatewayIdentifier, snatTableIdentifier := r.natGatewayIdentifiers(zoneIndex)
+		req := vpc.CreateCreateNatGatewayRequest()
+		req.NatType = natTypeEnhanced
+		req.VSwitchId = r.state.Get(ZoneIdentifier(zoneIndex, IdentifierZoneVSwitch))
+		if zone.NatGatewayCIDR != nil {
+			req.VSwitchId = r.state.Get(ZoneIdentifier(zoneIndex, IdentifierZoneNATVSwitch))
+		}
+		req.Name = r.natGatewayName(zoneIndex)
+		if err := r.ensureNATGatewayWithIdentifiers(ctx, natGatewayIdentifier, snatTableIdentifier, req); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *flowReconciler) ensureNATGatewayWithIdentifiers(ctx context.Context, natGatewayIdentifier, snatTableIdentifier string, req *vpc.CreateNatGatewayRequest) error {
+	if err := r.recoverNATGateway(ctx, natGatewayIdentifier, req.Name); err != nil {
+		return err
+	}
+	existing, err := r.describeNATGateway(r.state.Get(natGatewayIdentifier))
+	if err != nil {
+		return err
+	}
+
+	if existing == nil {
+		req.VpcId = r.state.Get(IdentifierVPC)
+		res, err := r.vpcClient.CreateNatGateway(req)
+		if err != nil {
+			return err
+		}
+		if err := r.setAndPersist(ctx, natGatewayIdentifier, res.NatGatewayId); err != nil {
+			return err
+		}
+		return fmt.Errorf("NAT gateway %s has been created but is not yet available", res.NatGatewayId)
+	}
+
+	if existing.Status != statusAvailable {
+		return fmt.Errorf("NAT gateway %s is not yet available, status is %s", existing.NatGatewayId, existing.Status)
+	}
+	if len(existing.SnatTableIds.SnatTableId) == 0 {
+		return fmt.Errorf("NAT gateway %s has no SNAT table", existing.NatGatewayId)
+	}
+
+	return r.setAndPersist(ctx, snatTableIdentifier, existing.SnatTableIds.SnatTableId[0])
+}
+
+func (r *flowReconciler) describeRouteTable(routeTableID string) (*vpc.RouterTableListType, error) {
+	if routeTableID == "" {
+		return nil, nil
+	}
+
+	req := vpc.CreateDescribeRouteTableListRequest()
+	req.VpcId = r.state.Get(IdentifierVPC)
+	req.RouteTableId = routeTableID
+	res, err := r.vpcClient.DescribeRouteTableList(req)
+	if err != nil {
+		return nil, err
+	}
+	if len(res.RouterTableList.RouterTableListType) == 0 {
+		return nil, nil
+	}
+	return &res.RouterTableList.RouterTableListType[0], nil
+}
+
+// ensureZoneRouteTables ensures a route table for every zone if the NAT gateways are created per zone. The default
+// route of the route table of a zone points to the NAT gateway of the zone, and the vswitches of the zone are
+// associated with it.
+func (r *flowReconciler) ensureZoneRouteTables(ctx context.Context) error {
+	if !isNATGatewayPerZone(r.config) {
+		return nil
+	}
+
+	for zoneIndex, zone := range r.config.Networks.Zones {
+		identifier := ZoneIdentifier(zoneIndex, IdentifierZoneRouteTable)
+
+		existing, err := r.describeRouteTable(r.state.Get(identifier))
+		if err != nil {
+			return err
+		}
+
+		if existing == nil {
+			req := vpc.CreateCreateRouteTableRequest()
+			req.VpcId = r.state.Get(IdentifierVPC)
+			req.RouteTableName = r.name(zone.Name + "-rt")
+			res, err := r.vpcClient.CreateRouteTable(req)
+			if err != nil {
+				return err
+			}
+			if err := r.setAndPersist(ctx, identifier, res.RouteTableId); err != nil {
+				return err
+			}
+			return fmt.Errorf("route table %s has been created but is not yet available", res.RouteTableId)
+		}
+
+		if existing.Status != statusAvailable {
+			return fmt.Errorf("route table %s is not yet available, status is %s", existing.RouteTableId, existing.Status)
+		}
+
+		natGatewayIdentifier, _ := r.natGatewayIdentifiers(zoneIndex)
+		natGatewayID := r.state.Get(natGatewayIdentifier)
+		defaultRoute, err := r.describeRouteEntry(existing.RouteTableId, defaultRouteCIDR)
+		if err != nil {
+			return err
+		}
+		if defaultRoute == nil {
+			req := vpc.CreateCreateRouteEntryRequest()
+			req.RouteTableId = existing.RouteTableId
+			req.DestinationCidrBlock = defaultRouteCIDR
+			req.NextHopType = routeNextHopTypeNatGateway
+			req.NextHopId = natGatewayID
+			if _, err := r.vpcClient.CreateRouteEntry(req); err != nil {
+				return err
+			}
+		}
+
+		for _, identifiers := range zoneNATVSwitchIdentifiers(zoneIndex, zone) {
+			vswitch, err := r.describeVSwitch(r.state.Get(identifiers.vswitch))
+			if err != nil {
+				return err
+			}
+			if vswitch == nil {
+				return fmt.Errorf("vswitch %s of zone %s does not exist", identifiers.vswitch, zone.Name)
+			}
+			if err := r.associateRouteTable(vswitch, existing.RouteTableId); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// associateRouteTable associates the given vswitch with the route table with the given ID unless it already is.
+func (r *flowReconciler) associateRouteTable(vswitch *vpc.VSwitch, routeTableID string) error {
+	if vswitch.RouteTable.RouteTableId == routeTableID {
+		return nil
+	}
+
+	req := vpc.CreateAssociateRouteTableRequest()
+	req.RouteTableId = routeTableID
+	req.VSwitchId = vswitch.VSwitchId
+	_, err := r.vpcClient.AssociateRouteTable(req)
+	return err
+}
+
+// unassociateRouteTable removes the association of the given vswitch with the route table with the given ID if it
+// exists.
+func (r *flowReconciler) unassociateRouteTable(vswitch *vpc.VSwitch, routeTableID string) error {
+	if vswitch.RouteTable.RouteTableId != routeTableID {
+		return nil
+	}
+
+	req := vpc.CreateUnassociateRouteTableRequest()
+	req.RouteTableId = routeTableID
+	req.VSwitchId = vswitch.VSwitchId
+	_, err := r.vpcClient.UnassociateRouteTable(req)
+	return err
+}
+
+func (r *flowReconciler) describeVSwitch(vswitchID string) (*vpc.VSwitch, error) {
+	if vswitchID == "" {
+		return nil, nil
+	}
+
+	req := vpc.CreateDescribeVSwitchesRequest()
+	req.VSwitchId = vswitchID
+	res, err := r.vpcClient.DescribeVSwitches(req)
+	if err != nil {
+		return nil, err
+	}
+	if len(res.VSwitches.VSwitch) == 0 {
+		return nil, nil
+	}
+	return &res.VSwitches.VSwitch[0], nil
+}
+
+// zoneWorkerCIDRs returns the CIDRs of all vswitches of the given zone.
+func zoneWorkerCIDRs(zone alicloudv1alpha1.Zone) []string {
+	workersCIDR := zone.Workers
+	// Backwards compatibility - remove this code in a future version.
+	if workersCIDR == "" {
+		workersCIDR = zone.Worker
+	}
+	return append([]string{workersCIDR}, zone.AdditionalWorkers...)
+}
+
+// vswitchName returns the name suffix of the vswitch with the given index in the given zone.
+func vswitchName(zone alicloudv1alpha1.Zone, vswitchIndex int) string {
+	name := fmt.Sprintf("%s-vsw", zone.Name)
+	if vswitchIndex > 0 {
+		name = fmt.Sprintf("%s-%d", name, vswitchIndex)
+	}
+	return name
+}
+
+// natVSwitchIdentifiers are the name suffix of a vswitch and the whiteboard keys of the vswitch and of its SNAT entry.
+type natVSwitchIdentifiers struct {
+	name      string
+	vswitch   string
+	snatEntry string
+}
+
+// zoneNATVSwitchIdentifiers returns the whiteboard keys of all vswitches of the given zone which reach the internet
+// through the NAT gateway, i.e., the vswitches of the workers and the dedicated vswitch of the pods.
+func zoneNATVSwitchIdentifiers(zoneIndex int, zone alicloudv1alpha1.Zone) []natVSwitchIdentifiers {
+	var identifiers []natVSwitchIdentifiers
+	for vswitchIndex := range zoneWorkerCIDRs(zone) {
+		identifiers = append(identifiers, natVSwitchIdentifiers{
+			name:      vswitchName(zone, vswitchIndex),
+			vswitch:   VSwitchIdentifier(zoneIndex, vswitchIndex, IdentifierZoneVSwitch),
+			snatEntry: VSwitchIdentifier(zoneIndex, vswitchIndex, IdentifierZoneSNATEntry),
+		})
+	}
+	if zone.PodsCIDR != nil {
+		identifiers = append(identifiers, natVSwitchIdentifiers{
+			name:      podsVSwitchName(zone),
+			vswitch:   ZoneIdentifier(zoneIndex, IdentifierZonePodsVSwitch),
+			snatEntry: ZoneIdentifier(zoneIndex, IdentifierZonePodsSNATEntry),
+		})
+	}
+	return identifiers
+}
+
+// podsVSwitchName returns the name suffix of the dedicated vswitch of the pods in the given zone.
+func podsVSwitchName(zone alicloudv1alpha1.Zone) string {
+	return zone.Name + "-pods-vsw"
+}
+
+func (r *flowReconciler) ensureVSwitches(ctx context.Context) error {
+	for zoneIndex, zone := range r.config.Networks.Zones {
+		for vswitchIndex, workersCIDR := range zoneWorkerCIDRs(zone) {
+			identifier := VSwitchIdentifier(zoneIndex, vswitchIndex, IdentifierZoneVSwitch)
+
+			existing, err := r.describeVSwitch(r.state.Get(identifier))
+			if err != nil {
+				return err
+			}
+
+			if existing == nil {
+				req := vpc.CreateCreateVSwitchRequest()
+				req.VpcId = r.state.Get(IdentifierVPC)
+				req.VSwitchName = r.name(vswitchName(zone, vswitchIndex))
+				req.ZoneId = zone.Name
+				req.CidrBlock = workersCIDR
+				if isDualStackEnabled(r.config) {
+					req.Ipv6CidrBlock = requests.NewInteger(vswitchIPv6CIDRMask(zoneIndex, vswitchIndex))
+				}
+				res, err := r.vpcClient.CreateVSwitch(req)
+				if err != nil {
+					return err
+				}
+				if err := r.setAndPersist(ctx, identifier, res.VSwitchId); err != nil {
+					return err
+				}
+				return fmt.Errorf("vswitch %s has been created but is not yet available", res.VSwitchId)
+			}
+
+			if existing.Status != statusAvailable {
+				return fmt.Errorf("vswitch %s is not yet available, status is %s", existing.VSwitchId, existing.Status)
+			}
+
+			if routeTableID := r.config.Networks.RouteTableID; routeTableID != nil {
+				if err := r.associateRouteTable(existing, *routeTableID); err != nil {
+					return err
+				}
+			}
+
+			r.state.Set(VSwitchIdentifier(zoneIndex, vswitchIndex, IdentifierZoneVSwitchIPv6CIDR), existing.Ipv6CidrBlock)
+			if vswitchIndex == 0 {
+				r.state.Set(ZoneIdentifier(zoneIndex, IdentifierZoneID), existing.ZoneId)
+			}
+		}
+	}
+
+	return r.persistState(ctx)
+}
+
+// ensureNATVSwitches ensures the dedicated vswitches of the NAT gateways of the zones which declare a NAT gateway CIDR.
+func (r *flowReconciler) ensureNATVSwitches(ctx context.Context) error {
+	if !isNATGatewayPerZone(r.config) {
+		return nil
+	}
+
+	for zoneIndex, zone := range r.config.Networks.Zones {
+		if zone.NatGatewayCIDR == nil {
+			continue
+		}
+		identifier := ZoneIdentifier(zoneIndex, IdentifierZoneNATVSwitch)
+
+		existing, err := r.describeVSwitch(r.state.Get(identifier))
+		if err != nil {
+			return err
+		}
+
+		if existing == nil {
+			req := vpc.CreateCreateVSwitchRequest()
+			req.VpcId = r.state.Get(IdentifierVPC)
+			req.VSwitchName = r.name(zone.Name + "-natgw-vsw")
+			req.ZoneId = zone.Name
+			req.CidrBlock = *zone.NatGatewayCIDR
+			res, err := r.vpcClient.CreateVSwitch(req)
+			if err != nil {
+				return err
+			}
+			if err := r.setAndPersist(ctx, identifier, res.VSwitchId); err != nil {
+				return err
+			}
+			return fmt.Errorf("vswitch %s has been created but is not yet available", res.VSwitchId)
+		}
+
+		if existing.Status != statusAvailable {
+			return fmt.Errorf("vswitch %s is not yet available, status is %s", existing.VSwitchId, existing.Status)
+		}
+	}
+	return nil
+}
+
+// ensurePodsVSwitches ensures the dedicated vswitches of the pods of the zones which declare a pods CIDR.
+func (r *flowReconciler) ensurePodsVSwitches(ctx context.Context) error {
+	for zoneIndex, zone := range r.config.Networks.Zones {
+		if zone.PodsCIDR == nil {
+			continue
+		}
+		identifier := ZoneIdentifier(zoneIndex, IdentifierZonePodsVSwitch)
+
+		existing, err := r.describeVSwitch(r.state.Get(identifier))
+		if err != nil {
+			return err
+		}
+
+		if existing == nil {
+			req := vpc.CreateCreateVSwitchRequest()
+			req.VpcId = r.state.Get(IdentifierVPC)
+			req.VSwitchName = r.name(podsVSwitchName(zone))
+			req.ZoneId = zone.Name
+			req.CidrBlock = *zone.PodsCIDR
+			res, err := r.vpcClient.CreateVSwitch(req)
+			if err != nil {
+				return err
+			}
+			if err := r.setAndPersist(ctx, identifier, res.VSwitchId); err != nil {
+				return err
+			}
+			return fmt.Errorf("vswitch %s has been created but is not yet available", res.VSwitchId)
+		}
+
+		if existing.Status != statusAvailable {
+			return fmt.Errorf("vswitch %s is not yet available, status is %s", existing.VSwitchId, existing.Status)
+		}
+
+		if routeTableID := r.config.Networks.RouteTableID; routeTableID != nil {
+			if err := r.associateRouteTable(existing, *routeTableID); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (r *flowReconciler) describeEIP(allocationID string) (*vpc.EipAddress, error) {
+	if allocationID == "" {
+		return nil, nil
+	}
+
+	req := vpc.CreateDescribeEipAddressesRequest()
+	req.AllocationId = allocationID
+	res, err := r.vpcClient.DescribeEipAddresses(req)
+	if err != nil {
+		return nil, err
+	}
+	if len(res.EipAddresses.EipAddress) == 0 {
+		return nil, nil
+	}
+	return &res.EipAddresses.EipAddress[0], nil
+}
+
+func (r *flowReconciler) describeSNATEntry(snatTableID, snatEntryID string) (*vpc.SnatTableEntry, error) {
+	if snatEntryID == "" {
+		return nil, nil
+	}
+
+	req := vpc.CreateDescribeSnatTableEntriesRequest()
+	req.SnatTableId = snatTableID
+	req.SnatEntryId = snatEntryID
+	res, err := r.vpcClient.DescribeSnatTableEntries(req)
+	if err != nil {
+		return nil, err
+	}
+	if len(res.SnatTableEntries.SnatTableEntry) == 0 {
+		return nil, nil
+	}
+	return &res.SnatTableEntries.SnatTableEntry[0], nil
+}
+
+func (r *flowReconciler) ensureEIPsAndSNATEntries(ctx context.Context) error {
+	for zoneIndex, zone := range r.config.Networks.Zones {
+		natGatewayIdentifier, snatTableIdentifier := r.natGatewayIdentifiers(zoneIndex)
+		natGatewayID, snatTableID := r.state.Get(natGatewayIdentifier), r.state.Get(snatTableIdentifier)
+
+		var eips []*vpc.EipAddress
+		if allocationID := eipAllocationID(r.config); allocationID != "" {
+			eip, err := r.describeUserEIP(allocationID, natGatewayID)
+			if err != nil {
+				return err
+			}
+			eips = append(eips, eip)
+		} else {
+			for eipIndex := 0; eipIndex < eipCount(r.config); eipIndex++ {
+				eip, err := r.ensureZoneEIP(ctx, zoneIndex, eipIndex)
+				if err != nil {
+					return err
+				}
+				eips = append(eips, eip)
+			}
+		}
+
+		var ipAddresses []string
+		for _, eip := range eips {
+			if err := r.associateEIP(eip, natGatewayID); err != nil {
+				return err
+			}
+			ipAddresses = append(ipAddresses, eip.IpAddress)
+		}
+
+		for _, identifiers := range zoneNATVSwitchIdentifiers(zoneIndex, zone) {
+			snatEntryIdentifier := identifiers.snatEntry
+
+			snatEntry, err := r.describeSNATEntry(snatTableID, r.state.Get(snatEntryIdentifier))
+			if err != nil {
+				return err
+			}
+
+			if snatEntry == nil {
+				req := vpc.CreateCreateSnatEntryRequest()
+				req.SnatTableId = snatTableID
+				req.SourceVSwitchId = r.state.Get(identifiers.vswitch)
+				req.SnatIp = strings.Join(ipAddresses, ",")
+				res, err := r.vpcClient.CreateSnatEntry(req)
+				if err != nil {
+					return err
+				}
+				if err := r.setAndPersist(ctx, snatEntryIdentifier, res.SnatEntryId); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// ensureZoneEIP returns the EIP with the given index allocated for the zone with the given index. If it does not exist
+// yet then a new one is allocated, and an error is returned so that the reconciliation is retried once it is available.
+func (r *flowReconciler) ensureZoneEIP(ctx context.Context, zoneIndex, eipIndex int) (*vpc.EipAddress, error) {
+	eipIdentifier := EIPIdentifier(zoneIndex, eipIndex, IdentifierZoneEIP)
+
+	eip, err := r.describeEIP(r.state.Get(eipIdentifier))
+	if err != nil {
+		return nil, err
+	}
+	if eip != nil {
+		r.state.Set(EIPIdentifier(zoneIndex, eipIndex, IdentifierZoneEIPIPAddress), eip.IpAddress)
+		return eip, nil
+	}
+
+	req := vpc.CreateAllocateEipAddressRequest()
+	req.Bandwidth = strconv.Itoa(int(eipBandwidth(r.config)))
+	req.InstanceChargeType = "PostPaid"
+	req.InternetChargeType = r.internetChargeType
+	res, err := r.vpcClient.AllocateEipAddress(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.setAndPersist(ctx, eipIdentifier, res.AllocationId); err != nil {
+		return nil, err
+	}
+	return nil, fmt.Errorf("EIP %s has been allocated but is not yet associated", res.AllocationId)
+}
+
+// associateEIP associates the given EIP with the given NAT gateway. It returns an error until the EIP is in use.
+func (r *flowReconciler) associateEIP(eip *vpc.EipAddress, natGatewayID string) error {
+	switch eip.Status {
+	case statusAvailable:
+		req := vpc.CreateAssociateEipAddressRequest()
+		req.AllocationId = eip.AllocationId
+		req.InstanceId = natGatewayID
+		req.InstanceType = eipInstanceTypeNat
+		if _, err := r.vpcClient.AssociateEipAddress(req); err != nil {
+			return err
+		}
+		return fmt.Errorf("EIP %s is being associated with NAT gateway %s", eip.AllocationId, natGatewayID)
+	case statusInUse:
+		return nil
+	default:
+		return fmt.Errorf("EIP %s is not yet associated, status is %s", eip.AllocationId, eip.Status)
+	}
+}
+
+// describeUserEIP returns the user-owned EIP with the given allocation ID. It must exist and must either be available
+// or already be associated with the given NAT gateway. The EIP is never stored in the state, hence it is neither tagged
+// nor released.
+func (r *flowReconciler) describeUserEIP(allocationID, natGatewayID string) (*vpc.EipAddress, error) {
+	eip, err := r.describeEIP(allocationID)
+	if err != nil {
+		return nil, err
+	}
+	if eip == nil {
+		return nil, fmt.Errorf("EIP %s does not exist", allocationID)
+	}
+	if eip.Status == statusInUse && eip.InstanceId != natGatewayID {
+		return nil, fmt.Errorf("EIP %s is already bound to %s %s", allocationID, eip.InstanceType, eip.InstanceId)
+	}
+	return eip, nil
+}
+
+func (r *flowReconciler) ensureSecurityGroup(ctx context.Context) error {
+	if !r.isSecurityGroupManaged() {
+		return r.setAndPersist(ctx, IdentifierSecurityGroup, *r.config.Networks.SecurityGroupID)
+	}
+
+	securityGroupID := r.state.Get(IdentifierSecurityGroup)
+
+	exists := false
+	if securityGroupID != "" {
+		var err error
+		if exists, err = r.ecsClient.CheckIfSecurityGroupExists(ctx, securityGroupID); err != nil {
+			return err
+		}
+	}
+
+	if !exists {
+		var err error
+		if securityGroupID, err = r.ecsClient.CreateSecurityGroup(ctx, r.state.Get(IdentifierVPC), r.name("sg")); err != nil {
+			return err
+		}
+		// The rules of a previous security group are gone together with it.
+		r.state.Set(IdentifierSecurityGroupRules, "")
+		if err := r.setAndPersist(ctx, IdentifierSecurityGroup, securityGroupID); err != nil {
+			return err
+		}
+	}
+
+	for _, rule := range r.baselineSecurityGroupRules() {
+		if err := r.authorizeSecurityGroupRule(ctx, securityGroupID, rule); err != nil {
+			return err
+		}
+	}
+
+	return r.reconcileSecurityGroupRules(ctx, securityGroupID)
+}
+
+// baselineSecurityGroupRules returns the rules which are always present in the security group of the nodes.
+func (r *flowReconciler) baselineSecurityGroupRules() []alicloudv1alpha1.SecurityGroupRule {
+	return baselineSecurityGroupRules(r.vpcCIDR)
+}
+
+// baselineSecurityGroupRules returns the rules the security group of the nodes needs for the traffic within the cluster
+// and to the node ports.
+func baselineSecurityGroupRules(vpcCIDR string) []alicloudv1alpha1.SecurityGroupRule {
+	return []alicloudv1alpha1.SecurityGroupRule{
+		{Direction: alicloudv1alpha1.SecurityGroupRuleDirectionIngress, Protocol: "tcp", PortRange: "30000/32767", CIDR: "0.0.0.0/0"},
+		{Direction: alicloudv1alpha1.SecurityGroupRuleDirectionIngress, Protocol: "tcp", PortRange: "1/65535", CIDR: vpcCIDR},
+		{Direction: alicloudv1alpha1.SecurityGroupRuleDirectionIngress, Protocol: "udp", PortRange: "1/65535", CIDR: vpcCIDR},
+	}
+}
+
+// reconcileSecurityGroupRules reconciles the security group rules of the InfrastructureConfig declaratively. The rules
+// applied by the last reconciliation are remembered in the state so that rules removed from the config are revoked.
+// Rules which equal a baseline rule are never revoked.
+func (r *flowReconciler) reconcileSecurityGroupRules(ctx context.Context, securityGroupID string) error {
+	appliedRules, err := getSecurityGroupRules(r.state)
+	if err != nil {
+		return err
+	}
+
+	desiredRules := r.config.Networks.SecurityGroupRules
+	keptRules := append(r.baselineSecurityGroupRules(), desiredRules...)
+
+	for _, rule := range appliedRules {
+		if containsSecurityGroupRule(keptRules, rule) {
+			continue
+		}
+		if err := r.revokeSecurityGroupRule(ctx, securityGroupID, rule); err != nil {
+			return err
+		}
+	}
+
+	for _, rule := range desiredRules {
+		if err := r.authorizeSecurityGroupRule(ctx, securityGroupID, rule); err != nil {
+			return err
+		}
+	}
+
+	if err := setSecurityGroupRules(r.state, desiredRules); err != nil {
+		return err
+	}
+	return r.persistState(ctx)
+}
+
+func (r *flowReconciler) authorizeSecurityGroupRule(ctx context.Context, securityGroupID string, rule alicloudv1alpha1.SecurityGroupRule) error {
+	if rule.Direction == alicloudv1alpha1.SecurityGroupRuleDirectionEgress {
+		return r.ecsClient.AuthorizeSecurityGroupEgress(ctx, securityGroupID, rule.Protocol, rule.PortRange, rule.CIDR)
+	}
+	return r.ecsClient.AuthorizeSecurityGroupIngress(ctx, securityGroupID, rule.Protocol, rule.PortRange, rule.CIDR)
+}
+
+func (r *flowReconciler) revokeSecurityGroupRule(ctx context.Context, securityGroupID string, rule alicloudv1alpha1.SecurityGroupRule) error {
+	if rule.Direction == alicloudv1alpha1.SecurityGroupRuleDirectionEgress {
+		return r.ecsClient.RevokeSecurityGroupEgress(ctx, securityGroupID, rule.Protocol, rule.PortRange, rule.CIDR)
+	}
+	return r.ecsClient.RevokeSecurityGroupIngress(ctx, securityGroupID, rule.Protocol, rule.PortRange, rule.CIDR)
+}
+
+func containsSecurityGroupRule(rules []alicloudv1alpha1.SecurityGroupRule, rule alicloudv1alpha1.SecurityGroupRule) bool {
+	for _, r := range rules {
+		if r == rule {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *flowReconciler) ensureKeyPair(ctx context.Context) error {
+	keyPairName := r.name("ssh-publickey")
+
+	exists, err := r.ecsClient.CheckIfKeyPairExists(ctx, keyPairName)
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		if err := r.ecsClient.ImportKeyPair(ctx, keyPairName, string(r.infra.Spec.SSHPublicKey)); err != nil {
+			return err
+		}
+	}
+
+	return r.setAndPersist(ctx, IdentifierKeyPair, keyPairName)
+}
+
+// Delete deletes all infrastructure resources managed by the flow reconciler. Resources of the same kind are deleted in
+// parallel, the dependencies of the tasks ensure that a resource is only deleted after the resources which depend on
+// it, e.g., the VPC after all vswitches.
+func (r *flowReconciler) Delete(ctx context.Context, cleanupServiceLoadBalancers flow.TaskFn) error {
+	var (
+		g = flow.NewGraph("Alicloud infrastructure destruction")
+
+		destroyServiceLoadBalancers = g.Add(flow.Task{
+			Name: "Destroying service load balancers",
+			Fn:   cleanupServiceLoadBalancers.RetryUntilTimeout(flowRetryInterval, flowRetryTimeout),
+		})
+		deleteSNATEntries = g.Add(flow.Task{
+			Name:         "Deleting SNAT entries",
+			Fn:           flow.TaskFn(r.deleteSNATEntries).RetryUntilTimeout(flowRetryInterval, flowRetryTimeout),
+			Dependencies: flow.NewTaskIDs(destroyServiceLoadBalancers),
+		})
+		deleteDNATEntries = g.Add(flow.Task{
+			Name:         "Deleting DNAT entries",
+			Fn:           flow.TaskFn(r.deleteDNATEntries).RetryUntilTimeout(flowRetryInterval, flowRetryTimeout),
+			Dependencies: flow.NewTaskIDs(destroyServiceLoadBalancers),
+		})
+		deleteEIPs = g.Add(flow.Task{
+			Name:         "Deleting EIPs",
+			Fn:           flow.TaskFn(r.deleteEIPs).RetryUntilTimeout(flowRetryInterval, flowRetryTimeout),
+			Dependencies: flow.NewTaskIDs(deleteSNATEntries, deleteDNATEntries),
+		})
+		deleteZoneRouteTables = g.Add(flow.Task{
+			Name:         "Deleting zone route tables",
+			Fn:           flow.TaskFn(r.deleteZoneRouteTables).RetryUntilTimeout(flowRetryInterval, flowRetryTimeout),
+			Dependencies: flow.NewTaskIDs(destroyServiceLoadBalancers),
+		})
+		deleteNATGateway = g.Add(flow.Task{
+			Name:         "Deleting NAT gateway",
+			Fn:           flow.TaskFn(r.deleteNATGateway).RetryUntilTimeout(flowRetryInterval, flowRetryTimeout),
+			Dependencies: flow.NewTaskIDs(deleteEIPs, deleteZoneRouteTables),
+		})
+		// NAT gateways per zone must be deleted before the vswitches they are placed in.
+		deleteVSwitches = g.Add(flow.Task{
+			Name:         "Deleting vswitches",
+			Fn:           flow.TaskFn(r.deleteVSwitches).RetryUntilTimeout(flowRetryInterval, flowRetryTimeout),
+			Dependencies: flow.NewTaskIDs(deleteSNATEntries, deleteZoneRouteTables).InsertIf(isNATGatewayPerZone(r.config), deleteNATGateway),
+		})
+		deleteNATVSwitches = g.Add(flow.Task{
+			Name:         "Deleting NAT gateway vswitches",
+			Fn:           flow.TaskFn(r.deleteNATVSwitches).RetryUntilTimeout(flowRetryInterval, flowRetryTimeout),
+			Dependencies: flow.NewTaskIDs(deleteNATGateway, deleteVSwitches),
+		})
+		deletePodsVSwitches = g.Add(flow.Task{
+			Name:         "Deleting pods vswitches",
+			Fn:           flow.TaskFn(r.deletePodsVSwitches).RetryUntilTimeout(flowRetryInterval, flowRetryTimeout),
+			Dependencies: flow.NewTaskIDs(deleteSNATEntries, deleteZoneRouteTables),
+		})
+		deleteSecurityGroup = g.Add(flow.Task{
+			Name:         "Deleting security group",
+			Fn:           flow.TaskFn(r.deleteSecurityGroup).RetryUntilTimeout(flowRetryInterval, flowRetryTimeout),
+			Dependencies: flow.NewTaskIDs(destroyServiceLoadBalancers),
+		})
+		_ = g.Add(flow.Task{
+			Name: "Deleting key pair",
+			Fn:   flow.TaskFn(r.deleteKeyPair).RetryUntilTimeout(flowRetryInterval, flowRetryTimeout),
+		})
+		deleteRoutes = g.Add(flow.Task{
+			Name: "Deleting routes",
+			Fn:   flow.TaskFn(r.deleteRoutes).RetryUntilTimeout(flowRetryInterval, flowRetryTimeout),
+		})
+		deleteFlowLog = g.Add(flow.Task{
+			Name: "Deleting flow log",
+			Fn:   flow.TaskFn(r.deleteFlowLog).RetryUntilTimeout(flowRetryInterval, flowRetryTimeout),
+		})
+		deleteDHCPOptions = g.Add(flow.Task{
+			Name: "Deleting DHCP options",
+			Fn:   flow.TaskFn(r.deleteDHCPOptions).RetryUntilTimeout(flowRetryInterval, flowRetryTimeout),
+		})
+		_ = g.Add(flow.Task{
+			Name:         "Deleting VPC",
+			Fn:           flow.TaskFn(r.deleteVPC).RetryUntilTimeout(flowRetryInterval, flowRetryTimeout),
+			Dependencies: flow.NewTaskIDs(deleteVSwitches, deleteNATGateway, deleteNATVSwitches, deletePodsVSwitches, deleteSecurityGroup, deleteRoutes, deleteFlowLog, deleteDHCPOptions),
+		})
+
+		f = g.Compile()
+	)
+
+	if err := f.Run(flow.Opts{Context: ctx}); err != nil {
+		return flow.Causes(err)
+	}
+	return nil
+}
+
+func (r *flowReconciler) deleteSNATEntries(ctx context.Context) error {
+	var snatEntryIdentifiers, snatTableIdentifiers []string
+	for zoneIndex, zone := range r.config.Networks.Zones {
+		_, snatTableIdentifier := r.natGatewayIdentifiers(zoneIndex)
+		for _, identifiers := range zoneNATVSwitchIdentifiers(zoneIndex, zone) {
+			snatEntryIdentifiers = append(snatEntryIdentifiers, identifiers.snatEntry)
+			snatTableIdentifiers = append(snatTableIdentifiers, snatTableIdentifier)
+		}
+	}
+
+	return deleteInParallel(ctx, len(snatEntryIdentifiers), func(i int) error {
+		return r.deleteSNATEntry(ctx, snatEntryIdentifiers[i], snatTableIdentifiers[i])
+	})
+}
+
+func (r *flowReconciler) deleteSNATEntry(ctx context.Context, identifier, snatTableIdentifier string) error {
+	snatEntry, err := r.describeSNATEntry(r.state.Get(snatTableIdentifier), r.state.Get(identifier))
+	if err != nil {
+		return err
+	}
+
+	if snatEntry != nil {
+		req := vpc.CreateDeleteSnatEntryRequest()
+		req.SnatTableId = snatEntry.SnatTableId
+		req.SnatEntryId = snatEntry.SnatEntryId
+		if _, err := r.vpcClient.DeleteSnatEntry(req); ignoreNotFoundError(err) != nil {
+			return err
+		}
+	}
+
+	return r.setAndPersist(ctx, identifier, "")
+}
+
+func (r *flowReconciler) deleteEIPs(ctx context.Context) error {
+	if err := r.unassociateUserEIP(); err != nil {
+		return err
+	}
+
+	type eipIndices struct {
+		zoneIndex, eipIndex int
+	}
+
+	var eips []eipIndices
+	for zoneIndex := range r.config.Networks.Zones {
+		for eipIndex := 0; eipIndex < eipCount(r.config); eipIndex++ {
+			eips = append(eips, eipIndices{zoneIndex, eipIndex})
+		}
+	}
+
+	return deleteInParallel(ctx, len(eips), func(i int) error {
+		zoneIndex, eipIndex := eips[i].zoneIndex, eips[i].eipIndex
+		identifier := EIPIdentifier(zoneIndex, eipIndex, IdentifierZoneEIP)
+
+		eip, err := r.describeEIP(r.state.Get(identifier))
+		if err != nil {
+			return err
+		}
+
+		if eip != nil {
+			switch eip.Status {
+			case statusInUse:
+				req := vpc.CreateUnassociateEipAddressRequest()
+				req.AllocationId = eip.AllocationId
+				req.InstanceId = eip.InstanceId
+				req.InstanceType = eipInstanceTypeNat
+				if _, err := r.vpcClient.UnassociateEipAddress(req); err != nil {
+					return err
+				}
+				return fmt.Errorf("EIP %s is being unassociated", eip.AllocationId)
+			case statusAvailable:
+				req := vpc.CreateReleaseEipAddressRequest()
+				req.AllocationId = eip.AllocationId
+				if _, err := r.vpcClient.ReleaseEipAddress(req); ignoreNotFoundError(err) != nil {
+					return err
+				}
+			default:
+				return fmt.Errorf("EIP %s cannot be released yet, status is %s", eip.AllocationId, eip.Status)
+			}
+		}
+
+		r.state.Set(EIPIdentifier(zoneIndex, eipIndex, IdentifierZoneEIPIPAddress), "")
+		return r.setAndPersist(ctx, identifier, "")
+	})
+}
+
+// unassociateUserEIP unassociates the user-owned EIP from the NAT gateway. The EIP itself is not released.
+func (r *flowReconciler) unassociateUserEIP() error {
+	allocationID := eipAllocationID(r.config)
+	if allocationID == "" {
+		return nil
+	}
+
+	eip, err := r.describeEIP(allocationID)
+	if err != nil {
+		return err
+	}
+
+	natGatewayID := r.state.Get(IdentifierNATGateway)
+	if eip == nil || natGatewayID == "" || eip.InstanceId != natGatewayID {
+		return nil
+	}
+
+	switch eip.Status {
+	case statusInUse:
+		req := vpc.CreateUnassociateEipAddressRequest()
+		req.AllocationId = eip.AllocationId
+		req.InstanceId = eip.InstanceId
+		req.InstanceType = eipInstanceTypeNat
+		if _, err := r.vpcClient.UnassociateEipAddress(req); err != nil {
+			return err
+		}
+		return fmt.Errorf("EIP %s is being unassociated", eip.AllocationId)
+	case statusAvailable:
+		return nil
+	default:
+		return fmt.Errorf("EIP %s is not yet unassociated, status is %s", eip.AllocationId, eip.Status)
+	}
+}
+
+func (r *flowReconciler) deleteVSwitches(ctx context.Context) error {
+	type vswitchIndices struct {
+		zoneIndex, vswitchIndex int
+	}
+
+	var vswitches []vswitchIndices
+	for zoneIndex, zone := range r.config.Networks.Zones {
+		for vswitchIndex := range zoneWorkerCIDRs(zone) {
+			vswitches = append(vswitches, vswitchIndices{zoneIndex, vswitchIndex})
+		}
+	}
+
+	return deleteInParallel(ctx, len(vswitches), func(i int) error {
+		zoneIndex, vswitchIndex := vswitches[i].zoneIndex, vswitches[i].vswitchIndex
+		identifier := VSwitchIdentifier(zoneIndex, vswitchIndex, IdentifierZoneVSwitch)
+
+		vswitch, err := r.describeVSwitch(r.state.Get(identifier))
+		if err != nil {
+			return err
+		}
+
+		if vswitch != nil {
+			// The route table is shared with other resources of the VPC, hence only the association is removed.
+			if routeTableID := r.config.Networks.RouteTableID; routeTableID != nil {
+				if err := r.unassociateRouteTable(vswitch, *routeTableID); err != nil {
+					return err
+				}
+			}
+
+			req := vpc.CreateDeleteVSwitchRequest()
+			req.VSwitchId = vswitch.VSwitchId
+			if _, err := r.vpcClient.DeleteVSwitch(req); ignoreNotFoundError(err) != nil {
+				return err
+			}
+		}
+
+		r.state.Set(VSwitchIdentifier(zoneIndex, vswitchIndex, IdentifierZoneVSwitchIPv6CIDR), "")
+		if vswitchIndex == 0 {
+			r.state.Set(ZoneIdentifier(zoneIndex, IdentifierZoneID), "")
+		}
+		return r.setAndPersist(ctx, identifier, "")
+	})
+}
+
+// deleteNATVSwitches deletes the dedicated vswitches of the NAT gateways. They are deleted last since the NAT gateways
+// cannot be deleted before.
+func (r *flowReconciler) deleteNATVSwitches(ctx context.Context) error {
+	return deleteInParallel(ctx, len(r.config.Networks.Zones), func(zoneIndex int) error {
+		identifier := ZoneIdentifier(zoneIndex, IdentifierZoneNATVSwitch)
+		if r.state.Get(identifier) == "" {
+			return nil
+		}
+
+		vswitch, err := r.describeVSwitch(r.state.Get(identifier))
+		if err != nil {
+			return err
+		}
+
+		if vswitch != nil {
+			req := vpc.CreateDeleteVSwitchRequest()
+			req.VSwitchId = vswitch.VSwitchId
+			if _, err := r.vpcClient.DeleteVSwitch(req); ignoreNotFoundError(err) != nil {
+				return err
+			}
+		}
+
+		return r.setAndPersist(ctx, identifier, "")
+	})
+}
+
+// deletePodsVSwitches deletes the dedicated vswitches of the pods.
+func (r *flowReconciler) deletePodsVSwitches(ctx context.Context) error {
+	return deleteInParallel(ctx, len(r.config.Networks.Zones), func(zoneIndex int) error {
+		identifier := ZoneIdentifier(zoneIndex, IdentifierZonePodsVSwitch)
+		if r.state.Get(identifier) == "" {
+			return nil
+		}
+
+		vswitch, err := r.describeVSwitch(r.state.Get(identifier))
+		if err != nil {
+			return err
+		}
+
+		if vswitch != nil {
+			// The route table is shared with other resources of the VPC, hence only the association is removed.
+			if routeTableID := r.config.Networks.RouteTableID; routeTableID != nil {
+				if err := r.unassociateRouteTable(vswitch, *routeTableID); err != nil {
+					return err
+				}
+			}
+
+			req := vpc.CreateDeleteVSwitchRequest()
+			req.VSwitchId = vswitch.VSwitchId
+			if _, err := r.vpcClient.DeleteVSwitch(req); ignoreNotFoundError(err) != nil {
+				return err
+			}
+		}
+
+		return r.setAndPersist(ctx, identifier, "")
+	})
+}
+
+func (r *flowReconciler) deleteNATGateway(ctx context.Context) error {
+	if !r.isVPCManaged() {
+		return nil
+	}
+
+	if !isNATGatewayPerZone(r.config) {
+		return r.deleteNATGatewayWithIdentifiers(ctx, IdentifierNATGateway, IdentifierSNATTable, r.natGatewayName(0))
+	}
+
+	return deleteInParallel(ctx, len(r.config.Networks.Zones), func(zoneIndex int) error {
+		natGatewayIdentifier, snatTableIdentifier := r.natGatewayIdentifiers(zoneIndex)
+		return r.deleteNATGatewayWithIdentifiers(ctx, natGatewayIdentifier, snatTableIdentifier, r.natGatewayName(zoneIndex))
+	})
+}
+
+func (r *flowReconciler) deleteNATGatewayWithIdentifiers(ctx context.Context, natGatewayIdentifier, snatTableIdentifier, name string) error {
+	if err := r.recoverNATGateway(ctx, natGatewayIdentifier, name); err != nil {
+		return err
+	}
+	natGateway, err := r.describeNATGateway(r.state.Get(natGatewayIdentifier))
+	if err != nil {
+		return err
+	}
+
+	if natGateway != nil {
+		req := vpc.CreateDeleteNatGatewayRequest()
+		req.NatGatewayId = natGateway.NatGatewayId
+		req.Force = requests.NewBoolean(true)
+		if _, err := r.vpcClient.DeleteNatGateway(req); ignoreNotFoundError(err) != nil {
+			return err
+		}
+	}
+
+	r.state.Set(snatTableIdentifier, "")
+	return r.setAndPersist(ctx, natGatewayIdentifier, "")
+}
+
+// deleteZoneRouteTables deletes the route tables of the zones which are created if the NAT gateways are created per
+// zone. The vswitches are unassociated and the default route is deleted before, since neither the route table nor
+// the NAT gateway can be deleted otherwise.
+func (r *flowReconciler) deleteZoneRouteTables(ctx context.Context) error {
+	return deleteInParallel(ctx, len(r.config.Networks.Zones), func(zoneIndex int) error {
+		zone := r.config.Networks.Zones[zoneIndex]
+		identifier := ZoneIdentifier(zoneIndex, IdentifierZoneRouteTable)
+		if r.state.Get(identifier) == "" {
+			return nil
+		}
+
+		routeTable, err := r.describeRouteTable(r.state.Get(identifier))
+		if err != nil {
+			return err
+		}
+
+		if routeTable != nil {
+			for _, identifiers := range zoneNATVSwitchIdentifiers(zoneIndex, zone) {
+				vswitch, err := r.describeVSwitch(r.state.Get(identifiers.vswitch))
+				if err != nil {
+					return err
+				}
+				if vswitch != nil {
+					if err := r.unassociateRouteTable(vswitch, routeTable.RouteTableId); err != nil {
+						return err
+					}
+				}
+			}
+
+			defaultRoute, err := r.describeRouteEntry(routeTable.RouteTableId, defaultRouteCIDR)
+			if err != nil {
+				return err
+			}
+			if defaultRoute != nil {
+				req := vpc.CreateDeleteRouteEntryRequest()
+				req.RouteTableId = routeTable.RouteTableId
+				req.DestinationCidrBlock = defaultRouteCIDR
+				req.NextHopId = defaultRoute.InstanceId
+				if _, err := r.vpcClient.DeleteRouteEntry(req); err != nil {
+					return err
+				}
+			}
+
+			req := vpc.CreateDeleteRouteTableRequest()
+			req.RouteTableId = routeTable.RouteTableId
+			if _, err := r.vpcClient.DeleteRouteTable(req); ignoreNotFoundError(err) != nil {
+				return err
+			}
+		}
+
+		return r.setAndPersist(ctx, identifier, "")
+	})
+}
+
+func (r *flowReconciler) deleteRoutes(ctx context.Context) error {
+	routes, err := getRoutes(r.state)
+	if err != nil {
+		return err
+	}
+
+	if r.state.Get(IdentifierRouteTable) != "" {
+		for _, route := range routes {
+			if err := r.deleteRouteEntry(route); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := setRoutes(r.state, nil); err != nil {
+		return err
+	}
+	return r.persistState(ctx)
+}
+
+func (r *flowReconciler) deleteSecurityGroup(ctx context.Context) error {
+	securityGroupID := r.state.Get(IdentifierSecurityGroup)
+	if securityGroupID == "" || !r.isSecurityGroupManaged() {
+		return nil
+	}
+
+	exists, err := r.ecsClient.CheckIfSecurityGroupExists(ctx, securityGroupID)
+	if err != nil {
+		return err
+	}
+	if exists {
+		if err := r.ecsClient.DeleteSecurityGroup(ctx, securityGroupID); err != nil {
+			return err
+		}
+	}
+
+	r.state.Set(IdentifierSecurityGroupRules, "")
+	return r.setAndPersist(ctx, IdentifierSecurityGroup, "")
+}
+
+func (r *flowReconciler) deleteKeyPair(ctx context.Context) error {
+	keyPairName := r.state.Get(IdentifierKeyPair)
+	if keyPairName == "" {
+		return nil
+	}
+
+	exists, err := r.ecsClient.CheckIfKeyPairExists(ctx, keyPairName)
+	if err != nil {
+		return err
+	}
+	if exists {
+		if err := r.ecsClient.DeleteKeyPair(ctx, keyPairName); err != nil {
+			return err
+		}
+	}
+
+	return r.setAndPersist(ctx, IdentifierKeyPair, "")
+}
+
+func (r *flowReconciler) deleteVPC(ctx context.Context) error {
+	if !r.isVPCManaged() {
+		return nil
+	}
+
+	if err := r.recoverVPC(ctx); err != nil {
+		return err
+	}
+	existing, err := r.describeVPC(r.state.Get(IdentifierVPC))
+	if err != nil {
+		return err
+	}
+
+	if existing != nil {
+		req := vpc.CreateDeleteVpcRequest()
+		req.VpcId = existing.VpcId
+		if _, err := r.vpcClient.DeleteVpc(req); ignoreNotFoundError(err) != nil {
+			return err
+		}
+	}
+
+	r.state.Set(IdentifierVPCIPv6CIDR, "")
+	r.state.Set(IdentifierRouteTable, "")
+	return r.setAndPersist(ctx, IdentifierVPC, "")
+}
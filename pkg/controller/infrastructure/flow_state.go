@@ -0,0 +1,436 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package infrastructure
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	alicloudv1alpha1 "github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/alicloud/v1alpha1"
+	"github.com/gardener/gardener-extensions/pkg/terraformer"
+
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	// FlowStateKind is the kind of the state persisted by the flow reconciler.
+	FlowStateKind = "FlowState"
+
+	// IdentifierVPC is the whiteboard key of the VPC ID.
+	IdentifierVPC = "vpc"
+	// IdentifierVPCIPv6CIDR is the whiteboard key of the IPv6 CIDR of the VPC.
+	IdentifierVPCIPv6CIDR = "vpc/ipv6CIDR"
+	// IdentifierNATGateway is the whiteboard key of the NAT gateway ID.
+	IdentifierNATGateway = "natGateway"
+	// IdentifierSNATTable is the whiteboard key of the SNAT table ID of the NAT gateway.
+	IdentifierSNATTable = "natGateway/snatTable"
+	// IdentifierRouteTable is the whiteboard key of the route table ID of the VPC.
+	IdentifierRouteTable = "vpc/routeTable"
+	// IdentifierRoutes is the whiteboard key of the custom routes applied to the route table of the VPC.
+	IdentifierRoutes = "vpc/routes"
+	// IdentifierFlowLog is the whiteboard key of the ID of the flow log of the VPC.
+	IdentifierFlowLog = "vpc/flowLog"
+	// IdentifierDHCPOptionsSet is the whiteboard key of the ID of the DHCP options set of the VPC.
+	IdentifierDHCPOptionsSet = "vpc/dhcpOptionsSet"
+	// IdentifierSecurityGroup is the whiteboard key of the security group ID.
+	IdentifierSecurityGroup = "securityGroup"
+	// IdentifierSecurityGroupRules is the whiteboard key of the custom security group rules applied to the security group.
+	IdentifierSecurityGroupRules = "securityGroup/rules"
+	// IdentifierKeyPair is the whiteboard key of the key pair name.
+	IdentifierKeyPair = "keyPair"
+
+	// IdentifierZoneVSwitch is the suffix of the whiteboard key of a zone's vswitch ID.
+	IdentifierZoneVSwitch = "vswitch"
+	// IdentifierZoneVSwitchIPv6CIDR is the suffix of the whiteboard key of a zone's vswitch IPv6 CIDR.
+	IdentifierZoneVSwitchIPv6CIDR = "vswitch/ipv6CIDR"
+	// IdentifierZoneID is the suffix of the whiteboard key of the availability zone which Alicloud reports for a zone's
+	// vswitch.
+	IdentifierZoneID = "zoneID"
+	// IdentifierZoneEIP is the suffix of the whiteboard key of a zone's EIP allocation ID.
+	IdentifierZoneEIP = "eip"
+	// IdentifierZoneEIPIPAddress is the suffix of the whiteboard key of the IP address of a zone's EIP.
+	IdentifierZoneEIPIPAddress = "eip/ipAddress"
+	// IdentifierZoneSNATEntry is the suffix of the whiteboard key of a zone's SNAT entry ID.
+	IdentifierZoneSNATEntry = "snatEntry"
+	// IdentifierZoneNATGateway is the suffix of the whiteboard key of a zone's NAT gateway ID if the NAT gateways are
+	// created per zone.
+	IdentifierZoneNATGateway = "natGateway"
+	// IdentifierZoneSNATTable is the suffix of the whiteboard key of the SNAT table ID of a zone's NAT gateway.
+	IdentifierZoneSNATTable = "natGateway/snatTable"
+	// IdentifierZoneNATVSwitch is the suffix of the whiteboard key of the ID of the dedicated vswitch of a zone's NAT
+	// gateway.
+	IdentifierZoneNATVSwitch = "natGateway/vswitch"
+	// IdentifierZonePodsVSwitch is the suffix of the whiteboard key of the ID of the dedicated vswitch of a zone's pods.
+	IdentifierZonePodsVSwitch = "pods/vswitch"
+	// IdentifierZonePodsSNATEntry is the suffix of the whiteboard key of the SNAT entry ID of a zone's pods vswitch.
+	IdentifierZonePodsSNATEntry = "pods/snatEntry"
+	// IdentifierZoneRouteTable is the suffix of the whiteboard key of a zone's route table ID if the NAT gateways are
+	// created per zone.
+	IdentifierZoneRouteTable = "routeTable"
+)
+
+// ZoneIdentifier returns the whiteboard key of the given identifier for the zone with the given index.
+func ZoneIdentifier(zoneIndex int, identifier string) string {
+	return fmt.Sprintf("zones/%d/%s", zoneIndex, identifier)
+}
+
+// VSwitchIdentifier returns the whiteboard key of the given identifier for the vswitch with the given index in the
+// zone with the given index. The keys of the first vswitch of a zone are the keys of the zone.
+func VSwitchIdentifier(zoneIndex, vswitchIndex int, identifier string) string {
+	if vswitchIndex == 0 {
+		return ZoneIdentifier(zoneIndex, identifier)
+	}
+	return fmt.Sprintf("zones/%d/%d/%s", zoneIndex, vswitchIndex, identifier)
+}
+
+// EIPIdentifier returns the whiteboard key of the given identifier for the EIP with the given index of the zone with the
+// given index. The keys of the first EIP of a zone are the keys of the zone.
+func EIPIdentifier(zoneIndex, eipIndex int, identifier string) string {
+	if eipIndex == 0 {
+		return ZoneIdentifier(zoneIndex, identifier)
+	}
+	return fmt.Sprintf("zones/%d/eips/%d/%s", zoneIndex, eipIndex, identifier)
+}
+
+// FlowState is the state persisted by the flow reconciler in the `.status.state` of the Infrastructure.
+// The whiteboard maps identifiers to the IDs of the cloud resources managed by the flow reconciler.
+type FlowState struct {
+	Kind       string            `json:"kind"`
+	Whiteboard map[string]string `json:"whiteboard"`
+
+	lock sync.RWMutex
+}
+
+// NewFlowState returns an empty FlowState.
+func NewFlowState() *FlowState {
+	return &FlowState{
+		Kind:       FlowStateKind,
+		Whiteboard: map[string]string{},
+	}
+}
+
+// Get returns the value of the given identifier or the empty string if it is not set.
+func (s *FlowState) Get(identifier string) string {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.Whiteboard[identifier]
+}
+
+// Set sets the value of the given identifier. An empty value removes the identifier.
+func (s *FlowState) Set(identifier, value string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if value == "" {
+		delete(s.Whiteboard, identifier)
+		return
+	}
+	s.Whiteboard[identifier] = value
+}
+
+// Marshal returns the JSON representation of the state.
+func (s *FlowState) Marshal() ([]byte, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return json.Marshal(s)
+}
+
+// IsFlowState checks whether the given Infrastructure state has been written by the flow reconciler.
+func IsFlowState(state *runtime.RawExtension) bool {
+	if state == nil || len(state.Raw) == 0 {
+		return false
+	}
+
+	meta := struct {
+		Kind string `json:"kind"`
+	}{}
+	if err := json.Unmarshal(state.Raw, &meta); err != nil {
+		return false
+	}
+	return meta.Kind == FlowStateKind
+}
+
+// FlowStateFromInfrastructureState returns the FlowState for the given Infrastructure state. If the state
+// has been written by Terraform, the IDs of the managed resources are imported from the Terraform state.
+func FlowStateFromInfrastructureState(state *runtime.RawExtension) (*FlowState, error) {
+	if IsFlowState(state) {
+		flowState := NewFlowState()
+		if err := json.Unmarshal(state.Raw, flowState); err != nil {
+			return nil, err
+		}
+		if flowState.Whiteboard == nil {
+			flowState.Whiteboard = map[string]string{}
+		}
+		return flowState, nil
+	}
+
+	rawState, err := terraformer.UnmarshalRawState(state)
+	if err != nil {
+		return nil, err
+	}
+
+	return importTerraformState(rawState.Data)
+}
+
+// terraformStateV4 is the relevant subset of the Terraform state in version 4 (Terraform >= 0.12).
+type terraformStateV4 struct {
+	Resources []struct {
+		Mode      string `json:"mode"`
+		Type      string `json:"type"`
+		Name      string `json:"name"`
+		Instances []struct {
+			Attributes map[string]interface{} `json:"attributes"`
+		} `json:"instances"`
+	} `json:"resources"`
+}
+
+// terraformStateV3 is the relevant subset of the Terraform state in version 3 (Terraform < 0.12).
+type terraformStateV3 struct {
+	Modules []struct {
+		Resources map[string]struct {
+			Primary struct {
+				ID         string            `json:"id"`
+				Attributes map[string]string `json:"attributes"`
+			} `json:"primary"`
+		} `json:"resources"`
+	} `json:"modules"`
+}
+
+// terraformResources returns the attributes of the managed resources in the given Terraform state
+// keyed by `<type>.<name>`.
+func terraformResources(data string) (map[string]map[string]string, error) {
+	version := struct {
+		Version int `json:"version"`
+	}{}
+	if err := json.Unmarshal([]byte(data), &version); err != nil {
+		return nil, err
+	}
+
+	resources := map[string]map[string]string{}
+
+	if version.Version < 4 {
+		state := terraformStateV3{}
+		if err := json.Unmarshal([]byte(data), &state); err != nil {
+			return nil, err
+		}
+		for _, module := range state.Modules {
+			for key, resource := range module.Resources {
+				attributes := map[string]string{"id": resource.Primary.ID}
+				for k, v := range resource.Primary.Attributes {
+					attributes[k] = v
+				}
+				resources[key] = attributes
+			}
+		}
+		return resources, nil
+	}
+
+	state := terraformStateV4{}
+	if err := json.Unmarshal([]byte(data), &state); err != nil {
+		return nil, err
+	}
+	for _, resource := range state.Resources {
+		if resource.Mode != "managed" || len(resource.Instances) == 0 {
+			continue
+		}
+		attributes := map[string]string{}
+		for k, v := range resource.Instances[0].Attributes {
+			if s, ok := v.(string); ok {
+				attributes[k] = s
+			}
+		}
+		resources[resource.Type+"."+resource.Name] = attributes
+	}
+	return resources, nil
+}
+
+// importTerraformState creates a FlowState from the resources managed by the Terraform infrastructure chart.
+func importTerraformState(data string) (*FlowState, error) {
+	flowState := NewFlowState()
+	if data == "" {
+		return flowState, nil
+	}
+
+	resources, err := terraformResources(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if vpc, ok := resources["alicloud_vpc.vpc"]; ok {
+		flowState.Set(IdentifierVPC, vpc["id"])
+		flowState.Set(IdentifierVPCIPv6CIDR, vpc["ipv6_cidr_block"])
+		flowState.Set(IdentifierRouteTable, vpc["route_table_id"])
+	}
+	var routes []alicloudv1alpha1.Route
+	for routeIndex := 0; ; routeIndex++ {
+		route, ok := resources[fmt.Sprintf("alicloud_route_entry.custom_%d", routeIndex)]
+		if !ok {
+			break
+		}
+		if routeTableID := route["route_table_id"]; routeTableID != "" {
+			flowState.Set(IdentifierRouteTable, routeTableID)
+		}
+		routes = append(routes, alicloudv1alpha1.Route{
+			DestinationCIDR: route["destination_cidrblock"],
+			NextHopType:     alicloudv1alpha1.RouteNextHopType(route["nexthop_type"]),
+			NextHopID:       route["nexthop_id"],
+		})
+	}
+	if err := setRoutes(flowState, routes); err != nil {
+		return nil, err
+	}
+	if natGateway, ok := resources["alicloud_nat_gateway.nat_gateway"]; ok {
+		flowState.Set(IdentifierNATGateway, natGateway["id"])
+		flowState.Set(IdentifierSNATTable, strings.Split(natGateway["snat_table_ids"], ",")[0])
+	}
+	if securityGroup, ok := resources["alicloud_security_group.sg"]; ok {
+		flowState.Set(IdentifierSecurityGroup, securityGroup["id"])
+	}
+	var securityGroupRules []alicloudv1alpha1.SecurityGroupRule
+	for ruleIndex := 0; ; ruleIndex++ {
+		rule, ok := resources[fmt.Sprintf("alicloud_security_group_rule.custom_%d", ruleIndex)]
+		if !ok {
+			break
+		}
+		securityGroupRules = append(securityGroupRules, alicloudv1alpha1.SecurityGroupRule{
+			Direction: alicloudv1alpha1.SecurityGroupRuleDirection(rule["type"]),
+			Protocol:  rule["ip_protocol"],
+			PortRange: rule["port_range"],
+			CIDR:      rule["cidr_ip"],
+		})
+	}
+	if err := setSecurityGroupRules(flowState, securityGroupRules); err != nil {
+		return nil, err
+	}
+	if flowLog, ok := resources["alicloud_vpc_flow_log.flow_log"]; ok {
+		flowState.Set(IdentifierFlowLog, flowLog["id"])
+	}
+	if dhcpOptionsSet, ok := resources["alicloud_vpc_dhcp_options_set.dhcp_options"]; ok {
+		flowState.Set(IdentifierDHCPOptionsSet, dhcpOptionsSet["id"])
+	}
+	if keyPair, ok := resources["alicloud_key_pair.publickey"]; ok {
+		flowState.Set(IdentifierKeyPair, keyPair["id"])
+	}
+
+	for zoneIndex := 0; ; zoneIndex++ {
+		vswitch, ok := resources[fmt.Sprintf("alicloud_vswitch.vsw_z%d", zoneIndex)]
+		if !ok {
+			break
+		}
+		flowState.Set(ZoneIdentifier(zoneIndex, IdentifierZoneVSwitch), vswitch["id"])
+		flowState.Set(ZoneIdentifier(zoneIndex, IdentifierZoneVSwitchIPv6CIDR), vswitch["ipv6_cidr_block"])
+		flowState.Set(ZoneIdentifier(zoneIndex, IdentifierZoneID), vswitch["availability_zone"])
+
+		for eipIndex := 0; ; eipIndex++ {
+			name := fmt.Sprintf("alicloud_eip.eip_natgw_z%d", zoneIndex)
+			if eipIndex > 0 {
+				name = fmt.Sprintf("%s_%d", name, eipIndex)
+			}
+			eip, ok := resources[name]
+			if !ok {
+				break
+			}
+			flowState.Set(EIPIdentifier(zoneIndex, eipIndex, IdentifierZoneEIP), eip["id"])
+			flowState.Set(EIPIdentifier(zoneIndex, eipIndex, IdentifierZoneEIPIPAddress), eip["ip_address"])
+		}
+		importTerraformSNATEntry(flowState, resources, fmt.Sprintf("alicloud_snat_entry.snat_z%d", zoneIndex), ZoneIdentifier(zoneIndex, IdentifierZoneSNATEntry))
+		if natGateway, ok := resources[fmt.Sprintf("alicloud_nat_gateway.nat_gateway_z%d", zoneIndex)]; ok {
+			flowState.Set(ZoneIdentifier(zoneIndex, IdentifierZoneNATGateway), natGateway["id"])
+			flowState.Set(ZoneIdentifier(zoneIndex, IdentifierZoneSNATTable), strings.Split(natGateway["snat_table_ids"], ",")[0])
+		}
+		if vswitch, ok := resources[fmt.Sprintf("alicloud_vswitch.vsw_natgw_z%d", zoneIndex)]; ok {
+			flowState.Set(ZoneIdentifier(zoneIndex, IdentifierZoneNATVSwitch), vswitch["id"])
+		}
+		if vswitch, ok := resources[fmt.Sprintf("alicloud_vswitch.vsw_pods_z%d", zoneIndex)]; ok {
+			flowState.Set(ZoneIdentifier(zoneIndex, IdentifierZonePodsVSwitch), vswitch["id"])
+		}
+		importTerraformSNATEntry(flowState, resources, fmt.Sprintf("alicloud_snat_entry.snat_pods_z%d", zoneIndex), ZoneIdentifier(zoneIndex, IdentifierZonePodsSNATEntry))
+		if routeTable, ok := resources[fmt.Sprintf("alicloud_route_table.rt_z%d", zoneIndex)]; ok {
+			flowState.Set(ZoneIdentifier(zoneIndex, IdentifierZoneRouteTable), routeTable["id"])
+		}
+
+		for vswitchIndex := 1; ; vswitchIndex++ {
+			suffix := VSwitchOutputKeySuffix(zoneIndex, vswitchIndex)
+			vswitch, ok := resources["alicloud_vswitch.vsw_z"+suffix]
+			if !ok {
+				break
+			}
+			flowState.Set(VSwitchIdentifier(zoneIndex, vswitchIndex, IdentifierZoneVSwitch), vswitch["id"])
+			flowState.Set(VSwitchIdentifier(zoneIndex, vswitchIndex, IdentifierZoneVSwitchIPv6CIDR), vswitch["ipv6_cidr_block"])
+			importTerraformSNATEntry(flowState, resources, "alicloud_snat_entry.snat_z"+suffix, VSwitchIdentifier(zoneIndex, vswitchIndex, IdentifierZoneSNATEntry))
+		}
+	}
+
+	return flowState, nil
+}
+
+func importTerraformSNATEntry(flowState *FlowState, resources map[string]map[string]string, resourceKey, identifier string) {
+	if snatEntry, ok := resources[resourceKey]; ok {
+		// The ID of a SNAT entry in the Terraform state has the format `<snat-table-id>:<snat-entry-id>`.
+		id := snatEntry["id"]
+		flowState.Set(identifier, id[strings.LastIndex(id, ":")+1:])
+	}
+}
+
+// getSecurityGroupRules returns the custom security group rules stored in the given state.
+func getSecurityGroupRules(flowState *FlowState) ([]alicloudv1alpha1.SecurityGroupRule, error) {
+	var rules []alicloudv1alpha1.SecurityGroupRule
+	if value := flowState.Get(IdentifierSecurityGroupRules); value != "" {
+		if err := json.Unmarshal([]byte(value), &rules); err != nil {
+			return nil, err
+		}
+	}
+	return rules, nil
+}
+
+// setSecurityGroupRules stores the given custom security group rules in the given state.
+func setSecurityGroupRules(flowState *FlowState, rules []alicloudv1alpha1.SecurityGroupRule) error {
+	if len(rules) == 0 {
+		flowState.Set(IdentifierSecurityGroupRules, "")
+		return nil
+	}
+	value, err := json.Marshal(rules)
+	if err != nil {
+		return err
+	}
+	flowState.Set(IdentifierSecurityGroupRules, string(value))
+	return nil
+}
+
+// getRoutes returns the custom routes stored in the given state.
+func getRoutes(flowState *FlowState) ([]alicloudv1alpha1.Route, error) {
+	var routes []alicloudv1alpha1.Route
+	if value := flowState.Get(IdentifierRoutes); value != "" {
+		if err := json.Unmarshal([]byte(value), &routes); err != nil {
+			return nil, err
+		}
+	}
+	return routes, nil
+}
+
+// setRoutes stores the given custom routes in the given state.
+func setRoutes(flowState *FlowState, routes []alicloudv1alpha1.Route) error {
+	if len(routes) == 0 {
+		flowState.Set(IdentifierRoutes, "")
+		return nil
+	}
+	value, err := json.Marshal(routes)
+	if err != nil {
+		return err
+	}
+	flowState.Set(IdentifierRoutes, string(value))
+	return nil
+}
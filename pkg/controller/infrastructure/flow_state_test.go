@@ -0,0 +1,126 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package infrastructure_test
+
+import (
+	. "github.com/gardener/gardener-extension-provider-alicloud/pkg/controller/infrastructure"
+	realterraformer "github.com/gardener/gardener-extensions/pkg/terraformer"
+
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+var _ = Describe("FlowState", func() {
+	const terraformState = `{
+  "version": 4,
+  "resources": [
+    {"mode": "managed", "type": "alicloud_vpc", "name": "vpc", "instances": [{"attributes": {"id": "vpc-1", "ipv6_cidr_block": "", "route_table_id": "vtb-1"}}]},
+    {"mode": "managed", "type": "alicloud_route_entry", "name": "custom_0", "instances": [{"attributes": {"route_table_id": "vtb-1", "destination_cidrblock": "172.16.0.0/16", "nexthop_type": "Instance", "nexthop_id": "i-1"}}]},
+    {"mode": "managed", "type": "alicloud_nat_gateway", "name": "nat_gateway", "instances": [{"attributes": {"id": "ngw-1", "snat_table_ids": "stb-1"}}]},
+    {"mode": "managed", "type": "alicloud_vswitch", "name": "vsw_z0", "instances": [{"attributes": {"id": "vsw-1"}}]},
+    {"mode": "managed", "type": "alicloud_eip", "name": "eip_natgw_z0", "instances": [{"attributes": {"id": "eip-1"}}]},
+    {"mode": "managed", "type": "alicloud_eip", "name": "eip_natgw_z0_1", "instances": [{"attributes": {"id": "eip-2", "ip_address": "47.0.0.2"}}]},
+    {"mode": "managed", "type": "alicloud_snat_entry", "name": "snat_z0", "instances": [{"attributes": {"id": "stb-1:snat-1"}}]},
+    {"mode": "managed", "type": "alicloud_vswitch", "name": "vsw_z0_1", "instances": [{"attributes": {"id": "vsw-2"}}]},
+    {"mode": "managed", "type": "alicloud_snat_entry", "name": "snat_z0_1", "instances": [{"attributes": {"id": "stb-1:snat-2"}}]},
+    {"mode": "managed", "type": "alicloud_security_group", "name": "sg", "instances": [{"attributes": {"id": "sg-1"}}]},
+    {"mode": "managed", "type": "alicloud_security_group_rule", "name": "custom_0", "instances": [{"attributes": {"type": "ingress", "ip_protocol": "tcp", "port_range": "22/22", "cidr_ip": "10.0.0.0/8"}}]},
+    {"mode": "managed", "type": "alicloud_key_pair", "name": "publickey", "instances": [{"attributes": {"id": "shoot--foo--bar-ssh-publickey"}}]}
+  ]
+}`
+
+	Describe("#FlowStateFromInfrastructureState", func() {
+		It("should return an empty state if there is no state yet", func() {
+			state, err := FlowStateFromInfrastructureState(nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(state.Whiteboard).To(BeEmpty())
+		})
+
+		It("should import the resources from the Terraform state", func() {
+			rawState := &realterraformer.RawState{
+				Data:     terraformState,
+				Encoding: realterraformer.NoneEncoding,
+			}
+			raw, err := rawState.Marshal()
+			Expect(err).NotTo(HaveOccurred())
+
+			state, err := FlowStateFromInfrastructureState(&runtime.RawExtension{Raw: raw})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(state.Whiteboard).To(Equal(map[string]string{
+				IdentifierVPC:                                    "vpc-1",
+				IdentifierRouteTable:                             "vtb-1",
+				IdentifierRoutes:                                 `[{"destinationCIDR":"172.16.0.0/16","nextHopType":"Instance","nextHopID":"i-1"}]`,
+				IdentifierNATGateway:                             "ngw-1",
+				IdentifierSNATTable:                              "stb-1",
+				IdentifierSecurityGroup:                          "sg-1",
+				IdentifierSecurityGroupRules:                     `[{"direction":"ingress","protocol":"tcp","portRange":"22/22","cidr":"10.0.0.0/8"}]`,
+				IdentifierKeyPair:                                "shoot--foo--bar-ssh-publickey",
+				ZoneIdentifier(0, IdentifierZoneVSwitch):         "vsw-1",
+				ZoneIdentifier(0, IdentifierZoneEIP):             "eip-1",
+				EIPIdentifier(0, 1, IdentifierZoneEIP):           "eip-2",
+				EIPIdentifier(0, 1, IdentifierZoneEIPIPAddress):  "47.0.0.2",
+				ZoneIdentifier(0, IdentifierZoneSNATEntry):       "snat-1",
+				VSwitchIdentifier(0, 1, IdentifierZoneVSwitch):   "vsw-2",
+				VSwitchIdentifier(0, 1, IdentifierZoneSNATEntry): "snat-2",
+			}))
+		})
+
+		It("should read a persisted flow state", func() {
+			state := NewFlowState()
+			state.Set(IdentifierVPC, "vpc-1")
+			raw, err := state.Marshal()
+			Expect(err).NotTo(HaveOccurred())
+
+			infraState := &runtime.RawExtension{Raw: raw}
+			Expect(IsFlowState(infraState)).To(BeTrue())
+
+			readState, err := FlowStateFromInfrastructureState(infraState)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(readState.Get(IdentifierVPC)).To(Equal("vpc-1"))
+		})
+	})
+
+	Describe("#ShouldUseFlow", func() {
+		It("should not use the flow by default", func() {
+			Expect(ShouldUseFlow(&extensionsv1alpha1.Infrastructure{})).To(BeFalse())
+		})
+
+		It("should use the flow if the annotation is set", func() {
+			infra := &extensionsv1alpha1.Infrastructure{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{AnnotationKeyUseFlow: "true"},
+				},
+			}
+			Expect(ShouldUseFlow(infra)).To(BeTrue())
+		})
+
+		It("should keep using the flow once the state has been migrated", func() {
+			raw, err := NewFlowState().Marshal()
+			Expect(err).NotTo(HaveOccurred())
+
+			infra := &extensionsv1alpha1.Infrastructure{
+				Status: extensionsv1alpha1.InfrastructureStatus{
+					DefaultStatus: extensionsv1alpha1.DefaultStatus{
+						State: &runtime.RawExtension{Raw: raw},
+					},
+				},
+			}
+			Expect(ShouldUseFlow(infra)).To(BeTrue())
+		})
+	})
+})
@@ -0,0 +1,1189 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	alicloudv1alpha1 "github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/alicloud/v1alpha1"
+	mockalicloudclient "github.com/gardener/gardener-extension-provider-alicloud/pkg/mock/provider-alicloud/alicloud/client"
+
+	"github.com/aliyun/alibaba-cloud-sdk-go/sdk/errors"
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/vpc"
+	mockclient "github.com/gardener/gardener-extensions/pkg/mock/controller-runtime/client"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+)
+
+var _ = Describe("Flow", func() {
+	var (
+		ctrl      *gomock.Controller
+		c         *mockclient.MockClient
+		vpcClient *mockalicloudclient.MockVPC
+
+		ctx = context.TODO()
+
+		config     *alicloudv1alpha1.InfrastructureConfig
+		reconciler *flowReconciler
+
+		oldRoute = alicloudv1alpha1.Route{DestinationCIDR: "172.16.0.0/16", NextHopType: alicloudv1alpha1.RouteNextHopTypeInstance, NextHopID: "i-old"}
+		newRoute = alicloudv1alpha1.Route{DestinationCIDR: "172.17.0.0/16", NextHopType: alicloudv1alpha1.RouteNextHopTypeInstance, NextHopID: "i-new"}
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		c = mockclient.NewMockClient(ctrl)
+		vpcClient = mockalicloudclient.NewMockVPC(ctrl)
+
+		c.EXPECT().Status().Return(c).AnyTimes()
+		c.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).AnyTimes()
+		c.EXPECT().Update(ctx, gomock.Any()).AnyTimes()
+
+		infra := &extensionsv1alpha1.Infrastructure{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "shoot--foo--bar", Name: "infra"},
+		}
+		config = &alicloudv1alpha1.InfrastructureConfig{
+			Networks: alicloudv1alpha1.Networks{
+				VPC:    alicloudv1alpha1.VPC{CIDR: pointer.StringPtr("10.250.0.0/16")},
+				Routes: []alicloudv1alpha1.Route{newRoute},
+			},
+		}
+
+		var err error
+		reconciler, err = newFlowReconciler(c, infra, config, vpcClient, nil, nil)
+		Expect(err).NotTo(HaveOccurred())
+		reconciler.state.Set(IdentifierRouteTable, "vtb-1")
+		Expect(setRoutes(reconciler.state, []alicloudv1alpha1.Route{oldRoute})).To(Succeed())
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	describeRouteEntries := func(destinationCIDR string, entries ...vpc.RouteEntry) {
+		vpcClient.EXPECT().DescribeRouteEntryList(gomock.Any()).DoAndReturn(func(req *vpc.DescribeRouteEntryListRequest) (*vpc.DescribeRouteEntryListResponse, error) {
+			Expect(req.RouteTableId).To(Equal("vtb-1"))
+			Expect(req.DestinationCidrBlock).To(Equal(destinationCIDR))
+			return &vpc.DescribeRouteEntryListResponse{RouteEntrys: vpc.RouteEntrysInDescribeRouteEntryList{RouteEntry: entries}}, nil
+		})
+	}
+
+	Describe("#ensureRoutes", func() {
+		It("should delete the routes removed from the config and create the new ones", func() {
+			describeRouteEntries(oldRoute.DestinationCIDR, vpc.RouteEntry{RouteTableId: "vtb-1", DestinationCidrBlock: oldRoute.DestinationCIDR, InstanceId: oldRoute.NextHopID})
+			vpcClient.EXPECT().DeleteRouteEntry(gomock.Any()).DoAndReturn(func(req *vpc.DeleteRouteEntryRequest) (*vpc.DeleteRouteEntryResponse, error) {
+				Expect(req.RouteTableId).To(Equal("vtb-1"))
+				Expect(req.DestinationCidrBlock).To(Equal(oldRoute.DestinationCIDR))
+				Expect(req.NextHopId).To(Equal(oldRoute.NextHopID))
+				return &vpc.DeleteRouteEntryResponse{}, nil
+			})
+			describeRouteEntries(newRoute.DestinationCIDR)
+			vpcClient.EXPECT().CreateRouteEntry(gomock.Any()).DoAndReturn(func(req *vpc.CreateRouteEntryRequest) (*vpc.CreateRouteEntryResponse, error) {
+				Expect(req.RouteTableId).To(Equal("vtb-1"))
+				Expect(req.DestinationCidrBlock).To(Equal(newRoute.DestinationCIDR))
+				Expect(req.NextHopType).To(Equal("Instance"))
+				Expect(req.NextHopId).To(Equal(newRoute.NextHopID))
+				return &vpc.CreateRouteEntryResponse{}, nil
+			})
+
+			Expect(reconciler.ensureRoutes(ctx)).To(Succeed())
+			Expect(getRoutes(reconciler.state)).To(Equal([]alicloudv1alpha1.Route{newRoute}))
+		})
+
+		It("should not touch routes which have not been applied by the reconciler", func() {
+			Expect(setRoutes(reconciler.state, nil)).To(Succeed())
+			config.Networks.Routes = nil
+
+			Expect(reconciler.ensureRoutes(ctx)).To(Succeed())
+			Expect(reconciler.state.Get(IdentifierRoutes)).To(BeEmpty())
+		})
+
+		It("should fail if the destination is already routed to another next hop", func() {
+			Expect(setRoutes(reconciler.state, nil)).To(Succeed())
+			describeRouteEntries(newRoute.DestinationCIDR, vpc.RouteEntry{RouteTableId: "vtb-1", DestinationCidrBlock: newRoute.DestinationCIDR, InstanceId: "i-other"})
+
+			Expect(reconciler.ensureRoutes(ctx)).To(MatchError(ContainSubstring("already contains a route to 172.17.0.0/16 via i-other")))
+		})
+	})
+
+	Describe("shared route table", func() {
+		const sharedRouteTableID = "vtb-shared"
+
+		BeforeEach(func() {
+			config.Networks.VPC = alicloudv1alpha1.VPC{ID: pointer.StringPtr("vpc-1")}
+			config.Networks.Zones = []alicloudv1alpha1.Zone{{Name: "cn-beijing-f", Workers: "10.250.0.0/19"}}
+			config.Networks.RouteTableID = pointer.StringPtr(sharedRouteTableID)
+			reconciler.state.Set(ZoneIdentifier(0, IdentifierZoneVSwitch), "vsw-1")
+		})
+
+		describeVSwitch := func(routeTableID string) {
+			vpcClient.EXPECT().DescribeVSwitches(gomock.Any()).Return(&vpc.DescribeVSwitchesResponse{
+				VSwitches: vpc.VSwitches{VSwitch: []vpc.VSwitch{{
+					VSwitchId:  "vsw-1",
+					Status:     statusAvailable,
+					RouteTable: vpc.RouteTable{RouteTableId: routeTableID},
+				}}},
+			}, nil)
+		}
+
+		It("should associate the vswitches with the shared route table", func() {
+			describeVSwitch("vtb-system")
+			vpcClient.EXPECT().AssociateRouteTable(gomock.Any()).DoAndReturn(func(req *vpc.AssociateRouteTableRequest) (*vpc.AssociateRouteTableResponse, error) {
+				Expect(req.RouteTableId).To(Equal(sharedRouteTableID))
+				Expect(req.VSwitchId).To(Equal("vsw-1"))
+				return &vpc.AssociateRouteTableResponse{}, nil
+			})
+
+			Expect(reconciler.ensureVSwitches(ctx)).To(Succeed())
+		})
+
+		It("should not associate vswitches which are already associated with the shared route table", func() {
+			describeVSwitch(sharedRouteTableID)
+
+			Expect(reconciler.ensureVSwitches(ctx)).To(Succeed())
+		})
+
+		It("should only unassociate the vswitches from the shared route table on deletion", func() {
+			describeVSwitch(sharedRouteTableID)
+			gomock.InOrder(
+				vpcClient.EXPECT().UnassociateRouteTable(gomock.Any()).DoAndReturn(func(req *vpc.UnassociateRouteTableRequest) (*vpc.UnassociateRouteTableResponse, error) {
+					Expect(req.RouteTableId).To(Equal(sharedRouteTableID))
+					Expect(req.VSwitchId).To(Equal("vsw-1"))
+					return &vpc.UnassociateRouteTableResponse{}, nil
+				}),
+				vpcClient.EXPECT().DeleteVSwitch(gomock.Any()).Return(&vpc.DeleteVSwitchResponse{}, nil),
+			)
+
+			Expect(reconciler.deleteVSwitches(ctx)).To(Succeed())
+		})
+	})
+
+	Describe("per-zone NAT gateways", func() {
+		BeforeEach(func() {
+			config.Networks.Routes = nil
+			config.Networks.NatGateway = &alicloudv1alpha1.NatGateway{PerZone: true}
+			config.Networks.Zones = []alicloudv1alpha1.Zone{
+				{Name: "cn-beijing-f", Workers: "10.250.0.0/19"},
+				{Name: "cn-beijing-g", Workers: "10.250.32.0/19"},
+			}
+			reconciler.state.Set(IdentifierVPC, "vpc-1")
+			reconciler.state.Set(ZoneIdentifier(0, IdentifierZoneVSwitch), "vsw-f")
+			reconciler.state.Set(ZoneIdentifier(1, IdentifierZoneVSwitch), "vsw-g")
+		})
+
+		It("should be rejected by the Terraform reconciler", func() {
+			a := &actuator{}
+
+			err := a.reconcileWithTerraform(ctx, &extensionsv1alpha1.Infrastructure{}, nil, config, nil)
+			Expect(err).To(MatchError("NAT gateways per zone are only supported by the flow reconciler, which is enabled with the annotation alicloud.provider.extensions.gardener.cloud/use-flow=true"))
+		})
+
+		It("should create an enhanced NAT gateway in the first vswitch of every zone", func() {
+			reconciler.state.Set(ZoneIdentifier(0, IdentifierZoneNATGateway), "ngw-f")
+			vpcClient.EXPECT().DescribeNatGateways(gomock.Any()).DoAndReturn(func(req *vpc.DescribeNatGatewaysRequest) (*vpc.DescribeNatGatewaysResponse, error) {
+				Expect(req.NatGatewayId).To(Equal("ngw-f"))
+				return &vpc.DescribeNatGatewaysResponse{NatGateways: vpc.NatGateways{NatGateway: []vpc.NatGateway{{
+					NatGatewayId: "ngw-f",
+					Status:       statusAvailable,
+					SnatTableIds: vpc.SnatTableIdsInDescribeNatGateways{SnatTableId: []string{"stb-f"}},
+				}}}}, nil
+			})
+			vpcClient.EXPECT().DescribeNatGateways(gomock.Any()).DoAndReturn(func(req *vpc.DescribeNatGatewaysRequest) (*vpc.DescribeNatGatewaysResponse, error) {
+				Expect(req.VpcId).To(Equal("vpc-1"))
+				Expect(req.Name).To(Equal("shoot--foo--bar-cn-beijing-g-natgw"))
+				return &vpc.DescribeNatGatewaysResponse{}, nil
+			})
+			vpcClient.EXPECT().CreateNatGateway(gomock.Any()).DoAndReturn(func(req *vpc.CreateNatGatewayRequest) (*vpc.CreateNatGatewayResponse, error) {
+				Expect(req.VpcId).To(Equal("vpc-1"))
+				Expect(req.VSwitchId).To(Equal("vsw-g"))
+				Expect(req.NatType).To(Equal("Enhanced"))
+				Expect(req.Name).To(Equal("shoot--foo--bar-cn-beijing-g-natgw"))
+				return &vpc.CreateNatGatewayResponse{NatGatewayId: "ngw-g"}, nil
+			})
+
+			Expect(reconciler.ensureNATGateway(ctx)).To(MatchError(ContainSubstring("NAT gateway ngw-g has been created")))
+			Expect(reconciler.state.Get(ZoneIdentifier(0, IdentifierZoneSNATTable))).To(Equal("stb-f"))
+			Expect(reconciler.state.Get(ZoneIdentifier(1, IdentifierZoneNATGateway))).To(Equal("ngw-g"))
+			Expect(reconciler.state.Get(IdentifierNATGateway)).To(BeEmpty())
+		})
+
+		It("should route the vswitches of a zone through the NAT gateway of the zone", func() {
+			config.Networks.Zones = config.Networks.Zones[1:]
+			reconciler.state.Set(ZoneIdentifier(0, IdentifierZoneVSwitch), "vsw-g")
+			reconciler.state.Set(ZoneIdentifier(0, IdentifierZoneNATGateway), "ngw-g")
+			reconciler.state.Set(ZoneIdentifier(0, IdentifierZoneRouteTable), "vtb-g")
+
+			vpcClient.EXPECT().DescribeRouteTableList(gomock.Any()).Return(&vpc.DescribeRouteTableListResponse{
+				RouterTableList: vpc.RouterTableList{RouterTableListType: []vpc.RouterTableListType{{RouteTableId: "vtb-g", Status: statusAvailable}}},
+			}, nil)
+			vpcClient.EXPECT().DescribeRouteEntryList(gomock.Any()).Return(&vpc.DescribeRouteEntryListResponse{}, nil)
+			vpcClient.EXPECT().CreateRouteEntry(gomock.Any()).DoAndReturn(func(req *vpc.CreateRouteEntryRequest) (*vpc.CreateRouteEntryResponse, error) {
+				Expect(req.RouteTableId).To(Equal("vtb-g"))
+				Expect(req.DestinationCidrBlock).To(Equal("0.0.0.0/0"))
+				Expect(req.NextHopType).To(Equal("NatGateway"))
+				Expect(req.NextHopId).To(Equal("ngw-g"))
+				return &vpc.CreateRouteEntryResponse{}, nil
+			})
+			vpcClient.EXPECT().DescribeVSwitches(gomock.Any()).Return(&vpc.DescribeVSwitchesResponse{
+				VSwitches: vpc.VSwitches{VSwitch: []vpc.VSwitch{{VSwitchId: "vsw-g", RouteTable: vpc.RouteTable{RouteTableId: "vtb-system"}}}},
+			}, nil)
+			vpcClient.EXPECT().AssociateRouteTable(gomock.Any()).DoAndReturn(func(req *vpc.AssociateRouteTableRequest) (*vpc.AssociateRouteTableResponse, error) {
+				Expect(req.RouteTableId).To(Equal("vtb-g"))
+				Expect(req.VSwitchId).To(Equal("vsw-g"))
+				return &vpc.AssociateRouteTableResponse{}, nil
+			})
+
+			Expect(reconciler.ensureZoneRouteTables(ctx)).To(Succeed())
+		})
+
+		It("should reject dedicated vswitches of NAT gateways in the Terraform reconciler", func() {
+			config.Networks.NatGateway = nil
+			config.Networks.Zones[0].NatGatewayCIDR = pointer.StringPtr("10.250.255.0/28")
+			a := &actuator{}
+
+			err := a.reconcileWithTerraform(ctx, &extensionsv1alpha1.Infrastructure{}, nil, config, nil)
+			Expect(err).To(MatchError(ContainSubstring("dedicated vswitches of NAT gateways are only supported by the flow reconciler")))
+		})
+
+		It("should create the NAT gateway in the dedicated vswitch of the zone", func() {
+			config.Networks.Zones = config.Networks.Zones[:1]
+			config.Networks.Zones[0].NatGatewayCIDR = pointer.StringPtr("10.250.255.0/28")
+
+			vpcClient.EXPECT().CreateVSwitch(gomock.Any()).DoAndReturn(func(req *vpc.CreateVSwitchRequest) (*vpc.CreateVSwitchResponse, error) {
+				Expect(req.CidrBlock).To(Equal("10.250.255.0/28"))
+				Expect(req.ZoneId).To(Equal("cn-beijing-f"))
+				return &vpc.CreateVSwitchResponse{VSwitchId: "vsw-natgw-f"}, nil
+			})
+			Expect(reconciler.ensureNATVSwitches(ctx)).To(MatchError(ContainSubstring("vswitch vsw-natgw-f has been created")))
+
+			vpcClient.EXPECT().DescribeNatGateways(gomock.Any()).Return(&vpc.DescribeNatGatewaysResponse{}, nil)
+			vpcClient.EXPECT().CreateNatGateway(gomock.Any()).DoAndReturn(func(req *vpc.CreateNatGatewayRequest) (*vpc.CreateNatGatewayResponse, error) {
+				Expect(req.VSwitchId).To(Equal("vsw-natgw-f"))
+				return &vpc.CreateNatGatewayResponse{NatGatewayId: "ngw-f"}, nil
+			})
+			Expect(reconciler.ensureNATGateway(ctx)).To(HaveOccurred())
+			Expect(reconciler.computeStatus().VPC.NatGateways).To(Equal([]alicloudv1alpha1.NatGatewayStatus{
+				{ID: "ngw-f", Zone: "cn-beijing-f", VSwitchID: "vsw-natgw-f"},
+			}))
+		})
+
+		It("should delete the dedicated vswitches of the NAT gateways", func() {
+			reconciler.state.Set(ZoneIdentifier(1, IdentifierZoneNATVSwitch), "vsw-natgw-g")
+			vpcClient.EXPECT().DescribeVSwitches(gomock.Any()).Return(&vpc.DescribeVSwitchesResponse{
+				VSwitches: vpc.VSwitches{VSwitch: []vpc.VSwitch{{VSwitchId: "vsw-natgw-g"}}},
+			}, nil)
+			vpcClient.EXPECT().DeleteVSwitch(gomock.Any()).DoAndReturn(func(req *vpc.DeleteVSwitchRequest) (*vpc.DeleteVSwitchResponse, error) {
+				Expect(req.VSwitchId).To(Equal("vsw-natgw-g"))
+				return &vpc.DeleteVSwitchResponse{}, nil
+			})
+
+			Expect(reconciler.deleteNATVSwitches(ctx)).To(Succeed())
+			Expect(reconciler.state.Get(ZoneIdentifier(1, IdentifierZoneNATVSwitch))).To(BeEmpty())
+		})
+
+		Context("multiple EIPs", func() {
+			var eips map[string]vpc.EipAddress
+
+			BeforeEach(func() {
+				config.Networks.Zones = config.Networks.Zones[:1]
+				config.Networks.NatGateway.EIPAllocation = &alicloudv1alpha1.EIPAllocation{Count: pointer.Int32Ptr(2)}
+				reconciler.state.Set(ZoneIdentifier(0, IdentifierZoneNATGateway), "ngw-f")
+				reconciler.state.Set(ZoneIdentifier(0, IdentifierZoneSNATTable), "stb-f")
+				reconciler.state.Set(EIPIdentifier(0, 0, IdentifierZoneEIP), "eip-f-0")
+
+				eips = map[string]vpc.EipAddress{
+					"eip-f-0": {AllocationId: "eip-f-0", Status: statusInUse, InstanceId: "ngw-f", IpAddress: "47.0.0.1"},
+				}
+				vpcClient.EXPECT().DescribeEipAddresses(gomock.Any()).DoAndReturn(func(req *vpc.DescribeEipAddressesRequest) (*vpc.DescribeEipAddressesResponse, error) {
+					res := &vpc.DescribeEipAddressesResponse{}
+					if eip, ok := eips[req.AllocationId]; ok {
+						res.EipAddresses.EipAddress = []vpc.EipAddress{eip}
+					}
+					return res, nil
+				}).AnyTimes()
+			})
+
+			It("should allocate all EIPs and use them for the SNAT entries of the zone", func() {
+				vpcClient.EXPECT().AllocateEipAddress(gomock.Any()).Return(&vpc.AllocateEipAddressResponse{AllocationId: "eip-f-1"}, nil)
+				Expect(reconciler.ensureEIPsAndSNATEntries(ctx)).To(MatchError(ContainSubstring("EIP eip-f-1 has been allocated")))
+				Expect(reconciler.state.Get(EIPIdentifier(0, 1, IdentifierZoneEIP))).To(Equal("eip-f-1"))
+
+				eips["eip-f-1"] = vpc.EipAddress{AllocationId: "eip-f-1", Status: statusAvailable, IpAddress: "47.0.0.2"}
+				vpcClient.EXPECT().AssociateEipAddress(gomock.Any()).DoAndReturn(func(req *vpc.AssociateEipAddressRequest) (*vpc.AssociateEipAddressResponse, error) {
+					Expect(req.AllocationId).To(Equal("eip-f-1"))
+					Expect(req.InstanceId).To(Equal("ngw-f"))
+					return &vpc.AssociateEipAddressResponse{}, nil
+				})
+				Expect(reconciler.ensureEIPsAndSNATEntries(ctx)).To(MatchError(ContainSubstring("EIP eip-f-1 is being associated")))
+
+				eips["eip-f-1"] = vpc.EipAddress{AllocationId: "eip-f-1", Status: statusInUse, InstanceId: "ngw-f", IpAddress: "47.0.0.2"}
+				vpcClient.EXPECT().CreateSnatEntry(gomock.Any()).DoAndReturn(func(req *vpc.CreateSnatEntryRequest) (*vpc.CreateSnatEntryResponse, error) {
+					Expect(req.SnatTableId).To(Equal("stb-f"))
+					Expect(req.SourceVSwitchId).To(Equal("vsw-f"))
+					Expect(req.SnatIp).To(Equal("47.0.0.1,47.0.0.2"))
+					return &vpc.CreateSnatEntryResponse{SnatEntryId: "snat-f"}, nil
+				})
+				Expect(reconciler.ensureEIPsAndSNATEntries(ctx)).To(Succeed())
+
+				Expect(reconciler.computeStatus().VPC.NatGateways).To(Equal([]alicloudv1alpha1.NatGatewayStatus{{
+					ID:   "ngw-f",
+					Zone: "cn-beijing-f",
+					EIPs: []alicloudv1alpha1.EIPStatus{
+						{AllocationID: "eip-f-0", IPAddress: "47.0.0.1"},
+						{AllocationID: "eip-f-1", IPAddress: "47.0.0.2"},
+					},
+				}}))
+			})
+
+			It("should release all EIPs on deletion", func() {
+				reconciler.state.Set(EIPIdentifier(0, 1, IdentifierZoneEIP), "eip-f-1")
+				reconciler.state.Set(EIPIdentifier(0, 1, IdentifierZoneEIPIPAddress), "47.0.0.2")
+				eips["eip-f-0"] = vpc.EipAddress{AllocationId: "eip-f-0", Status: statusAvailable}
+				eips["eip-f-1"] = vpc.EipAddress{AllocationId: "eip-f-1", Status: statusAvailable}
+
+				var (
+					lock     sync.Mutex
+					released []string
+				)
+				vpcClient.EXPECT().ReleaseEipAddress(gomock.Any()).DoAndReturn(func(req *vpc.ReleaseEipAddressRequest) (*vpc.ReleaseEipAddressResponse, error) {
+					lock.Lock()
+					defer lock.Unlock()
+					released = append(released, req.AllocationId)
+					return &vpc.ReleaseEipAddressResponse{}, nil
+				}).Times(2)
+
+				Expect(reconciler.deleteEIPs(ctx)).To(Succeed())
+				Expect(released).To(ConsistOf("eip-f-0", "eip-f-1"))
+				Expect(reconciler.state.Get(EIPIdentifier(0, 0, IdentifierZoneEIP))).To(BeEmpty())
+				Expect(reconciler.state.Get(EIPIdentifier(0, 1, IdentifierZoneEIP))).To(BeEmpty())
+				Expect(reconciler.state.Get(EIPIdentifier(0, 1, IdentifierZoneEIPIPAddress))).To(BeEmpty())
+			})
+		})
+
+		It("should record the NAT gateway of every zone in the status", func() {
+			reconciler.state.Set(ZoneIdentifier(0, IdentifierZoneNATGateway), "ngw-f")
+			reconciler.state.Set(ZoneIdentifier(1, IdentifierZoneNATGateway), "ngw-g")
+
+			Expect(reconciler.computeStatus().VPC.NatGateways).To(Equal([]alicloudv1alpha1.NatGatewayStatus{
+				{ID: "ngw-f", Zone: "cn-beijing-f"},
+				{ID: "ngw-g", Zone: "cn-beijing-g"},
+			}))
+		})
+	})
+
+	Describe("user-owned EIP", func() {
+		describeEIP := func(eips ...vpc.EipAddress) {
+			vpcClient.EXPECT().DescribeEipAddresses(gomock.Any()).DoAndReturn(func(req *vpc.DescribeEipAddressesRequest) (*vpc.DescribeEipAddressesResponse, error) {
+				Expect(req.AllocationId).To(Equal("eip-user"))
+				return &vpc.DescribeEipAddressesResponse{EipAddresses: vpc.EipAddresses{EipAddress: eips}}, nil
+			})
+		}
+
+		BeforeEach(func() {
+			config.Networks.Routes = nil
+			config.Networks.NatGateway = &alicloudv1alpha1.NatGateway{EIPAllocationID: pointer.StringPtr("eip-user")}
+			config.Networks.Zones = []alicloudv1alpha1.Zone{
+				{Name: "cn-beijing-f", Workers: "10.250.0.0/19"},
+				{Name: "cn-beijing-g", Workers: "10.250.32.0/19"},
+			}
+			reconciler.state.Set(IdentifierNATGateway, "ngw-1")
+			reconciler.state.Set(IdentifierSNATTable, "stb-1")
+			reconciler.state.Set(ZoneIdentifier(0, IdentifierZoneVSwitch), "vsw-f")
+			reconciler.state.Set(ZoneIdentifier(1, IdentifierZoneVSwitch), "vsw-g")
+		})
+
+		It("should associate the existing EIP with the NAT gateway instead of allocating a new one", func() {
+			describeEIP(vpc.EipAddress{AllocationId: "eip-user", Status: statusAvailable})
+			vpcClient.EXPECT().AssociateEipAddress(gomock.Any()).DoAndReturn(func(req *vpc.AssociateEipAddressRequest) (*vpc.AssociateEipAddressResponse, error) {
+				Expect(req.AllocationId).To(Equal("eip-user"))
+				Expect(req.InstanceId).To(Equal("ngw-1"))
+				Expect(req.InstanceType).To(Equal(eipInstanceTypeNat))
+				return &vpc.AssociateEipAddressResponse{}, nil
+			})
+
+			Expect(reconciler.ensureEIPsAndSNATEntries(ctx)).To(MatchError(ContainSubstring("EIP eip-user is being associated with NAT gateway ngw-1")))
+			Expect(reconciler.state.Get(ZoneIdentifier(0, IdentifierZoneEIP))).To(BeEmpty())
+		})
+
+		It("should use the existing EIP for the SNAT entries of all zones", func() {
+			for zoneIndex, vswitchID := range []string{"vsw-f", "vsw-g"} {
+				describeEIP(vpc.EipAddress{AllocationId: "eip-user", Status: statusInUse, InstanceId: "ngw-1", IpAddress: "47.0.0.1"})
+				snatEntryID := fmt.Sprintf("snat-%d", zoneIndex)
+				expectedVSwitchID := vswitchID
+				vpcClient.EXPECT().CreateSnatEntry(gomock.Any()).DoAndReturn(func(req *vpc.CreateSnatEntryRequest) (*vpc.CreateSnatEntryResponse, error) {
+					Expect(req.SnatTableId).To(Equal("stb-1"))
+					Expect(req.SourceVSwitchId).To(Equal(expectedVSwitchID))
+					Expect(req.SnatIp).To(Equal("47.0.0.1"))
+					return &vpc.CreateSnatEntryResponse{SnatEntryId: snatEntryID}, nil
+				})
+			}
+
+			Expect(reconciler.ensureEIPsAndSNATEntries(ctx)).To(Succeed())
+			Expect(reconciler.state.Get(ZoneIdentifier(1, IdentifierZoneSNATEntry))).To(Equal("snat-1"))
+		})
+
+		It("should fail if the EIP does not exist", func() {
+			describeEIP()
+
+			Expect(reconciler.ensureEIPsAndSNATEntries(ctx)).To(MatchError("EIP eip-user does not exist"))
+		})
+
+		It("should fail if the EIP is already bound to another instance", func() {
+			describeEIP(vpc.EipAddress{AllocationId: "eip-user", Status: statusInUse, InstanceType: "EcsInstance", InstanceId: "i-other"})
+
+			Expect(reconciler.ensureEIPsAndSNATEntries(ctx)).To(MatchError("EIP eip-user is already bound to EcsInstance i-other"))
+		})
+
+		It("should unassociate but not release the EIP on deletion", func() {
+			describeEIP(vpc.EipAddress{AllocationId: "eip-user", Status: statusInUse, InstanceId: "ngw-1"})
+			vpcClient.EXPECT().UnassociateEipAddress(gomock.Any()).DoAndReturn(func(req *vpc.UnassociateEipAddressRequest) (*vpc.UnassociateEipAddressResponse, error) {
+				Expect(req.AllocationId).To(Equal("eip-user"))
+				Expect(req.InstanceId).To(Equal("ngw-1"))
+				return &vpc.UnassociateEipAddressResponse{}, nil
+			})
+			Expect(reconciler.deleteEIPs(ctx)).To(MatchError(ContainSubstring("EIP eip-user is being unassociated")))
+
+			describeEIP(vpc.EipAddress{AllocationId: "eip-user", Status: statusAvailable})
+			Expect(reconciler.deleteEIPs(ctx)).To(Succeed())
+		})
+
+		It("should not unassociate the EIP if it is bound to another instance", func() {
+			describeEIP(vpc.EipAddress{AllocationId: "eip-user", Status: statusInUse, InstanceId: "ngw-other"})
+
+			Expect(reconciler.deleteEIPs(ctx)).To(Succeed())
+		})
+	})
+
+	Describe("availability zones", func() {
+		BeforeEach(func() {
+			config.Networks.Zones = []alicloudv1alpha1.Zone{
+				{Name: "cn-beijing-f", Workers: "10.250.0.0/24"},
+				{Name: "cn-beijing-g", Workers: "10.250.1.0/24"},
+			}
+			reconciler.state.Set(ZoneIdentifier(0, IdentifierZoneVSwitch), "vsw-f")
+			reconciler.state.Set(ZoneIdentifier(1, IdentifierZoneVSwitch), "vsw-g")
+		})
+
+		describeVSwitch := func(id, zoneID string) {
+			vpcClient.EXPECT().DescribeVSwitches(gomock.Any()).DoAndReturn(func(req *vpc.DescribeVSwitchesRequest) (*vpc.DescribeVSwitchesResponse, error) {
+				Expect(req.VSwitchId).To(Equal(id))
+				return &vpc.DescribeVSwitchesResponse{
+					VSwitches: vpc.VSwitches{VSwitch: []vpc.VSwitch{{VSwitchId: id, ZoneId: zoneID, Status: statusAvailable}}},
+				}, nil
+			})
+		}
+
+		It("should record the availability zones of the vswitches in the status", func() {
+			describeVSwitch("vsw-f", "cn-beijing-f")
+			describeVSwitch("vsw-g", "cn-beijing-g")
+
+			Expect(reconciler.ensureVSwitches(ctx)).To(Succeed())
+			Expect(reconciler.computeStatus().Zones).To(Equal([]alicloudv1alpha1.ZoneStatus{
+				{Name: "cn-beijing-f", ZoneID: "cn-beijing-f"},
+				{Name: "cn-beijing-g", ZoneID: "cn-beijing-g"},
+			}))
+		})
+
+		It("should omit the zones whose vswitches have not been described yet", func() {
+			reconciler.state.Set(ZoneIdentifier(1, IdentifierZoneID), "cn-beijing-g")
+
+			Expect(reconciler.computeStatus().Zones).To(Equal([]alicloudv1alpha1.ZoneStatus{
+				{Name: "cn-beijing-g", ZoneID: "cn-beijing-g"},
+			}))
+		})
+
+		It("should remove the availability zone together with the vswitch", func() {
+			reconciler.state.Set(ZoneIdentifier(0, IdentifierZoneID), "cn-beijing-f")
+			config.Networks.Zones = config.Networks.Zones[:1]
+			reconciler.state.Set(ZoneIdentifier(1, IdentifierZoneVSwitch), "")
+			vpcClient.EXPECT().DescribeVSwitches(gomock.Any()).Return(&vpc.DescribeVSwitchesResponse{
+				VSwitches: vpc.VSwitches{VSwitch: []vpc.VSwitch{{VSwitchId: "vsw-f"}}},
+			}, nil)
+			vpcClient.EXPECT().DeleteVSwitch(gomock.Any()).Return(&vpc.DeleteVSwitchResponse{}, nil)
+
+			Expect(reconciler.deleteVSwitches(ctx)).To(Succeed())
+			Expect(reconciler.state.Get(ZoneIdentifier(0, IdentifierZoneID))).To(BeEmpty())
+			Expect(reconciler.computeStatus().Zones).To(BeEmpty())
+		})
+	})
+
+	Describe("pods vswitches", func() {
+		BeforeEach(func() {
+			config.Networks.Zones = []alicloudv1alpha1.Zone{
+				{Name: "cn-beijing-f", Workers: "10.250.0.0/24", PodsCIDR: pointer.StringPtr("10.250.64.0/24")},
+			}
+			reconciler.state.Set(IdentifierVPC, "vpc-1")
+			reconciler.state.Set(ZoneIdentifier(0, IdentifierZoneVSwitch), "vsw-f")
+		})
+
+		It("should create the pods vswitch and record it in the status", func() {
+			vpcClient.EXPECT().CreateVSwitch(gomock.Any()).DoAndReturn(func(req *vpc.CreateVSwitchRequest) (*vpc.CreateVSwitchResponse, error) {
+				Expect(req.VpcId).To(Equal("vpc-1"))
+				Expect(req.VSwitchName).To(Equal("shoot--foo--bar-cn-beijing-f-pods-vsw"))
+				Expect(req.CidrBlock).To(Equal("10.250.64.0/24"))
+				Expect(req.ZoneId).To(Equal("cn-beijing-f"))
+				return &vpc.CreateVSwitchResponse{VSwitchId: "vsw-pods-f"}, nil
+			})
+
+			Expect(reconciler.ensurePodsVSwitches(ctx)).To(MatchError(ContainSubstring("vswitch vsw-pods-f has been created")))
+			Expect(reconciler.computeStatus().VPC.VSwitches).To(Equal([]alicloudv1alpha1.VSwitch{
+				{Purpose: alicloudv1alpha1.PurposeNodes, ID: "vsw-f", Zone: "cn-beijing-f"},
+				{Purpose: alicloudv1alpha1.PurposePods, ID: "vsw-pods-f", Zone: "cn-beijing-f"},
+			}))
+		})
+
+		It("should route the pods vswitch through the NAT gateway like the worker vswitches", func() {
+			Expect(zoneNATVSwitchIdentifiers(0, config.Networks.Zones[0])).To(Equal([]natVSwitchIdentifiers{
+				{name: "cn-beijing-f-vsw", vswitch: "zones/0/vswitch", snatEntry: "zones/0/snatEntry"},
+				{name: "cn-beijing-f-pods-vsw", vswitch: "zones/0/pods/vswitch", snatEntry: "zones/0/pods/snatEntry"},
+			}))
+		})
+
+		It("should delete the pods vswitch", func() {
+			reconciler.state.Set(ZoneIdentifier(0, IdentifierZonePodsVSwitch), "vsw-pods-f")
+			vpcClient.EXPECT().DescribeVSwitches(gomock.Any()).Return(&vpc.DescribeVSwitchesResponse{
+				VSwitches: vpc.VSwitches{VSwitch: []vpc.VSwitch{{VSwitchId: "vsw-pods-f"}}},
+			}, nil)
+			vpcClient.EXPECT().DeleteVSwitch(gomock.Any()).DoAndReturn(func(req *vpc.DeleteVSwitchRequest) (*vpc.DeleteVSwitchResponse, error) {
+				Expect(req.VSwitchId).To(Equal("vsw-pods-f"))
+				return &vpc.DeleteVSwitchResponse{}, nil
+			})
+
+			Expect(reconciler.deletePodsVSwitches(ctx)).To(Succeed())
+			Expect(reconciler.state.Get(ZoneIdentifier(0, IdentifierZonePodsVSwitch))).To(BeEmpty())
+		})
+	})
+
+	Describe("existing security group", func() {
+		BeforeEach(func() {
+			config.Networks.VPC = alicloudv1alpha1.VPC{ID: pointer.StringPtr("vpc-1")}
+			config.Networks.SecurityGroupID = pointer.StringPtr("sg-existing")
+		})
+
+		It("should use the existing security group without creating one or authorizing rules", func() {
+			Expect(reconciler.ensureSecurityGroup(ctx)).To(Succeed())
+			Expect(reconciler.state.Get(IdentifierSecurityGroup)).To(Equal("sg-existing"))
+		})
+
+		It("should not delete the existing security group", func() {
+			reconciler.state.Set(IdentifierSecurityGroup, "sg-existing")
+
+			Expect(reconciler.deleteSecurityGroup(ctx)).To(Succeed())
+			Expect(reconciler.state.Get(IdentifierSecurityGroup)).To(Equal("sg-existing"))
+		})
+
+		It("should not consider the existing security group as managed", func() {
+			reconciler.state.Set(IdentifierSecurityGroup, "sg-existing")
+
+			Expect(managedResourceIDs(config, reconciler.state)).NotTo(HaveKey(tagResourceTypeSecurityGroup))
+		})
+	})
+
+	Describe("dual-stack", func() {
+		It("should be rejected by the Terraform reconciler", func() {
+			config.Networks.DualStack = &alicloudv1alpha1.DualStack{Enabled: true}
+			a := &actuator{}
+
+			err := a.reconcileWithTerraform(ctx, &extensionsv1alpha1.Infrastructure{}, nil, config, nil)
+			Expect(err).To(MatchError("dual-stack VPCs are only supported by the flow reconciler, which is enabled with the annotation alicloud.provider.extensions.gardener.cloud/use-flow=true"))
+		})
+	})
+
+	Describe("#Reconcile", func() {
+		const (
+			keyPairName = "shoot--foo--bar-ssh-publickey"
+			zoneName    = "cn-beijing-f"
+		)
+
+		var (
+			ecsClient *mockalicloudclient.MockECS
+
+			// The fake cloud below is accessed by the tasks of the flow in parallel.
+			lock      sync.Mutex
+			counters  map[string]int
+			created   []string
+			deleted   []string
+			resources map[string]interface{}
+
+			oldFlowRetryInterval time.Duration
+		)
+
+		// create records the creation of a resource of the given kind and returns its ID.
+		create := func(kind string, resource func(id string) interface{}) string {
+			counters[kind]++
+			id := fmt.Sprintf("%s-%d", kind, counters[kind])
+			resources[id] = resource(id)
+			created = append(created, id)
+			return id
+		}
+
+		remove := func(id string) {
+			delete(resources, id)
+			deleted = append(deleted, id)
+		}
+
+		indexOf := func(id string) int {
+			for i, d := range deleted {
+				if d == id {
+					return i
+				}
+			}
+			Fail(fmt.Sprintf("%s has not been deleted", id))
+			return -1
+		}
+
+		restart := func(r *flowReconciler) *flowReconciler {
+			restarted, err := newFlowReconciler(c, r.infra.DeepCopy(), config, vpcClient, ecsClient, nil)
+			Expect(err).NotTo(HaveOccurred())
+			return restarted
+		}
+
+		BeforeEach(func() {
+			oldFlowRetryInterval = flowRetryInterval
+			flowRetryInterval = 10 * time.Millisecond
+
+			ecsClient = mockalicloudclient.NewMockECS(ctrl)
+			counters, created, deleted, resources = map[string]int{}, nil, nil, map[string]interface{}{}
+
+			c.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+			c.EXPECT().Update(gomock.Any(), gomock.Any()).AnyTimes()
+
+			config.Networks.Routes = nil
+			config.Networks.Zones = []alicloudv1alpha1.Zone{{Name: zoneName, Workers: "10.250.0.0/19"}}
+			infra := &extensionsv1alpha1.Infrastructure{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "shoot--foo--bar", Name: "infra"},
+			}
+
+			var err error
+			reconciler, err = newFlowReconciler(c, infra, config, vpcClient, ecsClient, nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			vpcClient.EXPECT().DescribeVpcs(gomock.Any()).DoAndReturn(func(req *vpc.DescribeVpcsRequest) (*vpc.DescribeVpcsResponse, error) {
+				lock.Lock()
+				defer lock.Unlock()
+				res := &vpc.DescribeVpcsResponse{}
+				for _, resource := range resources {
+					if v, ok := resource.(vpc.Vpc); ok && (v.VpcId == req.VpcId || (req.VpcId == "" && v.VpcName == req.VpcName)) {
+						res.Vpcs.Vpc = append(res.Vpcs.Vpc, v)
+					}
+				}
+				return res, nil
+			}).AnyTimes()
+			vpcClient.EXPECT().CreateVpc(gomock.Any()).DoAndReturn(func(req *vpc.CreateVpcRequest) (*vpc.CreateVpcResponse, error) {
+				lock.Lock()
+				defer lock.Unlock()
+				id := create("vpc", func(id string) interface{} {
+					return vpc.Vpc{VpcId: id, VpcName: req.VpcName, CidrBlock: req.CidrBlock, Status: statusAvailable, RouterTableIds: vpc.RouterTableIds{RouterTableIds: []string{"vtb-1"}}}
+				})
+				return &vpc.CreateVpcResponse{VpcId: id}, nil
+			}).AnyTimes()
+			vpcClient.EXPECT().DeleteVpc(gomock.Any()).DoAndReturn(func(req *vpc.DeleteVpcRequest) (*vpc.DeleteVpcResponse, error) {
+				lock.Lock()
+				defer lock.Unlock()
+				remove(req.VpcId)
+				return &vpc.DeleteVpcResponse{}, nil
+			}).AnyTimes()
+
+			vpcClient.EXPECT().DescribeVSwitches(gomock.Any()).DoAndReturn(func(req *vpc.DescribeVSwitchesRequest) (*vpc.DescribeVSwitchesResponse, error) {
+				lock.Lock()
+				defer lock.Unlock()
+				res := &vpc.DescribeVSwitchesResponse{}
+				if vswitch, ok := resources[req.VSwitchId].(vpc.VSwitch); ok {
+					res.VSwitches.VSwitch = append(res.VSwitches.VSwitch, vswitch)
+				}
+				return res, nil
+			}).AnyTimes()
+			vpcClient.EXPECT().CreateVSwitch(gomock.Any()).DoAndReturn(func(req *vpc.CreateVSwitchRequest) (*vpc.CreateVSwitchResponse, error) {
+				lock.Lock()
+				defer lock.Unlock()
+				id := create("vsw", func(id string) interface{} {
+					return vpc.VSwitch{VSwitchId: id, VpcId: req.VpcId, ZoneId: req.ZoneId, CidrBlock: req.CidrBlock, Status: statusAvailable}
+				})
+				return &vpc.CreateVSwitchResponse{VSwitchId: id}, nil
+			}).AnyTimes()
+			vpcClient.EXPECT().DeleteVSwitch(gomock.Any()).DoAndReturn(func(req *vpc.DeleteVSwitchRequest) (*vpc.DeleteVSwitchResponse, error) {
+				lock.Lock()
+				defer lock.Unlock()
+				remove(req.VSwitchId)
+				return &vpc.DeleteVSwitchResponse{}, nil
+			}).AnyTimes()
+
+			vpcClient.EXPECT().DescribeNatGateways(gomock.Any()).DoAndReturn(func(req *vpc.DescribeNatGatewaysRequest) (*vpc.DescribeNatGatewaysResponse, error) {
+				lock.Lock()
+				defer lock.Unlock()
+				res := &vpc.DescribeNatGatewaysResponse{}
+				for _, resource := range resources {
+					if n, ok := resource.(vpc.NatGateway); ok && (n.NatGatewayId == req.NatGatewayId || (req.NatGatewayId == "" && n.VpcId == req.VpcId && n.Name == req.Name)) {
+						res.NatGateways.NatGateway = append(res.NatGateways.NatGateway, n)
+					}
+				}
+				return res, nil
+			}).AnyTimes()
+			vpcClient.EXPECT().CreateNatGateway(gomock.Any()).DoAndReturn(func(req *vpc.CreateNatGatewayRequest) (*vpc.CreateNatGatewayResponse, error) {
+				lock.Lock()
+				defer lock.Unlock()
+				id := create("ngw", func(id string) interface{} {
+					return vpc.NatGateway{NatGatewayId: id, VpcId: req.VpcId, Name: req.Name, Status: statusAvailable, SnatTableIds: vpc.SnatTableIdsInDescribeNatGateways{SnatTableId: []string{"stb-1"}}}
+				})
+				return &vpc.CreateNatGatewayResponse{NatGatewayId: id}, nil
+			}).AnyTimes()
+			vpcClient.EXPECT().DeleteNatGateway(gomock.Any()).DoAndReturn(func(req *vpc.DeleteNatGatewayRequest) (*vpc.DeleteNatGatewayResponse, error) {
+				lock.Lock()
+				defer lock.Unlock()
+				remove(req.NatGatewayId)
+				return &vpc.DeleteNatGatewayResponse{}, nil
+			}).AnyTimes()
+
+			vpcClient.EXPECT().DescribeEipAddresses(gomock.Any()).DoAndReturn(func(req *vpc.DescribeEipAddressesRequest) (*vpc.DescribeEipAddressesResponse, error) {
+				lock.Lock()
+				defer lock.Unlock()
+				res := &vpc.DescribeEipAddressesResponse{}
+				if eip, ok := resources[req.AllocationId].(vpc.EipAddress); ok {
+					res.EipAddresses.EipAddress = append(res.EipAddresses.EipAddress, eip)
+				}
+				return res, nil
+			}).AnyTimes()
+			vpcClient.EXPECT().AllocateEipAddress(gomock.Any()).DoAndReturn(func(req *vpc.AllocateEipAddressRequest) (*vpc.AllocateEipAddressResponse, error) {
+				lock.Lock()
+				defer lock.Unlock()
+				id := create("eip", func(id string) interface{} {
+					return vpc.EipAddress{AllocationId: id, IpAddress: "47.0.0.1", Status: statusAvailable}
+				})
+				return &vpc.AllocateEipAddressResponse{AllocationId: id}, nil
+			}).AnyTimes()
+			vpcClient.EXPECT().AssociateEipAddress(gomock.Any()).DoAndReturn(func(req *vpc.AssociateEipAddressRequest) (*vpc.AssociateEipAddressResponse, error) {
+				lock.Lock()
+				defer lock.Unlock()
+				eip := resources[req.AllocationId].(vpc.EipAddress)
+				eip.Status, eip.InstanceId, eip.InstanceType = statusInUse, req.InstanceId, req.InstanceType
+				resources[req.AllocationId] = eip
+				return &vpc.AssociateEipAddressResponse{}, nil
+			}).AnyTimes()
+			vpcClient.EXPECT().UnassociateEipAddress(gomock.Any()).DoAndReturn(func(req *vpc.UnassociateEipAddressRequest) (*vpc.UnassociateEipAddressResponse, error) {
+				lock.Lock()
+				defer lock.Unlock()
+				eip := resources[req.AllocationId].(vpc.EipAddress)
+				eip.Status, eip.InstanceId, eip.InstanceType = statusAvailable, "", ""
+				resources[req.AllocationId] = eip
+				return &vpc.UnassociateEipAddressResponse{}, nil
+			}).AnyTimes()
+			vpcClient.EXPECT().ReleaseEipAddress(gomock.Any()).DoAndReturn(func(req *vpc.ReleaseEipAddressRequest) (*vpc.ReleaseEipAddressResponse, error) {
+				lock.Lock()
+				defer lock.Unlock()
+				remove(req.AllocationId)
+				return &vpc.ReleaseEipAddressResponse{}, nil
+			}).AnyTimes()
+
+			vpcClient.EXPECT().DescribeSnatTableEntries(gomock.Any()).DoAndReturn(func(req *vpc.DescribeSnatTableEntriesRequest) (*vpc.DescribeSnatTableEntriesResponse, error) {
+				lock.Lock()
+				defer lock.Unlock()
+				res := &vpc.DescribeSnatTableEntriesResponse{}
+				if entry, ok := resources[req.SnatEntryId].(vpc.SnatTableEntry); ok && entry.SnatTableId == req.SnatTableId {
+					res.SnatTableEntries.SnatTableEntry = append(res.SnatTableEntries.SnatTableEntry, entry)
+				}
+				return res, nil
+			}).AnyTimes()
+			vpcClient.EXPECT().CreateSnatEntry(gomock.Any()).DoAndReturn(func(req *vpc.CreateSnatEntryRequest) (*vpc.CreateSnatEntryResponse, error) {
+				lock.Lock()
+				defer lock.Unlock()
+				id := create("snat", func(id string) interface{} {
+					return vpc.SnatTableEntry{SnatEntryId: id, SnatTableId: req.SnatTableId, SourceVSwitchId: req.SourceVSwitchId, SnatIp: req.SnatIp}
+				})
+				return &vpc.CreateSnatEntryResponse{SnatEntryId: id}, nil
+			}).AnyTimes()
+			vpcClient.EXPECT().DeleteSnatEntry(gomock.Any()).DoAndReturn(func(req *vpc.DeleteSnatEntryRequest) (*vpc.DeleteSnatEntryResponse, error) {
+				lock.Lock()
+				defer lock.Unlock()
+				remove(req.SnatEntryId)
+				return &vpc.DeleteSnatEntryResponse{}, nil
+			}).AnyTimes()
+
+			ecsClient.EXPECT().CheckIfSecurityGroupExists(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, securityGroupID string) (bool, error) {
+				lock.Lock()
+				defer lock.Unlock()
+				_, ok := resources[securityGroupID]
+				return ok, nil
+			}).AnyTimes()
+			ecsClient.EXPECT().CreateSecurityGroup(gomock.Any(), gomock.Any(), "shoot--foo--bar-sg").DoAndReturn(func(_ context.Context, vpcID, name string) (string, error) {
+				lock.Lock()
+				defer lock.Unlock()
+				return create("sg", func(string) interface{} { return vpcID }), nil
+			}).AnyTimes()
+			ecsClient.EXPECT().AuthorizeSecurityGroupIngress(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+			ecsClient.EXPECT().DeleteSecurityGroup(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, securityGroupID string) error {
+				lock.Lock()
+				defer lock.Unlock()
+				remove(securityGroupID)
+				return nil
+			}).AnyTimes()
+
+			ecsClient.EXPECT().CheckIfKeyPairExists(gomock.Any(), keyPairName).DoAndReturn(func(_ context.Context, name string) (bool, error) {
+				lock.Lock()
+				defer lock.Unlock()
+				_, ok := resources[name]
+				return ok, nil
+			}).AnyTimes()
+			ecsClient.EXPECT().ImportKeyPair(gomock.Any(), keyPairName, gomock.Any()).DoAndReturn(func(_ context.Context, name, publicKey string) error {
+				lock.Lock()
+				defer lock.Unlock()
+				resources[name] = publicKey
+				created = append(created, name)
+				return nil
+			}).AnyTimes()
+			ecsClient.EXPECT().DeleteKeyPair(gomock.Any(), keyPairName).DoAndReturn(func(_ context.Context, name string) error {
+				lock.Lock()
+				defer lock.Unlock()
+				remove(name)
+				return nil
+			}).AnyTimes()
+		})
+
+		AfterEach(func() {
+			flowRetryInterval = oldFlowRetryInterval
+		})
+
+		It("should create all resources and report them in the status", func() {
+			status, err := reconciler.Reconcile(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(created).To(ConsistOf("vpc-1", "vsw-1", "ngw-1", "eip-1", "snat-1", "sg-1", keyPairName))
+			Expect(resources["eip-1"].(vpc.EipAddress).InstanceId).To(Equal("ngw-1"))
+			Expect(resources["snat-1"].(vpc.SnatTableEntry).SourceVSwitchId).To(Equal("vsw-1"))
+			Expect(resources["snat-1"].(vpc.SnatTableEntry).SnatIp).To(Equal("47.0.0.1"))
+			Expect(resources["sg-1"]).To(Equal("vpc-1"))
+
+			Expect(status.VPC.ID).To(Equal("vpc-1"))
+			Expect(status.VPC.VSwitches).To(Equal([]alicloudv1alpha1.VSwitch{{Purpose: alicloudv1alpha1.PurposeNodes, ID: "vsw-1", Zone: zoneName}}))
+			Expect(status.VPC.SecurityGroups).To(Equal([]alicloudv1alpha1.SecurityGroup{{Purpose: alicloudv1alpha1.PurposeNodes, ID: "sg-1"}}))
+			Expect(status.KeyPairName).To(Equal(keyPairName))
+			Expect(status.Zones).To(Equal([]alicloudv1alpha1.ZoneStatus{{Name: zoneName, ZoneID: zoneName}}))
+		})
+
+		It("should not create any resource again if the infrastructure is reconciled again", func() {
+			status, err := reconciler.Reconcile(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			created = nil
+
+			restarted := restart(reconciler)
+			Expect(restarted.Reconcile(ctx)).To(Equal(status))
+			Expect(created).To(BeEmpty())
+			Expect(deleted).To(BeEmpty())
+		})
+
+		It("should delete all resources after the resources depending on them", func() {
+			_, err := reconciler.Reconcile(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			restarted := restart(reconciler)
+			Expect(restarted.Delete(ctx, func(context.Context) error { return nil })).To(Succeed())
+
+			Expect(resources).To(BeEmpty())
+			Expect(indexOf("snat-1")).To(BeNumerically("<", indexOf("eip-1")))
+			Expect(indexOf("snat-1")).To(BeNumerically("<", indexOf("vsw-1")))
+			Expect(indexOf("eip-1")).To(BeNumerically("<", indexOf("ngw-1")))
+			Expect(deleted[len(deleted)-1]).To(Equal("vpc-1"))
+			Expect(restarted.state.Whiteboard).To(BeEmpty())
+			Expect(restart(restarted).state.Whiteboard).To(BeEmpty())
+		})
+	})
+
+	Describe("#Delete", func() {
+		var (
+			lock           sync.Mutex
+			calls          []string
+			inFlight       int
+			maxInFlight    int
+			failingVSwitch string
+		)
+
+		record := func(call string) {
+			lock.Lock()
+			defer lock.Unlock()
+			calls = append(calls, call)
+		}
+
+		indexOf := func(call string) int {
+			for i, c := range calls {
+				if c == call {
+					return i
+				}
+			}
+			Fail(fmt.Sprintf("%s has not been called", call))
+			return -1
+		}
+
+		BeforeEach(func() {
+			calls, inFlight, maxInFlight, failingVSwitch = nil, 0, 0, ""
+
+			c.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+			c.EXPECT().Update(gomock.Any(), gomock.Any()).AnyTimes()
+
+			config.Networks.Routes = nil
+			config.Networks.Zones = nil
+			Expect(setRoutes(reconciler.state, nil)).To(Succeed())
+			reconciler.state.Set(IdentifierVPC, "vpc-1")
+			reconciler.state.Set(IdentifierNATGateway, "ngw-1")
+			reconciler.state.Set(IdentifierSNATTable, "stb-1")
+			for zoneIndex, zoneName := range []string{"cn-beijing-f", "cn-beijing-g", "cn-beijing-h"} {
+				config.Networks.Zones = append(config.Networks.Zones, alicloudv1alpha1.Zone{
+					Name:              zoneName,
+					Workers:           fmt.Sprintf("10.250.%d.0/24", 2*zoneIndex),
+					AdditionalWorkers: []string{fmt.Sprintf("10.250.%d.0/24", 2*zoneIndex+1)},
+				})
+				reconciler.state.Set(ZoneIdentifier(zoneIndex, IdentifierZoneEIP), fmt.Sprintf("eip-%d", zoneIndex))
+				for vswitchIndex := 0; vswitchIndex < 2; vswitchIndex++ {
+					reconciler.state.Set(VSwitchIdentifier(zoneIndex, vswitchIndex, IdentifierZoneVSwitch), fmt.Sprintf("vsw-%d-%d", zoneIndex, vswitchIndex))
+					reconciler.state.Set(VSwitchIdentifier(zoneIndex, vswitchIndex, IdentifierZoneSNATEntry), fmt.Sprintf("snat-%d-%d", zoneIndex, vswitchIndex))
+				}
+			}
+
+			vpcClient.EXPECT().DescribeSnatTableEntries(gomock.Any()).DoAndReturn(func(req *vpc.DescribeSnatTableEntriesRequest) (*vpc.DescribeSnatTableEntriesResponse, error) {
+				return &vpc.DescribeSnatTableEntriesResponse{SnatTableEntries: vpc.SnatTableEntries{SnatTableEntry: []vpc.SnatTableEntry{
+					{SnatTableId: req.SnatTableId, SnatEntryId: req.SnatEntryId},
+				}}}, nil
+			}).AnyTimes()
+			vpcClient.EXPECT().DeleteSnatEntry(gomock.Any()).DoAndReturn(func(req *vpc.DeleteSnatEntryRequest) (*vpc.DeleteSnatEntryResponse, error) {
+				record(req.SnatEntryId)
+				return &vpc.DeleteSnatEntryResponse{}, nil
+			}).AnyTimes()
+			vpcClient.EXPECT().DescribeEipAddresses(gomock.Any()).DoAndReturn(func(req *vpc.DescribeEipAddressesRequest) (*vpc.DescribeEipAddressesResponse, error) {
+				return &vpc.DescribeEipAddressesResponse{EipAddresses: vpc.EipAddresses{EipAddress: []vpc.EipAddress{
+					{AllocationId: req.AllocationId, Status: statusAvailable},
+				}}}, nil
+			}).AnyTimes()
+			vpcClient.EXPECT().ReleaseEipAddress(gomock.Any()).DoAndReturn(func(req *vpc.ReleaseEipAddressRequest) (*vpc.ReleaseEipAddressResponse, error) {
+				record(req.AllocationId)
+				return &vpc.ReleaseEipAddressResponse{}, nil
+			}).AnyTimes()
+			vpcClient.EXPECT().DescribeNatGateways(gomock.Any()).DoAndReturn(func(req *vpc.DescribeNatGatewaysRequest) (*vpc.DescribeNatGatewaysResponse, error) {
+				return &vpc.DescribeNatGatewaysResponse{NatGateways: vpc.NatGateways{NatGateway: []vpc.NatGateway{
+					{NatGatewayId: req.NatGatewayId},
+				}}}, nil
+			}).AnyTimes()
+			vpcClient.EXPECT().DeleteNatGateway(gomock.Any()).DoAndReturn(func(req *vpc.DeleteNatGatewayRequest) (*vpc.DeleteNatGatewayResponse, error) {
+				record(req.NatGatewayId)
+				return &vpc.DeleteNatGatewayResponse{}, nil
+			}).AnyTimes()
+			vpcClient.EXPECT().DescribeVSwitches(gomock.Any()).DoAndReturn(func(req *vpc.DescribeVSwitchesRequest) (*vpc.DescribeVSwitchesResponse, error) {
+				return &vpc.DescribeVSwitchesResponse{VSwitches: vpc.VSwitches{VSwitch: []vpc.VSwitch{
+					{VSwitchId: req.VSwitchId},
+				}}}, nil
+			}).AnyTimes()
+			vpcClient.EXPECT().DeleteVSwitch(gomock.Any()).DoAndReturn(func(req *vpc.DeleteVSwitchRequest) (*vpc.DeleteVSwitchResponse, error) {
+				lock.Lock()
+				inFlight++
+				if inFlight > maxInFlight {
+					maxInFlight = inFlight
+				}
+				lock.Unlock()
+
+				time.Sleep(10 * time.Millisecond)
+
+				lock.Lock()
+				inFlight--
+				lock.Unlock()
+
+				if req.VSwitchId == failingVSwitch {
+					return nil, fmt.Errorf("vswitch %s has dependencies", req.VSwitchId)
+				}
+				record(req.VSwitchId)
+				return &vpc.DeleteVSwitchResponse{}, nil
+			}).AnyTimes()
+			vpcClient.EXPECT().DescribeVpcs(gomock.Any()).DoAndReturn(func(req *vpc.DescribeVpcsRequest) (*vpc.DescribeVpcsResponse, error) {
+				return &vpc.DescribeVpcsResponse{Vpcs: vpc.Vpcs{Vpc: []vpc.Vpc{{VpcId: req.VpcId}}}}, nil
+			}).AnyTimes()
+			vpcClient.EXPECT().DeleteVpc(gomock.Any()).DoAndReturn(func(req *vpc.DeleteVpcRequest) (*vpc.DeleteVpcResponse, error) {
+				record(req.VpcId)
+				return &vpc.DeleteVpcResponse{}, nil
+			}).AnyTimes()
+		})
+
+		It("should delete the resources in parallel in the order of their dependencies", func() {
+			Expect(reconciler.Delete(ctx, func(context.Context) error { return nil })).To(Succeed())
+
+			Expect(calls).To(HaveLen(6 + 3 + 1 + 6 + 1))
+			for zoneIndex := 0; zoneIndex < 3; zoneIndex++ {
+				eip := fmt.Sprintf("eip-%d", zoneIndex)
+				for vswitchIndex := 0; vswitchIndex < 2; vswitchIndex++ {
+					snatEntry := fmt.Sprintf("snat-%d-%d", zoneIndex, vswitchIndex)
+					vswitch := fmt.Sprintf("vsw-%d-%d", zoneIndex, vswitchIndex)
+
+					Expect(indexOf(snatEntry)).To(BeNumerically("<", indexOf(eip)))
+					Expect(indexOf(snatEntry)).To(BeNumerically("<", indexOf(vswitch)))
+					Expect(indexOf(vswitch)).To(BeNumerically("<", indexOf("vpc-1")))
+				}
+				Expect(indexOf(eip)).To(BeNumerically("<", indexOf("ngw-1")))
+			}
+			Expect(indexOf("ngw-1")).To(BeNumerically("<", indexOf("vpc-1")))
+			Expect(calls[len(calls)-1]).To(Equal("vpc-1"))
+
+			Expect(maxInFlight).To(BeNumerically(">", 1))
+			Expect(maxInFlight).To(BeNumerically("<=", flowDeletionWorkers))
+			Expect(reconciler.state.Get(IdentifierVPC)).To(BeEmpty())
+		})
+
+		It("should delete the other vswitches if one of them cannot be deleted", func() {
+			failingVSwitch = "vsw-1-0"
+
+			err := reconciler.deleteVSwitches(ctx)
+			Expect(err).To(MatchError(ContainSubstring("vswitch vsw-1-0 has dependencies")))
+
+			Expect(calls).To(ConsistOf("vsw-0-0", "vsw-0-1", "vsw-1-1", "vsw-2-0", "vsw-2-1"))
+			Expect(reconciler.state.Get(VSwitchIdentifier(1, 0, IdentifierZoneVSwitch))).To(Equal("vsw-1-0"))
+			Expect(reconciler.state.Get(VSwitchIdentifier(0, 0, IdentifierZoneVSwitch))).To(BeEmpty())
+		})
+	})
+
+	Describe("#deleteRoutes", func() {
+		It("should delete the applied routes", func() {
+			describeRouteEntries(oldRoute.DestinationCIDR, vpc.RouteEntry{RouteTableId: "vtb-1", DestinationCidrBlock: oldRoute.DestinationCIDR, InstanceId: oldRoute.NextHopID})
+			vpcClient.EXPECT().DeleteRouteEntry(gomock.Any()).Return(&vpc.DeleteRouteEntryResponse{}, nil)
+
+			Expect(reconciler.deleteRoutes(ctx)).To(Succeed())
+			Expect(reconciler.state.Get(IdentifierRoutes)).To(BeEmpty())
+		})
+	})
+
+	Describe("partially created infrastructure", func() {
+		const vpcName = "shoot--foo--bar-vpc"
+
+		BeforeEach(func() {
+			config.Networks.Routes = nil
+			infra := &extensionsv1alpha1.Infrastructure{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "shoot--foo--bar", Name: "infra"},
+			}
+
+			var err error
+			reconciler, err = newFlowReconciler(c, infra, config, vpcClient, nil, nil)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		// restart simulates a crash of the extension by creating a new reconciler from the state persisted by the given one.
+		restart := func(r *flowReconciler) *flowReconciler {
+			restarted, err := newFlowReconciler(c, r.infra.DeepCopy(), config, vpcClient, nil, nil)
+			Expect(err).NotTo(HaveOccurred())
+			return restarted
+		}
+
+		describeVPCsByName := func(vpcs ...vpc.Vpc) {
+			vpcClient.EXPECT().DescribeVpcs(gomock.Any()).DoAndReturn(func(req *vpc.DescribeVpcsRequest) (*vpc.DescribeVpcsResponse, error) {
+				Expect(req.VpcName).To(Equal(vpcName))
+				Expect(req.VpcId).To(BeEmpty())
+				return &vpc.DescribeVpcsResponse{Vpcs: vpc.Vpcs{Vpc: vpcs}}, nil
+			})
+		}
+
+		describeVPC := func(vpcID string) {
+			vpcClient.EXPECT().DescribeVpcs(gomock.Any()).DoAndReturn(func(req *vpc.DescribeVpcsRequest) (*vpc.DescribeVpcsResponse, error) {
+				Expect(req.VpcId).To(Equal(vpcID))
+				return &vpc.DescribeVpcsResponse{Vpcs: vpc.Vpcs{Vpc: []vpc.Vpc{{VpcId: vpcID, VpcName: vpcName, CidrBlock: "10.250.0.0/16", Status: statusAvailable}}}}, nil
+			})
+		}
+
+		expectDeleteVPC := func(vpcID string, err error) {
+			vpcClient.EXPECT().DeleteVpc(gomock.Any()).DoAndReturn(func(req *vpc.DeleteVpcRequest) (*vpc.DeleteVpcResponse, error) {
+				Expect(req.VpcId).To(Equal(vpcID))
+				return &vpc.DeleteVpcResponse{}, err
+			})
+		}
+
+		It("should delete the VPC if the creation of the NAT gateway failed", func() {
+			describeVPCsByName()
+			vpcClient.EXPECT().CreateVpc(gomock.Any()).Return(&vpc.CreateVpcResponse{VpcId: "vpc-1"}, nil)
+			Expect(reconciler.ensureVPC(ctx)).To(MatchError(ContainSubstring("VPC vpc-1 has been created")))
+
+			describeVPC("vpc-1")
+			vpcClient.EXPECT().DescribeNatGateways(gomock.Any()).Return(&vpc.DescribeNatGatewaysResponse{}, nil)
+			vpcClient.EXPECT().CreateNatGateway(gomock.Any()).Return(nil, fmt.Errorf("quota exceeded"))
+			Expect(reconciler.ensureVPC(ctx)).To(Succeed())
+			Expect(reconciler.ensureNATGateway(ctx)).To(MatchError("quota exceeded"))
+
+			restarted := restart(reconciler)
+			Expect(restarted.state.Get(IdentifierVPC)).To(Equal("vpc-1"))
+
+			vpcClient.EXPECT().DescribeNatGateways(gomock.Any()).DoAndReturn(func(req *vpc.DescribeNatGatewaysRequest) (*vpc.DescribeNatGatewaysResponse, error) {
+				Expect(req.VpcId).To(Equal("vpc-1"))
+				Expect(req.Name).To(Equal("shoot--foo--bar-natgw"))
+				return &vpc.DescribeNatGatewaysResponse{}, nil
+			})
+			Expect(restarted.deleteNATGateway(ctx)).To(Succeed())
+
+			describeVPC("vpc-1")
+			expectDeleteVPC("vpc-1", nil)
+			Expect(restarted.deleteVPC(ctx)).To(Succeed())
+			Expect(restarted.state.Get(IdentifierVPC)).To(BeEmpty())
+			Expect(restart(restarted).state.Get(IdentifierVPC)).To(BeEmpty())
+		})
+
+		It("should recover the VPC if it was created but not recorded before the crash", func() {
+			describeVPCsByName(vpc.Vpc{VpcId: "vpc-other", VpcName: vpcName, CidrBlock: "10.0.0.0/16"}, vpc.Vpc{VpcId: "vpc-1", VpcName: vpcName, CidrBlock: "10.250.0.0/16"})
+			describeVPC("vpc-1")
+			Expect(reconciler.ensureVPC(ctx)).To(Succeed())
+			Expect(reconciler.state.Get(IdentifierVPC)).To(Equal("vpc-1"))
+		})
+
+		It("should delete the VPC if it was created but not recorded before the crash", func() {
+			describeVPCsByName(vpc.Vpc{VpcId: "vpc-1", VpcName: vpcName, CidrBlock: "10.250.0.0/16"})
+			describeVPC("vpc-1")
+			expectDeleteVPC("vpc-1", nil)
+
+			Expect(reconciler.deleteVPC(ctx)).To(Succeed())
+		})
+
+		It("should recover the NAT gateway if it was created but not recorded before the crash", func() {
+			reconciler.state.Set(IdentifierVPC, "vpc-1")
+			vpcClient.EXPECT().DescribeNatGateways(gomock.Any()).Return(&vpc.DescribeNatGatewaysResponse{NatGateways: vpc.NatGateways{NatGateway: []vpc.NatGateway{
+				{NatGatewayId: "ngw-1", Name: "shoot--foo--bar-natgw"},
+			}}}, nil)
+			vpcClient.EXPECT().DescribeNatGateways(gomock.Any()).DoAndReturn(func(req *vpc.DescribeNatGatewaysRequest) (*vpc.DescribeNatGatewaysResponse, error) {
+				Expect(req.NatGatewayId).To(Equal("ngw-1"))
+				return &vpc.DescribeNatGatewaysResponse{NatGateways: vpc.NatGateways{NatGateway: []vpc.NatGateway{{NatGatewayId: "ngw-1"}}}}, nil
+			})
+			vpcClient.EXPECT().DeleteNatGateway(gomock.Any()).DoAndReturn(func(req *vpc.DeleteNatGatewayRequest) (*vpc.DeleteNatGatewayResponse, error) {
+				Expect(req.NatGatewayId).To(Equal("ngw-1"))
+				return &vpc.DeleteNatGatewayResponse{}, nil
+			})
+
+			Expect(reconciler.deleteNATGateway(ctx)).To(Succeed())
+			Expect(reconciler.state.Get(IdentifierNATGateway)).To(BeEmpty())
+		})
+
+		It("should tolerate resources which have already been deleted", func() {
+			reconciler.state.Set(IdentifierVPC, "vpc-1")
+			describeVPC("vpc-1")
+			expectDeleteVPC("vpc-1", errors.NewServerError(http.StatusNotFound, `{"Code":"InvalidVpcId.NotFound"}`, ""))
+
+			Expect(reconciler.deleteVPC(ctx)).To(Succeed())
+			Expect(reconciler.state.Get(IdentifierVPC)).To(BeEmpty())
+		})
+
+		It("should not tolerate other errors during the deletion", func() {
+			reconciler.state.Set(IdentifierVPC, "vpc-1")
+			describeVPC("vpc-1")
+			expectDeleteVPC("vpc-1", errors.NewServerError(http.StatusBadRequest, `{"Code":"DependencyViolation"}`, ""))
+
+			Expect(reconciler.deleteVPC(ctx)).To(HaveOccurred())
+			Expect(reconciler.state.Get(IdentifierVPC)).To(Equal("vpc-1"))
+		})
+	})
+})
@@ -0,0 +1,109 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/vpc"
+	"github.com/pkg/errors"
+)
+
+const (
+	flowLogResourceTypeVPC = "VPC"
+	flowLogTrafficTypeAll  = "All"
+	flowLogStatusInactive  = "Inactive"
+)
+
+func (r *flowReconciler) describeFlowLog(flowLogID string) (*vpc.FlowLog, error) {
+	if flowLogID == "" {
+		return nil, nil
+	}
+
+	req := vpc.CreateDescribeFlowLogsRequest()
+	req.FlowLogId = flowLogID
+	res, err := r.vpcClient.DescribeFlowLogs(req)
+	if err != nil {
+		return nil, err
+	}
+	if len(res.FlowLogs.FlowLog) == 0 {
+		return nil, nil
+	}
+	return &res.FlowLogs.FlowLog[0], nil
+}
+
+// ensureFlowLog ensures a flow log of the VPC delivering to the configured SLS logstore if flow logs are enabled, and
+// deletes it otherwise. A flow log delivering to another logstore is replaced.
+func (r *flowReconciler) ensureFlowLog(ctx context.Context) error {
+	target := r.config.Networks.FlowLogTarget
+	if !r.config.Networks.EnableFlowLogs || target == nil {
+		return r.deleteFlowLog(ctx)
+	}
+
+	existing, err := r.describeFlowLog(r.state.Get(IdentifierFlowLog))
+	if err != nil {
+		return err
+	}
+	if existing != nil && (existing.ProjectName != target.ProjectName || existing.LogStoreName != target.LogStoreName) {
+		if err := r.deleteFlowLog(ctx); err != nil {
+			return err
+		}
+		existing = nil
+	}
+
+	if existing == nil {
+		req := vpc.CreateCreateFlowLogRequest()
+		req.FlowLogName = r.name("flow-log")
+		req.ResourceType = flowLogResourceTypeVPC
+		req.ResourceId = r.state.Get(IdentifierVPC)
+		req.TrafficType = flowLogTrafficTypeAll
+		req.ProjectName = target.ProjectName
+		req.LogStoreName = target.LogStoreName
+		res, err := r.vpcClient.CreateFlowLog(req)
+		if err != nil {
+			// The logstore is not managed by the extension, hence the most likely cause is that it does not exist.
+			return errors.Wrapf(err, "could not create flow log delivering to logstore %s of SLS project %s, please make sure that the logstore exists", target.LogStoreName, target.ProjectName)
+		}
+		return r.setAndPersist(ctx, IdentifierFlowLog, res.FlowLogId)
+	}
+
+	if existing.Status == flowLogStatusInactive {
+		return fmt.Errorf("flow log %s is inactive, please check the SLS project %s", existing.FlowLogId, target.ProjectName)
+	}
+	return nil
+}
+
+// deleteFlowLog deletes the flow log of the VPC. The SLS logstore is not managed by the extension and is kept.
+func (r *flowReconciler) deleteFlowLog(ctx context.Context) error {
+	if r.state.Get(IdentifierFlowLog) == "" {
+		return nil
+	}
+
+	flowLog, err := r.describeFlowLog(r.state.Get(IdentifierFlowLog))
+	if err != nil {
+		return err
+	}
+
+	if flowLog != nil {
+		req := vpc.CreateDeleteFlowLogRequest()
+		req.FlowLogId = flowLog.FlowLogId
+		if _, err := r.vpcClient.DeleteFlowLog(req); err != nil {
+			return err
+		}
+	}
+
+	return r.setAndPersist(ctx, IdentifierFlowLog, "")
+}
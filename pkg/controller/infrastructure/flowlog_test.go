@@ -0,0 +1,154 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+
+	alicloudv1alpha1 "github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/alicloud/v1alpha1"
+	mockalicloudclient "github.com/gardener/gardener-extension-provider-alicloud/pkg/mock/provider-alicloud/alicloud/client"
+
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/vpc"
+	mockclient "github.com/gardener/gardener-extensions/pkg/mock/controller-runtime/client"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+)
+
+var _ = Describe("Flow log", func() {
+	var (
+		ctrl      *gomock.Controller
+		c         *mockclient.MockClient
+		vpcClient *mockalicloudclient.MockVPC
+
+		ctx = context.TODO()
+
+		config     *alicloudv1alpha1.InfrastructureConfig
+		reconciler *flowReconciler
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		c = mockclient.NewMockClient(ctrl)
+		vpcClient = mockalicloudclient.NewMockVPC(ctrl)
+
+		c.EXPECT().Status().Return(c).AnyTimes()
+		c.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).AnyTimes()
+		c.EXPECT().Update(ctx, gomock.Any()).AnyTimes()
+
+		infra := &extensionsv1alpha1.Infrastructure{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "shoot--foo--bar", Name: "infra"},
+		}
+		config = &alicloudv1alpha1.InfrastructureConfig{
+			Networks: alicloudv1alpha1.Networks{
+				VPC:            alicloudv1alpha1.VPC{CIDR: pointer.StringPtr("10.250.0.0/16")},
+				EnableFlowLogs: true,
+				FlowLogTarget:  &alicloudv1alpha1.FlowLogTarget{ProjectName: "forensics", LogStoreName: "flow-logs"},
+			},
+		}
+
+		var err error
+		reconciler, err = newFlowReconciler(c, infra, config, vpcClient, nil, nil)
+		Expect(err).NotTo(HaveOccurred())
+		reconciler.state.Set(IdentifierVPC, "vpc-1")
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	describeFlowLog := func(flowLog vpc.FlowLog) {
+		vpcClient.EXPECT().DescribeFlowLogs(gomock.Any()).DoAndReturn(func(req *vpc.DescribeFlowLogsRequest) (*vpc.DescribeFlowLogsResponse, error) {
+			Expect(req.FlowLogId).To(Equal(flowLog.FlowLogId))
+			return &vpc.DescribeFlowLogsResponse{FlowLogs: vpc.FlowLogs{FlowLog: []vpc.FlowLog{flowLog}}}, nil
+		})
+	}
+
+	Describe("#ensureFlowLog", func() {
+		It("should create a flow log of the VPC delivering to the configured logstore", func() {
+			vpcClient.EXPECT().CreateFlowLog(gomock.Any()).DoAndReturn(func(req *vpc.CreateFlowLogRequest) (*vpc.CreateFlowLogResponse, error) {
+				Expect(req.ResourceType).To(Equal("VPC"))
+				Expect(req.ResourceId).To(Equal("vpc-1"))
+				Expect(req.TrafficType).To(Equal("All"))
+				Expect(req.ProjectName).To(Equal("forensics"))
+				Expect(req.LogStoreName).To(Equal("flow-logs"))
+				return &vpc.CreateFlowLogResponse{FlowLogId: "fl-1"}, nil
+			})
+
+			Expect(reconciler.ensureFlowLog(ctx)).To(Succeed())
+			Expect(reconciler.state.Get(IdentifierFlowLog)).To(Equal("fl-1"))
+		})
+
+		It("should report a missing logstore", func() {
+			vpcClient.EXPECT().CreateFlowLog(gomock.Any()).Return(nil, fmt.Errorf("SLS logstore not found"))
+
+			Expect(reconciler.ensureFlowLog(ctx)).To(MatchError(ContainSubstring("please make sure that the logstore exists")))
+			Expect(reconciler.state.Get(IdentifierFlowLog)).To(BeEmpty())
+		})
+
+		It("should keep an existing flow log delivering to the configured logstore", func() {
+			reconciler.state.Set(IdentifierFlowLog, "fl-1")
+			describeFlowLog(vpc.FlowLog{FlowLogId: "fl-1", ProjectName: "forensics", LogStoreName: "flow-logs", Status: "Active"})
+
+			Expect(reconciler.ensureFlowLog(ctx)).To(Succeed())
+		})
+
+		It("should replace a flow log delivering to another logstore", func() {
+			reconciler.state.Set(IdentifierFlowLog, "fl-1")
+			describeFlowLog(vpc.FlowLog{FlowLogId: "fl-1", ProjectName: "forensics", LogStoreName: "old"})
+			describeFlowLog(vpc.FlowLog{FlowLogId: "fl-1", ProjectName: "forensics", LogStoreName: "old"})
+			gomock.InOrder(
+				vpcClient.EXPECT().DeleteFlowLog(gomock.Any()).Return(&vpc.DeleteFlowLogResponse{}, nil),
+				vpcClient.EXPECT().CreateFlowLog(gomock.Any()).Return(&vpc.CreateFlowLogResponse{FlowLogId: "fl-2"}, nil),
+			)
+
+			Expect(reconciler.ensureFlowLog(ctx)).To(Succeed())
+			Expect(reconciler.state.Get(IdentifierFlowLog)).To(Equal("fl-2"))
+		})
+
+		It("should delete the flow log if flow logs are disabled", func() {
+			config.Networks.EnableFlowLogs = false
+			config.Networks.FlowLogTarget = nil
+			reconciler.state.Set(IdentifierFlowLog, "fl-1")
+			describeFlowLog(vpc.FlowLog{FlowLogId: "fl-1"})
+			vpcClient.EXPECT().DeleteFlowLog(gomock.Any()).DoAndReturn(func(req *vpc.DeleteFlowLogRequest) (*vpc.DeleteFlowLogResponse, error) {
+				Expect(req.FlowLogId).To(Equal("fl-1"))
+				return &vpc.DeleteFlowLogResponse{}, nil
+			})
+
+			Expect(reconciler.ensureFlowLog(ctx)).To(Succeed())
+			Expect(reconciler.state.Get(IdentifierFlowLog)).To(BeEmpty())
+		})
+	})
+
+	Describe("#deleteFlowLog", func() {
+		It("should do nothing if no flow log has been created", func() {
+			Expect(reconciler.deleteFlowLog(ctx)).To(Succeed())
+		})
+	})
+
+	Describe("#reconcileWithTerraform", func() {
+		It("should reject flow logs as they are only supported by the flow reconciler", func() {
+			a := &actuator{}
+
+			err := a.reconcileWithTerraform(ctx, &extensionsv1alpha1.Infrastructure{}, nil, config, nil)
+			Expect(err).To(MatchError("flow logs are only supported by the flow reconciler, which is enabled with the annotation alicloud.provider.extensions.gardener.cloud/use-flow=true"))
+		})
+	})
+})
@@ -0,0 +1,90 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package infrastructure
+
+import (
+	"context"
+
+	"github.com/gardener/gardener-extension-provider-alicloud/pkg/alicloud"
+	mockalicloudclient "github.com/gardener/gardener-extension-provider-alicloud/pkg/mock/provider-alicloud/alicloud/client"
+	mockclient "github.com/gardener/gardener-extensions/pkg/mock/controller-runtime/client"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("Image sharing", func() {
+	var (
+		ctrl             *gomock.Controller
+		reader           *mockclient.MockClient
+		newClientFactory *mockalicloudclient.MockClientFactory
+
+		ctx       = context.TODO()
+		secretRef = &corev1.SecretReference{Namespace: "garden", Name: "machine-image-owner"}
+
+		a *actuator
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		reader = mockclient.NewMockClient(ctrl)
+		newClientFactory = mockalicloudclient.NewMockClientFactory(ctrl)
+
+		a = &actuator{newClientFactory: newClientFactory, machineImageOwnerSecretRef: secretRef}
+		Expect(a.InjectAPIReader(reader)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	expectSecret := func(accessKeyID, accessKeySecret string) {
+		reader.EXPECT().
+			Get(ctx, client.ObjectKey{Namespace: secretRef.Namespace, Name: secretRef.Name}, &corev1.Secret{}).
+			DoAndReturn(func(_ context.Context, _ client.ObjectKey, obj runtime.Object) error {
+				obj.(*corev1.Secret).Data = map[string][]byte{
+					alicloud.AccessKeyID:     []byte(accessKeyID),
+					alicloud.AccessKeySecret: []byte(accessKeySecret),
+				}
+				return nil
+			})
+	}
+
+	Describe("#newMachineImageOwnerECSClient", func() {
+		It("should use rotated credentials of the machine image owner", func() {
+			oldClient := mockalicloudclient.NewMockECS(ctrl)
+			newClient := mockalicloudclient.NewMockECS(ctrl)
+
+			expectSecret("old-id", "old-secret")
+			newClientFactory.EXPECT().NewECSClient(ctx, "", &alicloud.Credentials{AccessKeyID: "old-id", AccessKeySecret: "old-secret"}).Return(oldClient, nil)
+			Expect(a.newMachineImageOwnerECSClient(ctx)).To(BeIdenticalTo(oldClient))
+
+			expectSecret("new-id", "new-secret")
+			newClientFactory.EXPECT().NewECSClient(ctx, "", &alicloud.Credentials{AccessKeyID: "new-id", AccessKeySecret: "new-secret"}).Return(newClient, nil)
+			Expect(a.newMachineImageOwnerECSClient(ctx)).To(BeIdenticalTo(newClient))
+		})
+
+		It("should fail if image sharing is not configured", func() {
+			a.machineImageOwnerSecretRef = nil
+
+			_, err := a.newMachineImageOwnerECSClient(ctx)
+			Expect(err).To(MatchError(ContainSubstring("image sharing is not enabled")))
+		})
+	})
+})
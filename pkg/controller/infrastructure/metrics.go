@@ -0,0 +1,66 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package infrastructure
+
+import (
+	"context"
+	"time"
+
+	alicloudclient "github.com/gardener/gardener-extension-provider-alicloud/pkg/alicloud/client"
+
+	"github.com/gardener/gardener/pkg/utils/flow"
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	// PhaseVPC is the reconcile phase creating the VPC.
+	PhaseVPC = "vpc"
+	// PhaseVSwitches is the reconcile phase creating the vswitches.
+	PhaseVSwitches = "vswitches"
+	// PhaseNATGateway is the reconcile phase creating the NAT gateway.
+	PhaseNATGateway = "nat-gateway"
+	// PhaseSecurityGroup is the reconcile phase creating the security group.
+	PhaseSecurityGroup = "security-group"
+	// PhaseTerraform is the reconcile phase applying the Terraform configuration.
+	PhaseTerraform = "terraform"
+)
+
+// ReconcilePhaseDuration is the histogram of the durations of the infrastructure reconcile phases, labeled by phase
+// and result. The durations include all retries of a phase.
+var ReconcilePhaseDuration = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Namespace: "alicloud",
+		Subsystem: "infrastructure",
+		Name:      "reconcile_phase_duration_seconds",
+		Help:      "Duration of the phases of the infrastructure reconciliation.",
+		Buckets:   []float64{1, 5, 10, 30, 60, 120, 300, 600, 1200},
+	},
+	[]string{"phase", "result"},
+)
+
+func init() {
+	metrics.Registry.MustRegister(ReconcilePhaseDuration)
+}
+
+// instrumentPhase returns a TaskFn which records the duration of the given TaskFn as the given phase.
+func instrumentPhase(phase string, fn flow.TaskFn) flow.TaskFn {
+	return func(ctx context.Context) error {
+		start := time.Now()
+		err := fn(ctx)
+		ReconcilePhaseDuration.WithLabelValues(phase, alicloudclient.RequestResult(err)).Observe(time.Since(start).Seconds())
+		return err
+	}
+}
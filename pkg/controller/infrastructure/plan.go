@@ -0,0 +1,454 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/gardener/gardener-extension-provider-alicloud/pkg/alicloud"
+	alicloudv1alpha1 "github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/alicloud/v1alpha1"
+	extensioncontroller "github.com/gardener/gardener-extensions/pkg/controller"
+
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/util/retry"
+)
+
+// AnnotationKeyDryRun is the annotation key on Infrastructure resources that enables the dry-run mode. In dry-run mode
+// the changes which a reconciliation would apply are computed and written to the InfrastructureStatus, but no
+// infrastructure resources are changed.
+const AnnotationKeyDryRun = "alicloud.provider.extensions.gardener.cloud/dry-run"
+
+// IsDryRun checks whether the given Infrastructure should be reconciled in dry-run mode.
+func IsDryRun(infra *extensionsv1alpha1.Infrastructure) bool {
+	return infra.Annotations[AnnotationKeyDryRun] == "true"
+}
+
+type planner struct {
+	changes []alicloudv1alpha1.InfrastructureChange
+}
+
+func (p *planner) add(action alicloudv1alpha1.InfrastructureChangeAction, format string, args ...interface{}) {
+	p.changes = append(p.changes, alicloudv1alpha1.InfrastructureChange{
+		Action:   action,
+		Resource: fmt.Sprintf(format, args...),
+	})
+}
+
+// Plan computes the changes which Reconcile would apply to the infrastructure resources. It only describes the
+// existing resources and neither changes them nor persists the state.
+func (r *flowReconciler) Plan(ctx context.Context) (*alicloudv1alpha1.InfrastructurePlan, error) {
+	p := &planner{}
+
+	for _, fn := range []func(context.Context, *planner) error{
+		r.planVPC,
+		r.planNATGateway,
+		r.planVSwitches,
+		r.planNATVSwitches,
+		r.planPodsVSwitches,
+		r.planZoneRouteTables,
+		r.planRoutes,
+		r.planFlowLog,
+		r.planDHCPOptions,
+		r.planEIPsAndSNATEntries,
+		r.planSecurityGroup,
+		r.planKeyPair,
+	} {
+		if err := fn(ctx, p); err != nil {
+			return nil, err
+		}
+	}
+
+	return &alicloudv1alpha1.InfrastructurePlan{Changes: p.changes}, nil
+}
+
+func (r *flowReconciler) planVPC(_ context.Context, p *planner) error {
+	if !r.isVPCManaged() {
+		vpcID := *r.config.Networks.VPC.ID
+		vpcInfo, err := getExistingVPCInfo(r.vpcClient, r.config)
+		if err != nil {
+			return err
+		}
+
+		r.vpcCIDR = vpcInfo.CIDR
+		r.state.Set(IdentifierVPC, vpcID)
+		r.state.Set(IdentifierRouteTable, vpcInfo.RouteTableID)
+		r.state.Set(IdentifierNATGateway, vpcInfo.NATGatewayID)
+		r.state.Set(IdentifierSNATTable, strings.Split(vpcInfo.SNATTableIDs, ",")[0])
+		return nil
+	}
+
+	r.vpcCIDR = *r.config.Networks.VPC.CIDR
+
+	existing, err := r.describeVPC(r.state.Get(IdentifierVPC))
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		p.add(alicloudv1alpha1.InfrastructureChangeActionCreate, "VPC %s (%s)", r.name("vpc"), r.vpcCIDR)
+		// None of the resources in the VPC can exist without it.
+		r.state.Set(IdentifierVPC, "")
+		r.state.Set(IdentifierRouteTable, "")
+		return nil
+	}
+	r.state.Set(IdentifierRouteTable, vpcRouteTableID(*existing))
+	return nil
+}
+
+func (r *flowReconciler) planNATGateway(_ context.Context, p *planner) error {
+	if !r.isVPCManaged() {
+		return nil
+	}
+
+	if !isNATGatewayPerZone(r.config) {
+		existing, err := r.describeNATGateway(r.state.Get(IdentifierNATGateway))
+		if err != nil {
+			return err
+		}
+		if existing == nil {
+			p.add(alicloudv1alpha1.InfrastructureChangeActionCreate, "NAT gateway %s", r.name("natgw"))
+		}
+		return nil
+	}
+
+	for zoneIndex, zone := range r.config.Networks.Zones {
+		natGatewayIdentifier, _ := r.natGatewayIdentifiers(zoneIndex)
+		existing, err := r.describeNATGateway(r.state.Get(natGatewayIdentifier))
+		if err != nil {
+			return err
+		}
+		if existing == nil {
+			p.add(alicloudv1alpha1.InfrastructureChangeActionCreate, "NAT gateway %s in zone %s", r.name(zone.Name+"-natgw"), zone.Name)
+		}
+	}
+	return nil
+}
+
+func (r *flowReconciler) planNATVSwitches(_ context.Context, p *planner) error {
+	if !isNATGatewayPerZone(r.config) {
+		return nil
+	}
+
+	for zoneIndex, zone := range r.config.Networks.Zones {
+		if zone.NatGatewayCIDR == nil {
+			continue
+		}
+		existing, err := r.describeVSwitch(r.state.Get(ZoneIdentifier(zoneIndex, IdentifierZoneNATVSwitch)))
+		if err != nil {
+			return err
+		}
+		if existing == nil {
+			p.add(alicloudv1alpha1.InfrastructureChangeActionCreate, "vswitch %s (%s) in zone %s", r.name(zone.Name+"-natgw-vsw"), *zone.NatGatewayCIDR, zone.Name)
+		}
+	}
+	return nil
+}
+
+func (r *flowReconciler) planPodsVSwitches(_ context.Context, p *planner) error {
+	for zoneIndex, zone := range r.config.Networks.Zones {
+		if zone.PodsCIDR == nil {
+			continue
+		}
+		identifier := ZoneIdentifier(zoneIndex, IdentifierZonePodsVSwitch)
+
+		existing, err := r.describeVSwitch(r.state.Get(identifier))
+		if err != nil {
+			return err
+		}
+		if existing == nil {
+			p.add(alicloudv1alpha1.InfrastructureChangeActionCreate, "vswitch %s (%s) in zone %s", r.name(podsVSwitchName(zone)), *zone.PodsCIDR, zone.Name)
+			r.state.Set(identifier, "")
+		}
+		if routeTableID := r.config.Networks.RouteTableID; routeTableID != nil && (existing == nil || existing.RouteTable.RouteTableId != *routeTableID) {
+			p.add(alicloudv1alpha1.InfrastructureChangeActionCreate, "association of vswitch %s with route table %s", r.name(podsVSwitchName(zone)), *routeTableID)
+		}
+	}
+	return nil
+}
+
+func (r *flowReconciler) planZoneRouteTables(_ context.Context, p *planner) error {
+	if !isNATGatewayPerZone(r.config) {
+		return nil
+	}
+
+	for zoneIndex, zone := range r.config.Networks.Zones {
+		existing, err := r.describeRouteTable(r.state.Get(ZoneIdentifier(zoneIndex, IdentifierZoneRouteTable)))
+		if err != nil {
+			return err
+		}
+		if existing == nil {
+			p.add(alicloudv1alpha1.InfrastructureChangeActionCreate, "route table %s with a default route to the NAT gateway of zone %s", r.name(zone.Name+"-rt"), zone.Name)
+		}
+	}
+	return nil
+}
+
+func (r *flowReconciler) planVSwitches(_ context.Context, p *planner) error {
+	for zoneIndex, zone := range r.config.Networks.Zones {
+		for vswitchIndex, workersCIDR := range zoneWorkerCIDRs(zone) {
+			identifier := VSwitchIdentifier(zoneIndex, vswitchIndex, IdentifierZoneVSwitch)
+
+			existing, err := r.describeVSwitch(r.state.Get(identifier))
+			if err != nil {
+				return err
+			}
+			if existing == nil {
+				p.add(alicloudv1alpha1.InfrastructureChangeActionCreate, "vswitch %s (%s) in zone %s", r.name(vswitchName(zone, vswitchIndex)), workersCIDR, zone.Name)
+				r.state.Set(identifier, "")
+			}
+			if routeTableID := r.config.Networks.RouteTableID; routeTableID != nil && (existing == nil || existing.RouteTable.RouteTableId != *routeTableID) {
+				p.add(alicloudv1alpha1.InfrastructureChangeActionCreate, "association of vswitch %s with route table %s", r.name(vswitchName(zone, vswitchIndex)), *routeTableID)
+			}
+		}
+	}
+	return nil
+}
+
+func (r *flowReconciler) planZoneEIPs(p *planner, zoneIndex int, zone alicloudv1alpha1.Zone) error {
+	if allocationID := eipAllocationID(r.config); allocationID != "" {
+		eip, err := r.describeEIP(allocationID)
+		if err != nil {
+			return err
+		}
+
+		// The user-owned EIP is shared by the vswitches of all zones, hence its association is only planned once.
+		switch {
+		case eip == nil:
+			return fmt.Errorf("EIP %s does not exist", allocationID)
+		case eip.Status == statusAvailable && zoneIndex == 0:
+			p.add(alicloudv1alpha1.InfrastructureChangeActionUpdate, "association of EIP %s with the NAT gateway", eip.AllocationId)
+		}
+		return nil
+	}
+
+	for eipIndex := 0; eipIndex < eipCount(r.config); eipIndex++ {
+		eip, err := r.describeEIP(r.state.Get(EIPIdentifier(zoneIndex, eipIndex, IdentifierZoneEIP)))
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case eip == nil:
+			p.add(alicloudv1alpha1.InfrastructureChangeActionCreate, "EIP of zone %s", zone.Name)
+		case eip.Status == statusAvailable:
+			p.add(alicloudv1alpha1.InfrastructureChangeActionUpdate, "association of EIP %s with the NAT gateway", eip.AllocationId)
+		}
+	}
+	return nil
+}
+
+func (r *flowReconciler) planEIPsAndSNATEntries(_ context.Context, p *planner) error {
+	for zoneIndex, zone := range r.config.Networks.Zones {
+		_, snatTableIdentifier := r.natGatewayIdentifiers(zoneIndex)
+		if err := r.planZoneEIPs(p, zoneIndex, zone); err != nil {
+			return err
+		}
+
+		for _, identifiers := range zoneNATVSwitchIdentifiers(zoneIndex, zone) {
+			snatEntryID := r.state.Get(identifiers.snatEntry)
+			// SNAT entries of vswitches which are yet to be created cannot exist.
+			if r.state.Get(identifiers.vswitch) == "" {
+				snatEntryID = ""
+			}
+
+			snatEntry, err := r.describeSNATEntry(r.state.Get(snatTableIdentifier), snatEntryID)
+			if err != nil {
+				return err
+			}
+			if snatEntry == nil {
+				p.add(alicloudv1alpha1.InfrastructureChangeActionCreate, "SNAT entry of vswitch %s", r.name(identifiers.name))
+			}
+		}
+	}
+	return nil
+}
+
+func (r *flowReconciler) planSecurityGroup(ctx context.Context, p *planner) error {
+	if !r.isSecurityGroupManaged() {
+		return nil
+	}
+
+	securityGroupID := r.state.Get(IdentifierSecurityGroup)
+
+	exists := false
+	if securityGroupID != "" && r.state.Get(IdentifierVPC) != "" {
+		var err error
+		if exists, err = r.ecsClient.CheckIfSecurityGroupExists(ctx, securityGroupID); err != nil {
+			return err
+		}
+	}
+
+	if !exists {
+		p.add(alicloudv1alpha1.InfrastructureChangeActionCreate, "security group %s", r.name("sg"))
+		for _, rule := range r.config.Networks.SecurityGroupRules {
+			p.add(alicloudv1alpha1.InfrastructureChangeActionCreate, "security group rule %s", describeSecurityGroupRule(rule))
+		}
+		return nil
+	}
+
+	appliedRules, err := getSecurityGroupRules(r.state)
+	if err != nil {
+		return err
+	}
+	desiredRules := r.config.Networks.SecurityGroupRules
+	keptRules := append(r.baselineSecurityGroupRules(), desiredRules...)
+
+	for _, rule := range appliedRules {
+		if !containsSecurityGroupRule(keptRules, rule) {
+			p.add(alicloudv1alpha1.InfrastructureChangeActionDelete, "security group rule %s", describeSecurityGroupRule(rule))
+		}
+	}
+	for _, rule := range desiredRules {
+		if !containsSecurityGroupRule(appliedRules, rule) {
+			p.add(alicloudv1alpha1.InfrastructureChangeActionCreate, "security group rule %s", describeSecurityGroupRule(rule))
+		}
+	}
+	return nil
+}
+
+func (r *flowReconciler) planRoutes(_ context.Context, p *planner) error {
+	desiredRoutes := r.config.Networks.Routes
+	if r.state.Get(IdentifierRouteTable) == "" {
+		for _, route := range desiredRoutes {
+			p.add(alicloudv1alpha1.InfrastructureChangeActionCreate, "route %s", describeRoute(route))
+		}
+		return nil
+	}
+
+	appliedRoutes, err := getRoutes(r.state)
+	if err != nil {
+		return err
+	}
+	for _, route := range appliedRoutes {
+		if !containsRoute(desiredRoutes, route) {
+			p.add(alicloudv1alpha1.InfrastructureChangeActionDelete, "route %s", describeRoute(route))
+		}
+	}
+	for _, route := range desiredRoutes {
+		existing, err := r.describeRouteEntry(r.state.Get(IdentifierRouteTable), route.DestinationCIDR)
+		if err != nil {
+			return err
+		}
+		if existing == nil {
+			p.add(alicloudv1alpha1.InfrastructureChangeActionCreate, "route %s", describeRoute(route))
+		}
+	}
+	return nil
+}
+
+func (r *flowReconciler) planFlowLog(_ context.Context, p *planner) error {
+	existing, err := r.describeFlowLog(r.state.Get(IdentifierFlowLog))
+	if err != nil {
+		return err
+	}
+
+	target := r.config.Networks.FlowLogTarget
+	desired := r.config.Networks.EnableFlowLogs && target != nil
+	if existing != nil && (!desired || existing.ProjectName != target.ProjectName || existing.LogStoreName != target.LogStoreName) {
+		p.add(alicloudv1alpha1.InfrastructureChangeActionDelete, "flow log %s", existing.FlowLogId)
+		existing = nil
+	}
+	if desired && existing == nil {
+		p.add(alicloudv1alpha1.InfrastructureChangeActionCreate, "flow log %s to logstore %s of SLS project %s", r.name("flow-log"), target.LogStoreName, target.ProjectName)
+	}
+	return nil
+}
+
+func (r *flowReconciler) planDHCPOptions(_ context.Context, p *planner) error {
+	existing, err := r.getDHCPOptionsSet(r.state.Get(IdentifierDHCPOptionsSet))
+	if err != nil {
+		return err
+	}
+
+	options := r.config.Networks.DHCPOptions
+	if options == nil {
+		if existing != nil {
+			p.add(alicloudv1alpha1.InfrastructureChangeActionDelete, "DHCP options set %s", existing.DhcpOptionsSetId)
+		}
+		return nil
+	}
+
+	domainName, domainNameServers := dhcpOptions(options)
+	switch {
+	case existing == nil:
+		p.add(alicloudv1alpha1.InfrastructureChangeActionCreate, "DHCP options set %s with DNS servers %q and domain name %q", r.name("dhcp-options"), domainNameServers, domainName)
+	case existing.DhcpOptions.DomainName != domainName || existing.DhcpOptions.DomainNameServers != domainNameServers:
+		p.add(alicloudv1alpha1.InfrastructureChangeActionUpdate, "DHCP options set %s with DNS servers %q and domain name %q", existing.DhcpOptionsSetId, domainNameServers, domainName)
+	case r.dhcpOptionsSetAssociation(existing) == nil:
+		p.add(alicloudv1alpha1.InfrastructureChangeActionUpdate, "association of DHCP options set %s with VPC %s", existing.DhcpOptionsSetId, r.state.Get(IdentifierVPC))
+	}
+	return nil
+}
+
+func (r *flowReconciler) planKeyPair(ctx context.Context, p *planner) error {
+	keyPairName := r.name("ssh-publickey")
+
+	exists, err := r.ecsClient.CheckIfKeyPairExists(ctx, keyPairName)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		p.add(alicloudv1alpha1.InfrastructureChangeActionCreate, "key pair %s", keyPairName)
+	}
+	return nil
+}
+
+func describeSecurityGroupRule(rule alicloudv1alpha1.SecurityGroupRule) string {
+	return fmt.Sprintf("%s %s %s %s", rule.Direction, rule.Protocol, rule.PortRange, rule.CIDR)
+}
+
+func describeRoute(route alicloudv1alpha1.Route) string {
+	return fmt.Sprintf("%s via %s %s", route.DestinationCIDR, route.NextHopType, route.NextHopID)
+}
+
+// planWithFlow computes the changes which a reconciliation would apply and writes them to the InfrastructureStatus.
+// The status otherwise keeps describing the existing infrastructure and the state is not touched, so that an
+// Infrastructure reconciled with Terraform is not switched to the flow reconciler.
+func (a *actuator) planWithFlow(ctx context.Context, infra *extensionsv1alpha1.Infrastructure, cluster *extensioncontroller.Cluster, config *alicloudv1alpha1.InfrastructureConfig, credentials *alicloud.Credentials) error {
+	reconciler, err := a.newFlowReconciler(ctx, infra, cluster, config, credentials)
+	if err != nil {
+		return err
+	}
+
+	plan, err := reconciler.Plan(ctx)
+	if err != nil {
+		return errors.Wrapf(err, "failed to plan the infrastructure changes")
+	}
+
+	status := &alicloudv1alpha1.InfrastructureStatus{}
+	if providerStatus := infra.Status.ProviderStatus; providerStatus != nil {
+		if _, _, err := a.Decoder().Decode(providerStatus.Raw, nil, status); err != nil {
+			return errors.Wrapf(err, "could not decode infrastructure status")
+		}
+	}
+	status.TypeMeta = StatusTypeMeta
+	status.Plan = plan
+
+	if err := extensioncontroller.TryUpdateStatus(ctx, retry.DefaultBackoff, a.Client(), infra, func() error {
+		infra.Status.ProviderStatus = &runtime.RawExtension{Object: status}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	if len(status.VPC.ID) == 0 {
+		// The infrastructure has never been created, hence the reconciliation must not be reported as successful.
+		return fmt.Errorf("infrastructure has not been created because it is reconciled in dry-run mode (annotation %s)", AnnotationKeyDryRun)
+	}
+	return nil
+}
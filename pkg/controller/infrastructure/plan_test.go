@@ -0,0 +1,138 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package infrastructure
+
+import (
+	"context"
+
+	alicloudv1alpha1 "github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/alicloud/v1alpha1"
+	mockalicloudclient "github.com/gardener/gardener-extension-provider-alicloud/pkg/mock/provider-alicloud/alicloud/client"
+
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/vpc"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+)
+
+var _ = Describe("Plan", func() {
+	var (
+		ctrl      *gomock.Controller
+		vpcClient *mockalicloudclient.MockVPC
+		ecsClient *mockalicloudclient.MockECS
+
+		ctx = context.TODO()
+
+		infra      *extensionsv1alpha1.Infrastructure
+		config     *alicloudv1alpha1.InfrastructureConfig
+		reconciler *flowReconciler
+
+		oldRule = alicloudv1alpha1.SecurityGroupRule{Direction: alicloudv1alpha1.SecurityGroupRuleDirectionIngress, Protocol: "tcp", PortRange: "80/80", CIDR: "10.0.0.0/8"}
+		newRule = alicloudv1alpha1.SecurityGroupRule{Direction: alicloudv1alpha1.SecurityGroupRuleDirectionIngress, Protocol: "tcp", PortRange: "22/22", CIDR: "10.0.0.0/8"}
+	)
+
+	BeforeEach(func() {
+		// The mocks fail the tests on any unexpected call, in particular on calls which would change resources.
+		ctrl = gomock.NewController(GinkgoT())
+		vpcClient = mockalicloudclient.NewMockVPC(ctrl)
+		ecsClient = mockalicloudclient.NewMockECS(ctrl)
+
+		infra = &extensionsv1alpha1.Infrastructure{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "shoot--foo--bar", Name: "infra"},
+		}
+		config = &alicloudv1alpha1.InfrastructureConfig{
+			Networks: alicloudv1alpha1.Networks{
+				VPC:                alicloudv1alpha1.VPC{CIDR: pointer.StringPtr("10.250.0.0/16")},
+				Zones:              []alicloudv1alpha1.Zone{{Name: "cn-beijing-f", Workers: "10.250.0.0/19"}},
+				SecurityGroupRules: []alicloudv1alpha1.SecurityGroupRule{newRule},
+			},
+		}
+
+		var err error
+		reconciler, err = newFlowReconciler(nil, infra, config, vpcClient, ecsClient, nil)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	It("should plan to create all resources of a new infrastructure", func() {
+		ecsClient.EXPECT().CheckIfKeyPairExists(ctx, "shoot--foo--bar-ssh-publickey").Return(false, nil)
+
+		plan, err := reconciler.Plan(ctx)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(plan.Changes).To(Equal([]alicloudv1alpha1.InfrastructureChange{
+			{Action: alicloudv1alpha1.InfrastructureChangeActionCreate, Resource: "VPC shoot--foo--bar-vpc (10.250.0.0/16)"},
+			{Action: alicloudv1alpha1.InfrastructureChangeActionCreate, Resource: "NAT gateway shoot--foo--bar-natgw"},
+			{Action: alicloudv1alpha1.InfrastructureChangeActionCreate, Resource: "vswitch shoot--foo--bar-cn-beijing-f-vsw (10.250.0.0/19) in zone cn-beijing-f"},
+			{Action: alicloudv1alpha1.InfrastructureChangeActionCreate, Resource: "EIP of zone cn-beijing-f"},
+			{Action: alicloudv1alpha1.InfrastructureChangeActionCreate, Resource: "SNAT entry of vswitch shoot--foo--bar-cn-beijing-f-vsw"},
+			{Action: alicloudv1alpha1.InfrastructureChangeActionCreate, Resource: "security group shoot--foo--bar-sg"},
+			{Action: alicloudv1alpha1.InfrastructureChangeActionCreate, Resource: "security group rule ingress tcp 22/22 10.0.0.0/8"},
+			{Action: alicloudv1alpha1.InfrastructureChangeActionCreate, Resource: "key pair shoot--foo--bar-ssh-publickey"},
+		}))
+	})
+
+	It("should only plan the changes of an existing infrastructure", func() {
+		reconciler.state.Set(IdentifierVPC, "vpc-1")
+		reconciler.state.Set(IdentifierNATGateway, "ngw-1")
+		reconciler.state.Set(IdentifierSNATTable, "stb-1")
+		reconciler.state.Set(ZoneIdentifier(0, IdentifierZoneVSwitch), "vsw-1")
+		reconciler.state.Set(ZoneIdentifier(0, IdentifierZoneEIP), "eip-1")
+		reconciler.state.Set(ZoneIdentifier(0, IdentifierZoneSNATEntry), "snat-1")
+		reconciler.state.Set(IdentifierSecurityGroup, "sg-1")
+		Expect(setSecurityGroupRules(reconciler.state, []alicloudv1alpha1.SecurityGroupRule{oldRule})).To(Succeed())
+
+		vpcClient.EXPECT().DescribeVpcs(gomock.Any()).Return(&vpc.DescribeVpcsResponse{
+			Vpcs: vpc.Vpcs{Vpc: []vpc.Vpc{{VpcId: "vpc-1", Status: statusAvailable}}},
+		}, nil)
+		vpcClient.EXPECT().DescribeNatGateways(gomock.Any()).Return(&vpc.DescribeNatGatewaysResponse{
+			NatGateways: vpc.NatGateways{NatGateway: []vpc.NatGateway{{NatGatewayId: "ngw-1", Status: statusAvailable}}},
+		}, nil)
+		vpcClient.EXPECT().DescribeVSwitches(gomock.Any()).Return(&vpc.DescribeVSwitchesResponse{
+			VSwitches: vpc.VSwitches{VSwitch: []vpc.VSwitch{{VSwitchId: "vsw-1", Status: statusAvailable}}},
+		}, nil)
+		vpcClient.EXPECT().DescribeEipAddresses(gomock.Any()).Return(&vpc.DescribeEipAddressesResponse{
+			EipAddresses: vpc.EipAddresses{EipAddress: []vpc.EipAddress{{AllocationId: "eip-1", Status: statusAvailable}}},
+		}, nil)
+		vpcClient.EXPECT().DescribeSnatTableEntries(gomock.Any()).Return(&vpc.DescribeSnatTableEntriesResponse{
+			SnatTableEntries: vpc.SnatTableEntries{SnatTableEntry: []vpc.SnatTableEntry{{SnatEntryId: "snat-1"}}},
+		}, nil)
+		ecsClient.EXPECT().CheckIfSecurityGroupExists(ctx, "sg-1").Return(true, nil)
+		ecsClient.EXPECT().CheckIfKeyPairExists(ctx, "shoot--foo--bar-ssh-publickey").Return(true, nil)
+
+		plan, err := reconciler.Plan(ctx)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(plan.Changes).To(Equal([]alicloudv1alpha1.InfrastructureChange{
+			{Action: alicloudv1alpha1.InfrastructureChangeActionUpdate, Resource: "association of EIP eip-1 with the NAT gateway"},
+			{Action: alicloudv1alpha1.InfrastructureChangeActionDelete, Resource: "security group rule ingress tcp 80/80 10.0.0.0/8"},
+			{Action: alicloudv1alpha1.InfrastructureChangeActionCreate, Resource: "security group rule ingress tcp 22/22 10.0.0.0/8"},
+		}))
+	})
+
+	Describe("#IsDryRun", func() {
+		It("should only be true if the annotation is set", func() {
+			Expect(IsDryRun(infra)).To(BeFalse())
+
+			infra.Annotations = map[string]string{AnnotationKeyDryRun: "true"}
+			Expect(IsDryRun(infra)).To(BeTrue())
+		})
+	})
+})
@@ -0,0 +1,113 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	extensioncontroller "github.com/gardener/gardener-extensions/pkg/controller"
+
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	gardencorev1beta1helper "github.com/gardener/gardener/pkg/apis/core/v1beta1/helper"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"k8s.io/client-go/util/retry"
+)
+
+const (
+	// ConditionTypeQuotaExceeded is the type of the condition of the Infrastructure which reports whether the last
+	// reconciliation failed because a quota of the Alicloud account is exhausted.
+	ConditionTypeQuotaExceeded gardencorev1beta1.ConditionType = "QuotaExceeded"
+
+	// ConditionReasonQuotaExceeded is the reason of the QuotaExceeded condition for exhausted quotas without a
+	// specific reason.
+	ConditionReasonQuotaExceeded = "QuotaExceeded"
+	// ConditionReasonQuotaSufficient is the reason of the QuotaExceeded condition once the infrastructure has been
+	// reconciled successfully again.
+	ConditionReasonQuotaSufficient = "QuotaSufficient"
+)
+
+// quotaReasons maps the (lower case) error codes of the Alicloud API for exhausted quotas to the reasons of the
+// QuotaExceeded condition naming the quota.
+var quotaReasons = map[string]string{
+	"quotaexceeded.vpc":                    "VPCQuotaExceeded",
+	"quotaexceeded.vswitch":                "VSwitchQuotaExceeded",
+	"quotaexceeded.eip":                    "EIPQuotaExceeded",
+	"quotaexceeded.natgateway":             "NATGatewayQuotaExceeded",
+	"quotaexceeded.snatentry":              "SNATEntryQuotaExceeded",
+	"quotaexceeded.routeentry":             "RouteEntryQuotaExceeded",
+	"quotaexceed.securitygroup":            "SecurityGroupQuotaExceeded",
+	"quotaexceed.securitygrouprule":        "SecurityGroupRuleQuotaExceeded",
+	"authorizationlimitexceed":             "SecurityGroupRuleQuotaExceeded",
+	"quotaexceed.keypair":                  "KeyPairQuotaExceeded",
+	"quotaexceeded.commonbandwidthpackage": "BandwidthPackageQuotaExceeded",
+}
+
+// errorCodeRegex matches the error codes in the messages of the errors of the Alicloud SDK, which are also part of the
+// output of Terraform.
+var errorCodeRegex = regexp.MustCompile(`ErrorCode: ([A-Za-z0-9._]+)`)
+
+// ExceededQuota returns the reason of the QuotaExceeded condition and the error code of the Alicloud API if the given
+// error has been caused by an exhausted quota of the Alicloud account.
+func ExceededQuota(err error) (reason, code string, ok bool) {
+	if err == nil {
+		return "", "", false
+	}
+
+	for _, match := range errorCodeRegex.FindAllStringSubmatch(err.Error(), -1) {
+		code := match[1]
+		if reason, ok := quotaReasons[strings.ToLower(code)]; ok {
+			return reason, code, true
+		}
+		if strings.HasPrefix(strings.ToLower(code), "quotaexceed") {
+			return ConditionReasonQuotaExceeded, code, true
+		}
+	}
+	return "", "", false
+}
+
+// QuotaExceededCondition returns the QuotaExceeded condition for the given result of a reconciliation, based on the
+// given conditions of the Infrastructure. The condition is True if the reconciliation failed due to an exhausted
+// quota and becomes False once a reconciliation succeeds. Nil is returned if the condition does not need an update.
+func QuotaExceededCondition(conditions []gardencorev1beta1.Condition, reconcileErr error) *gardencorev1beta1.Condition {
+	if reason, code, ok := ExceededQuota(reconcileErr); ok {
+		condition := gardencorev1beta1helper.UpdatedCondition(gardencorev1beta1helper.GetOrInitCondition(conditions, ConditionTypeQuotaExceeded), gardencorev1beta1.ConditionTrue, reason,
+			fmt.Sprintf("Alicloud rejected the creation of a resource with error code %s as a quota of the account is exhausted, please request a quota increase or release unused resources", code))
+		return &condition
+	}
+
+	existing := gardencorev1beta1helper.GetCondition(conditions, ConditionTypeQuotaExceeded)
+	if reconcileErr != nil || existing == nil || existing.Status == gardencorev1beta1.ConditionFalse {
+		return nil
+	}
+	condition := gardencorev1beta1helper.UpdatedCondition(*existing, gardencorev1beta1.ConditionFalse, ConditionReasonQuotaSufficient, "The infrastructure has been reconciled successfully")
+	return &condition
+}
+
+// updateQuotaExceededCondition updates the QuotaExceeded condition of the given Infrastructure for the given result of
+// a reconciliation.
+func (a *actuator) updateQuotaExceededCondition(ctx context.Context, infra *extensionsv1alpha1.Infrastructure, reconcileErr error) error {
+	condition := QuotaExceededCondition(infra.Status.Conditions, reconcileErr)
+	if condition == nil {
+		return nil
+	}
+
+	return extensioncontroller.TryUpdateStatus(ctx, retry.DefaultBackoff, a.Client(), infra, func() error {
+		infra.Status.Conditions = gardencorev1beta1helper.MergeConditions(infra.Status.Conditions, *condition)
+		return nil
+	})
+}
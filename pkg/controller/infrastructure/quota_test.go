@@ -0,0 +1,85 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package infrastructure_test
+
+import (
+	"fmt"
+	"time"
+
+	. "github.com/gardener/gardener-extension-provider-alicloud/pkg/controller/infrastructure"
+	controllererrors "github.com/gardener/gardener-extensions/pkg/controller/error"
+
+	sdkerrors "github.com/aliyun/alibaba-cloud-sdk-go/sdk/errors"
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+)
+
+var _ = Describe("Quota", func() {
+	serverError := func(code string) error {
+		return sdkerrors.NewServerError(400, fmt.Sprintf(`{"Code":%q,"Message":"The quota is exceeded.","RequestId":"0ED8D006-F706-4D23-88ED-E11ED28DCAC0"}`, code), "")
+	}
+
+	DescribeTable("#ExceededQuota",
+		func(err error, expectedReason, expectedCode string, expectedOK bool) {
+			reason, code, ok := ExceededQuota(err)
+			Expect(ok).To(Equal(expectedOK))
+			Expect(reason).To(Equal(expectedReason))
+			Expect(code).To(Equal(expectedCode))
+		},
+
+		Entry("no error", nil, "", "", false),
+		Entry("other error", serverError("InvalidVpcId.NotFound"), "", "", false),
+		Entry("VPC quota", serverError("QuotaExceeded.Vpc"), "VPCQuotaExceeded", "QuotaExceeded.Vpc", true),
+		Entry("vswitch quota", serverError("QuotaExceeded.VSwitch"), "VSwitchQuotaExceeded", "QuotaExceeded.VSwitch", true),
+		Entry("EIP quota", serverError("QuotaExceeded.Eip"), "EIPQuotaExceeded", "QuotaExceeded.Eip", true),
+		Entry("NAT gateway quota", serverError("QuotaExceeded.NatGateway"), "NATGatewayQuotaExceeded", "QuotaExceeded.NatGateway", true),
+		Entry("security group quota", serverError("QuotaExceed.SecurityGroup"), "SecurityGroupQuotaExceeded", "QuotaExceed.SecurityGroup", true),
+		Entry("security group rule quota", serverError("AuthorizationLimitExceed"), "SecurityGroupRuleQuotaExceeded", "AuthorizationLimitExceed", true),
+		Entry("unknown quota", serverError("QuotaExceeded.Foo"), "QuotaExceeded", "QuotaExceeded.Foo", true),
+		Entry("wrapped error of the flow reconciler", &controllererrors.RequeueAfterError{Cause: errors.Wrap(serverError("QuotaExceeded.Eip"), "failed to create EIP"), RequeueAfter: 30 * time.Second}, "EIPQuotaExceeded", "QuotaExceeded.Eip", true),
+		Entry("output of Terraform", errors.New("Error: [ERROR] terraform-provider-alicloud/alicloud/resource_alicloud_vpc.go:125: Resource alicloud_vpc CreateVpc Failed!!! [SDK alibaba-cloud-sdk-go ERROR]:\nSDK.ServerError\nErrorCode: QuotaExceeded.Vpc\nRecommend: \nRequestId: 0ED8D006\nMessage: VPC quota exceeded."), "VPCQuotaExceeded", "QuotaExceeded.Vpc", true),
+	)
+
+	Describe("#QuotaExceededCondition", func() {
+		It("should set the condition with the reason of the exhausted quota", func() {
+			condition := QuotaExceededCondition(nil, serverError("QuotaExceeded.Eip"))
+
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Type).To(Equal(ConditionTypeQuotaExceeded))
+			Expect(condition.Status).To(Equal(gardencorev1beta1.ConditionTrue))
+			Expect(condition.Reason).To(Equal("EIPQuotaExceeded"))
+			Expect(condition.Message).To(ContainSubstring("QuotaExceeded.Eip"))
+		})
+
+		It("should reset the condition once a reconciliation succeeds", func() {
+			conditions := []gardencorev1beta1.Condition{*QuotaExceededCondition(nil, serverError("QuotaExceeded.Eip"))}
+
+			Expect(QuotaExceededCondition(conditions, errors.New("foo"))).To(BeNil())
+
+			condition := QuotaExceededCondition(conditions, nil)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(gardencorev1beta1.ConditionFalse))
+			Expect(condition.Reason).To(Equal(ConditionReasonQuotaSufficient))
+		})
+
+		It("should not add the condition if no quota has been exhausted", func() {
+			Expect(QuotaExceededCondition(nil, nil)).To(BeNil())
+			Expect(QuotaExceededCondition(nil, errors.New("foo"))).To(BeNil())
+		})
+	})
+})
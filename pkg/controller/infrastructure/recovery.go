@@ -0,0 +1,85 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package infrastructure
+
+import (
+	"context"
+	"strings"
+
+	"github.com/aliyun/alibaba-cloud-sdk-go/sdk/errors"
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/vpc"
+)
+
+// isNotFoundError checks whether the given error is returned by Alicloud for a resource which does not exist (anymore).
+func isNotFoundError(err error) bool {
+	sdkErr, ok := err.(errors.Error)
+	return ok && strings.HasSuffix(sdkErr.ErrorCode(), ".NotFound")
+}
+
+// ignoreNotFoundError returns nil for errors returned by Alicloud for a resource which does not exist (anymore), e.g.
+// because it has been deleted concurrently, and the given error otherwise.
+func ignoreNotFoundError(err error) error {
+	if isNotFoundError(err) {
+		return nil
+	}
+	return err
+}
+
+// recoverVPC records the managed VPC in the state if its ID is not recorded yet but a VPC with its name and CIDR
+// exists. The flow reconciler records the ID of every resource right after its creation, so that a deletion can clean
+// up whatever exists even if the reconciliation failed halfway. Only resources whose creation succeeded but whose ID
+// could not be recorded anymore, e.g. because the extension was restarted in between, are recovered by their names
+// before they are created again or deleted.
+func (r *flowReconciler) recoverVPC(ctx context.Context) error {
+	if !r.isVPCManaged() || r.state.Get(IdentifierVPC) != "" {
+		return nil
+	}
+
+	req := vpc.CreateDescribeVpcsRequest()
+	req.VpcName = r.name("vpc")
+	res, err := r.vpcClient.DescribeVpcs(req)
+	if err != nil {
+		return err
+	}
+	for _, existing := range res.Vpcs.Vpc {
+		if existing.VpcName == req.VpcName && existing.CidrBlock == *r.config.Networks.VPC.CIDR {
+			return r.setAndPersist(ctx, IdentifierVPC, existing.VpcId)
+		}
+	}
+	return nil
+}
+
+// recoverNATGateway records the NAT gateway with the given name in the state under the given identifier if its ID is
+// not recorded yet but a NAT gateway with the name exists in the VPC.
+func (r *flowReconciler) recoverNATGateway(ctx context.Context, natGatewayIdentifier, name string) error {
+	vpcID := r.state.Get(IdentifierVPC)
+	if vpcID == "" || r.state.Get(natGatewayIdentifier) != "" {
+		return nil
+	}
+
+	req := vpc.CreateDescribeNatGatewaysRequest()
+	req.VpcId = vpcID
+	req.Name = name
+	res, err := r.vpcClient.DescribeNatGateways(req)
+	if err != nil {
+		return err
+	}
+	for _, existing := range res.NatGateways.NatGateway {
+		if existing.Name == name {
+			return r.setAndPersist(ctx, natGatewayIdentifier, existing.NatGatewayId)
+		}
+	}
+	return nil
+}
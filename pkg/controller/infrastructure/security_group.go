@@ -0,0 +1,224 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/gardener/gardener-extension-provider-alicloud/pkg/alicloud"
+	alicloudv1alpha1 "github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/alicloud/v1alpha1"
+
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/ecs"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// AnnotationKeySuspendSecurityGroupDriftCorrection is the annotation key on Infrastructure resources which suspends the
+// correction of drifted rules of the managed security group while its value is "true", e.g. as long as manual changes
+// made during an incident have to persist.
+const AnnotationKeySuspendSecurityGroupDriftCorrection = "alicloud.provider.extensions.gardener.cloud/suspend-security-group-drift-correction"
+
+// checkSecurityGroup checks that the existing security group of the InfrastructureConfig, if any, exists in the VPC of
+// the infrastructure. As its rules are not managed, a warning event is emitted if it lacks any of the rules the nodes
+// need for the traffic within the cluster.
+func (a *actuator) checkSecurityGroup(ctx context.Context, infra *extensionsv1alpha1.Infrastructure, config *alicloudv1alpha1.InfrastructureConfig, credentials *alicloud.Credentials, vpcID string) error {
+	if config.Networks.SecurityGroupID == nil {
+		return nil
+	}
+	securityGroupID := *config.Networks.SecurityGroupID
+
+	ecsClient, err := a.newClientFactory.NewECSClient(ctx, infra.Spec.Region, credentials)
+	if err != nil {
+		return err
+	}
+
+	securityGroup, err := ecsClient.GetSecurityGroup(ctx, securityGroupID)
+	if err != nil {
+		return err
+	}
+	if securityGroup == nil {
+		return fmt.Errorf("security group %s does not exist", securityGroupID)
+	}
+	if securityGroup.VpcId != vpcID {
+		return fmt.Errorf("security group %s belongs to VPC %s instead of the VPC %s of the shoot", securityGroupID, securityGroup.VpcId, vpcID)
+	}
+
+	vpcClient, err := a.alicloudClientFactory.NewVPC(ctx, infra.Spec.Region, credentials)
+	if err != nil {
+		return err
+	}
+	vpcCIDR, err := getVPCCIDR(vpcClient, vpcID)
+	if err != nil {
+		return err
+	}
+
+	permissions, err := ecsClient.GetSecurityGroupRules(ctx, securityGroupID)
+	if err != nil {
+		return err
+	}
+
+	var missingRules []string
+	for _, rule := range baselineSecurityGroupRules(vpcCIDR) {
+		if !containsPermission(permissions, rule) {
+			missingRules = append(missingRules, describeSecurityGroupRule(rule))
+		}
+	}
+	if len(missingRules) > 0 {
+		a.logger.Info("Existing security group lacks rules needed within the cluster", "infrastructure", infra.Name, "securityGroup", securityGroupID, "missingRules", missingRules)
+		a.recorder.Eventf(infra, corev1.EventTypeWarning, EventReasonSecurityGroupRulesMissing, "Security group %s lacks the rules %s", securityGroupID, strings.Join(missingRules, ", "))
+	}
+
+	return nil
+}
+
+// correctSecurityGroupDrift restores the rules of the security group which is managed for the nodes of the given
+// status, e.g. after manual changes during an incident. Missing rules are authorized again and rules which were added
+// outside of the InfrastructureConfig are revoked, an event lists the corrected rules. The correction is skipped while
+// the Infrastructure has the AnnotationKeySuspendSecurityGroupDriftCorrection annotation.
+func (a *actuator) correctSecurityGroupDrift(ctx context.Context, infra *extensionsv1alpha1.Infrastructure, config *alicloudv1alpha1.InfrastructureConfig, credentials *alicloud.Credentials, status *alicloudv1alpha1.InfrastructureStatus) error {
+	if config.Networks.SecurityGroupID != nil {
+		return nil
+	}
+	securityGroupID := nodesSecurityGroupID(status)
+	if securityGroupID == "" {
+		return nil
+	}
+	if IsSecurityGroupDriftCorrectionSuspended(infra) {
+		a.logger.Info("Correction of drifted security group rules is suspended", "infrastructure", infra.Name, "securityGroup", securityGroupID)
+		return nil
+	}
+
+	var vpcCIDR string
+	if config.Networks.VPC.CIDR != nil {
+		vpcCIDR = *config.Networks.VPC.CIDR
+	} else {
+		vpcClient, err := a.alicloudClientFactory.NewVPC(ctx, infra.Spec.Region, credentials)
+		if err != nil {
+			return err
+		}
+		if vpcCIDR, err = getVPCCIDR(vpcClient, status.VPC.ID); err != nil {
+			return err
+		}
+	}
+
+	ecsClient, err := a.newClientFactory.NewECSClient(ctx, infra.Spec.Region, credentials)
+	if err != nil {
+		return err
+	}
+
+	permissions, err := ecsClient.GetSecurityGroupRules(ctx, securityGroupID)
+	if err != nil {
+		return err
+	}
+
+	desiredRules := append(baselineSecurityGroupRules(vpcCIDR), config.Networks.SecurityGroupRules...)
+	var correctedRules []string
+	for _, permission := range permissions {
+		if matchesAnyRule(permission, desiredRules) {
+			continue
+		}
+		if err := ecsClient.RevokeSecurityGroupPermission(ctx, securityGroupID, permission); err != nil {
+			return err
+		}
+		correctedRules = append(correctedRules, "revoked "+describePermission(permission))
+	}
+	for _, rule := range desiredRules {
+		if containsPermission(permissions, rule) {
+			continue
+		}
+		if rule.Direction == alicloudv1alpha1.SecurityGroupRuleDirectionEgress {
+			err = ecsClient.AuthorizeSecurityGroupEgress(ctx, securityGroupID, rule.Protocol, rule.PortRange, rule.CIDR)
+		} else {
+			err = ecsClient.AuthorizeSecurityGroupIngress(ctx, securityGroupID, rule.Protocol, rule.PortRange, rule.CIDR)
+		}
+		if err != nil {
+			return err
+		}
+		correctedRules = append(correctedRules, "authorized "+describeSecurityGroupRule(rule))
+	}
+
+	if len(correctedRules) > 0 {
+		a.logger.Info("Corrected drifted security group rules", "infrastructure", infra.Name, "securityGroup", securityGroupID, "correctedRules", correctedRules)
+		a.recorder.Eventf(infra, corev1.EventTypeWarning, EventReasonSecurityGroupDriftCorrected, "Corrected drifted rules of security group %s: %s", securityGroupID, strings.Join(correctedRules, ", "))
+	}
+	return nil
+}
+
+// IsSecurityGroupDriftCorrectionSuspended checks whether the correction of drifted rules of the managed security group
+// is suspended for the given Infrastructure.
+func IsSecurityGroupDriftCorrectionSuspended(infra *extensionsv1alpha1.Infrastructure) bool {
+	return infra.Annotations[AnnotationKeySuspendSecurityGroupDriftCorrection] == "true"
+}
+
+// nodesSecurityGroupID returns the ID of the security group of the nodes in the given status.
+func nodesSecurityGroupID(status *alicloudv1alpha1.InfrastructureStatus) string {
+	for _, securityGroup := range status.VPC.SecurityGroups {
+		if securityGroup.Purpose == alicloudv1alpha1.PurposeNodes {
+			return securityGroup.ID
+		}
+	}
+	return ""
+}
+
+// containsPermission checks whether the given permissions of a security group contain an accepting rule equal to the
+// given one.
+func containsPermission(permissions []ecs.Permission, rule alicloudv1alpha1.SecurityGroupRule) bool {
+	for _, permission := range permissions {
+		if permissionMatchesRule(permission, rule) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesAnyRule checks whether the given permission of a security group is an accepting rule equal to one of the
+// given ones.
+func matchesAnyRule(permission ecs.Permission, rules []alicloudv1alpha1.SecurityGroupRule) bool {
+	for _, rule := range rules {
+		if permissionMatchesRule(permission, rule) {
+			return true
+		}
+	}
+	return false
+}
+
+func permissionMatchesRule(permission ecs.Permission, rule alicloudv1alpha1.SecurityGroupRule) bool {
+	if permission.Policy != "" && !strings.EqualFold(permission.Policy, "accept") {
+		return false
+	}
+	if !strings.EqualFold(permission.Direction, string(rule.Direction)) || !strings.EqualFold(permission.IpProtocol, rule.Protocol) || permission.PortRange != rule.PortRange {
+		return false
+	}
+	if rule.Direction == alicloudv1alpha1.SecurityGroupRuleDirectionEgress {
+		return permission.DestCidrIp == rule.CIDR
+	}
+	return permission.SourceCidrIp == rule.CIDR
+}
+
+// describePermission returns a description of the given permission of a security group in the format of
+// describeSecurityGroupRule, followed by its policy if the permission does not accept traffic.
+func describePermission(permission ecs.Permission) string {
+	peer := permission.SourceCidrIp + permission.Ipv6SourceCidrIp + permission.SourceGroupId
+	if strings.EqualFold(permission.Direction, "egress") {
+		peer = permission.DestCidrIp + permission.Ipv6DestCidrIp + permission.DestGroupId
+	}
+	description := fmt.Sprintf("%s %s %s %s", strings.ToLower(permission.Direction), strings.ToLower(permission.IpProtocol), permission.PortRange, peer)
+	if permission.Policy != "" && !strings.EqualFold(permission.Policy, "accept") {
+		description += " " + strings.ToLower(permission.Policy)
+	}
+	return description
+}
@@ -0,0 +1,205 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package infrastructure
+
+import (
+	"context"
+
+	"github.com/gardener/gardener-extension-provider-alicloud/pkg/alicloud"
+	alicloudv1alpha1 "github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/alicloud/v1alpha1"
+	mockalicloudclient "github.com/gardener/gardener-extension-provider-alicloud/pkg/mock/provider-alicloud/alicloud/client"
+
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/ecs"
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/vpc"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var _ = Describe("Security group", func() {
+	var (
+		ctrl      *gomock.Controller
+		ecsClient *mockalicloudclient.MockECS
+		vpcClient *mockalicloudclient.MockVPC
+		recorder  *record.FakeRecorder
+
+		ctx         = context.TODO()
+		credentials = &alicloud.Credentials{AccessKeyID: "id", AccessKeySecret: "secret"}
+		infra       = &extensionsv1alpha1.Infrastructure{Spec: extensionsv1alpha1.InfrastructureSpec{Region: "cn-beijing"}}
+
+		config *alicloudv1alpha1.InfrastructureConfig
+		a      *actuator
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		ecsClient = mockalicloudclient.NewMockECS(ctrl)
+		vpcClient = mockalicloudclient.NewMockVPC(ctrl)
+		recorder = record.NewFakeRecorder(10)
+
+		newClientFactory := mockalicloudclient.NewMockClientFactory(ctrl)
+		newClientFactory.EXPECT().NewECSClient(ctx, "cn-beijing", credentials).Return(ecsClient, nil).AnyTimes()
+		alicloudClientFactory := mockalicloudclient.NewMockFactory(ctrl)
+		alicloudClientFactory.EXPECT().NewVPC(ctx, "cn-beijing", credentials).Return(vpcClient, nil).AnyTimes()
+
+		config = &alicloudv1alpha1.InfrastructureConfig{
+			Networks: alicloudv1alpha1.Networks{
+				VPC:             alicloudv1alpha1.VPC{ID: pointer.StringPtr("vpc-1")},
+				SecurityGroupID: pointer.StringPtr("sg-1"),
+			},
+		}
+		a = &actuator{
+			logger:                log.Log,
+			recorder:              recorder,
+			newClientFactory:      newClientFactory,
+			alicloudClientFactory: alicloudClientFactory,
+		}
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	describeVPC := func() {
+		vpcClient.EXPECT().DescribeVpcs(gomock.Any()).DoAndReturn(func(req *vpc.DescribeVpcsRequest) (*vpc.DescribeVpcsResponse, error) {
+			Expect(req.VpcId).To(Equal("vpc-1"))
+			return &vpc.DescribeVpcsResponse{Vpcs: vpc.Vpcs{Vpc: []vpc.Vpc{{VpcId: "vpc-1", CidrBlock: "10.250.0.0/16"}}}}, nil
+		})
+	}
+
+	Describe("#checkSecurityGroup", func() {
+		It("should do nothing if no existing security group is used", func() {
+			config.Networks.SecurityGroupID = nil
+
+			Expect(a.checkSecurityGroup(ctx, infra, config, credentials, "vpc-1")).To(Succeed())
+		})
+
+		It("should accept a security group with the rules needed within the cluster", func() {
+			ecsClient.EXPECT().GetSecurityGroup(ctx, "sg-1").Return(&ecs.SecurityGroup{SecurityGroupId: "sg-1", VpcId: "vpc-1"}, nil)
+			describeVPC()
+			ecsClient.EXPECT().GetSecurityGroupRules(ctx, "sg-1").Return([]ecs.Permission{
+				{Direction: "ingress", IpProtocol: "TCP", PortRange: "30000/32767", SourceCidrIp: "0.0.0.0/0", Policy: "Accept"},
+				{Direction: "ingress", IpProtocol: "TCP", PortRange: "1/65535", SourceCidrIp: "10.250.0.0/16", Policy: "Accept"},
+				{Direction: "ingress", IpProtocol: "UDP", PortRange: "1/65535", SourceCidrIp: "10.250.0.0/16", Policy: "Accept"},
+			}, nil)
+
+			Expect(a.checkSecurityGroup(ctx, infra, config, credentials, "vpc-1")).To(Succeed())
+			Expect(recorder.Events).To(BeEmpty())
+		})
+
+		It("should warn about missing rules", func() {
+			ecsClient.EXPECT().GetSecurityGroup(ctx, "sg-1").Return(&ecs.SecurityGroup{SecurityGroupId: "sg-1", VpcId: "vpc-1"}, nil)
+			describeVPC()
+			ecsClient.EXPECT().GetSecurityGroupRules(ctx, "sg-1").Return([]ecs.Permission{
+				{Direction: "ingress", IpProtocol: "TCP", PortRange: "1/65535", SourceCidrIp: "10.250.0.0/16", Policy: "Accept"},
+				{Direction: "ingress", IpProtocol: "UDP", PortRange: "1/65535", SourceCidrIp: "10.250.0.0/16", Policy: "Drop"},
+			}, nil)
+
+			Expect(a.checkSecurityGroup(ctx, infra, config, credentials, "vpc-1")).To(Succeed())
+			Expect(recorder.Events).To(Receive(Equal("Warning SecurityGroupRulesMissing Security group sg-1 lacks the rules ingress tcp 30000/32767 0.0.0.0/0, ingress udp 1/65535 10.250.0.0/16")))
+		})
+
+		It("should fail if the security group does not exist", func() {
+			ecsClient.EXPECT().GetSecurityGroup(ctx, "sg-1").Return(nil, nil)
+
+			Expect(a.checkSecurityGroup(ctx, infra, config, credentials, "vpc-1")).To(MatchError("security group sg-1 does not exist"))
+		})
+
+		It("should fail if the security group belongs to another VPC", func() {
+			ecsClient.EXPECT().GetSecurityGroup(ctx, "sg-1").Return(&ecs.SecurityGroup{SecurityGroupId: "sg-1", VpcId: "vpc-2"}, nil)
+
+			Expect(a.checkSecurityGroup(ctx, infra, config, credentials, "vpc-1")).To(MatchError("security group sg-1 belongs to VPC vpc-2 instead of the VPC vpc-1 of the shoot"))
+		})
+	})
+
+	Describe("#correctSecurityGroupDrift", func() {
+		var (
+			status          *alicloudv1alpha1.InfrastructureStatus
+			baselineRules   []ecs.Permission
+			infraWithStatus *extensionsv1alpha1.Infrastructure
+		)
+
+		BeforeEach(func() {
+			config.Networks.SecurityGroupID = nil
+			config.Networks.SecurityGroupRules = []alicloudv1alpha1.SecurityGroupRule{
+				{Direction: alicloudv1alpha1.SecurityGroupRuleDirectionEgress, Protocol: "tcp", PortRange: "443/443", CIDR: "203.0.113.0/24"},
+			}
+			status = &alicloudv1alpha1.InfrastructureStatus{
+				VPC: alicloudv1alpha1.VPCStatus{
+					ID:             "vpc-1",
+					SecurityGroups: []alicloudv1alpha1.SecurityGroup{{Purpose: alicloudv1alpha1.PurposeNodes, ID: "sg-1"}},
+				},
+			}
+			baselineRules = []ecs.Permission{
+				{Direction: "ingress", IpProtocol: "TCP", PortRange: "30000/32767", SourceCidrIp: "0.0.0.0/0", Policy: "Accept", Priority: "1"},
+				{Direction: "ingress", IpProtocol: "TCP", PortRange: "1/65535", SourceCidrIp: "10.250.0.0/16", Policy: "Accept", Priority: "1"},
+				{Direction: "ingress", IpProtocol: "UDP", PortRange: "1/65535", SourceCidrIp: "10.250.0.0/16", Policy: "Accept", Priority: "1"},
+				{Direction: "egress", IpProtocol: "TCP", PortRange: "443/443", DestCidrIp: "203.0.113.0/24", Policy: "Accept", Priority: "1"},
+			}
+			infraWithStatus = infra.DeepCopy()
+		})
+
+		It("should not change the rules of a security group without drift", func() {
+			describeVPC()
+			ecsClient.EXPECT().GetSecurityGroupRules(ctx, "sg-1").Return(baselineRules, nil)
+
+			Expect(a.correctSecurityGroupDrift(ctx, infraWithStatus, config, credentials, status)).To(Succeed())
+			Expect(recorder.Events).To(BeEmpty())
+		})
+
+		It("should restore missing rules and revoke rules added outside of the InfrastructureConfig", func() {
+			addedRule := ecs.Permission{Direction: "ingress", IpProtocol: "TCP", PortRange: "22/22", SourceCidrIp: "0.0.0.0/0", Policy: "Accept", Priority: "1"}
+			droppingRule := ecs.Permission{Direction: "ingress", IpProtocol: "UDP", PortRange: "1/65535", SourceCidrIp: "10.250.0.0/16", Policy: "Drop", Priority: "1"}
+
+			describeVPC()
+			ecsClient.EXPECT().GetSecurityGroupRules(ctx, "sg-1").Return([]ecs.Permission{
+				baselineRules[0], baselineRules[1], addedRule, droppingRule,
+			}, nil)
+			ecsClient.EXPECT().RevokeSecurityGroupPermission(ctx, "sg-1", addedRule)
+			ecsClient.EXPECT().RevokeSecurityGroupPermission(ctx, "sg-1", droppingRule)
+			ecsClient.EXPECT().AuthorizeSecurityGroupIngress(ctx, "sg-1", "udp", "1/65535", "10.250.0.0/16")
+			ecsClient.EXPECT().AuthorizeSecurityGroupEgress(ctx, "sg-1", "tcp", "443/443", "203.0.113.0/24")
+
+			Expect(a.correctSecurityGroupDrift(ctx, infraWithStatus, config, credentials, status)).To(Succeed())
+			Expect(recorder.Events).To(Receive(Equal("Warning SecurityGroupDriftCorrected Corrected drifted rules of security group sg-1: " +
+				"revoked ingress tcp 22/22 0.0.0.0/0, revoked ingress udp 1/65535 10.250.0.0/16 drop, " +
+				"authorized ingress udp 1/65535 10.250.0.0/16, authorized egress tcp 443/443 203.0.113.0/24")))
+		})
+
+		It("should use the CIDR of a new VPC without looking it up", func() {
+			config.Networks.VPC = alicloudv1alpha1.VPC{CIDR: pointer.StringPtr("10.250.0.0/16")}
+			ecsClient.EXPECT().GetSecurityGroupRules(ctx, "sg-1").Return(baselineRules, nil)
+
+			Expect(a.correctSecurityGroupDrift(ctx, infraWithStatus, config, credentials, status)).To(Succeed())
+		})
+
+		It("should not correct the rules while the correction is suspended", func() {
+			infraWithStatus.Annotations = map[string]string{AnnotationKeySuspendSecurityGroupDriftCorrection: "true"}
+
+			Expect(a.correctSecurityGroupDrift(ctx, infraWithStatus, config, credentials, status)).To(Succeed())
+			Expect(recorder.Events).To(BeEmpty())
+		})
+
+		It("should not correct the rules of an existing security group", func() {
+			config.Networks.SecurityGroupID = pointer.StringPtr("sg-1")
+
+			Expect(a.correctSecurityGroupDrift(ctx, infraWithStatus, config, credentials, status)).To(Succeed())
+		})
+	})
+})
@@ -0,0 +1,136 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package infrastructure
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/gardener/gardener-extension-provider-alicloud/pkg/alicloud"
+	alicloudv1alpha1 "github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/alicloud/v1alpha1"
+	extensioncontroller "github.com/gardener/gardener-extensions/pkg/controller"
+
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/util/retry"
+)
+
+const (
+	// AnnotationKeySpecHash is the annotation key on Infrastructure resources that contains the hash of the inputs of
+	// the last successful reconciliation. It is maintained by the infrastructure controller.
+	AnnotationKeySpecHash = "alicloud.provider.extensions.gardener.cloud/spec-hash"
+	// AnnotationKeyForceReconcile is the annotation key on Infrastructure resources that enforces a full reconciliation
+	// even if the inputs are unchanged. The annotation is removed after the next successful reconciliation.
+	AnnotationKeyForceReconcile = "alicloud.provider.extensions.gardener.cloud/force-reconcile"
+)
+
+// ComputeSpecHash computes the hash of all inputs of the reconciliation of the given Infrastructure, i.e., its spec
+// with the decoded InfrastructureConfig, the credentials, and the worker pools and cloud profile of the shoot which
+// determine the shared machine images and the checked security groups.
+func ComputeSpecHash(infra *extensionsv1alpha1.Infrastructure, config *alicloudv1alpha1.InfrastructureConfig, credentials *alicloud.Credentials, cluster *extensioncontroller.Cluster) (string, error) {
+	input := struct {
+		Region             string                                 `json:"region"`
+		SecretRef          corev1.SecretReference                 `json:"secretRef"`
+		SSHPublicKey       []byte                                 `json:"sshPublicKey,omitempty"`
+		Config             *alicloudv1alpha1.InfrastructureConfig `json:"config"`
+		Credentials        *alicloud.Credentials                  `json:"credentials"`
+		Workers            []gardencorev1beta1.Worker             `json:"workers,omitempty"`
+		CloudProfileConfig []byte                                 `json:"cloudProfileConfig,omitempty"`
+	}{
+		Region:       infra.Spec.Region,
+		SecretRef:    infra.Spec.SecretRef,
+		SSHPublicKey: infra.Spec.SSHPublicKey,
+		Config:       config,
+		Credentials:  credentials,
+	}
+	if cluster != nil && cluster.Shoot != nil {
+		input.Workers = cluster.Shoot.Spec.Provider.Workers
+	}
+	if cluster != nil && cluster.CloudProfile != nil && cluster.CloudProfile.Spec.ProviderConfig != nil {
+		input.CloudProfileConfig = cluster.CloudProfile.Spec.ProviderConfig.Raw
+	}
+
+	data, err := json.Marshal(input)
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:]), nil
+}
+
+// ShouldSkipReconcile checks whether the full reconciliation of the given Infrastructure can be skipped, because the
+// inputs with the given hash have been reconciled successfully before and the reconciliation is not enforced.
+func ShouldSkipReconcile(infra *extensionsv1alpha1.Infrastructure, specHash string) bool {
+	return infra.Annotations[AnnotationKeyForceReconcile] != "true" &&
+		infra.Annotations[AnnotationKeySpecHash] == specHash &&
+		infra.Status.LastError == nil &&
+		infra.Status.ProviderStatus != nil
+}
+
+// persistSpecHash records the given hash of the successfully reconciled inputs and removes the annotation which
+// enforced the reconciliation.
+func (a *actuator) persistSpecHash(ctx context.Context, infra *extensionsv1alpha1.Infrastructure, specHash string) error {
+	return extensioncontroller.TryUpdate(ctx, retry.DefaultBackoff, a.Client(), infra, func() error {
+		if infra.Annotations == nil {
+			infra.Annotations = map[string]string{}
+		}
+		infra.Annotations[AnnotationKeySpecHash] = specHash
+		delete(infra.Annotations, AnnotationKeyForceReconcile)
+		return nil
+	})
+}
+
+// refreshStatus computes the InfrastructureStatus from the persisted state without calling the Alicloud APIs. The
+// machine images of the current status are kept, as they are only determined by sharing the images.
+func (a *actuator) refreshStatus(ctx context.Context, infra *extensionsv1alpha1.Infrastructure, config *alicloudv1alpha1.InfrastructureConfig, credentials *alicloud.Credentials) error {
+	current := &alicloudv1alpha1.InfrastructureStatus{}
+	if _, _, err := a.Decoder().Decode(infra.Status.ProviderStatus.Raw, nil, current); err != nil {
+		return errors.Wrapf(err, "could not decode infrastructure status")
+	}
+
+	var status *alicloudv1alpha1.InfrastructureStatus
+	if ShouldUseFlow(infra) {
+		reconciler, err := newFlowReconciler(a.Client(), infra, config, nil, nil, nil)
+		if err != nil {
+			return err
+		}
+		status = reconciler.computeStatus()
+		status.MachineImages = current.MachineImages
+	} else {
+		tf, err := a.newTerraformer(ctx, infra, credentials)
+		if err != nil {
+			return err
+		}
+		if status, err = a.extractStatus(tf, config, current.MachineImages); err != nil {
+			return err
+		}
+	}
+
+	// Rules of the security group may also drift while the spec is unchanged.
+	if err := a.correctSecurityGroupDrift(ctx, infra, config, credentials, status); err != nil {
+		return errors.Wrapf(err, "failed to correct the drifted security group rules")
+	}
+
+	return extensioncontroller.TryUpdateStatus(ctx, retry.DefaultBackoff, a.Client(), infra, func() error {
+		infra.Status.ProviderStatus = &runtime.RawExtension{Object: status}
+		return nil
+	})
+}
@@ -0,0 +1,108 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package infrastructure_test
+
+import (
+	"github.com/gardener/gardener-extension-provider-alicloud/pkg/alicloud"
+	alicloudv1alpha1 "github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/alicloud/v1alpha1"
+	. "github.com/gardener/gardener-extension-provider-alicloud/pkg/controller/infrastructure"
+	"github.com/gardener/gardener-extensions/pkg/controller"
+
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
+)
+
+var _ = Describe("SpecHash", func() {
+	var (
+		infra       *extensionsv1alpha1.Infrastructure
+		config      *alicloudv1alpha1.InfrastructureConfig
+		credentials *alicloud.Credentials
+		cluster     *controller.Cluster
+	)
+
+	BeforeEach(func() {
+		infra = &extensionsv1alpha1.Infrastructure{
+			Spec: extensionsv1alpha1.InfrastructureSpec{Region: "cn-beijing"},
+		}
+		config = &alicloudv1alpha1.InfrastructureConfig{
+			Networks: alicloudv1alpha1.Networks{
+				VPC: alicloudv1alpha1.VPC{CIDR: pointer.StringPtr("10.250.0.0/16")},
+			},
+		}
+		credentials = &alicloud.Credentials{AccessKeyID: "id", AccessKeySecret: "secret"}
+		cluster = &controller.Cluster{
+			Shoot: &gardencorev1beta1.Shoot{
+				Spec: gardencorev1beta1.ShootSpec{
+					Provider: gardencorev1beta1.Provider{
+						Workers: []gardencorev1beta1.Worker{{Name: "pool-1"}},
+					},
+				},
+			},
+		}
+	})
+
+	Describe("#ComputeSpecHash", func() {
+		hash := func() string {
+			specHash, err := ComputeSpecHash(infra, config, credentials, cluster)
+			Expect(err).NotTo(HaveOccurred())
+			return specHash
+		}
+
+		It("should compute the same hash for the same inputs", func() {
+			Expect(hash()).To(Equal(hash()))
+		})
+
+		It("should compute another hash if the InfrastructureConfig changes", func() {
+			before := hash()
+			config.Networks.Zones = []alicloudv1alpha1.Zone{{Name: "cn-beijing-f", Workers: "10.250.0.0/19"}}
+
+			Expect(hash()).NotTo(Equal(before))
+		})
+
+		It("should compute another hash if the credentials change", func() {
+			before := hash()
+			credentials.AccessKeySecret = "rotated"
+
+			Expect(hash()).NotTo(Equal(before))
+		})
+
+		It("should compute another hash if the worker pools change", func() {
+			before := hash()
+			cluster.Shoot.Spec.Provider.Workers = append(cluster.Shoot.Spec.Provider.Workers, gardencorev1beta1.Worker{Name: "pool-2"})
+
+			Expect(hash()).NotTo(Equal(before))
+		})
+	})
+
+	DescribeTable("#ShouldSkipReconcile",
+		func(annotations map[string]string, lastError *gardencorev1beta1.LastError, providerStatus *runtime.RawExtension, expected bool) {
+			infra.Annotations = annotations
+			infra.Status.LastError = lastError
+			infra.Status.ProviderStatus = providerStatus
+
+			Expect(ShouldSkipReconcile(infra, "hash")).To(Equal(expected))
+		},
+		Entry("unchanged", map[string]string{AnnotationKeySpecHash: "hash"}, nil, &runtime.RawExtension{}, true),
+		Entry("changed", map[string]string{AnnotationKeySpecHash: "other"}, nil, &runtime.RawExtension{}, false),
+		Entry("never reconciled", nil, nil, nil, false),
+		Entry("forced", map[string]string{AnnotationKeySpecHash: "hash", AnnotationKeyForceReconcile: "true"}, nil, &runtime.RawExtension{}, false),
+		Entry("last reconciliation failed", map[string]string{AnnotationKeySpecHash: "hash"}, &gardencorev1beta1.LastError{Description: "error"}, &runtime.RawExtension{}, false),
+	)
+})
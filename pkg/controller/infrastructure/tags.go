@@ -0,0 +1,158 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package infrastructure
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/gardener/gardener-extension-provider-alicloud/pkg/alicloud"
+	alicloudclient "github.com/gardener/gardener-extension-provider-alicloud/pkg/alicloud/client"
+	alicloudv1alpha1 "github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/alicloud/v1alpha1"
+	extensioncontroller "github.com/gardener/gardener-extensions/pkg/controller"
+
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/vpc"
+	"github.com/gardener/gardener/pkg/operation/common"
+)
+
+// Resource types of the TagResources APIs.
+const (
+	tagResourceTypeVPC           = "VPC"
+	tagResourceTypeVSwitch       = "VSWITCH"
+	tagResourceTypeEIP           = "EIP"
+	tagResourceTypeNATGateway    = "NATGATEWAY"
+	tagResourceTypeSecurityGroup = "securitygroup"
+	tagResourceTypeKeyPair       = "keypair"
+)
+
+// ComputeTags computes the tags which are applied to all resources created for the infrastructure.
+// The tags of the InfrastructureConfig are complemented by the shoot and project name and the UID of the shoot.
+func ComputeTags(config *alicloudv1alpha1.InfrastructureConfig, cluster *extensioncontroller.Cluster) map[string]string {
+	tags := make(map[string]string, len(config.Tags)+3)
+	for key, value := range config.Tags {
+		tags[key] = value
+	}
+
+	if cluster != nil && cluster.Shoot != nil {
+		tags[alicloud.TagKeyShootName] = cluster.Shoot.Name
+		tags[alicloud.TagKeyProjectName] = strings.TrimPrefix(cluster.Shoot.Namespace, common.ProjectPrefix)
+		if uid := cluster.Shoot.UID; uid != "" {
+			tags[alicloud.TagKeyShootUID] = string(uid)
+		}
+	}
+
+	return tags
+}
+
+// vpcResourceTypes are the resource types of the VPC service in the order in which they are processed.
+var vpcResourceTypes = []string{tagResourceTypeVPC, tagResourceTypeNATGateway, tagResourceTypeVSwitch, tagResourceTypeEIP}
+
+// ecsResourceTypes are the resource types of the ECS service in the order in which they are processed.
+var ecsResourceTypes = []string{tagResourceTypeSecurityGroup, tagResourceTypeKeyPair}
+
+// managedResourceIDs returns the IDs of all resources in the given state which have been created for the infrastructure
+// keyed by their resource type. Resources which are not managed by the extension, like an existing VPC, are omitted.
+func managedResourceIDs(config *alicloudv1alpha1.InfrastructureConfig, state *FlowState) map[string][]string {
+	resourceIDs := map[string][]string{}
+	add := func(resourceType, id string) {
+		if id != "" {
+			resourceIDs[resourceType] = append(resourceIDs[resourceType], id)
+		}
+	}
+
+	if config.Networks.VPC.ID == nil {
+		add(tagResourceTypeVPC, state.Get(IdentifierVPC))
+		add(tagResourceTypeNATGateway, state.Get(IdentifierNATGateway))
+	}
+	for zoneIndex, zone := range config.Networks.Zones {
+		for vswitchIndex := range zoneWorkerCIDRs(zone) {
+			add(tagResourceTypeVSwitch, state.Get(VSwitchIdentifier(zoneIndex, vswitchIndex, IdentifierZoneVSwitch)))
+		}
+		add(tagResourceTypeVSwitch, state.Get(ZoneIdentifier(zoneIndex, IdentifierZonePodsVSwitch)))
+		for eipIndex := 0; eipIndex < eipCount(config); eipIndex++ {
+			add(tagResourceTypeEIP, state.Get(EIPIdentifier(zoneIndex, eipIndex, IdentifierZoneEIP)))
+		}
+		if isNATGatewayPerZone(config) {
+			add(tagResourceTypeNATGateway, state.Get(ZoneIdentifier(zoneIndex, IdentifierZoneNATGateway)))
+			add(tagResourceTypeVSwitch, state.Get(ZoneIdentifier(zoneIndex, IdentifierZoneNATVSwitch)))
+		}
+	}
+	if config.Networks.SecurityGroupID == nil {
+		add(tagResourceTypeSecurityGroup, state.Get(IdentifierSecurityGroup))
+	}
+	add(tagResourceTypeKeyPair, state.Get(IdentifierKeyPair))
+
+	return resourceIDs
+}
+
+// tagResources adds or updates the given tags of all resources in the given state which have been created for the
+// infrastructure.
+func tagResources(
+	ctx context.Context,
+	vpcClient alicloudclient.VPC,
+	ecsClient alicloudclient.ECS,
+	config *alicloudv1alpha1.InfrastructureConfig,
+	state *FlowState,
+	tags map[string]string,
+) error {
+	if len(tags) == 0 {
+		return nil
+	}
+
+	resourceIDs := managedResourceIDs(config, state)
+
+	vpcTags := make([]vpc.TagResourcesTag, 0, len(tags))
+	for _, key := range sortedKeys(tags) {
+		vpcTags = append(vpcTags, vpc.TagResourcesTag{Key: key, Value: tags[key]})
+	}
+
+	for _, resourceType := range vpcResourceTypes {
+		ids, ok := resourceIDs[resourceType]
+		if !ok {
+			continue
+		}
+
+		req := vpc.CreateTagResourcesRequest()
+		req.ResourceType = resourceType
+		req.ResourceId = &ids
+		req.Tag = &vpcTags
+		if _, err := vpcClient.TagResources(req); err != nil {
+			return err
+		}
+	}
+
+	for _, resourceType := range ecsResourceTypes {
+		ids, ok := resourceIDs[resourceType]
+		if !ok {
+			continue
+		}
+
+		if err := ecsClient.TagResources(ctx, resourceType, ids, tags); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,59 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package infrastructure_test
+
+import (
+	"github.com/gardener/gardener-extension-provider-alicloud/pkg/alicloud"
+	alicloudv1alpha1 "github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/alicloud/v1alpha1"
+	. "github.com/gardener/gardener-extension-provider-alicloud/pkg/controller/infrastructure"
+	"github.com/gardener/gardener-extensions/pkg/controller"
+
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Tags", func() {
+	Describe("#ComputeTags", func() {
+		It("should add the shoot and project name and the shoot UID to the configured tags", func() {
+			config := &alicloudv1alpha1.InfrastructureConfig{
+				Tags: map[string]string{"cost-center": "1234"},
+			}
+			cluster := &controller.Cluster{
+				Shoot: &gardencorev1beta1.Shoot{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "garden-dev",
+						Name:      "foo",
+						UID:       "1234-5678",
+					},
+				},
+			}
+
+			Expect(ComputeTags(config, cluster)).To(Equal(map[string]string{
+				"cost-center":              "1234",
+				alicloud.TagKeyShootName:   "foo",
+				alicloud.TagKeyProjectName: "dev",
+				alicloud.TagKeyShootUID:    "1234-5678",
+			}))
+		})
+
+		It("should only return the configured tags if there is no shoot", func() {
+			config := &alicloudv1alpha1.InfrastructureConfig{}
+
+			Expect(ComputeTags(config, &controller.Cluster{})).To(BeEmpty())
+		})
+	})
+})
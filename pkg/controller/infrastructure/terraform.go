@@ -15,6 +15,9 @@
 package infrastructure
 
 import (
+	"fmt"
+	"strconv"
+
 	"github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/alicloud/v1alpha1"
 
 	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
@@ -33,9 +36,11 @@ func (terraformOps) ComputeCreateVPCInitializerValues(config *v1alpha1.Infrastru
 		CreateVPC:          true,
 		VPCID:              TerraformDefaultVPCID,
 		VPCCIDR:            string(*config.Networks.VPC.CIDR),
+		VPCIPv6CIDR:        TerraformDefaultVPCIPv6CIDR,
 		NATGatewayID:       TerraformDefaultNATGatewayID,
 		SNATTableIDs:       TerraformDefaultSNATTableIDs,
-		InternetChargeType: internetChargeType,
+		RouteTableID:       TerraformDefaultRouteTableID,
+		InternetChargeType: eipInternetChargeType(config, internetChargeType),
 	}
 }
 
@@ -45,10 +50,93 @@ func (terraformOps) ComputeUseVPCInitializerValues(config *v1alpha1.Infrastructu
 		CreateVPC:          false,
 		VPCID:              *config.Networks.VPC.ID,
 		VPCCIDR:            info.CIDR,
+		VPCIPv6CIDR:        info.IPv6CIDR,
 		NATGatewayID:       info.NATGatewayID,
 		SNATTableIDs:       info.SNATTableIDs,
-		InternetChargeType: info.InternetChargeType,
+		RouteTableID:       info.RouteTableID,
+		InternetChargeType: eipInternetChargeType(config, info.InternetChargeType),
+	}
+}
+
+// VSwitchOutputKeySuffix returns the suffix of the Terraform output keys for the vswitch with the given index in the
+// zone with the given index. The first vswitch of a zone has index 0, the additional vswitches start with index 1.
+func VSwitchOutputKeySuffix(zoneIndex, vswitchIndex int) string {
+	if vswitchIndex == 0 {
+		return strconv.Itoa(zoneIndex)
+	}
+	return fmt.Sprintf("%d_%d", zoneIndex, vswitchIndex)
+}
+
+// ipv6CIDRMasksPerZone is the number of IPv6 CIDR masks which are reserved for the additional vswitches of each zone.
+const ipv6CIDRMasksPerZone = 16
+
+// vswitchIPv6CIDRMask returns the IPv6 CIDR mask of the vswitch with the given index in the zone with the given index.
+// The masks of the first vswitches of the zones are the zone indices. The additional vswitches of each zone get the
+// masks of a fixed block following them, so that adding zones or vswitches does not change the masks of the existing
+// vswitches.
+func vswitchIPv6CIDRMask(zoneIndex, vswitchIndex int) int {
+	if vswitchIndex == 0 {
+		return zoneIndex
+	}
+	return (zoneIndex+1)*ipv6CIDRMasksPerZone + vswitchIndex - 1
+}
+
+func eipAllocation(config *v1alpha1.InfrastructureConfig) *v1alpha1.EIPAllocation {
+	if config.Networks.NatGateway == nil {
+		return nil
+	}
+	return config.Networks.NatGateway.EIPAllocation
+}
+
+// eipAllocationID returns the allocation ID of the user-owned EIP of the NAT gateway or an empty string if EIPs are
+// allocated by the extension.
+func eipAllocationID(config *v1alpha1.InfrastructureConfig) string {
+	if config.Networks.NatGateway == nil || config.Networks.NatGateway.EIPAllocationID == nil {
+		return ""
+	}
+	return *config.Networks.NatGateway.EIPAllocationID
+}
+
+// eipCount returns the number of EIPs allocated for the NAT gateway of every zone.
+func eipCount(config *v1alpha1.InfrastructureConfig) int {
+	if allocation := eipAllocation(config); allocation != nil && allocation.Count != nil {
+		return int(*allocation.Count)
+	}
+	return 1
+}
+
+func eipInternetChargeType(config *v1alpha1.InfrastructureConfig, defaultInternetChargeType string) string {
+	if allocation := eipAllocation(config); allocation != nil && allocation.InternetChargeType != nil {
+		return *allocation.InternetChargeType
+	}
+	return defaultInternetChargeType
+}
+
+func eipBandwidth(config *v1alpha1.InfrastructureConfig) int32 {
+	if allocation := eipAllocation(config); allocation != nil && allocation.Bandwidth != nil {
+		return *allocation.Bandwidth
 	}
+	return DefaultEIPBandwidth
+}
+
+// securityGroupID returns the ID of the existing security group of the nodes or an empty string if the security group
+// is created by the extension.
+func securityGroupID(config *v1alpha1.InfrastructureConfig) string {
+	if config.Networks.SecurityGroupID == nil {
+		return ""
+	}
+	return *config.Networks.SecurityGroupID
+}
+
+func flowLogValues(config *v1alpha1.InfrastructureConfig) map[string]interface{} {
+	values := map[string]interface{}{
+		"enabled": config.Networks.EnableFlowLogs,
+	}
+	if target := config.Networks.FlowLogTarget; target != nil {
+		values["projectName"] = target.ProjectName
+		values["logStoreName"] = target.LogStoreName
+	}
+	return values
 }
 
 // ComputeTerraformerChartValues computes the values necessary for the infrastructure Terraform chart.
@@ -58,17 +146,55 @@ func (terraformOps) ComputeChartValues(
 	values *InitializerValues,
 ) map[string]interface{} {
 	zones := make([]map[string]interface{}, 0, len(config.Networks.Zones))
-	for _, zone := range config.Networks.Zones {
+	for zoneIndex, zone := range config.Networks.Zones {
 		workersCIDR := zone.Workers
 		// Backwards compatibility - remove this code in a future version.
 		if workersCIDR == "" {
 			workersCIDR = zone.Worker
 		}
+
+		additionalWorkers := make([]map[string]interface{}, 0, len(zone.AdditionalWorkers))
+		for i, cidr := range zone.AdditionalWorkers {
+			additionalWorkers = append(additionalWorkers, map[string]interface{}{
+				"index":        i + 1,
+				"cidr":         cidr,
+				"ipv6CIDRMask": vswitchIPv6CIDRMask(zoneIndex, i+1),
+			})
+		}
+
+		cidr := map[string]interface{}{
+			"workers": string(workersCIDR),
+		}
+		if zone.NatGatewayCIDR != nil {
+			cidr["natGateway"] = *zone.NatGatewayCIDR
+		}
+		if zone.PodsCIDR != nil {
+			cidr["pods"] = *zone.PodsCIDR
+		}
+
 		zones = append(zones, map[string]interface{}{
-			"name": zone.Name,
-			"cidr": map[string]interface{}{
-				"workers": string(workersCIDR),
-			},
+			"name":              zone.Name,
+			"cidr":              cidr,
+			"additionalWorkers": additionalWorkers,
+		})
+	}
+
+	securityGroupRules := make([]map[string]interface{}, 0, len(config.Networks.SecurityGroupRules))
+	for _, rule := range config.Networks.SecurityGroupRules {
+		securityGroupRules = append(securityGroupRules, map[string]interface{}{
+			"direction": string(rule.Direction),
+			"protocol":  rule.Protocol,
+			"portRange": rule.PortRange,
+			"cidr":      rule.CIDR,
+		})
+	}
+
+	routes := make([]map[string]interface{}, 0, len(config.Networks.Routes))
+	for _, route := range config.Networks.Routes {
+		routes = append(routes, map[string]interface{}{
+			"destinationCIDR": route.DestinationCIDR,
+			"nextHopType":     string(route.NextHopType),
+			"nextHopID":       route.NextHopID,
 		})
 	}
 
@@ -77,24 +203,51 @@ func (terraformOps) ComputeChartValues(
 			"region": infra.Spec.Region,
 		},
 		"create": map[string]interface{}{
-			"vpc": values.CreateVPC,
+			"vpc":                   values.CreateVPC,
+			"securityGroup":         config.Networks.SecurityGroupID == nil,
+			"routeTableAttachments": config.Networks.RouteTableID != nil || isNATGatewayPerZone(config),
+		},
+		"dualStack": map[string]interface{}{
+			"enabled": config.Networks.DualStack != nil && config.Networks.DualStack.Enabled,
 		},
 		"vpc": map[string]interface{}{
 			"cidr":               values.VPCCIDR,
+			"ipv6CIDR":           values.VPCIPv6CIDR,
 			"id":                 values.VPCID,
 			"natGatewayID":       values.NATGatewayID,
 			"snatTableID":        values.SNATTableIDs,
+			"routeTableID":       values.RouteTableID,
+			"securityGroupID":    securityGroupID(config),
 			"internetChargeType": values.InternetChargeType,
 		},
-		"clusterName":  infra.Namespace,
-		"sshPublicKey": string(infra.Spec.SSHPublicKey),
-		"zones":        zones,
+		"natGateway": map[string]interface{}{
+			"perZone": isNATGatewayPerZone(config),
+		},
+		"eip": map[string]interface{}{
+			"bandwidth":    eipBandwidth(config),
+			"allocationID": eipAllocationID(config),
+			"count":        eipCount(config),
+		},
+		"flowLog":            flowLogValues(config),
+		"clusterName":        infra.Namespace,
+		"sshPublicKey":       string(infra.Spec.SSHPublicKey),
+		"zones":              zones,
+		"securityGroupRules": securityGroupRules,
+		"routes":             routes,
 		"outputKeys": map[string]interface{}{
-			"vpcID":              TerraformerOutputKeyVPCID,
-			"vpcCIDR":            TerraformerOutputKeyVPCCIDR,
-			"securityGroupID":    TerraformerOutputKeySecurityGroupID,
-			"keyPairName":        TerraformerOutputKeyKeyPairName,
-			"vswitchNodesPrefix": TerraformerOutputKeyVSwitchNodesPrefix,
+			"vpcID":                        TerraformerOutputKeyVPCID,
+			"vpcCIDR":                      TerraformerOutputKeyVPCCIDR,
+			"securityGroupID":              TerraformerOutputKeySecurityGroupID,
+			"keyPairName":                  TerraformerOutputKeyKeyPairName,
+			"vswitchNodesPrefix":           TerraformerOutputKeyVSwitchNodesPrefix,
+			"vpcIPv6CIDR":                  TerraformerOutputKeyVPCIPv6CIDR,
+			"vswitchNodesIPv6Prefix":       TerraformerOutputKeyVSwitchNodesIPv6Prefix,
+			"natGatewayPrefix":             TerraformerOutputKeyNATGatewayPrefix,
+			"natGatewayVSwitchPrefix":      TerraformerOutputKeyNATGatewayVSwitchPrefix,
+			"natGatewayEIPPrefix":          TerraformerOutputKeyNATGatewayEIPPrefix,
+			"natGatewayEIPIPAddressPrefix": TerraformerOutputKeyNATGatewayEIPIPAddressPrefix,
+			"vswitchPodsPrefix":            TerraformerOutputKeyVSwitchPodsPrefix,
+			"zoneIDPrefix":                 TerraformerOutputKeyZoneIDPrefix,
 		},
 	}
 }
@@ -15,14 +15,23 @@
 package infrastructure_test
 
 import (
+	"path/filepath"
+
+	"github.com/gardener/gardener-extension-provider-alicloud/pkg/alicloud"
 	"github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/alicloud/v1alpha1"
 	. "github.com/gardener/gardener-extension-provider-alicloud/pkg/controller/infrastructure"
+	"github.com/gardener/gardener-extensions/pkg/util/chart"
 
 	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"github.com/gardener/gardener/pkg/chartrenderer"
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/engine"
+	"k8s.io/utils/pointer"
 )
 
 var _ = Describe("TerraformChartOps", func() {
@@ -55,11 +64,34 @@ var _ = Describe("TerraformChartOps", func() {
 				CreateVPC:          true,
 				VPCID:              TerraformDefaultVPCID,
 				VPCCIDR:            string(cidr),
+				VPCIPv6CIDR:        TerraformDefaultVPCIPv6CIDR,
 				NATGatewayID:       TerraformDefaultNATGatewayID,
 				SNATTableIDs:       TerraformDefaultSNATTableIDs,
+				RouteTableID:       TerraformDefaultRouteTableID,
 				InternetChargeType: internetChargeType,
 			}))
 		})
+
+		It("should prefer the internet charge type from the config", func() {
+			var (
+				cidr               = "192.168.0.0/16"
+				internetChargeType = "PayByBandwidth"
+				config             = v1alpha1.InfrastructureConfig{
+					Networks: v1alpha1.Networks{
+						VPC: v1alpha1.VPC{
+							CIDR: &cidr,
+						},
+						NatGateway: &v1alpha1.NatGateway{
+							EIPAllocation: &v1alpha1.EIPAllocation{
+								InternetChargeType: &internetChargeType,
+							},
+						},
+					},
+				}
+			)
+
+			Expect(ops.ComputeCreateVPCInitializerValues(&config, "foo").InternetChargeType).To(Equal(internetChargeType))
+		})
 	})
 
 	Describe("#ComputeUseVPCInitializerValues", func() {
@@ -67,12 +99,16 @@ var _ = Describe("TerraformChartOps", func() {
 			var (
 				id           = "id"
 				cidr         = "192.168.0.0/16"
+				ipv6CIDR     = "2408:4005:3a9:1000::/56"
 				natGatewayID = "natGatewayID"
 				sNATTableIDs = "sNATTableIDs"
+				routeTableID = "routeTableID"
 				info         = VPCInfo{
 					CIDR:         cidr,
+					IPv6CIDR:     ipv6CIDR,
 					NATGatewayID: natGatewayID,
 					SNATTableIDs: sNATTableIDs,
+					RouteTableID: routeTableID,
 				}
 				config = v1alpha1.InfrastructureConfig{
 					Networks: v1alpha1.Networks{
@@ -87,8 +123,10 @@ var _ = Describe("TerraformChartOps", func() {
 				CreateVPC:    false,
 				VPCID:        id,
 				VPCCIDR:      cidr,
+				VPCIPv6CIDR:  ipv6CIDR,
 				NATGatewayID: natGatewayID,
 				SNATTableIDs: sNATTableIDs,
+				RouteTableID: routeTableID,
 			}))
 		})
 	})
@@ -110,8 +148,9 @@ var _ = Describe("TerraformChartOps", func() {
 					},
 				}
 
-				zone1Name   = "zone1"
-				zone1Worker = "192.168.0.0/16"
+				zone1Name             = "zone1"
+				zone1Worker           = "192.168.0.0/16"
+				zone1AdditionalWorker = "192.171.0.0/16"
 
 				zone2Name   = "zone2"
 				zone2Worker = "192.169.0.0/16"
@@ -120,28 +159,51 @@ var _ = Describe("TerraformChartOps", func() {
 					Networks: v1alpha1.Networks{
 						Zones: []v1alpha1.Zone{
 							{
-								Name:    zone1Name,
-								Workers: zone1Worker,
+								Name:              zone1Name,
+								Workers:           zone1Worker,
+								AdditionalWorkers: []string{zone1AdditionalWorker},
 							},
 							{
 								Name:    zone2Name,
 								Workers: zone2Worker,
 							},
 						},
+						DualStack: &v1alpha1.DualStack{
+							Enabled: true,
+						},
+						SecurityGroupRules: []v1alpha1.SecurityGroupRule{
+							{
+								Direction: v1alpha1.SecurityGroupRuleDirectionIngress,
+								Protocol:  "tcp",
+								PortRange: "22/22",
+								CIDR:      "10.0.0.0/8",
+							},
+						},
+						Routes: []v1alpha1.Route{
+							{
+								DestinationCIDR: "172.16.0.0/16",
+								NextHopType:     v1alpha1.RouteNextHopTypeInstance,
+								NextHopID:       "i-123",
+							},
+						},
 					},
 				}
 
 				vpcCIDR            = "192.170.0.0/16"
+				vpcIPv6CIDR        = "vpcIPv6CIDR"
 				vpcID              = "vpcID"
 				natGatewayID       = "natGatewayID"
 				sNATTableIDs       = "sNATTableIDs"
+				routeTableID       = "routeTableID"
 				internetChargeType = "internetChargeType"
 				values             = InitializerValues{
 					CreateVPC:          true,
 					VPCCIDR:            vpcCIDR,
+					VPCIPv6CIDR:        vpcIPv6CIDR,
 					VPCID:              vpcID,
 					NATGatewayID:       natGatewayID,
 					SNATTableIDs:       sNATTableIDs,
+					RouteTableID:       routeTableID,
 					InternetChargeType: internetChargeType,
 				}
 			)
@@ -151,15 +213,34 @@ var _ = Describe("TerraformChartOps", func() {
 					"region": region,
 				},
 				"create": map[string]interface{}{
-					"vpc": true,
+					"vpc":                   true,
+					"securityGroup":         true,
+					"routeTableAttachments": false,
+				},
+				"dualStack": map[string]interface{}{
+					"enabled": true,
 				},
 				"vpc": map[string]interface{}{
 					"cidr":               vpcCIDR,
+					"ipv6CIDR":           vpcIPv6CIDR,
 					"id":                 vpcID,
 					"natGatewayID":       natGatewayID,
 					"snatTableID":        sNATTableIDs,
+					"routeTableID":       routeTableID,
+					"securityGroupID":    "",
 					"internetChargeType": internetChargeType,
 				},
+				"natGateway": map[string]interface{}{
+					"perZone": false,
+				},
+				"eip": map[string]interface{}{
+					"bandwidth":    DefaultEIPBandwidth,
+					"allocationID": "",
+					"count":        1,
+				},
+				"flowLog": map[string]interface{}{
+					"enabled": false,
+				},
 				"clusterName":  namespace,
 				"sshPublicKey": sshPublicKey,
 				"zones": []map[string]interface{}{
@@ -168,22 +249,152 @@ var _ = Describe("TerraformChartOps", func() {
 						"cidr": map[string]interface{}{
 							"workers": zone1Worker,
 						},
+						"additionalWorkers": []map[string]interface{}{
+							{
+								"index":        1,
+								"cidr":         zone1AdditionalWorker,
+								"ipv6CIDRMask": 16,
+							},
+						},
 					},
 					{
 						"name": zone2Name,
 						"cidr": map[string]interface{}{
 							"workers": zone2Worker,
 						},
+						"additionalWorkers": []map[string]interface{}{},
+					},
+				},
+				"securityGroupRules": []map[string]interface{}{
+					{
+						"direction": "ingress",
+						"protocol":  "tcp",
+						"portRange": "22/22",
+						"cidr":      "10.0.0.0/8",
+					},
+				},
+				"routes": []map[string]interface{}{
+					{
+						"destinationCIDR": "172.16.0.0/16",
+						"nextHopType":     "Instance",
+						"nextHopID":       "i-123",
 					},
 				},
 				"outputKeys": map[string]interface{}{
-					"vpcID":              TerraformerOutputKeyVPCID,
-					"vpcCIDR":            TerraformerOutputKeyVPCCIDR,
-					"securityGroupID":    TerraformerOutputKeySecurityGroupID,
-					"keyPairName":        TerraformerOutputKeyKeyPairName,
-					"vswitchNodesPrefix": TerraformerOutputKeyVSwitchNodesPrefix,
+					"vpcID":                        TerraformerOutputKeyVPCID,
+					"vpcCIDR":                      TerraformerOutputKeyVPCCIDR,
+					"securityGroupID":              TerraformerOutputKeySecurityGroupID,
+					"keyPairName":                  TerraformerOutputKeyKeyPairName,
+					"vswitchNodesPrefix":           TerraformerOutputKeyVSwitchNodesPrefix,
+					"vpcIPv6CIDR":                  TerraformerOutputKeyVPCIPv6CIDR,
+					"vswitchNodesIPv6Prefix":       TerraformerOutputKeyVSwitchNodesIPv6Prefix,
+					"natGatewayPrefix":             TerraformerOutputKeyNATGatewayPrefix,
+					"natGatewayVSwitchPrefix":      TerraformerOutputKeyNATGatewayVSwitchPrefix,
+					"natGatewayEIPPrefix":          TerraformerOutputKeyNATGatewayEIPPrefix,
+					"natGatewayEIPIPAddressPrefix": TerraformerOutputKeyNATGatewayEIPIPAddressPrefix,
+					"vswitchPodsPrefix":            TerraformerOutputKeyVSwitchPodsPrefix,
+					"zoneIDPrefix":                 TerraformerOutputKeyZoneIDPrefix,
 				},
 			}))
 		})
+
+		It("should not create a security group if an existing one is used", func() {
+			var (
+				securityGroupID = "sg-123"
+				infra           = extensionsv1alpha1.Infrastructure{ObjectMeta: metav1.ObjectMeta{Namespace: "cluster-foo"}}
+				config          = v1alpha1.InfrastructureConfig{
+					Networks: v1alpha1.Networks{
+						VPC:             v1alpha1.VPC{ID: pointer.StringPtr("vpc-123")},
+						SecurityGroupID: &securityGroupID,
+					},
+				}
+			)
+
+			values := ops.ComputeChartValues(&infra, &config, &InitializerValues{VPCID: "vpc-123"})
+			Expect(values["create"]).To(HaveKeyWithValue("securityGroup", false))
+			Expect(values["vpc"]).To(HaveKeyWithValue("securityGroupID", securityGroupID))
+		})
+
+		It("should not change the IPv6 CIDR masks of the existing vswitches if zones or vswitches are added", func() {
+			var (
+				infra  = extensionsv1alpha1.Infrastructure{ObjectMeta: metav1.ObjectMeta{Namespace: "cluster-foo"}}
+				config = v1alpha1.InfrastructureConfig{
+					Networks: v1alpha1.Networks{
+						Zones: []v1alpha1.Zone{
+							{Name: "zone1", Workers: "10.250.0.0/19", AdditionalWorkers: []string{"10.250.32.0/19"}},
+							{Name: "zone2", Workers: "10.250.64.0/19", AdditionalWorkers: []string{"10.250.96.0/19"}},
+						},
+						DualStack: &v1alpha1.DualStack{Enabled: true},
+					},
+				}
+			)
+
+			ipv6CIDRMasks := func() []int {
+				var masks []int
+				for _, zone := range ops.ComputeChartValues(&infra, &config, &InitializerValues{})["zones"].([]map[string]interface{}) {
+					for _, additionalWorkers := range zone["additionalWorkers"].([]map[string]interface{}) {
+						masks = append(masks, additionalWorkers["ipv6CIDRMask"].(int))
+					}
+				}
+				return masks
+			}
+
+			Expect(ipv6CIDRMasks()).To(Equal([]int{16, 32}))
+
+			config.Networks.Zones = append(config.Networks.Zones, v1alpha1.Zone{Name: "zone3", Workers: "10.250.128.0/19", AdditionalWorkers: []string{"10.250.160.0/19"}})
+			config.Networks.Zones[0].AdditionalWorkers = append(config.Networks.Zones[0].AdditionalWorkers, "10.250.192.0/19")
+
+			Expect(ipv6CIDRMasks()).To(Equal([]int{16, 17, 32, 48}))
+		})
+	})
+
+	Describe("alicloud-infra chart", func() {
+		var (
+			chartRenderer chartrenderer.Interface
+			infra         = extensionsv1alpha1.Infrastructure{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "shoot--foo--bar"},
+				Spec: extensionsv1alpha1.InfrastructureSpec{
+					Region:       "cn-beijing",
+					SSHPublicKey: []byte("ssh-rsa AAAA"),
+				},
+			}
+			config v1alpha1.InfrastructureConfig
+		)
+
+		BeforeEach(func() {
+			chartRenderer = chartrenderer.New(engine.New(), &chartutil.Capabilities{KubeVersion: &version.Info{GitVersion: "v1.16.0"}})
+			config = v1alpha1.InfrastructureConfig{
+				Networks: v1alpha1.Networks{
+					VPC: v1alpha1.VPC{CIDR: pointer.StringPtr("10.250.0.0/16")},
+					Zones: []v1alpha1.Zone{
+						{Name: "cn-beijing-f", Workers: "10.250.0.0/19", AdditionalWorkers: []string{"10.250.32.0/19"}, PodsCIDR: pointer.StringPtr("10.250.64.0/19")},
+						{Name: "cn-beijing-g", Workers: "10.250.96.0/19", PodsCIDR: pointer.StringPtr("10.250.128.0/19")},
+					},
+				},
+			}
+		})
+
+		renderMainTF := func() string {
+			values := ops.ComputeChartValues(&infra, &config, ops.ComputeCreateVPCInitializerValues(&config, "PayByTraffic"))
+			release, err := chartRenderer.Render(filepath.Join("..", "..", "..", alicloud.InfraChartPath), alicloud.InfraRelease, infra.Namespace, values)
+			Expect(err).NotTo(HaveOccurred())
+			files, err := chart.ExtractTerraformFiles(release)
+			Expect(err).NotTo(HaveOccurred())
+			return files.Main
+		}
+
+		It("should only use resources and arguments supported by the alicloud provider of the Terraformer image", func() {
+			mainTF := renderMainTF()
+
+			Expect(mainTF).To(ContainSubstring(`resource "alicloud_nat_gateway" "nat_gateway" {`))
+			// Enhanced NAT gateways, their route tables, flow logs and IPv6 require a newer provider, they are only
+			// created by the flow reconciler.
+			Expect(mainTF).NotTo(ContainSubstring("nat_type"))
+			Expect(mainTF).NotTo(ContainSubstring(`resource "alicloud_route_table" `))
+			Expect(mainTF).NotTo(ContainSubstring(`resource "alicloud_vswitch" "vsw_natgw_`))
+			Expect(mainTF).NotTo(ContainSubstring("alicloud_vpc_flow_log"))
+			Expect(mainTF).NotTo(ContainSubstring("enable_ipv6"))
+			Expect(mainTF).NotTo(ContainSubstring("ipv6_cidr_block_mask"))
+		})
 	})
 })
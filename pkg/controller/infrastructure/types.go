@@ -34,6 +34,24 @@ const (
 	TerraformerOutputKeyKeyPairName = "key_pair_name"
 	// TerraformerOutputKeyVSwitchNodesPrefix is the prefix for the vswitches.
 	TerraformerOutputKeyVSwitchNodesPrefix = "vswitch_id_z"
+	// TerraformerOutputKeyVPCIPv6CIDR is the output key of the VPC IPv6 CIDR.
+	TerraformerOutputKeyVPCIPv6CIDR = "vpc_ipv6_cidr"
+	// TerraformerOutputKeyVSwitchNodesIPv6Prefix is the prefix for the IPv6 CIDRs of the vswitches.
+	TerraformerOutputKeyVSwitchNodesIPv6Prefix = "vswitch_ipv6_cidr_z"
+	// TerraformerOutputKeyNATGatewayPrefix is the prefix for the NAT gateways of the zones.
+	TerraformerOutputKeyNATGatewayPrefix = "natgw_id_z"
+	// TerraformerOutputKeyNATGatewayVSwitchPrefix is the prefix for the dedicated vswitches of the NAT gateways of the zones.
+	TerraformerOutputKeyNATGatewayVSwitchPrefix = "natgw_vswitch_id_z"
+	// TerraformerOutputKeyNATGatewayEIPPrefix is the prefix for the allocation IDs of the EIPs of the NAT gateways of the
+	// zones.
+	TerraformerOutputKeyNATGatewayEIPPrefix = "natgw_eip_id_z"
+	// TerraformerOutputKeyNATGatewayEIPIPAddressPrefix is the prefix for the IP addresses of the EIPs of the NAT gateways
+	// of the zones.
+	TerraformerOutputKeyNATGatewayEIPIPAddressPrefix = "natgw_eip_ip_z"
+	// TerraformerOutputKeyVSwitchPodsPrefix is the prefix for the dedicated vswitches of the pods of the zones.
+	TerraformerOutputKeyVSwitchPodsPrefix = "vswitch_pods_id_z"
+	// TerraformerOutputKeyZoneIDPrefix is the prefix for the availability zones of the vswitches of the zones.
+	TerraformerOutputKeyZoneIDPrefix = "zone_id_z"
 
 	// TerraformDefaultVPCID is the default value for the VPC ID in the chart.
 	TerraformDefaultVPCID = "${alicloud_vpc.vpc.id}"
@@ -41,13 +59,22 @@ const (
 	TerraformDefaultNATGatewayID = "${alicloud_nat_gateway.nat_gateway.id}"
 	// TerraformDefaultSNATTableIDs is the default value for the SNAT table IDs in the chart.
 	TerraformDefaultSNATTableIDs = "${alicloud_nat_gateway.nat_gateway.snat_table_ids}"
+	// TerraformDefaultVPCIPv6CIDR is the default value for the VPC IPv6 CIDR in the chart.
+	TerraformDefaultVPCIPv6CIDR = "${alicloud_vpc.vpc.ipv6_cidr_block}"
+	// TerraformDefaultRouteTableID is the default value for the route table ID in the chart.
+	TerraformDefaultRouteTableID = "${alicloud_vpc.vpc.route_table_id}"
+
+	// DefaultEIPBandwidth is the default bandwidth in Mbps of the EIPs of the NAT gateway.
+	DefaultEIPBandwidth int32 = 100
 )
 
 // VPCInfo contains info about an existing VPC.
 type VPCInfo struct {
 	CIDR               string
+	IPv6CIDR           string
 	NATGatewayID       string
 	SNATTableIDs       string
+	RouteTableID       string
 	InternetChargeType string
 }
 
@@ -56,8 +83,10 @@ type InitializerValues struct {
 	CreateVPC          bool
 	VPCID              string
 	VPCCIDR            string
+	VPCIPv6CIDR        string
 	NATGatewayID       string
 	SNATTableIDs       string
+	RouteTableID       string
 	InternetChargeType string
 }
 
@@ -16,8 +16,10 @@ package worker
 
 import (
 	"context"
+	"time"
 
 	"github.com/gardener/gardener-extension-provider-alicloud/pkg/alicloud"
+	alicloudclient "github.com/gardener/gardener-extension-provider-alicloud/pkg/alicloud/client"
 	api "github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/alicloud"
 	"github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/alicloud/helper"
 	"github.com/gardener/gardener-extension-provider-alicloud/pkg/imagevector"
@@ -30,30 +32,108 @@ import (
 	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
 	gardener "github.com/gardener/gardener/pkg/client/kubernetes"
 	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/kubernetes"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/inject"
 )
 
 type delegateFactory struct {
 	logger logr.Logger
 	common.RESTConfigContext
+
+	alicloudClientFactory alicloudclient.ClientFactory
+	machineImageCache     *MachineImageCache
+	instanceTypeCache     *InstanceTypeCache
 }
 
 // NewActuator creates a new Actuator that updates the status of the handled WorkerPoolConfigs.
 func NewActuator() worker.Actuator {
 	delegateFactory := &delegateFactory{
-		logger: log.Log.WithName("worker-actuator"),
+		logger:                log.Log.WithName("worker-actuator"),
+		alicloudClientFactory: alicloudclient.NewClientFactory(),
+		machineImageCache:     NewMachineImageCache(),
+		instanceTypeCache:     NewInstanceTypeCache(),
 	}
 
-	return genericactuator.NewActuator(
-		log.Log.WithName("alicloud-worker-actuator"),
-		delegateFactory,
-		alicloud.MachineControllerManagerName,
-		mcmChart,
-		mcmShootChart,
-		imagevector.ImageVector(),
-		extensionscontroller.ChartRendererFactoryFunc(util.NewChartRendererForShoot),
-	)
+	return &actuator{
+		Actuator: genericactuator.NewActuator(
+			log.Log.WithName("alicloud-worker-actuator"),
+			delegateFactory,
+			alicloud.MachineControllerManagerName,
+			mcmChart,
+			mcmShootChart,
+			imagevector.ImageVector(),
+			extensionscontroller.ChartRendererFactoryFunc(util.NewChartRendererForShoot),
+		),
+		delegateFactory: delegateFactory,
+	}
+}
+
+// actuator deletes the encrypted copies of machine images which are no longer used by a worker once the generic
+// actuator has reconciled or deleted the worker, i.e., once no machine uses them anymore.
+type actuator struct {
+	worker.Actuator
+	delegateFactory *delegateFactory
+}
+
+// InjectFunc enables dependency injection into the generic actuator.
+func (a *actuator) InjectFunc(f inject.Func) error {
+	return f(a.Actuator)
+}
+
+// Reconcile checks that the machine types of the worker pools are available, reconciles the worker, and deletes the
+// encrypted machine images which are no longer used afterwards.
+func (a *actuator) Reconcile(ctx context.Context, w *extensionsv1alpha1.Worker, cluster *extensionscontroller.Cluster) error {
+	workerDelegate, err := a.delegateFactory.newGarbageCollectionDelegate(w, cluster)
+	if err != nil {
+		return err
+	}
+	if err := workerDelegate.checkInstanceTypes(ctx); err != nil {
+		return err
+	}
+
+	if err := a.Actuator.Reconcile(ctx, w, cluster); err != nil {
+		return a.explainPrePaidInstances(ctx, w, cluster, err)
+	}
+
+	if err := workerDelegate.generateMachineConfig(ctx); err != nil {
+		return err
+	}
+	return workerDelegate.deleteUnusedEncryptedMachineImages(ctx, workerDelegate.encryptedMachineImageIDs)
+}
+
+// Delete deletes the worker and the encrypted machine images which were used by it afterwards.
+func (a *actuator) Delete(ctx context.Context, w *extensionsv1alpha1.Worker, cluster *extensionscontroller.Cluster) error {
+	if err := a.Actuator.Delete(ctx, w, cluster); err != nil {
+		return a.explainPrePaidInstances(ctx, w, cluster, err)
+	}
+
+	workerDelegate, err := a.delegateFactory.newGarbageCollectionDelegate(w, cluster)
+	if err != nil {
+		return err
+	}
+	return workerDelegate.deleteUnusedEncryptedMachineImages(ctx, sets.NewString())
+}
+
+// explainPrePaidInstances adds a hint about PrePaid instances which cannot be released yet to the given error of the
+// generic actuator.
+func (a *actuator) explainPrePaidInstances(ctx context.Context, w *extensionsv1alpha1.Worker, cluster *extensionscontroller.Cluster, err error) error {
+	delegate, delegateErr := a.delegateFactory.newGarbageCollectionDelegate(w, cluster)
+	if delegateErr != nil {
+		return err
+	}
+	return delegate.explainPrePaidInstances(ctx, err, time.Now())
+}
+
+// newGarbageCollectionDelegate creates a worker delegate which can only generate the machine configuration and
+// access the Alicloud API, i.e., without chart applier and server version.
+func (d *delegateFactory) newGarbageCollectionDelegate(worker *extensionsv1alpha1.Worker, cluster *extensionscontroller.Cluster) (*workerDelegate, error) {
+	delegate, err := NewWorkerDelegate(d.ClientContext, d.alicloudClientFactory, d.machineImageCache, d.instanceTypeCache, nil, "", worker, cluster)
+	if err != nil {
+		return nil, err
+	}
+	return delegate.(*workerDelegate), nil
 }
 
 func (d *delegateFactory) WorkerDelegate(ctx context.Context, worker *extensionsv1alpha1.Worker, cluster *extensionscontroller.Cluster) (genericactuator.WorkerDelegate, error) {
@@ -74,6 +154,9 @@ func (d *delegateFactory) WorkerDelegate(ctx context.Context, worker *extensions
 
 	return NewWorkerDelegate(
 		d.ClientContext,
+		d.alicloudClientFactory,
+		d.machineImageCache,
+		d.instanceTypeCache,
 
 		seedChartApplier,
 		serverVersion.GitVersion,
@@ -85,6 +168,10 @@ func (d *delegateFactory) WorkerDelegate(ctx context.Context, worker *extensions
 
 type workerDelegate struct {
 	common.ClientContext
+	alicloudClientFactory alicloudclient.ClientFactory
+	machineImageCache     *MachineImageCache
+	machineImageLocks     machineImageLocks
+	instanceTypeCache     *InstanceTypeCache
 
 	seedChartApplier gardener.ChartApplier
 	serverVersion    string
@@ -96,11 +183,16 @@ type workerDelegate struct {
 	machineClasses     []map[string]interface{}
 	machineDeployments worker.MachineDeployments
 	machineImages      []api.MachineImage
+
+	encryptedMachineImageIDs sets.String
 }
 
 // NewWorkerDelegate creates a new context for a worker reconciliation.
 func NewWorkerDelegate(
 	clientContext common.ClientContext,
+	alicloudClientFactory alicloudclient.ClientFactory,
+	machineImageCache *MachineImageCache,
+	instanceTypeCache *InstanceTypeCache,
 
 	seedChartApplier gardener.ChartApplier,
 	serverVersion string,
@@ -113,7 +205,10 @@ func NewWorkerDelegate(
 		return nil, err
 	}
 	return &workerDelegate{
-		ClientContext: clientContext,
+		ClientContext:         clientContext,
+		alicloudClientFactory: alicloudClientFactory,
+		machineImageCache:     machineImageCache,
+		instanceTypeCache:     instanceTypeCache,
 
 		seedChartApplier: seedChartApplier,
 		serverVersion:    serverVersion,
@@ -123,3 +218,13 @@ func NewWorkerDelegate(
 		worker:             worker,
 	}, nil
 }
+
+// newECSClient creates a new ECS client with the credentials of the worker.
+func (w *workerDelegate) newECSClient(ctx context.Context) (alicloudclient.ECS, error) {
+	credentials, err := alicloud.ReadCredentialsFromSecretRef(ctx, w.Client(), &w.worker.Spec.SecretRef)
+	if err != nil {
+		return nil, err
+	}
+
+	return w.alicloudClientFactory.NewECSClient(ctx, w.worker.Spec.Region, credentials)
+}
@@ -0,0 +1,182 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package worker
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gardener/gardener-extension-provider-alicloud/pkg/alicloud"
+	mockalicloudclient "github.com/gardener/gardener-extension-provider-alicloud/pkg/mock/provider-alicloud/alicloud/client"
+	"github.com/gardener/gardener-extensions/pkg/controller/common"
+	mockclient "github.com/gardener/gardener-extensions/pkg/mock/controller-runtime/client"
+
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/ecs"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("Worker", func() {
+	const (
+		namespace  = "shoot--foo--bar"
+		region     = "eu-central-1"
+		clusterTag = "kubernetes.io/cluster/" + namespace
+	)
+
+	var (
+		ctrl                  *gomock.Controller
+		c                     *mockclient.MockClient
+		alicloudClientFactory *mockalicloudclient.MockClientFactory
+		ecsClient             *mockalicloudclient.MockECS
+
+		w *workerDelegate
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+
+		c = mockclient.NewMockClient(ctrl)
+		alicloudClientFactory = mockalicloudclient.NewMockClientFactory(ctrl)
+		ecsClient = mockalicloudclient.NewMockECS(ctrl)
+
+		w = &workerDelegate{
+			ClientContext:         common.NewClientContext(c, nil, nil),
+			alicloudClientFactory: alicloudClientFactory,
+			worker: &extensionsv1alpha1.Worker{
+				ObjectMeta: metav1.ObjectMeta{Namespace: namespace},
+				Spec: extensionsv1alpha1.WorkerSpec{
+					Region:    region,
+					SecretRef: corev1.SecretReference{Name: "secret", Namespace: namespace},
+				},
+			},
+		}
+
+		c.EXPECT().
+			Get(context.TODO(), gomock.Any(), gomock.AssignableToTypeOf(&corev1.Secret{})).
+			DoAndReturn(func(_ context.Context, _ client.ObjectKey, secret *corev1.Secret) error {
+				secret.Data = map[string][]byte{
+					alicloud.AccessKeyID:     []byte("access-key-id"),
+					alicloud.AccessKeySecret: []byte("access-key-secret"),
+				}
+				return nil
+			})
+		alicloudClientFactory.EXPECT().NewECSClient(context.TODO(), region, gomock.Any()).Return(ecsClient, nil)
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	image := func(id string, tags ...string) ecs.Image {
+		image := ecs.Image{ImageId: id}
+		for _, key := range tags {
+			image.Tags.Tag = append(image.Tags.Tag, ecs.Tag{TagKey: key, TagValue: "1"})
+		}
+		return image
+	}
+
+	Describe("#deleteUnusedEncryptedMachineImages", func() {
+		It("should delete unused copies and keep the used ones", func() {
+			ecsClient.EXPECT().GetImagesByTags(context.TODO(), map[string]string{clusterTag: "1"}).Return([]ecs.Image{
+				image("m-used", alicloud.TagKeyEncryptedImageSource, clusterTag),
+				image("m-unused", alicloud.TagKeyEncryptedImageSource, clusterTag),
+				image("m-foreign", clusterTag),
+			}, nil)
+			ecsClient.EXPECT().DeleteImage(context.TODO(), region, "m-unused")
+
+			Expect(w.deleteUnusedEncryptedMachineImages(context.TODO(), sets.NewString("m-used"))).To(Succeed())
+		})
+
+		It("should only untag unused copies which are still used by other shoots", func() {
+			ecsClient.EXPECT().GetImagesByTags(context.TODO(), map[string]string{clusterTag: "1"}).Return([]ecs.Image{
+				image("m-shared", alicloud.TagKeyEncryptedImageSource, clusterTag, "kubernetes.io/cluster/shoot--foo--baz"),
+			}, nil)
+			ecsClient.EXPECT().UntagResources(context.TODO(), "image", []string{"m-shared"}, []string{clusterTag})
+
+			Expect(w.deleteUnusedEncryptedMachineImages(context.TODO(), sets.NewString())).To(Succeed())
+		})
+	})
+
+	Describe("#checkInstanceTypes", func() {
+		BeforeEach(func() {
+			w.instanceTypeCache = NewInstanceTypeCache()
+			w.worker.Spec.Pools = []extensionsv1alpha1.WorkerPool{
+				{Name: "pool", MachineType: "ecs.g6.large", Zones: []string{"eu-central-1a", "eu-central-1b"}},
+			}
+		})
+
+		It("should succeed if the machine type is available in all zones", func() {
+			ecsClient.EXPECT().GetAvailableInstanceTypes(context.TODO(), region, "eu-central-1a", "PostPaid").Return([]string{"ecs.g6.large", "ecs.g6.xlarge"}, nil)
+			ecsClient.EXPECT().GetAvailableInstanceTypes(context.TODO(), region, "eu-central-1b", "PostPaid").Return([]string{"ecs.g6.large"}, nil)
+
+			Expect(w.checkInstanceTypes(context.TODO())).To(Succeed())
+		})
+
+		It("should name the zones in which the machine type is not available", func() {
+			ecsClient.EXPECT().GetAvailableInstanceTypes(context.TODO(), region, "eu-central-1a", "PostPaid").Return([]string{"ecs.g6.large"}, nil)
+			ecsClient.EXPECT().GetAvailableInstanceTypes(context.TODO(), region, "eu-central-1b", "PostPaid").Return([]string{"ecs.g6.xlarge"}, nil)
+
+			Expect(w.checkInstanceTypes(context.TODO())).To(MatchError("machine type ecs.g6.large of worker pool pool is not available for PostPaid instances in zones eu-central-1b, please choose another machine type or other zones"))
+		})
+
+		It("should cache the available instance types of the zones", func() {
+			ecsClient.EXPECT().GetAvailableInstanceTypes(context.TODO(), region, "eu-central-1a", "PostPaid").Return([]string{"ecs.g6.large"}, nil)
+			ecsClient.EXPECT().GetAvailableInstanceTypes(context.TODO(), region, "eu-central-1b", "PostPaid").Return([]string{"ecs.g6.large"}, nil)
+			c.EXPECT().
+				Get(context.TODO(), gomock.Any(), gomock.AssignableToTypeOf(&corev1.Secret{})).
+				DoAndReturn(func(_ context.Context, _ client.ObjectKey, secret *corev1.Secret) error {
+					secret.Data = map[string][]byte{
+						alicloud.AccessKeyID:     []byte("access-key-id"),
+						alicloud.AccessKeySecret: []byte("access-key-secret"),
+					}
+					return nil
+				})
+
+			Expect(w.checkInstanceTypes(context.TODO())).To(Succeed())
+			Expect(w.checkInstanceTypes(context.TODO())).To(Succeed())
+		})
+	})
+
+	Describe("#explainPrePaidInstances", func() {
+		var (
+			err = errors.New("machines are not deleted")
+			now = time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+		)
+
+		It("should name the PrePaid instances whose subscription has not ended yet", func() {
+			ecsClient.EXPECT().GetPrePaidInstancesByTags(context.TODO(), region, map[string]string{clusterTag: "1"}).Return([]ecs.Instance{
+				{InstanceId: "i-expired", ExpiredTime: "2020-05-01T16:00Z"},
+				{InstanceId: "i-subscribed", ExpiredTime: "2020-07-01T16:00Z"},
+			}, nil)
+
+			Expect(w.explainPrePaidInstances(context.TODO(), err, now)).To(MatchError(ContainSubstring("the PrePaid instances i-subscribed (until 2020-07-01T16:00Z) cannot be released")))
+		})
+
+		It("should return the error as is if there are no subscribed instances", func() {
+			ecsClient.EXPECT().GetPrePaidInstancesByTags(context.TODO(), region, map[string]string{clusterTag: "1"}).Return([]ecs.Instance{
+				{InstanceId: "i-expired", ExpiredTime: "2020-05-01T16:00Z"},
+			}, nil)
+
+			Expect(w.explainPrePaidInstances(context.TODO(), err, now)).To(Equal(err))
+		})
+	})
+})
@@ -0,0 +1,73 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package worker
+
+import (
+	"context"
+	"fmt"
+
+	alicloudapi "github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/alicloud"
+	alicloudapihelper "github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/alicloud/helper"
+	"github.com/gardener/gardener-extensions/pkg/controller/worker"
+
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// maxDeploymentSetInstancesPerZone is the maximum number of instances of a deployment set in one zone.
+const maxDeploymentSetInstancesPerZone = 20
+
+// deploymentSetStrategies are the strategies of deployment sets which implement the placement strategies.
+var deploymentSetStrategies = map[alicloudapi.PlacementStrategy][]string{
+	alicloudapi.PlacementStrategySpread: {"Availability", "AvailabilityGroup"},
+	alicloudapi.PlacementStrategyPack:   {"LowLatency"},
+}
+
+// checkDeploymentSet checks that the given deployment set exists, has the given placement strategy if any, and can
+// hold the machines of the given worker pool. Otherwise, machines could not be created and would be stuck until the
+// machine-controller-manager gives up.
+func (w *workerDelegate) checkDeploymentSet(ctx context.Context, pool extensionsv1alpha1.WorkerPool, deploymentSetID string, placementStrategy *alicloudapi.PlacementStrategy) error {
+	ecsClient, err := w.newECSClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	deploymentSet, err := ecsClient.GetDeploymentSet(ctx, w.worker.Spec.Region, deploymentSetID)
+	if err != nil {
+		return fmt.Errorf("could not get deployment set %s of worker pool %s: %v", deploymentSetID, pool.Name, err)
+	}
+	if deploymentSet == nil {
+		return fmt.Errorf("deployment set %s of worker pool %s does not exist in region %s", deploymentSetID, pool.Name, w.worker.Spec.Region)
+	}
+	if placementStrategy != nil && !sets.NewString(deploymentSetStrategies[*placementStrategy]...).Has(deploymentSet.Strategy) {
+		return fmt.Errorf("deployment set %s of worker pool %s has strategy %s which does not implement the placement strategy %s", deploymentSetID, pool.Name, deploymentSet.Strategy, *placementStrategy)
+	}
+	if placementStrategy != nil && *placementStrategy == alicloudapi.PlacementStrategyPack {
+		// Packed instances are placed close to each other, which is only possible within one zone.
+		if len(pool.Zones) > 1 {
+			return fmt.Errorf("worker pool %s spans %d zones, but the machines of a packed deployment set must be in one zone", pool.Name, len(pool.Zones))
+		}
+		if alicloudapihelper.IsSharedInstanceType(pool.MachineType) {
+			return fmt.Errorf("machine type %s of worker pool %s is a shared instance type which cannot be packed into a deployment set", pool.MachineType, pool.Name)
+		}
+	}
+
+	// The first zone gets the largest share of the machines of the pool.
+	if maximum := worker.DistributeOverZones(0, pool.Maximum, len(pool.Zones)); maximum > maxDeploymentSetInstancesPerZone {
+		return fmt.Errorf("deployment set %s is full, it can hold at most %d instances per zone but worker pool %s has up to %d machines per zone", deploymentSetID, maxDeploymentSetInstancesPerZone, pool.Name, maximum)
+	}
+
+	return nil
+}
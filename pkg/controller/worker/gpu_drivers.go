@@ -0,0 +1,46 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package worker
+
+import "fmt"
+
+// defaultGPUDriverVersion is the version of the NVIDIA driver which is installed on GPU instances if the worker pool
+// does not specify one.
+const defaultGPUDriverVersion = "470.57.02"
+
+// gpuDriverScript returns the script which installs the NVIDIA driver of the given version on GPU instances. The
+// driver is only installed if it is not yet present in this version, e.g. in the machine image.
+func gpuDriverScript(version string) string {
+	return fmt.Sprintf(`#!/bin/bash
+set -o errexit
+
+version="%s"
+if nvidia-smi --query-gpu=driver_version --format=csv,noheader 2> /dev/null | grep -qx "${version}"; then
+  exit 0
+fi
+
+if command -v apt-get > /dev/null; then
+  apt-get update
+  apt-get install -y gcc make "linux-headers-$(uname -r)"
+fi
+
+installer="/tmp/NVIDIA-Linux-x86_64-${version}.run"
+curl -fsSL -o "${installer}" "https://us.download.nvidia.com/tesla/${version}/NVIDIA-Linux-x86_64-${version}.run"
+sh "${installer}" --silent --no-questions
+rm -f "${installer}"
+
+nvidia-smi --persistence-mode=1
+`, version)
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package worker
+
+import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/cache"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+const (
+	// instanceTypeCacheTTL is the time the available instance types of a zone are cached. It is short because the
+	// availability of instance types changes with the capacity of the zones.
+	instanceTypeCacheTTL = 5 * time.Minute
+	// instanceTypeCacheSize is the maximum number of zones whose available instance types are cached.
+	instanceTypeCacheSize = 256
+)
+
+// InstanceTypeCache caches the instance types which are available in a zone so that not every worker reconciliation
+// has to call DescribeAvailableResource. It is safe for concurrent use by multiple reconciliations.
+type InstanceTypeCache struct {
+	cache *cache.LRUExpireCache
+}
+
+// NewInstanceTypeCache creates a new, empty InstanceTypeCache.
+func NewInstanceTypeCache() *InstanceTypeCache {
+	return &InstanceTypeCache{
+		cache: cache.NewLRUExpireCache(instanceTypeCacheSize),
+	}
+}
+
+// instanceTypeCacheKey identifies a lookup of the available instance types of a zone. The availability may differ
+// per account and instance charge type.
+type instanceTypeCacheKey struct {
+	accessKeyID        string
+	region             string
+	zone               string
+	instanceChargeType string
+}
+
+// get returns the cached available instance types for the given key, if any. A nil cache never contains any instance
+// types.
+func (c *InstanceTypeCache) get(key instanceTypeCacheKey) (sets.String, bool) {
+	if c == nil {
+		return nil, false
+	}
+	value, ok := c.cache.Get(key)
+	if !ok {
+		return nil, false
+	}
+	return value.(sets.String), true
+}
+
+// add caches the given available instance types for the given key.
+func (c *InstanceTypeCache) add(key instanceTypeCacheKey, instanceTypes sets.String) {
+	if c == nil {
+		return
+	}
+	c.cache.Add(key, instanceTypes, instanceTypeCacheTTL)
+}
@@ -0,0 +1,86 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package worker
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/gardener/gardener-extension-provider-alicloud/pkg/alicloud"
+	alicloudclient "github.com/gardener/gardener-extension-provider-alicloud/pkg/alicloud/client"
+	alicloudapi "github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/alicloud"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// checkInstanceTypes checks that the machine type of each worker pool is available in all zones of the pool.
+// Otherwise, machines could not be created and would be stuck until the machine-controller-manager gives up.
+func (w *workerDelegate) checkInstanceTypes(ctx context.Context) error {
+	credentials, err := alicloud.ReadCredentialsFromSecretRef(ctx, w.Client(), &w.worker.Spec.SecretRef)
+	if err != nil {
+		return err
+	}
+
+	var ecsClient alicloudclient.ECS
+	for _, pool := range w.worker.Spec.Pools {
+		instanceChargeType := alicloudapi.InstanceChargeTypePostPaid
+		if pool.ProviderConfig != nil && pool.ProviderConfig.Raw != nil {
+			workerConfig := &alicloudapi.WorkerConfig{}
+			if _, _, err := w.Decoder().Decode(pool.ProviderConfig.Raw, nil, workerConfig); err != nil {
+				return fmt.Errorf("could not decode provider config of worker pool %q: %+v", pool.Name, err)
+			}
+			if workerConfig.InstanceChargeType != nil {
+				instanceChargeType = *workerConfig.InstanceChargeType
+			}
+		}
+
+		var unavailableZones []string
+		for _, zone := range pool.Zones {
+			cacheKey := instanceTypeCacheKey{
+				accessKeyID:        credentials.AccessKeyID,
+				region:             w.worker.Spec.Region,
+				zone:               zone,
+				instanceChargeType: string(instanceChargeType),
+			}
+
+			availableInstanceTypes, ok := w.instanceTypeCache.get(cacheKey)
+			if !ok {
+				if ecsClient == nil {
+					if ecsClient, err = w.alicloudClientFactory.NewECSClient(ctx, w.worker.Spec.Region, credentials); err != nil {
+						return err
+					}
+				}
+
+				instanceTypes, err := ecsClient.GetAvailableInstanceTypes(ctx, w.worker.Spec.Region, zone, string(instanceChargeType))
+				if err != nil {
+					return fmt.Errorf("could not get the available instance types of zone %s: %v", zone, err)
+				}
+				availableInstanceTypes = sets.NewString(instanceTypes...)
+				w.instanceTypeCache.add(cacheKey, availableInstanceTypes)
+			}
+
+			if !availableInstanceTypes.Has(pool.MachineType) {
+				unavailableZones = append(unavailableZones, zone)
+			}
+		}
+
+		if len(unavailableZones) > 0 {
+			return fmt.Errorf("machine type %s of worker pool %s is not available for %s instances in zones %s, please choose another machine type or other zones", pool.MachineType, pool.Name, instanceChargeType, strings.Join(unavailableZones, ", "))
+		}
+	}
+
+	return nil
+}
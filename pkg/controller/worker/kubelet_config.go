@@ -0,0 +1,83 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package worker
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gardener/gardener-extension-provider-alicloud/pkg/alicloud"
+	alicloudapi "github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/alicloud"
+
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// kubeletExtraArgs returns the command line arguments of the kubelet for the given kubelet settings of a worker pool.
+func kubeletExtraArgs(kubeletConfig *alicloudapi.KubeletConfig) []string {
+	var args []string
+	if kubeletConfig.MaxPods != nil {
+		args = append(args, fmt.Sprintf("--max-pods=%d", *kubeletConfig.MaxPods))
+	}
+	for _, arg := range []struct {
+		name      string
+		values    map[string]string
+		separator string
+	}{
+		{"eviction-hard", kubeletConfig.EvictionHard, "<"},
+		{"eviction-soft", kubeletConfig.EvictionSoft, "<"},
+		{"eviction-soft-grace-period", kubeletConfig.EvictionSoftGracePeriod, "="},
+		{"kube-reserved", kubeletConfig.KubeReserved, "="},
+	} {
+		if len(arg.values) == 0 {
+			continue
+		}
+		var values []string
+		for _, key := range sets.StringKeySet(arg.values).List() {
+			values = append(values, key+arg.separator+arg.values[key])
+		}
+		args = append(args, fmt.Sprintf("--%s=%s", arg.name, strings.Join(values, ",")))
+	}
+	return args
+}
+
+// kubeletExtraArgsScript returns the script which writes the given command line arguments to the environment file of
+// the kubelet service, which passes them to the kubelet.
+func kubeletExtraArgsScript(args []string) string {
+	return fmt.Sprintf(`#!/bin/bash
+set -o errexit
+
+mkdir -p "$(dirname %[1]s)"
+cat > %[1]s <<'EOF'
+%[2]s="%[3]s"
+EOF
+`, alicloud.KubeletExtraArgsFile, alicloud.KubeletExtraArgsEnvVar, strings.Join(args, " "))
+}
+
+// shootKubeletConfigs returns the kubelet configurations of the shoot which apply to the given worker pool, i.e. the
+// cluster-wide one and the one of the worker pool.
+func (w *workerDelegate) shootKubeletConfigs(poolName string) []*gardencorev1beta1.KubeletConfig {
+	if w.cluster == nil || w.cluster.Shoot == nil {
+		return nil
+	}
+
+	configs := []*gardencorev1beta1.KubeletConfig{w.cluster.Shoot.Spec.Kubernetes.Kubelet}
+	for _, pool := range w.cluster.Shoot.Spec.Provider.Workers {
+		if pool.Name == poolName && pool.Kubernetes != nil {
+			configs = append(configs, pool.Kubernetes.Kubelet)
+		}
+	}
+	return configs
+}
@@ -0,0 +1,35 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package worker
+
+// localDisksScript formats the blank local NVMe disks of an instance and mounts them below /mnt/local-disks.
+const localDisksScript = `#!/bin/bash
+set -o errexit
+
+index=0
+for disk in $(lsblk --nodeps --noheadings --output NAME,TYPE | awk '$1 ~ /^nvme/ && $2 == "disk" { print "/dev/" $1 }'); do
+  dir="/mnt/local-disks/disk${index}"
+  index=$((index + 1))
+
+  if ! blkid "${disk}" > /dev/null; then
+    mkfs.ext4 -F "${disk}"
+  fi
+  mkdir -p "${dir}"
+  if ! grep -q "^${disk} " /etc/fstab; then
+    echo "${disk} ${dir} ext4 defaults,nofail 0 2" >> /etc/fstab
+  fi
+  mountpoint -q "${dir}" || mount "${dir}"
+done
+`
@@ -18,8 +18,10 @@ import (
 	"context"
 	"fmt"
 	"path/filepath"
+	"time"
 
 	"github.com/gardener/gardener-extension-provider-alicloud/pkg/alicloud"
+	alicloudapi "github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/alicloud"
 	extensionscontroller "github.com/gardener/gardener-extensions/pkg/controller"
 
 	"github.com/gardener/gardener/pkg/utils/chart"
@@ -29,6 +31,10 @@ import (
 	rbacv1 "k8s.io/api/rbac/v1"
 )
 
+// defaultMachineDrainTimeout is the drain timeout of the machine-controller-manager for worker pools which don't
+// configure one.
+const defaultMachineDrainTimeout = 2 * time.Hour
+
 var (
 	mcmChart = &chart.Chart{
 		Name:   alicloud.MachineControllerManagerName,
@@ -60,14 +66,50 @@ func (w *workerDelegate) GetMachineControllerManagerChartValues(ctx context.Cont
 		return nil, err
 	}
 
+	machineDrainTimeout, err := w.machineDrainTimeout()
+	if err != nil {
+		return nil, err
+	}
+
 	return map[string]interface{}{
 		"providerName": alicloud.Name,
 		"namespace": map[string]interface{}{
 			"uid": namespace.UID,
 		},
+		"machineDrainTimeout": machineDrainTimeout.String(),
 	}, nil
 }
 
+// machineDrainTimeout returns the drain timeout for the machine-controller-manager. It only supports one drain timeout
+// for all machines of a shoot, hence the longest drain timeout of the worker pools is used so that no node is deleted
+// before its pool's drain timeout has passed.
+func (w *workerDelegate) machineDrainTimeout() (time.Duration, error) {
+	var drainTimeout time.Duration
+
+	for _, pool := range w.worker.Spec.Pools {
+		poolDrainTimeout := defaultMachineDrainTimeout
+
+		if pool.ProviderConfig != nil && pool.ProviderConfig.Raw != nil {
+			workerConfig := &alicloudapi.WorkerConfig{}
+			if _, _, err := w.Decoder().Decode(pool.ProviderConfig.Raw, nil, workerConfig); err != nil {
+				return 0, fmt.Errorf("could not decode provider config of worker pool %q: %+v", pool.Name, err)
+			}
+			if workerConfig.DrainTimeout != nil {
+				poolDrainTimeout = workerConfig.DrainTimeout.Duration
+			}
+		}
+
+		if poolDrainTimeout > drainTimeout {
+			drainTimeout = poolDrainTimeout
+		}
+	}
+
+	if drainTimeout == 0 {
+		return defaultMachineDrainTimeout, nil
+	}
+	return drainTimeout, nil
+}
+
 func (w *workerDelegate) GetMachineControllerManagerShootChartValues(ctx context.Context) (map[string]interface{}, error) {
 	return map[string]interface{}{
 		"providerName": alicloud.Name,
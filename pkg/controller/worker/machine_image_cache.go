@@ -0,0 +1,119 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package worker
+
+import (
+	"sync"
+	"time"
+
+	extensionscontroller "github.com/gardener/gardener-extensions/pkg/controller"
+	"k8s.io/apimachinery/pkg/util/cache"
+)
+
+const (
+	// machineImageCacheTTL is the time the result of a machine image lookup is cached.
+	machineImageCacheTTL = 5 * time.Minute
+	// machineImageCacheSize is the maximum number of cached machine image lookups.
+	machineImageCacheSize = 1024
+)
+
+// MachineImageCache caches the IDs of machine images which were looked up via the ECS API so that not every worker
+// reconciliation has to call DescribeImages. It is safe for concurrent use by multiple reconciliations.
+type MachineImageCache struct {
+	cache *cache.LRUExpireCache
+}
+
+// NewMachineImageCache creates a new, empty MachineImageCache.
+func NewMachineImageCache() *MachineImageCache {
+	return &MachineImageCache{
+		cache: cache.NewLRUExpireCache(machineImageCacheSize),
+	}
+}
+
+// machineImageCacheKey identifies a machine image lookup. It contains the name and generation of the CloudProfile so
+// that all cached lookups are invalidated as soon as the CloudProfile changes.
+type machineImageCacheKey struct {
+	cloudProfile           string
+	cloudProfileGeneration int64
+
+	accessKeyID string
+	region      string
+	name        string
+	version     string
+	encrypted   bool
+	kmsKeyID    string
+}
+
+func newMachineImageCacheKey(cluster *extensionscontroller.Cluster, accessKeyID, region, name, version string, encrypted bool, kmsKeyID *string) machineImageCacheKey {
+	key := machineImageCacheKey{
+		accessKeyID: accessKeyID,
+		region:      region,
+		name:        name,
+		version:     version,
+		encrypted:   encrypted,
+	}
+	if cluster != nil && cluster.CloudProfile != nil {
+		key.cloudProfile = cluster.CloudProfile.Name
+		key.cloudProfileGeneration = cluster.CloudProfile.Generation
+	}
+	if kmsKeyID != nil {
+		key.kmsKeyID = *kmsKeyID
+	}
+	return key
+}
+
+// get returns the cached machine image ID for the given key, if any. A nil cache never contains any machine images.
+func (c *MachineImageCache) get(key machineImageCacheKey) (string, bool) {
+	if c == nil {
+		return "", false
+	}
+	value, ok := c.cache.Get(key)
+	if !ok {
+		return "", false
+	}
+	return value.(string), true
+}
+
+// add caches the given machine image ID for the given key.
+func (c *MachineImageCache) add(key machineImageCacheKey, imageID string) {
+	if c == nil {
+		return
+	}
+	c.cache.Add(key, imageID, machineImageCacheTTL)
+}
+
+// machineImageLocks serializes the lookups of the same machine image by the concurrently generated worker pools of a
+// worker, so that an image is only looked up, or copied, once and the other pools find it in the cache.
+type machineImageLocks struct {
+	mutex sync.Mutex
+	locks map[machineImageCacheKey]*sync.Mutex
+}
+
+// lock locks the given key and returns the function which unlocks it again.
+func (l *machineImageLocks) lock(key machineImageCacheKey) func() {
+	l.mutex.Lock()
+	if l.locks == nil {
+		l.locks = map[machineImageCacheKey]*sync.Mutex{}
+	}
+	keyLock, ok := l.locks[key]
+	if !ok {
+		keyLock = &sync.Mutex{}
+		l.locks[key] = keyLock
+	}
+	l.mutex.Unlock()
+
+	keyLock.Lock()
+	return keyLock.Unlock
+}
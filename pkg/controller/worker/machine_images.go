@@ -16,17 +16,29 @@ package worker
 
 import (
 	"context"
+	"fmt"
+	"strings"
+
 	"github.com/gardener/gardener-extensions/pkg/controller/worker"
 
+	"github.com/gardener/gardener-extension-provider-alicloud/pkg/alicloud"
 	api "github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/alicloud"
 	"github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/alicloud/helper"
 	"github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/alicloud/v1alpha1"
 
 	"github.com/gardener/gardener-extensions/pkg/util"
 
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/ecs"
 	"github.com/pkg/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+const (
+	imageStatusAvailable = "Available"
+	// imageResourceType is the ECS resource type of images.
+	imageResourceType = "image"
 )
 
 // GetMachineImages returns the used machine images for the `Worker` resource.
@@ -61,12 +73,17 @@ func (w *workerDelegate) GetMachineImages(ctx context.Context) (runtime.Object,
 	return workerStatusV1alpha1, nil
 }
 
-func (w *workerDelegate) findMachineImage(name, version, region string) (string, error) {
+func (w *workerDelegate) findMachineImage(ctx context.Context, name, version, region string) (string, error) {
 	machineImageID, err := helper.FindImageForRegionFromCloudProfile(w.cloudProfileConfig, name, version, region)
 	if err == nil {
 		return machineImageID, nil
 	}
 
+	// The explicit mapping of the region takes precedence, the image is only looked up by its name otherwise.
+	if machineImageVersion, err := helper.FindMachineImageVersion(w.cloudProfileConfig, name, version); err == nil && machineImageVersion.ImageName != nil {
+		return w.findMachineImageByName(ctx, name, version, *machineImageVersion.ImageName)
+	}
+
 	// Try to look up machine image in worker provider status as it was not found in componentconfig.
 	if providerStatus := w.worker.Status.ProviderStatus; providerStatus != nil {
 		workerStatus := &api.WorkerStatus{}
@@ -74,7 +91,7 @@ func (w *workerDelegate) findMachineImage(name, version, region string) (string,
 			return "", errors.Wrapf(err, "could not decode worker status of worker '%s'", util.ObjectName(w.worker))
 		}
 
-		machineImage, err := helper.FindMachineImage(workerStatus.MachineImages, name, version)
+		machineImage, err := helper.FindMachineImage(workerStatus.MachineImages, name, version, false)
 		if err != nil {
 			return "", worker.ErrorMachineImageNotFound(name, version)
 		}
@@ -85,8 +102,190 @@ func (w *workerDelegate) findMachineImage(name, version, region string) (string,
 	return "", worker.ErrorMachineImageNotFound(name, version)
 }
 
+// findMachineImageByName looks up the ID of the image with the given Alicloud image name in the region of the worker.
+// The IDs are cached under the given name and version of the machine image.
+func (w *workerDelegate) findMachineImageByName(ctx context.Context, name, version, imageName string) (string, error) {
+	credentials, err := alicloud.ReadCredentialsFromSecretRef(ctx, w.Client(), &w.worker.Spec.SecretRef)
+	if err != nil {
+		return "", err
+	}
+
+	cacheKey := newMachineImageCacheKey(w.cluster, credentials.AccessKeyID, w.worker.Spec.Region, name, version, false, nil)
+	defer w.machineImageLocks.lock(cacheKey)()
+	if imageID, ok := w.machineImageCache.get(cacheKey); ok {
+		return imageID, nil
+	}
+
+	ecsClient, err := w.alicloudClientFactory.NewECSClient(ctx, w.worker.Spec.Region, credentials)
+	if err != nil {
+		return "", err
+	}
+
+	image, err := ecsClient.GetAvailableImageByName(ctx, imageName)
+	if err != nil {
+		return "", err
+	}
+	if image == nil {
+		return "", fmt.Errorf("could not find image %q of machine image %q in version %q in region %q", imageName, name, version, w.worker.Spec.Region)
+	}
+
+	w.machineImageCache.add(cacheKey, image.ImageId)
+	return image.ImageId, nil
+}
+
+// ensureEncryptedMachineImage returns the ID of an encrypted copy of the given machine image. Alicloud only encrypts the
+// system disk of an instance if its image is encrypted, hence images which are not pre-encrypted are copied with
+// encryption enabled. As copying takes a while, an error is returned until the copy is available. Available copies
+// are cached under the given name and version of the machine image.
+func (w *workerDelegate) ensureEncryptedMachineImage(ctx context.Context, name, version, imageID string, kmsKeyID *string) (string, error) {
+	credentials, err := alicloud.ReadCredentialsFromSecretRef(ctx, w.Client(), &w.worker.Spec.SecretRef)
+	if err != nil {
+		return "", err
+	}
+
+	// The encrypted copies are owned by the account, hence the access key is part of the cache key.
+	cacheKey := newMachineImageCacheKey(w.cluster, credentials.AccessKeyID, w.worker.Spec.Region, name, version, true, kmsKeyID)
+	defer w.machineImageLocks.lock(cacheKey)()
+	if encryptedImageID, ok := w.machineImageCache.get(cacheKey); ok {
+		return encryptedImageID, nil
+	}
+
+	ecsClient, err := w.alicloudClientFactory.NewECSClient(ctx, w.worker.Spec.Region, credentials)
+	if err != nil {
+		return "", err
+	}
+
+	encryptedImageName := encryptedMachineImageName(imageID, kmsKeyID)
+	image, err := ecsClient.GetImageByName(ctx, encryptedImageName)
+	if err != nil {
+		return "", err
+	}
+
+	// Every shoot using an encrypted copy tags it, so that the copy is only deleted once no shoot uses it anymore.
+	tags := map[string]string{
+		alicloud.TagKeyEncryptedImageSource: imageID,
+		w.clusterTagKey():                   "1",
+	}
+
+	if image == nil {
+		// The image may be owned by another account, e.g. a custom image of the user, in which case it has to be shared
+		// with the account of the shoot before it can be copied. The copy is always encrypted with a key of the shoot's
+		// account, hence encrypted images of other accounts are re-encrypted.
+		exists, err := ecsClient.CheckIfImageExists(ctx, imageID)
+		if err != nil {
+			return "", err
+		}
+		if !exists {
+			return "", fmt.Errorf("image %s is not accessible by the account of the shoot, please share it with the account (if the image is encrypted, its owner also has to grant the account access to the KMS key of the image)", imageID)
+		}
+
+		encryptedImageID, err := ecsClient.CopyEncryptedImage(ctx, w.worker.Spec.Region, imageID, encryptedImageName, kmsKeyID, tags)
+		if err != nil {
+			return "", fmt.Errorf("could not copy image %s to an encrypted image: %v", imageID, err)
+		}
+		return "", fmt.Errorf("encrypted copy %s of image %s is being created", encryptedImageID, imageID)
+	}
+
+	if image.Status != imageStatusAvailable {
+		return "", fmt.Errorf("encrypted copy %s of image %s is not yet available, status is %q", image.ImageId, imageID, image.Status)
+	}
+
+	if !hasTags(image, tags) {
+		if err := ecsClient.TagResources(ctx, imageResourceType, []string{image.ImageId}, tags); err != nil {
+			return "", fmt.Errorf("could not tag encrypted copy %s of image %s: %v", image.ImageId, imageID, err)
+		}
+	}
+
+	w.machineImageCache.add(cacheKey, image.ImageId)
+	return image.ImageId, nil
+}
+
+// deleteUnusedEncryptedMachineImages removes the tag of the shoot from all encrypted copies of machine images which
+// are not in the given set of used images. Copies which are no longer tagged by any shoot are deleted. Only images
+// which carry the tag of the source image are considered, hence neither source images nor copies which were not
+// created by the worker controller are touched.
+func (w *workerDelegate) deleteUnusedEncryptedMachineImages(ctx context.Context, usedImageIDs sets.String) error {
+	ecsClient, err := w.newECSClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	images, err := ecsClient.GetImagesByTags(ctx, map[string]string{w.clusterTagKey(): "1"})
+	if err != nil {
+		return err
+	}
+
+	for _, image := range images {
+		if usedImageIDs.Has(image.ImageId) || !hasTagKey(image, alicloud.TagKeyEncryptedImageSource) {
+			continue
+		}
+
+		if usedByOtherShoots(image, w.clusterTagKey()) {
+			if err := ecsClient.UntagResources(ctx, imageResourceType, []string{image.ImageId}, []string{w.clusterTagKey()}); err != nil {
+				return fmt.Errorf("could not untag encrypted image %s: %v", image.ImageId, err)
+			}
+			continue
+		}
+
+		if err := ecsClient.DeleteImage(ctx, w.worker.Spec.Region, image.ImageId); err != nil {
+			return fmt.Errorf("could not delete unused encrypted image %s: %v", image.ImageId, err)
+		}
+	}
+
+	return nil
+}
+
+// clusterTagKey returns the key of the tag identifying the resources of the shoot of the worker.
+func (w *workerDelegate) clusterTagKey() string {
+	return fmt.Sprintf("kubernetes.io/cluster/%s", w.worker.Namespace)
+}
+
+func hasTags(image *ecs.Image, tags map[string]string) bool {
+	for key, value := range tags {
+		found := false
+		for _, tag := range image.Tags.Tag {
+			if tag.TagKey == key && tag.TagValue == value {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func hasTagKey(image ecs.Image, key string) bool {
+	for _, tag := range image.Tags.Tag {
+		if tag.TagKey == key {
+			return true
+		}
+	}
+	return false
+}
+
+// usedByOtherShoots checks whether the given image is tagged by another shoot than the one with the given tag key.
+func usedByOtherShoots(image ecs.Image, clusterTagKey string) bool {
+	for _, tag := range image.Tags.Tag {
+		if strings.HasPrefix(tag.TagKey, "kubernetes.io/cluster/") && tag.TagKey != clusterTagKey {
+			return true
+		}
+	}
+	return false
+}
+
+// encryptedMachineImageName returns the name of the encrypted copy of the given image using the given KMS key.
+func encryptedMachineImageName(imageID string, kmsKeyID *string) string {
+	if kmsKeyID != nil {
+		return fmt.Sprintf("%s-encrypted-%s", imageID, *kmsKeyID)
+	}
+	return fmt.Sprintf("%s-encrypted", imageID)
+}
+
 func appendMachineImage(machineImages []api.MachineImage, machineImage api.MachineImage) []api.MachineImage {
-	if _, err := helper.FindMachineImage(machineImages, machineImage.Name, machineImage.Version); err != nil {
+	encrypted := machineImage.Encrypted != nil && *machineImage.Encrypted
+	if _, err := helper.FindMachineImage(machineImages, machineImage.Name, machineImage.Version, encrypted); err != nil {
 		return append(machineImages, machineImage)
 	}
 	return machineImages
@@ -16,19 +16,34 @@ package worker
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"path/filepath"
+	"sync"
 
 	"github.com/gardener/gardener-extension-provider-alicloud/pkg/alicloud"
 	alicloudapi "github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/alicloud"
 	apisalicloud "github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/alicloud"
 	alicloudapihelper "github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/alicloud/helper"
+	"github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/alicloud/validation"
 	"github.com/gardener/gardener-extensions/pkg/controller/worker"
 	genericworkeractuator "github.com/gardener/gardener-extensions/pkg/controller/worker/genericactuator"
 
 	v1beta1constants "github.com/gardener/gardener/pkg/apis/core/v1beta1/constants"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
 	machinev1alpha1 "github.com/gardener/machine-controller-manager/pkg/apis/machine/v1alpha1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// spotStrategyNoSpot is the spot strategy of regular pay-as-you-go instances.
+	spotStrategyNoSpot = "NoSpot"
+	// maxConcurrentWorkerPools is the maximum number of worker pools whose machine configuration is generated
+	// concurrently. It limits the number of concurrent requests to the Alicloud APIs of a single worker.
+	maxConcurrentWorkerPools = 5
 )
 
 // MachineClassKind yields the name of the Alicloud machine class.
@@ -79,6 +94,7 @@ func (w *workerDelegate) generateMachineConfig(ctx context.Context) error {
 		machineDeployments = worker.MachineDeployments{}
 		machineClasses     []map[string]interface{}
 		machineImages      []apisalicloud.MachineImage
+		encryptedImageIDs  = sets.NewString()
 	)
 
 	machineClassSecretData, err := w.generateMachineClassSecretData(ctx)
@@ -96,97 +112,434 @@ func (w *workerDelegate) generateMachineConfig(ctx context.Context) error {
 		return err
 	}
 
-	for _, pool := range w.worker.Spec.Pools {
-		zoneLen := len(pool.Zones)
+	// The machine configuration of the worker pools is generated concurrently as it requires several API calls per
+	// pool, but the results are collected in the order of the pools so that the applied objects do not change.
+	var (
+		poolConfigs = make([]*poolMachineConfig, len(w.worker.Spec.Pools))
+		poolErrs    = make([]error, len(w.worker.Spec.Pools))
+		wg          sync.WaitGroup
+		semaphore   = make(chan struct{}, maxConcurrentWorkerPools)
+	)
+	for i, pool := range w.worker.Spec.Pools {
+		wg.Add(1)
+		go func(i int, pool extensionsv1alpha1.WorkerPool) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+			poolConfigs[i], poolErrs[i] = w.generatePoolMachineConfig(ctx, pool, infrastructureStatus, nodesSecurityGroup.ID, machineClassSecretData)
+		}(i, pool)
+	}
+	wg.Wait()
 
-		workerPoolHash, err := worker.WorkerPoolHash(pool, w.cluster)
-		if err != nil {
-			return err
+	for i, poolConfig := range poolConfigs {
+		if poolErrs[i] != nil {
+			return poolErrs[i]
+		}
+		machineDeployments = append(machineDeployments, poolConfig.machineDeployments...)
+		machineClasses = append(machineClasses, poolConfig.machineClasses...)
+		for _, machineImage := range poolConfig.machineImages {
+			machineImages = appendMachineImage(machineImages, machineImage)
+		}
+		encryptedImageIDs = encryptedImageIDs.Union(poolConfig.encryptedImageIDs)
+	}
+
+	w.machineDeployments = machineDeployments
+	w.machineClasses = machineClasses
+	w.machineImages = machineImages
+	w.encryptedMachineImageIDs = encryptedImageIDs
+
+	return nil
+}
+
+// rolloutWorkerConfigFields are the fields of the WorkerConfig which only control how the machines of a worker pool are
+// rolled and drained, the machines themselves do not depend on them.
+var rolloutWorkerConfigFields = []string{"drainTimeout", "maxSurge", "maxUnavailable"}
+
+// hashedWorkerPool returns the given worker pool without the rollout fields in its provider config. They must not be
+// part of the worker pool hash, otherwise changing them would roll all machines of the pool. Provider configs without
+// rollout fields are kept as they are so that the hash of their worker pools does not change. Otherwise, the provider
+// config is encoded with sorted keys like kubectl encodes YAML manifests, hence adding or removing rollout fields
+// keeps the hash of provider configs applied with kubectl.
+func hashedWorkerPool(pool extensionsv1alpha1.WorkerPool) (extensionsv1alpha1.WorkerPool, error) {
+	if pool.ProviderConfig == nil || pool.ProviderConfig.Raw == nil {
+		return pool, nil
+	}
+
+	providerConfig := map[string]interface{}{}
+	if err := yaml.Unmarshal(pool.ProviderConfig.Raw, &providerConfig); err != nil {
+		return pool, err
+	}
+
+	var removed bool
+	for _, field := range rolloutWorkerConfigFields {
+		if _, ok := providerConfig[field]; ok {
+			delete(providerConfig, field)
+			removed = true
+		}
+	}
+	if !removed {
+		return pool, nil
+	}
+
+	raw, err := json.Marshal(providerConfig)
+	if err != nil {
+		return pool, err
+	}
+	pool.ProviderConfig = &runtime.RawExtension{Raw: raw}
+	return pool, nil
+}
+
+// poolMachineConfig is the generated machine configuration of a single worker pool.
+type poolMachineConfig struct {
+	machineDeployments worker.MachineDeployments
+	machineClasses     []map[string]interface{}
+	machineImages      []apisalicloud.MachineImage
+	encryptedImageIDs  sets.String
+}
+
+func (w *workerDelegate) generatePoolMachineConfig(
+	ctx context.Context,
+	pool extensionsv1alpha1.WorkerPool,
+	infrastructureStatus *alicloudapi.InfrastructureStatus,
+	nodesSecurityGroupID string,
+	machineClassSecretData map[string][]byte,
+) (*poolMachineConfig, error) {
+	config := &poolMachineConfig{encryptedImageIDs: sets.NewString()}
+
+	workerConfig := &alicloudapi.WorkerConfig{}
+	if pool.ProviderConfig != nil && pool.ProviderConfig.Raw != nil {
+		if _, _, err := w.Decoder().Decode(pool.ProviderConfig.Raw, nil, workerConfig); err != nil {
+			return nil, fmt.Errorf("could not decode provider config of worker pool %q: %+v", pool.Name, err)
 		}
+	}
 
-		machineImageID, err := w.findMachineImage(pool.MachineImage.Name, pool.MachineImage.Version, w.worker.Spec.Region)
+	// A custom image of the worker pool is used as is, the machine image of the pool is only resolved otherwise.
+	// Pools without machine image use the default machine image of the region, which is part of the hash so that
+	// the machines are rolled if the default changes.
+	customImage := workerConfig.ImageID != nil
+	var additionalHashData []string
+	imageName, imageVersion := pool.MachineImage.Name, pool.MachineImage.Version
+	if !customImage {
+		imageName, imageVersion = alicloudapihelper.ResolveMachineImage(w.cloudProfileConfig, imageName, imageVersion, w.worker.Spec.Region)
+		if len(imageName) == 0 || len(imageVersion) == 0 {
+			return nil, fmt.Errorf("worker pool %s has no machine image and region %s has no default machine image", pool.Name, w.worker.Spec.Region)
+		}
+		if imageName != pool.MachineImage.Name || imageVersion != pool.MachineImage.Version {
+			additionalHashData = append(additionalHashData, imageName+imageVersion)
+		}
+	}
+
+	hashedPool, err := hashedWorkerPool(pool)
+	if err != nil {
+		return nil, fmt.Errorf("could not compute the hash of worker pool %q: %+v", pool.Name, err)
+	}
+	workerPoolHash, err := worker.WorkerPoolHash(hashedPool, w.cluster, additionalHashData...)
+	if err != nil {
+		return nil, err
+	}
+
+	maxSurge, maxUnavailable := pool.MaxSurge, pool.MaxUnavailable
+	if workerConfig.MaxSurge != nil {
+		maxSurge = *workerConfig.MaxSurge
+	}
+	if workerConfig.MaxUnavailable != nil {
+		maxUnavailable = *workerConfig.MaxUnavailable
+	}
+	if errs := validation.ValidateWorkerRollingUpdate(maxSurge, maxUnavailable, pool.Minimum, pool.Maximum, field.NewPath("pools").Key(pool.Name)); len(errs) > 0 {
+		return nil, fmt.Errorf("invalid rolling update configuration of worker pool %s: %v", pool.Name, errs.ToAggregate())
+	}
+	if workerConfig.KubeletConfig != nil {
+		if errs := validation.ValidateWorkerKubeletConfig(workerConfig.KubeletConfig, field.NewPath("pools").Key(pool.Name).Child("kubeletConfig"), w.shootKubeletConfigs(pool.Name)...); len(errs) > 0 {
+			return nil, fmt.Errorf("invalid kubelet configuration of worker pool %s: %v", pool.Name, errs.ToAggregate())
+		}
+	}
+
+	var machineImageID string
+	if customImage {
+		machineImageID = *workerConfig.ImageID
+	} else {
+		machineImageID, err = w.findMachineImage(ctx, imageName, imageVersion, w.worker.Spec.Region)
 		if err != nil {
-			return err
+			return nil, err
 		}
-		machineImages = appendMachineImage(machineImages, apisalicloud.MachineImage{
-			Name:    pool.MachineImage.Name,
-			Version: pool.MachineImage.Version,
+		config.machineImages = appendMachineImage(config.machineImages, apisalicloud.MachineImage{
+			Name:    imageName,
+			Version: imageVersion,
 			ID:      machineImageID,
 		})
+	}
 
-		volumeSize, err := worker.DiskSize(pool.Volume.Size)
+	encryptSystemDisk := workerConfig.SystemDisk != nil && workerConfig.SystemDisk.Encrypted
+	if encryptSystemDisk {
+		// Custom images have no name and version, hence their encrypted copies are cached under their ID.
+		encryptedImageName, encryptedImageVersion := imageName, imageVersion
+		if customImage {
+			encryptedImageName, encryptedImageVersion = machineImageID, ""
+		}
+		machineImageID, err = w.ensureEncryptedMachineImage(ctx, encryptedImageName, encryptedImageVersion, machineImageID, workerConfig.SystemDisk.KMSKeyID)
 		if err != nil {
-			return err
+			return nil, err
+		}
+		config.encryptedImageIDs.Insert(machineImageID)
+		if !customImage {
+			config.machineImages = appendMachineImage(config.machineImages, apisalicloud.MachineImage{
+				Name:      imageName,
+				Version:   imageVersion,
+				ID:        machineImageID,
+				Encrypted: &encryptSystemDisk,
+			})
 		}
+	}
 
-		for zoneIndex, zone := range pool.Zones {
-			nodesVSwitch, err := alicloudapihelper.FindVSwitchForPurposeAndZone(infrastructureStatus.VPC.VSwitches, alicloudapi.PurposeNodes, zone)
-			if err != nil {
-				return err
+	// The labels of the worker pool are added as tags so that they are also available on the instances, e.g. for
+	// topology information. The tags of the worker pool take precedence over the labels, and neither must
+	// overwrite the tags identifying the machines of the cluster.
+	tags := make(map[string]string, len(pool.Labels)+len(workerConfig.Tags)+2)
+	for key, value := range pool.Labels {
+		tags[key] = value
+	}
+	for key, value := range workerConfig.Tags {
+		tags[key] = value
+	}
+	tags[w.clusterTagKey()] = "1"
+	tags[fmt.Sprintf("kubernetes.io/role/worker/%s", w.worker.Namespace)] = "1"
+	if len(tags) > alicloud.MaxTagsPerResource {
+		return nil, fmt.Errorf("the labels and tags of worker pool %s result in %d instance tags, but Alicloud allows at most %d", pool.Name, len(tags), alicloud.MaxTagsPerResource)
+	}
+
+	var dataDisks []map[string]interface{}
+	for _, dataVolume := range workerConfig.DataVolumes {
+		dataVolumeSize, err := worker.DiskSize(dataVolume.Size)
+		if err != nil {
+			return nil, err
+		}
+
+		dataDisk := map[string]interface{}{
+			"name": dataVolume.Name,
+			"size": dataVolumeSize,
+		}
+		if dataVolume.Type != nil {
+			dataDisk["category"] = *dataVolume.Type
+		}
+		if dataVolume.PerformanceLevel != nil {
+			dataDisk["performanceLevel"] = *dataVolume.PerformanceLevel
+		}
+		dataDisks = append(dataDisks, dataDisk)
+	}
+
+	// Additional user data is run before the user data of Gardener, e.g. to prepare the machine for the kubelet.
+	var userDataParts [][]byte
+	if workerConfig.UseLocalDisk {
+		if !alicloudapihelper.HasLocalNVMeDisks(pool.MachineType) {
+			return nil, fmt.Errorf("machine type %s of worker pool %s has no local NVMe disks", pool.MachineType, pool.Name)
+		}
+		userDataParts = append(userDataParts, []byte(localDisksScript))
+	}
+	gpuInstances := alicloudapihelper.IsGPUInstanceType(pool.MachineType)
+	if gpuInstances {
+		gpuDriverVersion := defaultGPUDriverVersion
+		if workerConfig.GPUDriverVersion != nil {
+			gpuDriverVersion = *workerConfig.GPUDriverVersion
+		}
+		userDataParts = append(userDataParts, []byte(gpuDriverScript(gpuDriverVersion)))
+	} else if workerConfig.GPUDriverVersion != nil {
+		return nil, fmt.Errorf("machine type %s of worker pool %s has no GPUs", pool.MachineType, pool.Name)
+	}
+	if workerConfig.KubeletConfig != nil {
+		if args := kubeletExtraArgs(workerConfig.KubeletConfig); len(args) > 0 {
+			userDataParts = append(userDataParts, []byte(kubeletExtraArgsScript(args)))
+		}
+	}
+	if workerConfig.UserData != nil {
+		userDataParts = append(userDataParts, []byte(*workerConfig.UserData))
+	}
+	userData := string(pool.UserData)
+	if len(userDataParts) > 0 {
+		if userData, err = combineUserData(append(userDataParts, pool.UserData)...); err != nil {
+			return nil, fmt.Errorf("could not combine the user data of worker pool %s: %v", pool.Name, err)
+		}
+		if len(userData) > alicloud.MaxUserDataSize {
+			return nil, fmt.Errorf("the combined user data of worker pool %s has %d bytes, but Alicloud allows at most %d bytes", pool.Name, len(userData), alicloud.MaxUserDataSize)
+		}
+	}
+
+	if workerConfig.DeploymentSetID != nil {
+		if err := w.checkDeploymentSet(ctx, pool, *workerConfig.DeploymentSetID, workerConfig.PlacementStrategy); err != nil {
+			return nil, err
+		}
+	}
+
+	spotStrategy := spotStrategyNoSpot
+	if workerConfig.SpotStrategy != nil {
+		spotStrategy = string(*workerConfig.SpotStrategy)
+	}
+
+	instanceChargeType := alicloudapi.InstanceChargeTypePostPaid
+	if workerConfig.InstanceChargeType != nil {
+		instanceChargeType = *workerConfig.InstanceChargeType
+	}
+
+	volumeSize, err := worker.DiskSize(pool.Volume.Size)
+	if err != nil {
+		return nil, err
+	}
+
+	// Machines of a pool are distributed over all nodes vswitches of its zones. The first vswitch of a zone keeps
+	// the zone-based deployment name to not recreate the machines of existing pools.
+	type zoneVSwitch struct {
+		zone          string
+		vswitch       alicloudapi.VSwitch
+		vswitchSuffix string
+	}
+	var zoneVSwitches []zoneVSwitch
+	for _, zone := range pool.Zones {
+		nodesVSwitches, err := alicloudapihelper.FindVSwitchesForPurposeAndZone(infrastructureStatus.VPC.VSwitches, alicloudapi.PurposeNodes, zone)
+		if err != nil {
+			return nil, err
+		}
+		for vswitchIndex, nodesVSwitch := range nodesVSwitches {
+			var vswitchSuffix string
+			if vswitchIndex > 0 {
+				vswitchSuffix = fmt.Sprintf("-%d", vswitchIndex)
 			}
+			zoneVSwitches = append(zoneVSwitches, zoneVSwitch{zone, nodesVSwitch, vswitchSuffix})
+		}
+	}
+	zoneVSwitchLen := len(zoneVSwitches)
+
+	for zoneVSwitchIndex, zoneVSwitch := range zoneVSwitches {
+		zone := zoneVSwitch.zone
 
-			systemDisk := map[string]interface{}{
-				"size": volumeSize,
+		labels := pool.Labels
+		if workerConfig.SecondaryENIs || gpuInstances {
+			labels = make(map[string]string, len(pool.Labels)+3)
+			for key, value := range pool.Labels {
+				labels[key] = value
 			}
-			if pool.Volume.Type != nil {
-				systemDisk["category"] = *pool.Volume.Type
+		}
+		// The secondary ENIs of the pods are created by the network plugin in the pods vswitch of the zone, which
+		// it finds in the labels of the nodes.
+		if workerConfig.SecondaryENIs {
+			podsVSwitch, err := alicloudapihelper.FindVSwitchForPurposeAndZone(infrastructureStatus.VPC.VSwitches, alicloudapi.PurposePods, zone)
+			if err != nil {
+				return nil, fmt.Errorf("worker pool %s requires secondary ENIs: %v", pool.Name, err)
 			}
+			labels[alicloud.LabelSecondaryENIs] = "true"
+			labels[alicloud.LabelPodsVSwitch] = podsVSwitch.ID
+		}
+		// The NVIDIA device plugin only runs on the nodes whose driver is installed by the extension.
+		if gpuInstances {
+			labels[alicloud.LabelGPU] = "true"
+		}
 
-			machineClassSpec := map[string]interface{}{
-				"imageID":                 machineImageID,
-				"instanceType":            pool.MachineType,
-				"region":                  w.worker.Spec.Region,
-				"zoneID":                  zone,
-				"securityGroupID":         nodesSecurityGroup.ID,
-				"vSwitchID":               nodesVSwitch.ID,
-				"systemDisk":              systemDisk,
-				"instanceChargeType":      "PostPaid",
-				"internetChargeType":      "PayByTraffic",
-				"internetMaxBandwidthIn":  5,
-				"internetMaxBandwidthOut": 5,
-				"spotStrategy":            "NoSpot",
-				"tags": map[string]string{
-					fmt.Sprintf("kubernetes.io/cluster/%s", w.worker.Namespace):     "1",
-					fmt.Sprintf("kubernetes.io/role/worker/%s", w.worker.Namespace): "1",
-				},
-				"secret": map[string]interface{}{
-					"userData": string(pool.UserData),
-				},
-				"keyPairName": infrastructureStatus.KeyPairName,
+		systemDisk := map[string]interface{}{
+			"size": volumeSize,
+		}
+		if pool.Volume.Type != nil {
+			systemDisk["category"] = *pool.Volume.Type
+		}
+		if encryptSystemDisk {
+			systemDisk["encrypted"] = true
+			if kmsKeyID := workerConfig.SystemDisk.KMSKeyID; kmsKeyID != nil {
+				systemDisk["kmsKeyID"] = *kmsKeyID
 			}
+		}
 
-			var (
-				deploymentName = fmt.Sprintf("%s-%s-%s", w.worker.Namespace, pool.Name, zone)
-				className      = fmt.Sprintf("%s-%s", deploymentName, workerPoolHash)
-			)
-
-			machineDeployments = append(machineDeployments, worker.MachineDeployment{
-				Name:           deploymentName,
-				ClassName:      className,
-				SecretName:     className,
-				Minimum:        worker.DistributeOverZones(zoneIndex, pool.Minimum, zoneLen),
-				Maximum:        worker.DistributeOverZones(zoneIndex, pool.Maximum, zoneLen),
-				MaxSurge:       worker.DistributePositiveIntOrPercent(zoneIndex, pool.MaxSurge, zoneLen, pool.Maximum),
-				MaxUnavailable: worker.DistributePositiveIntOrPercent(zoneIndex, pool.MaxUnavailable, zoneLen, pool.Minimum),
-				Labels:         pool.Labels,
-				Annotations:    pool.Annotations,
-				Taints:         pool.Taints,
-			})
+		machineClassSpec := map[string]interface{}{
+			"imageID":                 machineImageID,
+			"instanceType":            pool.MachineType,
+			"region":                  w.worker.Spec.Region,
+			"zoneID":                  zone,
+			"securityGroupID":         nodesSecurityGroupID,
+			"vSwitchID":               zoneVSwitch.vswitch.ID,
+			"systemDisk":              systemDisk,
+			"instanceChargeType":      string(instanceChargeType),
+			"internetChargeType":      "PayByTraffic",
+			"internetMaxBandwidthIn":  5,
+			"internetMaxBandwidthOut": 5,
+			"spotStrategy":            spotStrategy,
+			"tags":                    tags,
+			"secret": map[string]interface{}{
+				"userData": userData,
+			},
+			"keyPairName": infrastructureStatus.KeyPairName,
+		}
 
-			machineClassSpec["name"] = className
-			machineClassSpec["labels"] = map[string]string{
-				v1beta1constants.GardenPurpose: genericworkeractuator.GardenPurposeMachineClass,
+		if len(dataDisks) > 0 {
+			// Alicloud only attaches disks to instances of the same zone, hence the data disks are pinned to the
+			// zone of the machine deployment.
+			zoneDataDisks := make([]map[string]interface{}, 0, len(dataDisks))
+			for _, dataDisk := range dataDisks {
+				zoneDataDisk := make(map[string]interface{}, len(dataDisk)+1)
+				for key, value := range dataDisk {
+					zoneDataDisk[key] = value
+				}
+				zoneDataDisk["zoneID"] = zone
+				zoneDataDisks = append(zoneDataDisks, zoneDataDisk)
 			}
-			machineClassSpec["secret"].(map[string]interface{})[alicloud.AccessKeyID] = string(machineClassSecretData[machinev1alpha1.AlicloudAccessKeyID])
-			machineClassSpec["secret"].(map[string]interface{})[alicloud.AccessKeySecret] = string(machineClassSecretData[machinev1alpha1.AlicloudAccessKeySecret])
+			machineClassSpec["dataDisks"] = zoneDataDisks
+		}
+		if len(workerConfig.SecurityGroupIDs) > 0 {
+			// The security group managed by Gardener is always attached for the traffic within the cluster.
+			machineClassSpec["securityGroupIDs"] = append([]string{nodesSecurityGroupID}, workerConfig.SecurityGroupIDs...)
+		}
+		if workerConfig.RAMRoleName != nil {
+			machineClassSpec["ramRoleName"] = *workerConfig.RAMRoleName
+		}
+		if workerConfig.DeploymentSetID != nil {
+			machineClassSpec["deploymentSetID"] = *workerConfig.DeploymentSetID
+		}
+		if workerConfig.SpotPriceLimit != nil {
+			machineClassSpec["spotPriceLimit"] = *workerConfig.SpotPriceLimit
+		}
+		if instanceChargeType == alicloudapi.InstanceChargeTypePrePaid {
+			period, periodUnit := int32(1), alicloudapi.PeriodUnitMonth
+			if workerConfig.Period != nil {
+				period = *workerConfig.Period
+			}
+			if workerConfig.PeriodUnit != nil {
+				periodUnit = *workerConfig.PeriodUnit
+			}
+			machineClassSpec["period"] = period
+			machineClassSpec["periodUnit"] = string(periodUnit)
 
-			machineClasses = append(machineClasses, machineClassSpec)
+			if workerConfig.AutoRenew {
+				autoRenewPeriod := int32(1)
+				if workerConfig.AutoRenewPeriod != nil {
+					autoRenewPeriod = *workerConfig.AutoRenewPeriod
+				}
+				machineClassSpec["autoRenew"] = true
+				machineClassSpec["autoRenewPeriod"] = autoRenewPeriod
+			}
 		}
-	}
 
-	w.machineDeployments = machineDeployments
-	w.machineClasses = machineClasses
-	w.machineImages = machineImages
+		var (
+			deploymentName = fmt.Sprintf("%s-%s-%s%s", w.worker.Namespace, pool.Name, zone, zoneVSwitch.vswitchSuffix)
+			className      = fmt.Sprintf("%s-%s", deploymentName, workerPoolHash)
+		)
 
-	return nil
+		config.machineDeployments = append(config.machineDeployments, worker.MachineDeployment{
+			Name:           deploymentName,
+			ClassName:      className,
+			SecretName:     className,
+			Minimum:        worker.DistributeOverZones(zoneVSwitchIndex, pool.Minimum, zoneVSwitchLen),
+			Maximum:        worker.DistributeOverZones(zoneVSwitchIndex, pool.Maximum, zoneVSwitchLen),
+			MaxSurge:       worker.DistributePositiveIntOrPercent(zoneVSwitchIndex, maxSurge, zoneVSwitchLen, pool.Maximum),
+			MaxUnavailable: worker.DistributePositiveIntOrPercent(zoneVSwitchIndex, maxUnavailable, zoneVSwitchLen, pool.Minimum),
+			Labels:         labels,
+			Annotations:    pool.Annotations,
+			Taints:         pool.Taints,
+		})
+
+		machineClassSpec["name"] = className
+		machineClassSpec["labels"] = map[string]string{
+			v1beta1constants.GardenPurpose: genericworkeractuator.GardenPurposeMachineClass,
+		}
+		machineClassSpec["secret"].(map[string]interface{})[alicloud.AccessKeyID] = string(machineClassSecretData[machinev1alpha1.AlicloudAccessKeyID])
+		machineClassSpec["secret"].(map[string]interface{})[alicloud.AccessKeySecret] = string(machineClassSecretData[machinev1alpha1.AlicloudAccessKeySecret])
+
+		config.machineClasses = append(config.machineClasses, machineClassSpec)
+	}
+
+	return config, nil
 }
@@ -19,11 +19,15 @@ import (
 	"encoding/json"
 	"fmt"
 	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
 
 	"github.com/gardener/gardener-extension-provider-alicloud/pkg/alicloud"
 	api "github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/alicloud"
 	apiv1alpha1 "github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/alicloud/v1alpha1"
 	. "github.com/gardener/gardener-extension-provider-alicloud/pkg/controller/worker"
+	mockalicloudclient "github.com/gardener/gardener-extension-provider-alicloud/pkg/mock/provider-alicloud/alicloud/client"
 	extensionscontroller "github.com/gardener/gardener-extensions/pkg/controller"
 	"github.com/gardener/gardener-extensions/pkg/controller/common"
 	"github.com/gardener/gardener-extensions/pkg/controller/worker"
@@ -31,6 +35,7 @@ import (
 	mockclient "github.com/gardener/gardener-extensions/pkg/mock/controller-runtime/client"
 	mockkubernetes "github.com/gardener/gardener-extensions/pkg/mock/gardener/client/kubernetes"
 
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/ecs"
 	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
 	v1beta1constants "github.com/gardener/gardener/pkg/apis/core/v1beta1/constants"
 	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
@@ -43,14 +48,18 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
 )
 
 var _ = Describe("Machines", func() {
 	var (
-		ctrl         *gomock.Controller
-		c            *mockclient.MockClient
-		chartApplier *mockkubernetes.MockChartApplier
+		ctrl                  *gomock.Controller
+		c                     *mockclient.MockClient
+		chartApplier          *mockkubernetes.MockChartApplier
+		alicloudClientFactory *mockalicloudclient.MockClientFactory
+		ecsClient             *mockalicloudclient.MockECS
 	)
 
 	BeforeEach(func() {
@@ -58,6 +67,8 @@ var _ = Describe("Machines", func() {
 
 		c = mockclient.NewMockClient(ctrl)
 		chartApplier = mockkubernetes.NewMockChartApplier(ctrl)
+		alicloudClientFactory = mockalicloudclient.NewMockClientFactory(ctrl)
+		ecsClient = mockalicloudclient.NewMockECS(ctrl)
 	})
 
 	AfterEach(func() {
@@ -65,7 +76,7 @@ var _ = Describe("Machines", func() {
 	})
 
 	Context("workerDelegate", func() {
-		workerDelegate, _ := NewWorkerDelegate(common.NewClientContext(nil, nil, nil), nil, "", nil, nil)
+		workerDelegate, _ := NewWorkerDelegate(common.NewClientContext(nil, nil, nil), nil, nil, nil, nil, "", nil, nil)
 
 		Describe("#MachineClassKind", func() {
 			It("should return the correct kind of the machine class", func() {
@@ -320,7 +331,7 @@ var _ = Describe("Machines", func() {
 				workerPoolHash1, _ = worker.WorkerPoolHash(w.Spec.Pools[0], cluster)
 				workerPoolHash2, _ = worker.WorkerPoolHash(w.Spec.Pools[1], cluster)
 
-				workerDelegate, _ = NewWorkerDelegate(common.NewClientContext(c, scheme, decoder), chartApplier, "", w, clusterWithoutImages)
+				workerDelegate, _ = NewWorkerDelegate(common.NewClientContext(c, scheme, decoder), alicloudClientFactory, nil, nil, chartApplier, "", w, clusterWithoutImages)
 			})
 
 			Describe("machine images", func() {
@@ -426,7 +437,7 @@ var _ = Describe("Machines", func() {
 				})
 
 				It("should return the expected machine deployments for profile image types", func() {
-					workerDelegate, _ = NewWorkerDelegate(common.NewClientContext(c, scheme, decoder), chartApplier, "", w, cluster)
+					workerDelegate, _ = NewWorkerDelegate(common.NewClientContext(c, scheme, decoder), alicloudClientFactory, nil, nil, chartApplier, "", w, cluster)
 
 					expectGetSecretCallToWork(c, alicloudAccessKeyID, alicloudAccessKeySecret)
 
@@ -470,6 +481,1281 @@ var _ = Describe("Machines", func() {
 					Expect(err).NotTo(HaveOccurred())
 					Expect(result).To(Equal(machineDeployments))
 				})
+
+				It("should merge the labels and tags of the worker pool with the tags identifying the machines", func() {
+					clusterTagKey := fmt.Sprintf("kubernetes.io/cluster/%s", namespace)
+					w.Spec.Pools[0].Labels = map[string]string{
+						"example.com/rack": "r1",
+						"cmdb-id":          "0815",
+					}
+					w.Spec.Pools[0].ProviderConfig = &runtime.RawExtension{
+						Raw: encode(&apiv1alpha1.WorkerConfig{
+							TypeMeta: metav1.TypeMeta{
+								APIVersion: apiv1alpha1.SchemeGroupVersion.String(),
+								Kind:       "WorkerConfig",
+							},
+							Tags: map[string]string{
+								"cmdb-id":     "4711",
+								clusterTagKey: "foo",
+							},
+						}),
+					}
+					workerDelegate, _ = NewWorkerDelegate(common.NewClientContext(c, scheme, decoder), alicloudClientFactory, nil, nil, chartApplier, "", w, cluster)
+
+					expectGetSecretCallToWork(c, alicloudAccessKeyID, alicloudAccessKeySecret)
+
+					chartApplier.
+						EXPECT().
+						ApplyChart(
+							context.TODO(),
+							filepath.Join(alicloud.InternalChartsPath, "machineclass"),
+							namespace,
+							"machineclass",
+							gomock.Any(),
+							nil,
+						).
+						DoAndReturn(func(_ context.Context, _, _, _ string, values map[string]interface{}, _ interface{}) error {
+							machineClasses := values["machineClasses"].([]map[string]interface{})
+							for _, machineClass := range machineClasses[:2] {
+								Expect(machineClass["tags"]).To(Equal(map[string]string{
+									"example.com/rack": "r1",
+									"cmdb-id":          "4711",
+									clusterTagKey:      "1",
+									fmt.Sprintf("kubernetes.io/role/worker/%s", namespace): "1",
+								}))
+							}
+							for _, machineClass := range machineClasses[2:] {
+								Expect(machineClass["tags"]).To(Equal(defaultMachineClass["tags"]))
+							}
+							return nil
+						})
+
+					Expect(workerDelegate.DeployMachineClasses(context.TODO())).To(Succeed())
+				})
+
+				It("should use the custom image of the worker pool without resolving its machine image", func() {
+					customImageID := "m-custom"
+					for i := range w.Spec.Pools {
+						w.Spec.Pools[i].ProviderConfig = &runtime.RawExtension{
+							Raw: encode(&apiv1alpha1.WorkerConfig{
+								TypeMeta: metav1.TypeMeta{
+									APIVersion: apiv1alpha1.SchemeGroupVersion.String(),
+									Kind:       "WorkerConfig",
+								},
+								ImageID: &customImageID,
+							}),
+						}
+					}
+					workerDelegate, _ = NewWorkerDelegate(common.NewClientContext(c, scheme, decoder), alicloudClientFactory, nil, nil, chartApplier, "", w, clusterWithoutImages)
+
+					expectGetSecretCallToWork(c, alicloudAccessKeyID, alicloudAccessKeySecret)
+
+					machineImages, err := workerDelegate.GetMachineImages(context.TODO())
+					Expect(err).NotTo(HaveOccurred())
+					Expect(machineImages.(*apiv1alpha1.WorkerStatus).MachineImages).To(BeEmpty())
+
+					chartApplier.
+						EXPECT().
+						ApplyChart(
+							context.TODO(),
+							filepath.Join(alicloud.InternalChartsPath, "machineclass"),
+							namespace,
+							"machineclass",
+							gomock.Any(),
+							nil,
+						).
+						DoAndReturn(func(_ context.Context, _, _, _ string, values map[string]interface{}, _ interface{}) error {
+							for _, machineClass := range values["machineClasses"].([]map[string]interface{}) {
+								Expect(machineClass).To(HaveKeyWithValue("imageID", customImageID))
+							}
+							return nil
+						})
+
+					Expect(workerDelegate.DeployMachineClasses(context.TODO())).To(Succeed())
+				})
+
+				It("should configure the data volumes of the worker pool in the zones of the machines", func() {
+					essd := "cloud_essd"
+					performanceLevel := "PL2"
+					w.Spec.Pools[0].ProviderConfig = &runtime.RawExtension{
+						Raw: encode(&apiv1alpha1.WorkerConfig{
+							TypeMeta: metav1.TypeMeta{
+								APIVersion: apiv1alpha1.SchemeGroupVersion.String(),
+								Kind:       "WorkerConfig",
+							},
+							DataVolumes: []apiv1alpha1.DataVolume{
+								{Name: "data", Type: &essd, Size: "100Gi", PerformanceLevel: &performanceLevel},
+								{Name: "scratch", Size: "20Gi"},
+							},
+						}),
+					}
+					workerDelegate, _ = NewWorkerDelegate(common.NewClientContext(c, scheme, decoder), alicloudClientFactory, nil, nil, chartApplier, "", w, cluster)
+
+					expectGetSecretCallToWork(c, alicloudAccessKeyID, alicloudAccessKeySecret)
+
+					chartApplier.
+						EXPECT().
+						ApplyChart(
+							context.TODO(),
+							filepath.Join(alicloud.InternalChartsPath, "machineclass"),
+							namespace,
+							"machineclass",
+							gomock.Any(),
+							nil,
+						).
+						DoAndReturn(func(_ context.Context, _, _, _ string, values map[string]interface{}, _ interface{}) error {
+							machineClasses := values["machineClasses"].([]map[string]interface{})
+							for i, zone := range []string{zone1, zone2} {
+								Expect(machineClasses[i]).To(HaveKeyWithValue("zoneID", zone))
+								Expect(machineClasses[i]).To(HaveKeyWithValue("dataDisks", []map[string]interface{}{
+									{"name": "data", "category": essd, "size": 100, "performanceLevel": performanceLevel, "zoneID": zone},
+									{"name": "scratch", "size": 20, "zoneID": zone},
+								}))
+							}
+							for _, machineClass := range machineClasses[2:] {
+								Expect(machineClass).NotTo(HaveKey("dataDisks"))
+							}
+							return nil
+						})
+
+					Expect(workerDelegate.DeployMachineClasses(context.TODO())).To(Succeed())
+				})
+
+				It("should attach the additional security groups of the worker pool", func() {
+					additionalSecurityGroupIDs := []string{"sg-1234", "sg-5678"}
+					w.Spec.Pools[0].ProviderConfig = &runtime.RawExtension{
+						Raw: encode(&apiv1alpha1.WorkerConfig{
+							TypeMeta: metav1.TypeMeta{
+								APIVersion: apiv1alpha1.SchemeGroupVersion.String(),
+								Kind:       "WorkerConfig",
+							},
+							SecurityGroupIDs: additionalSecurityGroupIDs,
+						}),
+					}
+					workerDelegate, _ = NewWorkerDelegate(common.NewClientContext(c, scheme, decoder), alicloudClientFactory, nil, nil, chartApplier, "", w, cluster)
+
+					expectGetSecretCallToWork(c, alicloudAccessKeyID, alicloudAccessKeySecret)
+
+					chartApplier.
+						EXPECT().
+						ApplyChart(
+							context.TODO(),
+							filepath.Join(alicloud.InternalChartsPath, "machineclass"),
+							namespace,
+							"machineclass",
+							gomock.Any(),
+							nil,
+						).
+						DoAndReturn(func(_ context.Context, _, _, _ string, values map[string]interface{}, _ interface{}) error {
+							machineClasses := values["machineClasses"].([]map[string]interface{})
+							for _, machineClass := range machineClasses[:2] {
+								Expect(machineClass).To(HaveKeyWithValue("securityGroupIDs", append([]string{securityGroupID}, additionalSecurityGroupIDs...)))
+							}
+							for _, machineClass := range machineClasses[2:] {
+								Expect(machineClass).NotTo(HaveKey("securityGroupIDs"))
+							}
+							return nil
+						})
+
+					Expect(workerDelegate.DeployMachineClasses(context.TODO())).To(Succeed())
+				})
+
+				It("should attach the RAM role of the worker pool", func() {
+					ramRoleName := "my-role"
+					w.Spec.Pools[0].ProviderConfig = &runtime.RawExtension{
+						Raw: encode(&apiv1alpha1.WorkerConfig{
+							TypeMeta: metav1.TypeMeta{
+								APIVersion: apiv1alpha1.SchemeGroupVersion.String(),
+								Kind:       "WorkerConfig",
+							},
+							RAMRoleName: &ramRoleName,
+						}),
+					}
+					workerDelegate, _ = NewWorkerDelegate(common.NewClientContext(c, scheme, decoder), alicloudClientFactory, nil, nil, chartApplier, "", w, cluster)
+
+					expectGetSecretCallToWork(c, alicloudAccessKeyID, alicloudAccessKeySecret)
+
+					chartApplier.
+						EXPECT().
+						ApplyChart(
+							context.TODO(),
+							filepath.Join(alicloud.InternalChartsPath, "machineclass"),
+							namespace,
+							"machineclass",
+							gomock.Any(),
+							nil,
+						).
+						DoAndReturn(func(_ context.Context, _, _, _ string, values map[string]interface{}, _ interface{}) error {
+							machineClasses := values["machineClasses"].([]map[string]interface{})
+							for _, machineClass := range machineClasses[:2] {
+								Expect(machineClass).To(HaveKeyWithValue("ramRoleName", ramRoleName))
+							}
+							for _, machineClass := range machineClasses[2:] {
+								Expect(machineClass).NotTo(HaveKey("ramRoleName"))
+							}
+							return nil
+						})
+
+					Expect(workerDelegate.DeployMachineClasses(context.TODO())).To(Succeed())
+				})
+
+				Context("default machine image", func() {
+					BeforeEach(func() {
+						cloudProfileConfigJSON, _ := json.Marshal(&apiv1alpha1.CloudProfileConfig{
+							TypeMeta: metav1.TypeMeta{
+								APIVersion: apiv1alpha1.SchemeGroupVersion.String(),
+								Kind:       "CloudProfileConfig",
+							},
+							MachineImages: []apiv1alpha1.MachineImages{
+								{
+									Name: machineImageName,
+									Versions: []apiv1alpha1.MachineImageVersion{
+										{
+											Version: machineImageVersion,
+											Regions: []apiv1alpha1.RegionIDMapping{{Name: region, ID: machineImageID}},
+										},
+									},
+								},
+							},
+							DefaultMachineImages: []apiv1alpha1.DefaultMachineImage{
+								{Region: region, Name: machineImageName, Version: machineImageVersion},
+							},
+						})
+						cluster.CloudProfile.Spec.ProviderConfig.Raw = cloudProfileConfigJSON
+					})
+
+					It("should use the default machine image of the region for worker pools without machine image", func() {
+						w.Spec.Pools[0].MachineImage = extensionsv1alpha1.MachineImage{}
+						workerDelegate, _ = NewWorkerDelegate(common.NewClientContext(c, scheme, decoder), alicloudClientFactory, nil, nil, chartApplier, "", w, cluster)
+
+						expectGetSecretCallToWork(c, alicloudAccessKeyID, alicloudAccessKeySecret)
+
+						result, err := workerDelegate.GenerateMachineDeployments(context.TODO())
+						Expect(err).NotTo(HaveOccurred())
+
+						workerPoolHash, _ := worker.WorkerPoolHash(w.Spec.Pools[0], cluster, machineImageName+machineImageVersion)
+						Expect(result[0].ClassName).To(Equal(fmt.Sprintf("%s-%s-%s-%s", namespace, namePool1, zone1, workerPoolHash)))
+						Expect(workerPoolHash).NotTo(Equal(workerPoolHash1))
+
+						machineImages, err := workerDelegate.GetMachineImages(context.TODO())
+						Expect(err).NotTo(HaveOccurred())
+						Expect(machineImages.(*apiv1alpha1.WorkerStatus).MachineImages).To(Equal([]apiv1alpha1.MachineImage{
+							{Name: machineImageName, Version: machineImageVersion, ID: machineImageID},
+						}))
+					})
+
+					It("should use the default version of the machine image for worker pools without version", func() {
+						w.Spec.Pools[0].MachineImage.Version = ""
+						workerDelegate, _ = NewWorkerDelegate(common.NewClientContext(c, scheme, decoder), alicloudClientFactory, nil, nil, chartApplier, "", w, cluster)
+
+						expectGetSecretCallToWork(c, alicloudAccessKeyID, alicloudAccessKeySecret)
+
+						_, err := workerDelegate.GenerateMachineDeployments(context.TODO())
+						Expect(err).NotTo(HaveOccurred())
+					})
+
+					It("should fail for worker pools without machine image in regions without default", func() {
+						w.Spec.Pools[0].MachineImage = extensionsv1alpha1.MachineImage{}
+						w.Spec.Region = "other-region"
+						workerDelegate, _ = NewWorkerDelegate(common.NewClientContext(c, scheme, decoder), alicloudClientFactory, nil, nil, chartApplier, "", w, cluster)
+
+						expectGetSecretCallToWork(c, alicloudAccessKeyID, alicloudAccessKeySecret)
+
+						_, err := workerDelegate.GenerateMachineDeployments(context.TODO())
+						Expect(err).To(MatchError(fmt.Sprintf("worker pool %s has no machine image and region other-region has no default machine image", namePool1)))
+					})
+				})
+
+				Context("rolling update", func() {
+					It("should overwrite the max surge and max unavailable of the worker pool", func() {
+						maxSurge, maxUnavailable := intstr.FromString("50%"), intstr.FromInt(0)
+						w.Spec.Pools[0].ProviderConfig = &runtime.RawExtension{
+							Raw: encode(&apiv1alpha1.WorkerConfig{
+								TypeMeta: metav1.TypeMeta{
+									APIVersion: apiv1alpha1.SchemeGroupVersion.String(),
+									Kind:       "WorkerConfig",
+								},
+								MaxSurge:       &maxSurge,
+								MaxUnavailable: &maxUnavailable,
+							}),
+						}
+						workerDelegate, _ = NewWorkerDelegate(common.NewClientContext(c, scheme, decoder), alicloudClientFactory, nil, nil, chartApplier, "", w, cluster)
+
+						expectGetSecretCallToWork(c, alicloudAccessKeyID, alicloudAccessKeySecret)
+
+						result, err := workerDelegate.GenerateMachineDeployments(context.TODO())
+						Expect(err).NotTo(HaveOccurred())
+						for _, machineDeployment := range result[:2] {
+							Expect(machineDeployment.MaxSurge).To(Equal(maxSurge))
+							Expect(machineDeployment.MaxUnavailable).To(Equal(intstr.FromInt(0)))
+						}
+						Expect(result[2].MaxSurge).To(Equal(worker.DistributePositiveIntOrPercent(0, maxSurgePool2, 2, maxPool2)))
+						Expect(result[2].MaxUnavailable).To(Equal(worker.DistributePositiveIntOrPercent(0, maxUnavailablePool2, 2, minPool2)))
+					})
+
+					It("should not change the machine class names if only the max surge and max unavailable change", func() {
+						workerConfig := &apiv1alpha1.WorkerConfig{
+							TypeMeta: metav1.TypeMeta{
+								APIVersion: apiv1alpha1.SchemeGroupVersion.String(),
+								Kind:       "WorkerConfig",
+							},
+							Tags: map[string]string{"team": "a"},
+						}
+						w.Spec.Pools[0].ProviderConfig = &runtime.RawExtension{Raw: encodeSorted(workerConfig)}
+						workerDelegate, _ = NewWorkerDelegate(common.NewClientContext(c, scheme, decoder), alicloudClientFactory, nil, nil, chartApplier, "", w, cluster)
+
+						expectGetSecretCallToWork(c, alicloudAccessKeyID, alicloudAccessKeySecret)
+
+						result, err := workerDelegate.GenerateMachineDeployments(context.TODO())
+						Expect(err).NotTo(HaveOccurred())
+
+						maxSurge, maxUnavailable := intstr.FromString("50%"), intstr.FromInt(0)
+						workerConfig.MaxSurge, workerConfig.MaxUnavailable = &maxSurge, &maxUnavailable
+						w.Spec.Pools[0].ProviderConfig = &runtime.RawExtension{Raw: encodeSorted(workerConfig)}
+						workerDelegate, _ = NewWorkerDelegate(common.NewClientContext(c, scheme, decoder), alicloudClientFactory, nil, nil, chartApplier, "", w, cluster)
+
+						expectGetSecretCallToWork(c, alicloudAccessKeyID, alicloudAccessKeySecret)
+
+						resultWithRollout, err := workerDelegate.GenerateMachineDeployments(context.TODO())
+						Expect(err).NotTo(HaveOccurred())
+						Expect(resultWithRollout[0].ClassName).To(Equal(result[0].ClassName))
+						Expect(resultWithRollout[0].MaxSurge).NotTo(Equal(result[0].MaxSurge))
+					})
+
+					It("should fail if the rolling update could not make progress", func() {
+						maxSurge := intstr.FromInt(0)
+						w.Spec.Pools[0].ProviderConfig = &runtime.RawExtension{
+							Raw: encode(&apiv1alpha1.WorkerConfig{
+								TypeMeta: metav1.TypeMeta{
+									APIVersion: apiv1alpha1.SchemeGroupVersion.String(),
+									Kind:       "WorkerConfig",
+								},
+								MaxSurge: &maxSurge,
+							}),
+						}
+						w.Spec.Pools[0].MaxUnavailable = intstr.FromString("10%")
+						workerDelegate, _ = NewWorkerDelegate(common.NewClientContext(c, scheme, decoder), alicloudClientFactory, nil, nil, chartApplier, "", w, cluster)
+
+						expectGetSecretCallToWork(c, alicloudAccessKeyID, alicloudAccessKeySecret)
+
+						_, err := workerDelegate.GenerateMachineDeployments(context.TODO())
+						Expect(err).To(MatchError(ContainSubstring("invalid rolling update configuration of worker pool")))
+					})
+				})
+
+				Context("secondary ENIs", func() {
+					BeforeEach(func() {
+						w.Spec.Pools[0].Labels = map[string]string{"example.com/rack": "r1"}
+						w.Spec.Pools[0].ProviderConfig = &runtime.RawExtension{
+							Raw: encode(&apiv1alpha1.WorkerConfig{
+								TypeMeta: metav1.TypeMeta{
+									APIVersion: apiv1alpha1.SchemeGroupVersion.String(),
+									Kind:       "WorkerConfig",
+								},
+								SecondaryENIs: true,
+							}),
+						}
+					})
+
+					It("should label the nodes with the pods vswitches of their zones", func() {
+						w.Spec.InfrastructureProviderStatus = &runtime.RawExtension{
+							Raw: encode(&api.InfrastructureStatus{
+								VPC: api.VPCStatus{
+									VSwitches: []api.VSwitch{
+										{ID: vswitchZone1, Purpose: api.PurposeNodes, Zone: zone1},
+										{ID: vswitchZone2, Purpose: api.PurposeNodes, Zone: zone2},
+										{ID: "vsw-pods-1", Purpose: api.PurposePods, Zone: zone1},
+										{ID: "vsw-pods-2", Purpose: api.PurposePods, Zone: zone2},
+									},
+									SecurityGroups: []api.SecurityGroup{{ID: securityGroupID, Purpose: api.PurposeNodes}},
+								},
+								KeyPairName: keyName,
+							}),
+						}
+						workerDelegate, _ = NewWorkerDelegate(common.NewClientContext(c, scheme, decoder), alicloudClientFactory, nil, nil, chartApplier, "", w, cluster)
+
+						expectGetSecretCallToWork(c, alicloudAccessKeyID, alicloudAccessKeySecret)
+
+						result, err := workerDelegate.GenerateMachineDeployments(context.TODO())
+						Expect(err).NotTo(HaveOccurred())
+						Expect(result[0].Labels).To(Equal(map[string]string{
+							"example.com/rack":          "r1",
+							alicloud.LabelSecondaryENIs: "true",
+							alicloud.LabelPodsVSwitch:   "vsw-pods-1",
+						}))
+						Expect(result[1].Labels).To(Equal(map[string]string{
+							"example.com/rack":          "r1",
+							alicloud.LabelSecondaryENIs: "true",
+							alicloud.LabelPodsVSwitch:   "vsw-pods-2",
+						}))
+						Expect(w.Spec.Pools[0].Labels).To(Equal(map[string]string{"example.com/rack": "r1"}))
+						for _, machineDeployment := range result[2:] {
+							Expect(machineDeployment.Labels).NotTo(HaveKey(alicloud.LabelSecondaryENIs))
+						}
+					})
+
+					It("should fail if the zones have no pods vswitches", func() {
+						workerDelegate, _ = NewWorkerDelegate(common.NewClientContext(c, scheme, decoder), alicloudClientFactory, nil, nil, chartApplier, "", w, cluster)
+
+						expectGetSecretCallToWork(c, alicloudAccessKeyID, alicloudAccessKeySecret)
+
+						_, err := workerDelegate.GenerateMachineDeployments(context.TODO())
+						Expect(err).To(MatchError(ContainSubstring("requires secondary ENIs")))
+					})
+				})
+
+				Context("local disks", func() {
+					BeforeEach(func() {
+						w.Spec.Pools[0].ProviderConfig = &runtime.RawExtension{
+							Raw: encode(&apiv1alpha1.WorkerConfig{
+								TypeMeta: metav1.TypeMeta{
+									APIVersion: apiv1alpha1.SchemeGroupVersion.String(),
+									Kind:       "WorkerConfig",
+								},
+								UseLocalDisk: true,
+							}),
+						}
+						w.Spec.Pools[0].UserData = []byte("#cloud-config\nhostname: foo\n")
+					})
+
+					It("should add the script mounting the local disks to the user data", func() {
+						w.Spec.Pools[0].MachineType = "ecs.i2.xlarge"
+						workerDelegate, _ = NewWorkerDelegate(common.NewClientContext(c, scheme, decoder), alicloudClientFactory, nil, nil, chartApplier, "", w, cluster)
+
+						expectGetSecretCallToWork(c, alicloudAccessKeyID, alicloudAccessKeySecret)
+
+						chartApplier.
+							EXPECT().
+							ApplyChart(
+								context.TODO(),
+								filepath.Join(alicloud.InternalChartsPath, "machineclass"),
+								namespace,
+								"machineclass",
+								gomock.Any(),
+								nil,
+							).
+							DoAndReturn(func(_ context.Context, _, _, _ string, values map[string]interface{}, _ interface{}) error {
+								machineClasses := values["machineClasses"].([]map[string]interface{})
+								for _, machineClass := range machineClasses[:2] {
+									secretUserData := machineClass["secret"].(map[string]interface{})["userData"].(string)
+									Expect(secretUserData).To(HavePrefix("Content-Type: multipart/mixed"))
+									Expect(secretUserData).To(ContainSubstring("Content-Type: text/x-shellscript\r\n\r\n#!/bin/bash"))
+									Expect(secretUserData).To(ContainSubstring("mkfs.ext4"))
+									Expect(secretUserData).To(ContainSubstring("Content-Type: text/cloud-config\r\n\r\n#cloud-config\nhostname: foo\n"))
+								}
+								for _, machineClass := range machineClasses[2:] {
+									Expect(machineClass["secret"]).To(HaveKeyWithValue("userData", string(userData)))
+								}
+								return nil
+							})
+
+						Expect(workerDelegate.DeployMachineClasses(context.TODO())).To(Succeed())
+					})
+
+					It("should fail for machine types without local disks", func() {
+						w.Spec.Pools[0].MachineType = "ecs.g6.large"
+						workerDelegate, _ = NewWorkerDelegate(common.NewClientContext(c, scheme, decoder), alicloudClientFactory, nil, nil, chartApplier, "", w, cluster)
+
+						expectGetSecretCallToWork(c, alicloudAccessKeyID, alicloudAccessKeySecret)
+
+						_, err := workerDelegate.GenerateMachineDeployments(context.TODO())
+						Expect(err).To(HaveOccurred())
+					})
+				})
+
+				Context("GPU drivers", func() {
+					var gpuDriverVersion = "460.106.00"
+
+					BeforeEach(func() {
+						w.Spec.Pools[0].MachineType = "ecs.gn6i-c4g1.xlarge"
+						w.Spec.Pools[0].UserData = []byte("#cloud-config\nhostname: foo\n")
+					})
+
+					expectMachineClasses := func(version string) {
+						chartApplier.
+							EXPECT().
+							ApplyChart(
+								context.TODO(),
+								filepath.Join(alicloud.InternalChartsPath, "machineclass"),
+								namespace,
+								"machineclass",
+								gomock.Any(),
+								nil,
+							).
+							DoAndReturn(func(_ context.Context, _, _, _ string, values map[string]interface{}, _ interface{}) error {
+								machineClasses := values["machineClasses"].([]map[string]interface{})
+								for _, machineClass := range machineClasses[:2] {
+									secretUserData := machineClass["secret"].(map[string]interface{})["userData"].(string)
+									Expect(secretUserData).To(HavePrefix("Content-Type: multipart/mixed"))
+									Expect(secretUserData).To(ContainSubstring(fmt.Sprintf("#!/bin/bash\nset -o errexit\n\nversion=%q\n", version)))
+									Expect(secretUserData).To(ContainSubstring("NVIDIA-Linux-x86_64-${version}.run"))
+								}
+								for _, machineClass := range machineClasses[2:] {
+									Expect(machineClass["secret"]).To(HaveKeyWithValue("userData", string(userData)))
+								}
+								return nil
+							})
+					}
+
+					It("should only install the GPU driver on the machines of GPU instance types", func() {
+						w.Spec.Pools[0].ProviderConfig = &runtime.RawExtension{
+							Raw: encode(&apiv1alpha1.WorkerConfig{
+								TypeMeta: metav1.TypeMeta{
+									APIVersion: apiv1alpha1.SchemeGroupVersion.String(),
+									Kind:       "WorkerConfig",
+								},
+								GPUDriverVersion: &gpuDriverVersion,
+							}),
+						}
+						workerDelegate, _ = NewWorkerDelegate(common.NewClientContext(c, scheme, decoder), alicloudClientFactory, nil, nil, chartApplier, "", w, cluster)
+
+						expectGetSecretCallToWork(c, alicloudAccessKeyID, alicloudAccessKeySecret)
+						expectMachineClasses(gpuDriverVersion)
+
+						result, err := workerDelegate.GenerateMachineDeployments(context.TODO())
+						Expect(err).NotTo(HaveOccurred())
+						for _, machineDeployment := range result[:2] {
+							Expect(machineDeployment.Labels).To(HaveKeyWithValue(alicloud.LabelGPU, "true"))
+						}
+						for _, machineDeployment := range result[2:] {
+							Expect(machineDeployment.Labels).NotTo(HaveKey(alicloud.LabelGPU))
+						}
+						Expect(workerDelegate.DeployMachineClasses(context.TODO())).To(Succeed())
+					})
+
+					It("should install the default GPU driver if the worker pool does not specify a version", func() {
+						workerDelegate, _ = NewWorkerDelegate(common.NewClientContext(c, scheme, decoder), alicloudClientFactory, nil, nil, chartApplier, "", w, cluster)
+
+						expectGetSecretCallToWork(c, alicloudAccessKeyID, alicloudAccessKeySecret)
+						expectMachineClasses("470.57.02")
+
+						Expect(workerDelegate.DeployMachineClasses(context.TODO())).To(Succeed())
+					})
+
+					It("should fail if a GPU driver version is specified for a machine type without GPUs", func() {
+						w.Spec.Pools[0].MachineType = "ecs.g6.large"
+						w.Spec.Pools[0].ProviderConfig = &runtime.RawExtension{
+							Raw: encode(&apiv1alpha1.WorkerConfig{
+								TypeMeta: metav1.TypeMeta{
+									APIVersion: apiv1alpha1.SchemeGroupVersion.String(),
+									Kind:       "WorkerConfig",
+								},
+								GPUDriverVersion: &gpuDriverVersion,
+							}),
+						}
+						workerDelegate, _ = NewWorkerDelegate(common.NewClientContext(c, scheme, decoder), alicloudClientFactory, nil, nil, chartApplier, "", w, cluster)
+
+						expectGetSecretCallToWork(c, alicloudAccessKeyID, alicloudAccessKeySecret)
+
+						_, err := workerDelegate.GenerateMachineDeployments(context.TODO())
+						Expect(err).To(MatchError(ContainSubstring("has no GPUs")))
+					})
+				})
+
+				Context("additional user data", func() {
+					var additionalUserData = "#!/bin/bash\necho mirror > /etc/registry-mirror\n"
+
+					BeforeEach(func() {
+						w.Spec.Pools[0].ProviderConfig = &runtime.RawExtension{
+							Raw: encode(&apiv1alpha1.WorkerConfig{
+								TypeMeta: metav1.TypeMeta{
+									APIVersion: apiv1alpha1.SchemeGroupVersion.String(),
+									Kind:       "WorkerConfig",
+								},
+								UseLocalDisk: true,
+								UserData:     &additionalUserData,
+							}),
+						}
+						w.Spec.Pools[0].MachineType = "ecs.i2.xlarge"
+						w.Spec.Pools[0].UserData = []byte("#cloud-config\nhostname: foo\n")
+					})
+
+					It("should run the additional user data before the user data of Gardener", func() {
+						workerDelegate, _ = NewWorkerDelegate(common.NewClientContext(c, scheme, decoder), alicloudClientFactory, nil, nil, chartApplier, "", w, cluster)
+
+						expectGetSecretCallToWork(c, alicloudAccessKeyID, alicloudAccessKeySecret)
+
+						chartApplier.
+							EXPECT().
+							ApplyChart(
+								context.TODO(),
+								filepath.Join(alicloud.InternalChartsPath, "machineclass"),
+								namespace,
+								"machineclass",
+								gomock.Any(),
+								nil,
+							).
+							DoAndReturn(func(_ context.Context, _, _, _ string, values map[string]interface{}, _ interface{}) error {
+								machineClasses := values["machineClasses"].([]map[string]interface{})
+								for _, machineClass := range machineClasses[:2] {
+									secretUserData := machineClass["secret"].(map[string]interface{})["userData"].(string)
+									localDisks := strings.Index(secretUserData, "mkfs.ext4")
+									additional := strings.Index(secretUserData, "Content-Type: text/x-shellscript\r\n\r\n"+additionalUserData)
+									gardener := strings.Index(secretUserData, "Content-Type: text/cloud-config\r\n\r\n#cloud-config\nhostname: foo\n")
+									Expect(localDisks).To(BeNumerically(">", 0))
+									Expect(additional).To(BeNumerically(">", localDisks))
+									Expect(gardener).To(BeNumerically(">", additional))
+								}
+								for _, machineClass := range machineClasses[2:] {
+									Expect(machineClass["secret"]).To(HaveKeyWithValue("userData", string(userData)))
+								}
+								return nil
+							})
+
+						Expect(workerDelegate.DeployMachineClasses(context.TODO())).To(Succeed())
+					})
+
+					It("should fail if the combined user data exceeds the size limit", func() {
+						w.Spec.Pools[0].UserData = []byte("#cloud-config\n" + strings.Repeat("#", alicloud.MaxUserDataSize))
+						workerDelegate, _ = NewWorkerDelegate(common.NewClientContext(c, scheme, decoder), alicloudClientFactory, nil, nil, chartApplier, "", w, cluster)
+
+						expectGetSecretCallToWork(c, alicloudAccessKeyID, alicloudAccessKeySecret)
+
+						_, err := workerDelegate.GenerateMachineDeployments(context.TODO())
+						Expect(err).To(MatchError(ContainSubstring("Alicloud allows at most")))
+					})
+				})
+
+				Context("kubelet config", func() {
+					BeforeEach(func() {
+						w.Spec.Pools[0].ProviderConfig = &runtime.RawExtension{
+							Raw: encode(&apiv1alpha1.WorkerConfig{
+								TypeMeta: metav1.TypeMeta{
+									APIVersion: apiv1alpha1.SchemeGroupVersion.String(),
+									Kind:       "WorkerConfig",
+								},
+								KubeletConfig: &apiv1alpha1.KubeletConfig{
+									MaxPods:                 pointer.Int32Ptr(64),
+									EvictionHard:            map[string]string{"nodefs.available": "10%", "memory.available": "500Mi"},
+									EvictionSoft:            map[string]string{"memory.available": "1Gi"},
+									EvictionSoftGracePeriod: map[string]string{"memory.available": "1m30s"},
+									KubeReserved:            map[string]string{"cpu": "100m"},
+								},
+							}),
+						}
+						w.Spec.Pools[0].UserData = []byte("#cloud-config\nhostname: foo\n")
+					})
+
+					It("should pass the kubelet settings of the worker pool to the kubelets of its machines", func() {
+						workerDelegate, _ = NewWorkerDelegate(common.NewClientContext(c, scheme, decoder), alicloudClientFactory, nil, nil, chartApplier, "", w, cluster)
+
+						expectGetSecretCallToWork(c, alicloudAccessKeyID, alicloudAccessKeySecret)
+
+						chartApplier.
+							EXPECT().
+							ApplyChart(
+								context.TODO(),
+								filepath.Join(alicloud.InternalChartsPath, "machineclass"),
+								namespace,
+								"machineclass",
+								gomock.Any(),
+								nil,
+							).
+							DoAndReturn(func(_ context.Context, _, _, _ string, values map[string]interface{}, _ interface{}) error {
+								machineClasses := values["machineClasses"].([]map[string]interface{})
+								for _, machineClass := range machineClasses[:2] {
+									secretUserData := machineClass["secret"].(map[string]interface{})["userData"].(string)
+									kubeletConfig := strings.Index(secretUserData, "cat > /var/lib/kubelet/extra-args <<'EOF'\n"+
+										`KUBELET_EXTRA_ARGS="--max-pods=64 --eviction-hard=memory.available<500Mi,nodefs.available<10% --eviction-soft=memory.available<1Gi --eviction-soft-grace-period=memory.available=1m30s --kube-reserved=cpu=100m"`)
+									gardener := strings.Index(secretUserData, "Content-Type: text/cloud-config\r\n\r\n#cloud-config\nhostname: foo\n")
+									Expect(kubeletConfig).To(BeNumerically(">", 0))
+									Expect(gardener).To(BeNumerically(">", kubeletConfig))
+								}
+								for _, machineClass := range machineClasses[2:] {
+									Expect(machineClass["secret"]).To(HaveKeyWithValue("userData", string(userData)))
+								}
+								return nil
+							})
+
+						Expect(workerDelegate.DeployMachineClasses(context.TODO())).To(Succeed())
+					})
+
+					It("should fail if the kubelet settings of the worker pool are also set in the kubelet configuration of the shoot", func() {
+						cluster.Shoot.Spec.Kubernetes.Kubelet = &gardencorev1beta1.KubeletConfig{MaxPods: pointer.Int32Ptr(110)}
+						workerDelegate, _ = NewWorkerDelegate(common.NewClientContext(c, scheme, decoder), alicloudClientFactory, nil, nil, chartApplier, "", w, cluster)
+
+						expectGetSecretCallToWork(c, alicloudAccessKeyID, alicloudAccessKeySecret)
+
+						_, err := workerDelegate.GenerateMachineDeployments(context.TODO())
+						Expect(err).To(MatchError(ContainSubstring("pools[pool-1].kubeletConfig.maxPods: Forbidden")))
+					})
+				})
+
+				It("should fail if the labels and tags of the worker pool exceed the instance tag limit", func() {
+					w.Spec.Pools[0].Labels = map[string]string{}
+					for i := 0; i < 19; i++ {
+						w.Spec.Pools[0].Labels[fmt.Sprintf("label-%d", i)] = "foo"
+					}
+					workerDelegate, _ = NewWorkerDelegate(common.NewClientContext(c, scheme, decoder), alicloudClientFactory, nil, nil, chartApplier, "", w, cluster)
+
+					expectGetSecretCallToWork(c, alicloudAccessKeyID, alicloudAccessKeySecret)
+
+					_, err := workerDelegate.GenerateMachineDeployments(context.TODO())
+					Expect(err).To(HaveOccurred())
+				})
+
+				It("should configure spot instances for the worker pool", func() {
+					poolSpotStrategy := apiv1alpha1.SpotStrategyWithPriceLimit
+					spotPriceLimit := "0.05"
+					w.Spec.Pools[0].ProviderConfig = &runtime.RawExtension{
+						Raw: encode(&apiv1alpha1.WorkerConfig{
+							TypeMeta: metav1.TypeMeta{
+								APIVersion: apiv1alpha1.SchemeGroupVersion.String(),
+								Kind:       "WorkerConfig",
+							},
+							SpotStrategy:   &poolSpotStrategy,
+							SpotPriceLimit: &spotPriceLimit,
+						}),
+					}
+					workerDelegate, _ = NewWorkerDelegate(common.NewClientContext(c, scheme, decoder), alicloudClientFactory, nil, nil, chartApplier, "", w, cluster)
+
+					expectGetSecretCallToWork(c, alicloudAccessKeyID, alicloudAccessKeySecret)
+
+					chartApplier.
+						EXPECT().
+						ApplyChart(
+							context.TODO(),
+							filepath.Join(alicloud.InternalChartsPath, "machineclass"),
+							namespace,
+							"machineclass",
+							gomock.Any(),
+							nil,
+						).
+						DoAndReturn(func(_ context.Context, _, _, _ string, values map[string]interface{}, _ interface{}) error {
+							machineClasses := values["machineClasses"].([]map[string]interface{})
+							for _, machineClass := range machineClasses[:2] {
+								Expect(machineClass).To(HaveKeyWithValue("spotStrategy", string(poolSpotStrategy)))
+								Expect(machineClass).To(HaveKeyWithValue("spotPriceLimit", spotPriceLimit))
+							}
+							for _, machineClass := range machineClasses[2:] {
+								Expect(machineClass).To(HaveKeyWithValue("spotStrategy", spotStrategy))
+								Expect(machineClass).NotTo(HaveKey("spotPriceLimit"))
+							}
+							return nil
+						})
+
+					Expect(workerDelegate.DeployMachineClasses(context.TODO())).To(Succeed())
+				})
+
+				It("should configure PrePaid instances for the worker pool", func() {
+					var (
+						prePaid         = apiv1alpha1.InstanceChargeTypePrePaid
+						period          = int32(2)
+						periodUnit      = apiv1alpha1.PeriodUnitWeek
+						autoRenewPeriod = int32(3)
+					)
+					w.Spec.Pools[0].ProviderConfig = &runtime.RawExtension{
+						Raw: encode(&apiv1alpha1.WorkerConfig{
+							TypeMeta: metav1.TypeMeta{
+								APIVersion: apiv1alpha1.SchemeGroupVersion.String(),
+								Kind:       "WorkerConfig",
+							},
+							InstanceChargeType: &prePaid,
+							Period:             &period,
+							PeriodUnit:         &periodUnit,
+							AutoRenew:          true,
+							AutoRenewPeriod:    &autoRenewPeriod,
+						}),
+					}
+					workerDelegate, _ = NewWorkerDelegate(common.NewClientContext(c, scheme, decoder), alicloudClientFactory, nil, nil, chartApplier, "", w, cluster)
+
+					expectGetSecretCallToWork(c, alicloudAccessKeyID, alicloudAccessKeySecret)
+
+					chartApplier.
+						EXPECT().
+						ApplyChart(
+							context.TODO(),
+							filepath.Join(alicloud.InternalChartsPath, "machineclass"),
+							namespace,
+							"machineclass",
+							gomock.Any(),
+							nil,
+						).
+						DoAndReturn(func(_ context.Context, _, _, _ string, values map[string]interface{}, _ interface{}) error {
+							machineClasses := values["machineClasses"].([]map[string]interface{})
+							for _, machineClass := range machineClasses[:2] {
+								Expect(machineClass).To(HaveKeyWithValue("instanceChargeType", "PrePaid"))
+								Expect(machineClass).To(HaveKeyWithValue("period", period))
+								Expect(machineClass).To(HaveKeyWithValue("periodUnit", "Week"))
+								Expect(machineClass).To(HaveKeyWithValue("autoRenew", true))
+								Expect(machineClass).To(HaveKeyWithValue("autoRenewPeriod", autoRenewPeriod))
+							}
+							for _, machineClass := range machineClasses[2:] {
+								Expect(machineClass).To(HaveKeyWithValue("instanceChargeType", "PostPaid"))
+								Expect(machineClass).NotTo(HaveKey("period"))
+								Expect(machineClass).NotTo(HaveKey("periodUnit"))
+								Expect(machineClass).NotTo(HaveKey("autoRenew"))
+							}
+							return nil
+						})
+
+					Expect(workerDelegate.DeployMachineClasses(context.TODO())).To(Succeed())
+				})
+
+				It("should configure the drain timeout of the machine-controller-manager", func() {
+					w.Spec.Pools[0].ProviderConfig = &runtime.RawExtension{
+						Raw: encode(&apiv1alpha1.WorkerConfig{
+							TypeMeta: metav1.TypeMeta{
+								APIVersion: apiv1alpha1.SchemeGroupVersion.String(),
+								Kind:       "WorkerConfig",
+							},
+							DrainTimeout: &metav1.Duration{Duration: 4 * time.Hour},
+						}),
+					}
+					workerDelegate, _ = NewWorkerDelegate(common.NewClientContext(c, scheme, decoder), alicloudClientFactory, nil, nil, chartApplier, "", w, cluster)
+
+					c.EXPECT().Get(context.TODO(), client.ObjectKey{Name: namespace}, gomock.AssignableToTypeOf(&corev1.Namespace{}))
+
+					values, err := workerDelegate.GetMachineControllerManagerChartValues(context.TODO())
+
+					Expect(err).NotTo(HaveOccurred())
+					Expect(values).To(HaveKeyWithValue("machineDrainTimeout", "4h0m0s"))
+				})
+
+				It("should not change the machine class names if only the drain timeout changes", func() {
+					workerConfig := &apiv1alpha1.WorkerConfig{
+						TypeMeta: metav1.TypeMeta{
+							APIVersion: apiv1alpha1.SchemeGroupVersion.String(),
+							Kind:       "WorkerConfig",
+						},
+						Tags: map[string]string{"team": "a"},
+					}
+					w.Spec.Pools[0].ProviderConfig = &runtime.RawExtension{Raw: encodeSorted(workerConfig)}
+					workerDelegate, _ = NewWorkerDelegate(common.NewClientContext(c, scheme, decoder), alicloudClientFactory, nil, nil, chartApplier, "", w, cluster)
+
+					expectGetSecretCallToWork(c, alicloudAccessKeyID, alicloudAccessKeySecret)
+
+					result, err := workerDelegate.GenerateMachineDeployments(context.TODO())
+					Expect(err).NotTo(HaveOccurred())
+
+					workerConfig.DrainTimeout = &metav1.Duration{Duration: 4 * time.Hour}
+					w.Spec.Pools[0].ProviderConfig = &runtime.RawExtension{Raw: encodeSorted(workerConfig)}
+					workerDelegate, _ = NewWorkerDelegate(common.NewClientContext(c, scheme, decoder), alicloudClientFactory, nil, nil, chartApplier, "", w, cluster)
+
+					expectGetSecretCallToWork(c, alicloudAccessKeyID, alicloudAccessKeySecret)
+
+					resultWithDrainTimeout, err := workerDelegate.GenerateMachineDeployments(context.TODO())
+					Expect(err).NotTo(HaveOccurred())
+					Expect(resultWithDrainTimeout[0].ClassName).To(Equal(result[0].ClassName))
+				})
+
+				It("should use the default drain timeout if it is longer than the configured ones", func() {
+					w.Spec.Pools[0].ProviderConfig = &runtime.RawExtension{
+						Raw: encode(&apiv1alpha1.WorkerConfig{
+							TypeMeta: metav1.TypeMeta{
+								APIVersion: apiv1alpha1.SchemeGroupVersion.String(),
+								Kind:       "WorkerConfig",
+							},
+							DrainTimeout: &metav1.Duration{Duration: 30 * time.Minute},
+						}),
+					}
+					workerDelegate, _ = NewWorkerDelegate(common.NewClientContext(c, scheme, decoder), alicloudClientFactory, nil, nil, chartApplier, "", w, cluster)
+
+					c.EXPECT().Get(context.TODO(), client.ObjectKey{Name: namespace}, gomock.AssignableToTypeOf(&corev1.Namespace{}))
+
+					values, err := workerDelegate.GetMachineControllerManagerChartValues(context.TODO())
+
+					Expect(err).NotTo(HaveOccurred())
+					Expect(values).To(HaveKeyWithValue("machineDrainTimeout", "2h0m0s"))
+				})
+
+				Context("deployment sets", func() {
+					var deploymentSetID = "ds-1234"
+
+					BeforeEach(func() {
+						w.Spec.Pools[0].ProviderConfig = &runtime.RawExtension{
+							Raw: encode(&apiv1alpha1.WorkerConfig{
+								TypeMeta: metav1.TypeMeta{
+									APIVersion: apiv1alpha1.SchemeGroupVersion.String(),
+									Kind:       "WorkerConfig",
+								},
+								DeploymentSetID: &deploymentSetID,
+							}),
+						}
+
+						expectGetSecretCallToWork(c, alicloudAccessKeyID, alicloudAccessKeySecret)
+						expectGetSecretCallToWork(c, alicloudAccessKeyID, alicloudAccessKeySecret)
+						alicloudClientFactory.EXPECT().NewECSClient(context.TODO(), region, &alicloud.Credentials{AccessKeyID: alicloudAccessKeyID, AccessKeySecret: alicloudAccessKeySecret}).Return(ecsClient, nil)
+					})
+
+					It("should place the machines of the worker pool into the deployment set", func() {
+						workerDelegate, _ = NewWorkerDelegate(common.NewClientContext(c, scheme, decoder), alicloudClientFactory, nil, nil, chartApplier, "", w, cluster)
+						ecsClient.EXPECT().GetDeploymentSet(context.TODO(), region, deploymentSetID).Return(&ecs.DeploymentSet{DeploymentSetId: deploymentSetID}, nil)
+
+						chartApplier.
+							EXPECT().
+							ApplyChart(
+								context.TODO(),
+								filepath.Join(alicloud.InternalChartsPath, "machineclass"),
+								namespace,
+								"machineclass",
+								gomock.Any(),
+								nil,
+							).
+							DoAndReturn(func(_ context.Context, _, _, _ string, values map[string]interface{}, _ interface{}) error {
+								machineClasses := values["machineClasses"].([]map[string]interface{})
+								for _, machineClass := range machineClasses[:2] {
+									Expect(machineClass).To(HaveKeyWithValue("deploymentSetID", deploymentSetID))
+								}
+								for _, machineClass := range machineClasses[2:] {
+									Expect(machineClass).NotTo(HaveKey("deploymentSetID"))
+								}
+								return nil
+							})
+
+						Expect(workerDelegate.DeployMachineClasses(context.TODO())).To(Succeed())
+					})
+
+					It("should fail if the deployment set does not exist", func() {
+						workerDelegate, _ = NewWorkerDelegate(common.NewClientContext(c, scheme, decoder), alicloudClientFactory, nil, nil, chartApplier, "", w, cluster)
+						ecsClient.EXPECT().GetDeploymentSet(context.TODO(), region, deploymentSetID).Return(nil, nil)
+
+						_, err := workerDelegate.GenerateMachineDeployments(context.TODO())
+						Expect(err).To(MatchError(ContainSubstring("does not exist")))
+					})
+
+					It("should fail if the deployment set cannot hold the machines of the worker pool", func() {
+						w.Spec.Pools[0].Maximum = 41
+						workerDelegate, _ = NewWorkerDelegate(common.NewClientContext(c, scheme, decoder), alicloudClientFactory, nil, nil, chartApplier, "", w, cluster)
+						ecsClient.EXPECT().GetDeploymentSet(context.TODO(), region, deploymentSetID).Return(&ecs.DeploymentSet{DeploymentSetId: deploymentSetID}, nil)
+
+						_, err := workerDelegate.GenerateMachineDeployments(context.TODO())
+						Expect(err).To(MatchError(ContainSubstring("is full")))
+					})
+
+					Context("placement strategy", func() {
+						var placementStrategy = apiv1alpha1.PlacementStrategyPack
+
+						BeforeEach(func() {
+							w.Spec.Pools[0].ProviderConfig = &runtime.RawExtension{
+								Raw: encode(&apiv1alpha1.WorkerConfig{
+									TypeMeta: metav1.TypeMeta{
+										APIVersion: apiv1alpha1.SchemeGroupVersion.String(),
+										Kind:       "WorkerConfig",
+									},
+									DeploymentSetID:   &deploymentSetID,
+									PlacementStrategy: &placementStrategy,
+								}),
+							}
+						})
+
+						It("should pack the machines of the worker pool into the deployment set", func() {
+							w.Spec.Pools[0].Zones = []string{zone1}
+							workerDelegate, _ = NewWorkerDelegate(common.NewClientContext(c, scheme, decoder), alicloudClientFactory, nil, nil, chartApplier, "", w, cluster)
+							ecsClient.EXPECT().GetDeploymentSet(context.TODO(), region, deploymentSetID).Return(&ecs.DeploymentSet{DeploymentSetId: deploymentSetID, Strategy: "LowLatency"}, nil)
+
+							chartApplier.
+								EXPECT().
+								ApplyChart(
+									context.TODO(),
+									filepath.Join(alicloud.InternalChartsPath, "machineclass"),
+									namespace,
+									"machineclass",
+									gomock.Any(),
+									nil,
+								).
+								DoAndReturn(func(_ context.Context, _, _, _ string, values map[string]interface{}, _ interface{}) error {
+									machineClasses := values["machineClasses"].([]map[string]interface{})
+									Expect(machineClasses[0]).To(HaveKeyWithValue("deploymentSetID", deploymentSetID))
+									Expect(machineClasses[0]).To(HaveKeyWithValue("zoneID", zone1))
+									for _, machineClass := range machineClasses[1:] {
+										Expect(machineClass).NotTo(HaveKey("deploymentSetID"))
+									}
+									return nil
+								})
+
+							Expect(workerDelegate.DeployMachineClasses(context.TODO())).To(Succeed())
+						})
+
+						It("should fail if the deployment set does not implement the placement strategy", func() {
+							w.Spec.Pools[0].Zones = []string{zone1}
+							workerDelegate, _ = NewWorkerDelegate(common.NewClientContext(c, scheme, decoder), alicloudClientFactory, nil, nil, chartApplier, "", w, cluster)
+							ecsClient.EXPECT().GetDeploymentSet(context.TODO(), region, deploymentSetID).Return(&ecs.DeploymentSet{DeploymentSetId: deploymentSetID, Strategy: "Availability"}, nil)
+
+							_, err := workerDelegate.GenerateMachineDeployments(context.TODO())
+							Expect(err).To(MatchError(ContainSubstring("does not implement the placement strategy Pack")))
+						})
+
+						It("should fail if the packed worker pool spans multiple zones", func() {
+							workerDelegate, _ = NewWorkerDelegate(common.NewClientContext(c, scheme, decoder), alicloudClientFactory, nil, nil, chartApplier, "", w, cluster)
+							ecsClient.EXPECT().GetDeploymentSet(context.TODO(), region, deploymentSetID).Return(&ecs.DeploymentSet{DeploymentSetId: deploymentSetID, Strategy: "LowLatency"}, nil)
+
+							_, err := workerDelegate.GenerateMachineDeployments(context.TODO())
+							Expect(err).To(MatchError(ContainSubstring("must be in one zone")))
+						})
+					})
+				})
+
+				Context("many worker pools", func() {
+					const (
+						poolCount = 12
+						delay     = 50 * time.Millisecond
+					)
+					var deploymentSetID = "ds-1234"
+
+					BeforeEach(func() {
+						pool := w.Spec.Pools[0]
+						pool.Zones = []string{zone1}
+						pool.ProviderConfig = &runtime.RawExtension{
+							Raw: encode(&apiv1alpha1.WorkerConfig{
+								TypeMeta: metav1.TypeMeta{
+									APIVersion: apiv1alpha1.SchemeGroupVersion.String(),
+									Kind:       "WorkerConfig",
+								},
+								DeploymentSetID: &deploymentSetID,
+							}),
+						}
+						w.Spec.Pools = nil
+						for i := 0; i < poolCount; i++ {
+							pool.Name = fmt.Sprintf("pool-%d", i)
+							w.Spec.Pools = append(w.Spec.Pools, pool)
+						}
+
+						c.EXPECT().
+							Get(context.TODO(), gomock.Any(), gomock.AssignableToTypeOf(&corev1.Secret{})).
+							DoAndReturn(func(_ context.Context, _ client.ObjectKey, secret *corev1.Secret) error {
+								secret.Data = map[string][]byte{
+									alicloud.AccessKeyID:     []byte(alicloudAccessKeyID),
+									alicloud.AccessKeySecret: []byte(alicloudAccessKeySecret),
+								}
+								return nil
+							}).
+							Times(poolCount + 1)
+						alicloudClientFactory.EXPECT().NewECSClient(context.TODO(), region, &alicloud.Credentials{AccessKeyID: alicloudAccessKeyID, AccessKeySecret: alicloudAccessKeySecret}).Return(ecsClient, nil).Times(poolCount)
+					})
+
+					It("should generate the machine deployments of the worker pools concurrently and in order", func() {
+						var inFlight, maxInFlight int32
+						ecsClient.EXPECT().GetDeploymentSet(context.TODO(), region, deploymentSetID).DoAndReturn(func(_ context.Context, _, _ string) (*ecs.DeploymentSet, error) {
+							current := atomic.AddInt32(&inFlight, 1)
+							defer atomic.AddInt32(&inFlight, -1)
+							for {
+								max := atomic.LoadInt32(&maxInFlight)
+								if current <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, current) {
+									break
+								}
+							}
+							time.Sleep(delay)
+							return &ecs.DeploymentSet{DeploymentSetId: deploymentSetID}, nil
+						}).Times(poolCount)
+						workerDelegate, _ = NewWorkerDelegate(common.NewClientContext(c, scheme, decoder), alicloudClientFactory, nil, nil, chartApplier, "", w, cluster)
+
+						start := time.Now()
+						result, err := workerDelegate.GenerateMachineDeployments(context.TODO())
+						elapsed := time.Since(start)
+
+						Expect(err).NotTo(HaveOccurred())
+						Expect(result).To(HaveLen(poolCount))
+						for i, machineDeployment := range result {
+							Expect(machineDeployment.Name).To(Equal(fmt.Sprintf("%s-pool-%d-%s", namespace, i, zone1)))
+						}
+						// At most five worker pools are generated at the same time.
+						Expect(maxInFlight).To(BeNumerically(">", 1))
+						Expect(maxInFlight).To(BeNumerically("<=", 5))
+						Expect(elapsed).To(BeNumerically("<", poolCount*delay/2))
+					})
+				})
+
+				Context("encrypted system disks", func() {
+					var (
+						kmsKeyID             = "kms-key"
+						encryptedImageName   string
+						encryptedImageID     = "ami-encrypted"
+						machineClassesPool1  []map[string]interface{}
+						expectedMachineImage apiv1alpha1.MachineImage
+					)
+
+					BeforeEach(func() {
+						encryptedImageName = machineImageID + "-encrypted-" + kmsKeyID
+
+						w.Spec.Pools[0].ProviderConfig = &runtime.RawExtension{
+							Raw: encode(&apiv1alpha1.WorkerConfig{
+								TypeMeta: metav1.TypeMeta{
+									APIVersion: apiv1alpha1.SchemeGroupVersion.String(),
+									Kind:       "WorkerConfig",
+								},
+								SystemDisk: &apiv1alpha1.SystemDisk{
+									Encrypted: true,
+									KMSKeyID:  &kmsKeyID,
+								},
+							}),
+						}
+						workerPoolHash1, _ = worker.WorkerPoolHash(w.Spec.Pools[0], cluster)
+
+						for _, zone := range []struct{ name, vswitch string }{{zone1, vswitchZone1}, {zone2, vswitchZone2}} {
+							machineClass := useDefaultMachineClass(defaultMachineClass,
+								"vSwitchID", zone.vswitch,
+								"zoneID", zone.name,
+								"imageID", encryptedImageID,
+								"systemDisk", map[string]interface{}{
+									"category":  volumeType,
+									"size":      volumeSize,
+									"encrypted": true,
+									"kmsKeyID":  kmsKeyID,
+								},
+							)
+							addNameAndSecretToMachineClass(machineClass, alicloudAccessKeyID, alicloudAccessKeySecret, fmt.Sprintf("%s-%s-%s-%s", namespace, namePool1, zone.name, workerPoolHash1))
+							machineClassesPool1 = append(machineClassesPool1, machineClass)
+						}
+
+						encrypted := true
+						expectedMachineImage = apiv1alpha1.MachineImage{
+							Name:      machineImageName,
+							Version:   machineImageVersion,
+							ID:        encryptedImageID,
+							Encrypted: &encrypted,
+						}
+
+						workerDelegate, _ = NewWorkerDelegate(common.NewClientContext(c, scheme, decoder), alicloudClientFactory, nil, nil, chartApplier, "", w, cluster)
+
+						expectGetSecretCallToWork(c, alicloudAccessKeyID, alicloudAccessKeySecret)
+						expectGetSecretCallToWork(c, alicloudAccessKeyID, alicloudAccessKeySecret)
+						alicloudClientFactory.EXPECT().NewECSClient(context.TODO(), region, &alicloud.Credentials{AccessKeyID: alicloudAccessKeyID, AccessKeySecret: alicloudAccessKeySecret}).Return(ecsClient, nil)
+					})
+
+					AfterEach(func() {
+						machineClassesPool1 = nil
+					})
+
+					encryptedImage := func() *ecs.Image {
+						return &ecs.Image{
+							ImageId: encryptedImageID,
+							Status:  "Available",
+							Tags: ecs.TagsInDescribeImages{Tag: []ecs.Tag{
+								{TagKey: "gardener.cloud/encrypted-image-source", TagValue: machineImageID},
+								{TagKey: "kubernetes.io/cluster/" + namespace, TagValue: "1"},
+							}},
+						}
+					}
+
+					It("should tag an encrypted copy created for another shoot", func() {
+						image := encryptedImage()
+						image.Tags.Tag = image.Tags.Tag[:1]
+						ecsClient.EXPECT().GetImageByName(context.TODO(), encryptedImageName).Return(image, nil)
+						ecsClient.EXPECT().TagResources(context.TODO(), "image", []string{encryptedImageID}, map[string]string{
+							"gardener.cloud/encrypted-image-source": machineImageID,
+							"kubernetes.io/cluster/" + namespace:    "1",
+						})
+
+						machineImages, err := workerDelegate.GetMachineImages(context.TODO())
+						Expect(err).NotTo(HaveOccurred())
+						Expect(machineImages.(*apiv1alpha1.WorkerStatus).MachineImages).To(ContainElement(expectedMachineImage))
+					})
+
+					It("should use the encrypted copy of the machine image", func() {
+						ecsClient.EXPECT().GetImageByName(context.TODO(), encryptedImageName).Return(encryptedImage(), nil)
+
+						machineImages, err := workerDelegate.GetMachineImages(context.TODO())
+						Expect(err).NotTo(HaveOccurred())
+						Expect(machineImages.(*apiv1alpha1.WorkerStatus).MachineImages).To(ContainElement(expectedMachineImage))
+
+						chartApplier.
+							EXPECT().
+							ApplyChart(
+								context.TODO(),
+								filepath.Join(alicloud.InternalChartsPath, "machineclass"),
+								namespace,
+								"machineclass",
+								gomock.Any(),
+								nil,
+							).
+							DoAndReturn(func(_ context.Context, _, _, _ string, values map[string]interface{}, _ interface{}) error {
+								Expect(values["machineClasses"].([]map[string]interface{})[:2]).To(Equal(machineClassesPool1))
+								return nil
+							})
+
+						Expect(workerDelegate.DeployMachineClasses(context.TODO())).To(Succeed())
+					})
+
+					It("should copy the machine image if no encrypted copy exists", func() {
+						ecsClient.EXPECT().GetImageByName(context.TODO(), encryptedImageName).Return(nil, nil)
+						ecsClient.EXPECT().CheckIfImageExists(context.TODO(), machineImageID).Return(true, nil)
+						ecsClient.EXPECT().CopyEncryptedImage(context.TODO(), region, machineImageID, encryptedImageName, &kmsKeyID, map[string]string{
+							"gardener.cloud/encrypted-image-source": machineImageID,
+							"kubernetes.io/cluster/" + namespace:    "1",
+						}).Return(encryptedImageID, nil)
+
+						_, err := workerDelegate.GenerateMachineDeployments(context.TODO())
+						Expect(err).To(MatchError(fmt.Sprintf("encrypted copy %s of image %s is being created", encryptedImageID, machineImageID)))
+					})
+
+					It("should copy a custom image which another account shared with the shoot's account", func() {
+						sharedImageID := "m-shared"
+						w.Spec.Pools[0].ProviderConfig = &runtime.RawExtension{
+							Raw: encode(&apiv1alpha1.WorkerConfig{
+								TypeMeta: metav1.TypeMeta{
+									APIVersion: apiv1alpha1.SchemeGroupVersion.String(),
+									Kind:       "WorkerConfig",
+								},
+								ImageID: &sharedImageID,
+								SystemDisk: &apiv1alpha1.SystemDisk{
+									Encrypted: true,
+									KMSKeyID:  &kmsKeyID,
+								},
+							}),
+						}
+						workerDelegate, _ = NewWorkerDelegate(common.NewClientContext(c, scheme, decoder), alicloudClientFactory, nil, nil, chartApplier, "", w, cluster)
+
+						ecsClient.EXPECT().GetImageByName(context.TODO(), sharedImageID+"-encrypted-"+kmsKeyID).Return(nil, nil)
+						ecsClient.EXPECT().CheckIfImageExists(context.TODO(), sharedImageID).Return(true, nil)
+						ecsClient.EXPECT().CopyEncryptedImage(context.TODO(), region, sharedImageID, sharedImageID+"-encrypted-"+kmsKeyID, &kmsKeyID, gomock.Any()).Return(encryptedImageID, nil)
+
+						_, err := workerDelegate.GenerateMachineDeployments(context.TODO())
+						Expect(err).To(MatchError(fmt.Sprintf("encrypted copy %s of image %s is being created", encryptedImageID, sharedImageID)))
+					})
+
+					It("should fail if the image is not shared with the shoot's account", func() {
+						ecsClient.EXPECT().GetImageByName(context.TODO(), encryptedImageName).Return(nil, nil)
+						ecsClient.EXPECT().CheckIfImageExists(context.TODO(), machineImageID).Return(false, nil)
+
+						_, err := workerDelegate.GenerateMachineDeployments(context.TODO())
+						Expect(err).To(MatchError(ContainSubstring(fmt.Sprintf("image %s is not accessible by the account of the shoot, please share it with the account", machineImageID))))
+					})
+
+					It("should not look up the encrypted copy again within the TTL of the cache", func() {
+						machineImageCache := NewMachineImageCache()
+						ecsClient.EXPECT().GetImageByName(context.TODO(), encryptedImageName).Return(encryptedImage(), nil)
+
+						for i := 0; i < 2; i++ {
+							if i > 0 {
+								expectGetSecretCallToWork(c, alicloudAccessKeyID, alicloudAccessKeySecret)
+								expectGetSecretCallToWork(c, alicloudAccessKeyID, alicloudAccessKeySecret)
+							}
+							workerDelegate, _ = NewWorkerDelegate(common.NewClientContext(c, scheme, decoder), alicloudClientFactory, machineImageCache, nil, chartApplier, "", w, cluster)
+
+							machineImages, err := workerDelegate.GetMachineImages(context.TODO())
+							Expect(err).NotTo(HaveOccurred())
+							Expect(machineImages.(*apiv1alpha1.WorkerStatus).MachineImages).To(ContainElement(expectedMachineImage))
+						}
+					})
+
+					It("should look up the encrypted copy again if the CloudProfile changed", func() {
+						machineImageCache := NewMachineImageCache()
+						ecsClient.EXPECT().GetImageByName(context.TODO(), encryptedImageName).Return(encryptedImage(), nil).Times(2)
+
+						workerDelegate, _ = NewWorkerDelegate(common.NewClientContext(c, scheme, decoder), alicloudClientFactory, machineImageCache, nil, chartApplier, "", w, cluster)
+						_, err := workerDelegate.GetMachineImages(context.TODO())
+						Expect(err).NotTo(HaveOccurred())
+
+						changedCluster := *cluster
+						changedCluster.CloudProfile = cluster.CloudProfile.DeepCopy()
+						changedCluster.CloudProfile.Generation++
+						expectGetSecretCallToWork(c, alicloudAccessKeyID, alicloudAccessKeySecret)
+						expectGetSecretCallToWork(c, alicloudAccessKeyID, alicloudAccessKeySecret)
+						alicloudClientFactory.EXPECT().NewECSClient(context.TODO(), region, &alicloud.Credentials{AccessKeyID: alicloudAccessKeyID, AccessKeySecret: alicloudAccessKeySecret}).Return(ecsClient, nil)
+
+						workerDelegate, _ = NewWorkerDelegate(common.NewClientContext(c, scheme, decoder), alicloudClientFactory, machineImageCache, nil, chartApplier, "", w, &changedCluster)
+						_, err = workerDelegate.GetMachineImages(context.TODO())
+						Expect(err).NotTo(HaveOccurred())
+					})
+
+					It("should fail while the encrypted copy is not yet available", func() {
+						ecsClient.EXPECT().GetImageByName(context.TODO(), encryptedImageName).Return(&ecs.Image{ImageId: encryptedImageID, Status: "Creating"}, nil)
+
+						_, err := workerDelegate.GenerateMachineDeployments(context.TODO())
+						Expect(err).To(HaveOccurred())
+					})
+				})
 			})
 
 			It("should fail because the secret cannot be read", func() {
@@ -486,7 +1772,7 @@ var _ = Describe("Machines", func() {
 				expectGetSecretCallToWork(c, alicloudAccessKeyID, alicloudAccessKeySecret)
 
 				clusterWithoutImages.Shoot.Spec.Kubernetes.Version = "invalid"
-				workerDelegate, _ = NewWorkerDelegate(common.NewClientContext(c, scheme, decoder), chartApplier, "", w, cluster)
+				workerDelegate, _ = NewWorkerDelegate(common.NewClientContext(c, scheme, decoder), alicloudClientFactory, nil, nil, chartApplier, "", w, cluster)
 
 				result, err := workerDelegate.GenerateMachineDeployments(context.TODO())
 				Expect(err).To(HaveOccurred())
@@ -498,7 +1784,7 @@ var _ = Describe("Machines", func() {
 
 				w.Spec.InfrastructureProviderStatus = &runtime.RawExtension{}
 
-				workerDelegate, _ = NewWorkerDelegate(common.NewClientContext(c, scheme, decoder), chartApplier, "", w, cluster)
+				workerDelegate, _ = NewWorkerDelegate(common.NewClientContext(c, scheme, decoder), alicloudClientFactory, nil, nil, chartApplier, "", w, cluster)
 
 				result, err := workerDelegate.GenerateMachineDeployments(context.TODO())
 				Expect(err).To(HaveOccurred())
@@ -514,17 +1800,61 @@ var _ = Describe("Machines", func() {
 					}),
 				}
 
-				workerDelegate, _ = NewWorkerDelegate(common.NewClientContext(c, scheme, decoder), chartApplier, "", w, cluster)
+				workerDelegate, _ = NewWorkerDelegate(common.NewClientContext(c, scheme, decoder), alicloudClientFactory, nil, nil, chartApplier, "", w, cluster)
 
 				result, err := workerDelegate.GenerateMachineDeployments(context.TODO())
 				Expect(err).To(HaveOccurred())
 				Expect(result).To(BeNil())
 			})
 
+			It("should look up the machine image by its name in regions without mapping", func() {
+				imageName, imageID := "coreos_2023_4_0_64_30G_alibase_20190319.vhd", "m-looked-up"
+				cloudProfileConfigJSON, _ := json.Marshal(&apiv1alpha1.CloudProfileConfig{
+					TypeMeta: metav1.TypeMeta{
+						APIVersion: apiv1alpha1.SchemeGroupVersion.String(),
+						Kind:       "CloudProfileConfig",
+					},
+					MachineImages: []apiv1alpha1.MachineImages{
+						{
+							Name: machineImageName,
+							Versions: []apiv1alpha1.MachineImageVersion{
+								{
+									Version:   machineImageVersion,
+									Regions:   []apiv1alpha1.RegionIDMapping{{Name: "other-region", ID: machineImageID}},
+									ImageName: &imageName,
+								},
+							},
+						},
+					},
+				})
+				clusterWithImageName := &extensionscontroller.Cluster{
+					CloudProfile: cluster.CloudProfile.DeepCopy(),
+					Shoot:        cluster.Shoot,
+				}
+				clusterWithImageName.CloudProfile.Spec.ProviderConfig.Raw = cloudProfileConfigJSON
+
+				// Both worker pools use the machine image, the second one gets the cached ID of the image.
+				for i := 0; i < 3; i++ {
+					expectGetSecretCallToWork(c, alicloudAccessKeyID, alicloudAccessKeySecret)
+				}
+				alicloudClientFactory.EXPECT().NewECSClient(context.TODO(), region, &alicloud.Credentials{AccessKeyID: alicloudAccessKeyID, AccessKeySecret: alicloudAccessKeySecret}).Return(ecsClient, nil)
+				ecsClient.EXPECT().GetAvailableImageByName(context.TODO(), imageName).Return(&ecs.Image{ImageId: imageID, Status: "Available"}, nil)
+
+				workerDelegate, _ = NewWorkerDelegate(common.NewClientContext(c, scheme, decoder), alicloudClientFactory, NewMachineImageCache(), nil, chartApplier, "", w, clusterWithImageName)
+
+				machineImages, err := workerDelegate.GetMachineImages(context.TODO())
+				Expect(err).NotTo(HaveOccurred())
+				Expect(machineImages.(*apiv1alpha1.WorkerStatus).MachineImages).To(ContainElement(apiv1alpha1.MachineImage{
+					Name:    machineImageName,
+					Version: machineImageVersion,
+					ID:      imageID,
+				}))
+			})
+
 			It("should fail because the machine image cannot be found", func() {
 				expectGetSecretCallToWork(c, alicloudAccessKeyID, alicloudAccessKeySecret)
 
-				workerDelegate, _ = NewWorkerDelegate(common.NewClientContext(c, scheme, decoder), chartApplier, "", w, clusterWithoutImages)
+				workerDelegate, _ = NewWorkerDelegate(common.NewClientContext(c, scheme, decoder), alicloudClientFactory, nil, nil, chartApplier, "", w, clusterWithoutImages)
 
 				result, err := workerDelegate.GenerateMachineDeployments(context.TODO())
 				Expect(err).To(HaveOccurred())
@@ -548,7 +1878,7 @@ var _ = Describe("Machines", func() {
 					}),
 				}
 
-				workerDelegate, _ = NewWorkerDelegate(common.NewClientContext(c, scheme, decoder), chartApplier, "", w, cluster)
+				workerDelegate, _ = NewWorkerDelegate(common.NewClientContext(c, scheme, decoder), alicloudClientFactory, nil, nil, chartApplier, "", w, cluster)
 
 				result, err := workerDelegate.GenerateMachineDeployments(context.TODO())
 				Expect(err).To(HaveOccurred())
@@ -560,7 +1890,7 @@ var _ = Describe("Machines", func() {
 
 				w.Spec.Pools[0].Volume.Size = "not-decodeable"
 
-				workerDelegate, _ = NewWorkerDelegate(common.NewClientContext(c, scheme, decoder), chartApplier, "", w, cluster)
+				workerDelegate, _ = NewWorkerDelegate(common.NewClientContext(c, scheme, decoder), alicloudClientFactory, nil, nil, chartApplier, "", w, cluster)
 
 				result, err := workerDelegate.GenerateMachineDeployments(context.TODO())
 				Expect(err).To(HaveOccurred())
@@ -575,6 +1905,12 @@ func encode(obj runtime.Object) []byte {
 	return data
 }
 
+// encodeSorted encodes the given object with sorted keys like kubectl encodes YAML manifests.
+func encodeSorted(obj runtime.Object) []byte {
+	data, _ := yaml.YAMLToJSON(encode(obj))
+	return data
+}
+
 func expectGetSecretCallToWork(c *mockclient.MockClient, alicloudAccessKeyID, alicloudAccessKeySecret string) {
 	c.EXPECT().
 		Get(context.TODO(), gomock.Any(), gomock.AssignableToTypeOf(&corev1.Secret{})).
@@ -0,0 +1,55 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package worker
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// expiredTimeLayout is the layout of the expiration time of PrePaid instances returned by the Alicloud API.
+const expiredTimeLayout = "2006-01-02T15:04Z"
+
+// explainPrePaidInstances adds a hint to the given error if the shoot has PrePaid instances whose subscription
+// period has not ended yet. Alicloud refuses to release such instances, hence the machine-controller-manager cannot
+// delete their machines and the rolling update or deletion of the worker gets stuck until the period ends.
+func (w *workerDelegate) explainPrePaidInstances(ctx context.Context, err error, now time.Time) error {
+	ecsClient, clientErr := w.newECSClient(ctx)
+	if clientErr != nil {
+		return err
+	}
+
+	instances, listErr := ecsClient.GetPrePaidInstancesByTags(ctx, w.worker.Spec.Region, map[string]string{w.clusterTagKey(): "1"})
+	if listErr != nil {
+		return err
+	}
+
+	var subscribed []string
+	for _, instance := range instances {
+		if expiredTime, parseErr := time.Parse(expiredTimeLayout, instance.ExpiredTime); parseErr == nil && !expiredTime.After(now) {
+			continue
+		}
+		subscribed = append(subscribed, fmt.Sprintf("%s (until %s)", instance.InstanceId, instance.ExpiredTime))
+	}
+	if len(subscribed) == 0 {
+		return err
+	}
+
+	sort.Strings(subscribed)
+	return fmt.Errorf("%v; the PrePaid instances %s cannot be released before the end of their subscription period, machines running on them are only deleted once it has ended", err, strings.Join(subscribed, ", "))
+}
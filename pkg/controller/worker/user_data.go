@@ -0,0 +1,68 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package worker
+
+import (
+	"bytes"
+	"fmt"
+	"mime/multipart"
+	"net/textproto"
+)
+
+// userDataBoundary is the boundary of the multi-part user data. It is fixed to keep the user data and hence the
+// machine classes stable across reconciliations.
+const userDataBoundary = "gardener-user-data-boundary"
+
+// combineUserData combines the given user data parts (cloud-configs or shell scripts) into a multi-part user data
+// which is processed by cloud-init. Shell scripts are run in the order of the parts.
+func combineUserData(parts ...[]byte) (string, error) {
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+	if err := writer.SetBoundary(userDataBoundary); err != nil {
+		return "", err
+	}
+
+	fmt.Fprintf(&buf, "Content-Type: multipart/mixed; boundary=%q\r\nMIME-Version: 1.0\r\n\r\n", userDataBoundary)
+	for _, part := range parts {
+		contentType, err := userDataContentType(part)
+		if err != nil {
+			return "", err
+		}
+		w, err := writer.CreatePart(textproto.MIMEHeader{"Content-Type": []string{contentType}})
+		if err != nil {
+			return "", err
+		}
+		if _, err := w.Write(part); err != nil {
+			return "", err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+// userDataContentType returns the cloud-init content type of the given user data.
+func userDataContentType(userData []byte) (string, error) {
+	switch {
+	case bytes.HasPrefix(userData, []byte("#cloud-config")):
+		return "text/cloud-config", nil
+	case bytes.HasPrefix(userData, []byte("#!")):
+		return "text/x-shellscript", nil
+	default:
+		return "", fmt.Errorf("user data must be a cloud-config or a shell script")
+	}
+}
@@ -1,15 +1,18 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: github.com/gardener/gardener-extension-provider-alicloud/pkg/alicloud/client (interfaces: VPC,Factory,ClientFactory,ECS,STS,SLB)
+// Source: github.com/gardener/gardener-extension-provider-alicloud/pkg/alicloud/client (interfaces: VPC,Factory,ClientFactory,ECS,STS,SLB,Storage)
 
 // Package client is a generated GoMock package.
 package client
 
 import (
 	context "context"
+	ecs "github.com/aliyun/alibaba-cloud-sdk-go/services/ecs"
 	vpc "github.com/aliyun/alibaba-cloud-sdk-go/services/vpc"
+	alicloud "github.com/gardener/gardener-extension-provider-alicloud/pkg/alicloud"
 	client "github.com/gardener/gardener-extension-provider-alicloud/pkg/alicloud/client"
 	gomock "github.com/golang/mock/gomock"
 	reflect "reflect"
+	time "time"
 )
 
 // MockVPC is a mock of VPC interface
@@ -35,6 +38,336 @@ func (m *MockVPC) EXPECT() *MockVPCMockRecorder {
 	return m.recorder
 }
 
+// AllocateEipAddress mocks base method
+func (m *MockVPC) AllocateEipAddress(arg0 *vpc.AllocateEipAddressRequest) (*vpc.AllocateEipAddressResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AllocateEipAddress", arg0)
+	ret0, _ := ret[0].(*vpc.AllocateEipAddressResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AllocateEipAddress indicates an expected call of AllocateEipAddress
+func (mr *MockVPCMockRecorder) AllocateEipAddress(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AllocateEipAddress", reflect.TypeOf((*MockVPC)(nil).AllocateEipAddress), arg0)
+}
+
+// AssociateEipAddress mocks base method
+func (m *MockVPC) AssociateEipAddress(arg0 *vpc.AssociateEipAddressRequest) (*vpc.AssociateEipAddressResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssociateEipAddress", arg0)
+	ret0, _ := ret[0].(*vpc.AssociateEipAddressResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AssociateEipAddress indicates an expected call of AssociateEipAddress
+func (mr *MockVPCMockRecorder) AssociateEipAddress(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssociateEipAddress", reflect.TypeOf((*MockVPC)(nil).AssociateEipAddress), arg0)
+}
+
+// AssociateRouteTable mocks base method
+func (m *MockVPC) AssociateRouteTable(arg0 *vpc.AssociateRouteTableRequest) (*vpc.AssociateRouteTableResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssociateRouteTable", arg0)
+	ret0, _ := ret[0].(*vpc.AssociateRouteTableResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AssociateRouteTable indicates an expected call of AssociateRouteTable
+func (mr *MockVPCMockRecorder) AssociateRouteTable(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssociateRouteTable", reflect.TypeOf((*MockVPC)(nil).AssociateRouteTable), arg0)
+}
+
+// AttachDhcpOptionsSetToVpc mocks base method
+func (m *MockVPC) AttachDhcpOptionsSetToVpc(arg0 *client.AttachDhcpOptionsSetToVpcRequest) (*client.DhcpOptionsSetResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AttachDhcpOptionsSetToVpc", arg0)
+	ret0, _ := ret[0].(*client.DhcpOptionsSetResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AttachDhcpOptionsSetToVpc indicates an expected call of AttachDhcpOptionsSetToVpc
+func (mr *MockVPCMockRecorder) AttachDhcpOptionsSetToVpc(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AttachDhcpOptionsSetToVpc", reflect.TypeOf((*MockVPC)(nil).AttachDhcpOptionsSetToVpc), arg0)
+}
+
+// CreateDhcpOptionsSet mocks base method
+func (m *MockVPC) CreateDhcpOptionsSet(arg0 *client.CreateDhcpOptionsSetRequest) (*client.CreateDhcpOptionsSetResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateDhcpOptionsSet", arg0)
+	ret0, _ := ret[0].(*client.CreateDhcpOptionsSetResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateDhcpOptionsSet indicates an expected call of CreateDhcpOptionsSet
+func (mr *MockVPCMockRecorder) CreateDhcpOptionsSet(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDhcpOptionsSet", reflect.TypeOf((*MockVPC)(nil).CreateDhcpOptionsSet), arg0)
+}
+
+// CreateFlowLog mocks base method
+func (m *MockVPC) CreateFlowLog(arg0 *vpc.CreateFlowLogRequest) (*vpc.CreateFlowLogResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateFlowLog", arg0)
+	ret0, _ := ret[0].(*vpc.CreateFlowLogResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateFlowLog indicates an expected call of CreateFlowLog
+func (mr *MockVPCMockRecorder) CreateFlowLog(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateFlowLog", reflect.TypeOf((*MockVPC)(nil).CreateFlowLog), arg0)
+}
+
+// CreateForwardEntry mocks base method
+func (m *MockVPC) CreateForwardEntry(arg0 *vpc.CreateForwardEntryRequest) (*vpc.CreateForwardEntryResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateForwardEntry", arg0)
+	ret0, _ := ret[0].(*vpc.CreateForwardEntryResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateForwardEntry indicates an expected call of CreateForwardEntry
+func (mr *MockVPCMockRecorder) CreateForwardEntry(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateForwardEntry", reflect.TypeOf((*MockVPC)(nil).CreateForwardEntry), arg0)
+}
+
+// CreateNatGateway mocks base method
+func (m *MockVPC) CreateNatGateway(arg0 *vpc.CreateNatGatewayRequest) (*vpc.CreateNatGatewayResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateNatGateway", arg0)
+	ret0, _ := ret[0].(*vpc.CreateNatGatewayResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateNatGateway indicates an expected call of CreateNatGateway
+func (mr *MockVPCMockRecorder) CreateNatGateway(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateNatGateway", reflect.TypeOf((*MockVPC)(nil).CreateNatGateway), arg0)
+}
+
+// CreateRouteEntry mocks base method
+func (m *MockVPC) CreateRouteEntry(arg0 *vpc.CreateRouteEntryRequest) (*vpc.CreateRouteEntryResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRouteEntry", arg0)
+	ret0, _ := ret[0].(*vpc.CreateRouteEntryResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateRouteEntry indicates an expected call of CreateRouteEntry
+func (mr *MockVPCMockRecorder) CreateRouteEntry(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRouteEntry", reflect.TypeOf((*MockVPC)(nil).CreateRouteEntry), arg0)
+}
+
+// CreateRouteTable mocks base method
+func (m *MockVPC) CreateRouteTable(arg0 *vpc.CreateRouteTableRequest) (*vpc.CreateRouteTableResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRouteTable", arg0)
+	ret0, _ := ret[0].(*vpc.CreateRouteTableResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateRouteTable indicates an expected call of CreateRouteTable
+func (mr *MockVPCMockRecorder) CreateRouteTable(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRouteTable", reflect.TypeOf((*MockVPC)(nil).CreateRouteTable), arg0)
+}
+
+// CreateSnatEntry mocks base method
+func (m *MockVPC) CreateSnatEntry(arg0 *vpc.CreateSnatEntryRequest) (*vpc.CreateSnatEntryResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSnatEntry", arg0)
+	ret0, _ := ret[0].(*vpc.CreateSnatEntryResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSnatEntry indicates an expected call of CreateSnatEntry
+func (mr *MockVPCMockRecorder) CreateSnatEntry(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSnatEntry", reflect.TypeOf((*MockVPC)(nil).CreateSnatEntry), arg0)
+}
+
+// CreateVSwitch mocks base method
+func (m *MockVPC) CreateVSwitch(arg0 *vpc.CreateVSwitchRequest) (*vpc.CreateVSwitchResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateVSwitch", arg0)
+	ret0, _ := ret[0].(*vpc.CreateVSwitchResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateVSwitch indicates an expected call of CreateVSwitch
+func (mr *MockVPCMockRecorder) CreateVSwitch(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateVSwitch", reflect.TypeOf((*MockVPC)(nil).CreateVSwitch), arg0)
+}
+
+// CreateVpc mocks base method
+func (m *MockVPC) CreateVpc(arg0 *vpc.CreateVpcRequest) (*vpc.CreateVpcResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateVpc", arg0)
+	ret0, _ := ret[0].(*vpc.CreateVpcResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateVpc indicates an expected call of CreateVpc
+func (mr *MockVPCMockRecorder) CreateVpc(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateVpc", reflect.TypeOf((*MockVPC)(nil).CreateVpc), arg0)
+}
+
+// DeleteDhcpOptionsSet mocks base method
+func (m *MockVPC) DeleteDhcpOptionsSet(arg0 *client.DeleteDhcpOptionsSetRequest) (*client.DhcpOptionsSetResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteDhcpOptionsSet", arg0)
+	ret0, _ := ret[0].(*client.DhcpOptionsSetResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteDhcpOptionsSet indicates an expected call of DeleteDhcpOptionsSet
+func (mr *MockVPCMockRecorder) DeleteDhcpOptionsSet(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDhcpOptionsSet", reflect.TypeOf((*MockVPC)(nil).DeleteDhcpOptionsSet), arg0)
+}
+
+// DeleteFlowLog mocks base method
+func (m *MockVPC) DeleteFlowLog(arg0 *vpc.DeleteFlowLogRequest) (*vpc.DeleteFlowLogResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteFlowLog", arg0)
+	ret0, _ := ret[0].(*vpc.DeleteFlowLogResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteFlowLog indicates an expected call of DeleteFlowLog
+func (mr *MockVPCMockRecorder) DeleteFlowLog(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFlowLog", reflect.TypeOf((*MockVPC)(nil).DeleteFlowLog), arg0)
+}
+
+// DeleteForwardEntry mocks base method
+func (m *MockVPC) DeleteForwardEntry(arg0 *vpc.DeleteForwardEntryRequest) (*vpc.DeleteForwardEntryResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteForwardEntry", arg0)
+	ret0, _ := ret[0].(*vpc.DeleteForwardEntryResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteForwardEntry indicates an expected call of DeleteForwardEntry
+func (mr *MockVPCMockRecorder) DeleteForwardEntry(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteForwardEntry", reflect.TypeOf((*MockVPC)(nil).DeleteForwardEntry), arg0)
+}
+
+// DeleteNatGateway mocks base method
+func (m *MockVPC) DeleteNatGateway(arg0 *vpc.DeleteNatGatewayRequest) (*vpc.DeleteNatGatewayResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteNatGateway", arg0)
+	ret0, _ := ret[0].(*vpc.DeleteNatGatewayResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteNatGateway indicates an expected call of DeleteNatGateway
+func (mr *MockVPCMockRecorder) DeleteNatGateway(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteNatGateway", reflect.TypeOf((*MockVPC)(nil).DeleteNatGateway), arg0)
+}
+
+// DeleteRouteEntry mocks base method
+func (m *MockVPC) DeleteRouteEntry(arg0 *vpc.DeleteRouteEntryRequest) (*vpc.DeleteRouteEntryResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteRouteEntry", arg0)
+	ret0, _ := ret[0].(*vpc.DeleteRouteEntryResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteRouteEntry indicates an expected call of DeleteRouteEntry
+func (mr *MockVPCMockRecorder) DeleteRouteEntry(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRouteEntry", reflect.TypeOf((*MockVPC)(nil).DeleteRouteEntry), arg0)
+}
+
+// DeleteRouteTable mocks base method
+func (m *MockVPC) DeleteRouteTable(arg0 *vpc.DeleteRouteTableRequest) (*vpc.DeleteRouteTableResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteRouteTable", arg0)
+	ret0, _ := ret[0].(*vpc.DeleteRouteTableResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteRouteTable indicates an expected call of DeleteRouteTable
+func (mr *MockVPCMockRecorder) DeleteRouteTable(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRouteTable", reflect.TypeOf((*MockVPC)(nil).DeleteRouteTable), arg0)
+}
+
+// DeleteSnatEntry mocks base method
+func (m *MockVPC) DeleteSnatEntry(arg0 *vpc.DeleteSnatEntryRequest) (*vpc.DeleteSnatEntryResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSnatEntry", arg0)
+	ret0, _ := ret[0].(*vpc.DeleteSnatEntryResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteSnatEntry indicates an expected call of DeleteSnatEntry
+func (mr *MockVPCMockRecorder) DeleteSnatEntry(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSnatEntry", reflect.TypeOf((*MockVPC)(nil).DeleteSnatEntry), arg0)
+}
+
+// DeleteVSwitch mocks base method
+func (m *MockVPC) DeleteVSwitch(arg0 *vpc.DeleteVSwitchRequest) (*vpc.DeleteVSwitchResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteVSwitch", arg0)
+	ret0, _ := ret[0].(*vpc.DeleteVSwitchResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteVSwitch indicates an expected call of DeleteVSwitch
+func (mr *MockVPCMockRecorder) DeleteVSwitch(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteVSwitch", reflect.TypeOf((*MockVPC)(nil).DeleteVSwitch), arg0)
+}
+
+// DeleteVpc mocks base method
+func (m *MockVPC) DeleteVpc(arg0 *vpc.DeleteVpcRequest) (*vpc.DeleteVpcResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteVpc", arg0)
+	ret0, _ := ret[0].(*vpc.DeleteVpcResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteVpc indicates an expected call of DeleteVpc
+func (mr *MockVPCMockRecorder) DeleteVpc(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteVpc", reflect.TypeOf((*MockVPC)(nil).DeleteVpc), arg0)
+}
+
 // DescribeEipAddresses mocks base method
 func (m *MockVPC) DescribeEipAddresses(arg0 *vpc.DescribeEipAddressesRequest) (*vpc.DescribeEipAddressesResponse, error) {
 	m.ctrl.T.Helper()
@@ -50,6 +383,36 @@ func (mr *MockVPCMockRecorder) DescribeEipAddresses(arg0 interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeEipAddresses", reflect.TypeOf((*MockVPC)(nil).DescribeEipAddresses), arg0)
 }
 
+// DescribeFlowLogs mocks base method
+func (m *MockVPC) DescribeFlowLogs(arg0 *vpc.DescribeFlowLogsRequest) (*vpc.DescribeFlowLogsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DescribeFlowLogs", arg0)
+	ret0, _ := ret[0].(*vpc.DescribeFlowLogsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeFlowLogs indicates an expected call of DescribeFlowLogs
+func (mr *MockVPCMockRecorder) DescribeFlowLogs(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeFlowLogs", reflect.TypeOf((*MockVPC)(nil).DescribeFlowLogs), arg0)
+}
+
+// DescribeForwardTableEntries mocks base method
+func (m *MockVPC) DescribeForwardTableEntries(arg0 *vpc.DescribeForwardTableEntriesRequest) (*vpc.DescribeForwardTableEntriesResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DescribeForwardTableEntries", arg0)
+	ret0, _ := ret[0].(*vpc.DescribeForwardTableEntriesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeForwardTableEntries indicates an expected call of DescribeForwardTableEntries
+func (mr *MockVPCMockRecorder) DescribeForwardTableEntries(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeForwardTableEntries", reflect.TypeOf((*MockVPC)(nil).DescribeForwardTableEntries), arg0)
+}
+
 // DescribeNatGateways mocks base method
 func (m *MockVPC) DescribeNatGateways(arg0 *vpc.DescribeNatGatewaysRequest) (*vpc.DescribeNatGatewaysResponse, error) {
 	m.ctrl.T.Helper()
@@ -65,6 +428,66 @@ func (mr *MockVPCMockRecorder) DescribeNatGateways(arg0 interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeNatGateways", reflect.TypeOf((*MockVPC)(nil).DescribeNatGateways), arg0)
 }
 
+// DescribeRouteEntryList mocks base method
+func (m *MockVPC) DescribeRouteEntryList(arg0 *vpc.DescribeRouteEntryListRequest) (*vpc.DescribeRouteEntryListResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DescribeRouteEntryList", arg0)
+	ret0, _ := ret[0].(*vpc.DescribeRouteEntryListResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeRouteEntryList indicates an expected call of DescribeRouteEntryList
+func (mr *MockVPCMockRecorder) DescribeRouteEntryList(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeRouteEntryList", reflect.TypeOf((*MockVPC)(nil).DescribeRouteEntryList), arg0)
+}
+
+// DescribeRouteTableList mocks base method
+func (m *MockVPC) DescribeRouteTableList(arg0 *vpc.DescribeRouteTableListRequest) (*vpc.DescribeRouteTableListResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DescribeRouteTableList", arg0)
+	ret0, _ := ret[0].(*vpc.DescribeRouteTableListResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeRouteTableList indicates an expected call of DescribeRouteTableList
+func (mr *MockVPCMockRecorder) DescribeRouteTableList(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeRouteTableList", reflect.TypeOf((*MockVPC)(nil).DescribeRouteTableList), arg0)
+}
+
+// DescribeSnatTableEntries mocks base method
+func (m *MockVPC) DescribeSnatTableEntries(arg0 *vpc.DescribeSnatTableEntriesRequest) (*vpc.DescribeSnatTableEntriesResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DescribeSnatTableEntries", arg0)
+	ret0, _ := ret[0].(*vpc.DescribeSnatTableEntriesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeSnatTableEntries indicates an expected call of DescribeSnatTableEntries
+func (mr *MockVPCMockRecorder) DescribeSnatTableEntries(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeSnatTableEntries", reflect.TypeOf((*MockVPC)(nil).DescribeSnatTableEntries), arg0)
+}
+
+// DescribeVSwitches mocks base method
+func (m *MockVPC) DescribeVSwitches(arg0 *vpc.DescribeVSwitchesRequest) (*vpc.DescribeVSwitchesResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DescribeVSwitches", arg0)
+	ret0, _ := ret[0].(*vpc.DescribeVSwitchesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeVSwitches indicates an expected call of DescribeVSwitches
+func (mr *MockVPCMockRecorder) DescribeVSwitches(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeVSwitches", reflect.TypeOf((*MockVPC)(nil).DescribeVSwitches), arg0)
+}
+
 // DescribeVpcs mocks base method
 func (m *MockVPC) DescribeVpcs(arg0 *vpc.DescribeVpcsRequest) (*vpc.DescribeVpcsResponse, error) {
 	m.ctrl.T.Helper()
@@ -80,6 +503,126 @@ func (mr *MockVPCMockRecorder) DescribeVpcs(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeVpcs", reflect.TypeOf((*MockVPC)(nil).DescribeVpcs), arg0)
 }
 
+// DetachDhcpOptionsSetFromVpc mocks base method
+func (m *MockVPC) DetachDhcpOptionsSetFromVpc(arg0 *client.DetachDhcpOptionsSetFromVpcRequest) (*client.DhcpOptionsSetResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DetachDhcpOptionsSetFromVpc", arg0)
+	ret0, _ := ret[0].(*client.DhcpOptionsSetResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DetachDhcpOptionsSetFromVpc indicates an expected call of DetachDhcpOptionsSetFromVpc
+func (mr *MockVPCMockRecorder) DetachDhcpOptionsSetFromVpc(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DetachDhcpOptionsSetFromVpc", reflect.TypeOf((*MockVPC)(nil).DetachDhcpOptionsSetFromVpc), arg0)
+}
+
+// GetDhcpOptionsSet mocks base method
+func (m *MockVPC) GetDhcpOptionsSet(arg0 *client.GetDhcpOptionsSetRequest) (*client.GetDhcpOptionsSetResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDhcpOptionsSet", arg0)
+	ret0, _ := ret[0].(*client.GetDhcpOptionsSetResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDhcpOptionsSet indicates an expected call of GetDhcpOptionsSet
+func (mr *MockVPCMockRecorder) GetDhcpOptionsSet(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDhcpOptionsSet", reflect.TypeOf((*MockVPC)(nil).GetDhcpOptionsSet), arg0)
+}
+
+// ListTagResources mocks base method
+func (m *MockVPC) ListTagResources(arg0 *vpc.ListTagResourcesRequest) (*vpc.ListTagResourcesResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTagResources", arg0)
+	ret0, _ := ret[0].(*vpc.ListTagResourcesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTagResources indicates an expected call of ListTagResources
+func (mr *MockVPCMockRecorder) ListTagResources(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTagResources", reflect.TypeOf((*MockVPC)(nil).ListTagResources), arg0)
+}
+
+// ReleaseEipAddress mocks base method
+func (m *MockVPC) ReleaseEipAddress(arg0 *vpc.ReleaseEipAddressRequest) (*vpc.ReleaseEipAddressResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseEipAddress", arg0)
+	ret0, _ := ret[0].(*vpc.ReleaseEipAddressResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReleaseEipAddress indicates an expected call of ReleaseEipAddress
+func (mr *MockVPCMockRecorder) ReleaseEipAddress(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseEipAddress", reflect.TypeOf((*MockVPC)(nil).ReleaseEipAddress), arg0)
+}
+
+// TagResources mocks base method
+func (m *MockVPC) TagResources(arg0 *vpc.TagResourcesRequest) (*vpc.TagResourcesResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TagResources", arg0)
+	ret0, _ := ret[0].(*vpc.TagResourcesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TagResources indicates an expected call of TagResources
+func (mr *MockVPCMockRecorder) TagResources(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TagResources", reflect.TypeOf((*MockVPC)(nil).TagResources), arg0)
+}
+
+// UnassociateEipAddress mocks base method
+func (m *MockVPC) UnassociateEipAddress(arg0 *vpc.UnassociateEipAddressRequest) (*vpc.UnassociateEipAddressResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnassociateEipAddress", arg0)
+	ret0, _ := ret[0].(*vpc.UnassociateEipAddressResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UnassociateEipAddress indicates an expected call of UnassociateEipAddress
+func (mr *MockVPCMockRecorder) UnassociateEipAddress(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnassociateEipAddress", reflect.TypeOf((*MockVPC)(nil).UnassociateEipAddress), arg0)
+}
+
+// UnassociateRouteTable mocks base method
+func (m *MockVPC) UnassociateRouteTable(arg0 *vpc.UnassociateRouteTableRequest) (*vpc.UnassociateRouteTableResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnassociateRouteTable", arg0)
+	ret0, _ := ret[0].(*vpc.UnassociateRouteTableResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UnassociateRouteTable indicates an expected call of UnassociateRouteTable
+func (mr *MockVPCMockRecorder) UnassociateRouteTable(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnassociateRouteTable", reflect.TypeOf((*MockVPC)(nil).UnassociateRouteTable), arg0)
+}
+
+// UpdateDhcpOptionsSetAttribute mocks base method
+func (m *MockVPC) UpdateDhcpOptionsSetAttribute(arg0 *client.UpdateDhcpOptionsSetAttributeRequest) (*client.DhcpOptionsSetResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateDhcpOptionsSetAttribute", arg0)
+	ret0, _ := ret[0].(*client.DhcpOptionsSetResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateDhcpOptionsSetAttribute indicates an expected call of UpdateDhcpOptionsSetAttribute
+func (mr *MockVPCMockRecorder) UpdateDhcpOptionsSetAttribute(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateDhcpOptionsSetAttribute", reflect.TypeOf((*MockVPC)(nil).UpdateDhcpOptionsSetAttribute), arg0)
+}
+
 // MockFactory is a mock of Factory interface
 type MockFactory struct {
 	ctrl     *gomock.Controller
@@ -104,7 +647,7 @@ func (m *MockFactory) EXPECT() *MockFactoryMockRecorder {
 }
 
 // NewVPC mocks base method
-func (m *MockFactory) NewVPC(arg0, arg1, arg2 string) (client.VPC, error) {
+func (m *MockFactory) NewVPC(arg0 context.Context, arg1 string, arg2 *alicloud.Credentials) (client.VPC, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "NewVPC", arg0, arg1, arg2)
 	ret0, _ := ret[0].(client.VPC)
@@ -141,87 +684,408 @@ func (m *MockClientFactory) EXPECT() *MockClientFactoryMockRecorder {
 	return m.recorder
 }
 
-// NewECSClient mocks base method
-func (m *MockClientFactory) NewECSClient(arg0 context.Context, arg1, arg2, arg3 string) (client.ECS, error) {
+// NewECSClient mocks base method
+func (m *MockClientFactory) NewECSClient(arg0 context.Context, arg1 string, arg2 *alicloud.Credentials) (client.ECS, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NewECSClient", arg0, arg1, arg2)
+	ret0, _ := ret[0].(client.ECS)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NewECSClient indicates an expected call of NewECSClient
+func (mr *MockClientFactoryMockRecorder) NewECSClient(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewECSClient", reflect.TypeOf((*MockClientFactory)(nil).NewECSClient), arg0, arg1, arg2)
+}
+
+// NewSLBClient mocks base method
+func (m *MockClientFactory) NewSLBClient(arg0 context.Context, arg1 string, arg2 *alicloud.Credentials) (client.SLB, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NewSLBClient", arg0, arg1, arg2)
+	ret0, _ := ret[0].(client.SLB)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NewSLBClient indicates an expected call of NewSLBClient
+func (mr *MockClientFactoryMockRecorder) NewSLBClient(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewSLBClient", reflect.TypeOf((*MockClientFactory)(nil).NewSLBClient), arg0, arg1, arg2)
+}
+
+// NewSTSClient mocks base method
+func (m *MockClientFactory) NewSTSClient(arg0 context.Context, arg1 string, arg2 *alicloud.Credentials) (client.STS, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NewSTSClient", arg0, arg1, arg2)
+	ret0, _ := ret[0].(client.STS)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NewSTSClient indicates an expected call of NewSTSClient
+func (mr *MockClientFactoryMockRecorder) NewSTSClient(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewSTSClient", reflect.TypeOf((*MockClientFactory)(nil).NewSTSClient), arg0, arg1, arg2)
+}
+
+// MockECS is a mock of ECS interface
+type MockECS struct {
+	ctrl     *gomock.Controller
+	recorder *MockECSMockRecorder
+}
+
+// MockECSMockRecorder is the mock recorder for MockECS
+type MockECSMockRecorder struct {
+	mock *MockECS
+}
+
+// NewMockECS creates a new mock instance
+func NewMockECS(ctrl *gomock.Controller) *MockECS {
+	mock := &MockECS{ctrl: ctrl}
+	mock.recorder = &MockECSMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockECS) EXPECT() *MockECSMockRecorder {
+	return m.recorder
+}
+
+// AuthorizeSecurityGroupEgress mocks base method
+func (m *MockECS) AuthorizeSecurityGroupEgress(arg0 context.Context, arg1, arg2, arg3, arg4 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AuthorizeSecurityGroupEgress", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AuthorizeSecurityGroupEgress indicates an expected call of AuthorizeSecurityGroupEgress
+func (mr *MockECSMockRecorder) AuthorizeSecurityGroupEgress(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuthorizeSecurityGroupEgress", reflect.TypeOf((*MockECS)(nil).AuthorizeSecurityGroupEgress), arg0, arg1, arg2, arg3, arg4)
+}
+
+// AuthorizeSecurityGroupIngress mocks base method
+func (m *MockECS) AuthorizeSecurityGroupIngress(arg0 context.Context, arg1, arg2, arg3, arg4 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AuthorizeSecurityGroupIngress", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AuthorizeSecurityGroupIngress indicates an expected call of AuthorizeSecurityGroupIngress
+func (mr *MockECSMockRecorder) AuthorizeSecurityGroupIngress(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuthorizeSecurityGroupIngress", reflect.TypeOf((*MockECS)(nil).AuthorizeSecurityGroupIngress), arg0, arg1, arg2, arg3, arg4)
+}
+
+// CheckIfImageExists mocks base method
+func (m *MockECS) CheckIfImageExists(arg0 context.Context, arg1 string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckIfImageExists", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckIfImageExists indicates an expected call of CheckIfImageExists
+func (mr *MockECSMockRecorder) CheckIfImageExists(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckIfImageExists", reflect.TypeOf((*MockECS)(nil).CheckIfImageExists), arg0, arg1)
+}
+
+// CheckIfKeyPairExists mocks base method
+func (m *MockECS) CheckIfKeyPairExists(arg0 context.Context, arg1 string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckIfKeyPairExists", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckIfKeyPairExists indicates an expected call of CheckIfKeyPairExists
+func (mr *MockECSMockRecorder) CheckIfKeyPairExists(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckIfKeyPairExists", reflect.TypeOf((*MockECS)(nil).CheckIfKeyPairExists), arg0, arg1)
+}
+
+// CheckIfSecurityGroupExists mocks base method
+func (m *MockECS) CheckIfSecurityGroupExists(arg0 context.Context, arg1 string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckIfSecurityGroupExists", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckIfSecurityGroupExists indicates an expected call of CheckIfSecurityGroupExists
+func (mr *MockECSMockRecorder) CheckIfSecurityGroupExists(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckIfSecurityGroupExists", reflect.TypeOf((*MockECS)(nil).CheckIfSecurityGroupExists), arg0, arg1)
+}
+
+// CopyEncryptedImage mocks base method
+func (m *MockECS) CopyEncryptedImage(arg0 context.Context, arg1, arg2, arg3 string, arg4 *string, arg5 map[string]string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CopyEncryptedImage", arg0, arg1, arg2, arg3, arg4, arg5)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CopyEncryptedImage indicates an expected call of CopyEncryptedImage
+func (mr *MockECSMockRecorder) CopyEncryptedImage(arg0, arg1, arg2, arg3, arg4, arg5 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CopyEncryptedImage", reflect.TypeOf((*MockECS)(nil).CopyEncryptedImage), arg0, arg1, arg2, arg3, arg4, arg5)
+}
+
+// CreateSecurityGroup mocks base method
+func (m *MockECS) CreateSecurityGroup(arg0 context.Context, arg1, arg2 string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSecurityGroup", arg0, arg1, arg2)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSecurityGroup indicates an expected call of CreateSecurityGroup
+func (mr *MockECSMockRecorder) CreateSecurityGroup(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSecurityGroup", reflect.TypeOf((*MockECS)(nil).CreateSecurityGroup), arg0, arg1, arg2)
+}
+
+// DeleteImage mocks base method
+func (m *MockECS) DeleteImage(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteImage", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteImage indicates an expected call of DeleteImage
+func (mr *MockECSMockRecorder) DeleteImage(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteImage", reflect.TypeOf((*MockECS)(nil).DeleteImage), arg0, arg1, arg2)
+}
+
+// DeleteKeyPair mocks base method
+func (m *MockECS) DeleteKeyPair(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteKeyPair", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteKeyPair indicates an expected call of DeleteKeyPair
+func (mr *MockECSMockRecorder) DeleteKeyPair(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteKeyPair", reflect.TypeOf((*MockECS)(nil).DeleteKeyPair), arg0, arg1)
+}
+
+// DeleteSecurityGroup mocks base method
+func (m *MockECS) DeleteSecurityGroup(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSecurityGroup", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSecurityGroup indicates an expected call of DeleteSecurityGroup
+func (mr *MockECSMockRecorder) DeleteSecurityGroup(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSecurityGroup", reflect.TypeOf((*MockECS)(nil).DeleteSecurityGroup), arg0, arg1)
+}
+
+// GetAvailableImageByName mocks base method
+func (m *MockECS) GetAvailableImageByName(arg0 context.Context, arg1 string) (*ecs.Image, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAvailableImageByName", arg0, arg1)
+	ret0, _ := ret[0].(*ecs.Image)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAvailableImageByName indicates an expected call of GetAvailableImageByName
+func (mr *MockECSMockRecorder) GetAvailableImageByName(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAvailableImageByName", reflect.TypeOf((*MockECS)(nil).GetAvailableImageByName), arg0, arg1)
+}
+
+// GetAvailableInstanceTypes mocks base method
+func (m *MockECS) GetAvailableInstanceTypes(arg0 context.Context, arg1, arg2, arg3 string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAvailableInstanceTypes", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAvailableInstanceTypes indicates an expected call of GetAvailableInstanceTypes
+func (mr *MockECSMockRecorder) GetAvailableInstanceTypes(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAvailableInstanceTypes", reflect.TypeOf((*MockECS)(nil).GetAvailableInstanceTypes), arg0, arg1, arg2, arg3)
+}
+
+// GetDeploymentSet mocks base method
+func (m *MockECS) GetDeploymentSet(arg0 context.Context, arg1, arg2 string) (*ecs.DeploymentSet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeploymentSet", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*ecs.DeploymentSet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeploymentSet indicates an expected call of GetDeploymentSet
+func (mr *MockECSMockRecorder) GetDeploymentSet(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeploymentSet", reflect.TypeOf((*MockECS)(nil).GetDeploymentSet), arg0, arg1, arg2)
+}
+
+// GetImageByName mocks base method
+func (m *MockECS) GetImageByName(arg0 context.Context, arg1 string) (*ecs.Image, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetImageByName", arg0, arg1)
+	ret0, _ := ret[0].(*ecs.Image)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetImageByName indicates an expected call of GetImageByName
+func (mr *MockECSMockRecorder) GetImageByName(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetImageByName", reflect.TypeOf((*MockECS)(nil).GetImageByName), arg0, arg1)
+}
+
+// GetImagesByTags mocks base method
+func (m *MockECS) GetImagesByTags(arg0 context.Context, arg1 map[string]string) ([]ecs.Image, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetImagesByTags", arg0, arg1)
+	ret0, _ := ret[0].([]ecs.Image)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetImagesByTags indicates an expected call of GetImagesByTags
+func (mr *MockECSMockRecorder) GetImagesByTags(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetImagesByTags", reflect.TypeOf((*MockECS)(nil).GetImagesByTags), arg0, arg1)
+}
+
+// GetPrePaidInstancesByTags mocks base method
+func (m *MockECS) GetPrePaidInstancesByTags(arg0 context.Context, arg1 string, arg2 map[string]string) ([]ecs.Instance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPrePaidInstancesByTags", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]ecs.Instance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPrePaidInstancesByTags indicates an expected call of GetPrePaidInstancesByTags
+func (mr *MockECSMockRecorder) GetPrePaidInstancesByTags(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPrePaidInstancesByTags", reflect.TypeOf((*MockECS)(nil).GetPrePaidInstancesByTags), arg0, arg1, arg2)
+}
+
+// GetSecurityGroup mocks base method
+func (m *MockECS) GetSecurityGroup(arg0 context.Context, arg1 string) (*ecs.SecurityGroup, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "NewECSClient", arg0, arg1, arg2, arg3)
-	ret0, _ := ret[0].(client.ECS)
+	ret := m.ctrl.Call(m, "GetSecurityGroup", arg0, arg1)
+	ret0, _ := ret[0].(*ecs.SecurityGroup)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// NewECSClient indicates an expected call of NewECSClient
-func (mr *MockClientFactoryMockRecorder) NewECSClient(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+// GetSecurityGroup indicates an expected call of GetSecurityGroup
+func (mr *MockECSMockRecorder) GetSecurityGroup(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewECSClient", reflect.TypeOf((*MockClientFactory)(nil).NewECSClient), arg0, arg1, arg2, arg3)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSecurityGroup", reflect.TypeOf((*MockECS)(nil).GetSecurityGroup), arg0, arg1)
 }
 
-// NewSLBClient mocks base method
-func (m *MockClientFactory) NewSLBClient(arg0 context.Context, arg1, arg2, arg3 string) (client.SLB, error) {
+// GetSecurityGroupRules mocks base method
+func (m *MockECS) GetSecurityGroupRules(arg0 context.Context, arg1 string) ([]ecs.Permission, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "NewSLBClient", arg0, arg1, arg2, arg3)
-	ret0, _ := ret[0].(client.SLB)
+	ret := m.ctrl.Call(m, "GetSecurityGroupRules", arg0, arg1)
+	ret0, _ := ret[0].([]ecs.Permission)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// NewSLBClient indicates an expected call of NewSLBClient
-func (mr *MockClientFactoryMockRecorder) NewSLBClient(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+// GetSecurityGroupRules indicates an expected call of GetSecurityGroupRules
+func (mr *MockECSMockRecorder) GetSecurityGroupRules(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewSLBClient", reflect.TypeOf((*MockClientFactory)(nil).NewSLBClient), arg0, arg1, arg2, arg3)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSecurityGroupRules", reflect.TypeOf((*MockECS)(nil).GetSecurityGroupRules), arg0, arg1)
 }
 
-// NewSTSClient mocks base method
-func (m *MockClientFactory) NewSTSClient(arg0 context.Context, arg1, arg2, arg3 string) (client.STS, error) {
+// GetSecurityGroupsByTags mocks base method
+func (m *MockECS) GetSecurityGroupsByTags(arg0 context.Context, arg1 string, arg2 map[string]string) ([]ecs.SecurityGroup, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "NewSTSClient", arg0, arg1, arg2, arg3)
-	ret0, _ := ret[0].(client.STS)
+	ret := m.ctrl.Call(m, "GetSecurityGroupsByTags", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]ecs.SecurityGroup)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// NewSTSClient indicates an expected call of NewSTSClient
-func (mr *MockClientFactoryMockRecorder) NewSTSClient(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+// GetSecurityGroupsByTags indicates an expected call of GetSecurityGroupsByTags
+func (mr *MockECSMockRecorder) GetSecurityGroupsByTags(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewSTSClient", reflect.TypeOf((*MockClientFactory)(nil).NewSTSClient), arg0, arg1, arg2, arg3)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSecurityGroupsByTags", reflect.TypeOf((*MockECS)(nil).GetSecurityGroupsByTags), arg0, arg1, arg2)
 }
 
-// MockECS is a mock of ECS interface
-type MockECS struct {
-	ctrl     *gomock.Controller
-	recorder *MockECSMockRecorder
+// ImportKeyPair mocks base method
+func (m *MockECS) ImportKeyPair(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportKeyPair", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// MockECSMockRecorder is the mock recorder for MockECS
-type MockECSMockRecorder struct {
-	mock *MockECS
+// ImportKeyPair indicates an expected call of ImportKeyPair
+func (mr *MockECSMockRecorder) ImportKeyPair(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportKeyPair", reflect.TypeOf((*MockECS)(nil).ImportKeyPair), arg0, arg1, arg2)
 }
 
-// NewMockECS creates a new mock instance
-func NewMockECS(ctrl *gomock.Controller) *MockECS {
-	mock := &MockECS{ctrl: ctrl}
-	mock.recorder = &MockECSMockRecorder{mock}
-	return mock
+// RevokeSecurityGroupEgress mocks base method
+func (m *MockECS) RevokeSecurityGroupEgress(arg0 context.Context, arg1, arg2, arg3, arg4 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeSecurityGroupEgress", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// EXPECT returns an object that allows the caller to indicate expected use
-func (m *MockECS) EXPECT() *MockECSMockRecorder {
-	return m.recorder
+// RevokeSecurityGroupEgress indicates an expected call of RevokeSecurityGroupEgress
+func (mr *MockECSMockRecorder) RevokeSecurityGroupEgress(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeSecurityGroupEgress", reflect.TypeOf((*MockECS)(nil).RevokeSecurityGroupEgress), arg0, arg1, arg2, arg3, arg4)
 }
 
-// CheckIfImageExists mocks base method
-func (m *MockECS) CheckIfImageExists(arg0 context.Context, arg1 string) (bool, error) {
+// RevokeSecurityGroupIngress mocks base method
+func (m *MockECS) RevokeSecurityGroupIngress(arg0 context.Context, arg1, arg2, arg3, arg4 string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CheckIfImageExists", arg0, arg1)
-	ret0, _ := ret[0].(bool)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "RevokeSecurityGroupIngress", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// CheckIfImageExists indicates an expected call of CheckIfImageExists
-func (mr *MockECSMockRecorder) CheckIfImageExists(arg0, arg1 interface{}) *gomock.Call {
+// RevokeSecurityGroupIngress indicates an expected call of RevokeSecurityGroupIngress
+func (mr *MockECSMockRecorder) RevokeSecurityGroupIngress(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckIfImageExists", reflect.TypeOf((*MockECS)(nil).CheckIfImageExists), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeSecurityGroupIngress", reflect.TypeOf((*MockECS)(nil).RevokeSecurityGroupIngress), arg0, arg1, arg2, arg3, arg4)
+}
+
+// RevokeSecurityGroupPermission mocks base method
+func (m *MockECS) RevokeSecurityGroupPermission(arg0 context.Context, arg1 string, arg2 ecs.Permission) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeSecurityGroupPermission", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeSecurityGroupPermission indicates an expected call of RevokeSecurityGroupPermission
+func (mr *MockECSMockRecorder) RevokeSecurityGroupPermission(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeSecurityGroupPermission", reflect.TypeOf((*MockECS)(nil).RevokeSecurityGroupPermission), arg0, arg1, arg2)
 }
 
 // ShareImageToAccount mocks base method
@@ -238,6 +1102,34 @@ func (mr *MockECSMockRecorder) ShareImageToAccount(arg0, arg1, arg2, arg3 interf
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ShareImageToAccount", reflect.TypeOf((*MockECS)(nil).ShareImageToAccount), arg0, arg1, arg2, arg3)
 }
 
+// TagResources mocks base method
+func (m *MockECS) TagResources(arg0 context.Context, arg1 string, arg2 []string, arg3 map[string]string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TagResources", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TagResources indicates an expected call of TagResources
+func (mr *MockECSMockRecorder) TagResources(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TagResources", reflect.TypeOf((*MockECS)(nil).TagResources), arg0, arg1, arg2, arg3)
+}
+
+// UntagResources mocks base method
+func (m *MockECS) UntagResources(arg0 context.Context, arg1 string, arg2, arg3 []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UntagResources", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UntagResources indicates an expected call of UntagResources
+func (mr *MockECSMockRecorder) UntagResources(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UntagResources", reflect.TypeOf((*MockECS)(nil).UntagResources), arg0, arg1, arg2, arg3)
+}
+
 // MockSTS is a mock of STS interface
 type MockSTS struct {
 	ctrl     *gomock.Controller
@@ -299,6 +1191,49 @@ func (m *MockSLB) EXPECT() *MockSLBMockRecorder {
 	return m.recorder
 }
 
+// AddAccessControlListEntries mocks base method
+func (m *MockSLB) AddAccessControlListEntries(arg0 context.Context, arg1, arg2 string, arg3 []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddAccessControlListEntries", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddAccessControlListEntries indicates an expected call of AddAccessControlListEntries
+func (mr *MockSLBMockRecorder) AddAccessControlListEntries(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddAccessControlListEntries", reflect.TypeOf((*MockSLB)(nil).AddAccessControlListEntries), arg0, arg1, arg2, arg3)
+}
+
+// CreateAccessControlList mocks base method
+func (m *MockSLB) CreateAccessControlList(arg0 context.Context, arg1, arg2 string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAccessControlList", arg0, arg1, arg2)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAccessControlList indicates an expected call of CreateAccessControlList
+func (mr *MockSLBMockRecorder) CreateAccessControlList(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccessControlList", reflect.TypeOf((*MockSLB)(nil).CreateAccessControlList), arg0, arg1, arg2)
+}
+
+// DeleteAccessControlList mocks base method
+func (m *MockSLB) DeleteAccessControlList(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAccessControlList", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAccessControlList indicates an expected call of DeleteAccessControlList
+func (mr *MockSLBMockRecorder) DeleteAccessControlList(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAccessControlList", reflect.TypeOf((*MockSLB)(nil).DeleteAccessControlList), arg0, arg1, arg2)
+}
+
 // DeleteLoadBalancer mocks base method
 func (m *MockSLB) DeleteLoadBalancer(arg0 context.Context, arg1, arg2 string) error {
 	m.ctrl.T.Helper()
@@ -313,6 +1248,36 @@ func (mr *MockSLBMockRecorder) DeleteLoadBalancer(arg0, arg1, arg2 interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLoadBalancer", reflect.TypeOf((*MockSLB)(nil).DeleteLoadBalancer), arg0, arg1, arg2)
 }
 
+// GetAccessControlListEntries mocks base method
+func (m *MockSLB) GetAccessControlListEntries(arg0 context.Context, arg1, arg2 string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccessControlListEntries", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccessControlListEntries indicates an expected call of GetAccessControlListEntries
+func (mr *MockSLBMockRecorder) GetAccessControlListEntries(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccessControlListEntries", reflect.TypeOf((*MockSLB)(nil).GetAccessControlListEntries), arg0, arg1, arg2)
+}
+
+// GetAccessControlListID mocks base method
+func (m *MockSLB) GetAccessControlListID(arg0 context.Context, arg1, arg2 string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccessControlListID", arg0, arg1, arg2)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccessControlListID indicates an expected call of GetAccessControlListID
+func (mr *MockSLBMockRecorder) GetAccessControlListID(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccessControlListID", reflect.TypeOf((*MockSLB)(nil).GetAccessControlListID), arg0, arg1, arg2)
+}
+
 // GetFirstVServerGroupName mocks base method
 func (m *MockSLB) GetFirstVServerGroupName(arg0 context.Context, arg1, arg2 string) (string, error) {
 	m.ctrl.T.Helper()
@@ -328,6 +1293,21 @@ func (mr *MockSLBMockRecorder) GetFirstVServerGroupName(arg0, arg1, arg2 interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFirstVServerGroupName", reflect.TypeOf((*MockSLB)(nil).GetFirstVServerGroupName), arg0, arg1, arg2)
 }
 
+// GetLoadBalancerIDByAddress mocks base method
+func (m *MockSLB) GetLoadBalancerIDByAddress(arg0 context.Context, arg1, arg2 string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLoadBalancerIDByAddress", arg0, arg1, arg2)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLoadBalancerIDByAddress indicates an expected call of GetLoadBalancerIDByAddress
+func (mr *MockSLBMockRecorder) GetLoadBalancerIDByAddress(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLoadBalancerIDByAddress", reflect.TypeOf((*MockSLB)(nil).GetLoadBalancerIDByAddress), arg0, arg1, arg2)
+}
+
 // GetLoadBalancerIDs mocks base method
 func (m *MockSLB) GetLoadBalancerIDs(arg0 context.Context, arg1 string) ([]string, error) {
 	m.ctrl.T.Helper()
@@ -342,3 +1322,241 @@ func (mr *MockSLBMockRecorder) GetLoadBalancerIDs(arg0, arg1 interface{}) *gomoc
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLoadBalancerIDs", reflect.TypeOf((*MockSLB)(nil).GetLoadBalancerIDs), arg0, arg1)
 }
+
+// RemoveAccessControlListEntries mocks base method
+func (m *MockSLB) RemoveAccessControlListEntries(arg0 context.Context, arg1, arg2 string, arg3 []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveAccessControlListEntries", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveAccessControlListEntries indicates an expected call of RemoveAccessControlListEntries
+func (mr *MockSLBMockRecorder) RemoveAccessControlListEntries(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveAccessControlListEntries", reflect.TypeOf((*MockSLB)(nil).RemoveAccessControlListEntries), arg0, arg1, arg2, arg3)
+}
+
+// MockStorage is a mock of Storage interface
+type MockStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockStorageMockRecorder
+}
+
+// MockStorageMockRecorder is the mock recorder for MockStorage
+type MockStorageMockRecorder struct {
+	mock *MockStorage
+}
+
+// NewMockStorage creates a new mock instance
+func NewMockStorage(ctrl *gomock.Controller) *MockStorage {
+	mock := &MockStorage{ctrl: ctrl}
+	mock.recorder = &MockStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockStorage) EXPECT() *MockStorageMockRecorder {
+	return m.recorder
+}
+
+// CopyObjectsWithPrefix mocks base method
+func (m *MockStorage) CopyObjectsWithPrefix(arg0 context.Context, arg1, arg2, arg3, arg4 string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CopyObjectsWithPrefix", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CopyObjectsWithPrefix indicates an expected call of CopyObjectsWithPrefix
+func (mr *MockStorageMockRecorder) CopyObjectsWithPrefix(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CopyObjectsWithPrefix", reflect.TypeOf((*MockStorage)(nil).CopyObjectsWithPrefix), arg0, arg1, arg2, arg3, arg4)
+}
+
+// CreateBucketIfNotExists mocks base method
+func (m *MockStorage) CreateBucketIfNotExists(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBucketIfNotExists", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateBucketIfNotExists indicates an expected call of CreateBucketIfNotExists
+func (mr *MockStorageMockRecorder) CreateBucketIfNotExists(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBucketIfNotExists", reflect.TypeOf((*MockStorage)(nil).CreateBucketIfNotExists), arg0, arg1)
+}
+
+// DeleteBucketIfExists mocks base method
+func (m *MockStorage) DeleteBucketIfExists(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteBucketIfExists", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteBucketIfExists indicates an expected call of DeleteBucketIfExists
+func (mr *MockStorageMockRecorder) DeleteBucketIfExists(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBucketIfExists", reflect.TypeOf((*MockStorage)(nil).DeleteBucketIfExists), arg0, arg1)
+}
+
+// DeleteBucketLogging mocks base method
+func (m *MockStorage) DeleteBucketLogging(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteBucketLogging", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteBucketLogging indicates an expected call of DeleteBucketLogging
+func (mr *MockStorageMockRecorder) DeleteBucketLogging(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBucketLogging", reflect.TypeOf((*MockStorage)(nil).DeleteBucketLogging), arg0, arg1)
+}
+
+// DeleteObjectsWithPrefix mocks base method
+func (m *MockStorage) DeleteObjectsWithPrefix(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteObjectsWithPrefix", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteObjectsWithPrefix indicates an expected call of DeleteObjectsWithPrefix
+func (mr *MockStorageMockRecorder) DeleteObjectsWithPrefix(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteObjectsWithPrefix", reflect.TypeOf((*MockStorage)(nil).DeleteObjectsWithPrefix), arg0, arg1, arg2)
+}
+
+// DeleteObjectsWithPrefixOlderThan mocks base method
+func (m *MockStorage) DeleteObjectsWithPrefixOlderThan(arg0 context.Context, arg1, arg2 string, arg3 time.Time) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteObjectsWithPrefixOlderThan", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteObjectsWithPrefixOlderThan indicates an expected call of DeleteObjectsWithPrefixOlderThan
+func (mr *MockStorageMockRecorder) DeleteObjectsWithPrefixOlderThan(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteObjectsWithPrefixOlderThan", reflect.TypeOf((*MockStorage)(nil).DeleteObjectsWithPrefixOlderThan), arg0, arg1, arg2, arg3)
+}
+
+// EnableBucketVersioning mocks base method
+func (m *MockStorage) EnableBucketVersioning(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnableBucketVersioning", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EnableBucketVersioning indicates an expected call of EnableBucketVersioning
+func (mr *MockStorageMockRecorder) EnableBucketVersioning(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnableBucketVersioning", reflect.TypeOf((*MockStorage)(nil).EnableBucketVersioning), arg0, arg1)
+}
+
+// GetBucketACL mocks base method
+func (m *MockStorage) GetBucketACL(arg0 context.Context, arg1 string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBucketACL", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBucketACL indicates an expected call of GetBucketACL
+func (mr *MockStorageMockRecorder) GetBucketACL(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBucketACL", reflect.TypeOf((*MockStorage)(nil).GetBucketACL), arg0, arg1)
+}
+
+// GetBucketRegion mocks base method
+func (m *MockStorage) GetBucketRegion(arg0 context.Context, arg1 string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBucketRegion", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBucketRegion indicates an expected call of GetBucketRegion
+func (mr *MockStorageMockRecorder) GetBucketRegion(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBucketRegion", reflect.TypeOf((*MockStorage)(nil).GetBucketRegion), arg0, arg1)
+}
+
+// RestoreLatestArchivedSnapshots mocks base method
+func (m *MockStorage) RestoreLatestArchivedSnapshots(arg0 context.Context, arg1, arg2 string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreLatestArchivedSnapshots", arg0, arg1, arg2)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreLatestArchivedSnapshots indicates an expected call of RestoreLatestArchivedSnapshots
+func (mr *MockStorageMockRecorder) RestoreLatestArchivedSnapshots(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreLatestArchivedSnapshots", reflect.TypeOf((*MockStorage)(nil).RestoreLatestArchivedSnapshots), arg0, arg1, arg2)
+}
+
+// SetBucketACL mocks base method
+func (m *MockStorage) SetBucketACL(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetBucketACL", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetBucketACL indicates an expected call of SetBucketACL
+func (mr *MockStorageMockRecorder) SetBucketACL(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetBucketACL", reflect.TypeOf((*MockStorage)(nil).SetBucketACL), arg0, arg1, arg2)
+}
+
+// SetBucketKMSEncryption mocks base method
+func (m *MockStorage) SetBucketKMSEncryption(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetBucketKMSEncryption", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetBucketKMSEncryption indicates an expected call of SetBucketKMSEncryption
+func (mr *MockStorageMockRecorder) SetBucketKMSEncryption(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetBucketKMSEncryption", reflect.TypeOf((*MockStorage)(nil).SetBucketKMSEncryption), arg0, arg1, arg2)
+}
+
+// SetBucketLogging mocks base method
+func (m *MockStorage) SetBucketLogging(arg0 context.Context, arg1, arg2, arg3 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetBucketLogging", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetBucketLogging indicates an expected call of SetBucketLogging
+func (mr *MockStorageMockRecorder) SetBucketLogging(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetBucketLogging", reflect.TypeOf((*MockStorage)(nil).SetBucketLogging), arg0, arg1, arg2, arg3)
+}
+
+// SetBucketStorageClassTransition mocks base method
+func (m *MockStorage) SetBucketStorageClassTransition(arg0 context.Context, arg1, arg2 string, arg3 int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetBucketStorageClassTransition", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetBucketStorageClassTransition indicates an expected call of SetBucketStorageClassTransition
+func (mr *MockStorageMockRecorder) SetBucketStorageClassTransition(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetBucketStorageClassTransition", reflect.TypeOf((*MockStorage)(nil).SetBucketStorageClassTransition), arg0, arg1, arg2, arg3)
+}
@@ -18,15 +18,61 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/gardener/gardener-extension-provider-alicloud/pkg/alicloud"
+	"github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/alicloud/helper"
 	extensionswebhook "github.com/gardener/gardener-extensions/pkg/webhook"
 	"github.com/gardener/gardener-extensions/pkg/webhook/controlplane/genericmutator"
 
 	"github.com/Masterminds/semver"
 	"github.com/coreos/go-systemd/unit"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"github.com/gardener/gardener/pkg/utils"
 	"github.com/go-logr/logr"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	kubeletconfigv1beta1 "k8s.io/kubelet/config/v1beta1"
+	"k8s.io/utils/pointer"
+)
+
+const (
+	// metadataServiceUnitName is the name of the unit restricting the access to the ECS metadata service.
+	metadataServiceUnitName = "restrict-metadata-service-access.service"
+	// metadataServiceUnitContent drops all packets to the ECS metadata service which are routed through the node, i.e.
+	// which have been sent by pods that are not in the host network. Packets sent by the node itself do not traverse
+	// the PREROUTING chain, hence the node and the pods in the host network can still access the metadata service.
+	metadataServiceUnitContent = `[Unit]
+Description=Restrict the access to the ECS metadata service to the host network
+Wants=network-online.target
+After=network-online.target
+[Install]
+WantedBy=multi-user.target
+[Service]
+Type=oneshot
+RemainAfterExit=yes
+ExecStart=/bin/sh -c 'iptables -t mangle -C PREROUTING -d 100.100.100.200/32 -j DROP 2>/dev/null || iptables -t mangle -I PREROUTING -d 100.100.100.200/32 -j DROP'
+`
+
+	// ipvsModulesUnitName is the name of the unit loading the kernel modules required by the IPVS proxy mode.
+	ipvsModulesUnitName = "load-ipvs-kernel-modules.service"
+	// ipvsModulesUnitContent loads the kernel modules required by the IPVS proxy mode of kube-proxy before the kubelet
+	// starts, as the Alicloud images do not load them on boot.
+	ipvsModulesUnitContent = `[Unit]
+Description=Load the kernel modules required by the IPVS proxy mode of kube-proxy
+Before=kubelet.service
+[Install]
+WantedBy=multi-user.target
+[Service]
+Type=oneshot
+RemainAfterExit=yes
+ExecStart=/sbin/modprobe -a ip_vs ip_vs_rr ip_vs_wrr ip_vs_sh nf_conntrack
+`
+
+	// providerIDEnvironmentFile is the path of the environment file defining the PROVIDER_ID of the kubelet.
+	providerIDEnvironmentFile = "/var/lib/kubelet/provider-id"
+	// providerIDCommand determines the provider id of the node from the ECS metadata service before the kubelet starts.
+	// The cloud-controller-manager matches nodes and instances by their provider id `<region>.<instance-id>`, hence it
+	// must not depend on the machine image whether and how the variable is set. Dollar signs are escaped for systemd.
+	providerIDCommand = `/bin/sh -c 'REGION_ID=$$(curl -sSf http://100.100.100.200/latest/meta-data/region-id) && INSTANCE_ID=$$(curl -sSf http://100.100.100.200/latest/meta-data/instance-id) && echo "PROVIDER_ID=$${REGION_ID}.$${INSTANCE_ID}" > ` + providerIDEnvironmentFile + `'`
 )
 
 // NewEnsurer creates a new controlplane ensurer.
@@ -113,13 +159,40 @@ func (e *ensurer) EnsureKubeletServiceUnitOptions(ctx context.Context, ectx gene
 		command = ensureKubeletCommandLineArgs(command)
 		opt.Value = extensionswebhook.SerializeCommandLine(command, 1, " \\\n    ")
 	}
+	opts = extensionswebhook.EnsureUnitOption(opts, &unit.UnitOption{
+		Section: "Service",
+		Name:    "ExecStartPre",
+		Value:   providerIDCommand,
+	})
+	// The file is read before each command of the unit is executed, hence it is present for the kubelet once the
+	// command above has written it. It is optional as it does not exist yet when the command above is executed.
+	opts = extensionswebhook.EnsureUnitOption(opts, &unit.UnitOption{
+		Section: "Service",
+		Name:    "EnvironmentFile",
+		Value:   "-" + providerIDEnvironmentFile,
+	})
+	// The kubelet settings of the worker pool of the node are written to this file by its user data, which is not
+	// known to the webhook. It is optional as pools without such settings do not have it.
+	opts = extensionswebhook.EnsureUnitOption(opts, &unit.UnitOption{
+		Section: "Service",
+		Name:    "EnvironmentFile",
+		Value:   "-" + alicloud.KubeletExtraArgsFile,
+	})
 	return opts, nil
 }
 
+// ensureKubeletCommandLineArgs ensures that the kubelet uses the external cloud provider and the provider id written
+// to the provider id environment file. Custom machine images configuring the kubelet for the legacy in-tree cloud
+// provider or with a provider id of another format are overwritten accordingly. The additional arguments of the worker
+// pool of the node are passed last so that they take precedence. systemd splits the unbraced variable into separate
+// arguments and removes it if it is not set.
 func ensureKubeletCommandLineArgs(command []string) []string {
 	command = extensionswebhook.EnsureStringWithPrefix(command, "--provider-id=", "${PROVIDER_ID}")
 	command = extensionswebhook.EnsureStringWithPrefix(command, "--cloud-provider=", "external")
 	command = extensionswebhook.EnsureStringWithPrefix(command, "--enable-controller-attach-detach=", "true")
+	if extraArgs := "$" + alicloud.KubeletExtraArgsEnvVar; !utils.ValueExists(extraArgs, command) {
+		command = append(command, extraArgs)
+	}
 	return command
 }
 
@@ -152,3 +225,34 @@ func (e *ensurer) EnsureKubeletConfiguration(ctx context.Context, ectx genericmu
 
 	return nil
 }
+
+// EnsureAdditionalUnits ensures that additional required system units are added.
+func (e *ensurer) EnsureAdditionalUnits(ctx context.Context, ectx genericmutator.EnsurerContext, units *[]extensionsv1alpha1.Unit) error {
+	cluster, err := ectx.GetCluster(ctx)
+	if err != nil {
+		return err
+	}
+	cpConfig, err := helper.ControlPlaneConfigFromCluster(cluster)
+	if err != nil {
+		return err
+	}
+
+	if cpConfig.RestrictMetadataServiceAccess {
+		extensionswebhook.AppendUniqueUnit(units, extensionsv1alpha1.Unit{
+			Name:    metadataServiceUnitName,
+			Command: pointer.StringPtr("start"),
+			Enable:  pointer.BoolPtr(true),
+			Content: pointer.StringPtr(metadataServiceUnitContent),
+		})
+	}
+
+	if helper.UsesIPVSProxyMode(cluster.Shoot) {
+		extensionswebhook.AppendUniqueUnit(units, extensionsv1alpha1.Unit{
+			Name:    ipvsModulesUnitName,
+			Command: pointer.StringPtr("start"),
+			Enable:  pointer.BoolPtr(true),
+			Content: pointer.StringPtr(ipvsModulesUnitContent),
+		})
+	}
+	return nil
+}
@@ -16,6 +16,11 @@ package controlplane
 
 import (
 	"context"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	extensionscontroller "github.com/gardener/gardener-extensions/pkg/controller"
@@ -23,6 +28,7 @@ import (
 	"github.com/gardener/gardener-extensions/pkg/webhook/controlplane/genericmutator"
 	"github.com/gardener/gardener-extensions/pkg/webhook/controlplane/test"
 	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
 
 	"github.com/coreos/go-systemd/unit"
 	v1beta1constants "github.com/gardener/gardener/pkg/apis/core/v1beta1/constants"
@@ -32,7 +38,9 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	kubeletconfigv1beta1 "k8s.io/kubelet/config/v1beta1"
+	"k8s.io/utils/pointer"
 )
 
 func TestController(t *testing.T) {
@@ -233,7 +241,23 @@ var _ = Describe("Ensurer", func() {
     --config=/var/lib/kubelet/config/kubelet \
     --provider-id=${PROVIDER_ID} \
     --cloud-provider=external \
-    --enable-controller-attach-detach=true`,
+    --enable-controller-attach-detach=true \
+    $KUBELET_EXTRA_ARGS`,
+					},
+					{
+						Section: "Service",
+						Name:    "ExecStartPre",
+						Value:   providerIDCommand,
+					},
+					{
+						Section: "Service",
+						Name:    "EnvironmentFile",
+						Value:   "-/var/lib/kubelet/provider-id",
+					},
+					{
+						Section: "Service",
+						Name:    "EnvironmentFile",
+						Value:   "-/var/lib/kubelet/extra-args",
 					},
 				}
 			)
@@ -245,6 +269,60 @@ var _ = Describe("Ensurer", func() {
 			opts, err := ensurer.EnsureKubeletServiceUnitOptions(context.TODO(), eContext13, oldUnitOptions)
 			Expect(err).To(Not(HaveOccurred()))
 			Expect(opts).To(Equal(newUnitOptions))
+
+			// Call it again to check that the unit options are not duplicated
+			opts, err = ensurer.EnsureKubeletServiceUnitOptions(context.TODO(), eContext13, opts)
+			Expect(err).To(Not(HaveOccurred()))
+			Expect(opts).To(Equal(newUnitOptions))
+		})
+
+		It("should overwrite the legacy cloud provider and provider id of custom machine images", func() {
+			oldUnitOptions := []*unit.UnitOption{
+				{
+					Section: "Service",
+					Name:    "ExecStart",
+					Value: `/opt/bin/hyperkube kubelet \
+    --cloud-provider=alicloud \
+    --provider-id=i-bp1g5ahlkal88d7xxxxx`,
+				},
+			}
+
+			opts, err := NewEnsurer(logger).EnsureKubeletServiceUnitOptions(context.TODO(), eContext13, oldUnitOptions)
+			Expect(err).To(Not(HaveOccurred()))
+			Expect(opts[0].Value).To(Equal(`/opt/bin/hyperkube kubelet \
+    --cloud-provider=external \
+    --provider-id=${PROVIDER_ID} \
+    --enable-controller-attach-detach=true \
+    $KUBELET_EXTRA_ARGS`))
+		})
+
+		It("should write the provider id of the node in the format expected by the cloud-controller-manager", func() {
+			dir, err := ioutil.TempDir("", "provider-id")
+			Expect(err).NotTo(HaveOccurred())
+			defer os.RemoveAll(dir)
+
+			// Fake the ECS metadata service
+			Expect(ioutil.WriteFile(filepath.Join(dir, "curl"), []byte(`#!/bin/sh
+case "$2" in
+  */region-id) echo -n eu-central-1 ;;
+  */instance-id) echo -n i-gw8a1b2c3d4e5f6g7h8i ;;
+  *) exit 22 ;;
+esac
+`), 0755)).To(Succeed())
+
+			// Undo the escaping for systemd and redirect the environment file
+			environmentFile := filepath.Join(dir, "provider-id")
+			command := strings.Replace(providerIDCommand, "$$", "$", -1)
+			command = strings.Replace(command, providerIDEnvironmentFile, environmentFile, 1)
+			Expect(command).To(And(HavePrefix("/bin/sh -c '"), HaveSuffix("'")))
+
+			cmd := exec.Command("/bin/sh", "-c", strings.TrimSuffix(strings.TrimPrefix(command, "/bin/sh -c '"), "'"))
+			cmd.Env = []string{"PATH=" + dir + ":/usr/bin:/bin"}
+			Expect(cmd.Run()).To(Succeed())
+
+			content, err := ioutil.ReadFile(environmentFile)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(content)).To(Equal("PROVIDER_ID=eu-central-1.i-gw8a1b2c3d4e5f6g7h8i\n"))
 		})
 	})
 
@@ -290,6 +368,95 @@ var _ = Describe("Ensurer", func() {
 			Expect(oldKubeletConfig14).To(Equal(newKubeletConfig14))
 		})
 	})
+
+	Describe("#EnsureAdditionalUnits", func() {
+		var (
+			ensurer = NewEnsurer(logger)
+			unit    = extensionsv1alpha1.Unit{Name: "foo.service"}
+
+			eContext = func(controlPlaneConfig string) genericmutator.EnsurerContext {
+				return genericmutator.NewInternalEnsurerContext(
+					&extensionscontroller.Cluster{
+						Shoot: &gardencorev1beta1.Shoot{
+							Spec: gardencorev1beta1.ShootSpec{
+								Provider: gardencorev1beta1.Provider{
+									ControlPlaneConfig: &gardencorev1beta1.ProviderConfig{RawExtension: runtime.RawExtension{Raw: []byte(controlPlaneConfig)}},
+								},
+							},
+						},
+					},
+				)
+			}
+			eContextWithProxyMode = func(mode gardencorev1beta1.ProxyMode) genericmutator.EnsurerContext {
+				return genericmutator.NewInternalEnsurerContext(
+					&extensionscontroller.Cluster{
+						Shoot: &gardencorev1beta1.Shoot{
+							Spec: gardencorev1beta1.ShootSpec{
+								Kubernetes: gardencorev1beta1.Kubernetes{
+									KubeProxy: &gardencorev1beta1.KubeProxyConfig{Mode: &mode},
+								},
+							},
+						},
+					},
+				)
+			}
+		)
+
+		It("should add the unit restricting the metadata service access if enabled", func() {
+			units := []extensionsv1alpha1.Unit{unit}
+			ectx := eContext(`{"apiVersion":"alicloud.provider.extensions.gardener.cloud/v1alpha1","kind":"ControlPlaneConfig","zone":"foo","restrictMetadataServiceAccess":true}`)
+
+			Expect(ensurer.EnsureAdditionalUnits(context.TODO(), ectx, &units)).To(Succeed())
+			Expect(ensurer.EnsureAdditionalUnits(context.TODO(), ectx, &units)).To(Succeed())
+
+			Expect(units).To(ConsistOf(
+				unit,
+				extensionsv1alpha1.Unit{
+					Name:    metadataServiceUnitName,
+					Command: pointer.StringPtr("start"),
+					Enable:  pointer.BoolPtr(true),
+					Content: pointer.StringPtr(metadataServiceUnitContent),
+				},
+			))
+			Expect(*units[1].Content).To(ContainSubstring("iptables -t mangle -I PREROUTING -d 100.100.100.200/32 -j DROP"))
+		})
+
+		It("should not add the unit restricting the metadata service access if disabled", func() {
+			units := []extensionsv1alpha1.Unit{unit}
+			ectx := eContext(`{"apiVersion":"alicloud.provider.extensions.gardener.cloud/v1alpha1","kind":"ControlPlaneConfig","zone":"foo"}`)
+
+			Expect(ensurer.EnsureAdditionalUnits(context.TODO(), ectx, &units)).To(Succeed())
+
+			Expect(units).To(ConsistOf(unit))
+		})
+
+		It("should add the unit loading the IPVS kernel modules in the IPVS proxy mode", func() {
+			units := []extensionsv1alpha1.Unit{unit}
+			ectx := eContextWithProxyMode(gardencorev1beta1.ProxyModeIPVS)
+
+			Expect(ensurer.EnsureAdditionalUnits(context.TODO(), ectx, &units)).To(Succeed())
+			Expect(ensurer.EnsureAdditionalUnits(context.TODO(), ectx, &units)).To(Succeed())
+
+			Expect(units).To(ConsistOf(
+				unit,
+				extensionsv1alpha1.Unit{
+					Name:    ipvsModulesUnitName,
+					Command: pointer.StringPtr("start"),
+					Enable:  pointer.BoolPtr(true),
+					Content: pointer.StringPtr(ipvsModulesUnitContent),
+				},
+			))
+			Expect(*units[1].Content).To(ContainSubstring("modprobe -a ip_vs ip_vs_rr ip_vs_wrr ip_vs_sh nf_conntrack"))
+		})
+
+		It("should not add the unit loading the IPVS kernel modules in the iptables proxy mode", func() {
+			units := []extensionsv1alpha1.Unit{unit}
+
+			Expect(ensurer.EnsureAdditionalUnits(context.TODO(), eContextWithProxyMode(gardencorev1beta1.ProxyModeIPTables), &units)).To(Succeed())
+
+			Expect(units).To(ConsistOf(unit))
+		})
+	})
 })
 
 func checkKubeAPIServerDeployment(dep *appsv1.Deployment, featureGates []string) {
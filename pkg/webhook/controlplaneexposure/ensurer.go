@@ -16,7 +16,10 @@ package controlplaneexposure
 
 import (
 	"context"
+	"strconv"
 
+	"github.com/gardener/gardener-extension-provider-alicloud/pkg/alicloud"
+	"github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/alicloud/helper"
 	"github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/config"
 	"github.com/gardener/gardener-extensions/pkg/controller"
 	"github.com/gardener/gardener-extensions/pkg/util"
@@ -55,6 +58,46 @@ func (e *ensurer) InjectClient(client client.Client) error {
 	return nil
 }
 
+// EnsureKubeAPIServerService ensures that the kube-apiserver service conforms to the provider requirements.
+func (e *ensurer) EnsureKubeAPIServerService(ctx context.Context, ectx genericmutator.EnsurerContext, svc *corev1.Service) error {
+	cluster, err := ectx.GetCluster(ctx)
+	if err != nil {
+		return err
+	}
+	cpConfig, err := helper.ControlPlaneConfigFromCluster(cluster)
+	if err != nil {
+		return err
+	}
+
+	// The annotations are only set if the respective settings are configured so that the load balancers of existing
+	// shoots are not touched.
+	lb := cpConfig.APIServerLoadBalancer
+	if lb == nil {
+		return nil
+	}
+	if lb.AddressType != nil {
+		setAnnotation(svc, alicloud.AnnotationLoadBalancerAddressType, string(*lb.AddressType))
+	}
+	if lb.IdleTimeout != nil {
+		setAnnotation(svc, alicloud.AnnotationLoadBalancerEstablishedTimeout, strconv.Itoa(int(*lb.IdleTimeout)))
+	}
+	if lb.ConnectionDrainTimeout != nil {
+		setAnnotation(svc, alicloud.AnnotationLoadBalancerConnectionDrain, "on")
+		setAnnotation(svc, alicloud.AnnotationLoadBalancerConnectionDrainTimeout, strconv.Itoa(int(*lb.ConnectionDrainTimeout)))
+	}
+	if lb.HealthCheckInterval != nil {
+		setAnnotation(svc, alicloud.AnnotationLoadBalancerHealthCheckInterval, strconv.Itoa(int(*lb.HealthCheckInterval)))
+	}
+	return nil
+}
+
+func setAnnotation(svc *corev1.Service, key, value string) {
+	if svc.Annotations == nil {
+		svc.Annotations = map[string]string{}
+	}
+	svc.Annotations[key] = value
+}
+
 // EnsureKubeAPIServerDeployment ensures that the kube-apiserver deployment conforms to the provider requirements.
 func (e *ensurer) EnsureKubeAPIServerDeployment(ctx context.Context, ectx genericmutator.EnsurerContext, dep *appsv1.Deployment) error {
 	cluster, err := controller.GetCluster(ctx, e.client, dep.Namespace)
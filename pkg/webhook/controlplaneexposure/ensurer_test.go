@@ -21,7 +21,10 @@ import (
 
 	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
 
+	"github.com/gardener/gardener-extension-provider-alicloud/pkg/alicloud"
+	apiv1alpha1 "github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/alicloud/v1alpha1"
 	"github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/config"
+	extensionscontroller "github.com/gardener/gardener-extensions/pkg/controller"
 	mockclient "github.com/gardener/gardener-extensions/pkg/mock/controller-runtime/client"
 	"github.com/gardener/gardener-extensions/pkg/util"
 	extensionswebhook "github.com/gardener/gardener-extensions/pkg/webhook"
@@ -38,6 +41,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/runtime/inject"
 )
@@ -90,6 +94,70 @@ var _ = Describe("Ensurer", func() {
 		ctrl.Finish()
 	})
 
+	Describe("#EnsureKubeAPIServerService", func() {
+		var (
+			svc *corev1.Service
+
+			ensurerContext = func(controlPlaneConfig *apiv1alpha1.ControlPlaneConfig) genericmutator.EnsurerContext {
+				shoot := &gardencorev1beta1.Shoot{}
+				if controlPlaneConfig != nil {
+					shoot.Spec.Provider.ControlPlaneConfig = &gardencorev1beta1.ProviderConfig{RawExtension: runtime.RawExtension{Raw: encode(controlPlaneConfig)}}
+				}
+				return genericmutator.NewInternalEnsurerContext(&extensionscontroller.Cluster{Shoot: shoot})
+			}
+		)
+
+		BeforeEach(func() {
+			svc = &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{Name: v1beta1constants.DeploymentNameKubeAPIServer, Namespace: namespace},
+			}
+		})
+
+		It("should annotate the kube-apiserver service with the configured address type", func() {
+			addressType := apiv1alpha1.LoadBalancerAddressTypeIntranet
+			ectx := ensurerContext(&apiv1alpha1.ControlPlaneConfig{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: apiv1alpha1.SchemeGroupVersion.String(),
+					Kind:       "ControlPlaneConfig",
+				},
+				APIServerLoadBalancer: &apiv1alpha1.APIServerLoadBalancer{AddressType: &addressType},
+			})
+
+			ensurer := NewEnsurer(etcdStorage, logger)
+			Expect(ensurer.EnsureKubeAPIServerService(context.TODO(), ectx, svc)).To(Succeed())
+			Expect(svc.Annotations).To(HaveKeyWithValue(alicloud.AnnotationLoadBalancerAddressType, "intranet"))
+		})
+
+		It("should annotate the kube-apiserver service with the configured listener settings", func() {
+			ectx := ensurerContext(&apiv1alpha1.ControlPlaneConfig{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: apiv1alpha1.SchemeGroupVersion.String(),
+					Kind:       "ControlPlaneConfig",
+				},
+				APIServerLoadBalancer: &apiv1alpha1.APIServerLoadBalancer{
+					IdleTimeout:            pointer.Int32Ptr(900),
+					ConnectionDrainTimeout: pointer.Int32Ptr(300),
+					HealthCheckInterval:    pointer.Int32Ptr(5),
+				},
+			})
+
+			ensurer := NewEnsurer(etcdStorage, logger)
+			Expect(ensurer.EnsureKubeAPIServerService(context.TODO(), ectx, svc)).To(Succeed())
+			Expect(svc.Annotations).To(Equal(map[string]string{
+				alicloud.AnnotationLoadBalancerEstablishedTimeout:     "900",
+				alicloud.AnnotationLoadBalancerConnectionDrain:        "on",
+				alicloud.AnnotationLoadBalancerConnectionDrainTimeout: "300",
+				alicloud.AnnotationLoadBalancerHealthCheckInterval:    "5",
+			}))
+		})
+
+		It("should not annotate the kube-apiserver service if no address type is configured", func() {
+			ensurer := NewEnsurer(etcdStorage, logger)
+			Expect(ensurer.EnsureKubeAPIServerService(context.TODO(), ensurerContext(nil), svc)).To(Succeed())
+			Expect(svc.Annotations).To(BeEmpty())
+		})
+	})
+
 	Describe("#EnsureKubeAPIServerDeployment", func() {
 		It("should add missing elements to kube-apiserver deployment", func() {
 			var (
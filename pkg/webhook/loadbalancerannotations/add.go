@@ -0,0 +1,64 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadbalancerannotations
+
+import (
+	extensionswebhook "github.com/gardener/gardener-extensions/pkg/webhook"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// WebhookName is the name of the load balancer annotations webhook.
+const WebhookName = "loadbalancer-annotations"
+
+var (
+	// DefaultAddOptions are the default AddOptions for AddToManager.
+	DefaultAddOptions = AddOptions{}
+)
+
+// AddOptions are options to apply when adding the Alicloud load balancer annotations webhook to the manager.
+type AddOptions struct{}
+
+var logger = log.Log.WithName("alicloud-loadbalancer-annotations-webhook")
+
+// AddToManagerWithOptions creates a webhook with the given options and adds it to the manager.
+// Like the load balancer defaults webhook, it is not restricted to the kube-system namespace as it applies to the
+// services of the shoot owner.
+func AddToManagerWithOptions(mgr manager.Manager, opts AddOptions) (*extensionswebhook.Webhook, error) {
+	logger.Info("Adding webhook to manager")
+
+	types := []runtime.Object{&corev1.Service{}}
+	handler, err := extensionswebhook.NewHandler(mgr, types, NewValidator(), logger)
+	if err != nil {
+		return nil, err
+	}
+
+	return &extensionswebhook.Webhook{
+		Name:    WebhookName,
+		Types:   types,
+		Path:    WebhookName,
+		Target:  extensionswebhook.TargetShoot,
+		Webhook: &admission.Webhook{Handler: handler},
+	}, nil
+}
+
+// AddToManager creates a webhook with the default options and adds it to the manager.
+func AddToManager(mgr manager.Manager) (*extensionswebhook.Webhook, error) {
+	return AddToManagerWithOptions(mgr, DefaultAddOptions)
+}
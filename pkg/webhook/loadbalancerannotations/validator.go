@@ -0,0 +1,171 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadbalancerannotations
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	extensionswebhook "github.com/gardener/gardener-extensions/pkg/webhook"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// annotationPrefixes are the prefixes of the service annotations which are understood by the Alicloud
+// cloud-controller-manager.
+var annotationPrefixes = []string{
+	"service.beta.kubernetes.io/alibaba-cloud-loadbalancer-",
+	"service.beta.kubernetes.io/alicloud-loadbalancer-",
+}
+
+const (
+	addressType            = "address-type"
+	vswitchID              = "vswitch-id"
+	loadBalancerID         = "id"
+	forceOverrideListeners = "force-override-listeners"
+	chargeType             = "charge-type"
+	bandwidth              = "bandwidth"
+	connectionDrain        = "connection-drain"
+	connectionDrainTimeout = "connection-drain-timeout"
+	healthCheckType        = "health-check-type"
+	healthCheckURI         = "health-check-uri"
+)
+
+var (
+	addressTypes       = sets.NewString("internet", "intranet")
+	chargeTypes        = sets.NewString("paybytraffic", "paybybandwidth")
+	switches           = sets.NewString("on", "off")
+	healthCheckTypes   = sets.NewString("tcp", "http")
+	numericAnnotations = []string{bandwidth, connectionDrainTimeout}
+)
+
+type validator struct {
+	logger logr.Logger
+}
+
+// NewValidator creates a new Mutator that rejects services of type LoadBalancer whose Alicloud load balancer
+// annotations are invalid or contradict each other, as the cloud-controller-manager silently ignores such settings.
+// It never changes the services.
+func NewValidator() extensionswebhook.Mutator {
+	return &validator{
+		logger: log.Log.WithName("loadbalancer-annotations-validator"),
+	}
+}
+
+// Mutate validates the annotations of services of type LoadBalancer.
+func (v *validator) Mutate(ctx context.Context, obj runtime.Object) error {
+	svc, ok := obj.(*corev1.Service)
+	if !ok || svc.Spec.Type != corev1.ServiceTypeLoadBalancer {
+		return nil
+	}
+	// If the object does have a deletion timestamp then we don't want to validate anything.
+	if svc.DeletionTimestamp != nil {
+		return nil
+	}
+
+	if allErrs := ValidateAnnotations(svc.Annotations, field.NewPath("metadata", "annotations")); len(allErrs) > 0 {
+		return allErrs.ToAggregate()
+	}
+	return nil
+}
+
+// ValidateAnnotations validates the Alicloud load balancer annotations of a service of type LoadBalancer.
+func ValidateAnnotations(annotations map[string]string, fldPath *field.Path) field.ErrorList {
+	var (
+		allErrs = field.ErrorList{}
+		a       = loadBalancerAnnotations{annotations: annotations, fldPath: fldPath}
+	)
+
+	allErrs = append(allErrs, a.validateValue(addressType, addressTypes)...)
+	allErrs = append(allErrs, a.validateValue(chargeType, chargeTypes)...)
+	allErrs = append(allErrs, a.validateValue(connectionDrain, switches)...)
+	allErrs = append(allErrs, a.validateValue(healthCheckType, healthCheckTypes)...)
+	for _, name := range numericAnnotations {
+		if key, value, ok := a.get(name); ok {
+			if n, err := strconv.Atoi(value); err != nil || n < 1 {
+				allErrs = append(allErrs, field.Invalid(fldPath.Key(key), value, "must be a positive number"))
+			}
+		}
+	}
+
+	if key, _, ok := a.get(vswitchID); ok {
+		if _, value, _ := a.get(addressType); value != "intranet" {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Key(key), fmt.Sprintf("a vswitch can only be selected for intranet load balancers, please set the %s annotation to intranet", a.key(addressType))))
+		}
+	}
+
+	if key, _, ok := a.get(forceOverrideListeners); ok {
+		if _, _, ok := a.get(loadBalancerID); !ok {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Key(key), fmt.Sprintf("listeners can only be overridden for existing load balancers, please reference one with the %s annotation or remove this annotation", a.key(loadBalancerID))))
+		}
+	}
+
+	if key, _, ok := a.get(connectionDrainTimeout); ok {
+		if _, value, _ := a.get(connectionDrain); value != "on" {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Key(key), fmt.Sprintf("the timeout only takes effect if connection draining is enabled, please set the %s annotation to on", a.key(connectionDrain))))
+		}
+	}
+
+	if key, _, ok := a.get(healthCheckURI); ok {
+		if _, value, _ := a.get(healthCheckType); value != "http" {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Key(key), fmt.Sprintf("a health check uri can only be used for http health checks, please set the %s annotation to http", a.key(healthCheckType))))
+		}
+	}
+
+	if key, value, ok := a.get(healthCheckType); ok && value == "http" {
+		if _, _, ok := a.get(healthCheckURI); !ok {
+			allErrs = append(allErrs, field.Required(fldPath.Key(key), fmt.Sprintf("http health checks require a uri, please set the %s annotation", a.key(healthCheckURI))))
+		}
+	}
+
+	return allErrs
+}
+
+// loadBalancerAnnotations gives access to the Alicloud load balancer annotations of a service regardless of the
+// prefix they are specified with.
+type loadBalancerAnnotations struct {
+	annotations map[string]string
+	fldPath     *field.Path
+}
+
+// get returns the key and the value of the annotation with the given name and whether it is set.
+func (a loadBalancerAnnotations) get(name string) (string, string, bool) {
+	for _, prefix := range annotationPrefixes {
+		if value, ok := a.annotations[prefix+name]; ok {
+			return prefix + name, strings.TrimSpace(value), true
+		}
+	}
+	return "", "", false
+}
+
+// key returns the key of the annotation with the given name as it is used in the messages.
+func (a loadBalancerAnnotations) key(name string) string {
+	return annotationPrefixes[0] + name
+}
+
+func (a loadBalancerAnnotations) validateValue(name string, values sets.String) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if key, value, ok := a.get(name); ok && !values.Has(value) {
+		allErrs = append(allErrs, field.NotSupported(a.fldPath.Key(key), value, values.List()))
+	}
+	return allErrs
+}
@@ -0,0 +1,159 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadbalancerannotations_test
+
+import (
+	"context"
+
+	. "github.com/gardener/gardener-extension-provider-alicloud/pkg/webhook/loadbalancerannotations"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+	"github.com/onsi/gomega/types"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+const (
+	prefix       = "service.beta.kubernetes.io/alibaba-cloud-loadbalancer-"
+	legacyPrefix = "service.beta.kubernetes.io/alicloud-loadbalancer-"
+)
+
+var _ = Describe("Validator", func() {
+	var (
+		ctx       = context.TODO()
+		validator = NewValidator()
+		fldPath   = field.NewPath("metadata", "annotations")
+
+		svc *corev1.Service
+	)
+
+	BeforeEach(func() {
+		svc = &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "foo",
+				Namespace:   "bar",
+				Annotations: map[string]string{prefix + "address-type": "internet", prefix + "vswitch-id": "vsw-1234"},
+			},
+			Spec: corev1.ServiceSpec{Type: corev1.ServiceTypeLoadBalancer},
+		}
+	})
+
+	Describe("#Mutate", func() {
+		It("should reject services of type LoadBalancer with invalid annotations", func() {
+			Expect(validator.Mutate(ctx, svc)).To(MatchError(ContainSubstring("a vswitch can only be selected for intranet load balancers")))
+		})
+
+		It("should not change services of type LoadBalancer with valid annotations", func() {
+			svc.Annotations[prefix+"address-type"] = "intranet"
+			expected := svc.DeepCopy()
+
+			Expect(validator.Mutate(ctx, svc)).To(Succeed())
+			Expect(svc).To(Equal(expected))
+		})
+
+		It("should ignore services of other types", func() {
+			svc.Spec.Type = corev1.ServiceTypeClusterIP
+
+			Expect(validator.Mutate(ctx, svc)).To(Succeed())
+		})
+
+		It("should ignore services which are being deleted", func() {
+			now := metav1.Now()
+			svc.DeletionTimestamp = &now
+
+			Expect(validator.Mutate(ctx, svc)).To(Succeed())
+		})
+	})
+
+	DescribeTable("#ValidateAnnotations",
+		func(annotations map[string]string, matcher types.GomegaMatcher) {
+			Expect(ValidateAnnotations(annotations, fldPath)).To(matcher)
+		},
+
+		Entry("no annotations", nil, BeEmpty()),
+		Entry("unrelated annotations", map[string]string{"foo": "bar"}, BeEmpty()),
+		Entry("intranet load balancer in a vswitch", map[string]string{
+			prefix + "address-type": "intranet",
+			prefix + "vswitch-id":   "vsw-1234",
+		}, BeEmpty()),
+		Entry("intranet load balancer in a vswitch with the legacy prefix", map[string]string{
+			legacyPrefix + "address-type": "intranet",
+			prefix + "vswitch-id":         "vsw-1234",
+		}, BeEmpty()),
+		Entry("existing load balancer with overridden listeners", map[string]string{
+			prefix + "id":                       "lb-1234",
+			prefix + "force-override-listeners": "true",
+		}, BeEmpty()),
+		Entry("connection draining with timeout and http health check", map[string]string{
+			prefix + "connection-drain":         "on",
+			prefix + "connection-drain-timeout": "30",
+			prefix + "health-check-type":        "http",
+			prefix + "health-check-uri":         "/healthz",
+			prefix + "charge-type":              "paybybandwidth",
+			prefix + "bandwidth":                "100",
+		}, BeEmpty()),
+
+		Entry("unsupported values", map[string]string{
+			prefix + "address-type":      "private",
+			legacyPrefix + "charge-type": "prepaid",
+			prefix + "connection-drain":  "true",
+			prefix + "health-check-type": "udp",
+		}, ConsistOf(
+			notSupportedError(prefix+"address-type"),
+			notSupportedError(legacyPrefix+"charge-type"),
+			notSupportedError(prefix+"connection-drain"),
+			notSupportedError(prefix+"health-check-type"),
+		)),
+		Entry("invalid numbers", map[string]string{
+			prefix + "bandwidth":                "unlimited",
+			prefix + "connection-drain":         "on",
+			prefix + "connection-drain-timeout": "0",
+		}, ConsistOf(
+			errorOfType(field.ErrorTypeInvalid, prefix+"bandwidth"),
+			errorOfType(field.ErrorTypeInvalid, prefix+"connection-drain-timeout"),
+		)),
+		Entry("vswitch for an internet load balancer", map[string]string{
+			prefix + "vswitch-id": "vsw-1234",
+		}, ConsistOf(errorOfType(field.ErrorTypeForbidden, prefix+"vswitch-id"))),
+		Entry("overridden listeners without existing load balancer", map[string]string{
+			legacyPrefix + "force-override-listeners": "true",
+		}, ConsistOf(errorOfType(field.ErrorTypeForbidden, legacyPrefix+"force-override-listeners"))),
+		Entry("connection draining timeout without connection draining", map[string]string{
+			prefix + "connection-drain":         "off",
+			prefix + "connection-drain-timeout": "30",
+		}, ConsistOf(errorOfType(field.ErrorTypeForbidden, prefix+"connection-drain-timeout"))),
+		Entry("health check uri for tcp health checks", map[string]string{
+			prefix + "health-check-uri": "/healthz",
+		}, ConsistOf(errorOfType(field.ErrorTypeForbidden, prefix+"health-check-uri"))),
+		Entry("http health check without uri", map[string]string{
+			prefix + "health-check-type": "http",
+		}, ConsistOf(errorOfType(field.ErrorTypeRequired, prefix+"health-check-type"))),
+	)
+})
+
+func notSupportedError(key string) types.GomegaMatcher {
+	return errorOfType(field.ErrorTypeNotSupported, key)
+}
+
+func errorOfType(errorType field.ErrorType, key string) types.GomegaMatcher {
+	return PointTo(MatchFields(IgnoreExtras, Fields{
+		"Type":  Equal(errorType),
+		"Field": Equal(field.NewPath("metadata", "annotations").Key(key).String()),
+	}))
+}
@@ -0,0 +1,91 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadbalancerdefaults
+
+import (
+	"context"
+
+	"github.com/gardener/gardener-extension-provider-alicloud/pkg/alicloud"
+	extensionswebhook "github.com/gardener/gardener-extensions/pkg/webhook"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// annotations maps the keys of the load balancer defaults to the service annotations they are applied as.
+var annotations = map[string]string{
+	alicloud.LoadBalancerDefaultSpec:       alicloud.AnnotationLoadBalancerSpec,
+	alicloud.LoadBalancerDefaultChargeType: alicloud.AnnotationLoadBalancerChargeType,
+	alicloud.LoadBalancerDefaultBandwidth:  alicloud.AnnotationLoadBalancerBandwidth,
+}
+
+type mutator struct {
+	logger logr.Logger
+}
+
+// NewMutator creates a new MutatorWithShootClient that applies the load balancer defaults of the shoot to its
+// services of type LoadBalancer.
+func NewMutator() extensionswebhook.MutatorWithShootClient {
+	return &mutator{
+		logger: log.Log.WithName("loadbalancer-defaults-mutator"),
+	}
+}
+
+// Mutate mutates resources.
+func (m *mutator) Mutate(ctx context.Context, obj runtime.Object, shootClient client.Client) error {
+	svc, ok := obj.(*corev1.Service)
+	if !ok || svc.Spec.Type != corev1.ServiceTypeLoadBalancer {
+		return nil
+	}
+	// If the object does have a deletion timestamp then we don't want to mutate anything.
+	if svc.DeletionTimestamp != nil {
+		return nil
+	}
+
+	defaults := &corev1.ConfigMap{}
+	if err := shootClient.Get(ctx, client.ObjectKey{Namespace: metav1.NamespaceSystem, Name: alicloud.LoadBalancerDefaultsName}, defaults); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return errors.Wrapf(err, "could not get load balancer defaults")
+	}
+
+	extensionswebhook.LogMutation(m.logger, svc.Kind, svc.Namespace, svc.Name)
+	applyDefaults(svc, defaults.Data)
+	return nil
+}
+
+// applyDefaults adds the annotations for the given load balancer defaults to the service unless it already has them.
+func applyDefaults(svc *corev1.Service, defaults map[string]string) {
+	for key, annotation := range annotations {
+		value, ok := defaults[key]
+		if !ok {
+			continue
+		}
+		if _, ok := svc.Annotations[annotation]; ok {
+			continue
+		}
+		if svc.Annotations == nil {
+			svc.Annotations = map[string]string{}
+		}
+		svc.Annotations[annotation] = value
+	}
+}
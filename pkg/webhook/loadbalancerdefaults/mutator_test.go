@@ -0,0 +1,135 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadbalancerdefaults_test
+
+import (
+	"context"
+
+	"github.com/gardener/gardener-extension-provider-alicloud/pkg/alicloud"
+	. "github.com/gardener/gardener-extension-provider-alicloud/pkg/webhook/loadbalancerdefaults"
+	mockclient "github.com/gardener/gardener-extensions/pkg/mock/controller-runtime/client"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("Mutator", func() {
+	var (
+		ctrl        *gomock.Controller
+		shootClient *mockclient.MockClient
+		mutator     = NewMutator()
+
+		ctx         = context.TODO()
+		defaultsKey = client.ObjectKey{Namespace: metav1.NamespaceSystem, Name: alicloud.LoadBalancerDefaultsName}
+		defaults    = &corev1.ConfigMap{
+			Data: map[string]string{
+				alicloud.LoadBalancerDefaultSpec:       "slb.s1.small",
+				alicloud.LoadBalancerDefaultChargeType: "paybybandwidth",
+				alicloud.LoadBalancerDefaultBandwidth:  "100",
+			},
+		}
+
+		svc *corev1.Service
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		shootClient = mockclient.NewMockClient(ctrl)
+
+		svc = &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "bar"},
+			Spec:       corev1.ServiceSpec{Type: corev1.ServiceTypeLoadBalancer},
+		}
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	expectGetDefaults := func() {
+		shootClient.EXPECT().Get(ctx, defaultsKey, &corev1.ConfigMap{}).DoAndReturn(func(_ context.Context, _ client.ObjectKey, obj runtime.Object) error {
+			*obj.(*corev1.ConfigMap) = *defaults
+			return nil
+		})
+	}
+
+	Describe("#Mutate", func() {
+		It("should add the default annotations to a service without annotations", func() {
+			expectGetDefaults()
+
+			Expect(mutator.Mutate(ctx, svc, shootClient)).To(Succeed())
+			Expect(svc.Annotations).To(Equal(map[string]string{
+				alicloud.AnnotationLoadBalancerSpec:       "slb.s1.small",
+				alicloud.AnnotationLoadBalancerChargeType: "paybybandwidth",
+				alicloud.AnnotationLoadBalancerBandwidth:  "100",
+			}))
+		})
+
+		It("should not overwrite annotations set by the user", func() {
+			expectGetDefaults()
+			svc.Annotations = map[string]string{
+				alicloud.AnnotationLoadBalancerSpec:      "slb.s2.medium",
+				alicloud.AnnotationLoadBalancerBandwidth: "10",
+				"foo":                                    "bar",
+			}
+
+			Expect(mutator.Mutate(ctx, svc, shootClient)).To(Succeed())
+			Expect(svc.Annotations).To(Equal(map[string]string{
+				alicloud.AnnotationLoadBalancerSpec:       "slb.s2.medium",
+				alicloud.AnnotationLoadBalancerChargeType: "paybybandwidth",
+				alicloud.AnnotationLoadBalancerBandwidth:  "10",
+				"foo":                                     "bar",
+			}))
+		})
+
+		It("should be idempotent", func() {
+			expectGetDefaults()
+			expectGetDefaults()
+
+			Expect(mutator.Mutate(ctx, svc, shootClient)).To(Succeed())
+			mutated := svc.DeepCopy()
+			Expect(mutator.Mutate(ctx, svc, shootClient)).To(Succeed())
+			Expect(svc).To(Equal(mutated))
+		})
+
+		It("should not mutate the service if there are no defaults", func() {
+			shootClient.EXPECT().Get(ctx, defaultsKey, &corev1.ConfigMap{}).Return(apierrors.NewNotFound(schema.GroupResource{Resource: "configmaps"}, alicloud.LoadBalancerDefaultsName))
+
+			Expect(mutator.Mutate(ctx, svc, shootClient)).To(Succeed())
+			Expect(svc.Annotations).To(BeNil())
+		})
+
+		It("should not mutate services of other types", func() {
+			svc.Spec.Type = corev1.ServiceTypeClusterIP
+
+			Expect(mutator.Mutate(ctx, svc, shootClient)).To(Succeed())
+			Expect(svc.Annotations).To(BeNil())
+		})
+
+		It("should not mutate other resources", func() {
+			cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "bar"}}
+
+			Expect(mutator.Mutate(ctx, cm, shootClient)).To(Succeed())
+			Expect(cm.Annotations).To(BeNil())
+		})
+	})
+})
@@ -0,0 +1,72 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workervolume
+
+import (
+	"github.com/gardener/gardener-extension-provider-alicloud/pkg/alicloud"
+	extensionswebhook "github.com/gardener/gardener-extensions/pkg/webhook"
+
+	v1beta1constants "github.com/gardener/gardener/pkg/apis/core/v1beta1/constants"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// WebhookName is the name of the worker volume webhook.
+const WebhookName = "worker-volume"
+
+var (
+	// DefaultAddOptions are the default AddOptions for AddToManager.
+	DefaultAddOptions = AddOptions{}
+)
+
+// AddOptions are options to apply when adding the Alicloud worker volume webhook to the manager.
+type AddOptions struct{}
+
+var logger = log.Log.WithName("alicloud-worker-volume-webhook")
+
+// AddToManagerWithOptions creates a webhook with the given options and adds it to the manager.
+// It is restricted to the namespaces of shoots of the Alicloud provider in the seed.
+func AddToManagerWithOptions(mgr manager.Manager, opts AddOptions) (*extensionswebhook.Webhook, error) {
+	logger.Info("Adding webhook to manager")
+
+	types := []runtime.Object{&extensionsv1alpha1.Worker{}}
+	handler, err := extensionswebhook.NewHandler(mgr, types, NewMutator(), logger)
+	if err != nil {
+		return nil, err
+	}
+
+	return &extensionswebhook.Webhook{
+		Name:     WebhookName,
+		Provider: alicloud.Type,
+		Types:    types,
+		Path:     WebhookName,
+		Target:   extensionswebhook.TargetSeed,
+		Webhook:  &admission.Webhook{Handler: handler},
+		Selector: &metav1.LabelSelector{
+			MatchExpressions: []metav1.LabelSelectorRequirement{
+				{Key: v1beta1constants.LabelShootProvider, Operator: metav1.LabelSelectorOpIn, Values: []string{alicloud.Type}},
+			},
+		},
+	}, nil
+}
+
+// AddToManager creates a webhook with the default options and adds it to the manager.
+func AddToManager(mgr manager.Manager) (*extensionswebhook.Webhook, error) {
+	return AddToManagerWithOptions(mgr, DefaultAddOptions)
+}
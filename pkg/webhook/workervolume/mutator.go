@@ -0,0 +1,120 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workervolume
+
+import (
+	"context"
+
+	"github.com/gardener/gardener-extension-provider-alicloud/pkg/alicloud"
+	apisalicloud "github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/alicloud"
+	"github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/alicloud/helper"
+	"github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/alicloud/validation"
+	extensionscontroller "github.com/gardener/gardener-extensions/pkg/controller"
+	extensionswebhook "github.com/gardener/gardener-extensions/pkg/webhook"
+
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+type mutator struct {
+	client client.Client
+	logger logr.Logger
+}
+
+// NewMutator creates a new Mutator that defaults the system disk sizes of the worker pools of Alicloud workers to the
+// minimum size their machine images require and rejects sizes which Alicloud does not support.
+func NewMutator() extensionswebhook.Mutator {
+	return &mutator{
+		logger: log.Log.WithName("worker-volume-mutator"),
+	}
+}
+
+// InjectClient injects the given client into the mutator.
+func (m *mutator) InjectClient(client client.Client) error {
+	m.client = client
+	return nil
+}
+
+// Mutate mutates resources.
+func (m *mutator) Mutate(ctx context.Context, obj runtime.Object) error {
+	worker, ok := obj.(*extensionsv1alpha1.Worker)
+	if !ok || worker.Spec.Type != alicloud.Type {
+		return nil
+	}
+	// If the object does have a deletion timestamp then we don't want to mutate anything.
+	if worker.DeletionTimestamp != nil {
+		return nil
+	}
+
+	cluster, err := extensionscontroller.GetCluster(ctx, m.client, worker.Namespace)
+	if err != nil {
+		return errors.Wrapf(err, "could not get cluster")
+	}
+	cloudProfileConfig, err := helper.CloudProfileConfigFromCluster(cluster)
+	if err != nil {
+		return err
+	}
+	workerConfigs, err := helper.WorkerConfigsFromCluster(cluster)
+	if err != nil {
+		return err
+	}
+
+	var (
+		allErrs   = field.ErrorList{}
+		poolsPath = field.NewPath("spec", "pools")
+	)
+
+	for i := range worker.Spec.Pools {
+		pool := &worker.Spec.Pools[i]
+
+		minimumSize, err := minimumVolumeSize(cloudProfileConfig, workerConfigs[pool.Name], pool.MachineImage, worker.Spec.Region)
+		if err != nil {
+			return err
+		}
+
+		if pool.Volume == nil || len(pool.Volume.Size) == 0 {
+			extensionswebhook.LogMutation(m.logger, worker.Kind, worker.Namespace, worker.Name)
+			if pool.Volume == nil {
+				pool.Volume = &extensionsv1alpha1.Volume{}
+			}
+			pool.Volume.Size = minimumSize.String()
+			continue
+		}
+
+		allErrs = append(allErrs, validation.ValidateWorkerVolume(pool.Volume.Type, pool.Volume.Size, minimumSize, poolsPath.Index(i).Child("volume"))...)
+	}
+
+	if len(allErrs) > 0 {
+		return allErrs.ToAggregate()
+	}
+	return nil
+}
+
+// minimumVolumeSize returns the minimum size of the system disk of a worker pool. Pools without machine image use the
+// default machine image of the region. Pools which use an image ID instead of a machine image of the cloud profile
+// only require the minimum size Alicloud supports.
+func minimumVolumeSize(cloudProfileConfig *apisalicloud.CloudProfileConfig, workerConfig *apisalicloud.WorkerConfig, machineImage extensionsv1alpha1.MachineImage, region string) (resource.Quantity, error) {
+	if workerConfig != nil && workerConfig.ImageID != nil {
+		cloudProfileConfig = nil
+	}
+	name, version := helper.ResolveMachineImage(cloudProfileConfig, machineImage.Name, machineImage.Version, region)
+	return helper.MinimumVolumeSize(cloudProfileConfig, name, version)
+}
@@ -0,0 +1,189 @@
+// Copyright (c) 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workervolume_test
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/gardener/gardener-extension-provider-alicloud/pkg/alicloud"
+	apisalicloudv1alpha1 "github.com/gardener/gardener-extension-provider-alicloud/pkg/apis/alicloud/v1alpha1"
+	. "github.com/gardener/gardener-extension-provider-alicloud/pkg/webhook/workervolume"
+	mockclient "github.com/gardener/gardener-extensions/pkg/mock/controller-runtime/client"
+	extensionswebhook "github.com/gardener/gardener-extensions/pkg/webhook"
+
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/inject"
+)
+
+const namespace = "shoot--foo--bar"
+
+var _ = Describe("Mutator", func() {
+	var (
+		ctrl    *gomock.Controller
+		c       *mockclient.MockClient
+		mutator extensionswebhook.Mutator
+
+		ctx     = context.TODO()
+		cluster = &extensionsv1alpha1.Cluster{
+			Spec: extensionsv1alpha1.ClusterSpec{
+				CloudProfile: runtime.RawExtension{Raw: encode(&gardencorev1beta1.CloudProfile{
+					TypeMeta: metav1.TypeMeta{
+						APIVersion: gardencorev1beta1.SchemeGroupVersion.String(),
+						Kind:       "CloudProfile",
+					},
+					Spec: gardencorev1beta1.CloudProfileSpec{
+						ProviderConfig: &gardencorev1beta1.ProviderConfig{RawExtension: runtime.RawExtension{Raw: encode(&apisalicloudv1alpha1.CloudProfileConfig{
+							TypeMeta: metav1.TypeMeta{
+								APIVersion: apisalicloudv1alpha1.SchemeGroupVersion.String(),
+								Kind:       "CloudProfileConfig",
+							},
+							MachineImages: []apisalicloudv1alpha1.MachineImages{{
+								Name: "coreos",
+								Versions: []apisalicloudv1alpha1.MachineImageVersion{{
+									Version:           "2023.4.0",
+									Regions:           []apisalicloudv1alpha1.RegionIDMapping{{Name: "eu-central-1", ID: "coreos_2023_4_0_64_30G_alibase_20190319.vhd"}},
+									MinimumVolumeSize: pointer.StringPtr("40Gi"),
+								}},
+							}},
+						})}},
+					},
+				})},
+				Shoot: runtime.RawExtension{Raw: encode(&gardencorev1beta1.Shoot{
+					TypeMeta: metav1.TypeMeta{
+						APIVersion: gardencorev1beta1.SchemeGroupVersion.String(),
+						Kind:       "Shoot",
+					},
+					Spec: gardencorev1beta1.ShootSpec{
+						Provider: gardencorev1beta1.Provider{
+							Workers: []gardencorev1beta1.Worker{{
+								Name: "custom-image",
+								ProviderConfig: &gardencorev1beta1.ProviderConfig{RawExtension: runtime.RawExtension{Raw: encode(&apisalicloudv1alpha1.WorkerConfig{
+									TypeMeta: metav1.TypeMeta{
+										APIVersion: apisalicloudv1alpha1.SchemeGroupVersion.String(),
+										Kind:       "WorkerConfig",
+									},
+									ImageID: pointer.StringPtr("m-gw8a1b2c3d4e5f6g7h8i"),
+								})}},
+							}},
+						},
+					},
+				})},
+			},
+		}
+
+		worker *extensionsv1alpha1.Worker
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		c = mockclient.NewMockClient(ctrl)
+
+		mutator = NewMutator()
+		_, err := inject.ClientInto(c, mutator)
+		Expect(err).NotTo(HaveOccurred())
+
+		worker = &extensionsv1alpha1.Worker{
+			ObjectMeta: metav1.ObjectMeta{Name: "worker", Namespace: namespace},
+			Spec: extensionsv1alpha1.WorkerSpec{
+				DefaultSpec: extensionsv1alpha1.DefaultSpec{Type: alicloud.Type},
+				Pools: []extensionsv1alpha1.WorkerPool{{
+					Name:         "pool",
+					MachineImage: extensionsv1alpha1.MachineImage{Name: "coreos", Version: "2023.4.0"},
+					Volume:       &extensionsv1alpha1.Volume{Size: "50Gi"},
+				}},
+			},
+		}
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	expectGetCluster := func() {
+		c.EXPECT().Get(ctx, client.ObjectKey{Name: namespace}, &extensionsv1alpha1.Cluster{}).DoAndReturn(func(_ context.Context, _ client.ObjectKey, obj *extensionsv1alpha1.Cluster) error {
+			*obj = *cluster
+			return nil
+		})
+	}
+
+	It("should accept sizes in the range of the machine image and the disk category", func() {
+		expectGetCluster()
+
+		Expect(mutator.Mutate(ctx, worker)).To(Succeed())
+		Expect(worker.Spec.Pools[0].Volume.Size).To(Equal("50Gi"))
+	})
+
+	It("should reject sizes below the minimum size of the machine image", func() {
+		expectGetCluster()
+		worker.Spec.Pools[0].Volume.Size = "30Gi"
+
+		Expect(mutator.Mutate(ctx, worker)).To(MatchError(ContainSubstring("spec.pools[0].volume.size: Invalid value: \"30Gi\": must be at least 40Gi as required by the machine image")))
+	})
+
+	It("should reject sizes above the maximum size of the disk category", func() {
+		expectGetCluster()
+		worker.Spec.Pools[0].Volume = &extensionsv1alpha1.Volume{Type: pointer.StringPtr("cloud"), Size: "600Gi"}
+
+		Expect(mutator.Mutate(ctx, worker)).To(MatchError(ContainSubstring("must not be larger than 500Gi for disk category cloud")))
+	})
+
+	It("should default missing sizes to the minimum size of the machine image", func() {
+		expectGetCluster()
+		worker.Spec.Pools[0].Volume = nil
+		worker.Spec.Pools = append(worker.Spec.Pools, extensionsv1alpha1.WorkerPool{
+			Name:         "pool-without-size",
+			MachineImage: extensionsv1alpha1.MachineImage{Name: "coreos", Version: "2023.4.0"},
+			Volume:       &extensionsv1alpha1.Volume{Type: pointer.StringPtr("cloud_essd")},
+		})
+
+		Expect(mutator.Mutate(ctx, worker)).To(Succeed())
+		Expect(worker.Spec.Pools[0].Volume).To(Equal(&extensionsv1alpha1.Volume{Size: "40Gi"}))
+		Expect(worker.Spec.Pools[1].Volume).To(Equal(&extensionsv1alpha1.Volume{Type: pointer.StringPtr("cloud_essd"), Size: "40Gi"}))
+	})
+
+	It("should only require the Alicloud minimum size for pools using an image ID", func() {
+		expectGetCluster()
+		worker.Spec.Pools[0].Name = "custom-image"
+		worker.Spec.Pools[0].Volume.Size = "30Gi"
+
+		Expect(mutator.Mutate(ctx, worker)).To(Succeed())
+	})
+
+	It("should not touch workers of other providers or workers in deletion", func() {
+		worker.Spec.Type = "aws"
+		worker.Spec.Pools[0].Volume = nil
+		Expect(mutator.Mutate(ctx, worker)).To(Succeed())
+		Expect(worker.Spec.Pools[0].Volume).To(BeNil())
+
+		worker.Spec.Type = alicloud.Type
+		worker.DeletionTimestamp = &metav1.Time{}
+		Expect(mutator.Mutate(ctx, worker)).To(Succeed())
+		Expect(worker.Spec.Pools[0].Volume).To(BeNil())
+	})
+})
+
+func encode(obj runtime.Object) []byte {
+	data, _ := json.Marshal(obj)
+	return data
+}